@@ -168,6 +168,26 @@ func TestType_PolicyPositions(t *testing.T) {
 	assert.Nil(t, typ2.PolicyPositions())
 }
 
+func TestType_ValidatorPositions(t *testing.T) {
+	pos := &load.Position{Index: 1}
+	typ := Type{schema: &load.Schema{
+		Validators: []*load.Position{{}, pos},
+	}}
+	positions := typ.ValidatorPositions()
+	require.Len(t, positions, 2)
+	assert.Equal(t, pos, positions[1])
+	assert.Equal(t, 2, typ.NumValidators())
+
+	// Views have no mutations.
+	view := Type{schema: &load.Schema{View: true, Validators: []*load.Position{pos}}}
+	assert.Zero(t, view.NumValidators())
+	assert.Nil(t, view.ValidatorPositions())
+
+	// Nil schema.
+	typ2 := Type{}
+	assert.Nil(t, typ2.ValidatorPositions())
+}
+
 func TestType_RelatedTypes(t *testing.T) {
 	postType := &Type{Name: "Post"}
 	tagType := &Type{Name: "Tag"}
@@ -61,6 +61,8 @@ type OptionalFeatureGenerator interface {
 	GenGlobalID() (*jen.File, error)
 	// GenEntQL generates querylanguage.go.
 	GenEntQL() (*jen.File, error)
+	// GenFactory generates factory/factory.go.
+	GenFactory() (*jen.File, error)
 }
 
 // MigrateFiles holds the files generated by MigrateGenerator.
@@ -50,6 +50,7 @@ func (m *mockOptionalFeatureGenerator) GenSnapshot() (*jen.File, error)
 func (m *mockOptionalFeatureGenerator) GenVersionedMigration() (*jen.File, error) { return nil, nil }
 func (m *mockOptionalFeatureGenerator) GenGlobalID() (*jen.File, error)           { return nil, nil }
 func (m *mockOptionalFeatureGenerator) GenEntQL() (*jen.File, error)              { return nil, nil }
+func (m *mockOptionalFeatureGenerator) GenFactory() (*jen.File, error)            { return nil, nil }
 
 // mockMinimalDialect implements MinimalDialect for testing.
 type mockMinimalDialect struct {
@@ -159,6 +160,9 @@ func TestOptionalFeatureGeneratorInterface(t *testing.T) {
 		f, err = m.GenEntQL()
 		assert.Nil(t, f)
 		assert.NoError(t, err)
+		f, err = m.GenFactory()
+		assert.Nil(t, f)
+		assert.NoError(t, err)
 	})
 }
 
@@ -253,6 +257,9 @@ func TestDialectGeneratorInterface(t *testing.T) {
 		f, err = m.GenEntQL()
 		assert.Nil(t, f)
 		assert.NoError(t, err)
+		f, err = m.GenFactory()
+		assert.Nil(t, f)
+		assert.NoError(t, err)
 	})
 }
 
@@ -56,6 +56,20 @@ var (
 		},
 	}
 
+	// FeatureFactory provides a feature-flag for generating test data factories.
+	// The generated factory package fills the required fields of the create
+	// builders with deterministic values that satisfy the built-in validators,
+	// and creates the required parent entities.
+	FeatureFactory = Feature{
+		Name:        "factory",
+		Stage:       Experimental,
+		Default:     false,
+		Description: "Generates a factory package with per-entity factories that create entities with valid default values in tests",
+		cleanup: func(c *Config) error {
+			return os.RemoveAll(filepath.Join(c.Target, "factory"))
+		},
+	}
+
 	// FeatureNamedEdges provides a feature-flag for eager-loading edges with dynamic names.
 	FeatureNamedEdges = Feature{
 		Name:        "namedges",
@@ -138,6 +152,42 @@ var (
 		Description: "Allows users to configure the `ON CONFLICT`/`ON DUPLICATE KEY` clause for `INSERT` statements",
 	}
 
+	// FeatureBulkLoad provides a feature-flag for loading large numbers of rows
+	// with COPY FROM STDIN or auto-chunked multi-row inserts.
+	FeatureBulkLoad = Feature{
+		Name:        "sql/bulkload",
+		Stage:       Experimental,
+		Default:     false,
+		Description: "Allows users to load rows in bulk using `COPY FROM STDIN` on PostgreSQL and auto-chunked multi-row `INSERT` statements on other dialects",
+	}
+
+	// FeatureOutbox provides a feature-flag for recording the mutations of
+	// annotated types in a transactional outbox table.
+	FeatureOutbox = Feature{
+		Name:        "sql/outbox",
+		Stage:       Experimental,
+		Default:     false,
+		Description: "Records the mutations of types annotated with `outbox.Events` in an outbox table, in the same transaction, for the `outbox.Relay` to publish",
+	}
+
+	// FeatureShard provides a feature-flag for deriving the shard of create
+	// builders from the shard key field of annotated types.
+	FeatureShard = Feature{
+		Name:        "sql/shard",
+		Stage:       Experimental,
+		Default:     false,
+		Description: "Routes the creates of types annotated with `shard.Key` to the shard of their key field, for use with `shard.Driver`",
+	}
+
+	// FeatureRowSecurity provides a feature-flag for isolating the rows of
+	// multi-tenant types with PostgreSQL row-level security policies.
+	FeatureRowSecurity = Feature{
+		Name:        "sql/rowsecurity",
+		Stage:       Experimental,
+		Default:     false,
+		Description: "Creates PostgreSQL row-level security policies for the types that use `mixin.TenantID`, and sets the tenant of the viewer for every statement of the client",
+	}
+
 	// FeatureVersionedMigration enables versioned migration file support.
 	FeatureVersionedMigration = Feature{
 		Name:        "sql/versioned-migration",
@@ -246,6 +296,7 @@ var (
 		FeaturePrivacy,
 		FeatureIntercept,
 		FeatureEntQL,
+		FeatureFactory,
 		FeatureNamedEdges,
 		FeatureBidiEdgeRefs,
 		FeatureSnapshot,
@@ -254,6 +305,10 @@ var (
 		FeatureModifier,
 		FeatureExecQuery,
 		FeatureUpsert,
+		FeatureBulkLoad,
+		FeatureOutbox,
+		FeatureShard,
+		FeatureRowSecurity,
 		FeatureVersionedMigration,
 		FeatureGlobalID,
 		FeatureValidator,
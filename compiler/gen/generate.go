@@ -143,6 +143,7 @@ var optionalFeatureSpecs = []optionalFeatureSpec{
 	{FeatureVersionedMigration, "migrate", "versioned.go", OptionalFeatureGenerator.GenVersionedMigration},
 	{FeatureGlobalID, "internal", "globalid.go", OptionalFeatureGenerator.GenGlobalID},
 	{FeatureEntQL, "", "querylanguage.go", OptionalFeatureGenerator.GenEntQL},
+	{FeatureFactory, "factory", "factory.go", OptionalFeatureGenerator.GenFactory},
 }
 
 // Generate generates all code with parallel execution and streaming writes.
@@ -1090,6 +1090,13 @@ func (d *fullMockDialect) GenEntQL() (*jen.File, error) {
 	}
 	return mockJenFile("gen"), nil
 }
+func (d *fullMockDialect) GenFactory() (*jen.File, error) {
+	d.record("GenFactory")
+	if d.returnNil {
+		return nil, nil
+	}
+	return mockJenFile("factory"), nil
+}
 
 // MigrateGenerator
 func (d *fullMockDialect) GenMigrate() (MigrateFiles, error) {
@@ -1182,6 +1189,7 @@ func TestGenerateOptionalFeatures(t *testing.T) {
 		FeatureVersionedMigration,
 		FeatureGlobalID,
 		FeatureEntQL,
+		FeatureFactory,
 	)
 
 	mock := newFullMockDialect()
@@ -1199,6 +1207,7 @@ func TestGenerateOptionalFeatures(t *testing.T) {
 		"GenVersionedMigration",
 		"GenGlobalID",
 		"GenEntQL",
+		"GenFactory",
 	} {
 		assert.Equal(t, 1, mock.callCount(name), "%s should be called once", name)
 	}
@@ -1213,6 +1222,7 @@ func TestGenerateOptionalFeatures(t *testing.T) {
 	assert.FileExists(t, filepath.Join(target, "migrate", "versioned.go"))
 	assert.FileExists(t, filepath.Join(target, "internal", "globalid.go"))
 	assert.FileExists(t, filepath.Join(target, "querylanguage.go"))
+	assert.FileExists(t, filepath.Join(target, "factory", "factory.go"))
 }
 
 func TestGenerateOptionalFeaturesNilReturn(t *testing.T) {
@@ -1225,6 +1235,7 @@ func TestGenerateOptionalFeaturesNilReturn(t *testing.T) {
 		FeatureVersionedMigration,
 		FeatureGlobalID,
 		FeatureEntQL,
+		FeatureFactory,
 	)
 
 	mock := newFullMockDialect()
@@ -1243,6 +1254,7 @@ func TestGenerateOptionalFeaturesNilReturn(t *testing.T) {
 		"GenVersionedMigration",
 		"GenGlobalID",
 		"GenEntQL",
+		"GenFactory",
 	} {
 		assert.Equal(t, 1, mock.callCount(name), "%s should be called once", name)
 	}
@@ -1255,6 +1267,7 @@ func TestGenerateOptionalFeaturesNilReturn(t *testing.T) {
 		filepath.Join(target, "internal", "schema.go"),
 		filepath.Join(target, "internal", "globalid.go"),
 		filepath.Join(target, "querylanguage.go"),
+		filepath.Join(target, "factory", "factory.go"),
 	} {
 		_, statErr := os.Stat(path)
 		assert.True(t, os.IsNotExist(statErr), "file should not exist: %s", path)
@@ -1281,6 +1294,7 @@ func TestGenerateDisabledFeatures(t *testing.T) {
 		"GenVersionedMigration",
 		"GenGlobalID",
 		"GenEntQL",
+		"GenFactory",
 	} {
 		assert.Equal(t, 0, mock.callCount(name), "%s should not be called", name)
 	}
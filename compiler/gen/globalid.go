@@ -72,6 +72,11 @@ func IncrementStartAnnotation(g *Graph) error {
 		lastIdx = -1
 	)
 	for _, n := range g.Nodes {
+		// Ranges apply to auto-increment IDs only. Generated IDs, like UUIDs
+		// or prefixed IDs, are unique on their own.
+		if !n.HasOneFieldID() || !n.ID.PK().Increment {
+			continue
+		}
 		a := n.EntSQL()
 		if a == nil {
 			a = &sqlschema.Annotation{}
@@ -188,6 +188,13 @@ func NewGraph(c *Config, schemas ...*load.Schema) (*Graph, error) {
 		}
 	}
 
+	// Add counter-cache fields
+	for _, t := range g.Nodes {
+		if err := t.addCounterCaches(); err != nil {
+			return nil, fmt.Errorf("type %s: %w", t.Name, err)
+		}
+	}
+
 	// Setup foreign keys
 	for _, t := range g.Nodes {
 		if err := t.setupFKs(); err != nil {
@@ -354,6 +361,40 @@ func (g *Graph) addEdges(sc *load.Schema) error {
 	}
 	seen := make(map[string]struct{}, len(sc.Edges))
 	for _, e := range sc.Edges {
+		if len(e.OneOf) > 0 {
+			if _, ok := t.fields[e.Name]; ok {
+				return &EdgeError{
+					From:    sc.Name,
+					Edge:    e.Name,
+					Message: fmt.Sprintf("%s schema cannot contain field and edge with the same name %q", sc.Name, e.Name),
+				}
+			}
+			if _, ok := seen[e.Name]; ok {
+				return &EdgeError{
+					From:    sc.Name,
+					Edge:    e.Name,
+					Message: fmt.Sprintf("%s schema contains multiple %q edges", sc.Name, e.Name),
+				}
+			}
+			seen[e.Name] = struct{}{}
+			types := make([]*Type, 0, len(e.OneOf))
+			for _, name := range e.OneOf {
+				typ, ok := g.typ(name)
+				if !ok {
+					return &EdgeError{
+						From:    sc.Name,
+						Edge:    e.Name,
+						To:      name,
+						Message: fmt.Sprintf("type %q does not exist for edge", name),
+					}
+				}
+				types = append(types, typ)
+			}
+			if err := t.addOneOfEdge(e, types); err != nil {
+				return err
+			}
+			continue
+		}
 		typ, ok := g.typ(e.Type)
 		if !ok {
 			return &EdgeError{
@@ -203,6 +203,14 @@ func (g *Graph) Views() (views []*schema.Table, err error) {
 			}
 			view.AddColumn(f.Column())
 		}
+		// Materialized views are stored, and can be indexed like tables.
+		if n.IsMaterializedView() {
+			for _, idx := range n.Indexes {
+				view.AddIndex(idx.Name, idx.Unique, idx.Columns)
+				index, _ := view.Index(idx.Name)
+				index.Annotation = sqlIndexAnnotate(idx.Annotations)
+			}
+		}
 		views = append(views, view)
 	}
 	return
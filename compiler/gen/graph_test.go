@@ -448,6 +448,33 @@ func TestPosition(t *testing.T) {
 	require.Equal(t, vs[0].Pos, "pet_view.go:10")
 }
 
+func TestGraph_MaterializedViews(t *testing.T) {
+	stats := func(name string, ant sqlschema.Annotation) *load.Schema {
+		return &load.Schema{
+			Name: name,
+			View: true,
+			Fields: []*load.Field{
+				{Name: "author_id", Info: &field.TypeInfo{Type: field.TypeInt}},
+			},
+			Indexes:     []*load.Index{{Fields: []string{"author_id"}, Unique: true}},
+			Annotations: map[string]any{ant.Name(): ant},
+		}
+	}
+	g, err := NewGraph(&Config{Package: "entc/gen", Storage: drivers["sql"]},
+		stats("PostStats", sqlschema.Annotation{ViewAs: "SELECT 1", Materialized: true}),
+		stats("PostView", sqlschema.Annotation{ViewAs: "SELECT 1"}),
+	)
+	require.NoError(t, err)
+	require.True(t, g.Nodes[0].IsMaterializedView())
+	require.False(t, g.Nodes[1].IsMaterializedView())
+	vs, err := g.Views()
+	require.NoError(t, err)
+	require.Len(t, vs, 2)
+	require.Len(t, vs[0].Indexes, 1, "materialized views can be indexed")
+	require.True(t, vs[0].Indexes[0].Unique)
+	require.Empty(t, vs[1].Indexes)
+}
+
 func TestMultiSchemaAnnotation(t *testing.T) {
 	antFn := func(s string) map[string]any {
 		return map[string]any{sqlschema.Annotation{}.Name(): map[string]string{"schema": s}}
@@ -3,8 +3,17 @@ package gen
 import (
 	"errors"
 	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/syssam/velox/dialect/sqlschema"
+	"github.com/syssam/velox/schema/edge"
 )
 
+// settingRe matches the names of custom PostgreSQL settings, such as "app.tenant_id".
+var settingRe = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*(\.[a-zA-Z_][a-zA-Z0-9_]*)+$`)
+
 // Validate performs comprehensive validation of the graph and returns all
 // errors found, rather than failing on the first error like NewGraph does.
 // This is useful for reporting all schema issues at once to the user.
@@ -107,6 +116,127 @@ func (g *Graph) Validate() error {
 		}
 	}
 
+	// Validate privacy.FieldPolicy annotations are only used with the privacy
+	// feature. Without it the guards are never generated, and silently
+	// exposing a field its schema marks as restricted is worse than failing.
+	if g.Config != nil && !g.FeatureEnabledF(FeaturePrivacy) {
+		for _, t := range g.Nodes {
+			for _, f := range t.FieldPolicyFields() {
+				errs = append(errs, &SchemaValidationError{
+					Type:    t.Name,
+					Field:   f.Name,
+					Message: fmt.Sprintf("field %q has a privacy.FieldPolicy annotation, but the %q feature is not enabled", f.Name, FeaturePrivacy.Name),
+				})
+			}
+		}
+	}
+
+	// Validate sqlschema.RenamedFrom annotations. The old column is written
+	// alongside the new one, so it must not be a column of the type itself.
+	for _, t := range g.Nodes {
+		columns := make(map[string]bool, len(t.Fields)+1)
+		if t.ID != nil {
+			columns[t.ID.StorageKey()] = true
+		}
+		for _, f := range t.Fields {
+			columns[f.StorageKey()] = true
+		}
+		for _, f := range t.Fields {
+			if old := f.RenamedFrom(); old != "" && columns[old] {
+				errs = append(errs, &SchemaValidationError{
+					Type:    t.Name,
+					Field:   f.Name,
+					Message: fmt.Sprintf("field %q is renamed from column %q, which is still a column of %s", f.Name, old, t.Name),
+				})
+			}
+		}
+	}
+
+	// Validate field.IDPrefix annotations. Prefixes identify the type of an
+	// ID, so they apply to string IDs only and must be unique in the graph.
+	prefixes := make(map[string]string)
+	for _, t := range g.Nodes {
+		p := t.IDPrefix()
+		if p == "" {
+			continue
+		}
+		if !t.ID.IsString() {
+			errs = append(errs, &SchemaValidationError{
+				Type:    t.Name,
+				Field:   t.ID.Name,
+				Message: fmt.Sprintf("ID prefix %q requires a string ID, got %s", p, t.ID.Type),
+			})
+		}
+		if other, ok := prefixes[p]; ok {
+			errs = append(errs, &SchemaValidationError{
+				Type:    t.Name,
+				Field:   t.ID.Name,
+				Message: fmt.Sprintf("ID prefix %q is already used by %s", p, other),
+			})
+		}
+		prefixes[p] = t.Name
+	}
+
+	// Validate shard.Key annotations reference a field of the type.
+	for _, t := range g.Nodes {
+		name := t.ShardKeyName()
+		if name == "" {
+			continue
+		}
+		if !slices.ContainsFunc(t.Fields, func(f *Field) bool { return f.Name == name }) {
+			errs = append(errs, &SchemaValidationError{
+				Type:    t.Name,
+				Field:   name,
+				Message: fmt.Sprintf("shard key references unknown field %q", name),
+			})
+		}
+	}
+
+	// Validate sqlschema.PartitionBy annotations.
+	for _, t := range g.Nodes {
+		errs = append(errs, g.checkPartition(t)...)
+	}
+
+	// Validate sqlschema.TenantPolicy annotations (and sql/rowsecurity policies).
+	for _, t := range g.Nodes {
+		rs := t.RowSecurity()
+		if rs == nil {
+			continue
+		}
+		if !slices.ContainsFunc(t.Fields, func(f *Field) bool { return f.StorageKey() == rs.Column }) {
+			errs = append(errs, &SchemaValidationError{
+				Type:    t.Name,
+				Field:   rs.Column,
+				Message: fmt.Sprintf("row-level security policy references unknown column %q", rs.Column),
+			})
+		}
+		if !settingRe.MatchString(rs.Setting) {
+			errs = append(errs, &SchemaValidationError{
+				Type:    t.Name,
+				Message: fmt.Sprintf("row-level security setting %q must be a custom setting, such as %q", rs.Setting, sqlschema.TenantSetting),
+			})
+		}
+	}
+
+	// Validate edge.Cascade annotations.
+	for _, t := range g.Nodes {
+		for _, e := range t.CascadeEdges() {
+			if e.Type == nil {
+				continue
+			}
+			errs = append(errs, checkCascade(t, e)...)
+		}
+	}
+
+	// Validate edge.CounterCache annotations.
+	for _, t := range g.Nodes {
+		for _, f := range t.Fields {
+			if e := f.CounterCache(); e != nil && e.Type != nil {
+				errs = append(errs, checkCounterCache(t, e)...)
+			}
+		}
+	}
+
 	// Validate indexes reference existing fields/columns.
 	for _, t := range g.Nodes {
 		validCols := make(map[string]bool, len(t.Fields)+len(t.Edges))
@@ -138,3 +268,133 @@ func (g *Graph) Validate() error {
 
 	return errors.Join(errs...)
 }
+
+// checkPartition validates the partition key of the type (sqlschema.PartitionBy).
+// The migration extends the primary key of a partitioned table with the columns
+// of the partition key, as PostgreSQL and MySQL require all unique keys of a
+// partitioned table to include it. Hence, the columns must be non-nullable, the
+// unique fields and indexes must include them, and, unless the partition key is
+// the ID, no other table can reference the type with a foreign key.
+func (g *Graph) checkPartition(t *Type) (errs []error) {
+	ant := t.EntSQL()
+	if ant == nil || ant.Partition == nil || t.IsView() {
+		return nil
+	}
+	p := ant.Partition
+	fail := func(field, format string, args ...any) {
+		errs = append(errs, &SchemaValidationError{Type: t.Name, Field: field, Message: fmt.Sprintf(format, args...)})
+	}
+	switch p.Type {
+	case sqlschema.Range, sqlschema.List, sqlschema.Hash:
+	default:
+		fail("", "unknown partition type %q", p.Type)
+	}
+	if len(p.Columns) == 0 {
+		fail("", "missing columns for partition key")
+	}
+	fields := make(map[string]*Field, len(t.Fields)+1)
+	if t.ID != nil {
+		fields[t.ID.StorageKey()] = t.ID
+	}
+	for _, f := range t.Fields {
+		fields[f.StorageKey()] = f
+	}
+	idOnly := true
+	for _, c := range p.Columns {
+		f, ok := fields[c]
+		switch {
+		case !ok:
+			fail(c, "partition key references unknown column %q", c)
+		case f.Column().Nullable:
+			fail(f.Name, "partition key column %q cannot be nullable", c)
+		}
+		idOnly = idOnly && t.ID != nil && c == t.ID.StorageKey()
+	}
+	for _, f := range t.Fields {
+		if f.Unique && !slices.Equal(p.Columns, []string{f.StorageKey()}) {
+			fail(f.Name, "unique field %q must include the partition key %v, use a unique index instead", f.Name, p.Columns)
+		}
+	}
+	for _, idx := range t.Indexes {
+		if !idx.Unique {
+			continue
+		}
+		for _, c := range p.Columns {
+			if !slices.Contains(idx.Columns, c) {
+				fail(c, "unique index %q must include the partition key column %q", idx.Name, c)
+			}
+		}
+	}
+	if idOnly {
+		return errs
+	}
+	// Foreign keys that reference the type are declared by its O2M, O2O (non-owner)
+	// and M2M edges, or by the edges of other types that hold the foreign key.
+	seen := make(map[string]bool)
+	reference := func(e *Edge) {
+		if key := e.Rel.Table + "." + strings.Join(e.Rel.Columns, ","); !seen[key] {
+			seen[key] = true
+			fail("", "edge %q references %s with a foreign key, but the primary key of a partitioned type includes the partition key %v", e.Name, t.Name, p.Columns)
+		}
+	}
+	for _, e := range t.Edges {
+		if e.M2M() || (e.O2M() || e.O2O()) && !e.OwnFK() {
+			reference(e)
+		}
+	}
+	for _, n := range g.Nodes {
+		for _, e := range n.Edges {
+			if e.Type == t && (e.M2M() || e.OwnFK()) {
+				reference(e)
+			}
+		}
+	}
+	return errs
+}
+
+// checkCascade validates the edge.Cascade annotation of an edge. The dependents
+// of the edge are selected by their foreign-key column. Hence, the edge must be
+// an O2M or O2O edge whose foreign key resides in the table of the dependents,
+// and SetNull must be able to clear the inverse edge of the dependents.
+func checkCascade(t *Type, e *Edge) (errs []error) {
+	fail := func(format string, args ...any) {
+		errs = append(errs, NewEdgeError(t.Name, e.Type.Name, e.Name, fmt.Sprintf(format, args...), nil))
+	}
+	action := e.Cascade()
+	switch action {
+	case edge.Delete, edge.SetNull, edge.Restrict:
+	default:
+		fail("unknown cascade action %q", action)
+		return errs
+	}
+	switch {
+	case !e.O2M() && !e.O2O(), e.OwnFK():
+		fail("cascade edge %q must be an O2M or O2O edge whose foreign key resides in the table of %s", e.Name, e.Type.Name)
+	case e.Type.IsView():
+		fail("cascade edge %q cannot reference the view %s", e.Name, e.Type.Name)
+	case action == edge.SetNull && e.Ref == nil:
+		fail("cascade edge %q with SetNull requires the inverse edge on %s", e.Name, e.Type.Name)
+	case action == edge.SetNull && (!e.Ref.Optional || e.Ref.Immutable):
+		fail("cascade edge %q with SetNull requires the inverse edge %s.%s to be optional and mutable", e.Name, e.Type.Name, e.Ref.Name)
+	}
+	return errs
+}
+
+// checkCounterCache validates the edge.CounterCache annotation of an edge. The
+// counter is maintained by the statements that change the foreign-key of the
+// dependents. Hence, the edge must be an O2M edge whose foreign key resides in
+// the table of the dependents, and the owner must be a table.
+func checkCounterCache(t *Type, e *Edge) (errs []error) {
+	fail := func(format string, args ...any) {
+		errs = append(errs, NewEdgeError(t.Name, e.Type.Name, e.Name, fmt.Sprintf(format, args...), nil))
+	}
+	switch {
+	case !e.O2M() || e.Through != nil:
+		fail("counter cache edge %q must be an O2M edge whose foreign key resides in the table of %s", e.Name, e.Type.Name)
+	case t.IsView() || e.Type.IsView():
+		fail("counter cache edge %q cannot be defined on views", e.Name)
+	case !t.HasOneFieldID() || !e.Type.HasOneFieldID():
+		fail("counter cache edge %q requires %s and %s to have a single-field id", e.Name, t.Name, e.Type.Name)
+	}
+	return errs
+}
@@ -3,6 +3,11 @@ package gen
 import (
 	"testing"
 
+	"github.com/syssam/velox/compiler/load"
+	"github.com/syssam/velox/dialect/sql/shard"
+	"github.com/syssam/velox/dialect/sqlschema"
+	"github.com/syssam/velox/privacy"
+	"github.com/syssam/velox/schema/edge"
 	"github.com/syssam/velox/schema/field"
 
 	"github.com/stretchr/testify/assert"
@@ -394,3 +399,381 @@ func TestGraph_Validate_BatchesMultipleErrorTypes(t *testing.T) {
 		assert.GreaterOrEqual(t, len(joined.Unwrap()), 3)
 	}
 }
+
+func TestGraph_Validate_FieldPolicyRequiresPrivacy(t *testing.T) {
+	newGraph := func(features ...Feature) *Graph {
+		g := &Graph{
+			Config: &Config{Package: "example.com/app/velox", Features: features},
+			nodes:  make(map[string]*Type),
+		}
+		userType := &Type{
+			Name: "User",
+			ID:   &Field{Name: "id", Type: &field.TypeInfo{Type: field.TypeInt}},
+			Fields: []*Field{{
+				Name:        "salary",
+				Type:        &field.TypeInfo{Type: field.TypeFloat64},
+				Annotations: Annotations{privacy.FieldAnnotationName: map[string]any{}},
+			}},
+		}
+		g.Nodes = append(g.Nodes, userType)
+		g.nodes["User"] = userType
+		return g
+	}
+
+	err := newGraph().Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "privacy.FieldPolicy")
+	assert.Contains(t, err.Error(), "salary")
+
+	assert.NoError(t, newGraph(FeaturePrivacy).Validate())
+}
+
+func TestGraph_Validate_RenamedFrom(t *testing.T) {
+	newGraph := func(old string) *Graph {
+		g := &Graph{
+			Config: &Config{Package: "example.com/app/velox"},
+			nodes:  make(map[string]*Type),
+		}
+		userType := &Type{
+			Name: "User",
+			ID:   &Field{Name: "id", Type: &field.TypeInfo{Type: field.TypeInt}},
+			Fields: []*Field{
+				{Name: "nickname", Type: &field.TypeInfo{Type: field.TypeString}},
+				{
+					Name:        "full_name",
+					Type:        &field.TypeInfo{Type: field.TypeString},
+					Annotations: Annotations{sqlschema.AnnotationName: sqlschema.RenamedFrom(old)},
+				},
+			},
+		}
+		g.Nodes = append(g.Nodes, userType)
+		g.nodes["User"] = userType
+		return g
+	}
+
+	err := newGraph("nickname").Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `renamed from column "nickname"`)
+
+	g := newGraph("name")
+	require.NoError(t, g.Validate())
+	assert.Equal(t, "name", g.Nodes[0].Fields[1].RenamedFrom())
+	assert.Equal(t, "name", g.Nodes[0].Fields[1].Column().RenamedFrom)
+}
+
+func TestGraph_Validate_IDPrefix(t *testing.T) {
+	newType := func(name, prefix string, typ field.Type) *Type {
+		return &Type{
+			Name: name,
+			ID: &Field{
+				Name:        "id",
+				Type:        &field.TypeInfo{Type: typ},
+				Annotations: Annotations{field.Annotation{}.Name(): field.IDPrefix(prefix)},
+			},
+		}
+	}
+	newGraph := func(types ...*Type) *Graph {
+		g := &Graph{
+			Config: &Config{Package: "example.com/app/velox"},
+			nodes:  make(map[string]*Type),
+		}
+		for _, t := range types {
+			g.Nodes = append(g.Nodes, t)
+			g.nodes[t.Name] = t
+		}
+		return g
+	}
+
+	g := newGraph(newType("User", "usr", field.TypeString), newType("Org", "org", field.TypeString))
+	require.NoError(t, g.Validate())
+	assert.Equal(t, "usr", g.Nodes[0].IDPrefix())
+
+	err := newGraph(newType("User", "usr", field.TypeString), newType("Member", "usr", field.TypeString)).Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `ID prefix "usr" is already used by User`)
+
+	err = newGraph(newType("User", "usr", field.TypeInt)).Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `ID prefix "usr" requires a string ID`)
+}
+
+func TestGraph_Validate_ShardKey(t *testing.T) {
+	newGraph := func(key string) *Graph {
+		g := &Graph{
+			Config: &Config{Package: "example.com/app/velox", Features: []Feature{FeatureShard}},
+			nodes:  make(map[string]*Type),
+		}
+		userType := &Type{
+			Name:        "User",
+			Config:      g.Config,
+			ID:          &Field{Name: "id", Type: &field.TypeInfo{Type: field.TypeInt}},
+			Fields:      []*Field{{Name: "tenant_id", Type: &field.TypeInfo{Type: field.TypeString}}},
+			Annotations: Annotations{shard.AnnotationName: shard.Key(key)},
+		}
+		g.Nodes = append(g.Nodes, userType)
+		g.nodes["User"] = userType
+		return g
+	}
+
+	g := newGraph("tenant_id")
+	require.NoError(t, g.Validate())
+	assert.Equal(t, "tenant_id", g.Nodes[0].ShardKey().Name)
+
+	err := newGraph("org_id").Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `shard key references unknown field "org_id"`)
+}
+
+func TestGraph_Validate_Partition(t *testing.T) {
+	newGraph := func(ant sqlschema.Annotation, fields []*load.Field, indexes []*load.Index, others ...*load.Schema) error {
+		event := &load.Schema{
+			Name: "Event",
+			Fields: append([]*load.Field{
+				{Name: "created_at", Info: &field.TypeInfo{Type: field.TypeTime}},
+			}, fields...),
+			Indexes:     indexes,
+			Annotations: map[string]any{ant.Name(): ant},
+		}
+		_, err := NewGraph(&Config{Package: "entc/gen", Storage: drivers["sql"]}, append([]*load.Schema{event}, others...)...)
+		return err
+	}
+	byCreatedAt := *sqlschema.PartitionBy(sqlschema.Range, "created_at")
+
+	require.NoError(t, newGraph(byCreatedAt, nil, []*load.Index{{Fields: []string{"created_at"}, Unique: true}}))
+	require.NoError(t, newGraph(*sqlschema.PartitionBy(sqlschema.Hash, "id"), nil, nil, &load.Schema{
+		Name:  "Audit",
+		Edges: []*load.Edge{{Name: "event", Type: "Event", Unique: true}},
+	}), "the primary key of types partitioned by ID is not extended")
+
+	tests := []struct {
+		name    string
+		ant     sqlschema.Annotation
+		fields  []*load.Field
+		indexes []*load.Index
+		others  []*load.Schema
+		wantErr string
+	}{
+		{
+			name:    "unknown type",
+			ant:     *sqlschema.PartitionBy("INTERVAL", "created_at"),
+			wantErr: `unknown partition type "INTERVAL"`,
+		},
+		{
+			name:    "no columns",
+			ant:     *sqlschema.PartitionBy(sqlschema.Range),
+			wantErr: "missing columns for partition key",
+		},
+		{
+			name:    "unknown column",
+			ant:     *sqlschema.PartitionBy(sqlschema.Range, "deleted_at"),
+			wantErr: `partition key references unknown column "deleted_at"`,
+		},
+		{
+			name:    "nullable column",
+			ant:     *sqlschema.PartitionBy(sqlschema.List, "region"),
+			fields:  []*load.Field{{Name: "region", Info: &field.TypeInfo{Type: field.TypeString}, Nillable: true, Optional: true}},
+			wantErr: `partition key column "region" cannot be nullable`,
+		},
+		{
+			name:    "unique field",
+			ant:     byCreatedAt,
+			fields:  []*load.Field{{Name: "key", Info: &field.TypeInfo{Type: field.TypeString}, Unique: true}},
+			wantErr: `unique field "key" must include the partition key [created_at]`,
+		},
+		{
+			name:    "unique index",
+			ant:     byCreatedAt,
+			fields:  []*load.Field{{Name: "key", Info: &field.TypeInfo{Type: field.TypeString}}},
+			indexes: []*load.Index{{Fields: []string{"key"}, Unique: true}},
+			wantErr: `must include the partition key column "created_at"`,
+		},
+		{
+			name: "referenced",
+			ant:  byCreatedAt,
+			others: []*load.Schema{{
+				Name:  "Audit",
+				Edges: []*load.Edge{{Name: "event", Type: "Event", Unique: true}},
+			}},
+			wantErr: `edge "event" references Event with a foreign key`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := newGraph(tt.ant, tt.fields, tt.indexes, tt.others...)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestGraph_RowSecurity(t *testing.T) {
+	newGraph := func(features []Feature, ant *sqlschema.Annotation) (*Graph, error) {
+		doc := &load.Schema{
+			Name: "Document",
+			Fields: []*load.Field{
+				{Name: "tenant_id", Info: &field.TypeInfo{Type: field.TypeString}, Position: &load.Position{MixedIn: true}},
+				{Name: "org_id", Info: &field.TypeInfo{Type: field.TypeInt}},
+			},
+		}
+		if ant != nil {
+			doc.Annotations = map[string]any{ant.Name(): ant}
+		}
+		return NewGraph(&Config{Package: "entc/gen", Storage: drivers["sql"], Features: features}, doc)
+	}
+
+	g, err := newGraph(nil, nil)
+	require.NoError(t, err)
+	assert.Nil(t, g.Nodes[0].RowSecurity(), "the mixin policy requires the feature")
+
+	g, err = newGraph([]Feature{FeatureRowSecurity}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, &sqlschema.RowSecurity{Column: "tenant_id", Setting: sqlschema.TenantSetting}, g.Nodes[0].RowSecurity())
+
+	g, err = newGraph(nil, &sqlschema.Annotation{RowSecurity: &sqlschema.RowSecurity{Column: "org_id", Force: new(bool)}})
+	require.NoError(t, err)
+	assert.Equal(t, &sqlschema.RowSecurity{Column: "org_id", Setting: sqlschema.TenantSetting, Force: new(bool)}, g.Nodes[0].RowSecurity())
+
+	_, err = newGraph(nil, sqlschema.TenantPolicy("workspace_id"))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `row-level security policy references unknown column "workspace_id"`)
+
+	_, err = newGraph(nil, &sqlschema.Annotation{RowSecurity: &sqlschema.RowSecurity{Column: "org_id", Setting: "tenant; DROP TABLE documents"}})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `must be a custom setting, such as "app.tenant_id"`)
+}
+
+func TestGraph_Cascade(t *testing.T) {
+	newGraph := func(action edge.CascadeAction, posts *load.Edge, author *load.Edge) (*Graph, error) {
+		posts.Annotations = map[string]any{edge.Annotation{}.Name(): edge.Cascade(action)}
+		post := &load.Schema{Name: "Post"}
+		if author != nil {
+			post.Edges = []*load.Edge{author}
+		}
+		return NewGraph(&Config{Package: "entc/gen", Storage: drivers["sql"]},
+			&load.Schema{Name: "User", Edges: []*load.Edge{posts}}, post)
+	}
+	author := func() *load.Edge {
+		return &load.Edge{Name: "author", Type: "User", Inverse: true, RefName: "posts", Unique: true}
+	}
+
+	g, err := newGraph(edge.SetNull, &load.Edge{Name: "posts", Type: "Post"}, author())
+	require.NoError(t, err)
+	edges := g.Nodes[0].CascadeEdges()
+	require.Len(t, edges, 1)
+	assert.Equal(t, edge.SetNull, edges[0].Cascade())
+	assert.Empty(t, g.Nodes[1].CascadeEdges())
+
+	_, err = newGraph(edge.Delete, &load.Edge{Name: "posts", Type: "Post", Unique: true}, author())
+	require.NoError(t, err)
+	_, err = newGraph(edge.Delete, &load.Edge{Name: "posts", Type: "Post", Unique: true}, nil)
+	require.Error(t, err, "M2O edges hold the foreign key")
+
+	_, err = newGraph("NO ACTION", &load.Edge{Name: "posts", Type: "Post"}, nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `unknown cascade action "NO ACTION"`)
+
+	_, err = newGraph(edge.Restrict, &load.Edge{Name: "posts", Type: "Post"}, nil)
+	require.NoError(t, err)
+	_, err = newGraph(edge.SetNull, &load.Edge{Name: "posts", Type: "Post"}, nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `with SetNull requires the inverse edge on Post`)
+
+	required := author()
+	required.Required = true
+	_, err = newGraph(edge.SetNull, &load.Edge{Name: "posts", Type: "Post"}, required)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `requires the inverse edge Post.author to be optional and mutable`)
+
+	_, err = newGraph(edge.Delete, &load.Edge{Name: "tags", Type: "Post"}, &load.Edge{Name: "users", Type: "User", Inverse: true, RefName: "tags"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `must be an O2M or O2O edge whose foreign key resides in the table of Post`)
+}
+
+func TestGraph_CounterCache(t *testing.T) {
+	newGraph := func(posts *load.Edge, author *load.Edge, fields ...*load.Field) (*Graph, error) {
+		posts.Annotations = map[string]any{edge.Annotation{}.Name(): edge.CounterCache("posts_count")}
+		post := &load.Schema{Name: "Post"}
+		if author != nil {
+			post.Edges = []*load.Edge{author}
+		}
+		return NewGraph(&Config{Package: "entc/gen", Storage: drivers["sql"]},
+			&load.Schema{Name: "User", Fields: fields, Edges: []*load.Edge{posts}}, post)
+	}
+
+	g, err := newGraph(&load.Edge{Name: "posts", Type: "Post"}, nil)
+	require.NoError(t, err)
+	f, ok := g.Nodes[0].FieldBy(func(f *Field) bool { return f.Name == "posts_count" })
+	require.True(t, ok)
+	assert.Equal(t, field.TypeInt, f.Type.Type)
+	assert.True(t, f.ReadOnly())
+	assert.True(t, f.Immutable)
+	require.NotNil(t, f.CounterCache())
+	assert.Equal(t, "posts", f.CounterCache().Name)
+	assert.Equal(t, 0, f.Column().Default)
+	assert.Equal(t, "posts_count", g.Nodes[0].Edges[0].CounterCache())
+
+	_, err = newGraph(&load.Edge{Name: "posts", Type: "Post"}, nil, &load.Field{Name: "posts_count", Info: &field.TypeInfo{Type: field.TypeInt}})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `counter cache of edge "posts"`)
+
+	_, err = newGraph(&load.Edge{Name: "posts", Type: "Post", Unique: true}, nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `counter cache edge "posts" must be an O2M edge`)
+
+	_, err = newGraph(&load.Edge{Name: "tags", Type: "Post"}, &load.Edge{Name: "users", Type: "User", Inverse: true, RefName: "tags"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `counter cache edge "tags" must be an O2M edge`)
+}
+
+func TestGraph_OneOfEdge(t *testing.T) {
+	newGraph := func(e *load.Edge, schemas ...*load.Schema) (*Graph, error) {
+		schemas = append([]*load.Schema{
+			{Name: "Comment", Edges: []*load.Edge{e}},
+			{Name: "Post"},
+			{Name: "Photo"},
+		}, schemas...)
+		return NewGraph(&Config{Package: "entc/gen", Storage: drivers["sql"]}, schemas...)
+	}
+
+	g, err := newGraph(&load.Edge{Name: "subject", OneOf: []string{"Post", "Photo"}})
+	require.NoError(t, err)
+	c := g.Nodes[0]
+	require.Empty(t, c.Edges)
+	require.Len(t, c.OneOfEdges, 1)
+	oe := c.OneOfEdges[0]
+	assert.Equal(t, []*Type{g.Nodes[1], g.Nodes[2]}, oe.Types)
+	assert.True(t, oe.Optional)
+	assert.Equal(t, "CommentSubjectUnion", oe.UnionName())
+	assert.Equal(t, "QuerySubjectPost", oe.QueryName(g.Nodes[1]))
+	require.NotNil(t, oe.TypeField)
+	assert.Equal(t, "subject_type", oe.TypeField.Name)
+	assert.True(t, oe.TypeField.IsEnum())
+	assert.Equal(t, []string{"Post", "Photo"}, oe.TypeField.EnumValues())
+	assert.True(t, oe.TypeField.Nillable)
+	assert.Equal(t, oe, oe.TypeField.OneOfEdge())
+	require.NotNil(t, oe.IDField)
+	assert.Equal(t, "subject_id", oe.IDField.Name)
+	assert.Equal(t, field.TypeInt, oe.IDField.Type.Type)
+	require.Len(t, c.Indexes, 1)
+	assert.Equal(t, []string{"subject_type", "subject_id"}, c.Indexes[0].Columns)
+
+	g, err = newGraph(&load.Edge{Name: "subject", OneOf: []string{"Post", "Photo"}, Required: true, Immutable: true})
+	require.NoError(t, err)
+	oe = g.Nodes[0].OneOfEdges[0]
+	assert.False(t, oe.TypeField.Optional)
+	assert.False(t, oe.IDField.Nillable)
+	assert.True(t, oe.IDField.Immutable)
+
+	_, err = newGraph(&load.Edge{Name: "subject", OneOf: []string{"Post", "Video"}})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `type "Video" does not exist for edge`)
+
+	_, err = newGraph(&load.Edge{Name: "subject", OneOf: []string{"Post", "Post"}})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `contains type "Post" more than once`)
+
+	_, err = newGraph(&load.Edge{Name: "subject", OneOf: []string{"Post", "Video"}},
+		&load.Schema{Name: "Video", Fields: []*load.Field{{Name: "id", Info: &field.TypeInfo{Type: field.TypeString}}}})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "requires the types to have the same id type")
+}
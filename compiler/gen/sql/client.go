@@ -1,6 +1,8 @@
 package sql
 
 import (
+	"slices"
+
 	"github.com/dave/jennifer/jen"
 
 	"github.com/syssam/velox/compiler/gen"
@@ -132,6 +134,7 @@ func genClientStruct(h gen.GeneratorHelper, f *jen.File) {
 		grp.For(jen.List(jen.Id("_"), jen.Id("opt")).Op(":=").Range().Id("opts")).Block(
 			jen.Id("opt").Call(jen.Op("&").Id("c")),
 		)
+		genClientRowSecurity(h, grp, graph)
 		// Issue 5: Auto-wrap driver with debug logging when debug option is set.
 		grp.If(jen.Id("c").Dot("debug")).Block(
 			jen.Id("c").Dot("driver").Op("=").Qual(dialectPkg(), "Debug").Call(
@@ -273,6 +276,9 @@ func genClientStruct(h gen.GeneratorHelper, f *jen.File) {
 	// Mutate dispatches to the correct entity client based on mutation type.
 	genClientMutateMethod(h, f, graph)
 
+	// Refresh<View> recomputes the rows of each materialized view.
+	genClientRefreshMethods(f, graph)
+
 	// init() constructs per-entity client fields directly from sub-packages.
 	f.Comment("init constructs per-entity client fields directly.")
 	f.Func().Params(jen.Id("c").Op("*").Id("Client")).Id("init").Params().BlockFunc(func(grp *jen.Group) {
@@ -287,6 +293,62 @@ func genClientStruct(h gen.GeneratorHelper, f *jen.File) {
 	})
 }
 
+// genClientRowSecurity wraps the PostgreSQL driver of the client, such that every
+// statement sets the settings of the row-level security policies in the graph to
+// the tenant of the viewer in its context.
+func genClientRowSecurity(h gen.GeneratorHelper, grp *jen.Group, graph *gen.Graph) {
+	var settings []string
+	for _, t := range graph.Nodes {
+		if rs := t.RowSecurity(); rs != nil && !slices.Contains(settings, rs.Setting) {
+			settings = append(settings, rs.Setting)
+		}
+	}
+	if len(settings) == 0 {
+		return
+	}
+	slices.Sort(settings)
+	grp.Comment("Set the tenant of the viewer for the row-level security policies.")
+	grp.If(
+		jen.Id("c").Dot("driver").Op("!=").Nil().Op("&&").
+			Id("c").Dot("driver").Dot("Dialect").Call().Op("==").Qual(dialectPkg(), "Postgres"),
+	).BlockFunc(func(grp *jen.Group) {
+		for _, setting := range settings {
+			grp.Id("c").Dot("driver").Op("=").Qual(h.SQLPkg(), "NewVarDriver").Call(
+				jen.Id("c").Dot("driver"),
+				jen.Lit(setting),
+				jen.Qual(privacyPkg, "TenantFromContext"),
+			)
+		}
+	})
+}
+
+// genClientRefreshMethods generates a Refresh method on Client for each
+// materialized view (sqlschema.Materialized) in the graph.
+func genClientRefreshMethods(f *jen.File, graph *gen.Graph) {
+	const schemaPkg = "github.com/syssam/velox/dialect/sql/schema"
+	migratePkg := graph.Package + "/migrate"
+	for _, t := range graph.Nodes {
+		if !t.IsMaterializedView() {
+			continue
+		}
+		name := "Refresh" + t.Name
+		f.Comment(name + " recomputes the rows of the \"" + t.Table() + "\" materialized view.")
+		f.Comment("On PostgreSQL, concurrently refreshes the view without locking out its readers,")
+		f.Comment("and requires a unique index on the view.")
+		f.Func().Params(jen.Id("c").Op("*").Id("Client")).Id(name).Params(
+			jen.Id("ctx").Qual("context", "Context"),
+			jen.Id("concurrently").Bool(),
+		).Error().Block(
+			jen.Return(jen.Qual(schemaPkg, "RefreshView").Call(
+				jen.Id("ctx"),
+				jen.Id("c").Dot("driver"),
+				jen.Qual(migratePkg, pascal(t.Name)+"Table"),
+				jen.Id("concurrently"),
+			)),
+		)
+	}
+}
+
 // genClientMutateMethod generates the Mutate method on Client that dispatches
 // to the correct entity client based on the mutation's concrete type.
 func genClientMutateMethod(_ gen.GeneratorHelper, f *jen.File, _ *gen.Graph) {
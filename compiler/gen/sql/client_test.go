@@ -40,6 +40,28 @@ func TestGenClient_MultipleEntities(t *testing.T) {
 	assert.Contains(t, code, "CommentClient")
 }
 
+func TestGenClient_RefreshMaterializedView(t *testing.T) {
+	helper := newMockHelper()
+	helper.graph.Nodes = []*gen.Type{createTestType("Post"), createMaterializedView(t)}
+
+	code := genClient(helper).GoString()
+	assert.Contains(t, code, "func (c *Client) RefreshPostStats(ctx context.Context, concurrently bool) error {")
+	assert.Contains(t, code, "return schema.RefreshView(ctx, c.driver, migrate.PostStatsTable, concurrently)")
+	assert.NotContains(t, code, "RefreshPost(")
+}
+
+func TestGenClient_RowSecurity(t *testing.T) {
+	helper := newMockHelper()
+	helper.graph.Nodes = []*gen.Type{createTestType("User")}
+	assert.NotContains(t, genClient(helper).GoString(), "NewVarDriver")
+
+	helper.graph.Nodes = append(helper.graph.Nodes, createTenantType(t))
+	code := genClient(helper).GoString()
+	assert.Contains(t, code, `if c.driver != nil && c.driver.Dialect() == dialect.Postgres {
+		c.driver = sql.NewVarDriver(c.driver, "app.tenant_id", privacy.TenantFromContext)
+	}`)
+}
+
 func TestGenConfigExecQueryMethods(t *testing.T) {
 	helper := newMockHelper()
 	f := jen.NewFile("ent")
@@ -79,7 +79,8 @@ func genCreate(h gen.GeneratorHelper, t *gen.Type) (*jen.File, error) { //nolint
 		fieldsForSetters = append(fieldsForSetters[:len(fieldsForSetters):len(fieldsForSetters)], t.ID)
 	}
 	for _, fd := range fieldsForSetters {
-		if fd.IsEdgeField() && !fd.UserDefined {
+		// Generated columns and counter caches are read-only.
+		if fd.IsEdgeField() && !fd.UserDefined || fd.ReadOnly() {
 			continue
 		}
 		genFieldSetter(h, f, createName, recv, fd, false, "mutation", creatorIface)
@@ -100,6 +101,7 @@ func genCreate(h gen.GeneratorHelper, t *gen.Type) (*jen.File, error) { //nolint
 		}
 		genEdgeEntitySetter(h, f, createName, recv, edge, false, creatorIface)
 	}
+	genOneOfSetters(h, f, t, createName, recv, false)
 
 	// --- Mutation ---
 	f.Commentf("Mutation returns the %s.", mutName)
@@ -114,6 +116,7 @@ func genCreate(h gen.GeneratorHelper, t *gen.Type) (*jen.File, error) { //nolint
 
 	// --- check ---
 	genCreateCheck(h, f, t, createName, recv)
+	genEntityValidate(h, f, t, createName, recv, false)
 
 	// --- sqlSave (named method extracted from Save closure — Ent pattern) ---
 	genCreateSQLSave(h, f, t, createName, recv, entityReturnPkg, upsertEnabled)
@@ -174,6 +177,9 @@ func genCreateDefaults(h gen.GeneratorHelper, f *jen.File, t *gen.Type, builderN
 
 	autoDefault := h.FeatureEnabled(gen.FeatureAutoDefault.Name)
 	fieldNeedsDefault := func(fd *gen.Field) bool {
+		if fd.ReadOnly() {
+			return false
+		}
 		if fd.Default {
 			return true
 		}
@@ -247,7 +253,7 @@ func genCreateCheck(h gen.GeneratorHelper, f *jen.File, t *gen.Type, builderName
 				continue
 			}
 			// Required field check
-			if !fd.Optional && !fd.Nillable {
+			if !fd.Optional && !fd.Nillable && !fd.IsGenerated() {
 				grp.If(
 					jen.List(jen.Id("_"), jen.Id("ok")).Op(":=").Id(recv).Dot("mutation").Dot(fd.MutationGet()).Call(),
 					jen.Op("!").Id("ok"),
@@ -284,7 +290,7 @@ func genCreateCheck(h gen.GeneratorHelper, f *jen.File, t *gen.Type, builderName
 		// Build set of required field keys to skip field-backed edges.
 		checkedFieldKeys := make(map[string]bool)
 		for _, fd := range fields {
-			if !fd.Optional && !fd.Nillable {
+			if !fd.Optional && !fd.Nillable && !fd.IsGenerated() {
 				checkedFieldKeys[fd.Name] = true
 				checkedFieldKeys[fd.StorageKey()] = true
 			}
@@ -323,7 +329,9 @@ func genCreateSQLSave(h gen.GeneratorHelper, f *jen.File, t *gen.Type, builderNa
 		grp.If(jen.Id("err").Op(":=").Id(recv).Dot("check").Call(), jen.Id("err").Op("!=").Nil()).Block(
 			jen.Return(jen.Nil(), jen.Id("err")),
 		)
+		genEntityValidateCall(grp, t, jen.Id(recv), jen.Nil())
 		grp.List(jen.Id("_node"), jen.Id("_spec")).Op(":=").Id(recv).Dot("createSpec").Call()
+		genCreateShardKey(grp, t, recv)
 		if upsertEnabled {
 			grp.If(jen.Len(jen.Id(recv).Dot("conflict")).Op(">").Lit(0)).Block(
 				jen.Id("_spec").Dot("OnConflict").Op("=").Id(recv).Dot("conflict"),
@@ -428,11 +436,13 @@ func genCreateSpecMethod(h gen.GeneratorHelper, f *jen.File, t *gen.Type, builde
 			typedField := "_" + fd.Name
 			grp.If(jen.Id(recv).Dot("mutation").Dot(typedField).Op("!=").Nil()).BlockFunc(func(blk *jen.Group) {
 				blk.Id("v").Op(":=").Op("*").Id(recv).Dot("mutation").Dot(typedField)
-				blk.Id("_spec").Dot("SetField").Call(
-					jen.Lit(fd.StorageKey()),
-					jen.Qual(fieldPkg, h.FieldTypeConstant(fd)),
-					jen.Id("v"),
-				)
+				for _, col := range dualWriteColumns(fd) {
+					blk.Id("_spec").Dot("SetField").Call(
+						jen.Lit(col),
+						jen.Qual(fieldPkg, h.FieldTypeConstant(fd)),
+						jen.Id("v"),
+					)
+				}
 				if fd.NillableValue() {
 					blk.Id("_node").Dot(fd.StructField()).Op("=").Op("&").Id("v")
 				} else {
@@ -447,6 +457,9 @@ func genCreateSpecMethod(h gen.GeneratorHelper, f *jen.File, t *gen.Type, builde
 			}
 			genCreateEdge(h, grp, t, edge, entityPkg, fieldPkg, sqlGraphPkg, "_node", "_spec")
 		}
+		if len(counterCacheFields(h.Graph(), t)) > 0 {
+			grp.Id("_spec").Dot("Counters").Op("=").Qual(entityPkg, "CounterCaches")
+		}
 		grp.Return(jen.Id("_node"), jen.Id("_spec"))
 	})
 }
@@ -528,6 +541,7 @@ func genCreateSave(h gen.GeneratorHelper, f *jen.File, t *gen.Type, builderName,
 	f.Func().Params(jen.Id(recv).Op("*").Id(builderName)).Id("Save").Params(
 		jen.Id("ctx").Qual("context", "Context"),
 	).Params(jen.Op("*").Qual(entityReturnPkg, t.Name), jen.Error()).BlockFunc(func(grp *jen.Group) {
+		genFieldPolicyCheck(h, grp, t, jen.Id(recv).Dot("mutation"), jen.Nil())
 		// Call defaults() if type has defaults
 		if t.NeedsDefaults() {
 			grp.If(jen.Id("err").Op(":=").Id(recv).Dot("defaults").Call(), jen.Id("err").Op("!=").Nil()).Block(
@@ -764,6 +778,12 @@ func genCreateBulk(h gen.GeneratorHelper, f *jen.File, t *gen.Type, createName,
 		grp.If(jen.Len(jen.Id("builders")).Op("==").Lit(0)).Block(
 			jen.Return(jen.Index().Op("*").Qual(entityReturnPkg, t.Name).Values(), jen.Nil()),
 		)
+		// Per-row field policy check — runs before defaults() in the loop below.
+		if h.FeatureEnabled(gen.FeaturePrivacy.Name) && len(t.FieldPolicyFields()) > 0 {
+			grp.For(jen.List(jen.Id("_"), jen.Id("b")).Op(":=").Range().Id("builders")).BlockFunc(func(loop *jen.Group) {
+				genFieldPolicyCheck(h, loop, t, jen.Id("b").Dot("mutation"), jen.Nil())
+			})
+		}
 		// Explicit per-row privacy check — runs before hooks since privacy no longer rides on Hooks[0].
 		if t.NumPolicy() > 0 {
 			grp.For(jen.List(jen.Id("_"), jen.Id("b")).Op(":=").Range().Id("builders")).Block(
@@ -776,6 +796,11 @@ func genCreateBulk(h gen.GeneratorHelper, f *jen.File, t *gen.Type, createName,
 				),
 			)
 		}
+		// Attach the mutation for the statements of the batch, as
+		// velox.WithHooks does for single-row saves.
+		grp.Id("ctx").Op("=").Qual(h.VeloxPkg(), "NewMutationContext").Call(
+			jen.Id("ctx"), jen.Id("builders").Index(jen.Lit(0)).Dot("mutation"),
+		)
 		grp.Id("specs").Op(":=").Make(
 			jen.Index().Op("*").Qual(h.SQLGraphPkg(), "CreateSpec"),
 			jen.Len(jen.Id("builders")),
@@ -815,6 +840,7 @@ func genCreateBulk(h gen.GeneratorHelper, f *jen.File, t *gen.Type, createName,
 							jen.Return(jen.Nil(), jen.Id("err")),
 						)
 						inner.Id("builder").Dot("mutation").Op("=").Id("mutation")
+						genEntityValidateCall(inner, t, jen.Id("builder"), jen.Nil())
 						inner.Var().Id("err").Error()
 						inner.List(
 							jen.Id("nodes").Index(jen.Id("i")),
@@ -840,6 +866,7 @@ func genCreateBulk(h gen.GeneratorHelper, f *jen.File, t *gen.Type, createName,
 							if upsertEnabled {
 								batchDict[jen.Id("OnConflict")] = jen.Id("_cb").Dot("conflict")
 							}
+							genBulkShardKey(leaf, t)
 							leaf.Id("spec").Op(":=").Op("&").Qual(h.SQLGraphPkg(), "BatchCreateSpec").Values(batchDict)
 							leaf.If(
 								jen.Id("err").Op("=").Qual(h.SQLGraphPkg(), "BatchCreate").Call(
@@ -959,6 +986,89 @@ func genCreateBulk(h gen.GeneratorHelper, f *jen.File, t *gen.Type, createName,
 			jen.Panic(jen.Id("err")),
 		),
 	)
+
+	if h.FeatureEnabled(gen.FeatureBulkLoad.Name) {
+		genCreateBulkLoad(h, f, t, createName)
+	}
+}
+
+// genCreateBulkLoad generates the Copy and load methods of the CreateBulk
+// builder (sql/bulkload feature). Unlike Save, the rows are written with
+// sqlgraph.Load: defaults, validators and privacy policies run per row, but
+// the mutation hooks do not, and the IDs of the rows are not read back.
+func genCreateBulkLoad(h gen.GeneratorHelper, f *jen.File, t *gen.Type, createName string) {
+	bulkName := t.CreateBulkName()
+	f.Commentf("Copy writes the %s entities in bulk and returns the number of rows written.", t.Name)
+	f.Comment("On PostgreSQL with the lib/pq driver, rows are streamed with COPY FROM STDIN.")
+	f.Comment("Otherwise, they are written with multi-row INSERT statements sized to the")
+	f.Comment("parameter limit of the dialect.")
+	f.Comment("")
+	f.Comment("Defaults, validators and privacy policies run for every row, but the")
+	f.Comment("mutation hooks (schema hooks and client.Use hooks) are bypassed, and the")
+	f.Comment("IDs of the created rows are not read back. Rows are committed in batches")
+	f.Comment("of BatchSize (default sqlgraph.DefaultLoadBatchSize); wrap the call in")
+	f.Comment("client.Tx(ctx) for all-or-nothing semantics.")
+	f.Func().Params(jen.Id("_cb").Op("*").Id(bulkName)).Id("Copy").Params(
+		jen.Id("ctx").Qual("context", "Context"),
+	).Params(jen.Int(), jen.Error()).Block(
+		jen.If(jen.Id("_cb").Dot("err").Op("!=").Nil()).Block(
+			jen.Return(jen.Lit(0), jen.Id("_cb").Dot("err")),
+		),
+		jen.Return(jen.Id("_cb").Dot("load").Call(
+			jen.Id("ctx"),
+			jen.Qual("slices", "Values").Call(jen.Id("_cb").Dot("builders")),
+		)),
+	)
+
+	f.Comment("load writes the builders yielded by seq with sqlgraph.Load.")
+	f.Func().Params(jen.Id("_cb").Op("*").Id(bulkName)).Id("load").Params(
+		jen.Id("ctx").Qual("context", "Context"),
+		jen.Id("seq").Qual("iter", "Seq").Index(jen.Op("*").Id(createName)),
+	).Params(jen.Int(), jen.Error()).Block(
+		jen.List(jen.Id("next"), jen.Id("stop")).Op(":=").Qual("iter", "Pull").Call(jen.Id("seq")),
+		jen.Defer().Id("stop").Call(),
+		jen.List(jen.Id("n"), jen.Id("err")).Op(":=").Qual(h.SQLGraphPkg(), "Load").Call(
+			jen.Id("ctx"),
+			jen.Id("_cb").Dot("config").Dot("Driver"),
+			jen.Op("&").Qual(h.SQLGraphPkg(), "LoadSpec").Values(jen.Dict{
+				jen.Id("BatchSize"): jen.Id("_cb").Dot("batchSize"),
+				jen.Id("Next"): jen.Func().Params().Params(
+					jen.Op("*").Qual(h.SQLGraphPkg(), "CreateSpec"),
+					jen.Error(),
+				).BlockFunc(func(grp *jen.Group) {
+					grp.List(jen.Id("builder"), jen.Id("ok")).Op(":=").Id("next").Call()
+					grp.If(jen.Op("!").Id("ok")).Block(
+						jen.Return(jen.Nil(), jen.Nil()),
+					)
+					genFieldPolicyCheck(h, grp, t, jen.Id("builder").Dot("mutation"), jen.Nil())
+					if t.NumPolicy() > 0 {
+						grp.If(jen.Id("builder").Dot("policy").Op("!=").Nil()).Block(
+							jen.If(jen.Id("err").Op(":=").Id("builder").Dot("policy").Dot("EvalMutation").Call(
+								jen.Id("ctx"), jen.Id("builder").Dot("mutation"),
+							), jen.Id("err").Op("!=").Nil()).Block(
+								jen.Return(jen.Nil(), jen.Id("err")),
+							),
+						)
+					}
+					if t.NeedsDefaults() {
+						grp.If(jen.Id("err").Op(":=").Id("builder").Dot("defaults").Call(), jen.Id("err").Op("!=").Nil()).Block(
+							jen.Return(jen.Nil(), jen.Id("err")),
+						)
+					}
+					grp.If(jen.Id("err").Op(":=").Id("builder").Dot("check").Call(), jen.Id("err").Op("!=").Nil()).Block(
+						jen.Return(jen.Nil(), jen.Id("err")),
+					)
+					genEntityValidateCall(grp, t, jen.Id("builder"), jen.Nil())
+					grp.List(jen.Id("_"), jen.Id("spec")).Op(":=").Id("builder").Dot("createSpec").Call()
+					grp.Return(jen.Id("spec"), jen.Nil())
+				}),
+			}),
+		),
+		jen.If(jen.Id("err").Op("!=").Nil()).Block(
+			jen.Id("err").Op("=").Qual(runtimePkg, "MayWrapConstraintError").Call(jen.Id("err")),
+		),
+		jen.Return(jen.Id("n"), jen.Id("err")),
+	)
 }
 
 // genCreateUpsert generates OnConflict/OnConflictColumns methods and the Upsert builder
@@ -1390,3 +1500,15 @@ func chainReturnType(builderName string, ifaceReturn jen.Code) jen.Code {
 	}
 	return jen.Op("*").Id(builderName)
 }
+
+// genFieldPolicyCheck generates the write check of field policies for a
+// builder's mutation. It is emitted before defaults() so only the fields set
+// by the caller are checked. zero is the first return value on failure.
+func genFieldPolicyCheck(h gen.GeneratorHelper, grp *jen.Group, t *gen.Type, mutation *jen.Statement, zero jen.Code) {
+	if !h.FeatureEnabled(gen.FeaturePrivacy.Name) || len(t.FieldPolicyFields()) == 0 {
+		return
+	}
+	grp.If(jen.Id("err").Op(":=").Add(mutation).Dot("checkFieldPolicies").Call(jen.Id("ctx")), jen.Id("err").Op("!=").Nil()).Block(
+		jen.Return(zero, jen.Id("err")),
+	)
+}
@@ -4,6 +4,7 @@ import (
 	"github.com/dave/jennifer/jen"
 
 	"github.com/syssam/velox/compiler/gen"
+	"github.com/syssam/velox/schema/edge"
 )
 
 // genDelete generates delete builders as a standalone file.
@@ -91,10 +92,21 @@ func genDeleteInto(h gen.GeneratorHelper, f *jen.File, t *gen.Type) {
 		if h.FeatureEnabled(gen.FeatureSchemaConfig.Name) {
 			baseDict[jen.Id("Schema")] = jen.Id(recv).Dot("schemaConfig").Dot(t.Name)
 		}
+		if len(counterCacheFields(h.Graph(), t)) > 0 {
+			baseDict[jen.Id("Counters")] = jen.Qual(entityPkg, "CounterCaches")
+		}
 		grp.Id("base").Op(":=").Op("&").Qual(runtimePkg, "DeleterBase").Values(baseDict)
-		grp.List(jen.Id("affected"), jen.Id("err")).Op(":=").Qual(runtimePkg, "DeleteNodes").Call(
-			jen.Id("ctx"), jen.Id("base"),
-		)
+		if cascades := genCascades(h, recv, entityPkg, t); cascades != nil {
+			// Dependents of edge.Cascade edges are handled by their own builders,
+			// in the transaction of the delete.
+			grp.List(jen.Id("affected"), jen.Id("err")).Op(":=").Qual(runtimePkg, "DeleteCascade").Call(
+				jen.Id("ctx"), jen.Id(recv).Dot("config"), jen.Id("base"), jen.Lit(t.Name), cascades,
+			)
+		} else {
+			grp.List(jen.Id("affected"), jen.Id("err")).Op(":=").Qual(runtimePkg, "DeleteNodes").Call(
+				jen.Id("ctx"), jen.Id("base"),
+			)
+		}
 		grp.If(jen.Id("err").Op("!=").Nil()).Block(
 			jen.Return(jen.Lit(0), jen.Id("err")),
 		)
@@ -207,3 +219,94 @@ func genDeleteInto(h gen.GeneratorHelper, f *jen.File, t *gen.Type) {
 		jen.Return(jen.Id(recv).Dot(recv[1:]).Dot("mutation")),
 	)
 }
+
+// cascadeActions maps the edge.Cascade actions to their identifiers in the edge package.
+var cascadeActions = map[edge.CascadeAction]string{
+	edge.Delete:   "Delete",
+	edge.SetNull:  "SetNull",
+	edge.Restrict: "Restrict",
+}
+
+// genCascades returns the []runtime.Cascade literal of the cascade edges
+// of the type (edge.Cascade), or nil if the type has none.
+func genCascades(h gen.GeneratorHelper, recv, entityPkg string, t *gen.Type) jen.Code {
+	edges := t.CascadeEdges()
+	if len(edges) == 0 {
+		return nil
+	}
+	return jen.Index().Qual(runtimePkg, "Cascade").ValuesFunc(func(grp *jen.Group) {
+		for _, e := range edges {
+			c := jen.Dict{
+				jen.Id("Edge"):   jen.Lit(e.Name),
+				jen.Id("Type"):   jen.Lit(e.Type.Name),
+				jen.Id("Table"):  jen.Qual(entityPkg, e.TableConstant()),
+				jen.Id("Column"): jen.Qual(entityPkg, e.ColumnConstant()),
+				jen.Id("Action"): jen.Qual(edgePkg, cascadeActions[e.Cascade()]),
+			}
+			if h.FeatureEnabled(gen.FeatureSchemaConfig.Name) {
+				c[jen.Id("Schema")] = jen.Id(recv).Dot("schemaConfig").Dot(e.Type.Name)
+			}
+			if e.Ref != nil {
+				c[jen.Id("Inverse")] = jen.Lit(e.Ref.Name)
+			}
+			grp.Values(c)
+		}
+	})
+}
+
+// isCascadeDependent reports if the type holds the dependents of a cascade
+// edge (edge.Cascade) of a type in the graph.
+func isCascadeDependent(g *gen.Graph, t *gen.Type) bool {
+	for _, n := range g.Nodes {
+		for _, e := range n.CascadeEdges() {
+			if e.Type != nil && e.Type.Name == t.Name {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// genCascadeFunc generates the runtime.CascadeFunc of a type that holds the
+// dependents of cascade edges. The dependents are deleted or cleared through
+// the generated builders of the type, so its hooks, privacy policy and
+// interceptors apply. The dependents of edge.Restrict are counted by
+// runtime.DeleteCascade instead, including the ones hidden from the viewer:
+//
+//	func(ctx context.Context, cfg runtime.Config, c runtime.Cascade, ids []any) (int, error) {
+//		p := predicate.Post(func(s *sql.Selector) { s.Where(sql.In(s.C(c.Column), ids...)) })
+//		client := NewPostClient(cfg)
+//		if c.Action == edge.SetNull {
+//			...
+//		}
+//		return client.Delete().Where(p).Exec(ctx)
+//	}
+func genCascadeFunc(h gen.GeneratorHelper, t *gen.Type) jen.Code {
+	client := func() *jen.Statement { return jen.Id("client") }
+	return jen.Func().Params(
+		jen.Id("ctx").Qual("context", "Context"),
+		jen.Id("cfg").Qual(runtimePkg, "Config"),
+		jen.Id("c").Qual(runtimePkg, "Cascade"),
+		jen.Id("ids").Index().Any(),
+	).Params(jen.Int(), jen.Error()).Block(
+		jen.Id("p").Op(":=").Add(h.PredicateType(t)).Call(
+			jen.Func().Params(jen.Id("s").Op("*").Qual(h.SQLPkg(), "Selector")).Block(
+				jen.Id("s").Dot("Where").Call(jen.Qual(h.SQLPkg(), "In").Call(
+					jen.Id("s").Dot("C").Call(jen.Id("c").Dot("Column")), jen.Id("ids").Op("..."),
+				)),
+			),
+		),
+		client().Op(":=").Id("New"+t.ClientName()).Call(jen.Id("cfg")),
+		jen.If(jen.Id("c").Dot("Action").Op("==").Qual(edgePkg, "SetNull")).Block(
+			jen.Id("u").Op(":=").Add(client()).Dot("Update").Call().Dot("Where").Call(jen.Id("p")),
+			jen.If(
+				jen.Id("err").Op(":=").Id("u").Dot("Mutation").Call().Dot("ClearEdge").Call(jen.Id("c").Dot("Inverse")),
+				jen.Id("err").Op("!=").Nil(),
+			).Block(
+				jen.Return(jen.Lit(0), jen.Id("err")),
+			),
+			jen.Return(jen.Id("u").Dot("Save").Call(jen.Id("ctx"))),
+		),
+		jen.Return(client().Dot("Delete").Call().Dot("Where").Call(jen.Id("p")).Dot("Exec").Call(jen.Id("ctx"))),
+	)
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/syssam/velox/compiler/gen"
+	"github.com/syssam/velox/schema/edge"
 	"github.com/syssam/velox/schema/field"
 )
 
@@ -195,3 +196,47 @@ func TestGenDelete_ConvertPredicates(t *testing.T) {
 	// Predicate conversion pattern (uses public method for cross-package access)
 	assert.Contains(t, code, "mutation.PredicatesFuncs()")
 }
+
+// createCascadeTypes returns a User type whose "posts" edge cascades to the
+// dependent Post type with the given action.
+func createCascadeTypes(action edge.CascadeAction) (*gen.Type, *gen.Type) {
+	userType := createTestType("User")
+	postType := createTestType("Post")
+	postsEdge := createO2MEdge("posts", postType, "posts", "user_posts")
+	postsEdge.Annotations = gen.Annotations{edge.Annotation{}.Name(): edge.Cascade(action)}
+	authorEdge := createM2OEdge("author", userType, "posts", "user_posts")
+	postsEdge.Ref, authorEdge.Ref = authorEdge, postsEdge
+	userType.Edges = []*gen.Edge{postsEdge}
+	postType.Edges = []*gen.Edge{authorEdge}
+	return userType, postType
+}
+
+// TestGenDelete_Cascade verifies the delete builders of types with edge.Cascade
+// edges run the cascades in the transaction of runtime.DeleteCascade.
+func TestGenDelete_Cascade(t *testing.T) {
+	t.Parallel()
+	helper := newMockHelper()
+	userType, postType := createCascadeTypes(edge.SetNull)
+	helper.graph.Nodes = []*gen.Type{userType, postType}
+
+	code := testGenDelete(helper, userType).GoString()
+	assert.Contains(t, code, `affected, err := runtime.DeleteCascade(ctx, _d.config, base, "User", []runtime.Cascade{{
+		Action:  edge.SetNull,
+		Column:  user.PostsColumn,
+		Edge:    "posts",
+		Inverse: "author",
+		Table:   user.PostsTable,
+		Type:    "Post",
+	}})`)
+	assert.NotContains(t, code, "runtime.DeleteNodes")
+
+	// The dependents table is counted in its schema by the Restrict cascades.
+	fh := newFeatureMockHelper().withFeatures(gen.FeatureSchemaConfig.Name)
+	fh.graph.Nodes = []*gen.Type{userType, postType}
+	code = testGenDelete(fh, userType).GoString()
+	assert.Contains(t, code, "Schema:  _d.schemaConfig.Post,")
+
+	code = testGenDelete(helper, postType).GoString()
+	assert.Contains(t, code, "runtime.DeleteNodes")
+	assert.NotContains(t, code, "runtime.DeleteCascade")
+}
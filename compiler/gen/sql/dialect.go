@@ -207,6 +207,9 @@ func (d *Dialect) GenGlobalID() (*jen.File, error) { return genGlobalID(d.helper
 // GenEntQL generates the querylanguage.go file.
 func (d *Dialect) GenEntQL() (*jen.File, error) { return genEntQL(d.helper), nil }
 
+// GenFactory generates the factory/factory.go file.
+func (d *Dialect) GenFactory() (*jen.File, error) { return genFactory(d.helper), nil }
+
 // GenTypes generates the types.go file with shared type aliases.
 func (d *Dialect) GenTypes() (*jen.File, error) { return genTypes(d.helper), nil }
 
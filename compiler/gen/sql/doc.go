@@ -28,7 +28,7 @@
 //
 //   - Entity struct with fields and edge accessors (entity.go)
 //   - Create/CreateBulk builders for INSERT operations (create.go)
-//   - Update/UpdateOne builders for UPDATE operations (update.go)
+//   - Update/UpdateOne/UpdateBulk builders for UPDATE operations (update.go)
 //   - Delete/DeleteOne builders for DELETE operations (delete.go)
 //   - Query builder for SELECT operations with filtering (query.go)
 //   - Mutation type for tracking field changes (mutation.go)
@@ -71,6 +71,7 @@
 //   - FeaturePrivacy: ORM-level authorization policies
 //   - FeatureIntercept: Query interceptors for middleware
 //   - FeatureEntQL: Runtime query language
+//   - FeatureFactory: Test data factories
 //   - FeatureNamedEdges: Named edge loading
 //   - FeatureBidiEdgeRefs: Bidirectional edge references
 //   - FeatureSnapshot: Schema snapshot for migrations
@@ -79,6 +80,10 @@
 //   - FeatureModifier: Query modifiers
 //   - FeatureExecQuery: Raw SQL execution
 //   - FeatureUpsert: ON CONFLICT support
+//   - FeatureBulkLoad: COPY FROM STDIN / chunked bulk loads
+//   - FeatureOutbox: Transactional outbox hook and table
+//   - FeatureShard: Shard key routing for creates
+//   - FeatureRowSecurity: PostgreSQL row-level security for tenants
 //   - FeatureVersionedMigration: Versioned migrations
 //   - FeatureGlobalID: Relay Global ID
 //
@@ -58,6 +58,8 @@ func genEntityClient(h gen.GeneratorHelper, t *gen.Type) *jen.File {
 	genEntityClientQueryMethod(h, f, t)
 	genEntityClientGetMethods(h, f, t)
 	genEntityClientEdgeQueryMethods(h, f, t)
+	genEntityClientOneOfQueryMethods(h, f, t)
+	genEntityClientRecountMethods(h, f, t)
 
 	// Use adds mutation hooks to this entity client via direct field access.
 	f.Commentf("Use adds the mutation hooks to the %s.", clientName)
@@ -100,7 +102,7 @@ func genEntityClient(h gen.GeneratorHelper, t *gen.Type) *jen.File {
 }
 
 // genEntityClientCRUD generates Create, CreateBulk, MapCreateBulk, Update, Delete,
-// UpdateOneID, UpdateOne, DeleteOneID, DeleteOne methods for entity sub-package mode.
+// UpdateOneID, UpdateOne, UpdateBulk, MapUpdateBulk, DeleteOneID, DeleteOne methods for entity sub-package mode.
 // All builder types are local (same package), so no package qualification is needed.
 func genEntityClientCRUD(h gen.GeneratorHelper, f *jen.File, t *gen.Type) {
 	clientName := t.ClientName()
@@ -139,6 +141,22 @@ func genEntityClientCRUD(h gen.GeneratorHelper, f *jen.File, t *gen.Type) {
 		)),
 	)
 
+	// Load writes the builders of an iterator in bulk (sql/bulkload feature).
+	if h.FeatureEnabled(gen.FeatureBulkLoad.Name) {
+		f.Commentf("Load writes the %s entities built by the given builders in bulk and", t.Name)
+		f.Comment("returns the number of rows written. The builders are consumed lazily, in")
+		f.Commentf("batches. See %s.Copy for the semantics.", bulkName)
+		f.Func().Params(jen.Id("c").Op("*").Id(clientName)).Id("Load").Params(
+			jen.Id("ctx").Qual("context", "Context"),
+			jen.Id("builders").Qual("iter", "Seq").Index(jen.Op("*").Id(t.CreateName())),
+		).Params(jen.Int(), jen.Error()).Block(
+			jen.Return(jen.Id("New"+bulkName).Call(
+				jen.Id("c").Dot("config"),
+				jen.Nil(),
+			).Dot("load").Call(jen.Id("ctx"), jen.Id("builders"))),
+		)
+	}
+
 	// MapCreateBulk creates a bulk builder from a slice using a mapping function.
 	f.Commentf("MapCreateBulk creates a bulk creation builder from the given slice.")
 	f.Commentf("For each item in the slice, the set function is called to configure the")
@@ -239,6 +257,55 @@ func genEntityClientCRUD(h gen.GeneratorHelper, f *jen.File, t *gen.Type) {
 		jen.Return(jen.Id("c").Dot("UpdateOneID").Call(jen.Id("v").Dot("ID"))),
 	)
 
+	// UpdateBulk batches update-one builders, each with its own values.
+	updateBulkName := t.UpdateBulkName()
+	f.Commentf("UpdateBulk returns a builder for updating a bulk of %s entities, each with", t.Name)
+	f.Comment("its own values. The builders are usually created by UpdateOneID or UpdateOne.")
+	f.Func().Params(jen.Id("c").Op("*").Id(clientName)).Id("UpdateBulk").Params(
+		jen.Id("builders").Op("...").Op("*").Id(t.UpdateOneName()),
+	).Op("*").Id(updateBulkName).Block(
+		jen.Return(jen.Id("New"+updateBulkName).Call(
+			jen.Id("c").Dot("config"),
+			jen.Id("builders"),
+		)),
+	)
+
+	// MapUpdateBulk creates a bulk update builder from a slice using mapping functions.
+	f.Commentf("MapUpdateBulk creates a bulk update builder from the given slice.")
+	f.Comment("For each item in the slice, the id function returns the id of the entity")
+	f.Comment("to update, and the set function is called to configure its builder.")
+	f.Func().Params(jen.Id("c").Op("*").Id(clientName)).Id("MapUpdateBulk").Params(
+		jen.Id("slice").Any(),
+		jen.Id("idFunc").Func().Params(jen.Int()).Add(idType),
+		jen.Id("setFunc").Func().Params(
+			jen.Op("*").Id(t.UpdateOneName()),
+			jen.Int(),
+		),
+	).Op("*").Id(updateBulkName).Block(
+		jen.Id("rv").Op(":=").Qual("reflect", "ValueOf").Call(jen.Id("slice")),
+		jen.If(jen.Id("rv").Dot("Kind").Call().Op("!=").Qual("reflect", "Slice")).Block(
+			jen.Return(jen.Op("&").Id(updateBulkName).Values(jen.Dict{
+				jen.Id("err"): jen.Qual("fmt", "Errorf").Call(
+					jen.Lit("calling to %T.MapUpdateBulk with wrong type %T, need slice"),
+					jen.Id("c"),
+					jen.Id("slice"),
+				),
+			})),
+		),
+		jen.Id("builders").Op(":=").Make(
+			jen.Index().Op("*").Id(t.UpdateOneName()),
+			jen.Id("rv").Dot("Len").Call(),
+		),
+		jen.For(jen.Id("i").Op(":=").Lit(0), jen.Id("i").Op("<").Id("rv").Dot("Len").Call(), jen.Id("i").Op("++")).Block(
+			jen.Id("builders").Index(jen.Id("i")).Op("=").Id("c").Dot("UpdateOneID").Call(jen.Id("idFunc").Call(jen.Id("i"))),
+			jen.Id("setFunc").Call(jen.Id("builders").Index(jen.Id("i")), jen.Id("i")),
+		),
+		jen.Return(jen.Id("New"+updateBulkName).Call(
+			jen.Id("c").Dot("config"),
+			jen.Id("builders"),
+		)),
+	)
+
 	// DeleteOneID returns a delete-one builder for the given id.
 	f.Commentf("DeleteOneID returns a delete builder for the given id.")
 	f.Func().Params(jen.Id("c").Op("*").Id(clientName)).Id("DeleteOneID").Params(
@@ -311,6 +378,43 @@ func genEntityClientQueryMethod(h gen.GeneratorHelper, f *jen.File, t *gen.Type)
 	})
 }
 
+// genEntityClientRecountMethods generates a RecountXxx method for each counter
+// cache (edge.CounterCache) of the type. The counter is recomputed from the
+// rows of the counted edge, e.g. after rows were changed with raw SQL:
+//
+//	func (c *UserClient) RecountPostsCount(ctx context.Context, ps ...predicate.User) (int, error)
+func genEntityClientRecountMethods(h gen.GeneratorHelper, f *jen.File, t *gen.Type) {
+	clientName := t.ClientName()
+	for i, c := range counterCacheFields(h.Graph(), t) {
+		if c.owner.Name != t.Name {
+			continue
+		}
+		name := "Recount" + c.field.StructField()
+		f.Commentf("%s recomputes the %q counter cache of the %s entities that match", name, c.field.Name, t.Name)
+		f.Comment("the given predicates, or of all entities if none is given, and returns the")
+		f.Comment("number of updated entities.")
+		f.Func().Params(jen.Id("c").Op("*").Id(clientName)).Id(name).Params(
+			jen.Id("ctx").Qual("context", "Context"),
+			jen.Id("ps").Op("...").Add(h.PredicateType(t)),
+		).Params(jen.Int(), jen.Error()).Block(
+			jen.Var().Id("pred").Func().Params(jen.Op("*").Qual(h.SQLPkg(), "Selector")),
+			jen.If(jen.Len(jen.Id("ps")).Op(">").Lit(0)).Block(
+				jen.Id("pred").Op("=").Func().Params(jen.Id("s").Op("*").Qual(h.SQLPkg(), "Selector")).Block(
+					jen.For(jen.List(jen.Id("_"), jen.Id("p")).Op(":=").Range().Id("ps")).Block(
+						jen.Id("p").Call(jen.Id("s")),
+					),
+				),
+			),
+			jen.Return(jen.Qual(h.SQLGraphPkg(), "RecountCounter").Call(
+				jen.Id("ctx"),
+				jen.Id("c").Dot("config").Dot("Driver"),
+				jen.Qual(h.LeafPkgPath(t), "CounterCaches").Index(jen.Lit(i)),
+				jen.Id("pred"),
+			)),
+		)
+	}
+}
+
 // genEntityClientGetMethods generates Get and GetX methods for entity sub-package mode.
 // Get uses Query().Where(...).Only(ctx) internally.
 func genEntityClientGetMethods(h gen.GeneratorHelper, f *jen.File, t *gen.Type) {
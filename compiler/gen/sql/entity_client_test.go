@@ -69,6 +69,34 @@ func TestGenEntityClient_CRUDMethods(t *testing.T) {
 	assert.Contains(t, code, "func (c *UserClient) DeleteOne(")
 }
 
+// TestGenEntityClient_BulkLoad verifies that the sql/bulkload feature adds
+// client.Load and CreateBulk.Copy, and that the default output has neither.
+func TestGenEntityClient_BulkLoad(t *testing.T) {
+	t.Parallel()
+	helper := newFeatureMockHelper()
+	helper.rootPkg = "github.com/test/project"
+	userType := createTestType("User")
+	helper.graph.Nodes = []*gen.Type{userType}
+
+	assert.NotContains(t, genEntityClient(helper, userType).GoString(), "Load(")
+	create, err := genCreate(helper, userType)
+	require.NoError(t, err)
+	assert.NotContains(t, create.GoString(), ") Copy(")
+
+	helper.withFeatures(gen.FeatureBulkLoad.Name)
+	client := genEntityClient(helper, userType)
+	assertValidGo(t, client, "user_client")
+	assert.Contains(t, client.GoString(), "func (c *UserClient) Load(ctx context.Context, builders iter.Seq[*UserCreate]) (int, error)")
+	create, err = genCreate(helper, userType)
+	require.NoError(t, err)
+	assertValidGo(t, create, "user_create")
+	code := create.GoString()
+	assert.Contains(t, code, "func (_cb *UserCreateBulk) Copy(ctx context.Context) (int, error)")
+	assert.Contains(t, code, "sqlgraph.Load(ctx, _cb.config.Driver, &sqlgraph.LoadSpec{")
+	assert.Contains(t, code, "builder.check()")
+	assert.Contains(t, code, "builder.createSpec()")
+}
+
 // TestGenEntityClient_QueryMethod verifies that Query() is generated using
 // runtime.NewEntityQuery (not direct query/ import).
 func TestGenEntityClient_QueryMethod(t *testing.T) {
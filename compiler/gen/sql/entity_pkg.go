@@ -76,6 +76,7 @@ func genEntityPkgFileWithRegistry(h gen.GeneratorHelper, t *gen.Type, allNodes [
 
 	// Generate Query{Edge} methods on the entity struct using registry dispatch.
 	genEntityPkgEdgeQueryMethods(h, f, t)
+	genEntityPkgOneOfQueryMethods(h, f, t)
 
 	// Generate Querier interface for cross-entity references.
 	genEntityPkgQuerierInterface(h, f, t)
@@ -163,10 +164,15 @@ func genEntityPkgEdgesStruct(h gen.GeneratorHelper, f *jen.File, t *gen.Type) {
 				group.Id("named" + edge.StructField()).Map(jen.String()).Index().Op("*").Id(edge.Type.Name)
 			}
 		}
+		for _, oe := range t.OneOfEdges {
+			group.Id(oe.StructField()).Id(oe.UnionName()).Tag(map[string]string{
+				"json": oe.Name + ",omitempty",
+			})
+		}
 
 		// loadedTypes bitmask tracks which edges have been loaded.
-		if len(t.Edges) > 0 {
-			group.Id("loadedTypes").Index(jen.Lit(len(t.Edges))).Bool()
+		if n := len(t.Edges) + len(t.OneOfEdges); n > 0 {
+			group.Id("loadedTypes").Index(jen.Lit(n)).Bool()
 		}
 
 		// totalCount field for GraphQL pagination - lazy-initialized map keyed by edge name.
@@ -180,6 +186,7 @@ func genEntityPkgEdgesStruct(h gen.GeneratorHelper, f *jen.File, t *gen.Type) {
 	for i, edge := range t.Edges {
 		genEntityPkgEdgeAccessors(f, t, edge, i)
 	}
+	genEntityPkgOneOfAccessors(f, t)
 
 	// Generate named edge methods when feature is enabled.
 	if namedEdgesEnabled {
@@ -775,6 +782,7 @@ func genEntityPkgQuerierInterface(h gen.GeneratorHelper, f *jen.File, t *gen.Typ
 			jen.Id("o").Op("...").Func().Params(jen.Op("*").Qual(sqlPkg, "Selector")),
 		).Id(ifaceName)
 		grp.Id("Unique").Params(jen.Id("unique").Bool()).Id(ifaceName)
+		grp.Id("Timeout").Params(jen.Id("d").Qual("time", "Duration")).Id(ifaceName)
 
 		// --- WithXxx edge eager loading ---
 		for _, e := range t.Edges {
@@ -784,6 +792,9 @@ func genEntityPkgQuerierInterface(h gen.GeneratorHelper, f *jen.File, t *gen.Typ
 				jen.Id("opts").Op("...").Func().Params(jen.Id(targetIface)),
 			).Id(ifaceName)
 		}
+		for _, oe := range t.OneOfEdges {
+			grp.Id("With" + oe.StructField()).Params().Id(ifaceName)
+		}
 
 		// --- Select / GroupBy / Aggregate / Modify ---
 		selectorName := t.Name + "Selector"
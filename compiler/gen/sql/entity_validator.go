@@ -0,0 +1,103 @@
+package sql
+
+import (
+	"github.com/dave/jennifer/jen"
+
+	"github.com/syssam/velox/compiler/gen"
+)
+
+// Entity validators (the Validators method of the schema) check invariants
+// that span several fields. The create, bulk-create and update-one builders
+// run them in validate(ctx), right after check() passed, on the state the
+// entity will have once the mutation is applied. They are not part of
+// check(), which takes no context: the validators receive the context of
+// the operation, and update-one loads the stored values with it.
+//
+// Update builders that mutate many rows by predicate do not run them, as
+// the stored values differ per row. For the same reason, upserts validate
+// the inserted values only; the values set on conflict are not validated.
+
+// genEntityValidate generates the validate method of a create or update-one
+// builder. Nothing is generated if the schema declares no validators.
+func genEntityValidate(h gen.GeneratorHelper, f *jen.File, t *gen.Type, builderName, recv string, updateOne bool) {
+	if t.NumValidators() == 0 {
+		return
+	}
+	old := jen.Nil()
+	if updateOne && len(entityValidateNillableFields(t)) > 0 {
+		old = jen.Id(recv).Dot("mutation").Dot("oldValidatedField")
+	}
+	f.Commentf("validate runs the entity validators of %s on the resolved state of the mutation.", t.Name)
+	f.Func().Params(jen.Id(recv).Op("*").Id(builderName)).Id("validate").Params(
+		jen.Id("ctx").Qual("context", "Context"),
+	).Error().Block(
+		jen.Return(jen.Qual(h.VeloxPkg(), "ValidateEntity").Call(
+			jen.Id("ctx"),
+			jen.Id(recv).Dot("mutation"),
+			old,
+			jen.Qual(h.LeafPkgPath(t), "Validators").Index(jen.Op(":")),
+		)),
+	)
+}
+
+// entityValidateNillableFields returns the fields whose stored NULL values
+// are flattened to zero values by OldField. It is empty if the type has no
+// entity validators or no mutable fields (no update-one loader).
+func entityValidateNillableFields(t *gen.Type) []*gen.Field {
+	if t.NumValidators() == 0 || len(t.MutableFields()) == 0 {
+		return nil
+	}
+	var fields []*gen.Field
+	for _, fd := range t.Fields {
+		if fd.IsEdgeField() && !fd.UserDefined {
+			continue
+		}
+		if fd.NillableValue() {
+			fields = append(fields, fd)
+		}
+	}
+	return fields
+}
+
+// genEntityValidateOld generates the mutation method that resolves stored
+// values for the entity validators. It differs from OldField by returning
+// nil for NULL values of nillable fields.
+func genEntityValidateOld(h gen.GeneratorHelper, f *jen.File, t *gen.Type, mutName string) {
+	fields := entityValidateNillableFields(t)
+	if len(fields) == 0 {
+		return
+	}
+	f.Comment("oldValidatedField returns the stored value of a field for the entity validators.")
+	f.Comment("Unlike OldField, NULL values of nillable fields are returned as nil.")
+	f.Func().Params(jen.Id("m").Op("*").Id(mutName)).Id("oldValidatedField").Params(
+		jen.Id("ctx").Qual("context", "Context"),
+		jen.Id("name").String(),
+	).Params(jen.Qual(h.VeloxPkg(), "Value"), jen.Error()).BlockFunc(func(body *jen.Group) {
+		body.Switch(jen.Id("name")).BlockFunc(func(sw *jen.Group) {
+			for _, fd := range fields {
+				sw.Case(jen.Lit(fd.Name)).Block(
+					jen.List(jen.Id("old"), jen.Id("err")).Op(":=").Id("m").Dot("loadOld").Call(jen.Id("ctx")),
+					jen.If(jen.Id("err").Op("!=").Nil()).Block(
+						jen.Return(jen.Nil(), jen.Id("err")),
+					),
+					jen.If(jen.Id("old").Dot(fd.StructField()).Op("==").Nil()).Block(
+						jen.Return(jen.Nil(), jen.Nil()),
+					),
+					jen.Return(jen.Op("*").Id("old").Dot(fd.StructField()), jen.Nil()),
+				)
+			}
+		})
+		body.Return(jen.Id("m").Dot("OldField").Call(jen.Id("ctx"), jen.Id("name")))
+	})
+}
+
+// genEntityValidateCall emits the call to the validate method of the given
+// builder. The emitted code returns (zero, err) on failure.
+func genEntityValidateCall(grp *jen.Group, t *gen.Type, builder jen.Code, zero jen.Code) {
+	if t.NumValidators() == 0 {
+		return
+	}
+	grp.If(jen.Id("err").Op(":=").Add(builder).Dot("validate").Call(jen.Id("ctx")), jen.Id("err").Op("!=").Nil()).Block(
+		jen.Return(zero, jen.Id("err")),
+	)
+}
@@ -0,0 +1,93 @@
+package sql
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/syssam/velox/compiler/gen"
+	"github.com/syssam/velox/compiler/load"
+	"github.com/syssam/velox/schema/field"
+)
+
+func newEntityValidatorHelper(t testing.TB) (*mockHelper, *gen.Type) {
+	t.Helper()
+	tm := &field.TypeInfo{Type: field.TypeTime}
+	h := newGraphHelper(t,
+		&load.Schema{Name: "Event", Fields: []*load.Field{
+			{Name: "starts_at", Info: tm},
+			{Name: "ends_at", Info: tm},
+			{Name: "coupon_code", Info: &field.TypeInfo{Type: field.TypeString}, Optional: true, Nillable: true},
+		}, Validators: []*load.Position{{Index: 0}, {Index: 1}}},
+		&load.Schema{Name: "Tag", Fields: []*load.Field{
+			{Name: "name", Info: &field.TypeInfo{Type: field.TypeString}, Optional: true, Nillable: true},
+		}},
+	)
+	return h, h.graph.Nodes[0]
+}
+
+func TestGenEntityValidators(t *testing.T) {
+	t.Parallel()
+	h, event := newEntityValidatorHelper(t)
+
+	pkg := genPackage(h, event, buildEntityPkgEnumRegistry(h.graph.Nodes)).GoString()
+	assert.Contains(t, pkg, "Validators [2]velox.EntityValidator")
+
+	runtime := genEntityRuntime(h, event)
+	assertValidGo(t, runtime, "event_runtime")
+	code := runtime.GoString()
+	assert.Contains(t, code, "eventValidators := schema.Event{}.Validators()")
+	assert.Contains(t, code, "event.Validators[1] = eventValidators[1]")
+
+	create, err := genCreate(h, event)
+	require.NoError(t, err)
+	assertValidGo(t, create, "event_create")
+	code = create.GoString()
+	assert.Contains(t, code, "func (c *EventCreate) validate(ctx context.Context) error {\n\treturn velox.ValidateEntity(ctx, c.mutation, nil, event.Validators[:])")
+	assert.Contains(t, code, "if err := c.validate(ctx); err != nil {")
+	assert.Contains(t, code, "if err := builder.validate(ctx); err != nil {")
+
+	update, err := genUpdate(h, event)
+	require.NoError(t, err)
+	assertValidGo(t, update, "event_update")
+	code = update.GoString()
+	assert.Contains(t, code, "return velox.ValidateEntity(ctx, _u.mutation, _u.mutation.oldValidatedField, event.Validators[:])")
+	assert.Contains(t, code, "if err := _u.validate(ctx); err != nil {")
+	assert.NotContains(t, code, "func (_u *EventUpdate) validate(")
+
+	mutation := genMutation(h, event)
+	assertValidGo(t, mutation, "event_mutation")
+	code = mutation.GoString()
+	assert.Contains(t, code, "func (m *EventMutation) oldValidatedField(ctx context.Context, name string) (velox.Value, error) {")
+	assert.Contains(t, code, "if old.CouponCode == nil {\n\t\t\treturn nil, nil\n\t\t}")
+}
+
+func TestGenEntityValidators_Upsert(t *testing.T) {
+	t.Parallel()
+	h, event := newEntityValidatorHelper(t)
+	fh := newFeatureMockHelper().withFeatures(gen.FeatureUpsert.Name)
+	fh.graph = h.graph
+
+	create, err := genCreate(fh, event)
+	require.NoError(t, err)
+	assertValidGo(t, create, "event_create")
+	code := create.GoString()
+	validate := strings.Index(code, "if err := c.validate(ctx); err != nil {")
+	conflict := strings.Index(code, "_spec.OnConflict = c.conflict")
+	require.True(t, validate > 0 && conflict > 0)
+	assert.Less(t, validate, conflict, "the inserted values of an upsert are validated")
+}
+
+func TestGenEntityValidators_None(t *testing.T) {
+	t.Parallel()
+	h, _ := newEntityValidatorHelper(t)
+	tag := h.graph.Nodes[1]
+
+	create, err := genCreate(h, tag)
+	require.NoError(t, err)
+	assert.NotContains(t, create.GoString(), "validate(ctx")
+	assert.NotContains(t, genMutation(h, tag).GoString(), "oldValidatedField")
+	assert.NotContains(t, genPackage(h, tag, buildEntityPkgEnumRegistry(h.graph.Nodes)).GoString(), "EntityValidator")
+}
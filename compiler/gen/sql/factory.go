@@ -0,0 +1,357 @@
+package sql
+
+import (
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/dave/jennifer/jen"
+
+	"github.com/syssam/velox/compiler/gen"
+	"github.com/syssam/velox/schema/field"
+)
+
+// factoryPkg is the import path of the runtime value generators of the factories.
+const factoryPkg = "github.com/syssam/velox/factory"
+
+// genFactory generates the factory package (factory/factory.go).
+// This is part of the factory feature.
+//
+// Every entity type gets an XxxFactory that fills the required fields of the
+// generated create builder with deterministic values, derived from a per-type
+// sequence number and the constraints of the built-in validators. Required
+// edges that no trait sets are satisfied by creating the parent entity with
+// its own factory.
+func genFactory(h gen.GeneratorHelper) *jen.File {
+	f := h.NewFile("factory")
+	graph := h.Graph()
+	f.ImportName(factoryPkg, "factory")
+	f.ImportName(graph.Package, h.Pkg())
+	f.ImportName(h.SharedEntityPkg(), "entity")
+
+	var nodes []*gen.Type
+	for _, t := range graph.Nodes {
+		if !t.IsView() {
+			nodes = append(nodes, t)
+		}
+	}
+
+	f.Comment("Factory creates entities with valid, deterministic values for tests.")
+	f.Comment("Each entity type has its own sequence of numbers, starting at 1.")
+	f.Type().Id("Factory").StructFunc(func(grp *jen.Group) {
+		grp.Id("client").Op("*").Qual(graph.Package, "Client")
+		for _, t := range nodes {
+			grp.Id(factorySeq(t)).Qual(factoryPkg, "Sequence")
+		}
+	})
+
+	f.Comment("New returns a Factory that creates entities with the given client.")
+	f.Func().Id("New").Params(jen.Id("client").Op("*").Qual(graph.Package, "Client")).Op("*").Id("Factory").Block(
+		jen.Return(jen.Op("&").Id("Factory").Values(jen.Dict{jen.Id("client"): jen.Id("client")})),
+	)
+
+	for _, t := range nodes {
+		genEntityFactory(h, f, t)
+	}
+	return f
+}
+
+// factorySeq returns the name of the sequence field of the type in the Factory struct.
+func factorySeq(t *gen.Type) string {
+	return strings.ToLower(t.Name[:1]) + t.Name[1:] + "Seq"
+}
+
+// genEntityFactory generates the XxxFactory type of t.
+func genEntityFactory(h gen.GeneratorHelper, f *jen.File, t *gen.Type) {
+	graph := h.Graph()
+	name := t.Name + "Factory"
+	clientPkg := graph.Package + "/client/" + t.PackageDir()
+	f.ImportName(clientPkg, t.PackageDir()+"client")
+	builder := jen.Op("*").Qual(clientPkg, t.CreateName())
+	trait := jen.Func().Params(jen.Int(), builder.Clone())
+	entityType := jen.Op("*").Qual(h.SharedEntityPkg(), t.Name)
+
+	f.Commentf("%s returns the factory of the %s entities.", t.Name, t.Name)
+	f.Func().Params(jen.Id("f").Op("*").Id("Factory")).Id(t.Name).Params().Op("*").Id(name).Block(
+		jen.Return(jen.Op("&").Id(name).Values(jen.Dict{jen.Id("factory"): jen.Id("f")})),
+	)
+
+	f.Commentf("%s creates %s entities. The traits are applied in order, after the default values.", name, t.Name)
+	f.Type().Id(name).Struct(
+		jen.Id("factory").Op("*").Id("Factory"),
+		jen.Id("traits").Index().Add(trait.Clone()),
+	)
+
+	f.Comment("With returns a copy of the factory that applies the given traits to the builders.")
+	f.Func().Params(jen.Id("_f").Op("*").Id(name)).Id("With").Params(
+		jen.Id("traits").Op("...").Func().Params(builder.Clone()),
+	).Op("*").Id(name).Block(
+		jen.Id("c").Op(":=").Op("&").Id(name).Values(jen.Dict{
+			jen.Id("factory"): jen.Id("_f").Dot("factory"),
+			jen.Id("traits"):  jen.Qual("slices", "Clip").Call(jen.Id("_f").Dot("traits")),
+		}),
+		jen.For(jen.List(jen.Id("_"), jen.Id("t")).Op(":=").Range().Id("traits")).Block(
+			jen.Id("c").Dot("traits").Op("=").Append(jen.Id("c").Dot("traits"), jen.Func().Params(jen.Id("_").Int(), jen.Id("b").Add(builder.Clone())).Block(
+				jen.Id("t").Call(jen.Id("b")),
+			)),
+		),
+		jen.Return(jen.Id("c")),
+	)
+
+	f.Comment("Sequence returns a copy of the factory that applies the given traits to the builders,")
+	f.Comment("with the sequence number of the builder. For example, for unique values.")
+	f.Func().Params(jen.Id("_f").Op("*").Id(name)).Id("Sequence").Params(
+		jen.Id("traits").Op("...").Add(trait.Clone()),
+	).Op("*").Id(name).Block(
+		jen.Return(jen.Op("&").Id(name).Values(jen.Dict{
+			jen.Id("factory"): jen.Id("_f").Dot("factory"),
+			jen.Id("traits"):  jen.Append(jen.Qual("slices", "Clip").Call(jen.Id("_f").Dot("traits")), jen.Id("traits").Op("...")),
+		})),
+	)
+
+	genEntityFactoryBuilder(h, f, t, name, builder)
+
+	f.Commentf("Create creates a %s entity.", t.Name)
+	f.Func().Params(jen.Id("_f").Op("*").Id(name)).Id("Create").Params(
+		jen.Id("ctx").Qual("context", "Context"),
+	).Params(entityType.Clone(), jen.Error()).Block(
+		jen.List(jen.Id("b"), jen.Id("err")).Op(":=").Id("_f").Dot("Builder").Call(jen.Id("ctx")),
+		jen.If(jen.Id("err").Op("!=").Nil()).Block(jen.Return(jen.Nil(), jen.Id("err"))),
+		jen.Return(jen.Id("b").Dot("Save").Call(jen.Id("ctx"))),
+	)
+
+	f.Comment("CreateX is like Create, but panics if an error occurs.")
+	f.Func().Params(jen.Id("_f").Op("*").Id(name)).Id("CreateX").Params(
+		jen.Id("ctx").Qual("context", "Context"),
+	).Add(entityType.Clone()).Block(
+		jen.List(jen.Id("v"), jen.Id("err")).Op(":=").Id("_f").Dot("Create").Call(jen.Id("ctx")),
+		jen.If(jen.Id("err").Op("!=").Nil()).Block(jen.Panic(jen.Id("err"))),
+		jen.Return(jen.Id("v")),
+	)
+
+	f.Commentf("CreateMany creates n %s entities in one bulk statement. The required", t.Name)
+	f.Comment("parents that the traits do not set are created one by one, before.")
+	f.Func().Params(jen.Id("_f").Op("*").Id(name)).Id("CreateMany").Params(
+		jen.Id("ctx").Qual("context", "Context"),
+		jen.Id("n").Int(),
+	).Params(jen.Index().Add(entityType.Clone()), jen.Error()).Block(
+		jen.Id("builders").Op(":=").Make(jen.Index().Add(builder.Clone()), jen.Id("n")),
+		jen.For(jen.Id("i").Op(":=").Range().Id("builders")).Block(
+			jen.List(jen.Id("b"), jen.Id("err")).Op(":=").Id("_f").Dot("Builder").Call(jen.Id("ctx")),
+			jen.If(jen.Id("err").Op("!=").Nil()).Block(jen.Return(jen.Nil(), jen.Id("err"))),
+			jen.Id("builders").Index(jen.Id("i")).Op("=").Id("b"),
+		),
+		jen.Return(jen.Id("_f").Dot("factory").Dot("client").Dot(t.Name).Dot("CreateBulk").Call(jen.Id("builders").Op("...")).Dot("Save").Call(jen.Id("ctx"))),
+	)
+
+	f.Comment("CreateManyX is like CreateMany, but panics if an error occurs.")
+	f.Func().Params(jen.Id("_f").Op("*").Id(name)).Id("CreateManyX").Params(
+		jen.Id("ctx").Qual("context", "Context"),
+		jen.Id("n").Int(),
+	).Index().Add(entityType.Clone()).Block(
+		jen.List(jen.Id("v"), jen.Id("err")).Op(":=").Id("_f").Dot("CreateMany").Call(jen.Id("ctx"), jen.Id("n")),
+		jen.If(jen.Id("err").Op("!=").Nil()).Block(jen.Panic(jen.Id("err"))),
+		jen.Return(jen.Id("v")),
+	)
+}
+
+// genEntityFactoryBuilder generates the Builder method of the XxxFactory type.
+func genEntityFactoryBuilder(h gen.GeneratorHelper, f *jen.File, t *gen.Type, name string, builder *jen.Statement) {
+	f.Comment("Builder returns a create builder with the default values of the next sequence")
+	f.Comment("number and the traits applied. The required edges that the traits do not set")
+	f.Comment("are set to new entities, created with the factories of their types.")
+	f.Func().Params(jen.Id("_f").Op("*").Id(name)).Id("Builder").Params(
+		jen.Id("ctx").Qual("context", "Context"),
+	).Params(builder.Clone(), jen.Error()).BlockFunc(func(grp *jen.Group) {
+		grp.Id("n").Op(":=").Id("_f").Dot("factory").Dot(factorySeq(t)).Dot("Next").Call()
+		grp.Id("b").Op(":=").Id("_f").Dot("factory").Dot("client").Dot(t.Name).Dot("Create").Call()
+		if t.HasOneFieldID() && t.ID.UserDefined && !t.ID.Default {
+			if v, ok := factoryValue(h, t.ID); ok {
+				grp.Id("b").Dot("SetID").Call(v)
+			}
+		}
+		for _, fd := range t.Fields {
+			if fd.Optional || fd.Default || fd.IsEdgeField() || fd.ReadOnly() {
+				continue
+			}
+			if v, ok := factoryValue(h, fd); ok {
+				grp.Id("b").Dot("Set" + fd.StructField()).Call(v)
+			}
+		}
+		grp.For(jen.List(jen.Id("_"), jen.Id("t")).Op(":=").Range().Id("_f").Dot("traits")).Block(
+			jen.Id("t").Call(jen.Id("n"), jen.Id("b")),
+		)
+		for _, e := range t.EdgesWithID() {
+			// Edges that lead back to t through required edges, such as self-references,
+			// cannot be satisfied by recursion, and must be set by a trait.
+			if e.Optional || e.Type.IsView() || factoryReaches(e.Type, t, map[*gen.Type]bool{}) {
+				continue
+			}
+			var (
+				unset []jen.Code
+				set   jen.Code
+			)
+			switch fd := e.Field(); {
+			case fd != nil:
+				unset = []jen.Code{
+					jen.List(jen.Id("_"), jen.Id("ok")).Op(":=").Id("b").Dot("Mutation").Call().Dot(fd.MutationGet()).Call(),
+					jen.Op("!").Id("ok"),
+				}
+				set = jen.Id("b").Dot("Set" + fd.StructField()).Call(jen.Id("v").Dot("ID"))
+			case e.Unique:
+				unset = []jen.Code{jen.Len(jen.Id("b").Dot("Mutation").Call().Dot(e.StructField() + "IDs").Call()).Op("==").Lit(0)}
+				set = jen.Id("b").Dot(e.MutationSet()).Call(jen.Id("v").Dot("ID"))
+			default:
+				unset = []jen.Code{jen.Len(jen.Id("b").Dot("Mutation").Call().Dot(e.StructField() + "IDs").Call()).Op("==").Lit(0)}
+				set = jen.Id("b").Dot(e.MutationAdd()).Call(jen.Id("v").Dot("ID"))
+			}
+			grp.If(unset...).Block(
+				jen.List(jen.Id("v"), jen.Id("err")).Op(":=").Id("_f").Dot("factory").Dot(e.Type.Name).Call().Dot("Create").Call(jen.Id("ctx")),
+				jen.If(jen.Id("err").Op("!=").Nil()).Block(
+					jen.Return(jen.Nil(), jen.Qual("fmt", "Errorf").Call(
+						jen.Lit("factory: creating the "+strconv.Quote(e.Name)+" edge of "+t.Name+": %w"),
+						jen.Id("err"),
+					)),
+				),
+				set,
+			)
+		}
+		grp.Return(jen.Id("b"), jen.Nil())
+	})
+}
+
+// factoryReaches reports if the type from is the type to, or if its required
+// edges lead to it. The factory of from would then create a "to" entity that
+// creates a "from" entity in turn, without end.
+func factoryReaches(from, to *gen.Type, visited map[*gen.Type]bool) bool {
+	if from == to {
+		return true
+	}
+	if visited[from] {
+		return false
+	}
+	visited[from] = true
+	for _, e := range from.EdgesWithID() {
+		if !e.Optional && !e.Type.IsView() && factoryReaches(e.Type, to, visited) {
+			return true
+		}
+	}
+	return false
+}
+
+// factoryValue returns the expression of the n-th value of a required field,
+// or false if no value can be derived, e.g. for JSON fields or Go types that
+// are not based on a basic type. Such fields must be set by a trait.
+func factoryValue(h gen.GeneratorHelper, fd *gen.Field) (jen.Code, bool) {
+	n := jen.Id("n")
+	c := fd.Constraints()
+	if c == nil {
+		c = &field.Constraints{}
+	}
+	// convert converts the value to the custom Go type of the field.
+	convert := func(v jen.Code) jen.Code {
+		if fd.HasGoType() {
+			return jen.Add(h.BaseType(fd)).Call(v)
+		}
+		return v
+	}
+	switch {
+	case fd.IsEnum():
+		values := []jen.Code{n}
+		for _, v := range fd.EnumValues() {
+			values = append(values, jen.Lit(v))
+		}
+		return jen.Qual(factoryPkg, "Enum").Types(h.BaseType(fd)).Call(values...), len(values) > 1
+	case fd.IsUUID():
+		if fd.Type.RType == nil || fd.Type.RType.Kind != reflect.Array {
+			return nil, false
+		}
+		return jen.Add(h.BaseType(fd)).Call(jen.Qual(factoryPkg, "UUID").Call(n)), true
+	case !fd.ConvertedToBasic():
+		return nil, false
+	case fd.IsString():
+		minLen, maxLen := factoryLen(c)
+		if c.Pattern != "" {
+			return convert(jen.Qual(factoryPkg, "Match").Call(n, jen.Lit(c.Pattern), jen.Lit(minLen), jen.Lit(maxLen))), true
+		}
+		return convert(jen.Qual(factoryPkg, "String").Call(n, jen.Lit(fd.Name), jen.Lit(minLen), jen.Lit(maxLen))), true
+	case fd.IsBytes():
+		return jen.Add(h.BaseType(fd)).Call(jen.Qual(factoryPkg, "String").Call(n, jen.Lit(fd.Name), jen.Lit(c.MinLen), jen.Lit(c.MaxLen))), true
+	case fd.IsBool():
+		return convert(n.Clone().Op("%").Lit(2).Op("==").Lit(0)), true
+	case fd.IsTime():
+		return convert(jen.Qual(factoryPkg, "Time").Call(n)), true
+	case fd.Type != nil && fd.Type.Numeric():
+		typ := h.BaseType(fd)
+		switch {
+		case c.Min != nil && c.Max != nil:
+			return jen.Qual(factoryPkg, "Range").Types(typ).Call(n, factoryBound(fd, *c.Min), factoryBound(fd, *c.Max)), true
+		case c.Min != nil:
+			return jen.Qual(factoryPkg, "Min").Types(typ).Call(n, factoryBound(fd, *c.Min)), true
+		case c.Max != nil:
+			return jen.Qual(factoryPkg, "Max").Types(typ).Call(n, factoryBound(fd, *c.Max)), true
+		default:
+			return jen.Qual(factoryPkg, "Number").Types(typ).Call(n), true
+		}
+	}
+	return nil, false
+}
+
+// factoryLen returns the length limits of a string field in bytes, where the
+// rune limits are applied as byte limits. That is exact for the ASCII values of
+// String, and keeps the values of Match within both limits in most cases.
+func factoryLen(c *field.Constraints) (minLen, maxLen int) {
+	minLen, maxLen = max(c.MinLen, c.MinRuneLen), c.MaxLen
+	if c.MaxRuneLen > 0 && (maxLen == 0 || c.MaxRuneLen < maxLen) {
+		maxLen = c.MaxRuneLen
+	}
+	return minLen, maxLen
+}
+
+// factoryBound returns a numeric bound of the field as an untyped constant.
+// Bounds at or beyond the limits of the field type are clamped to the limits,
+// since float64 cannot represent all of them and their constants overflow.
+func factoryBound(fd *gen.Field, v float64) jen.Code {
+	t := fd.Type.Type
+	if l, ok := factoryLimits[t]; ok {
+		switch {
+		case v >= l.max:
+			return jen.Qual("math", l.maxName)
+		case v <= l.min && l.minName != "":
+			return jen.Qual("math", l.minName)
+		case v <= l.min:
+			return jen.Lit(0)
+		}
+	}
+	switch {
+	case t == field.TypeFloat32 && v >= math.MaxFloat32:
+		return jen.Qual("math", "MaxFloat32")
+	case t == field.TypeFloat32 && v <= -math.MaxFloat32:
+		return jen.Op("-").Qual("math", "MaxFloat32")
+	case !t.Integer():
+		return jen.Id(strconv.FormatFloat(v, 'g', -1, 64))
+	case v < 0:
+		return jen.Id(strconv.FormatInt(int64(v), 10))
+	default:
+		return jen.Id(strconv.FormatUint(uint64(v), 10))
+	}
+}
+
+// factoryLimits are the limits of the integer types and their names in the
+// math package. The minimum of the unsigned types is 0.
+var factoryLimits = map[field.Type]struct {
+	min, max         float64
+	minName, maxName string
+}{
+	field.TypeInt:    {math.MinInt64, math.MaxInt64, "MinInt", "MaxInt"},
+	field.TypeInt8:   {math.MinInt8, math.MaxInt8, "MinInt8", "MaxInt8"},
+	field.TypeInt16:  {math.MinInt16, math.MaxInt16, "MinInt16", "MaxInt16"},
+	field.TypeInt32:  {math.MinInt32, math.MaxInt32, "MinInt32", "MaxInt32"},
+	field.TypeInt64:  {math.MinInt64, math.MaxInt64, "MinInt64", "MaxInt64"},
+	field.TypeUint:   {0, math.MaxUint64, "", "MaxUint"},
+	field.TypeUint8:  {0, math.MaxUint8, "", "MaxUint8"},
+	field.TypeUint16: {0, math.MaxUint16, "", "MaxUint16"},
+	field.TypeUint32: {0, math.MaxUint32, "", "MaxUint32"},
+	field.TypeUint64: {0, math.MaxUint64, "", "MaxUint64"},
+}
@@ -0,0 +1,139 @@
+package sql
+
+import (
+	"math"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/syssam/velox/compiler/gen"
+	"github.com/syssam/velox/compiler/load"
+	"github.com/syssam/velox/schema/field"
+)
+
+// factoryFields returns the load fields of the given field descriptors.
+func factoryFields(t *testing.T, fields ...interface{ Descriptor() *field.Descriptor }) []*load.Field {
+	t.Helper()
+	lf := make([]*load.Field, len(fields))
+	for i, f := range fields {
+		var err error
+		lf[i], err = load.NewField(f.Descriptor())
+		require.NoError(t, err)
+	}
+	return lf
+}
+
+func TestGenFactory(t *testing.T) {
+	t.Parallel()
+	helper := newMockHelper()
+	userType := createTypeWithSchemaFields(t, "User", factoryFields(t,
+		field.String("name").NotEmpty().MaxLen(16),
+		field.String("email").Match(regexp.MustCompile(`^[a-z]+@example\.com$`)),
+		field.Int("age").Range(18, 120),
+		field.Float("score").Positive(),
+		field.Enum("status").Values("active", "inactive"),
+		field.Bool("admin"),
+		field.String("nickname").Optional(),
+		field.String("role").Default("user"),
+	))
+	postType := createTypeWithSchemaFields(t, "Post", factoryFields(t,
+		field.Uint8("rank").Max(10),
+		field.Time("published_at"),
+	))
+	postType.Edges = []*gen.Edge{
+		createM2OEdge("author", userType, "posts", "user_posts"),
+		createM2OEdge("parent", postType, "posts", "post_parent"),
+		{Name: "editor", Type: userType, Unique: true, Optional: true, Rel: gen.Relation{Type: gen.M2O}},
+	}
+	helper.graph.Nodes = []*gen.Type{userType, postType}
+
+	file := genFactory(helper)
+	assertValidGo(t, file, "factory")
+	code := file.GoString()
+
+	// Per-entity factories and sequences.
+	assert.Contains(t, code, "func New(client *ent.Client) *Factory")
+	assert.Contains(t, code, "userSeq factory.Sequence")
+	assert.Contains(t, code, "func (f *Factory) Post() *PostFactory")
+	assert.Contains(t, code, "traits  []func(int, *userclient.UserCreate)")
+	assert.Contains(t, code, "func (_f *UserFactory) With(traits ...func(*userclient.UserCreate)) *UserFactory")
+	assert.Contains(t, code, "func (_f *UserFactory) Sequence(traits ...func(int, *userclient.UserCreate)) *UserFactory")
+	assert.Contains(t, code, "func (_f *UserFactory) CreateMany(ctx context.Context, n int) ([]*entity.User, error)")
+	assert.Contains(t, code, "_f.factory.client.User.CreateBulk(builders...).Save(ctx)")
+
+	// Validator-aware values of the required fields.
+	assert.Contains(t, code, `b.SetName(factory.String(n, "name", 1, 16))`)
+	assert.Contains(t, code, `b.SetEmail(factory.Match(n, "^[a-z]+@example\\.com$", 0, 0))`)
+	assert.Contains(t, code, "b.SetAge(factory.Range[int](n, 18, 120))")
+	assert.Contains(t, code, "b.SetScore(factory.Min[float64](n, 0))")
+	assert.Contains(t, code, "b.SetAdmin(n%2 == 0)")
+	assert.Contains(t, code, "b.SetRank(factory.Max[uint8](n, 10))")
+	assert.Contains(t, code, "b.SetPublishedAt(factory.Time(n))")
+	assert.Contains(t, code, `factory.Enum[`)
+	assert.Contains(t, code, `](n, "active", "inactive")`)
+	assert.NotContains(t, code, "SetNickname", "optional fields keep their zero value")
+	assert.NotContains(t, code, "SetRole", "fields with defaults keep their default")
+
+	// Required edges are created recursively, unless set by a trait.
+	assert.Contains(t, code, "if len(b.Mutation().AuthorIDs()) == 0 {")
+	assert.Contains(t, code, "v, err := _f.factory.User().Create(ctx)")
+	assert.Contains(t, code, `creating the \"author\" edge of Post: %w`)
+	assert.Contains(t, code, "b.SetAuthorID(v.ID)")
+	assert.NotContains(t, code, "ParentIDs", "self-references must be set by a trait")
+	assert.NotContains(t, code, "EditorIDs", "optional edges are not created")
+}
+
+func TestGenFactory_Limits(t *testing.T) {
+	t.Parallel()
+	helper := newMockHelper()
+	userType := createTypeWithSchemaFields(t, "User", factoryFields(t,
+		field.Int64("balance").Max(math.MaxInt64),
+		field.Int64("debt").Range(math.MinInt64, -1),
+		field.Uint64("quota").Range(1<<63, math.MaxUint64),
+		field.Int8("level").Range(-5, 5),
+		field.String("handle").MaxLen(32).MinRuneLen(3).MaxRuneLen(8),
+	))
+	helper.graph.Nodes = []*gen.Type{userType}
+
+	file := genFactory(helper)
+	assertValidGo(t, file, "factory")
+	code := file.GoString()
+	assert.Contains(t, code, "b.SetBalance(factory.Max[int64](n, math.MaxInt64))")
+	assert.Contains(t, code, "b.SetDebt(factory.Range[int64](n, math.MinInt64, -1))")
+	assert.Contains(t, code, "b.SetQuota(factory.Range[uint64](n, 9223372036854775808, math.MaxUint64))")
+	assert.Contains(t, code, "b.SetLevel(factory.Range[int8](n, -5, 5))")
+	assert.Contains(t, code, `b.SetHandle(factory.String(n, "handle", 3, 8))`, "rune limits bound the ASCII values")
+}
+
+func TestGenFactory_RequiredCycle(t *testing.T) {
+	t.Parallel()
+	helper := newMockHelper()
+	userType := createTestType("User")
+	teamType := createTestType("Team")
+	groupType := createTestType("Group")
+	userType.Edges = []*gen.Edge{createM2OEdge("team", teamType, "users", "team_users")}
+	teamType.Edges = []*gen.Edge{createM2OEdge("owner", userType, "teams", "user_teams")}
+	groupType.Edges = []*gen.Edge{createM2OEdge("owner", userType, "groups", "user_groups")}
+	helper.graph.Nodes = []*gen.Type{userType, teamType, groupType}
+
+	file := genFactory(helper)
+	assertValidGo(t, file, "factory")
+	code := file.GoString()
+	assert.NotContains(t, code, "_f.factory.Team().Create(ctx)", "edges of a required cycle must be set by a trait")
+	assert.NotContains(t, code, "TeamIDs")
+	assert.Contains(t, code, "_f.factory.User().Create(ctx)", "edges into a cycle are created")
+	assert.Equal(t, 1, strings.Count(code, "_f.factory.User().Create(ctx)"))
+}
+
+func TestDialect_GenFactory(t *testing.T) {
+	t.Parallel()
+	helper := newMockHelper()
+	helper.graph.Nodes = []*gen.Type{createTestType("User")}
+	file, err := NewDialect(helper).GenFactory()
+	require.NoError(t, err)
+	assertValidGo(t, file, "factory")
+	assert.Contains(t, file.GoString(), "type UserFactory struct")
+}
@@ -17,6 +17,12 @@ const veloxCorePkg = "github.com/syssam/velox"
 // Op, Value, AggregateFunc) from this package.
 const runtimePkg = "github.com/syssam/velox/runtime"
 
+// privacyPkg is the import path for the velox privacy package.
+const privacyPkg = "github.com/syssam/velox/privacy"
+
+// edgePkg is the import path for the velox schema/edge package.
+const edgePkg = "github.com/syssam/velox/schema/edge"
+
 // genConvertPredicates generates the code that converts typed predicates
 // to []func(*sql.Selector). Uses PredicatesFuncs() public method to work
 // across package boundaries (root wrapper accessing entity sub-package mutation).
@@ -0,0 +1,251 @@
+package sql
+
+import (
+	"strings"
+
+	"github.com/dave/jennifer/jen"
+
+	"github.com/syssam/velox/compiler/gen"
+)
+
+// sqljsonPkg is the import path for the velox sqljson package.
+const sqljsonPkg = "github.com/syssam/velox/dialect/sql/sqljson"
+
+// jsonPath is a value of a JSON field with a struct type, and the name
+// of its setter in the mutation and the update builders.
+type jsonPath struct {
+	*gen.JSONPath
+	Setter string
+}
+
+// jsonPaths returns the values of a JSON field with a struct type. The values
+// whose setter name conflicts with the setter of another field are skipped.
+func jsonPaths(t *gen.Type, fd *gen.Field) []*jsonPath {
+	paths := fd.JSONPaths()
+	if len(paths) == 0 {
+		return nil
+	}
+	setters := make(map[string]bool, len(t.Fields)+len(paths))
+	for _, f := range t.Fields {
+		setters["Set"+f.StructField()] = true
+	}
+	vs := make([]*jsonPath, 0, len(paths))
+	for _, p := range paths {
+		name := "Set" + fd.StructField() + strings.Join(p.Names, "")
+		if setters[name] {
+			continue
+		}
+		setters[name] = true
+		vs = append(vs, &jsonPath{JSONPath: p, Setter: name})
+	}
+	return vs
+}
+
+// hasJSONPaths reports if the mutation of the field holds the values set at
+// its paths. Only the mutable JSON fields with a struct type are patched.
+func hasJSONPaths(t *gen.Type, fd *gen.Field) bool {
+	return !fd.Immutable && len(jsonPaths(t, fd)) > 0
+}
+
+// typeHasJSONPaths reports if any of the fields of the type has JSON paths.
+func typeHasJSONPaths(t *gen.Type) bool {
+	for _, fd := range t.Fields {
+		if hasJSONPaths(t, fd) {
+			return true
+		}
+	}
+	return false
+}
+
+// jsonPathType returns the Go type of a JSON path value.
+func jsonPathType(p *jsonPath) *jen.Statement {
+	if p.Type.PkgPath == "" {
+		return jen.Id(p.Type.Ident)
+	}
+	return jen.Qual(p.Type.PkgPath, p.Type.Name)
+}
+
+// genJSONPathPredicates generates the predicates of the values of the JSON
+// fields with a struct type. Each field gets a variable that mirrors its
+// struct, with a sqljson.PathField per value:
+//
+//	user.InfoField.Address.City.EQ("Tel Aviv")
+func genJSONPathPredicates(h gen.GeneratorHelper, f *jen.File, t *gen.Type) {
+	for _, fd := range t.Fields {
+		paths := jsonPaths(t, fd)
+		if len(paths) == 0 {
+			continue
+		}
+		var (
+			decls    []jen.Code
+			typeName = lowerFirst(fd.StructField()) + "Paths"
+			values   = genJSONPathStruct(h, t, fd, &decls, typeName, paths, 0)
+			varName  = fd.StructField() + "Field"
+		)
+		f.Commentf("%s holds the predicates of the values of the %q field.", varName, fd.Name)
+		f.Var().Id(varName).Op("=").Id(typeName).Values(values)
+		for _, d := range decls {
+			f.Add(d)
+		}
+	}
+}
+
+// genJSONPathStruct appends the declaration of the struct that holds the
+// predicates of the paths at the given depth (and of its nested structs)
+// to decls, and returns the values of its fields.
+func genJSONPathStruct(h gen.GeneratorHelper, t *gen.Type, fd *gen.Field, decls *[]jen.Code, typeName string, paths []*jsonPath, depth int) jen.Dict {
+	var (
+		fields []jen.Code
+		values = jen.Dict{}
+		at     = len(*decls)
+	)
+	*decls = append(*decls, nil)
+	for _, g := range groupJSONPaths(paths, depth) {
+		name := g[0].Names[depth]
+		if p := g[0]; len(p.Names) == depth+1 {
+			args := []jen.Code{jen.Id(fd.Constant())}
+			for _, k := range p.Keys {
+				args = append(args, jen.Lit(k))
+			}
+			if p.Type.Ident == "string" {
+				fields = append(fields, jen.Id(name).Qual(sqljsonPkg, "StringPathField").Types(h.PredicateType(t)))
+				values[jen.Id(name)] = jen.Qual(sqljsonPkg, "NewStringPathField").Types(h.PredicateType(t)).Call(args...)
+			} else {
+				fields = append(fields, jen.Id(name).Qual(sqljsonPkg, "PathField").Types(h.PredicateType(t), jsonPathType(p)))
+				values[jen.Id(name)] = jen.Qual(sqljsonPkg, "NewPathField").Types(h.PredicateType(t), jsonPathType(p)).Call(args...)
+			}
+			continue
+		}
+		nested := typeName[:len(typeName)-len("Paths")] + name + "Paths"
+		fields = append(fields, jen.Id(name).Id(nested))
+		values[jen.Id(name)] = jen.Id(nested).Values(genJSONPathStruct(h, t, fd, decls, nested, g, depth+1))
+	}
+	object := fd.Name
+	if depth > 0 {
+		object = fd.Name + "." + strings.Join(paths[0].Keys[:depth], ".")
+	}
+	(*decls)[at] = jen.Commentf("%s holds the predicates of the values of the %q object.", typeName, object).Line().
+		Type().Id(typeName).Struct(fields...)
+	return values
+}
+
+// groupJSONPaths groups the paths by their name at the given depth, in order.
+// A group holds either a single value, or the values of a nested struct.
+func groupJSONPaths(paths []*jsonPath, depth int) [][]*jsonPath {
+	var (
+		groups [][]*jsonPath
+		index  = make(map[string]int)
+	)
+	for _, p := range paths {
+		name := p.Names[depth]
+		i, ok := index[name]
+		switch {
+		case !ok:
+			index[name] = len(groups)
+			groups = append(groups, []*jsonPath{p})
+		// Values and structs with the same Go name are promoted from
+		// embedded structs. Only the first one is accessible.
+		case len(groups[i][0].Names) > depth+1 && len(p.Names) > depth+1:
+			groups[i] = append(groups[i], p)
+		}
+	}
+	return groups
+}
+
+// genMutationJSONPaths generates the setters of the values of the mutable JSON
+// fields with a struct type, and the setPath helper that records them:
+//
+//	m.SetInfoAddressCity("Tel Aviv")
+//
+// The values are set with sqljson.SetPaths on update, and keep the other
+// values of the column.
+func genMutationJSONPaths(h gen.GeneratorHelper, f *jen.File, mutName string, t *gen.Type) {
+	if !typeHasJSONPaths(t) {
+		return
+	}
+	f.Comment("setPath records the value set at the path of a JSON field, replacing the previous value of the path.")
+	f.Comment("The full value of the field, and the values appended to it, are dropped, the last setter wins.")
+	f.Func().Params(jen.Id("m").Op("*").Id(mutName)).Id("setPath").Params(
+		jen.Id("name").String(), jen.Id("path").Index().String(), jen.Id("v").Any(),
+	).Block(
+		jen.Delete(jen.Id("m").Dot("appends"), jen.Id("name")),
+		jen.Delete(jen.Id("m").Dot("clearedFields"), jen.Id("name")),
+		jen.If(jen.Id("m").Dot("paths").Op("==").Nil()).Block(
+			jen.Id("m").Dot("paths").Op("=").Make(jen.Map(jen.String()).Index().Qual(sqljsonPkg, "PathValue")),
+		),
+		jen.For(jen.List(jen.Id("i"), jen.Id("pv")).Op(":=").Range().Id("m").Dot("paths").Index(jen.Id("name"))).Block(
+			jen.If(jen.Qual("slices", "Equal").Call(jen.Id("pv").Dot("Path"), jen.Id("path"))).Block(
+				jen.Id("m").Dot("paths").Index(jen.Id("name")).Index(jen.Id("i")).Dot("Value").Op("=").Id("v"),
+				jen.Return(),
+			),
+		),
+		jen.Id("m").Dot("paths").Index(jen.Id("name")).Op("=").Append(
+			jen.Id("m").Dot("paths").Index(jen.Id("name")),
+			jen.Qual(sqljsonPkg, "PathValue").Values(jen.Dict{jen.Id("Path"): jen.Id("path"), jen.Id("Value"): jen.Id("v")}),
+		),
+	)
+	for _, fd := range t.MutableFields() {
+		for _, p := range jsonPaths(t, fd) {
+			keys := make([]jen.Code, len(p.Keys))
+			for i, k := range p.Keys {
+				keys[i] = jen.Lit(k)
+			}
+			f.Commentf("%s sets the %q value of the %q field, and keeps its other values.", p.Setter, strings.Join(p.Keys, "."), fd.Name)
+			f.Func().Params(jen.Id("m").Op("*").Id(mutName)).Id(p.Setter).Params(jen.Id("v").Add(jsonPathType(p))).Block(
+				jen.Id("m").Dot("_"+fd.Name).Op("=").Nil(),
+				jen.Id("m").Dot("setPath").Call(jen.Lit(fd.Name), jen.Index().String().Values(keys...), jen.Id("v")),
+			)
+		}
+		if hasJSONPaths(t, fd) {
+			f.Commentf("%sPaths returns the values set at the paths of the %q field in this mutation.", fd.StructField(), fd.Name)
+			f.Func().Params(jen.Id("m").Op("*").Id(mutName)).Id(fd.StructField()+"Paths").Params().Index().Qual(sqljsonPkg, "PathValue").Block(
+				jen.Return(jen.Id("m").Dot("paths").Index(jen.Lit(fd.Name))),
+			)
+		}
+	}
+}
+
+// resetJSONPaths emits the statement that drops the values set at the
+// paths of the field, if it has any.
+func resetJSONPaths(grp *jen.Group, t *gen.Type, fd *gen.Field) {
+	if hasJSONPaths(t, fd) {
+		grp.Delete(jen.Id("m").Dot("paths"), jen.Lit(fd.Name))
+	}
+}
+
+// genJSONPathSetters generates the setters of the values of a JSON field on
+// an update builder. They delegate to the setters of the mutation.
+func genJSONPathSetters(h gen.GeneratorHelper, f *jen.File, t *gen.Type, builderName, recv string, fd *gen.Field, target string, ifaceReturn jen.Code) {
+	retType := chainReturnType(builderName, ifaceReturn)
+	for _, p := range jsonPaths(t, fd) {
+		f.Commentf("%s sets the %q value of the %q field, and keeps its other values.", p.Setter, strings.Join(p.Keys, "."), fd.Name)
+		f.Func().Params(jen.Id(recv).Op("*").Id(builderName)).Id(p.Setter).Params(
+			jen.Id("v").Add(jsonPathType(p)),
+		).Add(retType).Block(
+			jen.Id(recv).Dot(target).Dot(p.Setter).Call(jen.Id("v")),
+			jen.Return(jen.Id(recv)),
+		)
+	}
+}
+
+// genUpdateJSONPaths emits the modifiers that set the values at the paths of
+// the JSON fields in place, with sqljson.SetPaths.
+func genUpdateJSONPaths(h gen.GeneratorHelper, grp *jen.Group, t *gen.Type, recv string) {
+	for _, fd := range t.MutableFields() {
+		if !hasJSONPaths(t, fd) {
+			continue
+		}
+		grp.If(
+			jen.Id("vs").Op(":=").Id(recv).Dot("mutation").Dot("paths").Index(jen.Lit(fd.Name)),
+			jen.Len(jen.Id("vs")).Op(">").Lit(0),
+		).Block(
+			jen.Id("spec").Dot("AddModifier").Call(
+				jen.Func().Params(jen.Id("u").Op("*").Qual(h.SQLPkg(), "UpdateBuilder")).BlockFunc(func(blk *jen.Group) {
+					for _, col := range dualWriteColumns(fd) {
+						blk.Qual(sqljsonPkg, "SetPaths").Call(jen.Id("u"), jen.Lit(col), jen.Id("vs").Op("..."))
+					}
+				}),
+			),
+		)
+	}
+}
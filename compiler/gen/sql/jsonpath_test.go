@@ -0,0 +1,108 @@
+package sql
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/syssam/velox/compiler/gen"
+	"github.com/syssam/velox/schema/field"
+)
+
+type (
+	testAddress struct {
+		City string `json:"city"`
+		Zip  *int   `json:"zip,omitempty"`
+	}
+	testInfo struct {
+		Nickname string      `json:"nick"`
+		Address  testAddress `json:"address"`
+		Tags     []string    `json:"tags"`
+	}
+)
+
+// createJSONPathType returns a User type with a JSON "info" field of type testInfo.
+func createJSONPathType(t *testing.T, immutable bool) *gen.Type {
+	t.Helper()
+	desc := field.JSON("info", testInfo{}).Descriptor()
+	require.NoError(t, desc.Err)
+	return createTestTypeWithFields("User", []*gen.Field{
+		createTestField("name", field.TypeString),
+		{Name: "info", Type: desc.Info, Immutable: immutable},
+	})
+}
+
+func TestGenPredicate_JSONPaths(t *testing.T) {
+	helper := newMockHelper()
+	userType := createJSONPathType(t, false)
+	helper.graph.Nodes = []*gen.Type{userType}
+
+	file := genPredicate(helper, userType)
+	assertValidGo(t, file, "where")
+	code := file.GoString()
+	assert.Contains(t, code, "var InfoField = infoPaths{")
+	assert.Contains(t, code, `Nickname: sqljson.NewStringPathField[predicate.User](FieldInfo, "nick")`)
+	assert.Contains(t, code, `City: sqljson.NewStringPathField[predicate.User](FieldInfo, "address", "city")`)
+	assert.Contains(t, code, `Zip:  sqljson.NewPathField[predicate.User, int](FieldInfo, "address", "zip")`)
+	assert.Contains(t, code, "type infoPaths struct {")
+	assert.Contains(t, code, "Address  infoAddressPaths")
+	assert.Contains(t, code, "// infoAddressPaths holds the predicates of the values of the \"info.address\" object.")
+	// Slices are not basic values.
+	assert.NotContains(t, code, "Tags")
+
+	verbose := newFeatureMockHelper().withFeatures(gen.FeatureEntPredicates.Name)
+	verbose.graph.Nodes = []*gen.Type{userType}
+	file = genPredicate(verbose, userType)
+	assertValidGo(t, file, "where")
+	assert.Contains(t, file.GoString(), "var InfoField = infoPaths{")
+}
+
+func TestGenMutation_JSONPaths(t *testing.T) {
+	helper := newMockHelper()
+	userType := createJSONPathType(t, false)
+	helper.graph.Nodes = []*gen.Type{userType}
+
+	file := genMutation(helper, userType)
+	assertValidGo(t, file, "mutation")
+	code := file.GoString()
+	assert.Contains(t, code, "paths         map[string][]sqljson.PathValue")
+	assert.Contains(t, code, "func (m *UserMutation) setPath(name string, path []string, v any) {")
+	assert.Contains(t, code, "func (m *UserMutation) SetInfoAddressCity(v string) {\n\tm._info = nil\n\tm.setPath(\"info\", []string{\"address\", \"city\"}, v)\n}")
+	assert.Contains(t, code, "func (m *UserMutation) SetInfoAddressZip(v int) {")
+	assert.Contains(t, code, "func (m *UserMutation) InfoPaths() []sqljson.PathValue {")
+	// Setting, resetting or clearing the field drops the values set at its paths.
+	assert.Equal(t, 3, strings.Count(code, `delete(m.paths, "info")`))
+
+	// Immutable fields are not patched.
+	userType = createJSONPathType(t, true)
+	helper.graph.Nodes = []*gen.Type{userType}
+	code = genMutation(helper, userType).GoString()
+	assert.NotContains(t, code, "m.paths")
+	assert.NotContains(t, code, "SetInfoAddressCity")
+}
+
+func TestGenUpdate_JSONPaths(t *testing.T) {
+	helper := newMockHelper()
+	userType := createJSONPathType(t, false)
+	helper.graph.Nodes = []*gen.Type{userType}
+
+	file, err := genUpdate(helper, userType)
+	require.NoError(t, err)
+	assertValidGo(t, file, "update")
+	code := file.GoString()
+	assert.Contains(t, code, "func (_u *UserUpdate) SetInfoNickname(v string) *UserUpdate {")
+	assert.Contains(t, code, "func (_u *UserUpdateOne) SetInfoAddressCity(v string) *UserUpdateOne {")
+	assert.Contains(t, code, "if vs := _u.mutation.paths[\"info\"]; len(vs) > 0 {\n\t\tspec.AddModifier(func(u *sql.UpdateBuilder) {\n\t\t\tsqljson.SetPaths(u, \"info\", vs...)\n\t\t})\n\t}")
+}
+
+func TestGenJSONPaths_SetterConflict(t *testing.T) {
+	userType := createJSONPathType(t, false)
+	userType.Fields = append(userType.Fields, createTestField("info_nickname", field.TypeString))
+	var setters []string
+	for _, p := range jsonPaths(userType, userType.Fields[1]) {
+		setters = append(setters, p.Setter)
+	}
+	assert.Equal(t, []string{"SetInfoAddressCity", "SetInfoAddressZip"}, setters)
+}
@@ -83,86 +83,114 @@ func genEntityRuntimeRegistration(h gen.GeneratorHelper, grp *jen.Group, t *gen.
 	clientName := t.ClientName()
 	mutName := t.MutationName()
 
-	grp.Qual(runtimePkg, "RegisterEntity").Call(
-		jen.Qual(runtimePkg, "EntityRegistration").Values(jen.Dict{
-			jen.Id("Name"):  jen.Lit(t.Name),
-			jen.Id("Table"): jen.Qual(leafPkg, "Table"),
-			jen.Id("TypeInfo"): jen.Op("&").Qual(runtimePkg, "RegisteredTypeInfo").Values(jen.Dict{
-				jen.Id("Table"):       jen.Qual(leafPkg, "Table"),
-				jen.Id("Columns"):     jen.Qual(leafPkg, "Columns"),
-				jen.Id("IDColumn"):    jen.Qual(leafPkg, "FieldID"),
-				jen.Id("IDFieldType"): jen.Qual(schemaPkg(), t.ID.Type.ConstName()),
-				jen.Id("ScanValues"): jen.Func().Params(
-					jen.Id("columns").Index().String(),
-				).Params(
-					jen.Index().Any(), jen.Error(),
-				).Block(
-					jen.Return(jen.Parens(jen.Op("&").Add(entityType()).Values()).Dot("ScanValues").Call(jen.Id("columns"))),
-				),
-				jen.Id("New"): jen.Func().Params().Any().Block(
-					jen.Return(jen.Op("&").Add(entityType()).Values()),
-				),
-				jen.Id("Assign"): jen.Func().Params(
-					jen.Id("_e").Any(),
-					jen.Id("columns").Index().String(),
-					jen.Id("values").Index().Any(),
-				).Error().Block(
-					jen.Return(jen.Id("_e").Assert(jen.Op("*").Add(entityType())).Dot("AssignValues").Call(jen.Id("columns"), jen.Id("values"))),
-				),
-				jen.Id("GetID"): jen.Func().Params(
-					jen.Id("_e").Any(),
-				).Any().Block(
-					jen.Return(jen.Id("_e").Assert(jen.Op("*").Add(entityType())).Dot("ID")),
-				),
-			}),
-			jen.Id("ValidColumn"): jen.Qual(leafPkg, "ValidColumn"),
-			jen.Id("Mutator"): jen.Func().Params(
-				jen.Id("ctx").Qual("context", "Context"),
-				jen.Id("cfg").Qual(runtimePkg, "Config"),
-				jen.Id("m").Any(),
-			).Params(jen.Any(), jen.Error()).Block(
-				jen.Return(
-					jen.Id("New"+clientName).Call(jen.Id("cfg")).Dot("mutate").Call(
-						jen.Id("ctx"),
-						jen.Id("m").Assert(jen.Op("*").Id(mutName)),
-					),
-				),
+	reg := jen.Dict{
+		jen.Id("Name"):  jen.Lit(t.Name),
+		jen.Id("Table"): jen.Qual(leafPkg, "Table"),
+		jen.Id("TypeInfo"): jen.Op("&").Qual(runtimePkg, "RegisteredTypeInfo").Values(jen.Dict{
+			jen.Id("Table"):       jen.Qual(leafPkg, "Table"),
+			jen.Id("Columns"):     jen.Qual(leafPkg, "Columns"),
+			jen.Id("IDColumn"):    jen.Qual(leafPkg, "FieldID"),
+			jen.Id("IDFieldType"): jen.Qual(schemaPkg(), t.ID.Type.ConstName()),
+			jen.Id("ScanValues"): jen.Func().Params(
+				jen.Id("columns").Index().String(),
+			).Params(
+				jen.Index().Any(), jen.Error(),
+			).Block(
+				jen.Return(jen.Parens(jen.Op("&").Add(entityType()).Values()).Dot("ScanValues").Call(jen.Id("columns"))),
+			),
+			jen.Id("New"): jen.Func().Params().Any().Block(
+				jen.Return(jen.Op("&").Add(entityType()).Values()),
 			),
-			jen.Id("Client"): jen.Func().Params(
-				jen.Id("cfg").Qual(runtimePkg, "Config"),
+			jen.Id("Assign"): jen.Func().Params(
+				jen.Id("_e").Any(),
+				jen.Id("columns").Index().String(),
+				jen.Id("values").Index().Any(),
+			).Error().Block(
+				jen.Return(jen.Id("_e").Assert(jen.Op("*").Add(entityType())).Dot("AssignValues").Call(jen.Id("columns"), jen.Id("values"))),
+			),
+			jen.Id("GetID"): jen.Func().Params(
+				jen.Id("_e").Any(),
 			).Any().Block(
-				jen.Return(jen.Id("New" + clientName).Call(jen.Id("cfg"))),
+				jen.Return(jen.Id("_e").Assert(jen.Op("*").Add(entityType())).Dot("ID")),
 			),
 		}),
+		jen.Id("ValidColumn"): jen.Qual(leafPkg, "ValidColumn"),
+		jen.Id("Mutator"): jen.Func().Params(
+			jen.Id("ctx").Qual("context", "Context"),
+			jen.Id("cfg").Qual(runtimePkg, "Config"),
+			jen.Id("m").Any(),
+		).Params(jen.Any(), jen.Error()).Block(
+			jen.Return(
+				jen.Id("New"+clientName).Call(jen.Id("cfg")).Dot("mutate").Call(
+					jen.Id("ctx"),
+					jen.Id("m").Assert(jen.Op("*").Id(mutName)),
+				),
+			),
+		),
+		jen.Id("Client"): jen.Func().Params(
+			jen.Id("cfg").Qual(runtimePkg, "Config"),
+		).Any().Block(
+			jen.Return(jen.Id("New" + clientName).Call(jen.Id("cfg"))),
+		),
+	}
+	if cols := sensitiveColumns(leafPkg, t); len(cols) > 0 {
+		reg[jen.Id("SensitiveColumns")] = jen.Index().String().Values(cols...)
+	}
+	if isCascadeDependent(h.Graph(), t) {
+		reg[jen.Id("Cascade")] = genCascadeFunc(h, t)
+	}
+	grp.Qual(runtimePkg, "RegisterEntity").Call(
+		jen.Qual(runtimePkg, "EntityRegistration").Values(reg),
 	)
 
 	// Register a NodeResolver so that root client.Noder/Noders can resolve this
 	// entity by global ID. The resolver pulls Config from the context (injected
 	// by the generated Noder) and constructs a fresh entity client to call Get.
 	idType := h.IDType(t)
+	resolver := jen.Dict{
+		jen.Id("Type"): jen.Lit(t.Name),
+		jen.Id("Resolve"): jen.Func().Params(
+			jen.Id("ctx").Qual("context", "Context"),
+			jen.Id("id").Any(),
+		).Params(jen.Any(), jen.Error()).Block(
+			jen.Id("cfg").Op(":=").Qual(runtimePkg, "ConfigFromContext").Call(jen.Id("ctx")),
+			jen.If(jen.Id("cfg").Dot("Driver").Op("==").Nil()).Block(
+				jen.Return(jen.Nil(), jen.Qual("errors", "New").Call(
+					jen.Lit("velox: NodeResolver requires Config in context — call client.Noder so cfg is propagated"),
+				)),
+			),
+			jen.List(jen.Id("typedID"), jen.Id("ok")).Op(":=").Id("id").Assert(idType),
+			jen.If(jen.Op("!").Id("ok")).Block(
+				jen.Return(jen.Nil(), jen.Qual("fmt", "Errorf").Call(
+					jen.Lit("velox: NodeResolver: unexpected id type %T"),
+					jen.Id("id"),
+				)),
+			),
+			jen.Return(jen.Id("New"+clientName).Call(jen.Id("cfg")).Dot("Get").Call(jen.Id("ctx"), jen.Id("typedID"))),
+		),
+	}
+	// Prefixed IDs let the Noder dispatch on the type tag of the ID.
+	if prefix := t.IDPrefix(); prefix != "" {
+		resolver[jen.Id("IDPrefix")] = jen.Lit(prefix)
+	}
 	grp.Qual(runtimePkg, "RegisterNodeResolver").Call(
 		jen.Qual(leafPkg, "Table"),
-		jen.Qual(runtimePkg, "NodeResolver").Values(jen.Dict{
-			jen.Id("Type"): jen.Lit(t.Name),
-			jen.Id("Resolve"): jen.Func().Params(
-				jen.Id("ctx").Qual("context", "Context"),
-				jen.Id("id").Any(),
-			).Params(jen.Any(), jen.Error()).Block(
-				jen.Id("cfg").Op(":=").Qual(runtimePkg, "ConfigFromContext").Call(jen.Id("ctx")),
-				jen.If(jen.Id("cfg").Dot("Driver").Op("==").Nil()).Block(
-					jen.Return(jen.Nil(), jen.Qual("errors", "New").Call(
-						jen.Lit("velox: NodeResolver requires Config in context — call client.Noder so cfg is propagated"),
-					)),
-				),
-				jen.List(jen.Id("typedID"), jen.Id("ok")).Op(":=").Id("id").Assert(idType),
-				jen.If(jen.Op("!").Id("ok")).Block(
-					jen.Return(jen.Nil(), jen.Qual("fmt", "Errorf").Call(
-						jen.Lit("velox: NodeResolver: unexpected id type %T"),
-						jen.Id("id"),
-					)),
-				),
-				jen.Return(jen.Id("New"+clientName).Call(jen.Id("cfg")).Dot("Get").Call(jen.Id("ctx"), jen.Id("typedID"))),
-			),
-		}),
+		jen.Qual(runtimePkg, "NodeResolver").Values(resolver),
 	)
 }
+
+// sensitiveColumns returns the columns of the Sensitive fields of the type,
+// including the old columns of renamed fields, which are written as well.
+func sensitiveColumns(leafPkg string, t *gen.Type) []jen.Code {
+	var cols []jen.Code
+	for _, fd := range t.Fields {
+		if !fd.Sensitive() {
+			continue
+		}
+		cols = append(cols, jen.Qual(leafPkg, fd.Constant()))
+		if old := fd.RenamedFrom(); old != "" && !fd.Retyped() {
+			cols = append(cols, jen.Lit(old))
+		}
+	}
+	return cols
+}
@@ -9,6 +9,7 @@ import (
 
 	"github.com/syssam/velox/compiler/gen"
 	"github.com/syssam/velox/compiler/load"
+	"github.com/syssam/velox/schema/edge"
 	"github.com/syssam/velox/schema/field"
 )
 
@@ -147,6 +148,51 @@ func TestGenEntityRuntime_WithRootPkg_NoForeignKeys(t *testing.T) {
 	assert.NotContains(t, code, "ForeignKeys")
 }
 
+func TestGenEntityRuntime_SensitiveColumns(t *testing.T) {
+	t.Parallel()
+	helper := newMockHelper()
+	helper.rootPkg = "github.com/test/project/ent"
+
+	userType := createTestTypeWithSchema(t, "User", &load.Schema{
+		Fields: []*load.Field{
+			{Name: "name", Info: &field.TypeInfo{Type: field.TypeString}},
+			{Name: "password", Info: &field.TypeInfo{Type: field.TypeString}, Sensitive: true},
+		},
+	})
+	code := genEntityRuntime(helper, userType).GoString()
+	assert.Contains(t, code, "SensitiveColumns: []string{user.FieldPassword},")
+
+	code = genEntityRuntime(helper, createTestType("Pet")).GoString()
+	assert.NotContains(t, code, "SensitiveColumns")
+}
+
+func TestGenEntityRuntime_Cascade(t *testing.T) {
+	t.Parallel()
+	helper := newMockHelper()
+	helper.rootPkg = "github.com/test/project/ent"
+	userType, postType := createCascadeTypes(edge.Delete)
+	helper.graph.Nodes = []*gen.Type{userType, postType}
+
+	code := genEntityRuntime(helper, postType).GoString()
+	assert.Contains(t, code, `Cascade: func(ctx context.Context, cfg runtime.Config, c runtime.Cascade, ids []any) (int, error) {
+			p := predicate.Post(func(s *sql.Selector) {
+				s.Where(sql.In(s.C(c.Column), ids...))
+			})
+			client := NewPostClient(cfg)
+			if c.Action == edge.SetNull {
+				u := client.Update().Where(p)
+				if err := u.Mutation().ClearEdge(c.Inverse); err != nil {
+					return 0, err
+				}
+				return u.Save(ctx)
+			}
+			return client.Delete().Where(p).Exec(ctx)
+		},`)
+
+	code = genEntityRuntime(helper, userType).GoString()
+	assert.NotContains(t, code, "Cascade")
+}
+
 func TestGenEntityRuntime_WithRootPkg_WithForeignKeys(t *testing.T) {
 	t.Parallel()
 	helper := newMockHelper()
@@ -79,24 +79,42 @@ func genMigrateSchema(h gen.GeneratorHelper) *jen.File {
 
 		// Generate table definition
 		f.Comment("// " + tableVar + " holds the schema information for the \"" + t.Table() + "\" table.")
+		primaryKey, indexes := genPrimaryKey(t, columnsVar, schemaPkg), genIndexesSchema(t, schemaPkg)
+		if t.IsView() {
+			// Views have no primary key, and only materialized views can be indexed.
+			primaryKey = jen.Nil()
+			if !t.IsMaterializedView() {
+				indexes = jen.Nil()
+			}
+		}
 		tableDict := jen.Dict{
 			jen.Id("Name"):        jen.Lit(t.Table()),
 			jen.Id("Columns"):     jen.Id(columnsVar),
-			jen.Id("PrimaryKey"):  genPrimaryKey(t, columnsVar, schemaPkg),
+			jen.Id("PrimaryKey"):  primaryKey,
 			jen.Id("ForeignKeys"): genForeignKeysSchema(t),
-			jen.Id("Indexes"):     genIndexesSchema(t, schemaPkg),
+			jen.Id("Indexes"):     indexes,
 		}
 		if comment := t.TableComment(); comment != "" {
 			tableDict[jen.Id("Comment")] = jen.Lit(comment)
 		}
+		if t.IsView() {
+			tableDict[jen.Id("View")] = jen.True()
+		}
+		antDict := jen.Dict{}
 		if ant := t.EntSQL(); ant != nil {
 			if ant.Schema != "" {
 				tableDict[jen.Id("Schema")] = jen.Lit(ant.Schema)
 			}
-			if antDict := genTableAnnotationDict(ant); len(antDict) > 0 {
-				const sqlschemaPkg = "github.com/syssam/velox/dialect/sqlschema"
-				tableDict[jen.Id("Annotation")] = jen.Op("&").Qual(sqlschemaPkg, "Annotation").Values(antDict)
-			}
+			antDict = genTableAnnotationDict(ant)
+		}
+		// The row-level security policy is set by the annotation, or derived
+		// from the mixin.TenantID mixin with the sql/rowsecurity feature.
+		if rs := t.RowSecurity(); rs != nil {
+			antDict[jen.Id("RowSecurity")] = genRowSecurity(rs)
+		}
+		if len(antDict) > 0 {
+			const sqlschemaPkg = "github.com/syssam/velox/dialect/sqlschema"
+			tableDict[jen.Id("Annotation")] = jen.Op("&").Qual(sqlschemaPkg, "Annotation").Values(antDict)
 		}
 		f.Var().Id(tableVar).Op("=").Op("&").Qual(schemaPkg, "Table").Values(tableDict)
 		f.Line()
@@ -194,6 +212,14 @@ func genMigrateSchema(h gen.GeneratorHelper) *jen.File {
 		}
 	}
 
+	// Generate the outbox table of the sql/outbox feature.
+	outboxEnabled := h.FeatureEnabled(gen.FeatureOutbox.Name)
+	if outboxEnabled {
+		f.Comment("// OutboxTable holds the schema information for the transactional outbox table.")
+		f.Var().Id("OutboxTable").Op("=").Qual(outboxPkg, "NewTable").Call()
+		f.Line()
+	}
+
 	// Generate Tables slice
 	f.Comment("// Tables holds all the tables in the schema.")
 	f.Var().Id("Tables").Op("=").Index().Op("*").Qual(schemaPkg, "Table").ValuesFunc(func(g *jen.Group) {
@@ -203,6 +229,9 @@ func genMigrateSchema(h gen.GeneratorHelper) *jen.File {
 		for _, j := range m2mJoins {
 			g.Id(j.varName)
 		}
+		if outboxEnabled {
+			g.Id("OutboxTable")
+		}
 	})
 	f.Line()
 
@@ -397,6 +426,26 @@ func genColumnDict(col *schema.Column, fieldPkg string) jen.Dict {
 	if col.Comment != "" {
 		dict[jen.Id("Comment")] = jen.Lit(col.Comment)
 	}
+	if col.RenamedFrom != "" {
+		dict[jen.Id("RenamedFrom")] = jen.Lit(col.RenamedFrom)
+	}
+	if col.Retyped {
+		dict[jen.Id("Retyped")] = jen.True()
+	}
+	if g := col.Generated; g != nil {
+		generated := jen.Dict{jen.Id("Type"): jen.Lit(g.Type)}
+		if g.Expr != "" {
+			generated[jen.Id("Expr")] = jen.Lit(g.Expr)
+		}
+		if len(g.Exprs) > 0 {
+			exprs := make(jen.Dict, len(g.Exprs))
+			for k, v := range g.Exprs {
+				exprs[jen.Lit(k)] = jen.Lit(v)
+			}
+			generated[jen.Id("Exprs")] = jen.Map(jen.String()).String().Values(exprs)
+		}
+		dict[jen.Id("Generated")] = jen.Op("&").Qual("github.com/syssam/velox/dialect/sql/schema", "GeneratedExpr").Values(generated)
+	}
 	// Include SchemaType for TypeOther fields (like decimal) that need dialect-specific types.
 	// Iterate in sorted order for deterministic generated output.
 	if len(col.SchemaType) > 0 {
@@ -638,6 +687,12 @@ func genIndexAnnotationDict(ant *sqlschema.IndexAnnotation) jen.Dict {
 		}
 		d[jen.Id("IncludeColumns")] = jen.Index().String().Values(cols...)
 	}
+	if ant.FullText {
+		d[jen.Id("FullText")] = jen.Lit(true)
+	}
+	if ant.FullTextConfig != "" {
+		d[jen.Id("FullTextConfig")] = jen.Lit(ant.FullTextConfig)
+	}
 	return d
 }
 
@@ -649,8 +704,11 @@ func findColumnIndex(t *gen.Type, colName string) int {
 	if t.ID != nil && t.ID.StorageKey() == colName {
 		return 0
 	}
-	// 1..n: regular fields (skipped fields occupy no slot)
+	// 1..n: regular fields (skipped fields occupy no slot). Views have no ID column.
 	idx := 1
+	if t.ID == nil {
+		idx = 0
+	}
 	for _, f := range t.Fields {
 		if a := f.EntSQL(); a != nil && a.Skip {
 			continue
@@ -768,7 +826,8 @@ func m2mFKSymbols(e *gen.Edge, col1Name, col2Name string) (string, string) {
 }
 
 // genTableAnnotationDict builds a jen.Dict for the non-zero annotation fields that
-// Atlas needs at the table level: CHECK constraints, charset, collation, options (RISK 7).
+// Atlas needs at the table level: CHECK constraints, charset, collation, options (RISK 7),
+// the definition of views, and the partition key.
 // Schema is handled separately as a direct Table.Schema field.
 func genTableAnnotationDict(ant *sqlschema.Annotation) jen.Dict {
 	d := jen.Dict{}
@@ -796,9 +855,50 @@ func genTableAnnotationDict(ant *sqlschema.Annotation) jen.Dict {
 	if ant.Options != "" {
 		d[jen.Id("Options")] = jen.Lit(ant.Options)
 	}
+	if ant.ViewAs != "" {
+		d[jen.Id("ViewAs")] = jen.Lit(ant.ViewAs)
+	}
+	if len(ant.ViewFor) > 0 {
+		keys := make([]string, 0, len(ant.ViewFor))
+		for k := range ant.ViewFor {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		elems := make([]jen.Code, 0, len(keys))
+		for _, k := range keys {
+			elems = append(elems, jen.Lit(k).Op(":").Lit(ant.ViewFor[k]))
+		}
+		d[jen.Id("ViewFor")] = jen.Map(jen.String()).String().Values(elems...)
+	}
+	if ant.Materialized {
+		d[jen.Id("Materialized")] = jen.True()
+	}
+	if p := ant.Partition; p != nil {
+		const sqlschemaPkg = "github.com/syssam/velox/dialect/sqlschema"
+		columns := make([]jen.Code, len(p.Columns))
+		for i, c := range p.Columns {
+			columns[i] = jen.Lit(c)
+		}
+		d[jen.Id("Partition")] = jen.Op("&").Qual(sqlschemaPkg, "Partition").Values(jen.Dict{
+			jen.Id("Type"):    jen.Qual(sqlschemaPkg, p.Type.ConstName()),
+			jen.Id("Columns"): jen.Index().String().Values(columns...),
+		})
+	}
 	return d
 }
 
+// genRowSecurity returns the Jennifer code for the row-level security policy of a table.
+func genRowSecurity(rs *sqlschema.RowSecurity) jen.Code {
+	d := jen.Dict{
+		jen.Id("Column"):  jen.Lit(rs.Column),
+		jen.Id("Setting"): jen.Lit(rs.Setting),
+	}
+	if !rs.Forced() {
+		d[jen.Id("Force")] = jen.New(jen.Bool())
+	}
+	return jen.Op("&").Qual("github.com/syssam/velox/dialect/sqlschema", "RowSecurity").Values(d)
+}
+
 // deleteAction returns the Jennifer code for the ON DELETE referential action.
 // Checks the edge's sqlschema.OnDelete annotation first, then falls back to
 // default: NoAction for required edges, SetNull for optional edges.
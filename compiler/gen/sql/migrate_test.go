@@ -12,6 +12,7 @@ import (
 
 	"github.com/syssam/velox/compiler/gen"
 	"github.com/syssam/velox/compiler/load"
+	"github.com/syssam/velox/dialect"
 	"github.com/syssam/velox/dialect/sql/schema"
 	"github.com/syssam/velox/dialect/sqlschema"
 	"github.com/syssam/velox/schema/field"
@@ -550,6 +551,109 @@ func TestGenMigrateSchema_PartialIndexWhereClause(t *testing.T) {
 	assert.Contains(t, code, "deleted_at IS NULL")
 }
 
+// createMaterializedView creates a "PostStats" materialized view type with an
+// index on its author_id column.
+func createMaterializedView(t testing.TB) *gen.Type {
+	t.Helper()
+	view := createTestTypeWithSchema(t, "PostStats", &load.Schema{
+		View: true,
+		Fields: []*load.Field{
+			{Name: "author_id", Info: &field.TypeInfo{Type: field.TypeInt}},
+			{Name: "posts", Info: &field.TypeInfo{Type: field.TypeInt}},
+		},
+		Annotations: map[string]any{
+			sqlschema.AnnotationName: sqlschema.Annotation{
+				ViewAs:       "SELECT author_id, COUNT(*) FROM posts GROUP BY author_id",
+				ViewFor:      map[string]string{dialect.MySQL: "SELECT 1, 1"},
+				Materialized: true,
+			},
+		},
+	})
+	view.Indexes = []*gen.Index{{Name: "poststats_author_id", Unique: true, Columns: []string{"author_id"}}}
+	return view
+}
+
+func TestGenMigrateSchema_MaterializedView(t *testing.T) {
+	t.Parallel()
+	helper := newMockHelper()
+	helper.graph.Nodes = []*gen.Type{createMaterializedView(t)}
+
+	code := genMigrateSchema(helper).GoString()
+	assert.Contains(t, code, "View:       true,")
+	assert.Contains(t, code, "PrimaryKey: nil,")
+	assert.Contains(t, code, `ViewAs:       "SELECT author_id, COUNT(*) FROM posts GROUP BY author_id",`)
+	assert.Contains(t, code, `ViewFor:      map[string]string{"mysql": "SELECT 1, 1"},`)
+	assert.Contains(t, code, "Materialized: true,")
+	// The columns of the view start at index 0, as it has no ID column.
+	assert.Contains(t, code, "Columns: []*schema.Column{PostStatsColumns[0]},")
+	_, err := parser.ParseFile(token.NewFileSet(), "schema.go", code, parser.AllErrors)
+	require.NoError(t, err)
+}
+
+func TestGenMigrateSchema_Partition(t *testing.T) {
+	t.Parallel()
+	helper := newMockHelper()
+	eventType := createTestTypeWithSchema(t, "Event", &load.Schema{
+		Fields: []*load.Field{
+			{Name: "created_at", Info: &field.TypeInfo{Type: field.TypeTime}},
+		},
+		Annotations: map[string]any{
+			sqlschema.AnnotationName: *sqlschema.PartitionBy(sqlschema.Range, "created_at"),
+		},
+	})
+	helper.graph.Nodes = []*gen.Type{eventType}
+
+	code := genMigrateSchema(helper).GoString()
+	assert.Contains(t, code, `Annotation: &sqlschema.Annotation{Partition: &sqlschema.Partition{
+		Columns: []string{"created_at"},
+		Type:    sqlschema.Range,
+	}},`)
+	_, err := parser.ParseFile(token.NewFileSet(), "schema.go", code, parser.AllErrors)
+	require.NoError(t, err)
+}
+
+// createTenantType returns a "Document" type with a mixed-in tenant_id field,
+// and the sql/rowsecurity feature enabled.
+func createTenantType(t *testing.T) *gen.Type {
+	docType := createTestTypeWithSchema(t, "Document", &load.Schema{
+		Fields: []*load.Field{
+			{Name: "tenant_id", Info: &field.TypeInfo{Type: field.TypeString}, Position: &load.Position{MixedIn: true}},
+		},
+	})
+	docType.Features = []gen.Feature{gen.FeatureRowSecurity}
+	return docType
+}
+
+func TestGenMigrateSchema_RowSecurity(t *testing.T) {
+	t.Parallel()
+	helper := newMockHelper()
+	orgType := createTestTypeWithSchema(t, "Org", &load.Schema{
+		Fields: []*load.Field{
+			{Name: "org_id", Info: &field.TypeInfo{Type: field.TypeInt}},
+		},
+		Annotations: map[string]any{
+			sqlschema.AnnotationName: sqlschema.Annotation{
+				Table:       "orgs",
+				RowSecurity: &sqlschema.RowSecurity{Column: "org_id", Force: new(bool)},
+			},
+		},
+	})
+	helper.graph.Nodes = []*gen.Type{createTenantType(t), orgType}
+
+	code := genMigrateSchema(helper).GoString()
+	assert.Contains(t, code, `Annotation: &sqlschema.Annotation{RowSecurity: &sqlschema.RowSecurity{
+		Column:  "tenant_id",
+		Setting: "app.tenant_id",
+	}},`)
+	assert.Contains(t, code, `Annotation: &sqlschema.Annotation{RowSecurity: &sqlschema.RowSecurity{
+		Column:  "org_id",
+		Force:   new(bool),
+		Setting: "app.tenant_id",
+	}},`)
+	_, err := parser.ParseFile(token.NewFileSet(), "schema.go", code, parser.AllErrors)
+	require.NoError(t, err)
+}
+
 func TestGenIndexAnnotationDict_AllScalarFields(t *testing.T) {
 	t.Parallel()
 	const sqlschemaPkg = "github.com/syssam/velox/dialect/sqlschema"
@@ -1113,3 +1217,74 @@ func BenchmarkFieldTypeCode(b *testing.B) {
 		}
 	}
 }
+
+func TestGenMigrateSchema_RenamedFromDualWrites(t *testing.T) {
+	t.Parallel()
+	helper := newMockHelper()
+	userType := createTestType("User")
+	userType.Fields[0].Name = "full_name"
+	userType.Fields[0].Annotations = gen.Annotations{sqlschema.AnnotationName: sqlschema.RenamedFrom("name")}
+	helper.graph.Nodes = []*gen.Type{userType}
+
+	code := genMigrateSchema(helper).GoString()
+	assert.Contains(t, code, `RenamedFrom: "name"`)
+
+	create, err := genCreate(helper, userType)
+	require.NoError(t, err)
+	code = create.GoString()
+	assert.Contains(t, code, `_spec.SetField("full_name", field.TypeString, v)`)
+	assert.Contains(t, code, `_spec.SetField("name", field.TypeString, v)`)
+
+	update, err := genUpdate(helper, userType)
+	require.NoError(t, err)
+	code = update.GoString()
+	assert.Contains(t, code, `spec.SetField("full_name", field.TypeString, *_u.mutation._full_name)`)
+	assert.Contains(t, code, `spec.SetField("name", field.TypeString, *_u.mutation._full_name)`)
+
+	// The old column of a retyped field has another type, and is not written.
+	userType.Fields[0].Annotations = gen.Annotations{sqlschema.AnnotationName: sqlschema.RetypedFrom("name")}
+	code = genMigrateSchema(helper).GoString()
+	assert.Contains(t, code, `RenamedFrom: "name"`)
+	assert.Contains(t, code, `Retyped:     true`)
+	create, err = genCreate(helper, userType)
+	require.NoError(t, err)
+	code = create.GoString()
+	assert.Contains(t, code, `_spec.SetField("full_name", field.TypeString, v)`)
+	assert.NotContains(t, code, `_spec.SetField("name"`)
+	update, err = genUpdate(helper, userType)
+	require.NoError(t, err)
+	assert.NotContains(t, update.GoString(), `spec.SetField("name"`)
+}
+
+func TestGenMigrateSchema_GeneratedColumns(t *testing.T) {
+	t.Parallel()
+	helper := newMockHelper()
+	userType := createTypeWithSchemaFields(t, "User", factoryFields(t,
+		field.String("first_name"),
+		field.String("last_name"),
+		field.String("full_name").
+			Generated("first_name || ' ' || last_name", field.Stored).
+			GeneratedExprs(map[string]string{dialect.MySQL: "CONCAT(first_name, ' ', last_name)"}, field.Stored),
+	))
+	helper.graph.Nodes = []*gen.Type{userType}
+
+	code := genMigrateSchema(helper).GoString()
+	assert.Contains(t, code, `Generated: &schema.GeneratedExpr{`)
+	assert.Contains(t, code, `Expr:  "first_name || ' ' || last_name",`)
+	assert.Contains(t, code, `Exprs: map[string]string{"mysql": "CONCAT(first_name, ' ', last_name)"},`)
+	assert.Contains(t, code, `Type:  "STORED",`)
+
+	// Generated columns have no setters, and are not required on create.
+	create, err := genCreate(helper, userType)
+	require.NoError(t, err)
+	code = create.GoString()
+	assert.Contains(t, code, "SetFirstName(")
+	assert.NotContains(t, code, "SetFullName(")
+	assert.NotContains(t, code, `missing required field \"User.full_name\"`)
+
+	update, err := genUpdate(helper, userType)
+	require.NoError(t, err)
+	code = update.GoString()
+	assert.Contains(t, code, "SetFirstName(")
+	assert.NotContains(t, code, "SetFullName(")
+}
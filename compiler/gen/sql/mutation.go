@@ -20,13 +20,7 @@ func genMutation(h gen.GeneratorHelper, t *gen.Type) *jen.File {
 	// Entity package path (e.g. "example.com/app/entity") for typed oldValue closure.
 	entityReturnPkg := h.SharedEntityPkg()
 
-	hasJSONField := false
-	for _, fd := range t.Fields {
-		if fd.IsJSON() {
-			hasJSONField = true
-			break
-		}
-	}
+	hasJSONField := typeHasJSONField(t)
 	hasMutableFields := len(t.MutableFields()) > 0
 
 	// Mutation struct with typed pointer fields and operation metadata.
@@ -49,6 +43,9 @@ func genMutation(h gen.GeneratorHelper, t *gen.Type) *jen.File {
 		if hasJSONField {
 			group.Id("appends").Map(jen.String()).Any()
 		}
+		if typeHasJSONPaths(t) {
+			group.Id("paths").Map(jen.String()).Index().Qual(sqljsonPkg, "PathValue")
+		}
 		if hasMutableFields {
 			group.Id("oldValue").Func().Params(jen.Qual("context", "Context")).Params(jen.Op("*").Qual(entityReturnPkg, t.Name), jen.Error())
 			group.Id("oldLoaded").Bool()
@@ -66,6 +63,9 @@ func genMutation(h gen.GeneratorHelper, t *gen.Type) *jen.File {
 
 	// Interface assertion - compile-time check that mutation implements ent.Mutation
 	f.Var().Id("_").Qual(h.VeloxPkg(), "Mutation").Op("=").Parens(jen.Op("*").Id(mutName)).Call(jen.Nil())
+	if hasJSONField {
+		f.Var().Id("_").Qual(h.VeloxPkg(), "PatchMutation").Op("=").Parens(jen.Op("*").Id(mutName)).Call(jen.Nil())
+	}
 
 	// Option type (unexported, internal use only)
 	f.Commentf("%s allows management of the mutation configuration using functional options.", t.MutationOptionName())
@@ -200,6 +200,13 @@ func genMutation(h gen.GeneratorHelper, t *gen.Type) *jen.File {
 		)
 	}
 
+	// checkFieldPolicies — gated by FeaturePrivacy. Called by the builders
+	// before defaults are applied, so only fields set by the caller are
+	// checked against their write policies.
+	if h.FeatureEnabled(gen.FeaturePrivacy.Name) && len(t.FieldPolicyFields()) > 0 {
+		genMutationFieldPolicies(h, f, mutName, t)
+	}
+
 	// Per-field typed Set/Get/Clear/Reset
 	for _, fd := range t.Fields {
 		// Skip non-user-defined edge fields (they're handled by edge setters).
@@ -210,6 +217,9 @@ func genMutation(h gen.GeneratorHelper, t *gen.Type) *jen.File {
 		genMutationField(h, f, mutName, t, fd)
 	}
 
+	// Setters of the values of typed JSON fields
+	genMutationJSONPaths(h, f, mutName, t)
+
 	// Edge methods
 	for _, edge := range t.EdgesWithID() {
 		genMutationEdge(h, f, mutName, t, edge)
@@ -248,6 +258,7 @@ func genMutationField(h gen.GeneratorHelper, f *jen.File, mutName string, t *gen
 				jen.Delete(jen.Id("m").Dot("appends"), jen.Lit(column)),
 			)
 		}
+		resetJSONPaths(body, t, fd)
 	})
 
 	// Xxx returns the field value and whether it was set.
@@ -323,6 +334,7 @@ func genMutationField(h gen.GeneratorHelper, f *jen.File, mutName string, t *gen
 					jen.Delete(jen.Id("m").Dot("appends"), jen.Lit(column)),
 				)
 			}
+			resetJSONPaths(body, t, fd)
 			body.If(jen.Id("m").Dot("clearedFields").Op("==").Nil()).Block(
 				jen.Id("m").Dot("clearedFields").Op("=").Make(jen.Map(jen.String()).Struct()),
 			)
@@ -352,6 +364,7 @@ func genMutationField(h gen.GeneratorHelper, f *jen.File, mutName string, t *gen
 				jen.Delete(jen.Id("m").Dot("appends"), jen.Lit(column)),
 			)
 		}
+		resetJSONPaths(body, t, fd)
 		body.Delete(jen.Id("m").Dot("clearedFields"), jen.Lit(column))
 	})
 }
@@ -688,6 +701,8 @@ func genMutationFieldAccessors(h gen.GeneratorHelper, f *jen.File, mutName strin
 		jen.Return(jen.Id("ok")),
 	)
 
+	genMutationPatches(f, mutName, t)
+
 	// ClearField clears a field by name. Only Optional/Nillable fields can be cleared.
 	// Returns an error for unknown or non-nullable field names.
 	hasClearable := false
@@ -718,6 +733,7 @@ func genMutationFieldAccessors(h gen.GeneratorHelper, f *jen.File, mutName strin
 								jen.Delete(jen.Id("m").Dot("appends"), jen.Lit(fd.Name)),
 							)
 						}
+						resetJSONPaths(blk, t, fd)
 					})
 				}
 			}
@@ -749,6 +765,7 @@ func genMutationFieldAccessors(h gen.GeneratorHelper, f *jen.File, mutName strin
 							jen.Delete(jen.Id("m").Dot("appends"), jen.Lit(fd.Name)),
 						)
 					}
+					resetJSONPaths(blk, t, fd)
 					blk.Delete(jen.Id("m").Dot("clearedFields"), jen.Lit(fd.Name))
 				})
 			}
@@ -916,4 +933,118 @@ func genMutationFieldAccessors(h gen.GeneratorHelper, f *jen.File, mutName strin
 			genMutationOldField(h, f, mutName, t, fd)
 		}
 	}
+	genEntityValidateOld(h, f, t, mutName)
+}
+
+// genMutationFieldPolicies generates the checkFieldPolicies method that
+// evaluates the write policies of the guarded fields set, added to or
+// cleared by the mutation.
+func genMutationFieldPolicies(h gen.GeneratorHelper, f *jen.File, mutName string, t *gen.Type) {
+	leafPkg := h.LeafPkgPath(t)
+	f.Comment("checkFieldPolicies evaluates the field policies of the fields that were")
+	f.Comment("set, added to or cleared in this mutation.")
+	f.Func().Params(jen.Id("m").Op("*").Id(mutName)).Id("checkFieldPolicies").Params(
+		jen.Id("ctx").Qual("context", "Context"),
+	).Error().BlockFunc(func(grp *jen.Group) {
+		for _, fd := range t.FieldPolicyFields() {
+			name := jen.Qual(leafPkg, fd.Constant())
+			changed := jen.Id("m").Dot("hasFieldChange").Call(name)
+			grp.If(changed).Block(
+				jen.If(jen.Err().Op(":=").Qual(leafPkg, fd.FieldPolicyName()).Dot("EvalWrite").Call(jen.Id("ctx"), jen.Id("m")), jen.Err().Op("!=").Nil()).Block(
+					jen.Return(jen.Err()),
+				),
+			)
+		}
+		grp.Return(jen.Nil())
+	})
+
+	f.Comment("hasFieldChange reports if the given field was set, added to or cleared.")
+	f.Func().Params(jen.Id("m").Op("*").Id(mutName)).Id("hasFieldChange").Params(
+		jen.Id("name").String(),
+	).Bool().BlockFunc(func(grp *jen.Group) {
+		grp.If(jen.List(jen.Id("_"), jen.Id("ok")).Op(":=").Id("m").Dot("Field").Call(jen.Id("name")), jen.Id("ok")).Block(
+			jen.Return(jen.True()),
+		)
+		grp.If(jen.List(jen.Id("_"), jen.Id("ok")).Op(":=").Id("m").Dot("AddedField").Call(jen.Id("name")), jen.Id("ok")).Block(
+			jen.Return(jen.True()),
+		)
+		// Appends and path setters patch the column in place, and are not
+		// reported by Field or AddedField.
+		if typeHasJSONField(t) {
+			grp.If(jen.Id("m").Dot("fieldPatched").Call(jen.Id("name"))).Block(
+				jen.Return(jen.True()),
+			)
+		}
+		grp.Return(jen.Id("m").Dot("FieldCleared").Call(jen.Id("name")))
+	})
+}
+
+// genMutationPatches generates the accessors of the changes that patch the
+// JSON fields in place: the values appended to them, and the values set at
+// their paths. They implement velox.PatchMutation.
+func genMutationPatches(f *jen.File, mutName string, t *gen.Type) {
+	if !typeHasJSONField(t) {
+		return
+	}
+	var names []jen.Code
+	for _, fd := range t.Fields {
+		if fd.IsJSON() {
+			names = append(names, jen.Lit(fd.Name))
+		}
+	}
+	f.Comment("PatchedFields returns all JSON fields that were appended to, or set at a path, during this mutation.")
+	f.Func().Params(jen.Id("m").Op("*").Id(mutName)).Id("PatchedFields").Params().Index().String().Block(
+		jen.Var().Id("fields").Index().String(),
+		jen.For(jen.List(jen.Id("_"), jen.Id("name")).Op(":=").Range().Index().String().Values(names...)).Block(
+			jen.If(jen.Id("m").Dot("fieldPatched").Call(jen.Id("name"))).Block(
+				jen.Id("fields").Op("=").Append(jen.Id("fields"), jen.Id("name")),
+			),
+		),
+		jen.Return(jen.Id("fields")),
+	)
+
+	f.Comment("AppendedField returns the values appended to a JSON field in this mutation.")
+	f.Func().Params(jen.Id("m").Op("*").Id(mutName)).Id("AppendedField").Params(
+		jen.Id("name").String(),
+	).Params(jen.Qual(runtimePkg, "Value"), jen.Bool()).Block(
+		jen.List(jen.Id("v"), jen.Id("ok")).Op(":=").Id("m").Dot("appends").Index(jen.Id("name")),
+		jen.Return(jen.Id("v"), jen.Id("ok")),
+	)
+
+	f.Comment("FieldPaths returns the values set at the paths of a JSON field in this mutation.")
+	f.Func().Params(jen.Id("m").Op("*").Id(mutName)).Id("FieldPaths").Params(
+		jen.Id("name").String(),
+	).Index().Qual(sqljsonPkg, "PathValue").BlockFunc(func(grp *jen.Group) {
+		if typeHasJSONPaths(t) {
+			grp.Return(jen.Id("m").Dot("paths").Index(jen.Id("name")))
+		} else {
+			grp.Return(jen.Nil())
+		}
+	})
+
+	f.Comment("fieldPatched reports if the JSON field was appended to, or set at a path.")
+	f.Func().Params(jen.Id("m").Op("*").Id(mutName)).Id("fieldPatched").Params(
+		jen.Id("name").String(),
+	).Bool().BlockFunc(func(grp *jen.Group) {
+		if !typeHasJSONPaths(t) {
+			grp.List(jen.Id("_"), jen.Id("ok")).Op(":=").Id("m").Dot("appends").Index(jen.Id("name"))
+			grp.Return(jen.Id("ok"))
+			return
+		}
+		grp.If(jen.List(jen.Id("_"), jen.Id("ok")).Op(":=").Id("m").Dot("appends").Index(jen.Id("name")), jen.Id("ok")).Block(
+			jen.Return(jen.True()),
+		)
+		grp.Return(jen.Len(jen.Id("m").Dot("paths").Index(jen.Id("name"))).Op(">").Lit(0))
+	})
+}
+
+// typeHasJSONField reports if any of the fields of the type is a JSON field.
+// The mutations of these types hold the values appended to their fields.
+func typeHasJSONField(t *gen.Type) bool {
+	for _, fd := range t.Fields {
+		if fd.IsJSON() {
+			return true
+		}
+	}
+	return false
 }
@@ -0,0 +1,285 @@
+package sql
+
+import (
+	"github.com/dave/jennifer/jen"
+
+	"github.com/syssam/velox/compiler/gen"
+)
+
+// Polymorphic edges (edge.ToOneOf) are stored in two fields of the owner type,
+// the name of the referenced type (an enum) and its ID. The generators below
+// add the edge API on top of these fields: a marker interface implemented by
+// the target types, typed query methods per target type, eager-loading that
+// runs one query per target type, and builder setters that take any target.
+
+// oneOfMatch returns the condition that reports if the entity held by the
+// given variable references an entity of the target type.
+func oneOfMatch(h gen.GeneratorHelper, oe *gen.OneOfEdge, target *gen.Type, v string) *jen.Statement {
+	typeField, idField := oe.TypeField.StructField(), oe.IDField.StructField()
+	enum := jen.Qual(h.LeafPkgPath(oe.Owner), oe.TypeField.EnumName(target.Name))
+	if !oe.Optional {
+		return jen.Id(v).Dot(typeField).Op("==").Add(enum)
+	}
+	return jen.Id(v).Dot(typeField).Op("!=").Nil().
+		Op("&&").Op("*").Id(v).Dot(typeField).Op("==").Add(enum).
+		Op("&&").Id(v).Dot(idField).Op("!=").Nil()
+}
+
+// oneOfID returns the ID of the referenced entity held by the given variable.
+func oneOfID(oe *gen.OneOfEdge, v string) *jen.Statement {
+	if oe.Optional {
+		return jen.Op("*").Id(v).Dot(oe.IDField.StructField())
+	}
+	return jen.Id(v).Dot(oe.IDField.StructField())
+}
+
+// genOneOfPath generates the SetPath closure that selects the entity of the
+// target type referenced by the entity, or nothing if it references another
+// type. Table and column names are literals to avoid importing the target
+// entity package.
+func genOneOfPath(h gen.GeneratorHelper, oe *gen.OneOfEdge, target *gen.Type, entityVar, configVar string) jen.Code {
+	sqlPkg := h.SQLPkg()
+	return jen.Func().Params(
+		jen.Id("ctx").Qual("context", "Context"),
+	).Params(
+		jen.Op("*").Qual(sqlPkg, "Selector"),
+		jen.Error(),
+	).Block(
+		jen.Id("builder").Op(":=").Qual(sqlPkg, "Dialect").Call(jen.Id(configVar).Dot("config").Dot("Driver").Dot("Dialect").Call()),
+		jen.Id("s").Op(":=").Id("builder").Dot("Select").Call().Dot("From").Call(jen.Id("builder").Dot("Table").Call(jen.Lit(target.Table()))),
+		jen.If(oneOfMatch(h, oe, target, entityVar)).Block(
+			jen.Id("s").Dot("Where").Call(jen.Qual(sqlPkg, "EQ").Call(jen.Lit(target.ID.StorageKey()), oneOfID(oe, entityVar))),
+		).Else().Block(
+			jen.Id("s").Dot("Where").Call(jen.Qual(sqlPkg, "False").Call()),
+		),
+		jen.Return(jen.Id("s"), jen.Nil()),
+	)
+}
+
+// genEntityPkgOneOfAccessors generates the union interface of each polymorphic
+// edge, its marker methods on the target types, and the OrErr/Set/Loaded
+// accessors on the Edges struct. The loaded flags of polymorphic edges follow
+// the ones of the regular edges.
+func genEntityPkgOneOfAccessors(f *jen.File, t *gen.Type) {
+	edgesName := t.Name + "Edges"
+	for i, oe := range t.OneOfEdges {
+		var (
+			idx   = len(t.Edges) + i
+			name  = oe.StructField()
+			union = oe.UnionName()
+		)
+		f.Commentf("%s is implemented by the types of the %q edge of %s.", union, oe.Name, t.Name)
+		f.Type().Id(union).Interface(jen.Id("Is" + union).Params())
+		for _, target := range oe.Types {
+			f.Commentf("Is%s implements the %s interface.", union, union)
+			f.Func().Params(jen.Op("*").Id(target.Name)).Id("Is" + union).Params().Block()
+		}
+
+		f.Commentf("%sOrErr returns the %s value or an error if the edge was not loaded.", name, oe.Name)
+		f.Func().Params(jen.Id("e").Id(edgesName)).Id(name+"OrErr").Params().Params(jen.Id(union), jen.Error()).Block(
+			jen.If(jen.Id("e").Dot("loadedTypes").Index(jen.Lit(idx))).Block(
+				jen.Return(jen.Id("e").Dot(name), jen.Nil()),
+			),
+			jen.Return(jen.Nil(), jen.Qual(runtimePkg, "NewNotLoadedError").Call(jen.Lit(oe.Name))),
+		)
+		f.Commentf("Set%s stores the %s edge value and marks it as loaded.", name, oe.Name)
+		f.Func().Params(jen.Id("e").Op("*").Id(edgesName)).Id("Set"+name).Params(jen.Id("v").Id(union)).Block(
+			jen.Id("e").Dot(name).Op("=").Id("v"),
+			jen.Id("e").Dot("loadedTypes").Index(jen.Lit(idx)).Op("=").True(),
+		)
+		f.Commentf("%sLoaded reports whether the %s edge was loaded.", name, oe.Name)
+		f.Func().Params(jen.Id("e").Id(edgesName)).Id(name + "Loaded").Params().Bool().Block(
+			jen.Return(jen.Id("e").Dot("loadedTypes").Index(jen.Lit(idx))),
+		)
+		f.Commentf("Mark%sLoaded marks the %s edge as loaded, even if empty.", name, oe.Name)
+		f.Func().Params(jen.Id("e").Op("*").Id(edgesName)).Id("Mark" + name + "Loaded").Params().Block(
+			jen.Id("e").Dot("loadedTypes").Index(jen.Lit(idx)).Op("=").True(),
+		)
+	}
+}
+
+// genEntityPkgOneOfQueryMethods generates the Query{Edge}{Type} methods and the
+// {Edge} resolver method of each polymorphic edge on the entity struct.
+//
+//	func (_e *Comment) QuerySubjectPost() PostQuerier
+//	func (_e *Comment) Subject(ctx context.Context) (CommentSubjectUnion, error)
+func genEntityPkgOneOfQueryMethods(h gen.GeneratorHelper, f *jen.File, t *gen.Type) {
+	for _, oe := range t.OneOfEdges {
+		for _, target := range oe.Types {
+			targetQuerierIface := target.Name + "Querier"
+			f.Commentf("%s queries the %q edge of the %s, if it references a %s.", oe.QueryName(target), oe.Name, t.Name, target.Name)
+			f.Func().Params(jen.Id("_e").Op("*").Id(t.Name)).Id(oe.QueryName(target)).Params().Id(targetQuerierIface).BlockFunc(func(grp *jen.Group) {
+				grp.Id("tq").Op(":=").Qual(runtimePkg, "NewEntityQuery").Call(jen.Lit(target.Name), jen.Id("_e").Dot("config"))
+				grp.Id("_is").Op(",").Id("_").Op(":=").Id("_e").Dot("config").Dot("InterStore").Assert(jen.Op("*").Id("InterceptorStore"))
+				grp.If(jen.Id("_is").Op("==").Nil()).Block(
+					jen.Id("_is").Op("=").Op("&").Id("InterceptorStore").Values(),
+				)
+				grp.Add(assertSetInterStore("tq", "", jen.Id("_is")))
+				grp.Id("_tp").Op(":=").Qual(runtimePkg, "EntityPolicy").Call(jen.Lit(target.Name))
+				grp.If(jen.Id("_tp").Op("!=").Nil()).Block(
+					assertSetPolicy("tq", h.VeloxPkg(), jen.Id("_tp")),
+				)
+				grp.Add(assertSetPath("tq", h.SQLPkg(), genOneOfPath(h, oe, target, "_e", "_e")))
+				grp.Return(jen.Id("tq").Op(".").Parens(jen.Id(targetQuerierIface)))
+			})
+		}
+
+		name, union := oe.StructField(), oe.UnionName()
+		f.Commentf("%s returns the entity referenced by the %q edge of the %s. The eager-loaded", name, oe.Name, t.Name)
+		f.Commentf("value is returned if the edge was loaded, otherwise the entity is queried. A nil")
+		f.Commentf("value is returned if the edge is not set.")
+		f.Func().Params(jen.Id("_e").Op("*").Id(t.Name)).Id(name).Params(
+			jen.Id("ctx").Qual("context", "Context"),
+		).Params(jen.Id(union), jen.Error()).BlockFunc(func(grp *jen.Group) {
+			grp.If(jen.Id("_e").Dot("Edges").Dot(name + "Loaded").Call()).Block(
+				jen.Return(jen.Id("_e").Dot("Edges").Dot(name), jen.Nil()),
+			)
+			for _, target := range oe.Types {
+				grp.If(oneOfMatch(h, oe, target, "_e")).Block(
+					jen.List(jen.Id("n"), jen.Err()).Op(":=").Id("_e").Dot(oe.QueryName(target)).Call().Dot("Only").Call(jen.Id("ctx")),
+					jen.If(jen.Err().Op("!=").Nil()).Block(jen.Return(jen.Nil(), jen.Err())),
+					jen.Return(jen.Id("n"), jen.Nil()),
+				)
+			}
+			grp.Return(jen.Nil(), jen.Nil())
+		})
+	}
+}
+
+// genQueryPkgOneOfLoaders generates the WithXxx method of each polymorphic edge
+// and its loader, which runs one query per target type for the given nodes.
+//
+//	func (q *CommentQuery) WithSubject() entity.CommentQuerier
+//	func (q *CommentQuery) loadSubject(ctx context.Context, nodes []*entity.Comment) error
+func genQueryPkgOneOfLoaders(h gen.GeneratorHelper, f *jen.File, t *gen.Type, recv, queryName, entityPkgPath string) {
+	entityType := jen.Qual(entityPkgPath, t.Name)
+	for _, oe := range t.OneOfEdges {
+		name := oe.StructField()
+		f.Commentf("With%s tells the query-builder to eager-load the %q edge.", name, oe.Name)
+		f.Commentf("The referenced entities are loaded with one query per type.")
+		f.Func().Params(jen.Id(recv).Op("*").Id(queryName)).Id("With"+name).Params().Qual(entityPkgPath, t.Name+"Querier").Block(
+			jen.Id(recv).Dot("with"+name).Op("=").True(),
+			jen.Return(jen.Id(recv)),
+		)
+
+		f.Commentf("load%s eagerly loads the %q edge for the given nodes.", name, oe.Name)
+		f.Func().Params(jen.Id(recv).Op("*").Id(queryName)).Id("load"+name).Params(
+			jen.Id("ctx").Qual("context", "Context"),
+			jen.Id("nodes").Index().Op("*").Add(entityType),
+		).Error().BlockFunc(func(body *jen.Group) {
+			body.For(jen.List(jen.Id("_"), jen.Id("n")).Op(":=").Range().Id("nodes")).Block(
+				jen.Id("n").Dot("Edges").Dot("Mark" + name + "Loaded").Call(),
+			)
+			for _, target := range oe.Types {
+				body.If(
+					jen.Err().Op(":=").Id(recv).Dot("load"+name+target.Name).Call(jen.Id("ctx"), jen.Id("nodes")),
+					jen.Err().Op("!=").Nil(),
+				).Block(jen.Return(jen.Err()))
+			}
+			body.Return(jen.Nil())
+		})
+
+		for _, target := range oe.Types {
+			idType := h.IDType(target)
+			loader := "load" + name + target.Name
+			f.Commentf("%s loads the %s entities referenced by the %q edge of the given nodes.", loader, target.Name, oe.Name)
+			f.Func().Params(jen.Id(recv).Op("*").Id(queryName)).Id(loader).Params(
+				jen.Id("ctx").Qual("context", "Context"),
+				jen.Id("nodes").Index().Op("*").Add(entityType),
+			).Error().Block(
+				jen.Var().Id("ids").Index().Any(),
+				jen.Id("seen").Op(":=").Make(jen.Map(idType).Struct()),
+				jen.For(jen.List(jen.Id("_"), jen.Id("n")).Op(":=").Range().Id("nodes")).Block(
+					jen.If(jen.Op("!").Parens(oneOfMatch(h, oe, target, "n"))).Block(jen.Continue()),
+					jen.If(jen.List(jen.Id("_"), jen.Id("ok")).Op(":=").Id("seen").Index(oneOfID(oe, "n")), jen.Op("!").Id("ok")).Block(
+						jen.Id("seen").Index(oneOfID(oe, "n")).Op("=").Struct().Values(),
+						jen.Id("ids").Op("=").Append(jen.Id("ids"), oneOfID(oe, "n")),
+					),
+				),
+				jen.If(jen.Len(jen.Id("ids")).Op("==").Lit(0)).Block(jen.Return(jen.Nil())),
+				jen.Id("query").Op(":=").Id("New"+target.Name+"Query").Call(jen.Id(recv).Dot("config")),
+				jen.Id("query").Dot("inters").Op("=").Id(recv).Dot("inters"),
+				jen.Id("query").Dot("Where").Call(
+					jen.Func().Params(jen.Id("s").Op("*").Qual(h.SQLPkg(), "Selector")).Block(
+						jen.Id("s").Dot("Where").Call(jen.Qual(h.SQLPkg(), "In").Call(
+							jen.Id("s").Dot("C").Call(jen.Qual(h.LeafPkgPath(target), "FieldID")),
+							jen.Id("ids").Op("..."),
+						)),
+					),
+				),
+				jen.List(jen.Id("neighbors"), jen.Err()).Op(":=").Id("query").Dot("All").Call(jen.Id("ctx")),
+				jen.If(jen.Err().Op("!=").Nil()).Block(jen.Return(jen.Err())),
+				jen.Id("neighborByID").Op(":=").Make(jen.Map(idType).Op("*").Qual(entityPkgPath, target.Name), jen.Len(jen.Id("neighbors"))),
+				jen.For(jen.List(jen.Id("_"), jen.Id("n")).Op(":=").Range().Id("neighbors")).Block(
+					jen.Id("neighborByID").Index(jen.Id("n").Dot("ID")).Op("=").Id("n"),
+				),
+				jen.For(jen.List(jen.Id("_"), jen.Id("n")).Op(":=").Range().Id("nodes")).Block(
+					jen.If(jen.Op("!").Parens(oneOfMatch(h, oe, target, "n"))).Block(jen.Continue()),
+					jen.If(jen.List(jen.Id("neighbor"), jen.Id("ok")).Op(":=").Id("neighborByID").Index(oneOfID(oe, "n")), jen.Id("ok")).Block(
+						jen.Id("n").Dot("Edges").Dot(name).Op("=").Id("neighbor"),
+					),
+				),
+				jen.Return(jen.Nil()),
+			)
+		}
+	}
+}
+
+// genOneOfSetters generates the Set{Edge} builder method of each polymorphic
+// edge, which sets its fields from an entity of one of the edge types, and the
+// Clear{Edge} method for optional edges on update builders.
+func genOneOfSetters(h gen.GeneratorHelper, f *jen.File, t *gen.Type, builderName, recv string, isUpdate bool) {
+	for _, oe := range t.OneOfEdges {
+		if isUpdate && oe.Immutable {
+			continue
+		}
+		name := oe.StructField()
+		typeField, idField := oe.TypeField.StructField(), oe.IDField.StructField()
+		f.Commentf("Set%s sets the %q edge to the given entity.", name, oe.Name)
+		f.Func().Params(jen.Id(recv).Op("*").Id(builderName)).Id("Set"+name).Params(
+			jen.Id("v").Qual(h.SharedEntityPkg(), oe.UnionName()),
+		).Op("*").Id(builderName).Block(
+			jen.Switch(jen.Id("v").Op(":=").Id("v").Assert(jen.Type())).BlockFunc(func(sw *jen.Group) {
+				for _, target := range oe.Types {
+					sw.Case(jen.Op("*").Qual(h.SharedEntityPkg(), target.Name)).Block(
+						jen.Id(recv).Dot("Set"+typeField).Call(jen.Qual(h.LeafPkgPath(t), oe.TypeField.EnumName(target.Name))),
+						jen.Id(recv).Dot("Set"+idField).Call(jen.Id("v").Dot("ID")),
+					)
+				}
+			}),
+			jen.Return(jen.Id(recv)),
+		)
+		if isUpdate && oe.Optional {
+			f.Commentf("Clear%s clears the %q edge.", name, oe.Name)
+			f.Func().Params(jen.Id(recv).Op("*").Id(builderName)).Id("Clear"+name).Params().Op("*").Id(builderName).Block(
+				jen.Id(recv).Dot("Clear"+typeField).Call(),
+				jen.Id(recv).Dot("Clear"+idField).Call(),
+				jen.Return(jen.Id(recv)),
+			)
+		}
+	}
+}
+
+// genEntityClientOneOfQueryMethods generates the Query{Edge}{Type} methods of
+// the polymorphic edges on the entity client.
+func genEntityClientOneOfQueryMethods(h gen.GeneratorHelper, f *jen.File, t *gen.Type) {
+	entityPkg := h.SharedEntityPkg()
+	for _, oe := range t.OneOfEdges {
+		for _, target := range oe.Types {
+			targetQuerierIface := target.Name + "Querier"
+			f.Commentf("%s queries the %q edge of a %s, if it references a %s.", oe.QueryName(target), oe.Name, t.Name, target.Name)
+			f.Func().Params(jen.Id("c").Op("*").Id(t.ClientName())).Id(oe.QueryName(target)).Params(
+				jen.Id("v").Op("*").Qual(entityPkg, t.Name),
+			).Qual(entityPkg, targetQuerierIface).BlockFunc(func(grp *jen.Group) {
+				grp.Id("tq").Op(":=").Qual(runtimePkg, "NewEntityQuery").Call(jen.Lit(target.Name), jen.Id("c").Dot("config"))
+				grp.Add(assertSetInterStore("tq", entityPkg, jen.Id("c").Dot("interStore")))
+				grp.Id("_tp").Op(":=").Qual(runtimePkg, "EntityPolicy").Call(jen.Lit(target.Name))
+				grp.If(jen.Id("_tp").Op("!=").Nil()).Block(
+					assertSetPolicy("tq", h.VeloxPkg(), jen.Id("_tp")),
+				)
+				grp.Add(assertSetPath("tq", h.SQLPkg(), genOneOfPath(h, oe, target, "v", "c")))
+				grp.Return(jen.Id("tq").Op(".").Parens(jen.Qual(entityPkg, targetQuerierIface)))
+			})
+		}
+	}
+}
@@ -0,0 +1,78 @@
+package sql
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/syssam/velox/compiler/load"
+)
+
+func TestGenOneOfEdge(t *testing.T) {
+	t.Parallel()
+	h, comment := newOneOfHelper(t, &load.Edge{Name: "subject", OneOf: []string{"Post", "Photo"}})
+
+	entity := genEntityPkgFileWithRegistry(h, comment, h.graph.Nodes, nil)
+	assertValidGo(t, entity, "comment_entity")
+	code := entity.GoString()
+	assert.Contains(t, code, "CommentSubjectUnion `json:\"subject,omitempty\"`")
+	assert.Contains(t, code, "loadedTypes [1]bool")
+	assert.Contains(t, code, "type CommentSubjectUnion interface {\n\tIsCommentSubjectUnion()\n}")
+	assert.Contains(t, code, "func (*Post) IsCommentSubjectUnion() {}")
+	assert.Contains(t, code, "func (*Photo) IsCommentSubjectUnion() {}")
+	assert.Contains(t, code, "func (e CommentEdges) SubjectOrErr() (CommentSubjectUnion, error) {")
+	assert.Contains(t, code, "func (_e *Comment) QuerySubjectPost() PostQuerier {")
+	assert.Contains(t, code, "func (_e *Comment) QuerySubjectPhoto() PhotoQuerier {")
+	assert.Contains(t, code, "if _e.SubjectType != nil && *_e.SubjectType == comment.SubjectTypePost && _e.SubjectID != nil {")
+	assert.Contains(t, code, `s.Where(sql.EQ("id", *_e.SubjectID))`)
+	assert.Contains(t, code, "s.Where(sql.False())")
+	assert.Contains(t, code, "func (_e *Comment) Subject(ctx context.Context) (CommentSubjectUnion, error) {")
+	assert.Contains(t, code, "WithSubject() CommentQuerier")
+
+	query := genQueryPkg(h, comment, h.graph.Nodes, "github.com/test/project/ent/entity")
+	assertValidGo(t, query, "comment_query")
+	code = query.GoString()
+	assert.Contains(t, code, "withSubject bool")
+	assert.Contains(t, code, "case \"subject\":\n\t\tq.withSubject = true")
+	assert.Contains(t, code, "withSubject: q.withSubject,")
+	assert.Contains(t, code, "if err := q.loadSubject(ctx, nodes); err != nil {")
+	assert.Contains(t, code, "func (q *CommentQuery) loadSubjectPost(ctx context.Context, nodes []*entity.Comment) error {")
+	assert.Contains(t, code, "query := NewPhotoQuery(q.config)")
+	assert.Contains(t, code, "s.Where(sql.In(s.C(photo.FieldID), ids...))")
+
+	create, err := genCreate(h, comment)
+	require.NoError(t, err)
+	code = create.GoString()
+	assert.Contains(t, code, "func (c *CommentCreate) SetSubject(v entity.CommentSubjectUnion) *CommentCreate {")
+	assert.Contains(t, code, "case *entity.Photo:\n\t\tc.SetSubjectType(comment.SubjectTypePhoto)\n\t\tc.SetSubjectID(v.ID)")
+	update, err := genUpdate(h, comment)
+	require.NoError(t, err)
+	code = update.GoString()
+	assert.Contains(t, code, "func (_u *CommentUpdate) SetSubject(v entity.CommentSubjectUnion) *CommentUpdate {")
+	assert.Contains(t, code, "func (_u *CommentUpdateOne) ClearSubject() *CommentUpdateOne {")
+
+	client := genEntityClient(h, comment).GoString()
+	assert.Contains(t, client, "func (c *CommentClient) QuerySubjectPhoto(v *entity.Comment) entity.PhotoQuerier {")
+
+	pkg := genPackage(h, comment, buildEntityPkgEnumRegistry(h.graph.Nodes)).GoString()
+	assert.Contains(t, pkg, `EdgeSubject = "subject"`)
+	assert.Contains(t, pkg, `SubjectType = "Post"`)
+}
+
+func TestGenOneOfEdge_RequiredImmutable(t *testing.T) {
+	t.Parallel()
+	h, comment := newOneOfHelper(t, &load.Edge{Name: "subject", OneOf: []string{"Post", "Photo"}, Required: true, Immutable: true})
+
+	code := genEntityPkgFileWithRegistry(h, comment, h.graph.Nodes, nil).GoString()
+	assert.Contains(t, code, "if _e.SubjectType == comment.SubjectTypePost {")
+	assert.Contains(t, code, `s.Where(sql.EQ("id", _e.SubjectID))`)
+
+	create, err := genCreate(h, comment)
+	require.NoError(t, err)
+	assert.Contains(t, create.GoString(), "SetSubject(v entity.CommentSubjectUnion)")
+	update, err := genUpdate(h, comment)
+	require.NoError(t, err)
+	assert.NotContains(t, update.GoString(), "SetSubject")
+	assert.NotContains(t, update.GoString(), "ClearSubject")
+}
@@ -0,0 +1,63 @@
+package sql
+
+import (
+	"github.com/dave/jennifer/jen"
+
+	"github.com/syssam/velox"
+	"github.com/syssam/velox/compiler/gen"
+)
+
+// outboxPkg is the import path for the velox outbox package.
+const outboxPkg = "github.com/syssam/velox/outbox"
+
+// genRuntimeOutbox generates the registration of the outbox hook of a type
+// annotated with outbox.Events. The hook takes the last slot of the Hooks
+// array, after the schema hooks, so it records the mutation as executed.
+func genRuntimeOutbox(h gen.GeneratorHelper, grp *jen.Group, t *gen.Type) {
+	if !t.HasOutbox() {
+		return
+	}
+	var (
+		ant       = t.Outbox()
+		entityPkg = h.LeafPkgPath(t)
+		config    = jen.Dict{
+			jen.Id("Type"):     jen.Lit(t.Name),
+			jen.Id("Table"):    jen.Qual(entityPkg, "Table"),
+			jen.Id("IDColumn"): jen.Qual(entityPkg, "FieldID"),
+			jen.Id("Driver"): jen.Func().Params(jen.Id("m").Qual(veloxCorePkg, "Mutation")).Qual(dialectPkg(), "Driver").Block(
+				jen.Return(jen.Id("m").Assert(jen.Op("*").Id(t.MutationName())).Dot("config").Dot("Driver")),
+			),
+		}
+	)
+	if ant.Topic != "" {
+		config[jen.Id("Topic")] = jen.Lit(ant.Topic)
+	}
+	if ant.Ops != 0 {
+		ops := &jen.Statement{}
+		for _, op := range []velox.Op{velox.OpCreate, velox.OpUpdate, velox.OpUpdateOne, velox.OpDelete, velox.OpDeleteOne} {
+			if !ant.Ops.Is(op) {
+				continue
+			}
+			if len(*ops) > 0 {
+				ops.Op("|")
+			}
+			ops.Qual(veloxCorePkg, op.String())
+		}
+		config[jen.Id("Ops")] = ops
+	}
+	if ant.AllowNonTx {
+		config[jen.Id("AllowNonTx")] = jen.True()
+	}
+	var omit []jen.Code
+	for _, f := range t.Fields {
+		if f.Sensitive() {
+			omit = append(omit, jen.Lit(f.Name))
+		}
+	}
+	if len(omit) > 0 {
+		config[jen.Id("Omit")] = jen.Index().String().Values(omit...)
+	}
+	grp.Commentf("%s.Hooks[%d] records the mutations of %s in the transactional outbox.", t.Package(), t.NumHooks()-1, t.Name)
+	grp.Qual(entityPkg, "Hooks").Index(jen.Lit(t.NumHooks()-1)).Op("=").
+		Qual(outboxPkg, "Hook").Call(jen.Qual(outboxPkg, "HookConfig").Values(config))
+}
@@ -0,0 +1,75 @@
+package sql
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/syssam/velox"
+	"github.com/syssam/velox/compiler/gen"
+	"github.com/syssam/velox/outbox"
+)
+
+// outboxType returns a User type annotated with the given outbox annotation,
+// with the sql/outbox feature enabled in its config.
+func outboxType(ant *outbox.Annotation) *gen.Type {
+	t := createTestType("User")
+	t.Features = []gen.Feature{gen.FeatureOutbox}
+	t.Annotations = gen.Annotations{outbox.AnnotationName: ant}
+	return t
+}
+
+func TestGenRuntimeOutbox(t *testing.T) {
+	t.Parallel()
+	helper := newFeatureMockHelper().withFeatures(gen.FeatureOutbox.Name)
+	helper.rootPkg = "github.com/test/project/ent"
+	userType := outboxType(outbox.Events(velox.OpCreate, velox.OpDelete))
+	require.True(t, userType.HasOutbox())
+	require.Equal(t, 1, userType.NumHooks())
+
+	file := genEntityRuntime(helper, userType)
+	assertValidGo(t, file, "user_runtime")
+	code := file.GoString()
+	assert.Contains(t, code, "user.Hooks[0] = outbox.Hook(outbox.HookConfig{")
+	assert.Contains(t, code, `Type:     "User"`)
+	assert.Contains(t, code, "Ops:      velox.OpCreate | velox.OpDelete")
+	assert.Contains(t, code, "IDColumn: user.FieldID")
+	assert.Contains(t, code, "return m.(*UserMutation).config.Driver")
+	assert.NotContains(t, code, "Topic:")
+	assert.NotContains(t, code, "AllowNonTx:")
+
+	userType = outboxType(outbox.Topic("users"))
+	code = genEntityRuntime(helper, userType).GoString()
+	assert.Contains(t, code, `Topic:    "users"`)
+	assert.NotContains(t, code, "Ops:")
+
+	userType = outboxType(&outbox.Annotation{AllowNonTx: true})
+	code = genEntityRuntime(helper, userType).GoString()
+	assert.Contains(t, code, "AllowNonTx: true")
+}
+
+func TestGenRuntimeOutbox_Disabled(t *testing.T) {
+	t.Parallel()
+	helper := newFeatureMockHelper()
+	helper.rootPkg = "github.com/test/project/ent"
+	userType := outboxType(outbox.Events())
+	userType.Features = nil
+	require.False(t, userType.HasOutbox())
+	require.Zero(t, userType.NumHooks())
+	assert.NotContains(t, genEntityRuntime(helper, userType).GoString(), "outbox")
+}
+
+func TestGenMigrateSchema_OutboxTable(t *testing.T) {
+	t.Parallel()
+	helper := newFeatureMockHelper()
+	helper.graph.Nodes = []*gen.Type{createTestType("User")}
+	assert.NotContains(t, genMigrateSchema(helper).GoString(), "OutboxTable")
+
+	helper.withFeatures(gen.FeatureOutbox.Name)
+	file := genMigrateSchema(helper)
+	assertValidGo(t, file, "schema")
+	code := file.GoString()
+	assert.Contains(t, code, "OutboxTable = outbox.NewTable()")
+	assert.Contains(t, code, "Tables = []*schema.Table{UserTable, OutboxTable}")
+}
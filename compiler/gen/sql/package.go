@@ -73,6 +73,10 @@ func genPackage(h gen.GeneratorHelper, t *gen.Type, enumReg *entityPkgEnumRegist
 			defs.Commentf("%s holds the string denoting the %s edge name in mutations.", edge.Constant(), edge.Name)
 			defs.Id(edge.Constant()).Op("=").Lit(edge.Name)
 		}
+		for _, oe := range t.OneOfEdges {
+			defs.Commentf("%s holds the string denoting the %s polymorphic edge name.", oe.Constant(), oe.Name)
+			defs.Id(oe.Constant()).Op("=").Lit(oe.Name)
+		}
 
 		// Related type ID constants (following Ent meta.tmpl lines 11-20)
 		// Generate {TypeName}FieldID constant when related type's ID differs from current type's ID
@@ -191,6 +195,12 @@ func genPackage(h gen.GeneratorHelper, t *gen.Type, enumReg *entityPkgEnumRegist
 		jen.Return(jen.Id("ok")),
 	)
 
+	// CounterCaches variable — the counter caches that are maintained by the
+	// builders of this type (edge.CounterCache), as a parent or as a dependent.
+	if counters := counterCacheFields(graph, t); len(counters) > 0 {
+		genCounterCaches(h, f, counters)
+	}
+
 	genPackageRuntimeVars(h, f, t, graph)
 
 	// OrderOption type (following Ent pattern)
@@ -215,6 +225,14 @@ func genPackage(h gen.GeneratorHelper, t *gen.Type, enumReg *entityPkgEnumRegist
 		genEdgeOrderOptions(h, f, t, edge)
 	}
 
+	// ByRelevance ordering function for the full-text index.
+	if t.FullTextIndex() != nil {
+		f.Comment("ByRelevance orders the results by their relevance to the full-text query, the most relevant first.")
+		f.Func().Id("ByRelevance").Params(jen.Id("query").String()).Id("OrderOption").Block(
+			jen.Return(jen.Id("searchIndex").Dot("OrderByRelevance").Call(jen.Id("query"))),
+		)
+	}
+
 	// Generate new*Step() helper functions for edges (used by predicates and ordering)
 	for _, edge := range t.Edges {
 		genEdgeStepFunction(h, f, t, edge)
@@ -556,9 +574,10 @@ func genPackageRuntimeVars(h gen.GeneratorHelper, f *jen.File, t *gen.Type, grap
 	numHooks := t.NumHooks()
 	numInterceptors := t.NumInterceptors()
 	numPolicy := t.NumPolicy()
+	numValidators := t.NumValidators()
 
-	// Generate runtime comment if hooks or interceptors are present
-	if numHooks > 0 || numInterceptors > 0 {
+	// Generate runtime comment if hooks, interceptors or entity validators are present
+	if numHooks > 0 || numInterceptors > 0 || numValidators > 0 {
 		f.Comment("Note that the variables below are initialized by the runtime")
 		f.Comment("package on the initialization of the application. Therefore,")
 		f.Comment("it should be imported in the main as follows:")
@@ -592,7 +611,12 @@ func genPackageRuntimeVars(h gen.GeneratorHelper, f *jen.File, t *gen.Type, grap
 		}
 	}
 
-	if numHooks > 0 || numInterceptors > 0 || numPolicy > 0 || hasDefaults || hasValidators {
+	var fieldPolicies []*gen.Field
+	if h.FeatureEnabled(gen.FeaturePrivacy.Name) {
+		fieldPolicies = t.FieldPolicyFields()
+	}
+
+	if numHooks > 0 || numInterceptors > 0 || numPolicy > 0 || numValidators > 0 || hasDefaults || hasValidators || len(fieldPolicies) > 0 {
 		f.Var().DefsFunc(func(defs *jen.Group) {
 			if numHooks > 0 {
 				defs.Id("Hooks").Index(jen.Lit(numHooks)).Qual(h.VeloxPkg(), "Hook")
@@ -600,11 +624,20 @@ func genPackageRuntimeVars(h gen.GeneratorHelper, f *jen.File, t *gen.Type, grap
 			if numInterceptors > 0 {
 				defs.Id("Interceptors").Index(jen.Lit(numInterceptors)).Qual(h.VeloxPkg(), "Interceptor")
 			}
+			if numValidators > 0 {
+				defs.Comment("Validators holds the entity validators declared in the schema. They are")
+				defs.Comment("run on the resolved entity state by the create and update-one builders.")
+				defs.Id("Validators").Index(jen.Lit(numValidators)).Qual(h.VeloxPkg(), "EntityValidator")
+			}
 			if numPolicy > 0 {
 				defs.Id("Policy").Qual(h.VeloxPkg(), "Policy")
 				defs.Comment("RuntimePolicy is set by init() and read by the entity client constructor.")
 				defs.Id("RuntimePolicy").Qual(h.VeloxPkg(), "Policy")
 			}
+			for _, field := range fieldPolicies {
+				defs.Commentf("%s guards reads and writes of the %q field. It is set by init().", field.FieldPolicyName(), field.Name)
+				defs.Id(field.FieldPolicyName()).Op("*").Qual(privacyPkg, "FieldPolicy")
+			}
 
 			for _, field := range fields {
 				if field.Default {
@@ -642,3 +675,43 @@ func genPackageRuntimeVars(h gen.GeneratorHelper, f *jen.File, t *gen.Type, grap
 		})
 	}
 }
+
+// counterCache is a counter-cache field along with the type that holds it.
+type counterCache struct {
+	owner *gen.Type
+	field *gen.Field
+}
+
+// counterCacheFields returns the counter-cache fields (edge.CounterCache) that
+// count the rows of the type, or that are held by the type.
+func counterCacheFields(g *gen.Graph, t *gen.Type) []counterCache {
+	var cs []counterCache
+	for _, n := range g.Nodes {
+		for _, fd := range n.Fields {
+			if e := fd.CounterCache(); e != nil && (n.Name == t.Name || e.Type.Name == t.Name) {
+				cs = append(cs, counterCache{owner: n, field: fd})
+			}
+		}
+	}
+	return cs
+}
+
+// genCounterCaches generates the CounterCaches variable of the entity package.
+// Tables and columns of other types are emitted as literals to avoid imports
+// between the entity packages.
+func genCounterCaches(h gen.GeneratorHelper, f *jen.File, counters []counterCache) {
+	f.Comment("CounterCaches holds the counter caches that are kept in sync by the builders")
+	f.Comment("of this type, either as the type that holds the counter or as the counted type.")
+	f.Var().Id("CounterCaches").Op("=").Index().Op("*").Qual(h.SQLGraphPkg(), "CounterSpec").ValuesFunc(func(vals *jen.Group) {
+		for _, c := range counters {
+			e := c.field.CounterCache()
+			vals.Values(jen.Dict{
+				jen.Id("Table"):     jen.Lit(c.owner.Table()),
+				jen.Id("Column"):    jen.Lit(c.field.StorageKey()),
+				jen.Id("IDColumn"):  jen.Lit(c.owner.ID.StorageKey()),
+				jen.Id("RefTable"):  jen.Lit(e.Type.Table()),
+				jen.Id("RefColumn"): jen.Lit(e.Rel.Column()),
+			})
+		}
+	})
+}
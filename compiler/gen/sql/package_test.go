@@ -5,6 +5,7 @@ import (
 
 	"github.com/dave/jennifer/jen"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/syssam/velox/compiler/gen"
 	"github.com/syssam/velox/compiler/load"
@@ -431,3 +432,48 @@ func BenchmarkGenEdgeStepFunction(b *testing.B) {
 		genEdgeStepFunction(helper, f, userType, edge)
 	}
 }
+
+// TestGenPackage_CounterCaches verifies the counter caches (edge.CounterCache)
+// are emitted in the entity packages of both the parent and the counted type,
+// and are attached to the specs of their builders.
+func TestGenPackage_CounterCaches(t *testing.T) {
+	t.Parallel()
+	helper, userType, postType := newCounterCacheHelper(t)
+	spec := `var CounterCaches = []*sqlgraph.CounterSpec{{
+	Column:    "posts_count",
+	IDColumn:  "id",
+	RefColumn: "user_posts",
+	RefTable:  "posts",
+	Table:     "users",
+}}`
+	for _, typ := range []*gen.Type{userType, postType} {
+		code := genPackage(helper, typ, buildEntityPkgEnumRegistry(helper.graph.Nodes)).GoString()
+		assert.Contains(t, code, spec)
+
+		create, err := genCreate(helper, typ)
+		require.NoError(t, err)
+		assert.Contains(t, create.GoString(), "_spec.Counters = "+typ.PackageDir()+".CounterCaches")
+		update, err := genUpdate(helper, typ)
+		require.NoError(t, err)
+		assert.Contains(t, update.GoString(), "spec.Counters = "+typ.PackageDir()+".CounterCaches")
+		assert.Contains(t, testGenDelete(helper, typ).GoString(), "Counters:   "+typ.PackageDir()+".CounterCaches,")
+	}
+	code := genPackage(helper, userType, buildEntityPkgEnumRegistry(helper.graph.Nodes)).GoString()
+	assert.Contains(t, code, `FieldPostsCount = "posts_count"`)
+
+	// The counter is read-only: no setters on the create builder.
+	create, err := genCreate(helper, userType)
+	require.NoError(t, err)
+	assert.NotContains(t, create.GoString(), "SetPostsCount")
+
+	client := genEntityClient(helper, userType).GoString()
+	assert.Contains(t, client, "func (c *UserClient) RecountPostsCount(ctx context.Context, ps ...predicate.User) (int, error) {")
+	assert.Contains(t, client, "return sqlgraph.RecountCounter(ctx, c.config.Driver, user.CounterCaches[0], pred)")
+	assert.NotContains(t, genEntityClient(helper, postType).GoString(), "Recount")
+
+	// Types that are unrelated to counter caches have no CounterCaches.
+	plain := newMockHelper()
+	plainType := createTestType("Tag")
+	plain.graph.Nodes = []*gen.Type{plainType}
+	assert.NotContains(t, genPackage(plain, plainType, buildEntityPkgEnumRegistry(plain.graph.Nodes)).GoString(), "CounterCaches")
+}
@@ -47,6 +47,9 @@ func genGenericPredicate(h gen.GeneratorHelper, t *gen.Type) *jen.File {
 		genEdgePredicates(h, f, t, edge)
 	}
 
+	genJSONPathPredicates(h, f, t)
+	genFullTextPredicate(h, f, t)
+
 	// And/Or/Not combinators — use non-generic versions to avoid monomorphization
 	genPredicateCombinators(h, f, t)
 
@@ -216,6 +219,9 @@ func genVerbosePredicate(h gen.GeneratorHelper, t *gen.Type) *jen.File {
 		genEdgePredicates(h, f, t, edge)
 	}
 
+	genJSONPathPredicates(h, f, t)
+	genFullTextPredicate(h, f, t)
+
 	// And/Or/Not combinators — use non-generic versions to avoid monomorphization
 	genPredicateCombinators(h, f, t)
 
@@ -496,6 +502,36 @@ func genEdgePredicates(h gen.GeneratorHelper, f *jen.File, t *gen.Type, edge *ge
 	)
 }
 
+// genFullTextPredicate generates the full-text index of the type and its
+// Search predicate, if the type has a full-text index:
+//
+//	post.Search("velox orm")
+func genFullTextPredicate(h gen.GeneratorHelper, f *jen.File, t *gen.Type) {
+	idx := t.FullTextIndex()
+	if idx == nil {
+		return
+	}
+	predType := h.PredicateType(t)
+	dict := jen.Dict{
+		jen.Id("Index"): jen.Lit(idx.Name),
+		jen.Id("Columns"): jen.Index().String().ValuesFunc(func(g *jen.Group) {
+			for _, field := range t.FullTextFields() {
+				g.Id(field.Constant())
+			}
+		}),
+	}
+	if cfg := idx.EntSQL().FullTextConfig; cfg != "" {
+		dict[jen.Id("Config")] = jen.Lit(cfg)
+	}
+	f.Commentf("searchIndex is the full-text index of the %s entity.", t.Name)
+	f.Var().Id("searchIndex").Op("=").Qual(h.SQLPkg(), "FullText").Types(predType).Values(dict)
+
+	f.Comment("Search returns a predicate that matches the full-text index with the given query.")
+	f.Func().Id("Search").Params(jen.Id("query").String()).Add(predType).Block(
+		jen.Return(jen.Id("searchIndex").Dot("Search").Call(jen.Id("query"))),
+	)
+}
+
 // genPredicateCombinators generates And, Or, Not combinators as package-level
 // var declarations that delegate to the generic sql.PredicateAnd/Or/Not helpers.
 func genPredicateCombinators(h gen.GeneratorHelper, f *jen.File, t *gen.Type) {
@@ -7,6 +7,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/syssam/velox/compiler/gen"
+	"github.com/syssam/velox/dialect/sqlschema"
 	"github.com/syssam/velox/schema/field"
 )
 
@@ -378,3 +379,44 @@ func TestGenPredicate_TypeWithEnumField(t *testing.T) {
 	file := genPredicate(helper, testType)
 	require.NotNil(t, file)
 }
+
+func TestGenPredicate_FullText(t *testing.T) {
+	newType := func() *gen.Type {
+		typ := createTestTypeWithFields("Post", []*gen.Field{
+			createTestField("title", field.TypeString),
+			createTestField("body", field.TypeString),
+		})
+		typ.Indexes = []*gen.Index{{
+			Name:        "post_title_body",
+			Columns:     []string{"title", "body"},
+			Annotations: gen.Annotations{sqlschema.IndexAnnotationName: sqlschema.FullTextConfig("simple")},
+		}}
+		return typ
+	}
+	for _, features := range [][]string{nil, {"sql/entpredicates"}} {
+		helper := newFeatureMockHelper()
+		helper.withFeatures(features...)
+		typ := newType()
+		helper.graph.Nodes = []*gen.Type{typ}
+
+		file := genPredicate(helper, typ)
+		assertValidGo(t, file, "post_where")
+		code := file.GoString()
+		assert.Contains(t, code, `var searchIndex = sql.FullText[predicate.Post]{`)
+		assert.Contains(t, code, `Columns: []string{FieldTitle, FieldBody}`)
+		assert.Contains(t, code, `Config:  "simple"`)
+		assert.Contains(t, code, `func Search(query string) predicate.Post {`)
+	}
+
+	helper := newMockHelper()
+	typ := newType()
+	helper.graph.Nodes = []*gen.Type{typ}
+	file := genPackage(helper, typ, buildEntityPkgEnumRegistry(helper.graph.Nodes))
+	assertValidGo(t, file, "post")
+	assert.Contains(t, file.GoString(), `func ByRelevance(query string) OrderOption {`)
+
+	// Types without a full-text index have no search functions.
+	plain := createTestType("User")
+	helper.graph.Nodes = []*gen.Type{plain}
+	assert.NotContains(t, genPredicate(helper, plain).GoString(), "Search")
+}
@@ -7,6 +7,8 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/syssam/velox/compiler/gen"
+	"github.com/syssam/velox/privacy"
+	"github.com/syssam/velox/schema/field"
 )
 
 func TestGenPrivacy_BasicEntity(t *testing.T) {
@@ -111,3 +113,83 @@ func TestGenPrivacyEntityTypes_SingleEntity(t *testing.T) {
 	assert.Contains(t, code, "EvalQuery")
 	assert.Contains(t, code, "EvalMutation")
 }
+
+func TestGenFieldPolicy_Enforcement(t *testing.T) {
+	h := newFeatureMockHelper().withFeatures(gen.FeaturePrivacy.Name)
+	h.rootPkg = "github.com/test/project"
+	userType := createTestType("User")
+	userType.Fields[1].Annotations = gen.Annotations{privacy.FieldAnnotationName: map[string]any{}}
+	h.graph.Nodes = []*gen.Type{userType}
+
+	query := genQueryPkg(h, userType, h.graph.Nodes, "github.com/test/project/ent/entity")
+	assertValidGo(t, query, "user_query")
+	code := query.GoString()
+	assert.Contains(t, code, "func (q *UserQuery) applyFieldPolicies(")
+	assert.Contains(t, code, "func (q *UserQuery) checkFieldPolicies(")
+	assert.Contains(t, code, "EmailFieldPolicy.EvalRead(ctx, q)")
+	assert.Contains(t, code, "n.Email = _zero.Email")
+
+	mutation := genMutation(h, userType)
+	assertValidGo(t, mutation, "user_mutation")
+	assert.Contains(t, mutation.GoString(), "EmailFieldPolicy.EvalWrite(ctx, m)")
+
+	create, err := genCreate(h, userType)
+	require.NoError(t, err)
+	assert.Contains(t, create.GoString(), "c.mutation.checkFieldPolicies(ctx)")
+	update, err := genUpdate(h, userType)
+	require.NoError(t, err)
+	assert.Contains(t, update.GoString(), "_u.mutation.checkFieldPolicies(ctx)")
+}
+
+func TestGenFieldPolicy_Append(t *testing.T) {
+	h := newFeatureMockHelper().withFeatures(gen.FeaturePrivacy.Name)
+	h.rootPkg = "github.com/test/project"
+	desc := field.Strings("tags").Descriptor()
+	require.NoError(t, desc.Err)
+	userType := createTestTypeWithFields("User", []*gen.Field{
+		createTestField("name", field.TypeString),
+		{Name: "tags", Type: desc.Info, Annotations: gen.Annotations{privacy.FieldAnnotationName: map[string]any{}}},
+	})
+	h.graph.Nodes = []*gen.Type{userType}
+
+	// AppendTags records into m.appends, which AddedField does not report.
+	// The write policy of the field must still run for it.
+	mutation := genMutation(h, userType)
+	assertValidGo(t, mutation, "user_mutation")
+	code := mutation.GoString()
+	assert.Contains(t, code, "if m.hasFieldChange(user.FieldTags) {\n\t\tif err := user.TagsFieldPolicy.EvalWrite(ctx, m); err != nil {")
+	assert.Contains(t, code, "if m.fieldPatched(name) {\n\t\treturn true\n\t}")
+	assert.Contains(t, code, "func (m *UserMutation) FieldPaths(name string) []sqljson.PathValue {\n\treturn nil\n}")
+}
+
+func TestGenFieldPolicy_JSONPaths(t *testing.T) {
+	h := newFeatureMockHelper().withFeatures(gen.FeaturePrivacy.Name)
+	h.rootPkg = "github.com/test/project"
+	userType := createJSONPathType(t, false)
+	userType.Fields[1].Annotations = gen.Annotations{privacy.FieldAnnotationName: map[string]any{}}
+	h.graph.Nodes = []*gen.Type{userType}
+
+	// SetInfoAddressCity clears m._info and records the value in m.paths.
+	// The write policy of the field must still run for it.
+	mutation := genMutation(h, userType)
+	assertValidGo(t, mutation, "user_mutation")
+	code := mutation.GoString()
+	assert.Contains(t, code, "func (m *UserMutation) SetInfoAddressCity(v string) {\n\tm._info = nil\n\tm.setPath(")
+	assert.Contains(t, code, "if m.hasFieldChange(user.FieldInfo) {\n\t\tif err := user.InfoFieldPolicy.EvalWrite(ctx, m); err != nil {")
+	assert.Contains(t, code, "if m.fieldPatched(name) {\n\t\treturn true\n\t}")
+	assert.Contains(t, code, "func (m *UserMutation) fieldPatched(name string) bool {\n\tif _, ok := m.appends[name]; ok {\n\t\treturn true\n\t}\n\treturn len(m.paths[name]) > 0\n}")
+	assert.Contains(t, code, "func (m *UserMutation) FieldPaths(name string) []sqljson.PathValue {\n\treturn m.paths[name]\n}")
+	assert.Contains(t, code, "var _ velox.PatchMutation = (*UserMutation)(nil)")
+}
+
+func TestGenFieldPolicy_DisabledWithoutFeature(t *testing.T) {
+	h := newFeatureMockHelper()
+	h.rootPkg = "github.com/test/project"
+	userType := createTestType("User")
+	userType.Fields[1].Annotations = gen.Annotations{privacy.FieldAnnotationName: map[string]any{}}
+	h.graph.Nodes = []*gen.Type{userType}
+
+	code := genQueryPkg(h, userType, h.graph.Nodes, "github.com/test/project/ent/entity").GoString()
+	assert.NotContains(t, code, "FieldPolicies")
+	assert.NotContains(t, genMutation(h, userType).GoString(), "checkFieldPolicies")
+}
@@ -38,6 +38,13 @@ func genQueryPkg(h gen.GeneratorHelper, t *gen.Type, allNodes []*gen.Type, entit
 	// via q.policy.EvalQuery(). This unification means all entities
 	// (with or without policy) use the same direct access pattern.
 	hasPolicy := h.FeatureEnabled(gen.FeaturePrivacy.Name) && t.NumPolicy() > 0
+	// fieldPolicies are the fields guarded by a privacy.FieldPolicy. Reads
+	// are enforced on the loaded nodes (sqlAll) and on the scanned columns
+	// (Scan, Select, GroupBy).
+	var fieldPolicies []*gen.Field
+	if h.FeatureEnabled(gen.FeaturePrivacy.Name) {
+		fieldPolicies = t.FieldPolicyFields()
+	}
 	intersField := func(receiver string) *jen.Statement {
 		return jen.Id(receiver).Dot("inters").Dot(t.Name)
 	}
@@ -93,6 +100,10 @@ func genQueryPkg(h gen.GeneratorHelper, t *gen.Type, allNodes []*gen.Type, entit
 			targetQueryName := edge.Type.Name + "Query"
 			group.Id(edgeCallbackField(edge)).Op("*").Id(targetQueryName)
 		}
+		// Polymorphic edges are loaded with one query per target type.
+		for _, oe := range t.OneOfEdges {
+			group.Id("with" + oe.StructField()).Bool()
+		}
 		// loadTotal — registry of post-load hooks (Ent-style).
 		group.Id("loadTotal").Index().Func().Params(
 			jen.Qual("context", "Context"),
@@ -260,6 +271,9 @@ func genQueryPkg(h gen.GeneratorHelper, t *gen.Type, allNodes []*gen.Type, entit
 				}
 				sw.Case(jen.Lit(edge.Name)).Block(caseStmts...)
 			}
+			for _, oe := range t.OneOfEdges {
+				sw.Case(jen.Lit(oe.Name)).Block(jen.Id(recv).Dot("with" + oe.StructField()).Op("=").True())
+			}
 		})
 	})
 
@@ -379,6 +393,16 @@ func genQueryPkg(h gen.GeneratorHelper, t *gen.Type, allNodes []*gen.Type, entit
 		jen.Return(jen.Id(recv)),
 	)
 
+	// Timeout
+	f.Comment("Timeout limits the execution time of every statement of the query,")
+	f.Comment("including the statements that load its edges. See sql.WithTimeout.")
+	f.Func().Params(jen.Id(recv).Op("*").Id(queryName)).Id("Timeout").Params(
+		jen.Id("d").Qual("time", "Duration"),
+	).Qual(entityPkgPath, querierIface).Block(
+		jen.Id(recv).Dot("ctx").Dot("Timeout").Op("=").Id("d"),
+		jen.Return(jen.Id(recv)),
+	)
+
 	// Order
 	f.Comment("Order specifies how the records should be ordered.")
 	f.Func().Params(jen.Id(recv).Op("*").Id(queryName)).Id("Order").Params(
@@ -571,6 +595,11 @@ func genQueryPkg(h gen.GeneratorHelper, t *gen.Type, allNodes []*gen.Type, entit
 		allBody.If(jen.Len(jen.Id("nodes")).Op("==").Lit(0)).Block(
 			jen.Return(jen.Id("nodes"), jen.Nil()),
 		)
+		if len(fieldPolicies) > 0 {
+			allBody.If(jen.Err().Op(":=").Id(recv).Dot("applyFieldPolicies").Call(jen.Id("ctx"), jen.Id("nodes")), jen.Err().Op("!=").Nil()).Block(
+				jen.Return(jen.Nil(), jen.Err()),
+			)
+		}
 
 		// Phase 1 — Standard eager loading.
 		for _, edge := range t.Edges {
@@ -643,6 +672,14 @@ func genQueryPkg(h gen.GeneratorHelper, t *gen.Type, allNodes []*gen.Type, entit
 			})
 		}
 
+		for _, oe := range t.OneOfEdges {
+			allBody.If(jen.Id(recv).Dot("with" + oe.StructField())).Block(
+				jen.If(jen.Err().Op(":=").Id(recv).Dot("load"+oe.StructField()).Call(jen.Id("ctx"), jen.Id("nodes")), jen.Err().Op("!=").Nil()).Block(
+					jen.Return(jen.Nil(), jen.Err()),
+				),
+			)
+		}
+
 		// Phase 2 — Named edge variants (FeatureNamedEdges).
 		if namedEdgesEnabled {
 			for _, edge := range t.Edges {
@@ -729,6 +766,10 @@ func genQueryPkg(h gen.GeneratorHelper, t *gen.Type, allNodes []*gen.Type, entit
 		))
 	})
 
+	if len(fieldPolicies) > 0 {
+		genQueryFieldPolicies(f, fieldPolicies, queryName, recv, entityType, entitySubPkg)
+	}
+
 	// All — wraps sqlAll with interceptor support (Ent-style).
 	f.Commentf("All executes the query and returns a list of %s.", t.Name)
 	f.Func().Params(jen.Id(recv).Op("*").Id(queryName)).Id("All").Params(
@@ -927,7 +968,8 @@ func genQueryPkg(h gen.GeneratorHelper, t *gen.Type, allNodes []*gen.Type, entit
 	// SQL — returns the generated SQL string and args without executing.
 	f.Comment("SQL returns the SQL query string and arguments for debugging.")
 	f.Comment("It runs prepareQuery (privacy traversers) and builds the selector,")
-	f.Comment("but does not execute the query.")
+	f.Comment("but does not execute the query. The arguments of sensitive columns")
+	f.Comment("are redacted, see dialect.RedactArgs.")
 	f.Func().Params(jen.Id(recv).Op("*").Id(queryName)).Id("SQL").Params(
 		jen.Id("ctx").Qual("context", "Context"),
 	).Params(jen.String(), jen.Index().Any(), jen.Error()).Block(
@@ -939,12 +981,13 @@ func genQueryPkg(h gen.GeneratorHelper, t *gen.Type, allNodes []*gen.Type, entit
 			jen.Return(jen.Lit(""), jen.Nil(), jen.Err()),
 		),
 		jen.List(jen.Id("query"), jen.Id("args")).Op(":=").Id("selector").Dot("Query").Call(),
-		jen.Return(jen.Id("query"), jen.Id("args"), jen.Nil()),
+		jen.Return(jen.Id("query"), jen.Qual(dialectPkg(), "RedactArgs").Call(jen.Id("query"), jen.Id("args")), jen.Nil()),
 	)
 
 	// Explain — returns a *runtime.QueryPlan describing the query without executing.
 	f.Comment("Explain returns the query's execution plan without executing it.")
 	f.Comment("Includes the SQL, arguments, planned edge loads, and active interceptors.")
+	f.Comment("The arguments of sensitive columns are redacted, see dialect.RedactArgs.")
 	f.Func().Params(jen.Id(recv).Op("*").Id(queryName)).Id("Explain").Params(
 		jen.Id("ctx").Qual("context", "Context"),
 	).Params(jen.Op("*").Qual(runtimePkg, "QueryPlan"), jen.Error()).BlockFunc(func(body *jen.Group) {
@@ -963,7 +1006,7 @@ func genQueryPkg(h gen.GeneratorHelper, t *gen.Type, allNodes []*gen.Type, entit
 		// Initialize plan
 		body.Id("plan").Op(":=").Op("&").Qual(runtimePkg, "QueryPlan").Values(jen.Dict{
 			jen.Id("SQL"):  jen.Id("query"),
-			jen.Id("Args"): jen.Id("args"),
+			jen.Id("Args"): jen.Qual(dialectPkg(), "RedactArgs").Call(jen.Id("query"), jen.Id("args")),
 		})
 
 		// Collect interceptor type names
@@ -986,7 +1029,7 @@ func genQueryPkg(h gen.GeneratorHelper, t *gen.Type, allNodes []*gen.Type, entit
 						jen.Qual(runtimePkg, "EdgePlan").Values(jen.Dict{
 							jen.Id("Name"): jen.Lit(edge.Name),
 							jen.Id("SQL"):  jen.Id("eq"),
-							jen.Id("Args"): jen.Id("ea"),
+							jen.Id("Args"): jen.Qual(dialectPkg(), "RedactArgs").Call(jen.Id("eq"), jen.Id("ea")),
 						}),
 					),
 				)
@@ -1331,14 +1374,19 @@ func genQueryPkg(h gen.GeneratorHelper, t *gen.Type, allNodes []*gen.Type, entit
 	f.Func().Params(jen.Id(recv).Op("*").Id(queryName)).Id("Scan").Params(
 		jen.Id("ctx").Qual("context", "Context"),
 		jen.Id("v").Any(),
-	).Error().Block(
-		jen.If(jen.Err().Op(":=").Id(recv).Dot("prepareQuery").Call(jen.Id("ctx")), jen.Err().Op("!=").Nil()).Block(
+	).Error().BlockFunc(func(body *jen.Group) {
+		body.If(jen.Err().Op(":=").Id(recv).Dot("prepareQuery").Call(jen.Id("ctx")), jen.Err().Op("!=").Nil()).Block(
 			jen.Return(jen.Id("err")),
-		),
-		jen.Return(jen.Qual(runtimePkg, "QueryScan").Call(
+		)
+		if len(fieldPolicies) > 0 {
+			body.If(jen.Err().Op(":=").Id(recv).Dot("checkFieldPolicies").Call(jen.Id("ctx"), jen.Id(recv).Dot("ctx").Dot("Fields")), jen.Err().Op("!=").Nil()).Block(
+				jen.Return(jen.Err()),
+			)
+		}
+		body.Return(jen.Qual(runtimePkg, "QueryScan").Call(
 			jen.Id("ctx"), jen.Id(recv), jen.Id("v"),
-		)),
-	)
+		))
+	})
 
 	f.Comment("ScanX is like Scan, but panics if an error occurs.")
 	f.Func().Params(jen.Id(recv).Op("*").Id(queryName)).Id("ScanX").Params(
@@ -1410,6 +1458,17 @@ func genQueryPkg(h gen.GeneratorHelper, t *gen.Type, allNodes []*gen.Type, entit
 		body.If(jen.Err().Op(":=").Id("s").Dot(queryName).Dot("prepareQuery").Call(jen.Id("ctx")), jen.Err().Op("!=").Nil()).Block(
 			jen.Return(jen.Err()),
 		)
+		if len(fieldPolicies) > 0 {
+			// Aggregations may read any column, so they are checked
+			// against every guarded field.
+			body.Id("columns").Op(":=").Id("s").Dot(queryName).Dot("ctx").Dot("Fields")
+			body.If(jen.Len(jen.Id("s").Dot("Fns").Call()).Op(">").Lit(0)).Block(
+				jen.Id("columns").Op("=").Nil(),
+			)
+			body.If(jen.Err().Op(":=").Id("s").Dot(queryName).Dot("checkFieldPolicies").Call(jen.Id("ctx"), jen.Id("columns")), jen.Err().Op("!=").Nil()).Block(
+				jen.Return(jen.Err()),
+			)
+		}
 		body.Return(jen.Qual(runtimePkg, "ScanWithInterceptors").Call(
 			jen.Id("ctx"),
 			jen.Id("s").Dot(queryName),
@@ -1464,6 +1523,15 @@ func genQueryPkg(h gen.GeneratorHelper, t *gen.Type, allNodes []*gen.Type, entit
 		body.If(jen.Err().Op(":=").Id("g").Dot("build").Dot("prepareQuery").Call(jen.Id("ctx")), jen.Err().Op("!=").Nil()).Block(
 			jen.Return(jen.Err()),
 		)
+		if len(fieldPolicies) > 0 {
+			body.Id("columns").Op(":=").Id("g").Dot("fields")
+			body.If(jen.Len(jen.Id("g").Dot("Fns").Call()).Op(">").Lit(0)).Block(
+				jen.Id("columns").Op("=").Nil(),
+			)
+			body.If(jen.Err().Op(":=").Id("g").Dot("build").Dot("checkFieldPolicies").Call(jen.Id("ctx"), jen.Id("columns")), jen.Err().Op("!=").Nil()).Block(
+				jen.Return(jen.Err()),
+			)
+		}
 		body.Return(jen.Qual(runtimePkg, "ScanWithInterceptors").Call(
 			jen.Id("ctx"),
 			jen.Id("g").Dot("build"),
@@ -1524,6 +1592,10 @@ func genQueryPkg(h gen.GeneratorHelper, t *gen.Type, allNodes []*gen.Type, entit
 			field := edgeCallbackField(edge)
 			cloneDict[jen.Id(field)] = jen.Id(recv).Dot(field).Dot("clone").Call()
 		}
+		for _, oe := range t.OneOfEdges {
+			field := "with" + oe.StructField()
+			cloneDict[jen.Id(field)] = jen.Id(recv).Dot(field)
+		}
 		// Copy loadTotal slice.
 		cloneDict[jen.Id("loadTotal")] = jen.Qual(runtimePkg, "CloneSlice").Call(jen.Id(recv).Dot("loadTotal"))
 		body.Id("c").Op(":=").Op("&").Id(queryName).Values(cloneDict)
@@ -1553,6 +1625,7 @@ func genQueryPkg(h gen.GeneratorHelper, t *gen.Type, allNodes []*gen.Type, entit
 	for _, edge := range t.Edges {
 		genTypedEdgeLoader(f, h, t, edge, recv, queryName, entityPkgPath, entityType)
 	}
+	genQueryPkgOneOfLoaders(h, f, t, recv, queryName, entityPkgPath)
 
 	// Verify interface compliance at compile time
 	f.Commentf("Verify %s implements %s.%s at compile time.", queryName, "entity", querierIface)
@@ -2147,7 +2220,8 @@ func genQueryHelpers(h gen.GeneratorHelper) *jen.File {
 	f.Line()
 
 	// setContextOp bridges runtime.QueryContext to velox.QueryContext for interceptor context propagation.
-	f.Comment("setContextOp returns a new context with the given QueryContext attached (including its op) in case it does not exist.")
+	f.Comment("setContextOp returns a new context with the given QueryContext attached (including its op) in case it does not exist,")
+	f.Comment("and with the statement timeout of the query, if any.")
 	f.Func().Id("setContextOp").Params(
 		jen.Id("ctx").Qual("context", "Context"),
 		jen.Id("qc").Op("*").Qual(runtimePkg, "QueryContext"),
@@ -2166,8 +2240,61 @@ func genQueryHelpers(h gen.GeneratorHelper) *jen.File {
 				}),
 			),
 		),
+		jen.If(jen.Id("qc").Dot("Timeout").Op(">").Lit(0)).Block(
+			jen.Id("ctx").Op("=").Qual(h.SQLPkg(), "WithTimeout").Call(jen.Id("ctx"), jen.Id("qc").Dot("Timeout")),
+		),
 		jen.Return(jen.Id("ctx")),
 	)
 
 	return f
 }
+
+// genQueryFieldPolicies generates the read enforcement of field policies:
+// applyFieldPolicies clears (or, for strict policies, rejects) denied fields
+// on loaded nodes, and checkFieldPolicies rejects scans of denied columns.
+func genQueryFieldPolicies(f *jen.File, fields []*gen.Field, queryName, recv string, entityType func() *jen.Statement, entitySubPkg string) {
+	f.Comment("applyFieldPolicies evaluates the field policies of the query and clears")
+	f.Comment("the denied fields on the given nodes. Strict policies fail the query instead.")
+	f.Func().Params(jen.Id(recv).Op("*").Id(queryName)).Id("applyFieldPolicies").Params(
+		jen.Id("ctx").Qual("context", "Context"),
+		jen.Id("nodes").Index().Op("*").Add(entityType()),
+	).Error().BlockFunc(func(body *jen.Group) {
+		body.Var().Id("_zero").Add(entityType())
+		for _, fd := range fields {
+			policy := jen.Qual(entitySubPkg, fd.FieldPolicyName())
+			body.If(jen.Err().Op(":=").Add(policy.Clone()).Dot("EvalRead").Call(jen.Id("ctx"), jen.Id(recv)), jen.Err().Op("!=").Nil()).Block(
+				jen.If(jen.Add(policy.Clone()).Dot("Strict").Op("||").Op("!").Qual("errors", "Is").Call(jen.Err(), jen.Qual(privacyPkg, "Deny"))).Block(
+					jen.Return(jen.Err()),
+				),
+				jen.For(jen.List(jen.Id("_"), jen.Id("n")).Op(":=").Range().Id("nodes")).Block(
+					jen.Id("n").Dot(fd.StructField()).Op("=").Id("_zero").Dot(fd.StructField()),
+				),
+			)
+		}
+		body.Return(jen.Nil())
+	})
+
+	f.Comment("checkFieldPolicies evaluates the field policies of the given columns, or")
+	f.Comment("of all columns if none are given. Scans cannot clear a single column, so")
+	f.Comment("any denied column fails them.")
+	f.Func().Params(jen.Id(recv).Op("*").Id(queryName)).Id("checkFieldPolicies").Params(
+		jen.Id("ctx").Qual("context", "Context"),
+		jen.Id("columns").Index().String(),
+	).Error().BlockFunc(func(body *jen.Group) {
+		body.If(jen.Len(jen.Id("columns")).Op("==").Lit(0)).Block(
+			jen.Id("columns").Op("=").Qual(entitySubPkg, "Columns"),
+		)
+		body.For(jen.List(jen.Id("_"), jen.Id("c")).Op(":=").Range().Id("columns")).Block(
+			jen.Switch(jen.Id("c")).BlockFunc(func(sw *jen.Group) {
+				for _, fd := range fields {
+					sw.Case(jen.Qual(entitySubPkg, fd.Constant())).Block(
+						jen.If(jen.Err().Op(":=").Qual(entitySubPkg, fd.FieldPolicyName()).Dot("EvalRead").Call(jen.Id("ctx"), jen.Id(recv)), jen.Err().Op("!=").Nil()).Block(
+							jen.Return(jen.Err()),
+						),
+					)
+				}
+			}),
+		)
+		body.Return(jen.Nil())
+	})
+}
@@ -213,6 +213,18 @@ func TestGenQueryHelpers(t *testing.T) {
 	file := genQueryHelpers(h)
 	require.NotNil(t, file)
 	assertValidGo(t, file, "query_helpers")
+	assert.Contains(t, file.GoString(), "ctx = sql.WithTimeout(ctx, qc.Timeout)")
+}
+
+func TestGenQueryPkg_Timeout(t *testing.T) {
+	t.Parallel()
+	h := newFeatureMockHelper()
+	userType := createTestType("User")
+	h.graph.Nodes = []*gen.Type{userType}
+
+	code := genQueryPkg(h, userType, h.graph.Nodes, "github.com/test/project/ent/entity").GoString()
+	assert.Contains(t, code, "func (q *UserQuery) Timeout(d time.Duration) entity.UserQuerier {")
+	assert.Contains(t, code, "q.ctx.Timeout = d")
 }
 
 func TestEdgeCallbackField(t *testing.T) {
@@ -10,17 +10,20 @@ import (
 
 // genRuntimeEntityInit generates the runtime initialization for a single entity.
 // It follows Ent's template logic for handling mixins and field positions.
-// Order: Mixin → Policies → Hooks → Interceptors → Fields
+// Order: Mixin → Policies → Hooks → Interceptors → Validators → Fields
 func genRuntimeEntityInit(h gen.GeneratorHelper, grp *jen.Group, t *gen.Type, schemaPkg string) {
 	entityPkg := h.LeafPkgPath(t)
 	pkg := t.Package() // lowercase package name (e.g., "abtestevent")
 
 	// Check if entity has defaults, update defaults, validators, or value scanners
 	validatorsEnabled, _ := h.Graph().FeatureEnabled(gen.FeatureValidator.Name)
-	hasRuntimeFields := t.HasDefault() || t.HasUpdateDefault() || (validatorsEnabled && t.HasValidators())
+	privacyEnabled, _ := h.Graph().FeatureEnabled(gen.FeaturePrivacy.Name)
+	hasRuntimeFields := t.HasDefault() || t.HasUpdateDefault() || (validatorsEnabled && t.HasValidators()) ||
+		(privacyEnabled && len(t.FieldPolicyFields()) > 0)
 
-	// Skip if no runtime code needed (no mixins and no runtime fields)
-	if !hasRuntimeFields && !t.RuntimeMixin() {
+	// Skip if no runtime code needed (no mixins, no runtime fields, no outbox
+	// hook and no entity validators)
+	if !hasRuntimeFields && !t.RuntimeMixin() && !t.HasOutbox() && t.NumValidators() == 0 {
 		return
 	}
 
@@ -34,11 +37,15 @@ func genRuntimeEntityInit(h gen.GeneratorHelper, grp *jen.Group, t *gen.Type, sc
 
 	// 3. Generate hooks initialization
 	genRuntimeHooks(h, grp, t, schemaPkg, entityPkg, pkg)
+	genRuntimeOutbox(h, grp, t)
 
 	// 4. Generate interceptors initialization
 	genRuntimeInterceptors(h, grp, t, schemaPkg, entityPkg, pkg)
 
-	// 5. Generate fields initialization (defaults, validators)
+	// 5. Generate entity validators initialization
+	genRuntimeEntityValidators(grp, t, schemaPkg, entityPkg, pkg)
+
+	// 6. Generate fields initialization (defaults, validators)
 	if hasRuntimeFields {
 		genRuntimeFields(h, grp, t, schemaPkg, entityPkg, pkg)
 	}
@@ -101,6 +108,7 @@ func genRuntimeFields(h gen.GeneratorHelper, grp *jen.Group, t *gen.Type, schema
 
 	// Check if validators feature is enabled
 	validatorsEnabled, _ := h.Graph().FeatureEnabled(gen.FeatureValidator.Name)
+	privacyEnabled, _ := h.Graph().FeatureEnabled(gen.FeaturePrivacy.Name)
 
 	// Process each field (including edge fields - they can have validators too)
 	for _, field := range fields {
@@ -111,9 +119,10 @@ func genRuntimeFields(h gen.GeneratorHelper, grp *jen.Group, t *gen.Type, schema
 		hasUpdateDefault := field.UpdateDefault
 		hasValidators := validatorsEnabled && (field.Validators > 0 || field.IsEnum())
 		hasValueScanner := field.HasValueScanner()
+		hasFieldPolicy := privacyEnabled && field.HasFieldPolicy()
 
 		// Skip if no runtime code needed for this field
-		if !hasDefault && !hasUpdateDefault && !hasValidators && !hasValueScanner {
+		if !hasDefault && !hasUpdateDefault && !hasValidators && !hasValueScanner && !hasFieldPolicy {
 			continue
 		}
 
@@ -122,7 +131,7 @@ func genRuntimeFields(h gen.GeneratorHelper, grp *jen.Group, t *gen.Type, schema
 		// The descriptor is needed for defaults, updateDefaults, valueScanner, or
 		// non-enum validators. Enum-only validators are generated inline without
 		// referencing the descriptor.
-		needsDescriptor := hasDefault || hasUpdateDefault || hasValueScanner || (hasValidators && field.Validators > 0)
+		needsDescriptor := hasDefault || hasUpdateDefault || hasValueScanner || hasFieldPolicy || (hasValidators && field.Validators > 0)
 
 		if needsDescriptor {
 			// Generate descriptor assignment based on field position
@@ -165,6 +174,14 @@ func genRuntimeFields(h gen.GeneratorHelper, grp *jen.Group, t *gen.Type, schema
 		if hasValidators {
 			genRuntimeValidator(h, grp, t, field, fieldVar, entityPkg, pkg)
 		}
+
+		// Generate field policy initialization
+		if hasFieldPolicy {
+			grp.Commentf("// %s.%s guards reads and writes of the %q field.", pkg, field.FieldPolicyName(), field.Name)
+			grp.Qual(entityPkg, field.FieldPolicyName()).Op("=").Qual(privacyPkg, "FieldPolicyFor").Call(
+				jen.Lit(t.Name), jen.Lit(field.Name), jen.Id(fieldVar).Dot("Annotations"),
+			)
+		}
 	}
 }
 
@@ -245,6 +262,20 @@ func genRuntimeInterceptors(h gen.GeneratorHelper, grp *jen.Group, t *gen.Type,
 	}
 }
 
+// genRuntimeEntityValidators generates the entity validators initialization
+// for an entity. Validators are declared on the schema only (not on mixins).
+func genRuntimeEntityValidators(grp *jen.Group, t *gen.Type, schemaPkg, entityPkg, pkg string) {
+	positions := t.ValidatorPositions()
+	if len(positions) == 0 {
+		return
+	}
+	grp.Id(pkg+"Validators").Op(":=").Qual(schemaPkg, t.Name).Values().Dot("Validators").Call()
+	for i, p := range positions {
+		grp.Qual(entityPkg, "Validators").Index(jen.Lit(i)).Op("=").
+			Id(pkg + "Validators").Index(jen.Lit(p.Index))
+	}
+}
+
 // itoa converts int to string
 func itoa(i int) string {
 	return strconv.Itoa(i)
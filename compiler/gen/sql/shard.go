@@ -0,0 +1,50 @@
+package sql
+
+import (
+	"github.com/dave/jennifer/jen"
+
+	"github.com/syssam/velox/compiler/gen"
+)
+
+// shardPkg is the import path for the velox shard package.
+const shardPkg = "github.com/syssam/velox/dialect/sql/shard"
+
+// genCreateShardKey generates the routing of a create to the shard of its
+// shard key field, if the type has one. An unset key leaves the context as
+// is, so a shard key set by the caller still applies.
+func genCreateShardKey(grp *jen.Group, t *gen.Type, recv string) {
+	f := t.ShardKey()
+	if f == nil {
+		return
+	}
+	grp.If(
+		jen.List(jen.Id("v"), jen.Id("ok")).Op(":=").Id(recv).Dot("mutation").Dot(f.MutationGet()).Call(),
+		jen.Id("ok"),
+	).Block(
+		jen.Id("ctx").Op("=").Qual(shardPkg, "WithKey").Call(jen.Id("ctx"), jen.Id("v")),
+	)
+}
+
+// genBulkShardKey generates the routing of a bulk create to the shard of the
+// shard key field of its builders, which must all have the same key.
+func genBulkShardKey(grp *jen.Group, t *gen.Type) {
+	f := t.ShardKey()
+	if f == nil {
+		return
+	}
+	grp.Id("keys").Op(":=").Make(jen.Index().Any(), jen.Len(jen.Id("builders")))
+	grp.For(jen.List(jen.Id("j"), jen.Id("b")).Op(":=").Range().Id("builders")).Block(
+		jen.If(
+			jen.List(jen.Id("v"), jen.Id("ok")).Op(":=").Id("b").Dot("mutation").Dot(f.MutationGet()).Call(),
+			jen.Id("ok"),
+		).Block(
+			jen.Id("keys").Index(jen.Id("j")).Op("=").Id("v"),
+		),
+	)
+	grp.If(
+		jen.List(jen.Id("ctx"), jen.Id("err")).Op("=").Qual(shardPkg, "WithBulkKey").Call(jen.Id("ctx"), jen.Id("keys").Op("...")),
+		jen.Id("err").Op("!=").Nil(),
+	).Block(
+		jen.Return(jen.Nil(), jen.Id("err")),
+	)
+}
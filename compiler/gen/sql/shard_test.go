@@ -0,0 +1,34 @@
+package sql
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/syssam/velox/compiler/gen"
+	"github.com/syssam/velox/dialect/sql/shard"
+)
+
+func TestGenCreate_ShardKey(t *testing.T) {
+	t.Parallel()
+	helper := newFeatureMockHelper()
+	helper.rootPkg = "github.com/test/project"
+	userType := createTestType("User")
+	userType.Annotations = gen.Annotations{shard.AnnotationName: shard.Key("name")}
+	helper.graph.Nodes = []*gen.Type{userType}
+
+	create, err := genCreate(helper, userType)
+	require.NoError(t, err)
+	assert.NotContains(t, create.GoString(), "shard.")
+
+	userType.Features = []gen.Feature{gen.FeatureShard}
+	require.Equal(t, "name", userType.ShardKey().Name)
+	create, err = genCreate(helper, userType)
+	require.NoError(t, err)
+	assertValidGo(t, create, "user_create")
+	code := create.GoString()
+	assert.Contains(t, code, "if v, ok := c.mutation.Name(); ok {\n\t\tctx = shard.WithKey(ctx, v)\n\t}")
+	assert.Contains(t, code, "keys := make([]any, len(builders))")
+	assert.Contains(t, code, "if ctx, err = shard.WithBulkKey(ctx, keys...); err != nil {")
+}
@@ -254,6 +254,7 @@ func (_cb *PostCreateBulk) saveChunk(ctx context.Context, builders []*PostCreate
 	if len(builders) == 0 {
 		return []*entity.Post{}, nil
 	}
+	ctx = velox.NewMutationContext(ctx, builders[0].mutation)
 	specs := make([]*sqlgraph.CreateSpec, len(builders))
 	nodes := make([]*entity.Post, len(builders))
 	mutators := make([]runtime.Mutator, len(builders))
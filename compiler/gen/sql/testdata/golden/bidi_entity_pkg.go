@@ -6,6 +6,7 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"time"
 
 	velox "github.com/syssam/velox"
 	sql "github.com/syssam/velox/dialect/sql"
@@ -226,6 +227,7 @@ type PostQuerier interface {
 	Offset(n int) PostQuerier
 	Order(o ...func(*sql.Selector)) PostQuerier
 	Unique(unique bool) PostQuerier
+	Timeout(d time.Duration) PostQuerier
 	WithAuthor(opts ...func(UserQuerier)) PostQuerier
 	Select(fields ...string) PostSelector
 	Modify(modifiers ...func(*sql.Selector)) PostQuerier
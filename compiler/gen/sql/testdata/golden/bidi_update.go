@@ -5,6 +5,7 @@ package ent
 import (
 	"context"
 	"errors"
+	"fmt"
 	"slices"
 
 	velox "github.com/syssam/velox"
@@ -444,3 +445,240 @@ func (_u *PostUpdateOne) ExecX(ctx context.Context) {
 		panic(err)
 	}
 }
+
+// PostUpdateBulk is the builder for updating many Post entities in bulk,
+// each with its own values.
+type PostUpdateBulk struct {
+	config    runtime.Config
+	err       error
+	builders  []*PostUpdateOne
+	batchSize int
+}
+
+// NewPostUpdateBulk creates a new PostUpdateBulk builder.
+func NewPostUpdateBulk(c runtime.Config, builders []*PostUpdateOne) *PostUpdateBulk {
+	return &PostUpdateBulk{
+		builders: builders,
+		config:   c,
+	}
+}
+
+// BatchSize sets the number of rows updated per chunk. A value of 0
+// (default) means sqlgraph.DefaultBatchUpdateSize. Each chunk runs its
+// own mutator chain and transaction; wrap the call in client.Tx(ctx)
+// if you need all-or-nothing semantics across chunks.
+func (_ub *PostUpdateBulk) BatchSize(n int) *PostUpdateBulk {
+	_ub.batchSize = n
+	return _ub
+}
+
+// Save updates the Post entities in the database and returns them in the
+// order of the builders. Builders that set the same fields are updated with
+// one statement per chunk; see sqlgraph.BatchUpdate for the SQL of each
+// dialect. A mid-loop error returns the rows updated so far together with
+// the error.
+func (_ub *PostUpdateBulk) Save(ctx context.Context) ([]*entity.Post, error) {
+	if _ub.err != nil {
+		return nil, _ub.err
+	}
+	size := _ub.batchSize
+	if size <= 0 {
+		size = sqlgraph.DefaultBatchUpdateSize
+	}
+	nodes := make([]*entity.Post, 0, len(_ub.builders))
+	for start := 0; start < len(_ub.builders); start += size {
+		end := min(start+size, len(_ub.builders))
+		chunk, err := _ub.saveChunk(ctx, _ub.builders[start:end])
+		nodes = append(nodes, chunk...)
+		if err != nil {
+			return nodes, err
+		}
+	}
+	return nodes, nil
+}
+
+// saveChunk runs one mutator chain over a sub-slice of the builders.
+func (_ub *PostUpdateBulk) saveChunk(ctx context.Context, builders []*PostUpdateOne) ([]*entity.Post, error) {
+	for _, b := range builders {
+		b.mutation.oldValue = func(ctx context.Context) (*entity.Post, error) {
+			id, ok := b.mutation.ID()
+			if !ok {
+				return nil, errors.New("velox: missing ID for OldField")
+			}
+			build := func(_ context.Context) (*sql.Selector, error) {
+				s := sql.Select(post.Columns...).From(sql.Table(post.Table))
+				s.SetDialect(b.config.Driver.Dialect())
+				s.Where(sql.EQ(s.C(post.FieldID), id))
+				s.Limit(1)
+				return s, nil
+			}
+			_old, err := runtime.ScanFirst[entity.Post, *entity.Post](ctx, b.config.Driver, build, "Post")
+			if err != nil {
+				return nil, err
+			}
+			_old.SetConfig(b.config)
+			return _old, nil
+		}
+	}
+	specs := make([]*sqlgraph.UpdateSpec, len(builders))
+	nodes := make([]*entity.Post, len(builders))
+	mutators := make([]runtime.Mutator, len(builders))
+	for i := range builders {
+		func(i int, root context.Context) {
+			builder := builders[i]
+			var mut runtime.Mutator = runtime.MutateFunc(func(ctx context.Context, m runtime.Mutation) (runtime.Value, error) {
+				mutation, ok := m.(*PostMutation)
+				if !ok {
+					return nil, fmt.Errorf("velox: unexpected mutation type %T", m)
+				}
+				builder.mutation = mutation
+				if err := builder.check(); err != nil {
+					return nil, err
+				}
+				id, ok := builder.mutation.ID()
+				if !ok {
+					return nil, errors.New("velox: missing ID for UpdateOne")
+				}
+				spec := sqlgraph.NewUpdateSpec(post.Table, post.Columns, &sqlgraph.FieldSpec{
+					Column: post.FieldID,
+					Type:   postIDFieldType,
+				})
+				if len(builder.selectFields) == 0 || slices.Contains(builder.selectFields, "title") {
+					if builder.mutation._title != nil {
+						spec.SetField("title", field.TypeString, *builder.mutation._title)
+					}
+				}
+				if len(builder.selectFields) == 0 || slices.Contains(builder.selectFields, "content") {
+					if builder.mutation._content != nil {
+						spec.SetField("content", field.TypeString, *builder.mutation._content)
+					}
+				}
+				spec.Node.ID.Value = id
+				ps := builder.mutation.PredicatesFuncs()
+				if len(ps) > 0 {
+					spec.Predicate = func(s *sql.Selector) {
+						s.Where(sql.EQ(s.C(post.FieldID), id))
+						for i := range ps {
+							ps[i](s)
+						}
+					}
+				}
+				if builder.mutation.AuthorCleared() {
+					edge := &sqlgraph.EdgeSpec{
+						Bidi:    false,
+						Columns: []string{"user_posts"},
+						Inverse: true,
+						Rel:     sqlgraph.M2O,
+						Table:   "posts",
+						Target: &sqlgraph.EdgeTarget{IDSpec: &sqlgraph.FieldSpec{
+							Column: "id",
+							Type:   field.TypeInt64,
+						}},
+					}
+					spec.Edges.Clear = append(spec.Edges.Clear, edge)
+				}
+				if nodes := builder.mutation.AuthorIDs(); len(nodes) > 0 {
+					edge := &sqlgraph.EdgeSpec{
+						Bidi:    false,
+						Columns: []string{"user_posts"},
+						Inverse: true,
+						Rel:     sqlgraph.M2O,
+						Table:   "posts",
+						Target: &sqlgraph.EdgeTarget{IDSpec: &sqlgraph.FieldSpec{
+							Column: "id",
+							Type:   field.TypeInt64,
+						}},
+					}
+					for _, k := range nodes {
+						edge.Target.Nodes = append(edge.Target.Nodes, k)
+					}
+					spec.Edges.Add = append(spec.Edges.Add, edge)
+				}
+				if len(builder.modifiers) > 0 {
+					spec.AddModifiers(builder.modifiers...)
+				}
+				specs[i] = spec
+				var err error
+				if i < len(mutators)-1 {
+					_, err = mutators[i+1].Mutate(root, builders[i+1].mutation)
+				} else {
+					err = _ub.sqlSave(ctx, builders, specs, nodes)
+				}
+				if err != nil {
+					return nil, err
+				}
+				return nodes[i], nil
+			})
+			for j := len(builder.hooks) - 1; j >= 0; j-- {
+				mut = builder.hooks[j](mut)
+			}
+			mutators[i] = mut
+		}(i, ctx)
+	}
+	if len(mutators) > 0 {
+		if _, err := mutators[0].Mutate(ctx, builders[0].mutation); err != nil {
+			return nil, err
+		}
+	}
+	return nodes, nil
+}
+
+// sqlSave updates the rows of a chunk and reads the updated Post entities
+// back into nodes, in the order of the builders.
+func (_ub *PostUpdateBulk) sqlSave(ctx context.Context, builders []*PostUpdateOne, specs []*sqlgraph.UpdateSpec, nodes []*entity.Post) error {
+	if err := sqlgraph.BatchUpdate(ctx, _ub.config.Driver, &sqlgraph.BatchUpdateSpec{Nodes: specs}); err != nil {
+		if _, ok := err.(*sqlgraph.NotFoundError); ok {
+			return velox.NewNotFoundError("Post")
+		}
+		return runtime.MayWrapConstraintError(err)
+	}
+	ids := make([]any, len(builders))
+	for i, b := range builders {
+		ids[i] = *b.mutation.id
+	}
+	build := func(_ context.Context) (*sql.Selector, error) {
+		s := sql.Select(post.Columns...).From(sql.Table(post.Table))
+		s.SetDialect(_ub.config.Driver.Dialect())
+		s.Where(sql.In(s.C(post.FieldID), ids...))
+		return s, nil
+	}
+	updated, err := runtime.ScanAll[entity.Post, *entity.Post](ctx, _ub.config.Driver, build)
+	if err != nil {
+		return err
+	}
+	byID := make(map[int64]*entity.Post, len(updated))
+	for _, n := range updated {
+		byID[n.ID] = n
+	}
+	for i, b := range builders {
+		node, ok := byID[*b.mutation.id]
+		if !ok {
+			return velox.NewNotFoundError("Post")
+		}
+		node.SetConfig(_ub.config)
+		nodes[i] = node
+	}
+	return nil
+}
+
+// SaveX is like Save, but panics if an error occurs.
+func (_ub *PostUpdateBulk) SaveX(ctx context.Context) []*entity.Post {
+	v, err := _ub.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// Exec executes the query.
+func (_ub *PostUpdateBulk) Exec(ctx context.Context) error {
+	_, err := _ub.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (_ub *PostUpdateBulk) ExecX(ctx context.Context) {
+	if err := _ub.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
@@ -314,6 +314,7 @@ func (_cb *UserCreateBulk) saveChunk(ctx context.Context, builders []*UserCreate
 	if len(builders) == 0 {
 		return []*entity.User{}, nil
 	}
+	ctx = velox.NewMutationContext(ctx, builders[0].mutation)
 	specs := make([]*sqlgraph.CreateSpec, len(builders))
 	nodes := make([]*entity.User, len(builders))
 	mutators := make([]runtime.Mutator, len(builders))
@@ -90,6 +90,28 @@ func (c *UserClient) UpdateOne(v *entity.User) *UserUpdateOne {
 	return c.UpdateOneID(v.ID)
 }
 
+// UpdateBulk returns a builder for updating a bulk of User entities, each with
+// its own values. The builders are usually created by UpdateOneID or UpdateOne.
+func (c *UserClient) UpdateBulk(builders ...*UserUpdateOne) *UserUpdateBulk {
+	return NewUserUpdateBulk(c.config, builders)
+}
+
+// MapUpdateBulk creates a bulk update builder from the given slice.
+// For each item in the slice, the id function returns the id of the entity
+// to update, and the set function is called to configure its builder.
+func (c *UserClient) MapUpdateBulk(slice any, idFunc func(int) int64, setFunc func(*UserUpdateOne, int)) *UserUpdateBulk {
+	rv := reflect.ValueOf(slice)
+	if rv.Kind() != reflect.Slice {
+		return &UserUpdateBulk{err: fmt.Errorf("calling to %T.MapUpdateBulk with wrong type %T, need slice", c, slice)}
+	}
+	builders := make([]*UserUpdateOne, rv.Len())
+	for i := 0; i < rv.Len(); i++ {
+		builders[i] = c.UpdateOneID(idFunc(i))
+		setFunc(builders[i], i)
+	}
+	return NewUserUpdateBulk(c.config, builders)
+}
+
 // DeleteOneID returns a delete builder for the given id.
 func (c *UserClient) DeleteOneID(id int64) *UserDeleteOne {
 	mutation := NewUserMutation(c.config, runtime.OpDeleteOne)
@@ -6,6 +6,7 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"time"
 
 	velox "github.com/syssam/velox"
 	sql "github.com/syssam/velox/dialect/sql"
@@ -273,6 +274,7 @@ type UserQuerier interface {
 	Offset(n int) UserQuerier
 	Order(o ...func(*sql.Selector)) UserQuerier
 	Unique(unique bool) UserQuerier
+	Timeout(d time.Duration) UserQuerier
 	WithPosts(opts ...func(PostQuerier)) UserQuerier
 	Select(fields ...string) UserSelector
 	Modify(modifiers ...func(*sql.Selector)) UserQuerier
@@ -5,6 +5,7 @@ package ent
 import (
 	"context"
 	"fmt"
+	"time"
 
 	velox "github.com/syssam/velox"
 	dialect "github.com/syssam/velox/dialect"
@@ -141,6 +142,13 @@ func (q *UserQuery) Unique(unique bool) entity.UserQuerier {
 	return q
 }
 
+// Timeout limits the execution time of every statement of the query,
+// including the statements that load its edges. See sql.WithTimeout.
+func (q *UserQuery) Timeout(d time.Duration) entity.UserQuerier {
+	q.ctx.Timeout = d
+	return q
+}
+
 // Order specifies how the records should be ordered.
 func (q *UserQuery) Order(o ...func(*sql.Selector)) entity.UserQuerier {
 	q.order = append(q.order, o...)
@@ -348,7 +356,8 @@ func (q *UserQuery) ExistX(ctx context.Context) bool {
 
 // SQL returns the SQL query string and arguments for debugging.
 // It runs prepareQuery (privacy traversers) and builds the selector,
-// but does not execute the query.
+// but does not execute the query. The arguments of sensitive columns
+// are redacted, see dialect.RedactArgs.
 func (q *UserQuery) SQL(ctx context.Context) (string, []any, error) {
 	if err := q.prepareQuery(ctx); err != nil {
 		return "", nil, err
@@ -358,11 +367,12 @@ func (q *UserQuery) SQL(ctx context.Context) (string, []any, error) {
 		return "", nil, err
 	}
 	query, args := selector.Query()
-	return query, args, nil
+	return query, dialect.RedactArgs(query, args), nil
 }
 
 // Explain returns the query's execution plan without executing it.
 // Includes the SQL, arguments, planned edge loads, and active interceptors.
+// The arguments of sensitive columns are redacted, see dialect.RedactArgs.
 func (q *UserQuery) Explain(ctx context.Context) (*runtime.QueryPlan, error) {
 	if err := q.prepareQuery(ctx); err != nil {
 		return nil, err
@@ -373,7 +383,7 @@ func (q *UserQuery) Explain(ctx context.Context) (*runtime.QueryPlan, error) {
 	}
 	query, args := selector.Query()
 	plan := &runtime.QueryPlan{
-		Args: args,
+		Args: dialect.RedactArgs(query, args),
 		SQL:  query,
 	}
 	for _, inter := range q.inters.User {
@@ -384,7 +394,7 @@ func (q *UserQuery) Explain(ctx context.Context) (*runtime.QueryPlan, error) {
 		if eErr == nil {
 			eq, ea := eSel.Query()
 			plan.Edges = append(plan.Edges, runtime.EdgePlan{
-				Args: ea,
+				Args: dialect.RedactArgs(eq, ea),
 				Name: "posts",
 				SQL:  eq,
 			})
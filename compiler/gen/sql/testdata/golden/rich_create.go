@@ -363,6 +363,7 @@ func (_cb *ArticleCreateBulk) saveChunk(ctx context.Context, builders []*Article
 	if len(builders) == 0 {
 		return []*entity.Article{}, nil
 	}
+	ctx = velox.NewMutationContext(ctx, builders[0].mutation)
 	specs := make([]*sqlgraph.CreateSpec, len(builders))
 	nodes := make([]*entity.Article, len(builders))
 	mutators := make([]runtime.Mutator, len(builders))
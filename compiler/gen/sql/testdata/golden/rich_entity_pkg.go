@@ -288,6 +288,7 @@ type ArticleQuerier interface {
 	Offset(n int) ArticleQuerier
 	Order(o ...func(*sql.Selector)) ArticleQuerier
 	Unique(unique bool) ArticleQuerier
+	Timeout(d time.Duration) ArticleQuerier
 	WithAuthor(opts ...func(AuthorQuerier)) ArticleQuerier
 	Select(fields ...string) ArticleSelector
 	Modify(modifiers ...func(*sql.Selector)) ArticleQuerier
@@ -9,6 +9,7 @@ import (
 
 	velox "github.com/syssam/velox"
 	sql "github.com/syssam/velox/dialect/sql"
+	sqljson "github.com/syssam/velox/dialect/sql/sqljson"
 	runtime "github.com/syssam/velox/runtime"
 	entity "github.com/test/project/ent/entity"
 	predicate "github.com/test/project/ent/predicate"
@@ -37,6 +38,7 @@ type ArticleMutation struct {
 }
 
 var _ velox.Mutation = (*ArticleMutation)(nil)
+var _ velox.PatchMutation = (*ArticleMutation)(nil)
 
 // articleOption allows management of the mutation configuration using functional options.
 type articleOption func(*ArticleMutation)
@@ -433,6 +435,34 @@ func (m *ArticleMutation) FieldCleared(name string) bool {
 	return ok
 }
 
+// PatchedFields returns all JSON fields that were appended to, or set at a path, during this mutation.
+func (m *ArticleMutation) PatchedFields() []string {
+	var fields []string
+	for _, name := range []string{"tags"} {
+		if m.fieldPatched(name) {
+			fields = append(fields, name)
+		}
+	}
+	return fields
+}
+
+// AppendedField returns the values appended to a JSON field in this mutation.
+func (m *ArticleMutation) AppendedField(name string) (runtime.Value, bool) {
+	v, ok := m.appends[name]
+	return v, ok
+}
+
+// FieldPaths returns the values set at the paths of a JSON field in this mutation.
+func (m *ArticleMutation) FieldPaths(name string) []sqljson.PathValue {
+	return nil
+}
+
+// fieldPatched reports if the JSON field was appended to, or set at a path.
+func (m *ArticleMutation) fieldPatched(name string) bool {
+	_, ok := m.appends[name]
+	return ok
+}
+
 // ClearField clears the value of the field with the given name. It returns an error if the field is not defined in the schema or is not nullable.
 func (m *ArticleMutation) ClearField(name string) error {
 	switch name {
@@ -5,6 +5,7 @@ package ent
 import (
 	"context"
 	"fmt"
+	"time"
 
 	velox "github.com/syssam/velox"
 	dialect "github.com/syssam/velox/dialect"
@@ -142,6 +143,13 @@ func (q *ArticleQuery) Unique(unique bool) entity.ArticleQuerier {
 	return q
 }
 
+// Timeout limits the execution time of every statement of the query,
+// including the statements that load its edges. See sql.WithTimeout.
+func (q *ArticleQuery) Timeout(d time.Duration) entity.ArticleQuerier {
+	q.ctx.Timeout = d
+	return q
+}
+
 // Order specifies how the records should be ordered.
 func (q *ArticleQuery) Order(o ...func(*sql.Selector)) entity.ArticleQuerier {
 	q.order = append(q.order, o...)
@@ -349,7 +357,8 @@ func (q *ArticleQuery) ExistX(ctx context.Context) bool {
 
 // SQL returns the SQL query string and arguments for debugging.
 // It runs prepareQuery (privacy traversers) and builds the selector,
-// but does not execute the query.
+// but does not execute the query. The arguments of sensitive columns
+// are redacted, see dialect.RedactArgs.
 func (q *ArticleQuery) SQL(ctx context.Context) (string, []any, error) {
 	if err := q.prepareQuery(ctx); err != nil {
 		return "", nil, err
@@ -359,11 +368,12 @@ func (q *ArticleQuery) SQL(ctx context.Context) (string, []any, error) {
 		return "", nil, err
 	}
 	query, args := selector.Query()
-	return query, args, nil
+	return query, dialect.RedactArgs(query, args), nil
 }
 
 // Explain returns the query's execution plan without executing it.
 // Includes the SQL, arguments, planned edge loads, and active interceptors.
+// The arguments of sensitive columns are redacted, see dialect.RedactArgs.
 func (q *ArticleQuery) Explain(ctx context.Context) (*runtime.QueryPlan, error) {
 	if err := q.prepareQuery(ctx); err != nil {
 		return nil, err
@@ -374,7 +384,7 @@ func (q *ArticleQuery) Explain(ctx context.Context) (*runtime.QueryPlan, error)
 	}
 	query, args := selector.Query()
 	plan := &runtime.QueryPlan{
-		Args: args,
+		Args: dialect.RedactArgs(query, args),
 		SQL:  query,
 	}
 	for _, inter := range q.inters.Article {
@@ -385,7 +395,7 @@ func (q *ArticleQuery) Explain(ctx context.Context) (*runtime.QueryPlan, error)
 		if eErr == nil {
 			eq, ea := eSel.Query()
 			plan.Edges = append(plan.Edges, runtime.EdgePlan{
-				Args: ea,
+				Args: dialect.RedactArgs(eq, ea),
 				Name: "author",
 				SQL:  eq,
 			})
@@ -687,6 +687,305 @@ func (_u *ArticleUpdateOne) ExecX(ctx context.Context) {
 	}
 }
 
+// ArticleUpdateBulk is the builder for updating many Article entities in bulk,
+// each with its own values.
+type ArticleUpdateBulk struct {
+	config    runtime.Config
+	err       error
+	builders  []*ArticleUpdateOne
+	batchSize int
+}
+
+// NewArticleUpdateBulk creates a new ArticleUpdateBulk builder.
+func NewArticleUpdateBulk(c runtime.Config, builders []*ArticleUpdateOne) *ArticleUpdateBulk {
+	return &ArticleUpdateBulk{
+		builders: builders,
+		config:   c,
+	}
+}
+
+// BatchSize sets the number of rows updated per chunk. A value of 0
+// (default) means sqlgraph.DefaultBatchUpdateSize. Each chunk runs its
+// own mutator chain and transaction; wrap the call in client.Tx(ctx)
+// if you need all-or-nothing semantics across chunks.
+func (_ub *ArticleUpdateBulk) BatchSize(n int) *ArticleUpdateBulk {
+	_ub.batchSize = n
+	return _ub
+}
+
+// Save updates the Article entities in the database and returns them in the
+// order of the builders. Builders that set the same fields are updated with
+// one statement per chunk; see sqlgraph.BatchUpdate for the SQL of each
+// dialect. A mid-loop error returns the rows updated so far together with
+// the error.
+func (_ub *ArticleUpdateBulk) Save(ctx context.Context) ([]*entity.Article, error) {
+	if _ub.err != nil {
+		return nil, _ub.err
+	}
+	size := _ub.batchSize
+	if size <= 0 {
+		size = sqlgraph.DefaultBatchUpdateSize
+	}
+	nodes := make([]*entity.Article, 0, len(_ub.builders))
+	for start := 0; start < len(_ub.builders); start += size {
+		end := min(start+size, len(_ub.builders))
+		chunk, err := _ub.saveChunk(ctx, _ub.builders[start:end])
+		nodes = append(nodes, chunk...)
+		if err != nil {
+			return nodes, err
+		}
+	}
+	return nodes, nil
+}
+
+// saveChunk runs one mutator chain over a sub-slice of the builders.
+func (_ub *ArticleUpdateBulk) saveChunk(ctx context.Context, builders []*ArticleUpdateOne) ([]*entity.Article, error) {
+	for _, b := range builders {
+		b.mutation.oldValue = func(ctx context.Context) (*entity.Article, error) {
+			id, ok := b.mutation.ID()
+			if !ok {
+				return nil, errors.New("velox: missing ID for OldField")
+			}
+			build := func(_ context.Context) (*sql.Selector, error) {
+				s := sql.Select(article.Columns...).From(sql.Table(article.Table))
+				s.SetDialect(b.config.Driver.Dialect())
+				s.Where(sql.EQ(s.C(article.FieldID), id))
+				s.Limit(1)
+				return s, nil
+			}
+			_old, err := runtime.ScanFirst[entity.Article, *entity.Article](ctx, b.config.Driver, build, "Article")
+			if err != nil {
+				return nil, err
+			}
+			_old.SetConfig(b.config)
+			return _old, nil
+		}
+		if err := b.defaults(); err != nil {
+			return nil, err
+		}
+	}
+	specs := make([]*sqlgraph.UpdateSpec, len(builders))
+	nodes := make([]*entity.Article, len(builders))
+	mutators := make([]runtime.Mutator, len(builders))
+	for i := range builders {
+		func(i int, root context.Context) {
+			builder := builders[i]
+			var mut runtime.Mutator = runtime.MutateFunc(func(ctx context.Context, m runtime.Mutation) (runtime.Value, error) {
+				mutation, ok := m.(*ArticleMutation)
+				if !ok {
+					return nil, fmt.Errorf("velox: unexpected mutation type %T", m)
+				}
+				builder.mutation = mutation
+				if err := builder.check(); err != nil {
+					return nil, err
+				}
+				id, ok := builder.mutation.ID()
+				if !ok {
+					return nil, errors.New("velox: missing ID for UpdateOne")
+				}
+				spec := sqlgraph.NewUpdateSpec(article.Table, article.Columns, &sqlgraph.FieldSpec{
+					Column: article.FieldID,
+					Type:   articleIDFieldType,
+				})
+				if len(builder.selectFields) == 0 || slices.Contains(builder.selectFields, "title") {
+					if builder.mutation._title != nil {
+						spec.SetField("title", field.TypeString, *builder.mutation._title)
+					}
+				}
+				if len(builder.selectFields) == 0 || slices.Contains(builder.selectFields, "content") {
+					if builder.mutation._content != nil {
+						spec.SetField("content", field.TypeString, *builder.mutation._content)
+					}
+				}
+				if len(builder.selectFields) == 0 || slices.Contains(builder.selectFields, "created_at") {
+					if builder.mutation._created_at != nil {
+						spec.SetField("created_at", field.TypeTime, *builder.mutation._created_at)
+					}
+				}
+				if len(builder.selectFields) == 0 || slices.Contains(builder.selectFields, "updated_at") {
+					if builder.mutation._updated_at != nil {
+						spec.SetField("updated_at", field.TypeTime, *builder.mutation._updated_at)
+					}
+				}
+				if len(builder.selectFields) == 0 || slices.Contains(builder.selectFields, "tags") {
+					if builder.mutation._tags != nil {
+						spec.SetField("tags", field.TypeJSON, *builder.mutation._tags)
+					}
+				}
+				if len(builder.selectFields) == 0 || slices.Contains(builder.selectFields, "status") {
+					if builder.mutation._status != nil {
+						spec.SetField("status", field.TypeEnum, *builder.mutation._status)
+					}
+				}
+				if len(builder.selectFields) == 0 || slices.Contains(builder.selectFields, "content") {
+					if _, ok := builder.mutation.clearedFields["content"]; ok {
+						spec.ClearField("content", field.TypeString)
+					}
+				}
+				spec.Node.ID.Value = id
+				ps := builder.mutation.PredicatesFuncs()
+				if len(ps) > 0 {
+					spec.Predicate = func(s *sql.Selector) {
+						s.Where(sql.EQ(s.C(article.FieldID), id))
+						for i := range ps {
+							ps[i](s)
+						}
+					}
+				}
+				for col, val := range builder.mutation.appends {
+					colCopy := col
+					valCopy := val
+					d := builder.config.Driver.Dialect()
+					spec.AddModifier(func(u *sql.UpdateBuilder) {
+						appendJSON, err := json.Marshal(valCopy)
+						if err != nil {
+							u.AddError(fmt.Errorf("marshal append value for %q: %w", colCopy, err))
+							return
+						}
+						if string(appendJSON) == "null" {
+							return
+						}
+						switch d {
+						case dialect.MySQL:
+							u.Set(colCopy, sql.ExprFunc(func(b *sql.Builder) {
+								b.WriteString(fmt.Sprintf("JSON_MERGE_PRESERVE(IF(%[1]s IS NULL OR JSON_TYPE(%[1]s) = 'NULL', JSON_ARRAY(), %[1]s), ", colCopy))
+								b.Arg(string(appendJSON))
+								b.WriteString(")")
+							}))
+						case dialect.SQLite:
+							u.Set(colCopy, sql.ExprFunc(func(b *sql.Builder) {
+								b.WriteString(fmt.Sprintf("(SELECT json_group_array(value) FROM (SELECT value FROM json_each(CASE WHEN json_type(CAST(COALESCE(%[1]s, '[]') AS TEXT)) = 'null' THEN '[]' ELSE CAST(COALESCE(%[1]s, '[]') AS TEXT) END) UNION ALL SELECT value FROM json_each(", colCopy))
+								b.Arg(string(appendJSON))
+								b.WriteString(")))")
+							}))
+						default:
+							u.Set(colCopy, sql.ExprFunc(func(b *sql.Builder) {
+								b.WriteString(fmt.Sprintf("COALESCE(NULLIF(%s, 'null'::jsonb), '[]') || ", colCopy))
+								b.Arg(string(appendJSON))
+							}))
+						}
+					})
+				}
+				if builder.mutation.AuthorCleared() {
+					edge := &sqlgraph.EdgeSpec{
+						Bidi:    false,
+						Columns: []string{"author_id"},
+						Inverse: true,
+						Rel:     sqlgraph.M2O,
+						Table:   "articles",
+						Target: &sqlgraph.EdgeTarget{IDSpec: &sqlgraph.FieldSpec{
+							Column: "id",
+							Type:   field.TypeInt64,
+						}},
+					}
+					spec.Edges.Clear = append(spec.Edges.Clear, edge)
+				}
+				if nodes := builder.mutation.AuthorIDs(); len(nodes) > 0 {
+					edge := &sqlgraph.EdgeSpec{
+						Bidi:    false,
+						Columns: []string{"author_id"},
+						Inverse: true,
+						Rel:     sqlgraph.M2O,
+						Table:   "articles",
+						Target: &sqlgraph.EdgeTarget{IDSpec: &sqlgraph.FieldSpec{
+							Column: "id",
+							Type:   field.TypeInt64,
+						}},
+					}
+					for _, k := range nodes {
+						edge.Target.Nodes = append(edge.Target.Nodes, k)
+					}
+					spec.Edges.Add = append(spec.Edges.Add, edge)
+				}
+				if len(builder.modifiers) > 0 {
+					spec.AddModifiers(builder.modifiers...)
+				}
+				specs[i] = spec
+				var err error
+				if i < len(mutators)-1 {
+					_, err = mutators[i+1].Mutate(root, builders[i+1].mutation)
+				} else {
+					err = _ub.sqlSave(ctx, builders, specs, nodes)
+				}
+				if err != nil {
+					return nil, err
+				}
+				return nodes[i], nil
+			})
+			for j := len(builder.hooks) - 1; j >= 0; j-- {
+				mut = builder.hooks[j](mut)
+			}
+			mutators[i] = mut
+		}(i, ctx)
+	}
+	if len(mutators) > 0 {
+		if _, err := mutators[0].Mutate(ctx, builders[0].mutation); err != nil {
+			return nil, err
+		}
+	}
+	return nodes, nil
+}
+
+// sqlSave updates the rows of a chunk and reads the updated Article entities
+// back into nodes, in the order of the builders.
+func (_ub *ArticleUpdateBulk) sqlSave(ctx context.Context, builders []*ArticleUpdateOne, specs []*sqlgraph.UpdateSpec, nodes []*entity.Article) error {
+	if err := sqlgraph.BatchUpdate(ctx, _ub.config.Driver, &sqlgraph.BatchUpdateSpec{Nodes: specs}); err != nil {
+		if _, ok := err.(*sqlgraph.NotFoundError); ok {
+			return velox.NewNotFoundError("Article")
+		}
+		return runtime.MayWrapConstraintError(err)
+	}
+	ids := make([]any, len(builders))
+	for i, b := range builders {
+		ids[i] = *b.mutation.id
+	}
+	build := func(_ context.Context) (*sql.Selector, error) {
+		s := sql.Select(article.Columns...).From(sql.Table(article.Table))
+		s.SetDialect(_ub.config.Driver.Dialect())
+		s.Where(sql.In(s.C(article.FieldID), ids...))
+		return s, nil
+	}
+	updated, err := runtime.ScanAll[entity.Article, *entity.Article](ctx, _ub.config.Driver, build)
+	if err != nil {
+		return err
+	}
+	byID := make(map[int64]*entity.Article, len(updated))
+	for _, n := range updated {
+		byID[n.ID] = n
+	}
+	for i, b := range builders {
+		node, ok := byID[*b.mutation.id]
+		if !ok {
+			return velox.NewNotFoundError("Article")
+		}
+		node.SetConfig(_ub.config)
+		nodes[i] = node
+	}
+	return nil
+}
+
+// SaveX is like Save, but panics if an error occurs.
+func (_ub *ArticleUpdateBulk) SaveX(ctx context.Context) []*entity.Article {
+	v, err := _ub.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// Exec executes the query.
+func (_ub *ArticleUpdateBulk) Exec(ctx context.Context) error {
+	_, err := _ub.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (_ub *ArticleUpdateBulk) ExecX(ctx context.Context) {
+	if err := _ub.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
+
 // articleWrapperUpdateDefaults applies update default values shared by ArticleUpdate and ArticleUpdateOne.
 func articleWrapperUpdateDefaults(m *ArticleMutation, skipDefaults bool, skipDefaultFields map[string]struct{}) {
 	if skipDefaults {
@@ -5,6 +5,7 @@ package ent
 import (
 	"context"
 	"errors"
+	"fmt"
 	"slices"
 
 	velox "github.com/syssam/velox"
@@ -628,3 +629,279 @@ func (_u *UserUpdateOne) ExecX(ctx context.Context) {
 		panic(err)
 	}
 }
+
+// UserUpdateBulk is the builder for updating many User entities in bulk,
+// each with its own values.
+type UserUpdateBulk struct {
+	config    runtime.Config
+	err       error
+	builders  []*UserUpdateOne
+	batchSize int
+}
+
+// NewUserUpdateBulk creates a new UserUpdateBulk builder.
+func NewUserUpdateBulk(c runtime.Config, builders []*UserUpdateOne) *UserUpdateBulk {
+	return &UserUpdateBulk{
+		builders: builders,
+		config:   c,
+	}
+}
+
+// BatchSize sets the number of rows updated per chunk. A value of 0
+// (default) means sqlgraph.DefaultBatchUpdateSize. Each chunk runs its
+// own mutator chain and transaction; wrap the call in client.Tx(ctx)
+// if you need all-or-nothing semantics across chunks.
+func (_ub *UserUpdateBulk) BatchSize(n int) *UserUpdateBulk {
+	_ub.batchSize = n
+	return _ub
+}
+
+// Save updates the User entities in the database and returns them in the
+// order of the builders. Builders that set the same fields are updated with
+// one statement per chunk; see sqlgraph.BatchUpdate for the SQL of each
+// dialect. A mid-loop error returns the rows updated so far together with
+// the error.
+func (_ub *UserUpdateBulk) Save(ctx context.Context) ([]*entity.User, error) {
+	if _ub.err != nil {
+		return nil, _ub.err
+	}
+	size := _ub.batchSize
+	if size <= 0 {
+		size = sqlgraph.DefaultBatchUpdateSize
+	}
+	nodes := make([]*entity.User, 0, len(_ub.builders))
+	for start := 0; start < len(_ub.builders); start += size {
+		end := min(start+size, len(_ub.builders))
+		chunk, err := _ub.saveChunk(ctx, _ub.builders[start:end])
+		nodes = append(nodes, chunk...)
+		if err != nil {
+			return nodes, err
+		}
+	}
+	return nodes, nil
+}
+
+// saveChunk runs one mutator chain over a sub-slice of the builders.
+func (_ub *UserUpdateBulk) saveChunk(ctx context.Context, builders []*UserUpdateOne) ([]*entity.User, error) {
+	for _, b := range builders {
+		b.mutation.oldValue = func(ctx context.Context) (*entity.User, error) {
+			id, ok := b.mutation.ID()
+			if !ok {
+				return nil, errors.New("velox: missing ID for OldField")
+			}
+			build := func(_ context.Context) (*sql.Selector, error) {
+				s := sql.Select(user.Columns...).From(sql.Table(user.Table))
+				s.SetDialect(b.config.Driver.Dialect())
+				s.Where(sql.EQ(s.C(user.FieldID), id))
+				s.Limit(1)
+				return s, nil
+			}
+			_old, err := runtime.ScanFirst[entity.User, *entity.User](ctx, b.config.Driver, build, "User")
+			if err != nil {
+				return nil, err
+			}
+			_old.SetConfig(b.config)
+			return _old, nil
+		}
+	}
+	specs := make([]*sqlgraph.UpdateSpec, len(builders))
+	nodes := make([]*entity.User, len(builders))
+	mutators := make([]runtime.Mutator, len(builders))
+	for i := range builders {
+		func(i int, root context.Context) {
+			builder := builders[i]
+			var mut runtime.Mutator = runtime.MutateFunc(func(ctx context.Context, m runtime.Mutation) (runtime.Value, error) {
+				mutation, ok := m.(*UserMutation)
+				if !ok {
+					return nil, fmt.Errorf("velox: unexpected mutation type %T", m)
+				}
+				builder.mutation = mutation
+				id, ok := builder.mutation.ID()
+				if !ok {
+					return nil, errors.New("velox: missing ID for UpdateOne")
+				}
+				spec := sqlgraph.NewUpdateSpec(user.Table, user.Columns, &sqlgraph.FieldSpec{
+					Column: user.FieldID,
+					Type:   userIDFieldType,
+				})
+				if len(builder.selectFields) == 0 || slices.Contains(builder.selectFields, "name") {
+					if builder.mutation._name != nil {
+						spec.SetField("name", field.TypeString, *builder.mutation._name)
+					}
+				}
+				if len(builder.selectFields) == 0 || slices.Contains(builder.selectFields, "email") {
+					if builder.mutation._email != nil {
+						spec.SetField("email", field.TypeString, *builder.mutation._email)
+					}
+				}
+				if len(builder.selectFields) == 0 || slices.Contains(builder.selectFields, "age") {
+					if builder.mutation._age != nil {
+						spec.SetField("age", field.TypeInt, *builder.mutation._age)
+					}
+				}
+				if len(builder.selectFields) == 0 || slices.Contains(builder.selectFields, "bio") {
+					if builder.mutation._bio != nil {
+						spec.SetField("bio", field.TypeString, *builder.mutation._bio)
+					}
+				}
+				if len(builder.selectFields) == 0 || slices.Contains(builder.selectFields, "nickname") {
+					if builder.mutation._nickname != nil {
+						spec.SetField("nickname", field.TypeString, *builder.mutation._nickname)
+					}
+				}
+				if len(builder.selectFields) == 0 || slices.Contains(builder.selectFields, "age") {
+					if builder.mutation._addage != nil {
+						spec.AddField("age", field.TypeInt, *builder.mutation._addage)
+					}
+				}
+				if len(builder.selectFields) == 0 || slices.Contains(builder.selectFields, "nickname") {
+					if _, ok := builder.mutation.clearedFields["nickname"]; ok {
+						spec.ClearField("nickname", field.TypeString)
+					}
+				}
+				spec.Node.ID.Value = id
+				ps := builder.mutation.PredicatesFuncs()
+				if len(ps) > 0 {
+					spec.Predicate = func(s *sql.Selector) {
+						s.Where(sql.EQ(s.C(user.FieldID), id))
+						for i := range ps {
+							ps[i](s)
+						}
+					}
+				}
+				if builder.mutation.PostsCleared() {
+					edge := &sqlgraph.EdgeSpec{
+						Bidi:    false,
+						Columns: []string{"user_id"},
+						Inverse: false,
+						Rel:     sqlgraph.O2M,
+						Table:   "posts",
+						Target: &sqlgraph.EdgeTarget{IDSpec: &sqlgraph.FieldSpec{
+							Column: "id",
+							Type:   field.TypeInt64,
+						}},
+					}
+					spec.Edges.Clear = append(spec.Edges.Clear, edge)
+				}
+				if nodes := builder.mutation.RemovedPostsIDs(); len(nodes) > 0 && !builder.mutation.PostsCleared() {
+					edge := &sqlgraph.EdgeSpec{
+						Bidi:    false,
+						Columns: []string{"user_id"},
+						Inverse: false,
+						Rel:     sqlgraph.O2M,
+						Table:   "posts",
+						Target: &sqlgraph.EdgeTarget{IDSpec: &sqlgraph.FieldSpec{
+							Column: "id",
+							Type:   field.TypeInt64,
+						}},
+					}
+					for _, k := range nodes {
+						edge.Target.Nodes = append(edge.Target.Nodes, k)
+					}
+					spec.Edges.Clear = append(spec.Edges.Clear, edge)
+				}
+				if nodes := builder.mutation.PostsIDs(); len(nodes) > 0 {
+					edge := &sqlgraph.EdgeSpec{
+						Bidi:    false,
+						Columns: []string{"user_id"},
+						Inverse: false,
+						Rel:     sqlgraph.O2M,
+						Table:   "posts",
+						Target: &sqlgraph.EdgeTarget{IDSpec: &sqlgraph.FieldSpec{
+							Column: "id",
+							Type:   field.TypeInt64,
+						}},
+					}
+					for _, k := range nodes {
+						edge.Target.Nodes = append(edge.Target.Nodes, k)
+					}
+					spec.Edges.Add = append(spec.Edges.Add, edge)
+				}
+				if len(builder.modifiers) > 0 {
+					spec.AddModifiers(builder.modifiers...)
+				}
+				specs[i] = spec
+				var err error
+				if i < len(mutators)-1 {
+					_, err = mutators[i+1].Mutate(root, builders[i+1].mutation)
+				} else {
+					err = _ub.sqlSave(ctx, builders, specs, nodes)
+				}
+				if err != nil {
+					return nil, err
+				}
+				return nodes[i], nil
+			})
+			for j := len(builder.hooks) - 1; j >= 0; j-- {
+				mut = builder.hooks[j](mut)
+			}
+			mutators[i] = mut
+		}(i, ctx)
+	}
+	if len(mutators) > 0 {
+		if _, err := mutators[0].Mutate(ctx, builders[0].mutation); err != nil {
+			return nil, err
+		}
+	}
+	return nodes, nil
+}
+
+// sqlSave updates the rows of a chunk and reads the updated User entities
+// back into nodes, in the order of the builders.
+func (_ub *UserUpdateBulk) sqlSave(ctx context.Context, builders []*UserUpdateOne, specs []*sqlgraph.UpdateSpec, nodes []*entity.User) error {
+	if err := sqlgraph.BatchUpdate(ctx, _ub.config.Driver, &sqlgraph.BatchUpdateSpec{Nodes: specs}); err != nil {
+		if _, ok := err.(*sqlgraph.NotFoundError); ok {
+			return velox.NewNotFoundError("User")
+		}
+		return runtime.MayWrapConstraintError(err)
+	}
+	ids := make([]any, len(builders))
+	for i, b := range builders {
+		ids[i] = *b.mutation.id
+	}
+	build := func(_ context.Context) (*sql.Selector, error) {
+		s := sql.Select(user.Columns...).From(sql.Table(user.Table))
+		s.SetDialect(_ub.config.Driver.Dialect())
+		s.Where(sql.In(s.C(user.FieldID), ids...))
+		return s, nil
+	}
+	updated, err := runtime.ScanAll[entity.User, *entity.User](ctx, _ub.config.Driver, build)
+	if err != nil {
+		return err
+	}
+	byID := make(map[int64]*entity.User, len(updated))
+	for _, n := range updated {
+		byID[n.ID] = n
+	}
+	for i, b := range builders {
+		node, ok := byID[*b.mutation.id]
+		if !ok {
+			return velox.NewNotFoundError("User")
+		}
+		node.SetConfig(_ub.config)
+		nodes[i] = node
+	}
+	return nil
+}
+
+// SaveX is like Save, but panics if an error occurs.
+func (_ub *UserUpdateBulk) SaveX(ctx context.Context) []*entity.User {
+	v, err := _ub.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// Exec executes the query.
+func (_ub *UserUpdateBulk) Exec(ctx context.Context) error {
+	_, err := _ub.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (_ub *UserUpdateBulk) ExecX(ctx context.Context) {
+	if err := _ub.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
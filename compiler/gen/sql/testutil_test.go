@@ -5,6 +5,7 @@ import (
 
 	"github.com/syssam/velox/compiler/gen"
 	"github.com/syssam/velox/compiler/load"
+	"github.com/syssam/velox/schema/edge"
 	"github.com/syssam/velox/schema/field"
 )
 
@@ -229,3 +230,47 @@ func createFieldWithValidators(name string, typ field.Type, count int) *gen.Fiel
 		Validators: count,
 	}
 }
+
+// newGraphHelper returns a mockHelper whose graph is built from the given
+// schemas with the sql storage, in the order of the schemas.
+func newGraphHelper(t testing.TB, schemas ...*load.Schema) *mockHelper {
+	t.Helper()
+	storage, err := gen.NewStorage("sql")
+	if err != nil {
+		t.Fatal(err)
+	}
+	h := newMockHelper()
+	g, err := gen.NewGraph(&gen.Config{Package: h.graph.Package, Target: h.graph.Target, Storage: storage}, schemas...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	h.graph = g
+	return h
+}
+
+// newCounterCacheHelper returns a mockHelper whose graph holds a User type
+// with a "posts_count" counter cache (edge.CounterCache) of its "posts" edge.
+func newCounterCacheHelper(t testing.TB) (*mockHelper, *gen.Type, *gen.Type) {
+	t.Helper()
+	h := newGraphHelper(t,
+		&load.Schema{Name: "User", Edges: []*load.Edge{{
+			Name:        "posts",
+			Type:        "Post",
+			Annotations: map[string]any{edge.Annotation{}.Name(): edge.CounterCache("posts_count")},
+		}}},
+		&load.Schema{Name: "Post", Edges: []*load.Edge{{Name: "author", Type: "User", Inverse: true, RefName: "posts", Unique: true}}},
+	)
+	return h, h.graph.Nodes[0], h.graph.Nodes[1]
+}
+
+// newOneOfHelper returns a mock helper with a graph built from schemas with a
+// polymorphic edge from Comment to Post or Photo, along with the Comment type.
+func newOneOfHelper(t testing.TB, e *load.Edge) (*mockHelper, *gen.Type) {
+	t.Helper()
+	h := newGraphHelper(t,
+		&load.Schema{Name: "Comment", Edges: []*load.Edge{e}},
+		&load.Schema{Name: "Post"},
+		&load.Schema{Name: "Photo"},
+	)
+	return h, h.graph.Nodes[0]
+}
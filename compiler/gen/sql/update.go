@@ -18,6 +18,7 @@ func genUpdate(h gen.GeneratorHelper, t *gen.Type) (*jen.File, error) { //nolint
 // Generated types:
 //   - {Entity}Update      — bulk update (Save returns int, no wrapping needed)
 //   - {Entity}UpdateOne   — single update (Save returns *Entity, needs wrapping)
+//   - {Entity}UpdateBulk  — many UpdateOne builders batched into one statement per chunk
 func genUpdateInto(h gen.GeneratorHelper, f *jen.File, t *gen.Type) {
 	entityPkg := h.LeafPkgPath(t)
 	// Entity package path for return types (entity.User).
@@ -33,6 +34,7 @@ func genUpdateInto(h gen.GeneratorHelper, f *jen.File, t *gen.Type) {
 
 	genUpdateBulk(h, f, t, entityPkg, mutName, updaterIface)
 	genUpdateOne(h, f, t, entityPkg, entityReturnPkg, mutName, updateOnerIface)
+	genUpdateOneBulk(h, f, t, entityPkg, entityReturnPkg, mutName)
 
 	// Shared defaults function and per-builder wrappers (only when entity has UpdateDefault fields).
 	if t.HasUpdateDefault() {
@@ -103,6 +105,7 @@ func genUpdateBulk(h gen.GeneratorHelper, f *jen.File, t *gen.Type, entityPkg, m
 	// --- Field setters (mutable fields only) ---
 	for _, fd := range t.MutableFields() {
 		genFieldSetter(h, f, updateName, recv, fd, true, "mutation", ifaceReturn)
+		genJSONPathSetters(h, f, t, updateName, recv, fd, "mutation", ifaceReturn)
 	}
 
 	// --- Edge setters ---
@@ -120,6 +123,7 @@ func genUpdateBulk(h gen.GeneratorHelper, f *jen.File, t *gen.Type, entityPkg, m
 		}
 		genEdgeEntitySetter(h, f, updateName, recv, edge, true, ifaceReturn)
 	}
+	genOneOfSetters(h, f, t, updateName, recv, true)
 
 	// --- Mutation ---
 	f.Commentf("Mutation returns the %s.", mutName)
@@ -173,6 +177,7 @@ func genUpdateBulk(h gen.GeneratorHelper, f *jen.File, t *gen.Type, entityPkg, m
 	f.Func().Params(jen.Id(recv).Op("*").Id(updateName)).Id("Save").Params(
 		jen.Id("ctx").Qual("context", "Context"),
 	).Params(jen.Int(), jen.Error()).BlockFunc(func(grp *jen.Group) {
+		genFieldPolicyCheck(h, grp, t, jen.Id(recv).Dot("mutation"), jen.Lit(0))
 		if t.HasUpdateDefault() {
 			grp.If(jen.Id("err").Op(":=").Id(recv).Dot("defaults").Call(), jen.Id("err").Op("!=").Nil()).Block(
 				jen.Return(jen.Lit(0), jen.Id("err")),
@@ -307,6 +312,7 @@ func genUpdateOne(h gen.GeneratorHelper, f *jen.File, t *gen.Type, entityPkg, en
 	// --- Field setters (mutable fields only) ---
 	for _, fd := range t.MutableFields() {
 		genFieldSetter(h, f, updateOneName, recv, fd, true, "mutation", ifaceReturn)
+		genJSONPathSetters(h, f, t, updateOneName, recv, fd, "mutation", ifaceReturn)
 	}
 
 	// --- Edge setters ---
@@ -324,6 +330,7 @@ func genUpdateOne(h gen.GeneratorHelper, f *jen.File, t *gen.Type, entityPkg, en
 		}
 		genEdgeEntitySetter(h, f, updateOneName, recv, edge, true, ifaceReturn)
 	}
+	genOneOfSetters(h, f, t, updateOneName, recv, true)
 
 	// --- Mutation ---
 	f.Commentf("Mutation returns the %s.", mutName)
@@ -336,47 +343,14 @@ func genUpdateOne(h gen.GeneratorHelper, f *jen.File, t *gen.Type, entityPkg, en
 
 	// --- check() for required-edge validation ---
 	genUpdateCheck(f, t, updateOneName, recv)
+	genEntityValidate(h, f, t, updateOneName, recv, true)
 
 	// --- sqlSave (named method — Ent pattern) ---
 	f.Commentf("sqlSave executes the SQL update for a single %s after hooks have run.", t.Name)
 	f.Func().Params(jen.Id(recv).Op("*").Id(updateOneName)).Id("sqlSave").Params(
 		jen.Id("ctx").Qual("context", "Context"),
 	).Params(jen.Op("*").Qual(entityReturnPkg, t.Name), jen.Error()).BlockFunc(func(grp *jen.Group) {
-		// Validate required edges before executing SQL.
-		if hasRequiredUniqueEdge(t) {
-			grp.If(jen.Id("err").Op(":=").Id(recv).Dot("check").Call(), jen.Id("err").Op("!=").Nil()).Block(
-				jen.Return(jen.Nil(), jen.Id("err")),
-			)
-		}
-		// Get ID from mutation.
-		grp.Id("id").Op(",").Id("ok").Op(":=").Id(recv).Dot("mutation").Dot("ID").Call()
-		grp.If(jen.Op("!").Id("ok")).Block(
-			jen.Return(jen.Nil(), jen.Qual("errors", "New").Call(jen.Lit("velox: missing ID for UpdateOne"))),
-		)
-		// Build UpdateSpec from typed mutation fields — selectFields-aware for UpdateOne.
-		genUpdateSpecBuild(h, grp, t, recv, true)
-		// sqlgraph.UpdateNode (used below) builds its WHERE from spec.Node.ID.Value
-		// and runs ensureExists on zero affected rows; set the id so the single-node
-		// path and its existence re-check target the right row.
-		grp.Id("spec").Dot("Node").Dot("ID").Dot("Value").Op("=").Id("id")
-		// Apply the by-ID predicate AND any chained .Where() predicates from the
-		// mutation. Without merging PredicatesFuncs, UpdateOneID(...).Where(...)
-		// silently drops the guard, turning optimistic-lock / status-guard
-		// conditional updates into unconditional ones (a lost-update footgun).
-		grp.Id("ps").Op(":=").Id(recv).Dot("mutation").Dot("PredicatesFuncs").Call()
-		grp.Id("spec").Dot("Predicate").Op("=").Func().Params(
-			jen.Id("s").Op("*").Qual(h.SQLPkg(), "Selector"),
-		).Block(
-			jen.Id("s").Dot("Where").Call(jen.Qual(h.SQLPkg(), "EQ").Call(
-				jen.Id("s").Dot("C").Call(jen.Qual(entityPkg, "FieldID")),
-				jen.Id("id"),
-			)),
-			jen.For(jen.Id("i").Op(":=").Range().Id("ps")).Block(
-				jen.Id("ps").Index(jen.Id("i")).Call(jen.Id("s")),
-			),
-		)
-		// Emit edge operations into spec.Edges.Add / spec.Edges.Clear and apply modifiers.
-		genUpdateEdgesAndModifiers(h, grp, t, recv, true)
+		genUpdateOneSpec(h, grp, t, recv, true)
 		// Single-node update: sqlgraph.UpdateNode runs the UPDATE and, on zero
 		// affected rows, calls ensureExists — re-selecting by id AND the chained
 		// .Where() predicates to tell "no row matched" (→ NotFound) apart from
@@ -440,58 +414,7 @@ func genUpdateOne(h gen.GeneratorHelper, f *jen.File, t *gen.Type, entityPkg, en
 	f.Func().Params(jen.Id(recv).Op("*").Id(updateOneName)).Id("Save").Params(
 		jen.Id("ctx").Qual("context", "Context"),
 	).Params(jen.Op("*").Qual(entityReturnPkg, t.Name), jen.Error()).BlockFunc(func(grp *jen.Group) {
-		// Wire up typed oldValue closure so OldXxx(ctx) methods work in hooks.
-		if len(t.MutableFields()) > 0 {
-			grp.Id(recv).Dot("mutation").Dot("oldValue").Op("=").Func().Params(
-				jen.Id("ctx").Qual("context", "Context"),
-			).Params(jen.Op("*").Qual(entityReturnPkg, t.Name), jen.Error()).BlockFunc(func(loader *jen.Group) {
-				loader.Id("id").Op(",").Id("ok").Op(":=").Id(recv).Dot("mutation").Dot("ID").Call()
-				loader.If(jen.Op("!").Id("ok")).Block(
-					jen.Return(jen.Nil(), jen.Qual("errors", "New").Call(jen.Lit("velox: missing ID for OldField"))),
-				)
-				loader.Id("build").Op(":=").Func().Params(
-					jen.Id("_").Qual("context", "Context"),
-				).Params(
-					jen.Op("*").Qual(h.SQLPkg(), "Selector"), jen.Error(),
-				).Block(
-					jen.Id("s").Op(":=").Qual(h.SQLPkg(), "Select").Call(jen.Qual(entityPkg, "Columns").Op("...")).Dot("From").Call(
-						jen.Qual(h.SQLPkg(), "Table").Call(jen.Qual(entityPkg, "Table")),
-					),
-					jen.Id("s").Dot("SetDialect").Call(jen.Id(recv).Dot("config").Dot("Driver").Dot("Dialect").Call()),
-					jen.Id("s").Dot("Where").Call(jen.Qual(h.SQLPkg(), "EQ").Call(
-						jen.Id("s").Dot("C").Call(jen.Qual(entityPkg, "FieldID")),
-						jen.Id("id"),
-					)),
-					jen.Id("s").Dot("Limit").Call(jen.Lit(1)),
-					jen.Return(jen.Id("s"), jen.Nil()),
-				)
-				loader.List(jen.Id("_old"), jen.Id("err")).Op(":=").Qual(runtimePkg, "ScanFirst").Types(
-					jen.Qual(entityReturnPkg, t.Name), jen.Op("*").Qual(entityReturnPkg, t.Name),
-				).Call(jen.Id("ctx"), jen.Id(recv).Dot("config").Dot("Driver"), jen.Id("build"), jen.Lit(t.Name))
-				loader.If(jen.Id("err").Op("!=").Nil()).Block(
-					jen.Return(jen.Nil(), jen.Id("err")),
-				)
-				// Propagate config onto the loaded old entity so hooks that
-				// traverse edges off it (e.g. old.QueryPosts()) don't panic.
-				loader.Id("_old").Dot(t.SetConfigMethodName()).Call(jen.Id(recv).Dot("config"))
-				loader.Return(jen.Id("_old"), jen.Nil())
-			})
-		}
-		if t.HasUpdateDefault() {
-			grp.If(jen.Id("err").Op(":=").Id(recv).Dot("defaults").Call(), jen.Id("err").Op("!=").Nil()).Block(
-				jen.Return(jen.Nil(), jen.Id("err")),
-			)
-		}
-		// Explicit privacy check — runs before hooks since privacy no longer rides on Hooks[0].
-		if t.NumPolicy() > 0 {
-			grp.If(jen.Id(recv).Dot("policy").Op("!=").Nil()).Block(
-				jen.If(jen.Id("err").Op(":=").Id(recv).Dot("policy").Dot("EvalMutation").Call(
-					jen.Id("ctx"), jen.Id(recv).Dot("mutation"),
-				), jen.Id("err").Op("!=").Nil()).Block(
-					jen.Return(jen.Nil(), jen.Id("err")),
-				),
-			)
-		}
+		genUpdateOnePrepare(h, grp, t, recv)
 		// Collect hooks: client-level (from Use) + schema-level (from codegen init).
 		if t.NumHooks() > 0 {
 			grp.Id("hooks").Op(":=").Id("append").Call(jen.Id(recv).Dot("hooks"), jen.Qual(h.LeafPkgPath(t), "Hooks").Index(jen.Op(":")).Op("..."))
@@ -538,6 +461,386 @@ func genUpdateOne(h gen.GeneratorHelper, f *jen.File, t *gen.Type, entityPkg, en
 	)
 }
 
+// genUpdateOneBulk generates the root-level UserUpdateBulk builder. It runs a
+// list of UpdateOne builders through the same mutator chain as CreateBulk —
+// hooks run per row, and the innermost mutator of the last row hands every
+// spec to a single sqlgraph.BatchUpdate — then reads the rows back by id.
+func genUpdateOneBulk(h gen.GeneratorHelper, f *jen.File, t *gen.Type, entityPkg, entityReturnPkg, mutName string) {
+	bulkName := t.UpdateBulkName()
+	updateOneName := t.UpdateOneName()
+	recv := "_ub"
+	nodesType := jen.Index().Op("*").Qual(entityReturnPkg, t.Name)
+
+	f.Commentf("%s is the builder for updating many %s entities in bulk,", bulkName, t.Name)
+	f.Comment("each with its own values.")
+	f.Type().Id(bulkName).Struct(
+		jen.Id("config").Qual(runtimePkg, "Config"),
+		jen.Id("err").Error(),
+		jen.Id("builders").Index().Op("*").Id(updateOneName),
+		jen.Id("batchSize").Int(),
+	)
+
+	f.Commentf("New%s creates a new %s builder.", bulkName, bulkName)
+	f.Func().Id("New"+bulkName).Params(
+		jen.Id("c").Qual(runtimePkg, "Config"),
+		jen.Id("builders").Index().Op("*").Id(updateOneName),
+	).Op("*").Id(bulkName).Block(
+		jen.Return(jen.Op("&").Id(bulkName).Values(jen.Dict{
+			jen.Id("config"):   jen.Id("c"),
+			jen.Id("builders"): jen.Id("builders"),
+		})),
+	)
+
+	f.Comment("BatchSize sets the number of rows updated per chunk. A value of 0")
+	f.Comment("(default) means sqlgraph.DefaultBatchUpdateSize. Each chunk runs its")
+	f.Comment("own mutator chain and transaction; wrap the call in client.Tx(ctx)")
+	f.Comment("if you need all-or-nothing semantics across chunks.")
+	f.Func().Params(jen.Id(recv).Op("*").Id(bulkName)).Id("BatchSize").Params(
+		jen.Id("n").Int(),
+	).Op("*").Id(bulkName).Block(
+		jen.Id(recv).Dot("batchSize").Op("=").Id("n"),
+		jen.Return(jen.Id(recv)),
+	)
+
+	f.Commentf("Save updates the %s entities in the database and returns them in the", t.Name)
+	f.Comment("order of the builders. Builders that set the same fields are updated with")
+	f.Comment("one statement per chunk; see sqlgraph.BatchUpdate for the SQL of each")
+	f.Comment("dialect. A mid-loop error returns the rows updated so far together with")
+	f.Comment("the error.")
+	f.Func().Params(jen.Id(recv).Op("*").Id(bulkName)).Id("Save").Params(
+		jen.Id("ctx").Qual("context", "Context"),
+	).Params(nodesType.Clone(), jen.Error()).BlockFunc(func(grp *jen.Group) {
+		grp.If(jen.Id(recv).Dot("err").Op("!=").Nil()).Block(
+			jen.Return(jen.Nil(), jen.Id(recv).Dot("err")),
+		)
+		grp.Id("size").Op(":=").Id(recv).Dot("batchSize")
+		grp.If(jen.Id("size").Op("<=").Lit(0)).Block(
+			jen.Id("size").Op("=").Qual(h.SQLGraphPkg(), "DefaultBatchUpdateSize"),
+		)
+		grp.Id("nodes").Op(":=").Make(nodesType.Clone(), jen.Lit(0), jen.Len(jen.Id(recv).Dot("builders")))
+		grp.For(
+			jen.Id("start").Op(":=").Lit(0),
+			jen.Id("start").Op("<").Len(jen.Id(recv).Dot("builders")),
+			jen.Id("start").Op("+=").Id("size"),
+		).Block(
+			jen.Id("end").Op(":=").Min(jen.Id("start").Op("+").Id("size"), jen.Len(jen.Id(recv).Dot("builders"))),
+			jen.List(jen.Id("chunk"), jen.Id("err")).Op(":=").Id(recv).Dot("saveChunk").Call(
+				jen.Id("ctx"),
+				jen.Id(recv).Dot("builders").Index(jen.Id("start"), jen.Id("end")),
+			),
+			jen.Id("nodes").Op("=").Append(jen.Id("nodes"), jen.Id("chunk").Op("...")),
+			jen.If(jen.Id("err").Op("!=").Nil()).Block(
+				jen.Return(jen.Id("nodes"), jen.Id("err")),
+			),
+		)
+		grp.Return(jen.Id("nodes"), jen.Nil())
+	})
+
+	f.Comment("saveChunk runs one mutator chain over a sub-slice of the builders.")
+	f.Func().Params(jen.Id(recv).Op("*").Id(bulkName)).Id("saveChunk").Params(
+		jen.Id("ctx").Qual("context", "Context"),
+		jen.Id("builders").Index().Op("*").Id(updateOneName),
+	).Params(nodesType.Clone(), jen.Error()).BlockFunc(func(grp *jen.Group) {
+		// genUpdateOnePrepare emits nothing for an entity without mutable
+		// fields, defaults or policies; skip the loop to avoid an unused b.
+		if len(t.MutableFields()) > 0 || t.HasUpdateDefault() || t.NumPolicy() > 0 ||
+			h.FeatureEnabled(gen.FeaturePrivacy.Name) && len(t.FieldPolicyFields()) > 0 {
+			grp.For(jen.List(jen.Id("_"), jen.Id("b")).Op(":=").Range().Id("builders")).BlockFunc(func(loop *jen.Group) {
+				genUpdateOnePrepare(h, loop, t, "b")
+			})
+		}
+		grp.Id("specs").Op(":=").Make(
+			jen.Index().Op("*").Qual(h.SQLGraphPkg(), "UpdateSpec"),
+			jen.Len(jen.Id("builders")),
+		)
+		grp.Id("nodes").Op(":=").Make(nodesType.Clone(), jen.Len(jen.Id("builders")))
+		grp.Id("mutators").Op(":=").Make(jen.Index().Qual(runtimePkg, "Mutator"), jen.Len(jen.Id("builders")))
+		grp.For(jen.Id("i").Op(":=").Range().Id("builders")).Block(
+			jen.Func().Params(
+				jen.Id("i").Int(),
+				jen.Id("root").Qual("context", "Context"),
+			).BlockFunc(func(iife *jen.Group) {
+				iife.Id("builder").Op(":=").Id("builders").Index(jen.Id("i"))
+				iife.Var().Id("mut").Qual(runtimePkg, "Mutator").Op("=").Qual(runtimePkg, "MutateFunc").Call(
+					jen.Func().Params(
+						jen.Id("ctx").Qual("context", "Context"),
+						jen.Id("m").Qual(runtimePkg, "Mutation"),
+					).Params(jen.Qual(runtimePkg, "Value"), jen.Error()).BlockFunc(func(inner *jen.Group) {
+						inner.List(jen.Id("mutation"), jen.Id("ok")).Op(":=").Id("m").Assert(jen.Op("*").Id(mutName))
+						inner.If(jen.Op("!").Id("ok")).Block(
+							jen.Return(jen.Nil(), jen.Qual("fmt", "Errorf").Call(jen.Lit("velox: unexpected mutation type %T"), jen.Id("m"))),
+						)
+						inner.Id("builder").Dot("mutation").Op("=").Id("mutation")
+						genUpdateOneSpec(h, inner, t, "builder", false)
+						inner.Id("specs").Index(jen.Id("i")).Op("=").Id("spec")
+						inner.Var().Id("err").Error()
+						inner.If(jen.Id("i").Op("<").Len(jen.Id("mutators")).Op("-").Lit(1)).Block(
+							jen.List(jen.Id("_"), jen.Id("err")).Op("=").
+								Id("mutators").Index(jen.Id("i").Op("+").Lit(1)).Dot("Mutate").
+								Call(
+									jen.Id("root"),
+									jen.Id("builders").Index(jen.Id("i").Op("+").Lit(1)).Dot("mutation"),
+								),
+						).Else().Block(
+							jen.Id("err").Op("=").Id(recv).Dot("sqlSave").Call(
+								jen.Id("ctx"), jen.Id("builders"), jen.Id("specs"), jen.Id("nodes"),
+							),
+						)
+						inner.If(jen.Id("err").Op("!=").Nil()).Block(
+							jen.Return(jen.Nil(), jen.Id("err")),
+						)
+						inner.Return(jen.Id("nodes").Index(jen.Id("i")), jen.Nil())
+					}),
+				)
+				// Same hook order as UpdateOne.Save: client hooks, then schema hooks.
+				hooks := jen.Id("builder").Dot("hooks")
+				if t.NumHooks() > 0 {
+					iife.Id("hooks").Op(":=").Append(
+						jen.Id("builder").Dot("hooks"),
+						jen.Qual(h.LeafPkgPath(t), "Hooks").Index(jen.Op(":")).Op("..."),
+					)
+					hooks = jen.Id("hooks")
+				}
+				iife.For(
+					jen.Id("j").Op(":=").Len(hooks.Clone()).Op("-").Lit(1),
+					jen.Id("j").Op(">=").Lit(0),
+					jen.Id("j").Op("--"),
+				).Block(
+					jen.Id("mut").Op("=").Add(hooks.Clone()).Index(jen.Id("j")).Call(jen.Id("mut")),
+				)
+				iife.Id("mutators").Index(jen.Id("i")).Op("=").Id("mut")
+			}).Call(jen.Id("i"), jen.Id("ctx")),
+		)
+		grp.If(jen.Len(jen.Id("mutators")).Op(">").Lit(0)).Block(
+			jen.If(
+				jen.List(jen.Id("_"), jen.Id("err")).Op(":=").
+					Id("mutators").Index(jen.Lit(0)).Dot("Mutate").
+					Call(
+						jen.Id("ctx"),
+						jen.Id("builders").Index(jen.Lit(0)).Dot("mutation"),
+					),
+				jen.Id("err").Op("!=").Nil(),
+			).Block(
+				jen.Return(jen.Nil(), jen.Id("err")),
+			),
+		)
+		grp.Return(jen.Id("nodes"), jen.Nil())
+	})
+
+	idField := t.ID.StructField()
+	f.Commentf("sqlSave updates the rows of a chunk and reads the updated %s entities", t.Name)
+	f.Comment("back into nodes, in the order of the builders.")
+	f.Func().Params(jen.Id(recv).Op("*").Id(bulkName)).Id("sqlSave").Params(
+		jen.Id("ctx").Qual("context", "Context"),
+		jen.Id("builders").Index().Op("*").Id(updateOneName),
+		jen.Id("specs").Index().Op("*").Qual(h.SQLGraphPkg(), "UpdateSpec"),
+		jen.Id("nodes").Add(nodesType.Clone()),
+	).Error().BlockFunc(func(grp *jen.Group) {
+		grp.If(
+			jen.Id("err").Op(":=").Qual(h.SQLGraphPkg(), "BatchUpdate").Call(
+				jen.Id("ctx"),
+				jen.Id(recv).Dot("config").Dot("Driver"),
+				jen.Op("&").Qual(h.SQLGraphPkg(), "BatchUpdateSpec").Values(jen.Dict{
+					jen.Id("Nodes"): jen.Id("specs"),
+				}),
+			),
+			jen.Id("err").Op("!=").Nil(),
+		).Block(
+			jen.If(
+				jen.List(jen.Id("_"), jen.Id("ok")).Op(":=").Id("err").Assert(jen.Op("*").Qual(h.SQLGraphPkg(), "NotFoundError")),
+				jen.Id("ok"),
+			).Block(
+				jen.Return(jen.Qual(h.VeloxPkg(), "NewNotFoundError").Call(jen.Lit(t.Name))),
+			),
+			jen.Return(jen.Qual(runtimePkg, "MayWrapConstraintError").Call(jen.Id("err"))),
+		)
+		// Every builder has an id here: building its spec failed otherwise.
+		grp.Id("ids").Op(":=").Make(jen.Index().Any(), jen.Len(jen.Id("builders")))
+		grp.For(jen.List(jen.Id("i"), jen.Id("b")).Op(":=").Range().Id("builders")).Block(
+			jen.Id("ids").Index(jen.Id("i")).Op("=").Op("*").Id("b").Dot("mutation").Dot("id"),
+		)
+		grp.Id("build").Op(":=").Func().Params(
+			jen.Id("_").Qual("context", "Context"),
+		).Params(
+			jen.Op("*").Qual(h.SQLPkg(), "Selector"), jen.Error(),
+		).Block(
+			jen.Id("s").Op(":=").Qual(h.SQLPkg(), "Select").Call(jen.Qual(entityPkg, "Columns").Op("...")).Dot("From").Call(
+				jen.Qual(h.SQLPkg(), "Table").Call(jen.Qual(entityPkg, "Table")),
+			),
+			jen.Id("s").Dot("SetDialect").Call(jen.Id(recv).Dot("config").Dot("Driver").Dot("Dialect").Call()),
+			jen.Id("s").Dot("Where").Call(jen.Qual(h.SQLPkg(), "In").Call(
+				jen.Id("s").Dot("C").Call(jen.Qual(entityPkg, "FieldID")),
+				jen.Id("ids").Op("..."),
+			)),
+			jen.Return(jen.Id("s"), jen.Nil()),
+		)
+		grp.List(jen.Id("updated"), jen.Id("err")).Op(":=").Qual(runtimePkg, "ScanAll").Types(
+			jen.Qual(entityReturnPkg, t.Name), jen.Op("*").Qual(entityReturnPkg, t.Name),
+		).Call(jen.Id("ctx"), jen.Id(recv).Dot("config").Dot("Driver"), jen.Id("build"))
+		grp.If(jen.Id("err").Op("!=").Nil()).Block(
+			jen.Return(jen.Id("err")),
+		)
+		grp.Id("byID").Op(":=").Make(
+			jen.Map(h.IDType(t)).Op("*").Qual(entityReturnPkg, t.Name),
+			jen.Len(jen.Id("updated")),
+		)
+		grp.For(jen.List(jen.Id("_"), jen.Id("n")).Op(":=").Range().Id("updated")).Block(
+			jen.Id("byID").Index(jen.Id("n").Dot(idField)).Op("=").Id("n"),
+		)
+		grp.For(jen.List(jen.Id("i"), jen.Id("b")).Op(":=").Range().Id("builders")).Block(
+			jen.List(jen.Id("node"), jen.Id("ok")).Op(":=").Id("byID").Index(jen.Op("*").Id("b").Dot("mutation").Dot("id")),
+			jen.If(jen.Op("!").Id("ok")).Block(
+				jen.Return(jen.Qual(h.VeloxPkg(), "NewNotFoundError").Call(jen.Lit(t.Name))),
+			),
+			jen.Id("node").Dot(t.SetConfigMethodName()).Call(jen.Id(recv).Dot("config")),
+			jen.Id("nodes").Index(jen.Id("i")).Op("=").Id("node"),
+		)
+		grp.Return(jen.Nil())
+	})
+
+	f.Comment("SaveX is like Save, but panics if an error occurs.")
+	f.Func().Params(jen.Id(recv).Op("*").Id(bulkName)).Id("SaveX").Params(
+		jen.Id("ctx").Qual("context", "Context"),
+	).Add(nodesType.Clone()).Block(
+		jen.List(jen.Id("v"), jen.Id("err")).Op(":=").Id(recv).Dot("Save").Call(jen.Id("ctx")),
+		jen.If(jen.Id("err").Op("!=").Nil()).Block(
+			jen.Panic(jen.Id("err")),
+		),
+		jen.Return(jen.Id("v")),
+	)
+
+	f.Comment("Exec executes the query.")
+	f.Func().Params(jen.Id(recv).Op("*").Id(bulkName)).Id("Exec").Params(
+		jen.Id("ctx").Qual("context", "Context"),
+	).Error().Block(
+		jen.List(jen.Id("_"), jen.Id("err")).Op(":=").Id(recv).Dot("Save").Call(jen.Id("ctx")),
+		jen.Return(jen.Id("err")),
+	)
+
+	f.Comment("ExecX is like Exec, but panics if an error occurs.")
+	f.Func().Params(jen.Id(recv).Op("*").Id(bulkName)).Id("ExecX").Params(
+		jen.Id("ctx").Qual("context", "Context"),
+	).Block(
+		jen.If(jen.Id("err").Op(":=").Id(recv).Dot("Exec").Call(jen.Id("ctx")), jen.Id("err").Op("!=").Nil()).Block(
+			jen.Panic(jen.Id("err")),
+		),
+	)
+}
+
+// genUpdateOneSpec emits the code that builds the sqlgraph.UpdateSpec of an
+// UpdateOne builder into a local variable `spec`. The emitted code returns
+// (nil, err) on failure. When byID is false, the by-ID predicate is only set
+// if the mutation has predicates, so the spec can be batched by
+// sqlgraph.BatchUpdate.
+func genUpdateOneSpec(h gen.GeneratorHelper, grp *jen.Group, t *gen.Type, recv string, byID bool) {
+	entityPkg := h.LeafPkgPath(t)
+	// Validate required edges before executing SQL.
+	if hasRequiredUniqueEdge(t) {
+		grp.If(jen.Id("err").Op(":=").Id(recv).Dot("check").Call(), jen.Id("err").Op("!=").Nil()).Block(
+			jen.Return(jen.Nil(), jen.Id("err")),
+		)
+	}
+	// Run entity validators on the merged old and pending values.
+	genEntityValidateCall(grp, t, jen.Id(recv), jen.Nil())
+	// Get ID from mutation.
+	grp.Id("id").Op(",").Id("ok").Op(":=").Id(recv).Dot("mutation").Dot("ID").Call()
+	grp.If(jen.Op("!").Id("ok")).Block(
+		jen.Return(jen.Nil(), jen.Qual("errors", "New").Call(jen.Lit("velox: missing ID for UpdateOne"))),
+	)
+	// Build UpdateSpec from typed mutation fields — selectFields-aware for UpdateOne.
+	genUpdateSpecBuild(h, grp, t, recv, true)
+	// sqlgraph.UpdateNode (used below) builds its WHERE from spec.Node.ID.Value
+	// and runs ensureExists on zero affected rows; set the id so the single-node
+	// path and its existence re-check target the right row.
+	grp.Id("spec").Dot("Node").Dot("ID").Dot("Value").Op("=").Id("id")
+	// Apply the by-ID predicate AND any chained .Where() predicates from the
+	// mutation. Without merging PredicatesFuncs, UpdateOneID(...).Where(...)
+	// silently drops the guard, turning optimistic-lock / status-guard
+	// conditional updates into unconditional ones (a lost-update footgun).
+	grp.Id("ps").Op(":=").Id(recv).Dot("mutation").Dot("PredicatesFuncs").Call()
+	predicate := jen.Id("spec").Dot("Predicate").Op("=").Func().Params(
+		jen.Id("s").Op("*").Qual(h.SQLPkg(), "Selector"),
+	).Block(
+		jen.Id("s").Dot("Where").Call(jen.Qual(h.SQLPkg(), "EQ").Call(
+			jen.Id("s").Dot("C").Call(jen.Qual(entityPkg, "FieldID")),
+			jen.Id("id"),
+		)),
+		jen.For(jen.Id("i").Op(":=").Range().Id("ps")).Block(
+			jen.Id("ps").Index(jen.Id("i")).Call(jen.Id("s")),
+		),
+	)
+	if byID {
+		grp.Add(predicate)
+	} else {
+		// A bulk node without predicates is left for sqlgraph.BatchUpdate to
+		// batch; one with predicates is updated on its own by UpdateNode.
+		grp.If(jen.Len(jen.Id("ps")).Op(">").Lit(0)).Block(predicate)
+	}
+	// Emit edge operations into spec.Edges.Add / spec.Edges.Clear and apply modifiers.
+	genUpdateEdgesAndModifiers(h, grp, t, recv, true)
+}
+
+// genUpdateOnePrepare emits the code that runs before the mutation hooks of
+// an UpdateOne builder: the OldXxx loader, field policies, update defaults
+// and the privacy policy. The emitted code returns (nil, err) on failure.
+func genUpdateOnePrepare(h gen.GeneratorHelper, grp *jen.Group, t *gen.Type, recv string) {
+	entityPkg := h.LeafPkgPath(t)
+	entityReturnPkg := h.SharedEntityPkg()
+	// Wire up typed oldValue closure so OldXxx(ctx) methods work in hooks.
+	if len(t.MutableFields()) > 0 {
+		grp.Id(recv).Dot("mutation").Dot("oldValue").Op("=").Func().Params(
+			jen.Id("ctx").Qual("context", "Context"),
+		).Params(jen.Op("*").Qual(entityReturnPkg, t.Name), jen.Error()).BlockFunc(func(loader *jen.Group) {
+			loader.Id("id").Op(",").Id("ok").Op(":=").Id(recv).Dot("mutation").Dot("ID").Call()
+			loader.If(jen.Op("!").Id("ok")).Block(
+				jen.Return(jen.Nil(), jen.Qual("errors", "New").Call(jen.Lit("velox: missing ID for OldField"))),
+			)
+			loader.Id("build").Op(":=").Func().Params(
+				jen.Id("_").Qual("context", "Context"),
+			).Params(
+				jen.Op("*").Qual(h.SQLPkg(), "Selector"), jen.Error(),
+			).Block(
+				jen.Id("s").Op(":=").Qual(h.SQLPkg(), "Select").Call(jen.Qual(entityPkg, "Columns").Op("...")).Dot("From").Call(
+					jen.Qual(h.SQLPkg(), "Table").Call(jen.Qual(entityPkg, "Table")),
+				),
+				jen.Id("s").Dot("SetDialect").Call(jen.Id(recv).Dot("config").Dot("Driver").Dot("Dialect").Call()),
+				jen.Id("s").Dot("Where").Call(jen.Qual(h.SQLPkg(), "EQ").Call(
+					jen.Id("s").Dot("C").Call(jen.Qual(entityPkg, "FieldID")),
+					jen.Id("id"),
+				)),
+				jen.Id("s").Dot("Limit").Call(jen.Lit(1)),
+				jen.Return(jen.Id("s"), jen.Nil()),
+			)
+			loader.List(jen.Id("_old"), jen.Id("err")).Op(":=").Qual(runtimePkg, "ScanFirst").Types(
+				jen.Qual(entityReturnPkg, t.Name), jen.Op("*").Qual(entityReturnPkg, t.Name),
+			).Call(jen.Id("ctx"), jen.Id(recv).Dot("config").Dot("Driver"), jen.Id("build"), jen.Lit(t.Name))
+			loader.If(jen.Id("err").Op("!=").Nil()).Block(
+				jen.Return(jen.Nil(), jen.Id("err")),
+			)
+			// Propagate config onto the loaded old entity so hooks that
+			// traverse edges off it (e.g. old.QueryPosts()) don't panic.
+			loader.Id("_old").Dot(t.SetConfigMethodName()).Call(jen.Id(recv).Dot("config"))
+			loader.Return(jen.Id("_old"), jen.Nil())
+		})
+	}
+	genFieldPolicyCheck(h, grp, t, jen.Id(recv).Dot("mutation"), jen.Nil())
+	if t.HasUpdateDefault() {
+		grp.If(jen.Id("err").Op(":=").Id(recv).Dot("defaults").Call(), jen.Id("err").Op("!=").Nil()).Block(
+			jen.Return(jen.Nil(), jen.Id("err")),
+		)
+	}
+	// Explicit privacy check — runs before hooks since privacy no longer rides on Hooks[0].
+	if t.NumPolicy() > 0 {
+		grp.If(jen.Id(recv).Dot("policy").Op("!=").Nil()).Block(
+			jen.If(jen.Id("err").Op(":=").Id(recv).Dot("policy").Dot("EvalMutation").Call(
+				jen.Id("ctx"), jen.Id(recv).Dot("mutation"),
+			), jen.Id("err").Op("!=").Nil()).Block(
+				jen.Return(jen.Nil(), jen.Id("err")),
+			),
+		)
+	}
+}
+
 // genUpdateSpecBuild emits code that builds a sqlgraph.UpdateSpec directly
 // from typed mutation fields (_name, _age, _addage, clearedFields). The emitted
 // code declares a local variable `spec` used by the caller.
@@ -577,13 +880,15 @@ func genUpdateSpecBuild(h gen.GeneratorHelper, grp *jen.Group, t *gen.Type, recv
 			continue
 		}
 		typedField := "_" + fd.Name
-		setStmt := jen.If(jen.Id(recv).Dot("mutation").Dot(typedField).Op("!=").Nil()).Block(
-			jen.Id("spec").Dot("SetField").Call(
-				jen.Lit(fd.StorageKey()),
-				jen.Qual(fieldPkg, h.FieldTypeConstant(fd)),
-				jen.Op("*").Id(recv).Dot("mutation").Dot(typedField),
-			),
-		)
+		setStmt := jen.If(jen.Id(recv).Dot("mutation").Dot(typedField).Op("!=").Nil()).BlockFunc(func(blk *jen.Group) {
+			for _, col := range dualWriteColumns(fd) {
+				blk.Id("spec").Dot("SetField").Call(
+					jen.Lit(col),
+					jen.Qual(fieldPkg, h.FieldTypeConstant(fd)),
+					jen.Op("*").Id(recv).Dot("mutation").Dot(typedField),
+				)
+			}
+		})
 		grp.Add(selectGuard(fd.Name, setStmt))
 	}
 
@@ -596,13 +901,15 @@ func genUpdateSpecBuild(h gen.GeneratorHelper, grp *jen.Group, t *gen.Type, recv
 			continue
 		}
 		addField := "_add" + fd.Name
-		addStmt := jen.If(jen.Id(recv).Dot("mutation").Dot(addField).Op("!=").Nil()).Block(
-			jen.Id("spec").Dot("AddField").Call(
-				jen.Lit(fd.StorageKey()),
-				jen.Qual(fieldPkg, h.FieldTypeConstant(fd)),
-				jen.Op("*").Id(recv).Dot("mutation").Dot(addField),
-			),
-		)
+		addStmt := jen.If(jen.Id(recv).Dot("mutation").Dot(addField).Op("!=").Nil()).BlockFunc(func(blk *jen.Group) {
+			for _, col := range dualWriteColumns(fd) {
+				blk.Id("spec").Dot("AddField").Call(
+					jen.Lit(col),
+					jen.Qual(fieldPkg, h.FieldTypeConstant(fd)),
+					jen.Op("*").Id(recv).Dot("mutation").Dot(addField),
+				)
+			}
+		})
 		grp.Add(selectGuard(fd.Name, addStmt))
 	}
 
@@ -620,16 +927,30 @@ func genUpdateSpecBuild(h gen.GeneratorHelper, grp *jen.Group, t *gen.Type, recv
 		clearStmt := jen.If(
 			jen.List(jen.Id("_"), jen.Id("ok")).Op(":=").Id(recv).Dot("mutation").Dot("clearedFields").Index(jen.Lit(fd.Name)),
 			jen.Id("ok"),
-		).Block(
-			jen.Id("spec").Dot("ClearField").Call(
-				jen.Lit(fd.StorageKey()),
-				jen.Qual(fieldPkg, h.FieldTypeConstant(fd)),
-			),
-		)
+		).BlockFunc(func(blk *jen.Group) {
+			for _, col := range dualWriteColumns(fd) {
+				blk.Id("spec").Dot("ClearField").Call(
+					jen.Lit(col),
+					jen.Qual(fieldPkg, h.FieldTypeConstant(fd)),
+				)
+			}
+		})
 		grp.Add(selectGuard(fd.Name, clearStmt))
 	}
 }
 
+// dualWriteColumns returns the columns an update writes for the field: its
+// own column, followed by the old column of a renamed field, which is kept
+// in sync until the sqlschema.RenamedFrom annotation is removed. The old
+// column of a retyped field (sqlschema.RetypedFrom) has another type, and
+// is not written.
+func dualWriteColumns(fd *gen.Field) []string {
+	if old := fd.RenamedFrom(); old != "" && !fd.Retyped() {
+		return []string{fd.StorageKey(), old}
+	}
+	return []string{fd.StorageKey()}
+}
+
 // jsonAppendSet emits `u.Set(colCopy, sql.ExprFunc(func(b *sql.Builder) {...}))`
 // for a JSON array-append expression split around its single value placeholder.
 // prefix is a fmt.Sprintf template containing one %s for the column name;
@@ -729,6 +1050,9 @@ func genUpdateEdgesAndModifiers(h gen.GeneratorHelper, grp *jen.Group, t *gen.Ty
 		})
 	}
 
+	// Values set at the paths of typed JSON fields.
+	genUpdateJSONPaths(h, grp, t, recv)
+
 	// Emit one EdgeSpec block per edge based on its relation type.
 	for _, edge := range t.EdgesWithID() {
 		if fld := edge.Field(); fld != nil && fld.UserDefined {
@@ -737,6 +1061,10 @@ func genUpdateEdgesAndModifiers(h gen.GeneratorHelper, grp *jen.Group, t *gen.Ty
 		genUpdateEdge(h, grp, t, edge, entityPkg, fieldPkg, sqlGraphPkg, recv)
 	}
 
+	if len(counterCacheFields(h.Graph(), t)) > 0 {
+		grp.Id("spec").Dot("Counters").Op("=").Qual(entityPkg, "CounterCaches")
+	}
+
 	// User modifiers.
 	grp.If(jen.Len(jen.Id(recv).Dot("modifiers")).Op(">").Lit(0)).Block(
 		jen.Id("spec").Dot("AddModifiers").Call(jen.Id(recv).Dot("modifiers").Op("...")),
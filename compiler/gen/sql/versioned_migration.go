@@ -7,7 +7,7 @@ import (
 )
 
 // genVersionedMigration generates the migrate/versioned.go file with the
-// versioned-migration types (Migration, MigrationDir, LocalDir). It must NOT
+// versioned-migration types (Migration, MigrationDir, LocalDir, Runner). It must NOT
 // target migrate/migrate.go — that file is owned by genMigrateMigrate via
 // generateMigrations, and a second writer on the same path races (the loser's
 // content is silently discarded).
@@ -90,8 +90,14 @@ func genVersionedMigration(h gen.GeneratorHelper) *jen.File {
 		)),
 	)
 
+	genVersionedRunner(f)
+	genExpandContract(f)
+
 	// MigrationRunner struct
 	f.Comment("MigrationRunner runs versioned migrations.")
+	f.Comment("")
+	f.Comment("Deprecated: use Runner, which records checksums, locks the database")
+	f.Comment("and supports down migrations.")
 	f.Type().Id("MigrationRunner").Struct(
 		jen.Id("db").Op("*").Qual("database/sql", "DB"),
 		jen.Id("dir").Id("MigrationDir"),
@@ -281,3 +287,63 @@ func genVersionedMigration(h gen.GeneratorHelper) *jen.File {
 
 	return f
 }
+
+// genVersionedRunner generates the Runner type, which re-exports the
+// schema.VersionedRunner the same way migrate.go re-exports the schema
+// migration options.
+func genVersionedRunner(f *jen.File) {
+	const schemaPkg = "github.com/syssam/velox/dialect/sql/schema"
+
+	f.Comment("Runner applies and reverts the migrations of a MigrationDir. It records the")
+	f.Comment("applied migrations with their checksums, holds a database lock while running")
+	f.Comment("and supports down files and the \"-- +velox NoTransaction\" directive.")
+	f.Type().Id("Runner").Op("=").Qual(schemaPkg, "VersionedRunner")
+
+	f.Comment("Revision is the state of a versioned migration, as returned by the Runner.")
+	f.Type().Id("Revision").Op("=").Qual(schemaPkg, "Revision")
+
+	f.Var().Defs(
+		jen.Comment("WithRevisionsTable sets the name of the table that records the applied migrations."),
+		jen.Id("WithRevisionsTable").Op("=").Qual(schemaPkg, "WithRevisionsTable"),
+		jen.Comment("WithLockTimeout sets how long the Runner waits for the migration lock."),
+		jen.Id("WithLockTimeout").Op("=").Qual(schemaPkg, "WithLockTimeout"),
+		jen.Comment("ErrChecksumMismatch is returned when an applied migration file was edited."),
+		jen.Id("ErrChecksumMismatch").Op("=").Qual(schemaPkg, "ErrChecksumMismatch"),
+	)
+
+	f.Comment("NewRunner returns a Runner for the migrations of dir, run against db,")
+	f.Comment("a database of the given dialect.")
+	f.Func().Id("NewRunner").Params(
+		jen.Id("db").Op("*").Qual("database/sql", "DB"),
+		jen.Id("dialect").String(),
+		jen.Id("dir").Id("MigrationDir"),
+		jen.Id("opts").Op("...").Qual(schemaPkg, "RunnerOption"),
+	).Params(jen.Op("*").Id("Runner"), jen.Error()).Block(
+		jen.Return(jen.Qual(schemaPkg, "NewVersionedRunner").Call(
+			jen.Id("db"), jen.Id("dialect"), jen.Id("dir"), jen.Id("opts").Op("..."),
+		)),
+	)
+}
+
+// genExpandContract generates PlanExpandContract, which plans the schema
+// changes of Tables as expand/contract phases written by schema.DirWriter.
+func genExpandContract(f *jen.File) {
+	const schemaPkg = "github.com/syssam/velox/dialect/sql/schema"
+
+	f.Comment("PlanExpandContract compares the database of drv with the schema and splits the")
+	f.Comment("changes that would break running instances into expand, backfill, dual-write")
+	f.Comment("and contract phases. Use ExpandContractPlan.WriteDir to write each phase as a")
+	f.Comment("separate migration file.")
+	f.Func().Id("PlanExpandContract").Params(
+		jen.Id("ctx").Qual("context", "Context"),
+		jen.Id("drv").Qual("github.com/syssam/velox/dialect", "Driver"),
+		jen.Id("name").String(),
+		jen.Id("opts").Op("...").Qual(schemaPkg, "MigrateOption"),
+	).Params(jen.Op("*").Qual(schemaPkg, "ExpandContractPlan"), jen.Error()).Block(
+		jen.List(jen.Id("m"), jen.Id("err")).Op(":=").Qual(schemaPkg, "NewMigrate").Call(jen.Id("drv"), jen.Id("opts").Op("...")),
+		jen.If(jen.Id("err").Op("!=").Nil()).Block(
+			jen.Return(jen.Nil(), jen.Id("err")),
+		),
+		jen.Return(jen.Id("m").Dot("PlanExpandContract").Call(jen.Id("ctx"), jen.Id("name"), jen.Id("Tables").Op("..."))),
+	)
+}
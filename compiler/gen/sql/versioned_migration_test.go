@@ -74,3 +74,21 @@ func TestGenVersionedMigration_HelperTypes(t *testing.T) {
 	assert.Contains(t, code, "BeginTx")
 	assert.Contains(t, code, "Commit")
 }
+
+func TestGenVersionedMigration_Runner(t *testing.T) {
+	helper := newMockHelper()
+	helper.graph.Nodes = []*gen.Type{createTestType("User")}
+
+	file := genVersionedMigration(helper)
+	require.NotNil(t, file)
+	assertValidGo(t, file, "versioned")
+
+	code := file.GoString()
+	assert.Contains(t, code, "type Runner = schema.VersionedRunner")
+	assert.Contains(t, code, "type Revision = schema.Revision")
+	assert.Contains(t, code, "func NewRunner(db *sql.DB, dialect string, dir MigrationDir, opts ...schema.RunnerOption) (*Runner, error)")
+	assert.Contains(t, code, "WithLockTimeout = schema.WithLockTimeout")
+	assert.Contains(t, code, "Deprecated: use Runner")
+	assert.Contains(t, code, "func PlanExpandContract(ctx context.Context, drv dialect.Driver, name string, opts ...schema.MigrateOption) (*schema.ExpandContractPlan, error)")
+	assert.Contains(t, code, "m.PlanExpandContract(ctx, name, Tables...)")
+}
@@ -762,6 +762,23 @@ func TestNodeResolverIDTypeAssertion(t *testing.T) {
 	})
 }
 
+// TestNodeResolverIDPrefix guards that the resolver records the ID prefix of
+// the type, so the generated Noder dispatches prefixed IDs to it directly.
+func TestNodeResolverIDPrefix(t *testing.T) {
+	helper := newMockHelper()
+	helper.rootPkg = "github.com/test/project/ent"
+	userType := createTestType("User")
+	if src := genEntityRuntime(helper, userType).GoString(); strings.Contains(src, "IDPrefix") {
+		t.Error("NodeResolver must not set IDPrefix for types without an ID prefix")
+	}
+
+	userType.ID.Type = &field.TypeInfo{Type: field.TypeString}
+	userType.ID.Annotations = gen.Annotations{field.Annotation{}.Name(): field.IDPrefix("usr")}
+	if src := genEntityRuntime(helper, userType).GoString(); !strings.Contains(src, `IDPrefix: "usr"`) {
+		t.Errorf("NodeResolver must set IDPrefix for prefixed IDs; got:\n%s", src)
+	}
+}
+
 // TestMutationTypedStateInvariants pins Velox's core design advantage
 // over Ent: mutations store state inline with Go types, not in a
 // map[string]any with a generic ConvertOldValue[T] shim. This test
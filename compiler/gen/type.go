@@ -6,14 +6,18 @@ import (
 	"fmt"
 	"go/token"
 	"go/types"
+	"maps"
 	"path"
 	"reflect"
+	"regexp"
 	"slices"
 	"strings"
 	"unicode"
 
 	"github.com/syssam/velox/compiler/load"
+	"github.com/syssam/velox/dialect/sql/shard"
 	"github.com/syssam/velox/dialect/sqlschema"
+	"github.com/syssam/velox/outbox"
 	entschema "github.com/syssam/velox/schema"
 	"github.com/syssam/velox/schema/field"
 )
@@ -38,6 +42,8 @@ type (
 		fields map[string]*Field
 		// Edge holds all the edges of this type.
 		Edges []*Edge
+		// OneOfEdges holds the polymorphic edges of this type (edge.ToOneOf).
+		OneOfEdges []*OneOfEdge
 		// Indexes are the configured indexes for this type.
 		Indexes []*Index
 		// ForeignKeys are the foreign-keys that resides in the type table.
@@ -92,6 +98,33 @@ type (
 		Annotations Annotations
 		// referenced foreign-key.
 		fk *ForeignKey
+		// counted edge of a counter-cache field.
+		counter *Edge
+		// polymorphic edge stored by the field.
+		oneOf *OneOfEdge
+	}
+
+	// OneOfEdge is a polymorphic edge (edge.ToOneOf) that points to one entity
+	// of one of several types. It is stored in two fields of the owner type,
+	// the name of the referenced type and its ID.
+	OneOfEdge struct {
+		def *load.Edge
+		// Name holds the name of the edge.
+		Name string
+		// Owner holds the type that declares the edge.
+		Owner *Type
+		// Types holds the types the edge can point to.
+		Types []*Type
+		// Optional indicates is this edge is optional on create.
+		Optional bool
+		// Immutable indicates is this edge cannot be updated.
+		Immutable bool
+		// StructTag of the edge-field in the struct. default to "json".
+		StructTag string
+		// TypeField holds the enum field that stores the name of the referenced type.
+		TypeField *Field
+		// IDField holds the field that stores the ID of the referenced entity.
+		IDField *Field
 	}
 
 	// Edge of a graph between two types.
@@ -242,7 +275,7 @@ func NewType(c *Config, schema *load.Schema) (*Type, error) {
 			Optional:      f.Optional,
 			Default:       f.Default,
 			UpdateDefault: f.UpdateDefault,
-			Immutable:     f.Immutable,
+			Immutable:     f.Immutable || f.Generated != nil,
 			StructTag:     structTag(f.Name, f.Tag),
 			Validators:    f.Validators,
 			UserDefined:   true,
@@ -258,6 +291,8 @@ func NewType(c *Config, schema *load.Schema) (*Type, error) {
 				return nil, errors.New("id field cannot be optional")
 			case f.ValueScanner:
 				return nil, errors.New("id field cannot have an external ValueScanner")
+			case f.Generated != nil:
+				return nil, errors.New("id field cannot be generated")
 			}
 			typ.ID = tf
 		} else {
@@ -277,6 +312,13 @@ func (t Type) IsView() bool {
 	return t.schema != nil && t.schema.View
 }
 
+// IsMaterializedView indicates if the type (schema) is a materialized view.
+// See sqlschema.Materialized.
+func (t Type) IsMaterializedView() bool {
+	ant := t.EntSQL()
+	return t.IsView() && ant != nil && ant.Materialized
+}
+
 // IsEdgeSchema indicates if the type (schema) is used as an edge-schema.
 // i.e. is being used by an edge (or its inverse) with edge.Through modifier.
 func (t Type) IsEdgeSchema() bool {
@@ -293,6 +335,18 @@ func (t Type) HasOneFieldID() bool {
 	return !t.HasCompositeID() && t.ID != nil
 }
 
+// IDPrefix returns the type prefix of the string IDs of the type,
+// as set by the field.IDPrefix annotation on its ID field.
+func (t Type) IDPrefix() string {
+	if t.ID == nil {
+		return ""
+	}
+	if ant := fieldAnnotate(t.ID.Annotations); ant != nil {
+		return ant.IDPrefix
+	}
+	return ""
+}
+
 // Label returns the label name of the node/type (snake_case).
 func (t Type) Label() string {
 	return snake(t.Name)
@@ -671,6 +725,39 @@ func (i Index) EntSQL() *sqlschema.IndexAnnotation {
 	return sqlIndexAnnotate(i.Annotations)
 }
 
+// FullText reports if the index is a full-text index.
+func (i Index) FullText() bool {
+	ant := i.EntSQL()
+	return ant != nil && ant.FullText
+}
+
+// FullTextIndex returns the full-text index of the type, or nil if it has none.
+func (t Type) FullTextIndex() *Index {
+	for _, idx := range t.Indexes {
+		if idx.FullText() {
+			return idx
+		}
+	}
+	return nil
+}
+
+// FullTextFields returns the fields of the full-text index of the type.
+func (t Type) FullTextFields() []*Field {
+	idx := t.FullTextIndex()
+	if idx == nil {
+		return nil
+	}
+	fields := make([]*Field, 0, len(idx.Columns))
+	for _, c := range idx.Columns {
+		for _, f := range t.Fields {
+			if f.StorageKey() == c {
+				fields = append(fields, f)
+			}
+		}
+	}
+	return fields
+}
+
 // AddIndex adds a new index for the type.
 // It fails if the schema index is invalid.
 func (t *Type) AddIndex(idx *load.Index) error {
@@ -722,10 +809,43 @@ func (t *Type) AddIndex(idx *load.Index) error {
 		parts := append([]string{strings.ToLower(t.Name)}, index.Columns...)
 		index.Name = strings.Join(parts, "_")
 	}
+	if index.FullText() {
+		if err := t.checkFullText(idx, index.EntSQL()); err != nil {
+			return fmt.Errorf("full-text index %q: %w", index.Name, err)
+		}
+	}
 	t.Indexes = append(t.Indexes, index)
 	return nil
 }
 
+// fullTextConfig matches the valid text search configurations of full-text indexes.
+var fullTextConfig = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_.]*$`)
+
+// checkFullText checks the definition of a full-text index.
+func (t *Type) checkFullText(idx *load.Index, ant *sqlschema.IndexAnnotation) error {
+	switch {
+	case t.FullTextIndex() != nil:
+		return errors.New("a type can have only one full-text index")
+	case idx.Unique:
+		return errors.New("cannot be unique")
+	case len(idx.Edges) > 0:
+		return errors.New("cannot contain edges")
+	case ant.FullTextConfig != "" && !fullTextConfig.MatchString(ant.FullTextConfig):
+		return fmt.Errorf("invalid text search configuration %q", ant.FullTextConfig)
+	}
+	for _, name := range idx.Fields {
+		if f := t.fields[name]; f != nil && !f.IsString() {
+			return fmt.Errorf("field %q is not a string", name)
+		}
+	}
+	for _, f := range t.Fields {
+		if n := f.StructField(); n == "Search" || n == "Relevance" {
+			return fmt.Errorf("field %q conflicts with the generated Search and ByRelevance functions", f.Name)
+		}
+	}
+	return nil
+}
+
 // setupFKs makes sure all edge-fks are created for the edges.
 func (t *Type) setupFKs() error {
 	for _, e := range t.Edges {
@@ -842,6 +962,112 @@ func (t *Type) addFK(fk *ForeignKey) {
 	t.ForeignKeys = append(t.ForeignKeys, fk)
 }
 
+// addCounterCaches adds the read-only integer fields that count the
+// dependents of the edges annotated with edge.CounterCache.
+func (t *Type) addCounterCaches() error {
+	for _, e := range t.Edges {
+		name := e.CounterCache()
+		if name == "" {
+			continue
+		}
+		f := &load.Field{
+			Name:      name,
+			Info:      &field.TypeInfo{Type: field.TypeInt},
+			Optional:  true,
+			Immutable: true,
+			Comment:   fmt.Sprintf("%s holds the number of %s edges.", pascal(name), e.Name),
+		}
+		tf := &Field{
+			cfg:       t.Config,
+			def:       f,
+			typ:       t,
+			Name:      f.Name,
+			Type:      f.Info,
+			Optional:  f.Optional,
+			Immutable: f.Immutable,
+			StructTag: structTag(f.Name, ""),
+			counter:   e,
+		}
+		if err := t.checkField(tf, f); err != nil {
+			return fmt.Errorf("counter cache of edge %q: %w", e.Name, err)
+		}
+		t.Fields = append(t.Fields, tf)
+		t.fields[f.Name] = tf
+	}
+	return nil
+}
+
+// addOneOfEdge adds a polymorphic edge (edge.ToOneOf) to the type, along with
+// the fields that store it, and an index on them for the reverse lookups.
+func (t *Type) addOneOfEdge(e *load.Edge, types []*Type) error {
+	fail := func(format string, args ...any) error {
+		return &EdgeError{From: t.Name, Edge: e.Name, Message: fmt.Sprintf(format, args...)}
+	}
+	seen := make(map[string]bool, len(types))
+	for _, typ := range types {
+		switch {
+		case seen[typ.Name]:
+			return fail("polymorphic edge %q contains type %q more than once", e.Name, typ.Name)
+		case typ.IsView():
+			return fail("polymorphic edge %q cannot point to view %q", e.Name, typ.Name)
+		case !typ.HasOneFieldID():
+			return fail("polymorphic edge %q requires type %q to have a single-field id", e.Name, typ.Name)
+		case typ.ID.Type.String() != types[0].ID.Type.String():
+			return fail("polymorphic edge %q requires the types to have the same id type (%s: %s, %s: %s)", e.Name, types[0].Name, types[0].ID.Type, typ.Name, typ.ID.Type)
+		}
+		seen[typ.Name] = true
+	}
+	oe := &OneOfEdge{
+		def:       e,
+		Name:      e.Name,
+		Owner:     t,
+		Types:     types,
+		Optional:  !e.Required,
+		Immutable: e.Immutable,
+		StructTag: structTag(e.Name, e.Tag),
+	}
+	enums := make([]struct{ N, V string }, len(types))
+	for i, typ := range types {
+		enums[i] = struct{ N, V string }{N: typ.Name, V: typ.Name}
+	}
+	idType := *types[0].ID.Type
+	for _, f := range []*load.Field{
+		{
+			Name:    e.Name + "_type",
+			Info:    &field.TypeInfo{Type: field.TypeEnum},
+			Enums:   enums,
+			Comment: fmt.Sprintf("%sType holds the type of the %s edge.", pascal(e.Name), e.Name),
+		},
+		{
+			Name:    e.Name + "_id",
+			Info:    &idType,
+			Comment: fmt.Sprintf("%sID holds the id of the %s edge.", pascal(e.Name), e.Name),
+		},
+	} {
+		f.Optional, f.Nillable, f.Immutable = oe.Optional, oe.Optional, oe.Immutable
+		tf := &Field{
+			cfg:       t.Config,
+			def:       f,
+			typ:       t,
+			Name:      f.Name,
+			Type:      f.Info,
+			Optional:  f.Optional,
+			Nillable:  f.Nillable,
+			Immutable: f.Immutable,
+			StructTag: structTag(f.Name, ""),
+			oneOf:     oe,
+		}
+		if err := t.checkField(tf, f); err != nil {
+			return fail("polymorphic edge %q: %v", e.Name, err)
+		}
+		t.Fields = append(t.Fields, tf)
+		t.fields[f.Name] = tf
+	}
+	oe.TypeField, oe.IDField = t.fields[e.Name+"_type"], t.fields[e.Name+"_id"]
+	t.OneOfEdges = append(t.OneOfEdges, oe)
+	return t.AddIndex(&load.Index{Fields: []string{oe.TypeField.Name, oe.IDField.Name}})
+}
+
 // ClientName returns the struct name denoting the client of this type.
 func (t Type) ClientName() string {
 	return pascal(t.Name) + "Client"
@@ -901,6 +1127,12 @@ func (t Type) UpdateOneName() string {
 	return pascal(t.Name) + "UpdateOne"
 }
 
+// UpdateBulkName returns the struct name denoting the update-bulk-builder for
+// this type, which updates many nodes by id, each with its own values.
+func (t Type) UpdateBulkName() string {
+	return pascal(t.Name) + "UpdateBulk"
+}
+
 // UpdateOneReceiver returns the receiver name of the update-one-builder for this type.
 // Matches Ent's convention of using "_u" for all update-one builders (same as UpdateReceiver).
 func (t Type) UpdateOneReceiver() string {
@@ -1019,12 +1251,103 @@ func (t Type) SiblingImports() []struct{ Alias, Path string } {
 	return imports
 }
 
-// NumHooks returns the number of hooks declared in the type schema.
+// NumHooks returns the number of hooks declared in the type schema,
+// plus the outbox hook of the type, if any.
 func (t Type) NumHooks() int {
+	n := 0
 	if t.schema != nil {
-		return len(t.schema.Hooks)
+		n = len(t.schema.Hooks)
 	}
-	return 0
+	if t.HasOutbox() {
+		n++
+	}
+	return n
+}
+
+// Outbox returns the outbox.Annotation of the type, or nil if the type
+// is not annotated.
+func (t Type) Outbox() *outbox.Annotation {
+	ant := &outbox.Annotation{}
+	if t.Annotations == nil || t.Annotations[ant.Name()] == nil {
+		return nil
+	}
+	if b, err := json.Marshal(t.Annotations[ant.Name()]); err == nil {
+		_ = json.Unmarshal(b, ant)
+	}
+	return ant
+}
+
+// HasOutbox reports if the mutations of the type are recorded in the
+// transactional outbox: the type is annotated and the feature is enabled.
+func (t Type) HasOutbox() bool {
+	return t.Config != nil && t.featureEnabled(FeatureOutbox) && t.Outbox() != nil
+}
+
+// CascadeEdges returns the edges of the type whose dependents are handled by
+// its generated delete builders (edge.Cascade).
+func (t Type) CascadeEdges() []*Edge {
+	var edges []*Edge
+	for _, e := range t.Edges {
+		if e.Cascade() != "" {
+			edges = append(edges, e)
+		}
+	}
+	return edges
+}
+
+// ShardKeyName returns the name of the shard key field declared with the
+// shard.Key annotation, or an empty string if the type is not annotated.
+func (t Type) ShardKeyName() string {
+	ant := &shard.Annotation{}
+	if t.Annotations == nil || t.Annotations[ant.Name()] == nil {
+		return ""
+	}
+	if b, err := json.Marshal(t.Annotations[ant.Name()]); err == nil {
+		_ = json.Unmarshal(b, ant)
+	}
+	return ant.Field
+}
+
+// ShardKey returns the shard key field of the type, or nil if the type has
+// no shard key or the sql/shard feature is disabled. Create builders of the
+// type route their statements by the value of this field.
+func (t Type) ShardKey() *Field {
+	name := t.ShardKeyName()
+	if name == "" || t.Config == nil || !t.featureEnabled(FeatureShard) {
+		return nil
+	}
+	for _, f := range t.Fields {
+		if f.Name == name {
+			return f
+		}
+	}
+	return nil
+}
+
+// RowSecurity returns the PostgreSQL row-level security policy of the type, or
+// nil if it has none. The policy is set with sqlschema.TenantPolicy or, with the
+// sql/rowsecurity feature enabled, derived from the "tenant_id" field of the
+// mixin.TenantID mixin.
+func (t Type) RowSecurity() *sqlschema.RowSecurity {
+	if t.IsView() {
+		return nil
+	}
+	if ant := t.EntSQL(); ant != nil && ant.RowSecurity != nil {
+		rs := *ant.RowSecurity
+		if rs.Setting == "" {
+			rs.Setting = sqlschema.TenantSetting
+		}
+		return &rs
+	}
+	if t.Config == nil || !t.featureEnabled(FeatureRowSecurity) {
+		return nil
+	}
+	for _, f := range t.Fields {
+		if f.Name == "tenant_id" && f.Position != nil && f.Position.MixedIn {
+			return &sqlschema.RowSecurity{Column: f.StorageKey(), Setting: sqlschema.TenantSetting}
+		}
+	}
+	return nil
 }
 
 // HookPositions returns the position information of hooks declared in the type schema.
@@ -1067,6 +1390,23 @@ func (t Type) PolicyPositions() []*load.Position {
 	return nil
 }
 
+// NumValidators returns the number of entity validators declared in the
+// type schema. Views have no mutations and never run validators.
+func (t Type) NumValidators() int {
+	if t.schema != nil && !t.IsView() {
+		return len(t.schema.Validators)
+	}
+	return 0
+}
+
+// ValidatorPositions returns the position information of entity validators declared in the type schema.
+func (t Type) ValidatorPositions() []*load.Position {
+	if t.schema != nil && !t.IsView() {
+		return t.schema.Validators
+	}
+	return nil
+}
+
 // RelatedTypes returns all the types (nodes) that
 // are related (with edges) to this type.
 func (t Type) RelatedTypes() []*Type {
@@ -1159,9 +1499,35 @@ func (t *Type) checkField(tf *Field, f *load.Field) (err error) {
 		// Note: id field has its own check in NewType ("id field cannot be optional").
 		err = fmt.Errorf("optional enum field %q requires Default() value (empty string is not a valid enum value)", f.Name)
 	}
+	if err == nil && f.Generated != nil {
+		err = checkGenerated(f, tf.EntSQL())
+	}
 	return err
 }
 
+// checkGenerated checks the expressions of a generated field.
+func checkGenerated(f *load.Field, ant *sqlschema.Annotation) error {
+	g := f.Generated
+	switch {
+	case g.Kind != field.Stored && g.Kind != field.Virtual:
+		return fmt.Errorf("generated field %q has an invalid kind %q", f.Name, g.Kind)
+	case g.Expr == "" && len(g.Exprs) == 0:
+		return fmt.Errorf("generated field %q has no expression", f.Name)
+	case f.Default || f.UpdateDefault || ant != nil && (ant.Default != "" || ant.DefaultExpr != "" || ant.DefaultExprs != nil):
+		return fmt.Errorf("generated field %q cannot have a default value", f.Name)
+	}
+	exprs := append([]string{g.Expr}, slices.Sorted(maps.Values(g.Exprs))...)
+	for _, x := range exprs {
+		if x == "" {
+			continue
+		}
+		if err := sqlschema.ValidateExpression(x); err != nil {
+			return fmt.Errorf("generated field %q: %w", f.Name, err)
+		}
+	}
+	return nil
+}
+
 // UnexportedForeignKeys returns all foreign-keys that belong to the type
 // but are not exported (not defined with field). i.e. generated by velox.
 func (t Type) UnexportedForeignKeys() []*ForeignKey {
@@ -1234,6 +1600,18 @@ func (t Type) HasValueScanner() bool {
 	return false
 }
 
+// FieldPolicyFields returns all fields of the type that are guarded by
+// a privacy.FieldPolicy annotation.
+func (t Type) FieldPolicyFields() []*Field {
+	var fs []*Field
+	for _, f := range t.Fields {
+		if f.HasFieldPolicy() {
+			fs = append(fs, f)
+		}
+	}
+	return fs
+}
+
 // DeprecatedFields returns all deprecated fields of the type.
 func (t Type) DeprecatedFields() []*Field {
 	fs := make([]*Field, 0, len(t.Fields))
@@ -1,6 +1,7 @@
 package gen
 
 import (
+	"encoding/json"
 	"fmt"
 	"log/slog"
 
@@ -311,6 +312,33 @@ func (e Edge) EntSQL() *sqlschema.Annotation {
 	return sqlAnnotate(e.Annotations)
 }
 
+// Cascade returns the ORM-level cascade action of the edge, declared with the
+// edge.Cascade annotation, or an empty string if the edge is not annotated.
+func (e Edge) Cascade() edge.CascadeAction {
+	ant := &edge.Annotation{}
+	if e.Annotations == nil || e.Annotations[ant.Name()] == nil {
+		return ""
+	}
+	if b, err := json.Marshal(e.Annotations[ant.Name()]); err == nil {
+		_ = json.Unmarshal(b, ant)
+	}
+	return ant.Cascade
+}
+
+// CounterCache returns the name of the counter-cache field of the edge owner,
+// declared with the edge.CounterCache annotation, or an empty string if the edge
+// is not annotated.
+func (e Edge) CounterCache() string {
+	ant := &edge.Annotation{}
+	if e.Annotations == nil || e.Annotations[ant.Name()] == nil {
+		return ""
+	}
+	if b, err := json.Marshal(e.Annotations[ant.Name()]); err == nil {
+		_ = json.Unmarshal(b, ant)
+	}
+	return ant.CounterCache
+}
+
 // DeleteAction returns the referential ON DELETE action for this edge's foreign
 // key: the explicit sqlschema.OnDelete annotation if set, otherwise SetNull when
 // the FK column is nullable, otherwise NoAction. This is the single source of
@@ -418,3 +446,37 @@ func (r Rel) String() string {
 	}
 	return s
 }
+
+// =============================================================================
+// OneOfEdge methods
+// =============================================================================
+
+// StructField returns the struct member of the polymorphic edge in the model.
+func (e OneOfEdge) StructField() string {
+	return pascal(e.Name)
+}
+
+// Constant returns the constant name of the edge.
+func (e OneOfEdge) Constant() string {
+	return "Edge" + pascal(e.Name)
+}
+
+// UnionName returns the name of the interface that is implemented by the
+// types of the edge, e.g. CommentSubjectUnion.
+func (e OneOfEdge) UnionName() string {
+	return pascal(e.Owner.Name) + pascal(e.Name) + "Union"
+}
+
+// QueryName returns the name of the method that queries the entity of
+// the given type of the edge, e.g. QuerySubjectPost.
+func (e OneOfEdge) QueryName(t *Type) string {
+	return "Query" + pascal(e.Name) + pascal(t.Name)
+}
+
+// Comment returns the comment of the edge.
+func (e OneOfEdge) Comment() string {
+	if e.def != nil {
+		return e.def.Comment
+	}
+	return ""
+}
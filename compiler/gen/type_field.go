@@ -13,6 +13,7 @@ import (
 	"github.com/syssam/velox/compiler/load"
 	"github.com/syssam/velox/dialect"
 	"github.com/syssam/velox/dialect/sql/schema"
+	"github.com/syssam/velox/privacy"
 	"github.com/syssam/velox/schema/field"
 )
 
@@ -284,6 +285,45 @@ func (f Field) IsTime() bool { return f.Type != nil && f.Type.Type == field.Type
 // IsJSON returns true if the field is a JSON field.
 func (f Field) IsJSON() bool { return f.Type != nil && f.Type.Type == field.TypeJSON }
 
+// JSONPath is the path of a basic value in a JSON field with a struct type.
+type JSONPath struct {
+	// Names are the Go names of the struct fields on the path.
+	Names []string
+	// Keys are the JSON keys of the path.
+	Keys []string
+	// Type is the type of the value.
+	Type *field.RType
+}
+
+// JSONPaths returns the paths of the basic values of a JSON field with a
+// struct type, as they are encoded by encoding/json. Values of unexported
+// named types are skipped, as the generated code cannot reference them.
+// It returns nil for other fields.
+func (f Field) JSONPaths() []*JSONPath {
+	if !f.IsJSON() || f.Type.RType == nil {
+		return nil
+	}
+	var (
+		paths []*JSONPath
+		walk  func([]*field.RField, []string, []string)
+	)
+	walk = func(fields []*field.RField, names, keys []string) {
+		for _, rf := range fields {
+			names, keys := append(slices.Clip(names), rf.Name), append(slices.Clip(keys), rf.Key)
+			if len(rf.Type.Fields) > 0 {
+				walk(rf.Type.Fields, names, keys)
+				continue
+			}
+			if rf.Type.PkgPath != "" && !token.IsExported(rf.Type.Name) {
+				continue
+			}
+			paths = append(paths, &JSONPath{Names: names, Keys: keys, Type: rf.Type})
+		}
+	}
+	walk(f.Type.RType.Fields, nil, nil)
+	return paths
+}
+
 // IsOther returns true if the field is an Other field.
 func (f Field) IsOther() bool { return f.Type != nil && f.Type.Type == field.TypeOther }
 
@@ -338,6 +378,64 @@ func (f Field) Edge() (*Edge, error) {
 // Sensitive returns true if the field is a sensitive field.
 func (f Field) Sensitive() bool { return f.def != nil && f.def.Sensitive }
 
+// Constraints returns the value constraints of the field validators, or nil if there are none.
+func (f Field) Constraints() *field.Constraints {
+	if f.def == nil {
+		return nil
+	}
+	return f.def.Constraints
+}
+
+// Generated returns the expression of a database-generated column, or nil if the field is not generated.
+func (f Field) Generated() *field.Generated {
+	if f.def == nil {
+		return nil
+	}
+	return f.def.Generated
+}
+
+// IsGenerated reports if the field is a database-generated column. Generated
+// fields are read-only: the create and update builders have no setters for them.
+func (f Field) IsGenerated() bool { return f.Generated() != nil }
+
+// CounterCache returns the edge whose dependents are counted by the field, or
+// nil if the field is not a counter cache (edge.CounterCache).
+func (f Field) CounterCache() *Edge { return f.counter }
+
+// OneOfEdge returns the polymorphic edge (edge.ToOneOf) that is stored by
+// the field, or nil if the field does not store one.
+func (f Field) OneOfEdge() *OneOfEdge { return f.oneOf }
+
+// ReadOnly reports if the field is maintained by the database or by the
+// generated code, and hence, has no setters in the create and update builders.
+func (f Field) ReadOnly() bool { return f.IsGenerated() || f.counter != nil }
+
+// HasFieldPolicy reports if the field is guarded by a privacy.FieldPolicy annotation.
+func (f Field) HasFieldPolicy() bool {
+	return f.Annotations != nil && f.Annotations[privacy.FieldAnnotationName] != nil
+}
+
+// RenamedFrom returns the previous column name of the field, as set by the
+// sqlschema.RenamedFrom annotation, or an empty string.
+func (f Field) RenamedFrom() string {
+	if ant := f.EntSQL(); ant != nil {
+		return ant.RenamedFrom
+	}
+	return ""
+}
+
+// Retyped reports if the renamed column of the field changes its type, as
+// set by the sqlschema.RetypedFrom annotation. The old column of a retyped
+// field is not written by the generated mutations.
+func (f Field) Retyped() bool {
+	ant := f.EntSQL()
+	return ant != nil && ant.RenamedFrom != "" && ant.Retyped
+}
+
+// FieldPolicyName returns the name of the package variable holding the
+// privacy.FieldPolicy of the field (e.g. SalaryFieldPolicy).
+func (f Field) FieldPolicyName() string { return f.StructField() + "FieldPolicy" }
+
 // Comment returns the comment of the field,
 func (f Field) Comment() string {
 	if f.def != nil {
@@ -502,6 +600,8 @@ func (f Field) Column() *schema.Column {
 		Comment:  f.sqlComment(),
 	}
 	switch {
+	case f.counter != nil:
+		c.Default = 0
 	case f.Default && (f.Type.Numeric() || f.Type.Type == field.TypeBool):
 		c.Default = f.DefaultValue()
 	case f.Default && (f.IsString() || f.IsEnum()):
@@ -522,9 +622,13 @@ func (f Field) Column() *schema.Column {
 	if ant := f.EntSQL(); ant != nil && ant.Collation != "" {
 		c.Collation = ant.Collation
 	}
+	c.RenamedFrom, c.Retyped = f.RenamedFrom(), f.Retyped()
 	if f.def != nil {
 		c.SchemaType = f.def.SchemaType
 	}
+	if g := f.Generated(); g != nil {
+		c.Generated = &schema.GeneratedExpr{Expr: g.Expr, Exprs: g.Exprs, Type: string(g.Kind)}
+	}
 	return c
 }
 
@@ -546,7 +650,8 @@ func (f Field) needsAutoDefault() bool {
 		return false
 	}
 	// Skip Nillable fields - NULL columns don't need DEFAULT.
-	if f.Nillable {
+	// Generated columns cannot have a DEFAULT.
+	if f.Nillable || f.IsGenerated() {
 		return false
 	}
 	// Skip if field already has an explicit default.
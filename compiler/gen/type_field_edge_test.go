@@ -71,6 +71,34 @@ func TestField_TypeChecks_NilType(t *testing.T) {
 	assert.False(t, f.IsEnum())
 }
 
+type jsonPathLevel int
+
+type jsonPathOptions struct {
+	Theme string        `json:"theme"`
+	Level jsonPathLevel `json:"level,omitempty"`
+}
+
+func TestField_JSONPaths(t *testing.T) {
+	type settings struct {
+		Name    string          `json:"name"`
+		Options jsonPathOptions `json:"opts"`
+		Emails  []string        `json:"emails"`
+	}
+	desc := field.JSON("settings", settings{}).Descriptor()
+	f := Field{Name: "settings", Type: desc.Info}
+	paths := f.JSONPaths()
+	// Values of unexported named types (Options.Level) cannot be referenced
+	// by the generated code, and slices are not basic values.
+	require.Len(t, paths, 2)
+	assert.Equal(t, []string{"Name"}, paths[0].Names)
+	assert.Equal(t, []string{"Options", "Theme"}, paths[1].Names)
+	assert.Equal(t, []string{"opts", "theme"}, paths[1].Keys)
+	assert.Equal(t, "string", paths[1].Type.Ident)
+
+	assert.Nil(t, Field{Type: &field.TypeInfo{Type: field.TypeString}}.JSONPaths())
+	assert.Nil(t, Field{Type: field.JSON("tags", []string{}).Descriptor().Info}.JSONPaths())
+}
+
 // =============================================================================
 // Field naming methods
 // =============================================================================
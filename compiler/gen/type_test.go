@@ -74,6 +74,31 @@ func TestType(t *testing.T) {
 	})
 	require.EqualError(err, "sensitive field \"foo\" cannot have struct tags", "sensitive field cannot have tags")
 
+	typ, err = NewType(&Config{Package: "entc/gen"}, &load.Schema{
+		Name: "TestSchema",
+		Fields: []*load.Field{
+			{Name: "full_name", Info: &field.TypeInfo{Type: field.TypeString}, Generated: &field.Generated{Expr: "first || last", Kind: field.Stored}},
+		},
+	})
+	require.NoError(err)
+	require.True(typ.Fields[0].IsGenerated())
+	require.True(typ.Fields[0].Immutable, "generated fields are read-only")
+	require.Equal("first || last", typ.Fields[0].Column().Generated.Expr)
+
+	for _, tt := range []struct {
+		f   *load.Field
+		err string
+	}{
+		{&load.Field{Name: "foo", Generated: &field.Generated{Expr: "1", Kind: "ALWAYS"}}, `generated field "foo" has an invalid kind "ALWAYS"`},
+		{&load.Field{Name: "foo", Generated: &field.Generated{Kind: field.Virtual}}, `generated field "foo" has no expression`},
+		{&load.Field{Name: "foo", Default: true, Generated: &field.Generated{Expr: "1", Kind: field.Virtual}}, `generated field "foo" cannot have a default value`},
+		{&load.Field{Name: "id", Generated: &field.Generated{Expr: "1", Kind: field.Virtual}}, "id field cannot be generated"},
+	} {
+		tt.f.Info = &field.TypeInfo{Type: field.TypeInt}
+		_, err = NewType(&Config{Package: "entc/gen"}, &load.Schema{Name: "T", Fields: []*load.Field{tt.f}})
+		require.EqualError(err, tt.err)
+	}
+
 	typ, err = NewType(&Config{Package: "entc/gen"}, &load.Schema{
 		Name: "TestSchema",
 		Fields: []*load.Field{
@@ -87,6 +112,20 @@ func TestType(t *testing.T) {
 	require.NotNil(pkCol)
 	require.Equal("utf8_ci_bin", pkCol.Collation)
 
+	typ, err = NewType(&Config{Package: "entc/gen"}, &load.Schema{
+		Name:   "T",
+		Fields: []*load.Field{{Name: "id", Default: true, Info: &field.TypeInfo{Type: field.TypeInt64}}},
+	})
+	require.NoError(err)
+	require.True(typ.ID.PK().Increment, "integer IDs with a default are auto-incremented")
+	typ, err = NewType(&Config{Package: "entc/gen"}, &load.Schema{
+		Name: "T",
+		Fields: []*load.Field{{Name: "id", Default: true, Info: &field.TypeInfo{Type: field.TypeInt64},
+			Annotations: dict("sql", dict("Incremental", false))}},
+	})
+	require.NoError(err)
+	require.False(typ.ID.PK().Increment, "application-generated IDs opt out of auto-increment")
+
 	_, err = NewType(&Config{Package: "entc/gen"}, &load.Schema{
 		Name: "T",
 		Fields: []*load.Field{
@@ -276,6 +315,53 @@ func TestType_AddIndex(t *testing.T) {
 	require.NoError(t, err, "valid index on M2O relation and field")
 }
 
+func TestType_AddIndex_FullText(t *testing.T) {
+	typ, err := NewType(&Config{}, &load.Schema{
+		Name: "Post",
+		Fields: []*load.Field{
+			{Name: "title", Info: &field.TypeInfo{Type: field.TypeString}},
+			{Name: "body", Info: &field.TypeInfo{Type: field.TypeString}},
+			{Name: "views", Info: &field.TypeInfo{Type: field.TypeInt}},
+		},
+	})
+	require.NoError(t, err)
+	fullText := func(ant *sqlschema.IndexAnnotation) map[string]any {
+		return map[string]any{ant.Name(): ant}
+	}
+	require.Nil(t, typ.FullTextIndex())
+
+	err = typ.AddIndex(&load.Index{Fields: []string{"title", "views"}, Annotations: fullText(sqlschema.FullText())})
+	require.EqualError(t, err, `full-text index "post_title_views": field "views" is not a string`)
+	err = typ.AddIndex(&load.Index{Unique: true, Fields: []string{"title"}, Annotations: fullText(sqlschema.FullText())})
+	require.EqualError(t, err, `full-text index "post_title": cannot be unique`)
+	err = typ.AddIndex(&load.Index{Fields: []string{"title"}, Annotations: fullText(sqlschema.FullTextConfig("english'"))})
+	require.EqualError(t, err, `full-text index "post_title": invalid text search configuration "english'"`)
+
+	err = typ.AddIndex(&load.Index{Fields: []string{"title", "body"}, Annotations: fullText(sqlschema.FullTextConfig("pg_catalog.simple"))})
+	require.NoError(t, err)
+	idx := typ.FullTextIndex()
+	require.NotNil(t, idx)
+	require.Equal(t, "post_title_body", idx.Name)
+	require.Equal(t, "pg_catalog.simple", idx.EntSQL().FullTextConfig)
+	fields := typ.FullTextFields()
+	require.Len(t, fields, 2)
+	require.Equal(t, "title", fields[0].Name)
+
+	err = typ.AddIndex(&load.Index{Fields: []string{"body"}, Annotations: fullText(sqlschema.FullText())})
+	require.EqualError(t, err, `full-text index "post_body": a type can have only one full-text index`)
+
+	typ, err = NewType(&Config{}, &load.Schema{
+		Name: "Document",
+		Fields: []*load.Field{
+			{Name: "text", Info: &field.TypeInfo{Type: field.TypeString}},
+			{Name: "relevance", Info: &field.TypeInfo{Type: field.TypeFloat64}},
+		},
+	})
+	require.NoError(t, err)
+	err = typ.AddIndex(&load.Index{Fields: []string{"text"}, Annotations: fullText(sqlschema.FullText())})
+	require.EqualError(t, err, `full-text index "document_text": field "relevance" conflicts with the generated Search and ByRelevance functions`)
+}
+
 func TestField_Constant(t *testing.T) {
 	tests := []struct {
 		name     string
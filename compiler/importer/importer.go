@@ -0,0 +1,863 @@
+// Package importer generates velox schema files from an existing database.
+//
+// The tables are read with the Atlas inspector of the database dialect (see
+// schema.Atlas.InspectSchema) and each table is rendered as a velox.Schema
+// type: columns become fields, foreign keys become edges, join tables with
+// exactly two foreign keys become M2M edges, and indexes, checks, defaults,
+// collations and table names are kept using the sqlschema annotations.
+// Column types that have no velox builder fall back to field.Other with the
+// inspected SchemaType.
+//
+//	err := importer.Import(ctx, drv, "./schema",
+//		importer.WithExcludeTables("schema_migrations"),
+//	)
+//
+// The generated files are a starting point for adopting velox on a legacy
+// database, and are expected to be reviewed and edited by hand.
+package importer
+
+import (
+	"context"
+	stdsql "database/sql"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"ariga.io/atlas/sql/mysql"
+	"ariga.io/atlas/sql/postgres"
+	atlas "ariga.io/atlas/sql/schema"
+	"ariga.io/atlas/sql/sqlite"
+	"github.com/dave/jennifer/jen"
+	"github.com/go-openapi/inflect"
+
+	"github.com/syssam/velox/compiler/gen"
+	"github.com/syssam/velox/dialect"
+	"github.com/syssam/velox/dialect/sql/schema"
+	"github.com/syssam/velox/dialect/sqlschema"
+)
+
+// Import paths of the packages referenced by the generated schema files.
+const (
+	veloxPkg     = "github.com/syssam/velox"
+	schemaPkg    = "github.com/syssam/velox/schema"
+	fieldPkg     = "github.com/syssam/velox/schema/field"
+	edgePkg      = "github.com/syssam/velox/schema/edge"
+	indexPkg     = "github.com/syssam/velox/schema/index"
+	dialectPkg   = "github.com/syssam/velox/dialect"
+	sqlschemaPkg = "github.com/syssam/velox/dialect/sqlschema"
+)
+
+type (
+	// Option configures the importer.
+	Option func(*config)
+
+	config struct {
+		pkg     string
+		tables  []string
+		exclude map[string]bool
+	}
+)
+
+// WithPackage sets the package name of the generated files. Defaults to "schema".
+func WithPackage(name string) Option {
+	return func(c *config) {
+		c.pkg = name
+	}
+}
+
+// WithTables limits the import to the given tables. Note that edges are only
+// inferred between imported tables.
+func WithTables(names ...string) Option {
+	return func(c *config) {
+		c.tables = append(c.tables, names...)
+	}
+}
+
+// WithExcludeTables skips the given tables, e.g. the revision table of an
+// existing migration tool.
+func WithExcludeTables(names ...string) Option {
+	return func(c *config) {
+		for _, n := range names {
+			c.exclude[n] = true
+		}
+	}
+}
+
+func newConfig(opts []Option) *config {
+	c := &config{pkg: "schema", exclude: make(map[string]bool)}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// Inspect reads the tables of the database connected to the given driver.
+func Inspect(ctx context.Context, drv dialect.Driver, opts ...Option) (*atlas.Schema, error) {
+	cfg := newConfig(opts)
+	m, err := schema.NewMigrate(drv)
+	if err != nil {
+		return nil, fmt.Errorf("importer: %w", err)
+	}
+	s, err := m.InspectSchema(ctx, cfg.tables...)
+	if err != nil {
+		return nil, fmt.Errorf("importer: inspect schema: %w", err)
+	}
+	return s, nil
+}
+
+// Import inspects the database connected to the given driver, and writes one
+// schema file per table to dir. Existing files are not overwritten.
+func Import(ctx context.Context, drv dialect.Driver, dir string, opts ...Option) error {
+	s, err := Inspect(ctx, drv, opts...)
+	if err != nil {
+		return err
+	}
+	files, err := Generate(s, drv.Dialect(), opts...)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("importer: %w", err)
+	}
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		path := filepath.Join(dir, name)
+		if _, err := os.Stat(path); err == nil {
+			return fmt.Errorf("importer: file %q already exists", path)
+		}
+	}
+	for _, name := range names {
+		if err := os.WriteFile(filepath.Join(dir, name), files[name], 0o644); err != nil {
+			return fmt.Errorf("importer: %w", err)
+		}
+	}
+	return nil
+}
+
+// LoadDump executes the statements of a .sql dump on the given database. It is
+// used to import a dump without a running database server, by loading it into
+// an in-memory SQLite database first.
+func LoadDump(ctx context.Context, db *stdsql.DB, dump string) error {
+	for _, stmt := range schema.SplitStatements(dump) {
+		if _, err := db.ExecContext(ctx, stmt); err != nil {
+			return fmt.Errorf("importer: load dump: %w", err)
+		}
+	}
+	return nil
+}
+
+// Generate renders the tables of the given schema as velox schema files. The
+// returned map is keyed by file name, e.g. "user.go".
+func Generate(s *atlas.Schema, dialectName string, opts ...Option) (map[string][]byte, error) {
+	im := &importer{
+		cfg:     newConfig(opts),
+		dialect: dialectName,
+		nodes:   make(map[string]*node),
+	}
+	if err := im.load(s); err != nil {
+		return nil, err
+	}
+	files := make(map[string][]byte, len(im.order))
+	for _, n := range im.order {
+		f, err := im.render(n)
+		if err != nil {
+			return nil, err
+		}
+		files[n.file] = f
+	}
+	return files, nil
+}
+
+type (
+	// importer holds the state of a single Generate call.
+	importer struct {
+		cfg     *config
+		dialect string
+		nodes   map[string]*node // nodes by table name.
+		order   []*node
+	}
+
+	// node is a table that is imported as a velox type.
+	node struct {
+		t        *atlas.Table
+		name     string // type name, e.g. "User".
+		singular string // singular snake-case name, e.g. "user".
+		file     string
+		id       *atlas.Column
+		idType   *fieldType
+		omitID   bool            // the id is the velox default.
+		names    map[string]bool // field and edge names in use.
+		columns  map[string]string
+		fields   []jen.Code
+		edges    []jen.Code
+		indexes  []jen.Code
+		comments []string
+	}
+
+	// fieldType describes the field builder a column type is mapped to.
+	fieldType struct {
+		ctor  string     // field constructor, e.g. "String".
+		arg   jen.Code   // second constructor argument, if any.
+		calls []jen.Code // type-specific builder calls, e.g. MaxLen.
+		raw   string     // database type kept with SchemaType.
+	}
+)
+
+func (im *importer) load(s *atlas.Schema) error {
+	var tables, joins []*atlas.Table
+	for _, t := range s.Tables {
+		if im.cfg.exclude[t.Name] {
+			continue
+		}
+		tables = append(tables, t)
+	}
+	sort.Slice(tables, func(i, j int) bool { return tables[i].Name < tables[j].Name })
+	for _, t := range tables {
+		if isJoinTable(t) {
+			joins = append(joins, t)
+			continue
+		}
+		if err := im.addNode(t); err != nil {
+			return err
+		}
+	}
+	// A join table is imported as an M2M edge only if it references
+	// two imported types, otherwise it is imported as a type.
+	var m2m []*atlas.Table
+	for _, t := range joins {
+		if im.ref(t.ForeignKeys[0]) != nil && im.ref(t.ForeignKeys[1]) != nil {
+			m2m = append(m2m, t)
+			continue
+		}
+		if err := im.addNode(t); err != nil {
+			return err
+		}
+	}
+	for _, n := range im.order {
+		if err := im.fields(n); err != nil {
+			return err
+		}
+	}
+	for _, n := range im.order {
+		im.m2o(n)
+	}
+	for _, t := range m2m {
+		im.m2m(t)
+	}
+	for _, n := range im.order {
+		im.indexes(n)
+	}
+	return nil
+}
+
+// addNode adds the table as a velox type.
+func (im *importer) addNode(t *atlas.Table) error {
+	singular := inflect.Singularize(fieldName(t.Name))
+	n := &node{
+		t:        t,
+		name:     gen.Pascal(singular),
+		singular: singular,
+		file:     singular + ".go",
+		names:    make(map[string]bool),
+		columns:  make(map[string]string),
+	}
+	for _, o := range im.order {
+		if o.name == n.name {
+			return fmt.Errorf("importer: tables %q and %q are both mapped to type %s", o.t.Name, t.Name, n.name)
+		}
+	}
+	if pk := t.PrimaryKey; pk != nil && len(pk.Parts) == 1 && pk.Parts[0].C != nil {
+		n.id = pk.Parts[0].C
+		n.idType = im.fieldType(n.id)
+		if n.id.Name == "id" && n.idType.raw == "" && (n.idType.ctor == "Int" || n.idType.ctor == "Int64") {
+			n.idType, n.omitID = &fieldType{ctor: "Int"}, true
+		}
+	}
+	im.nodes[t.Name] = n
+	im.order = append(im.order, n)
+	return nil
+}
+
+// ref returns the node referenced by the given single-column foreign key,
+// or nil if the key does not reference the primary key of an imported type.
+func (im *importer) ref(fk *atlas.ForeignKey) *node {
+	if len(fk.Columns) != 1 || len(fk.RefColumns) != 1 || fk.RefTable == nil {
+		return nil
+	}
+	n, ok := im.nodes[fk.RefTable.Name]
+	if !ok || n.id == nil || n.id.Name != fk.RefColumns[0].Name {
+		return nil
+	}
+	return n
+}
+
+// isJoinTable reports if the table only holds the two foreign-key columns
+// of an M2M relation, keyed by both columns or not keyed at all.
+func isJoinTable(t *atlas.Table) bool {
+	if len(t.Columns) != 2 || len(t.ForeignKeys) != 2 {
+		return false
+	}
+	for _, fk := range t.ForeignKeys {
+		if len(fk.Columns) != 1 || len(fk.RefColumns) != 1 {
+			return false
+		}
+	}
+	if t.ForeignKeys[0].Columns[0] == t.ForeignKeys[1].Columns[0] {
+		return false
+	}
+	return t.PrimaryKey == nil || len(t.PrimaryKey.Parts) == 0 || len(t.PrimaryKey.Parts) == 2
+}
+
+// fields adds the fields of the node. A single-column integer primary key
+// named "id" is the velox default and is omitted.
+func (im *importer) fields(n *node) error {
+	if pk := n.t.PrimaryKey; pk != nil && len(pk.Parts) > 1 {
+		cols := make([]string, 0, len(pk.Parts))
+		for _, p := range pk.Parts {
+			if p.C != nil {
+				cols = append(cols, p.C.Name)
+			}
+		}
+		n.comments = append(n.comments, fmt.Sprintf("The table has a composite primary key (%s) that is not imported.", strings.Join(cols, ", ")))
+	}
+	fks := make(map[string]*node)
+	for _, fk := range n.t.ForeignKeys {
+		if ref := im.ref(fk); ref != nil {
+			fks[fk.Columns[0].Name] = ref
+		}
+	}
+	for _, c := range n.t.Columns {
+		name := fieldName(c.Name)
+		if c == n.id {
+			name = "id"
+		} else if name == "id" {
+			name = n.singular + "_id"
+		}
+		if n.names[name] {
+			return fmt.Errorf("importer: columns of table %q are both mapped to field %q", n.t.Name, name)
+		}
+		n.names[name] = true
+		n.columns[c.Name] = name
+		if c == n.id && n.omitID {
+			continue
+		}
+		ft := im.fieldType(c)
+		if c == n.id {
+			ft = n.idType
+		}
+		if ref, ok := fks[c.Name]; ok {
+			ft = ref.idType
+		}
+		n.fields = append(n.fields, im.field(n, c, name, ft))
+	}
+	return nil
+}
+
+// field renders the field builder of the given column.
+func (im *importer) field(n *node, c *atlas.Column, name string, ft *fieldType) jen.Code {
+	args := []jen.Code{jen.Lit(name)}
+	if ft.arg != nil {
+		args = append(args, ft.arg)
+	}
+	calls := append([]jen.Code(nil), ft.calls...)
+	if ft.raw != "" {
+		calls = append(calls, jen.Id("SchemaType").Call(jen.Map(jen.String()).String().Values(jen.Dict{
+			jen.Qual(dialectPkg, dialectConst(im.dialect)): jen.Lit(ft.raw),
+		})))
+	}
+	if name != c.Name {
+		calls = append(calls, jen.Id("StorageKey").Call(jen.Lit(c.Name)))
+	}
+	if c.Type != nil && c.Type.Null {
+		calls = append(calls, jen.Id("Optional").Call(), jen.Id("Nillable").Call())
+	}
+	if c != n.id && uniqueColumn(n.t, c) {
+		calls = append(calls, jen.Id("Unique").Call())
+	}
+	var ants []jen.Code
+	if c.Default != nil {
+		dc, da := im.defaultValue(n, c, ft)
+		calls = append(calls, dc...)
+		ants = append(ants, da...)
+	}
+	for _, a := range c.Attrs {
+		switch a := a.(type) {
+		case *atlas.Comment:
+			if a.Text != "" {
+				calls = append(calls, jen.Id("Comment").Call(jen.Lit(a.Text)))
+			}
+		case *atlas.Collation:
+			if a.V != "" {
+				ants = append(ants, jen.Qual(sqlschemaPkg, "Collation").Call(jen.Lit(a.V)))
+			}
+		}
+	}
+	if len(ants) > 0 {
+		calls = append(calls, jen.Id("Annotations").Call(ants...))
+	}
+	return chain(jen.Qual(fieldPkg, ft.ctor).Call(args...), calls)
+}
+
+// defaultValue maps the column default to a Go default where possible, and
+// to a database default otherwise.
+func (im *importer) defaultValue(n *node, c *atlas.Column, ft *fieldType) (calls, ants []jen.Code) {
+	var expr string
+	switch d := c.Default.(type) {
+	case *atlas.Literal:
+		if v, ok := literal(d.V, ft.ctor); ok && ft.raw == "" {
+			return []jen.Code{jen.Id("Default").Call(v)}, nil
+		}
+		expr = d.V
+	case *atlas.RawExpr:
+		expr = d.X
+	default:
+		return nil, nil
+	}
+	if err := sqlschema.ValidateExpression(expr); err != nil {
+		n.comments = append(n.comments, fmt.Sprintf("The default value of column %q is not imported: %v.", c.Name, err))
+		return nil, nil
+	}
+	if _, ok := c.Default.(*atlas.Literal); ok {
+		ants = append(ants, jen.Qual(sqlschemaPkg, "Default").Call(jen.Lit(expr)))
+	} else {
+		ants = append(ants, jen.Qual(sqlschemaPkg, "DefaultExpr").Call(jen.Lit(expr)))
+	}
+	if ft.ctor == "Time" && isNow(expr) {
+		calls = append(calls, jen.Id("Default").Call(jen.Qual("time", "Now")))
+	}
+	return calls, ants
+}
+
+// literal converts a database literal to a Go value of the given field type.
+func literal(v, ctor string) (jen.Code, bool) {
+	switch ctor {
+	case "String", "Text", "Enum":
+		if s, ok := unquote(v); ok {
+			return jen.Lit(s), true
+		}
+	case "Bool":
+		switch strings.ToLower(v) {
+		case "true", "1":
+			return jen.True(), true
+		case "false", "0":
+			return jen.False(), true
+		}
+	case "Float", "Float32":
+		if f, err := strconv.ParseFloat(strings.Trim(v, "'"), 64); err == nil {
+			return jen.Lit(f), true
+		}
+	case "Int", "Int8", "Int16", "Int32", "Int64":
+		if i, err := strconv.ParseInt(strings.Trim(v, "'"), 10, 64); err == nil {
+			return jen.Lit(int(i)), true
+		}
+	case "Uint", "Uint8", "Uint16", "Uint32", "Uint64":
+		if i, err := strconv.ParseUint(strings.Trim(v, "'"), 10, 64); err == nil {
+			return jen.Lit(int(i)), true
+		}
+	}
+	return nil, false
+}
+
+// unquote returns the value of a single-quoted SQL string literal.
+func unquote(v string) (string, bool) {
+	if len(v) < 2 || v[0] != '\'' || v[len(v)-1] != '\'' {
+		return "", false
+	}
+	s := v[1 : len(v)-1]
+	if strings.Contains(strings.ReplaceAll(s, "''", ""), "'") {
+		return "", false
+	}
+	return strings.ReplaceAll(s, "''", "'"), true
+}
+
+// isNow reports if the expression evaluates to the current time.
+func isNow(expr string) bool {
+	switch strings.ToLower(strings.Trim(expr, "()")) {
+	case "current_timestamp", "current_timestamp(6", "now", "localtimestamp", "datetime('now'":
+		return true
+	}
+	return false
+}
+
+// fieldType maps the column type to a field builder.
+func (im *importer) fieldType(c *atlas.Column) *fieldType {
+	if c.Type == nil || c.Type.Type == nil {
+		return im.other(c)
+	}
+	switch t := c.Type.Type.(type) {
+	case *atlas.BoolType:
+		return &fieldType{ctor: "Bool"}
+	case *atlas.IntegerType:
+		var ctor string
+		switch strings.ToLower(t.T) {
+		case "tinyint", "int1":
+			ctor = "Int8"
+		case "smallint", "int2", "smallserial":
+			ctor = "Int16"
+		case "mediumint", "int", "integer", "int4", "serial":
+			ctor = "Int32"
+			if im.dialect == dialect.SQLite {
+				ctor = "Int"
+			}
+		case "bigint", "int8", "bigserial", "unsigned big int", "uint64":
+			ctor = "Int64"
+		default:
+			ctor = "Int"
+		}
+		if t.Unsigned {
+			ctor = "U" + strings.ToLower(ctor[:1]) + ctor[1:]
+		}
+		return &fieldType{ctor: ctor}
+	case *atlas.StringType:
+		switch strings.ToLower(t.T) {
+		case "text", "longtext", "mediumtext", "tinytext", "clob":
+			return &fieldType{ctor: "Text"}
+		case "varchar", "character varying", "nvarchar", "varying character":
+			ft := &fieldType{ctor: "String"}
+			if t.Size > 0 {
+				ft.calls = append(ft.calls, jen.Id("MaxLen").Call(jen.Lit(t.Size)))
+			}
+			return ft
+		default:
+			return &fieldType{ctor: "String", raw: im.format(c)}
+		}
+	case *atlas.FloatType:
+		switch strings.ToLower(t.T) {
+		case "real", "float4", "float":
+			return &fieldType{ctor: "Float32"}
+		default:
+			return &fieldType{ctor: "Float"}
+		}
+	case *atlas.DecimalType:
+		return &fieldType{ctor: "Float", raw: im.format(c)}
+	case *atlas.TimeType:
+		switch strings.ToLower(t.T) {
+		case "timestamp", "timestamptz", "timestamp with time zone", "datetime":
+			return &fieldType{ctor: "Time"}
+		default:
+			return &fieldType{ctor: "Time", raw: im.format(c)}
+		}
+	case *atlas.BinaryType:
+		return &fieldType{ctor: "Bytes"}
+	case *atlas.JSONType:
+		return &fieldType{ctor: "JSON", arg: jen.Qual("encoding/json", "RawMessage").Values()}
+	case *atlas.UUIDType:
+		return &fieldType{ctor: "UUID", arg: jen.Qual("github.com/google/uuid", "UUID").Values()}
+	case *atlas.EnumType:
+		if len(t.Values) == 0 {
+			return im.other(c)
+		}
+		values := make([]jen.Code, len(t.Values))
+		for i, v := range t.Values {
+			values[i] = jen.Lit(v)
+		}
+		return &fieldType{ctor: "Enum", calls: []jen.Code{jen.Id("Values").Call(values...)}}
+	default:
+		return im.other(c)
+	}
+}
+
+// other maps an unsupported column type to field.Other, keeping the
+// database type with SchemaType.
+func (im *importer) other(c *atlas.Column) *fieldType {
+	return &fieldType{
+		ctor: "Other",
+		arg:  jen.Op("&").Qual("database/sql", "NullString").Values(),
+		raw:  im.format(c),
+	}
+}
+
+// format returns the database type of the column in the importer dialect,
+// or an empty string if the column has no type.
+func (im *importer) format(c *atlas.Column) string {
+	if c.Type == nil {
+		return ""
+	}
+	if c.Type.Type == nil {
+		return c.Type.Raw
+	}
+	var (
+		s   string
+		err error
+	)
+	switch im.dialect {
+	case dialect.Postgres:
+		s, err = postgres.FormatType(c.Type.Type)
+	case dialect.MySQL:
+		s, err = mysql.FormatType(c.Type.Type)
+	default:
+		s, err = sqlite.FormatType(c.Type.Type)
+	}
+	// Keep the raw type if formatting drops its parameters,
+	// e.g. the precision and scale of a SQLite decimal.
+	if err != nil || s == "" || strings.Contains(c.Type.Raw, "(") && !strings.Contains(s, "(") {
+		return c.Type.Raw
+	}
+	return s
+}
+
+// m2o adds the edges of the foreign keys of the node: an inverse edge
+// holding the key on the node, and an assoc edge on the referenced type.
+func (im *importer) m2o(n *node) {
+	for _, fk := range n.t.ForeignKeys {
+		ref := im.ref(fk)
+		if ref == nil {
+			continue
+		}
+		col := fk.Columns[0]
+		fname := n.columns[col.Name]
+		name := n.unique(strings.TrimSuffix(fname, "_id"), ref.singular)
+		to := ref.unique(inflect.Pluralize(n.singular), inflect.Pluralize(n.singular)+"_"+name)
+		o2o := uniqueColumn(n.t, col)
+		if o2o {
+			to = ref.unique(n.singular, n.singular+"_"+name)
+		}
+		var ants []jen.Code
+		if action := fk.OnDelete; action != "" && action != atlas.NoAction {
+			if c, ok := cascadeConst(action); ok {
+				ants = append(ants, jen.Qual(sqlschemaPkg, "OnDelete").Call(jen.Qual(sqlschemaPkg, c)))
+			}
+		}
+		if ref == n {
+			// Self-reference, e.g. a tree of nodes.
+			calls := []jen.Code{jen.Id("From").Call(jen.Lit(name)), jen.Id("Unique").Call(), jen.Id("Field").Call(jen.Lit(fname))}
+			if len(ants) > 0 {
+				calls = append(calls, jen.Id("Annotations").Call(ants...))
+			}
+			head := jen.Qual(edgePkg, "To").Call(jen.Lit(to), jen.Id(n.name).Dot("Type"))
+			if o2o {
+				head = chain(head, []jen.Code{jen.Id("Unique").Call()})
+			}
+			n.edges = append(n.edges, chain(head, calls))
+			continue
+		}
+		calls := []jen.Code{jen.Id("Ref").Call(jen.Lit(to)), jen.Id("Unique").Call(), jen.Id("Field").Call(jen.Lit(fname))}
+		if col.Type != nil && !col.Type.Null {
+			calls = append(calls, jen.Id("Required").Call())
+		}
+		n.edges = append(n.edges, chain(jen.Qual(edgePkg, "From").Call(jen.Lit(name), jen.Id(ref.name).Dot("Type")), calls))
+		var inverse []jen.Code
+		if o2o {
+			inverse = append(inverse, jen.Id("Unique").Call())
+		}
+		if len(ants) > 0 {
+			inverse = append(inverse, jen.Id("Annotations").Call(ants...))
+		}
+		ref.edges = append(ref.edges, chain(jen.Qual(edgePkg, "To").Call(jen.Lit(to), jen.Id(n.name).Dot("Type")), inverse))
+	}
+}
+
+// m2m adds the edges of a join table.
+func (im *importer) m2m(t *atlas.Table) {
+	// The owner of the edge is the type referenced by the first column.
+	fa, fb := t.ForeignKeys[0], t.ForeignKeys[1]
+	if fa.Columns[0] == t.Columns[1] {
+		fa, fb = fb, fa
+	}
+	a, b := im.ref(fa), im.ref(fb)
+	ca, cb := fa.Columns[0].Name, fb.Columns[0].Name
+	storage := jen.Id("StorageKey").Call(
+		jen.Qual(edgePkg, "Table").Call(jen.Lit(t.Name)),
+		jen.Qual(edgePkg, "Columns").Call(jen.Lit(ca), jen.Lit(cb)),
+	)
+	if a == b {
+		// Self-referencing M2M, e.g. followers and following.
+		// The edge names are derived from the join columns.
+		from := a.unique(inflect.Pluralize(strings.TrimSuffix(fieldName(ca), "_id")), inflect.Pluralize(a.singular)+"_"+t.Name)
+		to := a.unique(inflect.Pluralize(strings.TrimSuffix(fieldName(cb), "_id")), t.Name)
+		a.edges = append(a.edges, chain(jen.Qual(edgePkg, "To").Call(jen.Lit(to), jen.Id(a.name).Dot("Type")), []jen.Code{
+			storage, jen.Id("From").Call(jen.Lit(from)),
+		}))
+		return
+	}
+	to := a.unique(inflect.Pluralize(b.singular), t.Name)
+	from := b.unique(inflect.Pluralize(a.singular), t.Name)
+	a.edges = append(a.edges, chain(jen.Qual(edgePkg, "To").Call(jen.Lit(to), jen.Id(b.name).Dot("Type")), []jen.Code{storage}))
+	b.edges = append(b.edges, chain(jen.Qual(edgePkg, "From").Call(jen.Lit(from), jen.Id(a.name).Dot("Type")), []jen.Code{
+		jen.Id("Ref").Call(jen.Lit(to)),
+	}))
+}
+
+// indexes adds the indexes of the node. Single-column unique indexes are
+// imported as unique fields, and expression indexes are skipped.
+func (im *importer) indexes(n *node) {
+	for _, idx := range n.t.Indexes {
+		if idx.Unique && len(idx.Parts) == 1 && idx.Parts[0].C != nil {
+			continue
+		}
+		names := make([]jen.Code, 0, len(idx.Parts))
+		for _, p := range idx.Parts {
+			if p.C == nil {
+				n.comments = append(n.comments, fmt.Sprintf("The expression index %q is not imported.", idx.Name))
+				names = nil
+				break
+			}
+			names = append(names, jen.Lit(n.columns[p.C.Name]))
+		}
+		if len(names) == 0 {
+			continue
+		}
+		var calls []jen.Code
+		if idx.Unique {
+			calls = append(calls, jen.Id("Unique").Call())
+		}
+		if idx.Name != "" && !strings.HasPrefix(idx.Name, "sqlite_autoindex_") {
+			calls = append(calls, jen.Id("StorageKey").Call(jen.Lit(idx.Name)))
+		}
+		n.indexes = append(n.indexes, chain(jen.Qual(indexPkg, "Fields").Call(names...), calls))
+	}
+}
+
+// annotations returns the type annotations of the node.
+func (im *importer) annotations(n *node) []jen.Code {
+	ants := []jen.Code{jen.Qual(sqlschemaPkg, "Table").Call(jen.Lit(n.t.Name))}
+	checks := make(jen.Dict)
+	for i, a := range n.t.Attrs {
+		c, ok := a.(*atlas.Check)
+		if !ok {
+			continue
+		}
+		if err := sqlschema.ValidateExpression(c.Expr); err != nil {
+			n.comments = append(n.comments, fmt.Sprintf("The check constraint %q is not imported: %v.", c.Name, err))
+			continue
+		}
+		name := c.Name
+		if name == "" {
+			name = fmt.Sprintf("%s_check%d", n.t.Name, i)
+		}
+		checks[jen.Lit(name)] = jen.Lit(c.Expr)
+	}
+	if len(checks) > 0 {
+		ants = append(ants, jen.Qual(sqlschemaPkg, "Checks").Call(jen.Map(jen.String()).String().Values(checks)))
+	}
+	return ants
+}
+
+// render renders the schema file of the node.
+func (im *importer) render(n *node) ([]byte, error) {
+	f := jen.NewFile(im.cfg.pkg)
+	f.ImportName(veloxPkg, "velox")
+	f.HeaderComment(fmt.Sprintf("Imported from table %q of an existing database.", n.t.Name))
+	f.Commentf("%s holds the schema definition for the %s entity.", n.name, n.name)
+	for _, c := range n.comments {
+		f.Comment("")
+		f.Comment(c)
+	}
+	f.Type().Id(n.name).Struct(jen.Qual(veloxPkg, "Schema"))
+	method := func(name, typ string, items []jen.Code) {
+		if len(items) == 0 {
+			return
+		}
+		f.Line()
+		f.Commentf("%s of the %s.", name, n.name)
+		f.Func().Params(jen.Id(n.name)).Id(name).Params().Index().Qual(veloxPkg, typ).Block(
+			jen.Return(jen.Index().Qual(veloxPkg, typ).Custom(jen.Options{Open: "{", Close: "}", Separator: ",", Multi: true}, items...)),
+		)
+	}
+	method("Fields", "Field", n.fields)
+	method("Edges", "Edge", n.edges)
+	method("Indexes", "Index", n.indexes)
+	f.Line()
+	f.Commentf("Annotations of the %s.", n.name)
+	ants := im.annotations(n)
+	f.Func().Params(jen.Id(n.name)).Id("Annotations").Params().Index().Qual(schemaPkg, "Annotation").Block(
+		jen.Return(jen.Index().Qual(schemaPkg, "Annotation").Custom(jen.Options{Open: "{", Close: "}", Separator: ",", Multi: true}, ants...)),
+	)
+	var buf strings.Builder
+	if err := f.Render(&buf); err != nil {
+		return nil, fmt.Errorf("importer: render %s: %w", n.file, err)
+	}
+	return []byte(buf.String()), nil
+}
+
+// unique returns the first name that is not used by a field or an edge of
+// the node, and marks it as used.
+func (n *node) unique(names ...string) string {
+	for _, name := range names {
+		if name != "" && !n.names[name] {
+			n.names[name] = true
+			return name
+		}
+	}
+	base := names[len(names)-1]
+	for i := 1; ; i++ {
+		if name := base + strconv.Itoa(i); !n.names[name] {
+			n.names[name] = true
+			return name
+		}
+	}
+}
+
+// chain renders a builder chain with one call per line.
+func chain(head *jen.Statement, calls []jen.Code) *jen.Statement {
+	for _, c := range calls {
+		head = head.Op(".").Line().Add(c)
+	}
+	return head
+}
+
+// uniqueColumn reports if the column is unique on its own.
+func uniqueColumn(t *atlas.Table, c *atlas.Column) bool {
+	for _, idx := range t.Indexes {
+		if idx.Unique && len(idx.Parts) == 1 && idx.Parts[0].C == c {
+			return true
+		}
+	}
+	return false
+}
+
+// fieldName converts a column name to a valid field name.
+func fieldName(column string) string {
+	var (
+		b    strings.Builder
+		prev rune
+	)
+	for _, r := range column {
+		switch {
+		case unicode.IsUpper(r):
+			if unicode.IsLower(prev) || unicode.IsDigit(prev) {
+				b.WriteByte('_')
+			}
+			b.WriteRune(unicode.ToLower(r))
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			b.WriteRune(r)
+		default:
+			b.WriteByte('_')
+		}
+		prev = r
+	}
+	name := strings.Trim(b.String(), "_")
+	if name == "" || unicode.IsDigit(rune(name[0])) {
+		name = "f_" + name
+	}
+	return name
+}
+
+// dialectConst returns the name of the dialect constant.
+func dialectConst(name string) string {
+	switch name {
+	case dialect.Postgres:
+		return "Postgres"
+	case dialect.MySQL:
+		return "MySQL"
+	default:
+		return "SQLite"
+	}
+}
+
+// cascadeConst returns the name of the sqlschema constant of the action.
+func cascadeConst(action atlas.ReferenceOption) (string, bool) {
+	switch sqlschema.CascadeAction(action) {
+	case sqlschema.Cascade, sqlschema.SetNull, sqlschema.Restrict, sqlschema.SetDefault:
+		return sqlschema.CascadeAction(action).ConstName(), true
+	}
+	return "", false
+}
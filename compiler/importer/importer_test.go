@@ -0,0 +1,156 @@
+package importer
+
+import (
+	"context"
+	"database/sql"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"testing"
+
+	atlas "ariga.io/atlas/sql/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	_ "modernc.org/sqlite"
+
+	"github.com/syssam/velox/dialect"
+	entsql "github.com/syssam/velox/dialect/sql"
+)
+
+const dump = `
+CREATE TABLE users (
+	id integer NOT NULL PRIMARY KEY AUTOINCREMENT,
+	email varchar(100) NOT NULL UNIQUE,
+	name text NOT NULL DEFAULT 'anonymous',
+	age integer NULL CHECK (age >= 0),
+	created_at datetime NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	balance decimal(10,2) NOT NULL DEFAULT 0,
+	location point NULL
+);
+CREATE TABLE posts (
+	id integer NOT NULL PRIMARY KEY AUTOINCREMENT,
+	title varchar(255) NOT NULL,
+	published boolean NOT NULL DEFAULT false,
+	author_id integer NOT NULL,
+	parent_id integer NULL,
+	CONSTRAINT posts_author FOREIGN KEY (author_id) REFERENCES users (id) ON DELETE CASCADE,
+	FOREIGN KEY (parent_id) REFERENCES posts (id)
+);
+CREATE INDEX posts_title_published ON posts (title, published);
+CREATE TABLE tags (
+	id integer NOT NULL PRIMARY KEY AUTOINCREMENT,
+	label text NOT NULL
+);
+CREATE TABLE post_tags (
+	post_id integer NOT NULL,
+	tag_id integer NOT NULL,
+	PRIMARY KEY (post_id, tag_id),
+	FOREIGN KEY (post_id) REFERENCES posts (id),
+	FOREIGN KEY (tag_id) REFERENCES tags (id)
+);
+CREATE TABLE schema_migrations (version text NOT NULL PRIMARY KEY);
+`
+
+func openDump(t *testing.T) dialect.Driver {
+	t.Helper()
+	db, err := sql.Open("sqlite", "file:"+filepath.Join(t.TempDir(), "legacy.db")+"?_pragma=foreign_keys(1)")
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+	require.NoError(t, LoadDump(context.Background(), db, dump))
+	return entsql.OpenDB(dialect.SQLite, db)
+}
+
+func TestImport(t *testing.T) {
+	drv := openDump(t)
+	dir := t.TempDir()
+	require.NoError(t, Import(context.Background(), drv, dir, WithExcludeTables("schema_migrations")))
+
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	var names []string
+	for _, e := range entries {
+		names = append(names, e.Name())
+	}
+	assert.Equal(t, []string{"post.go", "tag.go", "user.go"}, names, "join and excluded tables are not imported as types")
+
+	files := make(map[string]string)
+	for _, name := range names {
+		b, err := os.ReadFile(filepath.Join(dir, name))
+		require.NoError(t, err)
+		_, err = parser.ParseFile(token.NewFileSet(), name, b, parser.AllErrors)
+		require.NoError(t, err, string(b))
+		files[name] = string(b)
+	}
+
+	user := files["user.go"]
+	assert.Contains(t, user, "package schema")
+	assert.Contains(t, user, "type User struct {\n\tvelox.Schema\n}")
+	assert.NotContains(t, user, `field.Int("id")`, "the default id is omitted")
+	assert.Contains(t, user, "field.String(\"email\").\n\t\t\tMaxLen(100).\n\t\t\tUnique()")
+	assert.Contains(t, user, "field.Text(\"name\").\n\t\t\tDefault(\"anonymous\")")
+	assert.Contains(t, user, "field.Int(\"age\").\n\t\t\tOptional().\n\t\t\tNillable()")
+	assert.Contains(t, user, "Default(time.Now).\n\t\t\tAnnotations(sqlschema.DefaultExpr(\"CURRENT_TIMESTAMP\"))")
+	assert.Contains(t, user, "field.Float(\"balance\").\n\t\t\tSchemaType(map[string]string{dialect.SQLite: \"decimal(10,2)\"})")
+	assert.Contains(t, user, "field.Other(\"location\", &sql.NullString{}).\n\t\t\tSchemaType(map[string]string{dialect.SQLite: \"point\"})")
+	assert.Contains(t, user, "edge.To(\"posts\", Post.Type).\n\t\t\tAnnotations(sqlschema.OnDelete(sqlschema.Cascade))")
+	assert.Contains(t, user, `sqlschema.Table("users")`)
+	assert.Contains(t, user, `sqlschema.Checks(map[string]string{"users_check1": "(age >= 0)"})`)
+
+	post := files["post.go"]
+	assert.Contains(t, post, "field.String(\"title\").\n\t\t\tMaxLen(255),")
+	assert.Contains(t, post, "field.Bool(\"published\").\n\t\t\tDefault(false)")
+	assert.Contains(t, post, "edge.From(\"author\", User.Type).\n\t\t\tRef(\"posts\").\n\t\t\tUnique().\n\t\t\tField(\"author_id\").\n\t\t\tRequired()")
+	assert.Contains(t, post, "edge.To(\"posts\", Post.Type).\n\t\t\tFrom(\"parent\").\n\t\t\tUnique().\n\t\t\tField(\"parent_id\")")
+	assert.Contains(t, post, "edge.To(\"tags\", Tag.Type).\n\t\t\tStorageKey(edge.Table(\"post_tags\"), edge.Columns(\"post_id\", \"tag_id\"))")
+	assert.Contains(t, post, "index.Fields(\"title\", \"published\").\n\t\t\tStorageKey(\"posts_title_published\")")
+
+	tag := files["tag.go"]
+	assert.Contains(t, tag, "edge.From(\"posts\", Post.Type).\n\t\t\tRef(\"tags\")")
+
+	// Existing files are not overwritten.
+	require.ErrorContains(t, Import(context.Background(), drv, dir, WithExcludeTables("schema_migrations")), "already exists")
+}
+
+func TestGenerate_Options(t *testing.T) {
+	drv := openDump(t)
+	s, err := Inspect(context.Background(), drv, WithTables("tags"))
+	require.NoError(t, err)
+	files, err := Generate(s, dialect.SQLite, WithPackage("models"))
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+	assert.Contains(t, string(files["tag.go"]), "package models")
+	assert.NotContains(t, string(files["tag.go"]), "Edges()")
+}
+
+func TestGenerate_UntypedColumn(t *testing.T) {
+	id := &atlas.Column{Name: "id", Type: &atlas.ColumnType{Type: &atlas.IntegerType{T: "integer"}}}
+	events := &atlas.Table{
+		Name: "events",
+		Columns: []*atlas.Column{
+			id,
+			{Name: "payload"},
+			{Name: "raw", Type: &atlas.ColumnType{Raw: "blob_v2"}},
+		},
+	}
+	events.PrimaryKey = &atlas.Index{Parts: []*atlas.IndexPart{{C: id}}}
+	files, err := Generate(&atlas.Schema{Tables: []*atlas.Table{events}}, dialect.SQLite)
+	require.NoError(t, err)
+	event := string(files["event.go"])
+	_, err = parser.ParseFile(token.NewFileSet(), "event.go", event, parser.AllErrors)
+	require.NoError(t, err, event)
+	assert.Contains(t, event, "field.Other(\"payload\", &sql.NullString{}),")
+	assert.Contains(t, event, "field.Other(\"raw\", &sql.NullString{}).\n\t\t\tSchemaType(map[string]string{dialect.SQLite: \"blob_v2\"})")
+}
+
+func TestFieldName(t *testing.T) {
+	for column, want := range map[string]string{
+		"name":       "name",
+		"UserID":     "user_id",
+		"createdAt":  "created_at",
+		"first name": "first_name",
+		"2fa":        "f_2fa",
+	} {
+		assert.Equal(t, want, fieldName(column), column)
+	}
+}
@@ -24,6 +24,7 @@ type Schema struct {
 	Hooks        []*Position    `json:"hooks,omitempty"`
 	Interceptors []*Position    `json:"interceptors,omitempty"`
 	Policy       []*Position    `json:"policy,omitempty"`
+	Validators   []*Position    `json:"validators,omitempty"`
 	Annotations  map[string]any `json:"annotations,omitempty"`
 }
 
@@ -51,6 +52,8 @@ type Field struct {
 	UpdateDefault    bool                    `json:"update_default,omitempty"`
 	Immutable        bool                    `json:"immutable,omitempty"`
 	Validators       int                     `json:"validators,omitempty"`
+	Constraints      *field.Constraints      `json:"constraints,omitempty"`
+	Generated        *field.Generated        `json:"generated,omitempty"`
 	StorageKey       string                  `json:"storage_key,omitempty"`
 	Position         *Position               `json:"position,omitempty"`
 	Sensitive        bool                    `json:"sensitive,omitempty"`
@@ -70,6 +73,7 @@ type Edge struct {
 	RefName     string                 `json:"ref_name,omitempty"`
 	Ref         *Edge                  `json:"ref,omitempty"`
 	Through     *struct{ N, T string } `json:"through,omitempty"`
+	OneOf       []string               `json:"one_of,omitempty"`
 	Unique      bool                   `json:"unique,omitempty"`
 	Inverse     bool                   `json:"inverse,omitempty"`
 	Required    bool                   `json:"required,omitempty"`
@@ -105,6 +109,7 @@ func NewEdge(ed *edge.Descriptor) (*Edge, error) {
 		Immutable:   ed.Immutable,
 		RefName:     ed.RefName,
 		Through:     ed.Through,
+		OneOf:       ed.OneOf,
 		StorageKey:  ed.StorageKey,
 		Comment:     ed.Comment,
 		Annotations: make(map[string]any),
@@ -142,6 +147,8 @@ func NewField(fd *field.Descriptor) (*Field, error) {
 		Immutable:        fd.Immutable,
 		StorageKey:       fd.StorageKey,
 		Validators:       len(fd.Validators),
+		Constraints:      fd.Constraints,
+		Generated:        fd.Generated,
 		Sensitive:        fd.Sensitive,
 		SchemaType:       fd.SchemaType,
 		Annotations:      make(map[string]any),
@@ -234,6 +241,9 @@ func MarshalSchema(iface velox.Interface) (b []byte, err error) {
 	if err := s.loadPolicy(iface); err != nil {
 		return nil, fmt.Errorf("schema %q: %w", s.Name, err)
 	}
+	if err := s.loadValidators(iface); err != nil {
+		return nil, fmt.Errorf("schema %q: %w", s.Name, err)
+	}
 	return json.Marshal(s)
 }
 
@@ -377,6 +387,20 @@ func (s *Schema) loadInterceptors(iface velox.Interface) error {
 	return nil
 }
 
+func (s *Schema) loadValidators(iface velox.Interface) error {
+	validators, err := safeValidators(iface)
+	if err != nil {
+		return err
+	}
+	for i := range validators {
+		s.Validators = append(s.Validators, &Position{
+			Index:   i,
+			MixedIn: false,
+		})
+	}
+	return nil
+}
+
 func (s *Schema) loadPolicy(iface velox.Interface) error {
 	policy, err := safePolicy(iface)
 	if err != nil {
@@ -500,6 +524,19 @@ func safeInterceptors(iface interface{ Interceptors() []velox.Interceptor }) (in
 	return iface.Interceptors(), nil
 }
 
+// safeValidators wraps the schema.Validators method with recover to ensure no panics in marshaling.
+func safeValidators(iface interface {
+	Validators() []velox.EntityValidator
+}) (validators []velox.EntityValidator, err error) {
+	defer func() {
+		if v := recover(); v != nil {
+			err = fmt.Errorf("%T.Validators panics: %v", iface, v)
+			validators = nil
+		}
+	}()
+	return iface.Validators(), nil
+}
+
 // safePolicy wraps the schema.Policy method with recover to ensure no panics in marshaling.
 func safePolicy(iface interface{ Policy() velox.Policy }) (policy velox.Policy, err error) {
 	defer func() {
@@ -441,6 +441,13 @@ func (WithMixin) Policy() velox.Policy {
 	return BoringPolicy{}
 }
 
+func (WithMixin) Validators() []velox.EntityValidator {
+	return []velox.EntityValidator{
+		func(context.Context, velox.EntityState) error { return nil },
+		func(context.Context, velox.EntityState) error { return nil },
+	}
+}
+
 func TestMarshalMixin(t *testing.T) {
 	d := WithMixin{}
 	buf, err := MarshalSchema(d)
@@ -519,4 +526,11 @@ func TestMarshalMixin(t *testing.T) {
 		require.True(t, schema.Policy[0].MixedIn)
 		require.False(t, schema.Policy[1].MixedIn)
 	})
+
+	t.Run("Validators", func(t *testing.T) {
+		require.Len(t, schema.Validators, 2)
+		require.False(t, schema.Validators[0].MixedIn)
+		require.Equal(t, 0, schema.Validators[0].Index)
+		require.Equal(t, 1, schema.Validators[1].Index)
+	})
 }
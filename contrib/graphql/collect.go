@@ -115,10 +115,11 @@ func gqlCollectRelayEdgeOpts(
 	// Extract pagination args from GraphQL field arguments.
 	args := field.ArgumentMap(opCtx.Variables)
 
-	// Cursor args mean the resolver's Paginate call must handle this edge.
+	// Cursor and search args mean the resolver's Paginate call must handle this edge.
 	_, hasAfter := args["after"]
 	_, hasBefore := args["before"]
-	if hasAfter || hasBefore {
+	_, hasSearch := args["search"]
+	if hasAfter || hasBefore || hasSearch {
 		return nil, true
 	}
 
@@ -253,6 +254,7 @@ func (g *Generator) genEntityCollectionInit(f *jen.File, t *gen.Type, metaVar st
 
 	// Collect edges.
 	filteredEdges := g.filterEdges(t.Edges, SkipType)
+	oneOfs := g.oneOfEdges(t)
 
 	f.Func().Id("init").Params().BlockFunc(func(grp *jen.Group) {
 		// FieldColumns: map GraphQL field name → DB column name.
@@ -265,7 +267,7 @@ func (g *Generator) genEntityCollectionInit(f *jen.File, t *gen.Type, metaVar st
 		})
 
 		// Edges: map GraphQL edge name → EdgeMeta.
-		if len(filteredEdges) > 0 {
+		if len(filteredEdges)+len(oneOfs) > 0 {
 			grp.Id(metaVar).Dot("Edges").Op("=").Map(jen.String()).Qual(runtimePkgPath, "EdgeMeta").ValuesFunc(func(d *jen.Group) {
 				for _, e := range filteredEdges {
 					edgeName := camel(e.Name)
@@ -288,6 +290,17 @@ func (g *Generator) genEntityCollectionInit(f *jen.File, t *gen.Type, metaVar st
 						jen.Id("Inverse"):   jen.Lit(e.Inverse),
 					})
 				}
+				// Polymorphic edges are loaded from the type and ID columns.
+				for _, oe := range oneOfs {
+					d.Lit(camel(oe.Name)).Op(":").Values(jen.Dict{
+						jen.Id("Name"):   jen.Lit(oe.Name),
+						jen.Id("Unique"): jen.True(),
+						jen.Id("FKColumns"): jen.Index().String().Values(
+							jen.Lit(oe.TypeField.StorageKey()),
+							jen.Lit(oe.IDField.StorageKey()),
+						),
+					})
+				}
 			})
 		}
 	})
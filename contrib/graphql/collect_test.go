@@ -314,6 +314,45 @@ func TestCollectFields_RelayLastArg(t *testing.T) {
 	assert.Equal(t, 6, *cfg.Limit, "should be last+1 for hasPreviousPage probe")
 }
 
+func TestCollectFields_RelaySearchArgSkipsEagerLoad(t *testing.T) {
+	searchField := &ast.Field{
+		Name:  "posts",
+		Alias: "posts",
+		Arguments: ast.ArgumentList{
+			{
+				Name: "search",
+				Value: &ast.Value{
+					Kind: ast.StringValue,
+					Raw:  "velox",
+				},
+			},
+		},
+		Definition: &ast.FieldDefinition{
+			Name: "posts",
+			Arguments: ast.ArgumentDefinitionList{
+				{
+					Name: "search",
+					Type: ast.NamedType("String", nil),
+				},
+			},
+		},
+	}
+	selections := ast.SelectionSet{searchField}
+	ctx := newGQLContext(t, selections)
+
+	edges := map[string]runtime.EdgeMeta{
+		"posts": {Name: "posts", Target: "posts", Relay: true, FKColumns: []string{"user_posts"}},
+	}
+	q := runtime.NewQueryBase(nil, "users", []string{"id"}, "id", nil, "User")
+
+	err := runtime.CollectFields(ctx, q, map[string]string{}, edges)
+	require.NoError(t, err)
+
+	// The search is applied by the resolver's Paginate call, not by eager-loading.
+	assert.Empty(t, q.Edges, "Relay edge with a search arg should not be eager-loaded")
+	assert.Contains(t, q.Ctx.Fields, "user_posts", "FK columns should still be selected")
+}
+
 func TestCollectFields_EmptySelections(t *testing.T) {
 	selections := ast.SelectionSet{}
 	ctx := newGQLContext(t, selections)
@@ -167,6 +167,9 @@ func (g *Generator) genListEdgeMethod(f *jen.File, _ *gen.Type, e *gen.Edge, typ
 // where.Filter is threaded through WithXxxFilter — a method value of shape
 // `func() (predicate.X, error)` matching the closure parameter directly.
 // Paginate's body invokes the closure once and propagates any error.
+//
+// `search *string` is added when the target has a full-text index, matching
+// the `search:` argument of the edge SDL. It is threaded through WithXxxSearch.
 func (g *Generator) genConnectionEdgeMethod(f *jen.File, _ *gen.Type, e *gen.Edge, typeName string) {
 	edgePascal := pascal(e.Name)
 	targetType := g.graphqlTypeName(e.Type)
@@ -181,6 +184,7 @@ func (g *Generator) genConnectionEdgeMethod(f *jen.File, _ *gen.Type, e *gen.Edg
 	edgeOrErr := edgePascal + "OrErr"
 
 	wantsWhere := g.config.WhereInputs && g.hasWhereInput(e)
+	wantsSearch := e.Type.FullTextIndex() != nil
 	// When the TARGET entity is graphql.MultiOrder(), its WithXxxOrder takes a
 	// []*XxxOrder and returns (opt, error), and BuildXxxConnection takes the
 	// slice — so the edge method's orderBy parameter and order wiring must
@@ -205,6 +209,9 @@ func (g *Generator) genConnectionEdgeMethod(f *jen.File, _ *gen.Type, e *gen.Edg
 			whereInputName := e.Type.Name + "WhereInput"
 			params.Id("where").Op("*").Qual(filterPkg, whereInputName)
 		}
+		if wantsSearch {
+			params.Id("search").Op("*").String()
+		}
 	}).Params(
 		jen.Op("*").Id(connName),
 		jen.Error(),
@@ -213,6 +220,9 @@ func (g *Generator) genConnectionEdgeMethod(f *jen.File, _ *gen.Type, e *gen.Edg
 		if wantsWhere {
 			fastCondition = fastCondition.Op("&&").Id("where").Op("==").Nil()
 		}
+		if wantsSearch {
+			fastCondition = fastCondition.Op("&&").Id("search").Op("==").Nil()
+		}
 		fastCondition = fastCondition.Op("&&").Id("after").Op("==").Nil().
 			Op("&&").Id("before").Op("==").Nil()
 		body.If(
@@ -253,6 +263,14 @@ func (g *Generator) genConnectionEdgeMethod(f *jen.File, _ *gen.Type, e *gen.Edg
 				),
 			)
 		}
+		if wantsSearch {
+			body.If(jen.Id("search").Op("!=").Nil()).Block(
+				jen.Id("opts").Op("=").Append(
+					jen.Id("opts"),
+					jen.Id("With"+targetType+"Search").Call(jen.Id("search")),
+				),
+			)
+		}
 		body.Return(
 			jen.Id("m").Dot(queryMethod).Call().Assert(jen.Id(paginatable)).Dot("Paginate").Call(
 				jen.Id("ctx"),
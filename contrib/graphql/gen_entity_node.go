@@ -146,7 +146,8 @@ func (g *Generator) genNodeShared() *jen.File {
 
 	// Client.Noder — registry-based dispatch
 	f.Comment("Noder returns a Node by its ID using the registered node resolvers.")
-	f.Comment("It tries each registered resolver until one succeeds.")
+	f.Comment("Prefixed IDs (e.g., \"usr_...\") are dispatched to the resolver of their type;")
+	f.Comment("otherwise, it tries each registered resolver until one succeeds.")
 	f.Comment("Not-found errors are skipped; all other errors (e.g., database failures) are returned immediately.")
 	f.Func().Params(
 		jen.Id("c").Add(g.clientPtrType()),
@@ -158,7 +159,7 @@ func (g *Generator) genNodeShared() *jen.File {
 		jen.Error(),
 	).Block(
 		jen.Id("ctx").Op("=").Qual(runtimePkgPath, "WithConfigContext").Call(jen.Id("ctx"), jen.Id("c").Dot("RuntimeConfig").Call()),
-		jen.For(jen.List(jen.Id("_"), jen.Id("resolver")).Op(":=").Range().Qual(runtimePkgPath, "NodeResolvers").Call()).Block(
+		jen.For(jen.List(jen.Id("_"), jen.Id("resolver")).Op(":=").Range().Qual(runtimePkgPath, "NodeResolversFor").Call(jen.Id("id"))).Block(
 			jen.List(jen.Id("result"), jen.Id("err")).Op(":=").Id("resolver").Dot("Resolve").Call(jen.Id("ctx"), jen.Id("id")),
 			jen.If(jen.Id("err").Op("!=").Nil()).Block(
 				// Only skip not-found errors; return real errors immediately
@@ -192,10 +193,9 @@ func (g *Generator) genNodeShared() *jen.File {
 		jen.Error(),
 	).Block(
 		jen.Id("ctx").Op("=").Qual(runtimePkgPath, "WithConfigContext").Call(jen.Id("ctx"), jen.Id("c").Dot("RuntimeConfig").Call()),
-		jen.Id("resolvers").Op(":=").Qual(runtimePkgPath, "NodeResolvers").Call(),
 		jen.Id("nodes").Op(":=").Make(jen.Index().Id("Noder"), jen.Len(jen.Id("ids"))),
 		jen.For(jen.List(jen.Id("i"), jen.Id("id")).Op(":=").Range().Id("ids")).Block(
-			jen.For(jen.List(jen.Id("_"), jen.Id("resolver")).Op(":=").Range().Id("resolvers")).Block(
+			jen.For(jen.List(jen.Id("_"), jen.Id("resolver")).Op(":=").Range().Qual(runtimePkgPath, "NodeResolversFor").Call(jen.Id("id"))).Block(
 				jen.List(jen.Id("result"), jen.Id("err")).Op(":=").Id("resolver").Dot("Resolve").Call(jen.Id("ctx"), jen.Id("id")),
 				jen.If(jen.Id("err").Op("!=").Nil()).Block(
 					jen.If(jen.Qual(runtimePkgPath, "IsNotFound").Call(jen.Id("err"))).Block(
@@ -118,6 +118,11 @@ func (g *Generator) genEntityPagination(t *gen.Type) *jen.File {
 				jen.Id("q").Dot("Where").Call(jen.Id("p")),
 			)
 		})
+		if t.FullTextIndex() != nil {
+			grp.If(jen.Id("cfg").Dot("Search").Op("!=").Nil()).Block(
+				jen.Id("q").Dot("Where").Call(jen.Qual(subEntityPkg, "Search").Call(jen.Op("*").Id("cfg").Dot("Search"))),
+			)
+		}
 
 		// Count total BEFORE applying cursor predicates so TotalCount reflects
 		// the full filtered dataset (matching Ent's pager semantics) — not the
@@ -448,10 +453,13 @@ func (g *Generator) genModelPaginationDefs(f *jen.File, t *gen.Type) {
 	if multiOrder {
 		orderField = jen.Id("Order").Index().Op("*").Id(orderName)
 	}
-	f.Type().Id(pagerConfigName).Struct(
-		orderField,
-		jen.Id("Filter").Func().Params().Params(jen.Qual(predicatePkg, t.Name), jen.Error()),
-	)
+	f.Type().Id(pagerConfigName).StructFunc(func(grp *jen.Group) {
+		grp.Add(orderField)
+		grp.Id("Filter").Func().Params().Params(jen.Qual(predicatePkg, t.Name), jen.Error())
+		if t.FullTextIndex() != nil {
+			grp.Id("Search").Op("*").String()
+		}
+	})
 	f.Line()
 
 	// --- OrderField struct ---
@@ -502,6 +510,11 @@ func (g *Generator) genModelPaginationDefs(f *jen.File, t *gen.Type) {
 	// --- WithFilter function ---
 	g.genModelWithFilter(f, t, typeName, optName, pagerConfigName)
 
+	// --- WithSearch function ---
+	if t.FullTextIndex() != nil {
+		g.genModelWithSearch(f, typeName, optName, pagerConfigName)
+	}
+
 	// --- BuildConnection helper ---
 	// Shared "assemble a connection from a node slice" helper. Used by BOTH
 	// the slow path (query.XxxQuery.Paginate, which does a fresh DB query)
@@ -696,6 +709,22 @@ func (g *Generator) genModelWithFilter(f *jen.File, t *gen.Type, typeName, optNa
 	f.Line()
 }
 
+// genModelWithSearch generates the WithXxxSearch function of entities with a
+// full-text index. The query is matched by the Search predicate of the entity
+// in Paginate; a nil query matches all nodes.
+func (g *Generator) genModelWithSearch(f *jen.File, typeName, optName, pagerConfigName string) {
+	optSearchName := "With" + typeName + "Search"
+	f.Commentf("%s sets a full-text search query for %s pagination.", optSearchName, typeName)
+	f.Func().Id(optSearchName).Params(
+		jen.Id("query").Op("*").String(),
+	).Id(optName).Block(
+		jen.Return(jen.Func().Params(jen.Id("cfg").Op("*").Id(pagerConfigName)).Block(
+			jen.Id("cfg").Dot("Search").Op("=").Id("query"),
+		)),
+	)
+	f.Line()
+}
+
 // genPaginatableInterface generates the Paginatable interface for an entity.
 // Edge methods use type assertion to call Paginate on querier results:
 //
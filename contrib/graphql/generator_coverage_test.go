@@ -11,6 +11,8 @@ import (
 	"github.com/stretchr/testify/require"
 
 	entgen "github.com/syssam/velox/compiler/gen"
+	"github.com/syssam/velox/compiler/load"
+	"github.com/syssam/velox/dialect/sqlschema"
 	"github.com/syssam/velox/schema/field"
 )
 
@@ -553,6 +555,45 @@ func TestGenerator_GenMutationType_CreateOnly(t *testing.T) {
 	assert.NotContains(t, mutation, "deleteEvent")
 }
 
+func TestGenerator_GenMutationType_View(t *testing.T) {
+	mutationAnnotation := map[string]any{
+		AnnotationName: Annotation{
+			Mutations:       mutCreate | mutUpdate,
+			HasMutationsSet: true,
+		},
+	}
+
+	view, err := entgen.NewType(&entgen.Config{Package: "example/ent"}, &load.Schema{
+		Name:        "PostStats",
+		View:        true,
+		Fields:      []*load.Field{{Name: "posts", Info: &field.TypeInfo{Type: field.TypeInt}}},
+		Annotations: mutationAnnotation,
+	})
+	require.NoError(t, err)
+	user := &entgen.Type{
+		Name:        "User",
+		ID:          &entgen.Field{Name: "id", Type: &field.TypeInfo{Type: field.TypeInt64}},
+		Fields:      []*entgen.Field{{Name: "name", Type: &field.TypeInfo{Type: field.TypeString}}},
+		Annotations: mutationAnnotation,
+	}
+	g := &entgen.Graph{
+		Config: &entgen.Config{Package: "example/ent"},
+		Nodes:  []*entgen.Type{user, view},
+	}
+
+	gen := NewGenerator(g, Config{
+		Package:   "graphql",
+		Mutations: true,
+		RelaySpec: true,
+	})
+
+	assert.False(t, gen.wantsMutationCreate(view), "views are read-only")
+	assert.False(t, gen.wantsMutationUpdate(view), "views are read-only")
+	mutation := gen.genMutationType()
+	assert.Contains(t, mutation, "createUser")
+	assert.NotContains(t, mutation, "PostStats")
+}
+
 // =============================================================================
 // validateResolverMappings Tests
 // =============================================================================
@@ -678,6 +719,38 @@ func TestGenerator_GenEntityType_WithDirectives(t *testing.T) {
 	assert.Contains(t, result, "@deprecated")
 }
 
+func TestGenerator_GenEntityType_OneOfEdge(t *testing.T) {
+	newType := func(name string) *entgen.Type {
+		return &entgen.Type{
+			Name:        name,
+			ID:          &entgen.Field{Name: "id", Type: &field.TypeInfo{Type: field.TypeInt}},
+			Annotations: map[string]any{},
+		}
+	}
+	post, photo, comment := newType("Post"), newType("Photo"), newType("Comment")
+	oe := &entgen.OneOfEdge{Name: "subject", Owner: comment, Types: []*entgen.Type{post, photo}, Optional: true}
+	comment.OneOfEdges = []*entgen.OneOfEdge{oe}
+
+	g := &entgen.Graph{
+		Config: &entgen.Config{Package: "example/ent"},
+		Nodes:  []*entgen.Type{post, photo, comment},
+	}
+	gen := NewGenerator(g, Config{Package: "graphql", ORMPackage: "example/ent"})
+
+	result := gen.genEntityType(comment)
+	assert.Contains(t, result, "  subject: CommentSubject\n}")
+	assert.Contains(t, result, `union CommentSubject @goModel(model: "example/ent/entity.CommentSubjectUnion") = Post | Photo`)
+
+	oe.Optional = false
+	assert.Contains(t, gen.genEntityType(comment), "subject: CommentSubject!")
+
+	// Edges to skipped types are not exposed.
+	photo.Annotations[AnnotationName] = &Annotation{Skip: SkipType}
+	result = gen.genEntityType(comment)
+	assert.NotContains(t, result, "subject:")
+	assert.NotContains(t, result, "union CommentSubject")
+}
+
 // =============================================================================
 // splitPascal Tests
 // =============================================================================
@@ -1231,6 +1304,79 @@ func TestGenerator_GenEntityPagination_FilterApplication(t *testing.T) {
 	assert.NotContains(t, code, "invalid filter type")
 }
 
+// fullTextPostType returns a Post type with a full-text index on its title.
+func fullTextPostType() *entgen.Type {
+	ant := sqlschema.FullText()
+	return &entgen.Type{
+		Name: "Post",
+		ID:   &entgen.Field{Name: "id", Type: &field.TypeInfo{Type: field.TypeInt64}},
+		Fields: []*entgen.Field{
+			{Name: "title", Type: &field.TypeInfo{Type: field.TypeString}},
+		},
+		Indexes: []*entgen.Index{
+			{Name: "post_title", Columns: []string{"title"}, Annotations: map[string]any{ant.Name(): ant}},
+		},
+		Annotations: map[string]any{
+			AnnotationName: &Annotation{RelayConnection: true},
+		},
+	}
+}
+
+func TestGenerator_FullTextSearch(t *testing.T) {
+	postType := fullTextPostType()
+	userType := &entgen.Type{
+		Name: "User",
+		ID:   &entgen.Field{Name: "id", Type: &field.TypeInfo{Type: field.TypeInt64}},
+		Edges: []*entgen.Edge{
+			{Name: "posts", Type: postType},
+		},
+		Annotations: map[string]any{
+			AnnotationName: &Annotation{RelayConnection: true},
+		},
+	}
+	g := &entgen.Graph{
+		Config: &entgen.Config{Package: "example/ent"},
+		Nodes:  []*entgen.Type{userType, postType},
+	}
+	gen := NewGenerator(g, Config{
+		Package:         "graphql",
+		ORMPackage:      "example/ent",
+		RelayConnection: true,
+		Ordering:        true,
+	})
+
+	t.Run("SDL", func(t *testing.T) {
+		assert.Contains(t, gen.genQueryConnectionArgs(postType, "Post", ""), "search: String")
+		assert.Contains(t, gen.genEdgeField(userType, userType.Edges[0]), "search: String")
+		assert.NotContains(t, gen.genQueryConnectionArgs(userType, "User", ""), "search")
+	})
+
+	t.Run("PaginationTypes", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, gen.genModelPaginationTypes([]*entgen.Type{postType}).Render(&buf))
+		code := buf.String()
+		assert.Regexp(t, `Search\s+\*string`, code)
+		assert.Contains(t, code, "func WithPostSearch(query *string) PostPaginateOption")
+		assert.Contains(t, code, "cfg.Search = query")
+	})
+
+	t.Run("Paginate", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, gen.genEntityPagination(postType).Render(&buf))
+		code := buf.String()
+		assert.Contains(t, code, "cfg.Search != nil")
+		assert.Contains(t, code, "q.Where(post.Search(*cfg.Search))")
+	})
+
+	t.Run("Edge", func(t *testing.T) {
+		src := gen.genEntityEdge(userType).GoString()
+		mustParseGo(t, src)
+		assert.Contains(t, src, "search *string")
+		assert.Contains(t, src, "search == nil")
+		assert.Contains(t, src, "WithPostSearch(search)")
+	})
+}
+
 // TestGenerator_GenEntityPagination_DelegatesToBuildConnection pins a
 // single-source-of-truth invariant: query.XxxQuery.Paginate MUST delegate
 // connection assembly to entity.BuildXxxConnection rather than inlining
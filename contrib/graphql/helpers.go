@@ -309,8 +309,8 @@ func (m *MutationDescriptor) InputFields(g *Generator) []*InputFieldDescriptor {
 		ann := g.getFieldAnnotation(f)
 		annSkip := g.annotationSkipMode(ann)
 
-		// Skip edge fields (FK fields managed by edges)
-		if f.IsEdgeField() {
+		// Skip edge fields (FK fields managed by edges) and read-only fields
+		if f.IsEdgeField() || f.ReadOnly() {
 			continue
 		}
 		// Skip based on mutation type
@@ -353,14 +353,18 @@ func (g *Generator) orderByArg(t *gen.Type) string {
 	return fmt.Sprintf("orderBy: %sOrder", typeName)
 }
 
+// wantsMutationCreate reports if the create mutation is generated for the type.
+// Views (including materialized views) are read-only.
 func (g *Generator) wantsMutationCreate(t *gen.Type) bool {
 	ann := g.getTypeAnnotation(t)
-	return ann.WantsMutationCreate() && !ann.IsSkipMutationCreate()
+	return !t.IsView() && ann.WantsMutationCreate() && !ann.IsSkipMutationCreate()
 }
 
+// wantsMutationUpdate reports if the update mutation is generated for the type.
+// Views (including materialized views) are read-only.
 func (g *Generator) wantsMutationUpdate(t *gen.Type) bool {
 	ann := g.getTypeAnnotation(t)
-	return ann.WantsMutationUpdate() && !ann.IsSkipMutationUpdate()
+	return !t.IsView() && ann.WantsMutationUpdate() && !ann.IsSkipMutationUpdate()
 }
 
 func (g *Generator) wantsMutationDelete(_ *gen.Type) bool {
@@ -379,6 +383,10 @@ func (g *Generator) modelPkg() string {
 // =============================================================================
 
 func (g *Generator) fieldInCreateInput(f *gen.Field) bool {
+	// Generated columns and counter caches have no setters.
+	if f.ReadOnly() {
+		return false
+	}
 	// Check annotation first
 	ann := g.getFieldAnnotation(f)
 	if ann.HasFieldMutationOpsSet() {
@@ -392,6 +400,10 @@ func (g *Generator) fieldInCreateInput(f *gen.Field) bool {
 }
 
 func (g *Generator) fieldInUpdateInput(f *gen.Field) bool {
+	// Generated columns and counter caches have no setters.
+	if f.ReadOnly() {
+		return false
+	}
 	// Check annotation first
 	ann := g.getFieldAnnotation(f)
 	if ann.HasFieldMutationOpsSet() {
@@ -34,9 +34,23 @@ func writeConnectionBaseArgs(buf *bytes.Buffer) {
 `)
 }
 
+// writeSearchArg writes the full-text search argument of a connection
+// of entities with a full-text index.
+func writeSearchArg(buf *bytes.Buffer, typeName string) {
+	if !bytes.HasSuffix(buf.Bytes(), []byte("\n\n")) {
+		buf.WriteByte('\n')
+	}
+	fmt.Fprintf(buf, `    """
+    Full-text search query for %s returned from the connection.
+    """
+    search: String
+`, pluralize(typeName))
+}
+
 // genConnectionArgs generates documented connection arguments.
-// wantsWhere indicates whether the where parameter should be included.
-func (g *Generator) genConnectionArgs(targetType string, orderByArg string, wantsOrder, wantsWhere bool) string {
+// wantsWhere indicates whether the where parameter should be included,
+// and wantsSearch whether the search parameter should be included.
+func (g *Generator) genConnectionArgs(targetType string, orderByArg string, wantsOrder, wantsWhere, wantsSearch bool) string {
 	var buf bytes.Buffer
 	writeConnectionBaseArgs(&buf)
 	if targetType != "" {
@@ -56,6 +70,9 @@ func (g *Generator) genConnectionArgs(targetType string, orderByArg string, want
     where: %sWhereInput
 `, pluralize(targetType), targetType)
 		}
+		if wantsSearch {
+			writeSearchArg(&buf, targetType)
+		}
 	}
 	buf.WriteString("  )")
 	return buf.String()
@@ -81,7 +98,8 @@ func (g *Generator) genEdgeField(_ *gen.Type, e *gen.Edge) string {
 		orderByArg := g.orderByArg(e.Type)
 		wantsOrder := g.config.Ordering && g.wantsOrderField(e.Type)
 		wantsWhere := g.config.WhereInputs && g.hasWhereInput(e)
-		args := g.genConnectionArgs(targetType, orderByArg, wantsOrder, wantsWhere)
+		wantsSearch := e.Type.FullTextIndex() != nil
+		args := g.genConnectionArgs(targetType, orderByArg, wantsOrder, wantsWhere, wantsSearch)
 		fieldDef = fmt.Sprintf("%s%s: %sConnection!", name, args, targetType)
 		// Plan 3 (2026-04-25): the entity method (*Parent).Edge(ctx, after,
 		// first, before, last, orderBy, where *filter.XxxWhereInput) carries
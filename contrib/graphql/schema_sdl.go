@@ -300,6 +300,9 @@ func (g *Generator) genQueryConnectionArgs(t *gen.Type, typeName string, orderBy
     where: %sWhereInput
 `, pluralize(typeName), typeName)
 	}
+	if t.FullTextIndex() != nil {
+		writeSearchArg(&buf, typeName)
+	}
 	buf.WriteString("  )")
 	return buf.String()
 }
@@ -491,6 +491,12 @@ func (g *Generator) genEntityType(t *gen.Type) string {
 		fmt.Fprintf(&buf, "  %s\n", g.genEdgeField(t, e))
 	}
 
+	// Polymorphic edge fields, resolved by the entity {Edge}(ctx) methods.
+	oneOfs := g.oneOfEdges(t)
+	for _, oe := range oneOfs {
+		fmt.Fprintf(&buf, "  %s\n", g.genOneOfEdgeField(t, oe))
+	}
+
 	// Resolver mapping fields (add new fields with @goField(forceResolver: true))
 	entityAnn := g.getTypeAnnotation(t)
 	for _, rm := range entityAnn.ResolverMappings {
@@ -502,9 +508,58 @@ func (g *Generator) genEntityType(t *gen.Type) string {
 	}
 
 	buf.WriteString("}\n")
+	for _, oe := range oneOfs {
+		buf.WriteString("\n")
+		buf.WriteString(g.genOneOfUnion(t, oe))
+	}
 	return buf.String()
 }
 
+// oneOfEdges returns the polymorphic edges of the type that are exposed in the
+// schema. Edges that point to a type that is skipped are not exposed, as the
+// union would not be able to resolve its entities.
+func (g *Generator) oneOfEdges(t *gen.Type) []*gen.OneOfEdge {
+	var edges []*gen.OneOfEdge
+	for _, oe := range t.OneOfEdges {
+		if len(g.filterNodes(oe.Types, SkipType)) == len(oe.Types) {
+			edges = append(edges, oe)
+		}
+	}
+	return edges
+}
+
+// oneOfUnionName returns the GraphQL union name of a polymorphic edge,
+// e.g. CommentSubject.
+func (g *Generator) oneOfUnionName(t *gen.Type, oe *gen.OneOfEdge) string {
+	return g.graphqlTypeName(t) + pascal(oe.Name)
+}
+
+// genOneOfEdgeField generates the union-typed field of a polymorphic edge.
+func (g *Generator) genOneOfEdgeField(t *gen.Type, oe *gen.OneOfEdge) string {
+	fieldDef := fmt.Sprintf("%s: %s", camel(oe.Name), g.oneOfUnionName(t, oe))
+	if !oe.Optional {
+		fieldDef += "!"
+	}
+	if comment := oe.Comment(); comment != "" {
+		return fmt.Sprintf("\"\"\"\n  %s\n  \"\"\"\n  %s", comment, fieldDef)
+	}
+	return fieldDef
+}
+
+// genOneOfUnion generates the union of a polymorphic edge, bound to the union
+// interface that is generated in the entity package.
+func (g *Generator) genOneOfUnion(t *gen.Type, oe *gen.OneOfEdge) string {
+	members := make([]string, len(oe.Types))
+	for i, typ := range oe.Types {
+		members[i] = g.graphqlTypeName(typ)
+	}
+	directive := ""
+	if g.config.ORMPackage != "" {
+		directive = fmt.Sprintf(" @goModel(model: \"%s/entity.%s\")", g.config.ORMPackage, oe.UnionName())
+	}
+	return fmt.Sprintf("union %s%s = %s\n", g.oneOfUnionName(t, oe), directive, strings.Join(members, " | "))
+}
+
 // isEdgeFKField returns true if the field is a foreign key field for an edge.
 func (g *Generator) isEdgeFKField(t *gen.Type, f *gen.Field) bool {
 	// Direct check if the field is marked as an edge field (has fk info)
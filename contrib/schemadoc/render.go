@@ -0,0 +1,426 @@
+package schemadoc
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"strings"
+
+	"github.com/syssam/velox/compiler/gen"
+)
+
+type (
+	// doc is the format-independent model of the rendered graph.
+	doc struct {
+		Title     string
+		Entities  []*entity
+		Relations []*relation
+	}
+
+	// entity describes a single schema type.
+	entity struct {
+		Name          string
+		Table         string
+		Comment       string
+		View          bool
+		Fields        []*docField
+		Edges         []*docEdge
+		Indexes       []*docIndex
+		Hooks         int
+		Interceptors  int
+		Policies      int
+		FieldPolicies int
+	}
+
+	// docField describes a field, or an unexported foreign-key column.
+	docField struct {
+		Name       string
+		Column     string
+		Type       string // Go type, e.g. "time.Time".
+		Kind       string // diagram type, e.g. "time".
+		Keys       []string
+		Modifiers  []string
+		Enums      []string
+		Comment    string
+		Deprecated bool
+		Reason     string
+	}
+
+	// docEdge describes an edge of a type.
+	docEdge struct {
+		Name     string
+		Type     string
+		Rel      string
+		Ref      string
+		Inverse  bool
+		Unique   bool
+		Required bool
+		Table    string // join table of M2M edges.
+		Through  string
+		Comment  string
+	}
+
+	// docIndex describes an index of a type.
+	docIndex struct {
+		Name    string
+		Columns []string
+		Unique  bool
+	}
+
+	// relation is a diagram line between two types. Only assoc edges
+	// (edge.To) are drawn, with the name of their inverse edge.
+	relation struct {
+		From, To string
+		Rel      gen.Rel
+		Label    string
+	}
+)
+
+func newDoc(g *gen.Graph) *doc {
+	d := &doc{Title: "Schema Reference"}
+	for _, t := range g.Nodes {
+		e := &entity{
+			Name:         t.Name,
+			Table:        t.Table(),
+			Comment:      t.TableComment(),
+			View:         t.IsView(),
+			Hooks:        t.NumHooks(),
+			Interceptors: t.NumInterceptors(),
+			Policies:     t.NumPolicy(),
+		}
+		if t.HasOneFieldID() {
+			f := newField(t.ID)
+			f.Keys = []string{"PK"}
+			e.Fields = append(e.Fields, f)
+		}
+		for _, f := range t.Fields {
+			df := newField(f)
+			if f.IsEdgeField() {
+				df.Keys = append(df.Keys, "FK")
+			}
+			if f.Unique {
+				df.Keys = append(df.Keys, "UK")
+			}
+			if f.HasFieldPolicy() {
+				e.FieldPolicies++
+			}
+			e.Fields = append(e.Fields, df)
+		}
+		for _, fk := range t.UnexportedForeignKeys() {
+			df := newField(fk.Field)
+			df.Keys = []string{"FK"}
+			df.Modifiers = append(df.Modifiers, "edge "+fk.Edge.Name)
+			e.Fields = append(e.Fields, df)
+		}
+		for _, ed := range t.Edges {
+			de := &docEdge{
+				Name:     ed.Name,
+				Type:     ed.Type.Name,
+				Rel:      ed.Rel.Type.String(),
+				Inverse:  ed.IsInverse(),
+				Unique:   ed.Unique,
+				Required: !ed.Optional,
+				Comment:  ed.Comment(),
+			}
+			if ed.Ref != nil {
+				de.Ref = ed.Ref.Name
+			}
+			if ed.M2M() {
+				de.Table = ed.Rel.Table
+			}
+			if ed.Through != nil {
+				de.Through = ed.Through.Name
+			}
+			e.Edges = append(e.Edges, de)
+			if ed.IsInverse() {
+				continue
+			}
+			r := &relation{From: t.Name, To: ed.Type.Name, Rel: ed.Rel.Type, Label: ed.Name}
+			if ed.Ref != nil && !ed.Bidi {
+				r.Label += "/" + ed.Ref.Name
+			}
+			if de.Through != "" {
+				r.Label += " through " + de.Through
+			} else if de.Table != "" {
+				r.Label += " (" + de.Table + ")"
+			}
+			d.Relations = append(d.Relations, r)
+		}
+		for _, idx := range t.Indexes {
+			e.Indexes = append(e.Indexes, &docIndex{Name: idx.Name, Columns: idx.Columns, Unique: idx.Unique})
+		}
+		d.Entities = append(d.Entities, e)
+	}
+	return d
+}
+
+func newField(f *gen.Field) *docField {
+	df := &docField{
+		Name:       f.Name,
+		Column:     f.StorageKey(),
+		Comment:    f.Comment(),
+		Deprecated: f.IsDeprecated(),
+		Reason:     f.DeprecationReason(),
+	}
+	if f.Type != nil {
+		df.Type = f.Type.String()
+		df.Kind = strings.ToLower(strings.TrimPrefix(f.Type.Type.ConstName(), "Type"))
+	}
+	if f.IsEnum() {
+		df.Type = "enum"
+		df.Enums = f.EnumValues()
+	}
+	for _, m := range []struct {
+		ok   bool
+		name string
+	}{
+		{f.Optional, "optional"},
+		{f.Nillable, "nillable"},
+		{f.Immutable, "immutable"},
+		{f.IsGenerated(), "generated"},
+		{f.CounterCache() != nil, "counter cache"},
+		{f.Default, "default"},
+		{f.UpdateDefault, "update default"},
+		{f.Validators > 0, "validated"},
+		{f.Sensitive(), "sensitive"},
+		{f.HasFieldPolicy(), "field policy"},
+		{f.IsDeprecated(), "deprecated"},
+	} {
+		if m.ok {
+			df.Modifiers = append(df.Modifiers, m.name)
+		}
+	}
+	return df
+}
+
+// note returns the diagram comment of the field.
+func (f *docField) note() string {
+	var parts []string
+	if len(f.Enums) > 0 {
+		parts = append(parts, strings.Join(f.Enums, ", "))
+	}
+	if f.Deprecated {
+		parts = append(parts, strings.TrimSpace("deprecated "+f.Reason))
+	}
+	if f.Comment != "" {
+		parts = append(parts, f.Comment)
+	}
+	return strings.Join(parts, "; ")
+}
+
+// Features returns the hooks, interceptors and policies of the entity.
+func (e *entity) Features() string {
+	var parts []string
+	for _, c := range []struct {
+		n    int
+		name string
+	}{
+		{e.Hooks, "hooks"},
+		{e.Interceptors, "interceptors"},
+		{e.Policies, "policies"},
+		{e.FieldPolicies, "field policies"},
+	} {
+		if c.n > 0 {
+			parts = append(parts, fmt.Sprintf("%d %s", c.n, c.name))
+		}
+	}
+	if len(parts) == 0 {
+		return "none"
+	}
+	return strings.Join(parts, ", ")
+}
+
+// mermaidCardinality returns the Mermaid crow's foot notation of a relation.
+func mermaidCardinality(r gen.Rel) string {
+	switch r {
+	case gen.O2O:
+		return "||--o|"
+	case gen.O2M:
+		return "||--o{"
+	case gen.M2O:
+		return "}o--||"
+	default:
+		return "}o--o{"
+	}
+}
+
+func (d *doc) mermaid() []byte {
+	var b strings.Builder
+	b.WriteString("erDiagram\n")
+	for _, e := range d.Entities {
+		fmt.Fprintf(&b, "    %s {\n", e.Name)
+		for _, f := range e.Fields {
+			fmt.Fprintf(&b, "        %s %s", f.Kind, f.Name)
+			if len(f.Keys) > 0 {
+				fmt.Fprintf(&b, " %s", strings.Join(f.Keys, ","))
+			}
+			if note := f.note(); note != "" {
+				fmt.Fprintf(&b, " \"%s\"", strings.ReplaceAll(note, `"`, "'"))
+			}
+			b.WriteString("\n")
+		}
+		b.WriteString("    }\n")
+	}
+	for _, r := range d.Relations {
+		fmt.Fprintf(&b, "    %s %s %s : \"%s\"\n", r.From, mermaidCardinality(r.Rel), r.To, strings.ReplaceAll(r.Label, `"`, "'"))
+	}
+	return []byte(b.String())
+}
+
+// dotEscaper escapes the special characters of DOT record labels.
+var dotEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "{", `\{`, "}", `\}`, "|", `\|`, "<", `\<`, ">", `\>`, "\n", " ")
+
+func (d *doc) dot() []byte {
+	var b strings.Builder
+	b.WriteString("digraph schema {\n")
+	b.WriteString("\trankdir=LR;\n")
+	b.WriteString("\tnode [shape=record, fontname=\"Helvetica\"];\n")
+	b.WriteString("\tedge [fontname=\"Helvetica\", fontsize=10];\n")
+	for _, e := range d.Entities {
+		rows := make([]string, 0, len(e.Fields))
+		for _, f := range e.Fields {
+			row := f.Name + ": " + f.Type
+			if len(f.Keys) > 0 {
+				row += " (" + strings.Join(f.Keys, ",") + ")"
+			}
+			rows = append(rows, dotEscaper.Replace(row)+`\l`)
+		}
+		fmt.Fprintf(&b, "\t%s [label=\"{%s|%s}\"];\n", e.Name, dotEscaper.Replace(e.Name), strings.Join(rows, ""))
+	}
+	for _, r := range d.Relations {
+		fmt.Fprintf(&b, "\t%s -> %s [label=\"%s (%s)\"", r.From, r.To, dotEscaper.Replace(r.Label), r.Rel)
+		if r.Rel == gen.M2M {
+			b.WriteString(", dir=both")
+		}
+		b.WriteString("];\n")
+	}
+	b.WriteString("}\n")
+	return []byte(b.String())
+}
+
+// mdEscaper escapes the text of Markdown table cells.
+var mdEscaper = strings.NewReplacer("|", `\|`, "\n", " ")
+
+func (d *doc) markdown() []byte {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s\n\n", d.Title)
+	for _, e := range d.Entities {
+		fmt.Fprintf(&b, "- [%s](#%s)\n", e.Name, strings.ToLower(e.Name))
+	}
+	b.WriteString("\n```mermaid\n")
+	b.Write(d.mermaid())
+	b.WriteString("```\n")
+	for _, e := range d.Entities {
+		fmt.Fprintf(&b, "\n## %s\n\n", e.Name)
+		if e.Comment != "" {
+			fmt.Fprintf(&b, "%s\n\n", e.Comment)
+		}
+		kind := "Table"
+		if e.View {
+			kind = "View"
+		}
+		fmt.Fprintf(&b, "%s: `%s`. Hooks and privacy: %s.\n\n", kind, e.Table, e.Features())
+		b.WriteString("### Fields\n\n")
+		b.WriteString("| Field | Column | Type | Key | Modifiers | Comment |\n")
+		b.WriteString("|-------|--------|------|-----|-----------|---------|\n")
+		for _, f := range e.Fields {
+			typ := "`" + f.Type + "`"
+			if len(f.Enums) > 0 {
+				typ += " (" + strings.Join(f.Enums, ", ") + ")"
+			}
+			comment := f.Comment
+			if f.Deprecated {
+				comment = strings.TrimSpace("**Deprecated:** " + f.Reason + " " + comment)
+			}
+			fmt.Fprintf(&b, "| %s | %s | %s | %s | %s | %s |\n",
+				mdEscaper.Replace(f.Name), mdEscaper.Replace(f.Column), mdEscaper.Replace(typ),
+				strings.Join(f.Keys, ", "), strings.Join(f.Modifiers, ", "), mdEscaper.Replace(comment))
+		}
+		if len(e.Edges) > 0 {
+			b.WriteString("\n### Edges\n\n")
+			b.WriteString("| Edge | Type | Relation | Inverse | Table | Through | Comment |\n")
+			b.WriteString("|------|------|----------|---------|-------|---------|---------|\n")
+			for _, ed := range e.Edges {
+				fmt.Fprintf(&b, "| %s | [%s](#%s) | %s | %s | %s | %s | %s |\n",
+					ed.Name, ed.Type, strings.ToLower(ed.Type), ed.Rel, ed.Ref, ed.Table, ed.Through, mdEscaper.Replace(ed.Comment))
+			}
+		}
+		if len(e.Indexes) > 0 {
+			b.WriteString("\n### Indexes\n\n")
+			b.WriteString("| Name | Columns | Unique |\n")
+			b.WriteString("|------|---------|--------|\n")
+			for _, idx := range e.Indexes {
+				fmt.Fprintf(&b, "| %s | %s | %t |\n", idx.Name, strings.Join(idx.Columns, ", "), idx.Unique)
+			}
+		}
+	}
+	return []byte(b.String())
+}
+
+var htmlTmpl = template.Must(template.New("schemadoc").Funcs(template.FuncMap{
+	"join":  strings.Join,
+	"lower": strings.ToLower,
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>{{ .Title }}</title>
+<style>
+body { font-family: -apple-system, Helvetica, Arial, sans-serif; margin: 2em; color: #222; }
+table { border-collapse: collapse; margin-bottom: 1.5em; }
+th, td { border: 1px solid #ccc; padding: 4px 8px; text-align: left; vertical-align: top; }
+th { background: #f4f4f4; }
+code { font-size: 0.9em; }
+.deprecated { color: #b00; }
+</style>
+</head>
+<body>
+<h1>{{ .Title }}</h1>
+<ul>
+{{- range .Entities }}
+<li><a href="#{{ lower .Name }}">{{ .Name }}</a></li>
+{{- end }}
+</ul>
+{{- range .Entities }}
+<h2 id="{{ lower .Name }}">{{ .Name }}</h2>
+{{- with .Comment }}
+<p>{{ . }}</p>
+{{- end }}
+<p>{{ if .View }}View{{ else }}Table{{ end }}: <code>{{ .Table }}</code>. Hooks and privacy: {{ .Features }}.</p>
+<h3>Fields</h3>
+<table>
+<tr><th>Field</th><th>Column</th><th>Type</th><th>Key</th><th>Modifiers</th><th>Comment</th></tr>
+{{- range .Fields }}
+<tr><td>{{ .Name }}</td><td>{{ .Column }}</td><td><code>{{ .Type }}</code>{{ with .Enums }} ({{ join . ", " }}){{ end }}</td><td>{{ join .Keys ", " }}</td><td>{{ join .Modifiers ", " }}</td><td>{{ if .Deprecated }}<span class="deprecated">Deprecated{{ with .Reason }}: {{ . }}{{ end }}</span> {{ end }}{{ .Comment }}</td></tr>
+{{- end }}
+</table>
+{{- with .Edges }}
+<h3>Edges</h3>
+<table>
+<tr><th>Edge</th><th>Type</th><th>Relation</th><th>Inverse</th><th>Table</th><th>Through</th><th>Comment</th></tr>
+{{- range . }}
+<tr><td>{{ .Name }}</td><td><a href="#{{ lower .Type }}">{{ .Type }}</a></td><td>{{ .Rel }}</td><td>{{ .Ref }}</td><td>{{ .Table }}</td><td>{{ .Through }}</td><td>{{ .Comment }}</td></tr>
+{{- end }}
+</table>
+{{- end }}
+{{- with .Indexes }}
+<h3>Indexes</h3>
+<table>
+<tr><th>Name</th><th>Columns</th><th>Unique</th></tr>
+{{- range . }}
+<tr><td>{{ .Name }}</td><td>{{ join .Columns ", " }}</td><td>{{ .Unique }}</td></tr>
+{{- end }}
+</table>
+{{- end }}
+{{- end }}
+</body>
+</html>
+`))
+
+func (d *doc) html() ([]byte, error) {
+	var buf bytes.Buffer
+	if err := htmlTmpl.Execute(&buf, d); err != nil {
+		return nil, fmt.Errorf("schemadoc: render html: %w", err)
+	}
+	return buf.Bytes(), nil
+}
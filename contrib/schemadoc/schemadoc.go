@@ -0,0 +1,181 @@
+// Package schemadoc renders a velox schema graph as entity-relationship
+// diagrams and reference documentation.
+//
+// The supported formats are Mermaid (erDiagram), Graphviz DOT, Markdown and a
+// static, self-contained HTML page. The output includes the fields of each
+// entity with their types and modifiers, enum values, comments and deprecation
+// reasons, the edges with their cardinality, join and Through tables, the
+// indexes, and the presence of hooks, interceptors and privacy policies.
+//
+// # Usage
+//
+// Add the extension to your generate.go:
+//
+//	ex, err := schemadoc.NewExtension(
+//	    schemadoc.WithDir("./docs/schema"),
+//	    schemadoc.WithFormats(schemadoc.Mermaid, schemadoc.Markdown),
+//	)
+//	if err != nil {
+//	    log.Fatalf("creating schemadoc extension: %v", err)
+//	}
+//	err = compiler.Generate("./schema", cfg, compiler.Extensions(ex))
+//
+// Or render a loaded graph directly:
+//
+//	g, err := compiler.LoadGraph("./schema", cfg)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	out, err := schemadoc.Render(g, schemadoc.DOT)
+package schemadoc
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/syssam/velox/compiler"
+	"github.com/syssam/velox/compiler/gen"
+)
+
+// Format is an output format of the schema documentation.
+type Format string
+
+// Supported output formats.
+const (
+	// Mermaid renders a Mermaid erDiagram.
+	Mermaid Format = "mermaid"
+	// DOT renders a Graphviz digraph.
+	DOT Format = "dot"
+	// Markdown renders a Markdown reference document.
+	Markdown Format = "markdown"
+	// HTML renders a static HTML reference page.
+	HTML Format = "html"
+)
+
+// Filename returns the name of the file the format is written to.
+func (f Format) Filename() string {
+	switch f {
+	case Mermaid:
+		return "schema.mmd"
+	case DOT:
+		return "schema.dot"
+	case Markdown:
+		return "schema.md"
+	case HTML:
+		return "schema.html"
+	default:
+		return "schema." + string(f)
+	}
+}
+
+// Extension implements the compiler.Extension interface. It writes the schema
+// documentation after the code generation.
+type Extension struct {
+	compiler.DefaultExtension
+	dir     string
+	title   string
+	formats []Format
+}
+
+// ExtensionOption configures the Extension.
+type ExtensionOption func(*Extension) error
+
+// WithDir sets the output directory of the documentation. Defaults to the
+// "docs" directory in the generation target.
+func WithDir(dir string) ExtensionOption {
+	return func(e *Extension) error {
+		e.dir = dir
+		return nil
+	}
+}
+
+// WithTitle sets the title of the Markdown and HTML documents.
+func WithTitle(title string) ExtensionOption {
+	return func(e *Extension) error {
+		e.title = title
+		return nil
+	}
+}
+
+// WithFormats sets the output formats. Defaults to all formats.
+func WithFormats(formats ...Format) ExtensionOption {
+	return func(e *Extension) error {
+		for _, f := range formats {
+			switch f {
+			case Mermaid, DOT, Markdown, HTML:
+			default:
+				return fmt.Errorf("schemadoc: unknown format %q", f)
+			}
+		}
+		e.formats = formats
+		return nil
+	}
+}
+
+// NewExtension creates a new schemadoc extension with the given options.
+func NewExtension(opts ...ExtensionOption) (*Extension, error) {
+	ex := &Extension{formats: []Format{Mermaid, DOT, Markdown, HTML}}
+	for _, opt := range opts {
+		if err := opt(ex); err != nil {
+			return nil, err
+		}
+	}
+	return ex, nil
+}
+
+// Hooks returns the hook that writes the documentation.
+func (e *Extension) Hooks() []gen.Hook {
+	return []gen.Hook{
+		func(next gen.Generator) gen.Generator {
+			return gen.GenerateFunc(func(g *gen.Graph) error {
+				if err := next.Generate(g); err != nil {
+					return err
+				}
+				return e.write(g)
+			})
+		},
+	}
+}
+
+// write renders the graph in all formats and writes the output files.
+func (e *Extension) write(g *gen.Graph) error {
+	dir := e.dir
+	if dir == "" {
+		dir = filepath.Join(g.Target, "docs")
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("schemadoc: %w", err)
+	}
+	for _, f := range e.formats {
+		out, err := Render(g, f, e.title)
+		if err != nil {
+			return err
+		}
+		if _, err := gen.WriteFileIfChanged(filepath.Join(dir, f.Filename()), out, 0o644); err != nil {
+			return fmt.Errorf("schemadoc: write %s: %w", f.Filename(), err)
+		}
+	}
+	return nil
+}
+
+// Render renders the graph in the given format. The optional title is used
+// by the Markdown and HTML formats.
+func Render(g *gen.Graph, f Format, title ...string) ([]byte, error) {
+	d := newDoc(g)
+	if len(title) > 0 && title[0] != "" {
+		d.Title = title[0]
+	}
+	switch f {
+	case Mermaid:
+		return d.mermaid(), nil
+	case DOT:
+		return d.dot(), nil
+	case Markdown:
+		return d.markdown(), nil
+	case HTML:
+		return d.html()
+	default:
+		return nil, fmt.Errorf("schemadoc: unknown format %q", f)
+	}
+}
@@ -0,0 +1,178 @@
+package schemadoc
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/syssam/velox"
+	"github.com/syssam/velox/compiler/gen"
+	"github.com/syssam/velox/compiler/load"
+	"github.com/syssam/velox/schema/edge"
+	"github.com/syssam/velox/schema/field"
+	"github.com/syssam/velox/schema/index"
+)
+
+type User struct{ velox.Schema }
+
+func (User) Fields() []velox.Field {
+	return []velox.Field{
+		field.String("name").Comment("Display name"),
+		field.String("email").Unique().Sensitive(),
+		field.Enum("status").Values("active", "disabled").Default("active"),
+		field.String("nick").Optional().Deprecated("use name"),
+	}
+}
+
+func (User) Edges() []velox.Edge {
+	return []velox.Edge{
+		edge.To("posts", Post.Type),
+		edge.To("groups", Group.Type),
+	}
+}
+
+func (User) Indexes() []velox.Index {
+	return []velox.Index{index.Fields("name", "status")}
+}
+
+func (User) Hooks() []velox.Hook {
+	return []velox.Hook{func(next velox.Mutator) velox.Mutator { return next }}
+}
+
+func (User) Policy() velox.Policy { return allow{} }
+
+type allow struct{}
+
+func (allow) EvalMutation(context.Context, velox.Mutation) error { return nil }
+func (allow) EvalQuery(context.Context, velox.Query) error       { return nil }
+
+type Post struct{ velox.Schema }
+
+func (Post) Fields() []velox.Field {
+	return []velox.Field{
+		field.Text("body"),
+		field.Int("author_id"),
+	}
+}
+
+func (Post) Edges() []velox.Edge {
+	return []velox.Edge{
+		edge.From("author", User.Type).Ref("posts").Unique().Required().Field("author_id"),
+	}
+}
+
+type Group struct{ velox.Schema }
+
+func (Group) Fields() []velox.Field {
+	return []velox.Field{field.String("title")}
+}
+
+func (Group) Edges() []velox.Edge {
+	return []velox.Edge{edge.From("users", User.Type).Ref("groups")}
+}
+
+func testGraph(t *testing.T) *gen.Graph {
+	t.Helper()
+	var schemas []*load.Schema
+	for _, s := range []velox.Interface{User{}, Post{}, Group{}} {
+		b, err := load.MarshalSchema(s)
+		require.NoError(t, err)
+		ls, err := load.UnmarshalSchema(b)
+		require.NoError(t, err)
+		schemas = append(schemas, ls)
+	}
+	storage, err := gen.NewStorage("sql")
+	require.NoError(t, err)
+	g, err := gen.NewGraph(&gen.Config{
+		Package: "example.com/velox",
+		Target:  t.TempDir(),
+		Storage: storage,
+		IDType:  &field.TypeInfo{Type: field.TypeInt},
+	}, schemas...)
+	require.NoError(t, err)
+	return g
+}
+
+func TestRender_Mermaid(t *testing.T) {
+	out, err := Render(testGraph(t), Mermaid)
+	require.NoError(t, err)
+	s := string(out)
+	assert.True(t, strings.HasPrefix(s, "erDiagram\n"))
+	assert.Contains(t, s, "    User {\n        int id PK\n        string name \"Display name\"\n        string email UK\n")
+	assert.Contains(t, s, `        enum status "active, disabled"`)
+	assert.Contains(t, s, `        string nick "deprecated use name"`)
+	assert.Contains(t, s, "        int author_id FK\n")
+	assert.Contains(t, s, `    User ||--o{ Post : "posts/author"`)
+	assert.Contains(t, s, `    User }o--o{ Group : "groups/users (user_groups)"`)
+	assert.NotContains(t, s, "Post }o--|| User", "inverse edges are not drawn twice")
+}
+
+func TestRender_DOT(t *testing.T) {
+	out, err := Render(testGraph(t), DOT)
+	require.NoError(t, err)
+	s := string(out)
+	assert.Contains(t, s, "digraph schema {\n")
+	assert.Contains(t, s, `	Post [label="{Post|id: int (PK)\lbody: string\lauthor_id: int (FK)\l}"];`)
+	assert.Contains(t, s, `	User -> Post [label="posts/author (O2M)"];`)
+	assert.Contains(t, s, `	User -> Group [label="groups/users (user_groups) (M2M)", dir=both];`)
+}
+
+func TestRender_Markdown(t *testing.T) {
+	out, err := Render(testGraph(t), Markdown, "Blog")
+	require.NoError(t, err)
+	s := string(out)
+	assert.Contains(t, s, "# Blog\n")
+	assert.Contains(t, s, "```mermaid\nerDiagram\n")
+	assert.Contains(t, s, "Table: `users`. Hooks and privacy: 1 hooks, 1 policies.")
+	assert.Contains(t, s, "| status | status | `enum` (active, disabled) |  | default |  |")
+	assert.Contains(t, s, "| email | email | `string` | UK | sensitive |  |")
+	assert.Contains(t, s, "| nick | nick | `string` |  | optional, deprecated | **Deprecated:** use name |")
+	assert.Contains(t, s, "| groups | [Group](#group) | M2M | users | user_groups |  |  |")
+	assert.Contains(t, s, "| author | [User](#user) | M2O | posts |  |  |  |")
+	assert.Contains(t, s, "Table: `posts`. Hooks and privacy: none.")
+}
+
+func TestRender_HTML(t *testing.T) {
+	out, err := Render(testGraph(t), HTML)
+	require.NoError(t, err)
+	s := string(out)
+	assert.Contains(t, s, "<h1>Schema Reference</h1>")
+	assert.Contains(t, s, `<h2 id="user">User</h2>`)
+	assert.Contains(t, s, `<span class="deprecated">Deprecated: use name</span>`)
+	assert.Contains(t, s, "<code>enum</code> (active, disabled)")
+	_, err = Render(testGraph(t), Format("pdf"))
+	require.Error(t, err)
+}
+
+func TestExtension(t *testing.T) {
+	_, err := NewExtension(WithFormats("pdf"))
+	require.Error(t, err)
+
+	dir := t.TempDir()
+	ex, err := NewExtension(WithDir(dir), WithFormats(Mermaid, Markdown))
+	require.NoError(t, err)
+	hooks := ex.Hooks()
+	require.Len(t, hooks, 1)
+	var called bool
+	g := testGraph(t)
+	require.NoError(t, hooks[0](gen.GenerateFunc(func(*gen.Graph) error {
+		called = true
+		return nil
+	})).Generate(g))
+	assert.True(t, called)
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	assert.Equal(t, "schema.md", entries[0].Name())
+	assert.Equal(t, "schema.mmd", entries[1].Name())
+	b, err := os.ReadFile(filepath.Join(dir, "schema.mmd"))
+	require.NoError(t, err)
+	want, err := Render(g, Mermaid)
+	require.NoError(t, err)
+	assert.Equal(t, string(want), string(b))
+}
@@ -0,0 +1,268 @@
+package schemalint
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+
+	"github.com/syssam/velox/compiler/gen"
+	"github.com/syssam/velox/contrib/graphql"
+)
+
+// DefaultRules returns the built-in rules, used by NewExtension.
+func DefaultRules() []LintRule {
+	return []LintRule{
+		FKIndex(),
+		StringMaxLen(),
+		OptionalNillable(),
+		M2MThrough(),
+		EnumUpperSnake(),
+		Timestamps(),
+		SensitiveGraphQL(),
+	}
+}
+
+// rule implements LintRule for the built-in rules.
+type rule struct {
+	name, desc string
+	severity   Severity
+	check      func(*gen.Graph) []Diagnostic
+}
+
+func (r *rule) Name() string                    { return r.name }
+func (r *rule) Description() string             { return r.desc }
+func (r *rule) Severity() Severity              { return r.severity }
+func (r *rule) Check(g *gen.Graph) []Diagnostic { return r.check(g) }
+
+// FKIndex reports foreign-key columns that are not the leading column of an
+// index. Unique columns are skipped, since the database indexes them.
+func FKIndex() LintRule {
+	return &rule{
+		name:     "fk-index",
+		desc:     "Foreign-key columns are covered by an index.",
+		severity: Warning,
+		check: func(g *gen.Graph) (diags []Diagnostic) {
+			for _, t := range g.MutableNodes() {
+				for _, fk := range t.ForeignKeys {
+					column := fk.Field.StorageKey()
+					if fk.Field.Unique || fk.Edge.O2O() || leadingIndex(t, column) {
+						continue
+					}
+					diags = append(diags, Diagnostic{
+						Type:    t.Name,
+						Field:   fk.Field.Name,
+						Message: fmt.Sprintf("foreign-key column %q has no index", column),
+					})
+				}
+			}
+			return diags
+		},
+	}
+}
+
+// leadingIndex reports whether column is the first column of an index of t.
+func leadingIndex(t *gen.Type, column string) bool {
+	for _, idx := range t.Indexes {
+		if len(idx.Columns) > 0 && idx.Columns[0] == column {
+			return true
+		}
+	}
+	return false
+}
+
+// StringMaxLen reports String fields without a MaxLen or MaxRuneLen. Text
+// fields are unbounded by design and are skipped.
+func StringMaxLen() LintRule {
+	return &rule{
+		name:     "string-max-len",
+		desc:     "String fields declare a MaxLen.",
+		severity: Warning,
+		check: func(g *gen.Graph) (diags []Diagnostic) {
+			for _, t := range g.MutableNodes() {
+				for _, f := range t.Fields {
+					if !f.IsString() || f.IsEdgeField() || f.OneOfEdge() != nil || f.Column().Size != 0 {
+						continue
+					}
+					if c := f.Constraints(); c != nil && (c.MaxLen > 0 || c.MaxRuneLen > 0) {
+						continue
+					}
+					diags = append(diags, Diagnostic{
+						Type:    t.Name,
+						Field:   f.Name,
+						Message: "string field has no MaxLen; use MaxLen or Text",
+					})
+				}
+			}
+			return diags
+		},
+	}
+}
+
+// OptionalNillable reports Optional fields that are not Nillable, whose
+// unset values are stored and read as the zero value of their type. Fields
+// with a default value, JSON and bytes fields are skipped.
+func OptionalNillable() LintRule {
+	return &rule{
+		name:     "optional-nillable",
+		desc:     "Optional fields are Nillable.",
+		severity: Note,
+		check: func(g *gen.Graph) (diags []Diagnostic) {
+			for _, t := range g.MutableNodes() {
+				for _, f := range t.Fields {
+					switch {
+					case !f.Optional, f.Nillable, f.Default, f.IsJSON(), f.IsBytes(),
+						f.IsEdgeField(), f.IsGenerated(), f.OneOfEdge() != nil:
+						continue
+					}
+					diags = append(diags, Diagnostic{
+						Type:    t.Name,
+						Field:   f.Name,
+						Message: "optional field is not Nillable; unset values are read as the zero value",
+					})
+				}
+			}
+			return diags
+		},
+	}
+}
+
+// M2MThrough reports many-to-many edges without a Through schema. Only the
+// assoc side of the edge is reported.
+func M2MThrough() LintRule {
+	return &rule{
+		name:     "m2m-through",
+		desc:     "Many-to-many edges declare their join table with Through.",
+		severity: Note,
+		check: func(g *gen.Graph) (diags []Diagnostic) {
+			for _, t := range g.MutableNodes() {
+				for _, e := range t.Edges {
+					if !e.M2M() || e.IsInverse() || e.Through != nil {
+						continue
+					}
+					diags = append(diags, Diagnostic{
+						Type:    t.Name,
+						Field:   e.Name,
+						Message: fmt.Sprintf("many-to-many edge to %s has no Through schema", e.Type.Name),
+					})
+				}
+			}
+			return diags
+		},
+	}
+}
+
+// upperSnake matches UPPER_SNAKE identifiers, e.g. "IN_PROGRESS".
+var upperSnake = regexp.MustCompile(`^[A-Z][A-Z0-9]*(_[A-Z0-9]+)*$`)
+
+// EnumUpperSnake reports enum values that are not UPPER_SNAKE.
+func EnumUpperSnake() LintRule {
+	return &rule{
+		name:     "enum-upper-snake",
+		desc:     "Enum values are UPPER_SNAKE.",
+		severity: Warning,
+		check: func(g *gen.Graph) (diags []Diagnostic) {
+			for _, t := range g.Nodes {
+				for _, f := range t.Fields {
+					// The values of polymorphic type columns are type names.
+					if !f.IsEnum() || f.OneOfEdge() != nil {
+						continue
+					}
+					for _, e := range f.Enums {
+						if upperSnake.MatchString(e.Value) {
+							continue
+						}
+						diags = append(diags, Diagnostic{
+							Type:    t.Name,
+							Field:   f.Name,
+							Message: fmt.Sprintf("enum value %q is not UPPER_SNAKE", e.Value),
+						})
+					}
+				}
+			}
+			return diags
+		},
+	}
+}
+
+// Timestamps reports tables that miss one of the given timestamp fields.
+// The fields default to "created_at" and "updated_at", as declared by
+// mixin.Time.
+func Timestamps(fields ...string) LintRule {
+	if len(fields) == 0 {
+		fields = []string{"created_at", "updated_at"}
+	}
+	return &rule{
+		name:     "timestamps",
+		desc:     "Tables declare timestamp fields.",
+		severity: Note,
+		check: func(g *gen.Graph) (diags []Diagnostic) {
+			for _, t := range g.MutableNodes() {
+				for _, name := range fields {
+					if f, ok := findField(t, name); ok && f.IsTime() {
+						continue
+					}
+					diags = append(diags, Diagnostic{
+						Type:    t.Name,
+						Message: fmt.Sprintf("table %q has no %q timestamp field", t.Table(), name),
+					})
+				}
+			}
+			return diags
+		},
+	}
+}
+
+// findField returns the field of t with the given name.
+func findField(t *gen.Type, name string) (*gen.Field, bool) {
+	for _, f := range t.Fields {
+		if f.Name == name {
+			return f, true
+		}
+	}
+	return nil, false
+}
+
+// SensitiveGraphQL reports Sensitive fields that are exposed in the GraphQL
+// output types. A field is hidden by skipping it, or its type, with
+// graphql.Skip(graphql.SkipType). The rule only applies to graphs generated
+// with the GraphQL extension.
+func SensitiveGraphQL() LintRule {
+	return &rule{
+		name:     "sensitive-graphql",
+		desc:     "Sensitive fields are not exposed in GraphQL output types.",
+		severity: Error,
+		check: func(g *gen.Graph) (diags []Diagnostic) {
+			if _, ok := g.Annotations["GraphQL"]; !ok {
+				return nil
+			}
+			for _, t := range g.Nodes {
+				if graphqlAnnotation(t.Annotations).IsSkipType() {
+					continue
+				}
+				for _, f := range t.Fields {
+					if !f.Sensitive() || graphqlAnnotation(f.Annotations).IsSkipType() {
+						continue
+					}
+					diags = append(diags, Diagnostic{
+						Type:    t.Name,
+						Field:   f.Name,
+						Message: "sensitive field is exposed in the GraphQL output; skip it with graphql.Skip(graphql.SkipType)",
+					})
+				}
+			}
+			return diags
+		},
+	}
+}
+
+// graphqlAnnotation decodes the GraphQL annotation of a type or a field.
+// Loaded schemas hold annotations as decoded JSON.
+func graphqlAnnotation(annotations gen.Annotations) graphql.Annotation {
+	var ant graphql.Annotation
+	if v, ok := annotations[graphql.AnnotationName]; ok {
+		if b, err := json.Marshal(v); err == nil {
+			_ = json.Unmarshal(b, &ant)
+		}
+	}
+	return ant
+}
@@ -0,0 +1,145 @@
+package schemalint
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// SARIF 2.1.0 log, limited to the properties written by WriteSARIF.
+// See https://docs.oasis-open.org/sarif/sarif/v2.1.0/sarif-v2.1.0.html.
+type (
+	sarifLog struct {
+		Schema  string     `json:"$schema"`
+		Version string     `json:"version"`
+		Runs    []sarifRun `json:"runs"`
+	}
+	sarifRun struct {
+		Tool    sarifTool     `json:"tool"`
+		Results []sarifResult `json:"results"`
+	}
+	sarifTool struct {
+		Driver sarifDriver `json:"driver"`
+	}
+	sarifDriver struct {
+		Name           string      `json:"name"`
+		InformationURI string      `json:"informationUri"`
+		Rules          []sarifRule `json:"rules"`
+	}
+	sarifRule struct {
+		ID                   string       `json:"id"`
+		ShortDescription     sarifMessage `json:"shortDescription"`
+		DefaultConfiguration struct {
+			Level Severity `json:"level"`
+		} `json:"defaultConfiguration"`
+	}
+	sarifResult struct {
+		RuleID    string          `json:"ruleId"`
+		RuleIndex int             `json:"ruleIndex"`
+		Level     Severity        `json:"level"`
+		Message   sarifMessage    `json:"message"`
+		Locations []sarifLocation `json:"locations,omitempty"`
+	}
+	sarifMessage struct {
+		Text string `json:"text"`
+	}
+	sarifLocation struct {
+		PhysicalLocation *sarifPhysicalLocation `json:"physicalLocation,omitempty"`
+		LogicalLocations []sarifLogicalLocation `json:"logicalLocations,omitempty"`
+	}
+	sarifPhysicalLocation struct {
+		ArtifactLocation struct {
+			URI string `json:"uri"`
+		} `json:"artifactLocation"`
+		Region *sarifRegion `json:"region,omitempty"`
+	}
+	sarifRegion struct {
+		StartLine int `json:"startLine"`
+	}
+	sarifLogicalLocation struct {
+		FullyQualifiedName string `json:"fullyQualifiedName"`
+		Kind               string `json:"kind"`
+	}
+)
+
+// WriteSARIF writes the diagnostics as a SARIF 2.1.0 log to w. The rules are
+// listed in the log with their effective severity, given the overrides in
+// severity (which may be nil). Disabled rules are omitted.
+func WriteSARIF(w io.Writer, rules []LintRule, severity map[string]Severity, diags []Diagnostic) error {
+	driver := sarifDriver{
+		Name:           "velox-schemalint",
+		InformationURI: "https://github.com/syssam/velox",
+		Rules:          []sarifRule{},
+	}
+	index := make(map[string]int, len(rules))
+	for _, r := range rules {
+		s, ok := severity[r.Name()]
+		if !ok {
+			s = r.Severity()
+		}
+		if s.rank() == 0 {
+			continue
+		}
+		sr := sarifRule{ID: r.Name(), ShortDescription: sarifMessage{Text: r.Description()}}
+		sr.DefaultConfiguration.Level = s
+		index[r.Name()] = len(driver.Rules)
+		driver.Rules = append(driver.Rules, sr)
+	}
+	results := make([]sarifResult, 0, len(diags))
+	for _, d := range diags {
+		i, ok := index[d.Rule]
+		if !ok {
+			return fmt.Errorf("schemalint: diagnostic of unknown rule %q", d.Rule)
+		}
+		results = append(results, sarifResult{
+			RuleID:    d.Rule,
+			RuleIndex: i,
+			Level:     d.Severity,
+			Message:   sarifMessage{Text: d.Message},
+			Locations: []sarifLocation{location(d)},
+		})
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(sarifLog{
+		Schema:  "https://json.schemastore.org/sarif-2.1.0.json",
+		Version: "2.1.0",
+		Runs:    []sarifRun{{Tool: sarifTool{Driver: driver}, Results: results}},
+	})
+}
+
+// location returns the SARIF location of a diagnostic. The schema file is
+// made relative to the working directory, where code-scanning tools expect
+// paths relative to the repository root.
+func location(d Diagnostic) sarifLocation {
+	loc := sarifLocation{
+		LogicalLocations: []sarifLogicalLocation{{FullyQualifiedName: d.path(), Kind: "type"}},
+	}
+	if d.Field != "" {
+		loc.LogicalLocations[0].Kind = "member"
+	}
+	file, line := d.Pos, 0
+	if i := strings.LastIndexByte(d.Pos, ':'); i > 0 {
+		if n, err := strconv.Atoi(d.Pos[i+1:]); err == nil {
+			file, line = d.Pos[:i], n
+		}
+	}
+	if file == "" {
+		return loc
+	}
+	if wd, err := os.Getwd(); err == nil && filepath.IsAbs(file) {
+		if rel, err := filepath.Rel(wd, file); err == nil && !strings.HasPrefix(rel, "..") {
+			file = rel
+		}
+	}
+	loc.PhysicalLocation = &sarifPhysicalLocation{}
+	loc.PhysicalLocation.ArtifactLocation.URI = filepath.ToSlash(file)
+	if line > 0 {
+		loc.PhysicalLocation.Region = &sarifRegion{StartLine: line}
+	}
+	return loc
+}
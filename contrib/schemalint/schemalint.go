@@ -0,0 +1,310 @@
+// Package schemalint checks a velox schema graph against team conventions.
+//
+// gen.Graph.Validate rejects graphs that cannot be generated. The lint rules
+// of this package report graphs that can be generated, but break a convention,
+// such as foreign keys without an index or enum values that are not
+// UPPER_SNAKE. Each rule has a default severity that can be overridden, or set
+// to Off to disable the rule. The diagnostics are logged, and can be written as
+// a SARIF report for code-scanning tools in CI.
+//
+// # Usage
+//
+// Add the extension to your generate.go. It lints the graph before the code
+// generation, and fails it if a diagnostic is an error:
+//
+//	ex, err := schemalint.NewExtension(
+//	    schemalint.WithSeverity("timestamps", schemalint.Off),
+//	    schemalint.WithSeverity("string-max-len", schemalint.Error),
+//	    schemalint.WithRules(NoPluralTables{}),
+//	    schemalint.WithSARIF("./schemalint.sarif"),
+//	)
+//	if err != nil {
+//	    log.Fatalf("creating schemalint extension: %v", err)
+//	}
+//	err = compiler.Generate("./schema", cfg, compiler.Extensions(ex))
+//
+// Or lint a loaded graph directly:
+//
+//	g, err := compiler.LoadGraph("./schema", cfg)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	for _, d := range schemalint.Lint(g, schemalint.DefaultRules()...) {
+//	    fmt.Println(d)
+//	}
+package schemalint
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/syssam/velox/compiler"
+	"github.com/syssam/velox/compiler/gen"
+)
+
+// Severity is the severity of a diagnostic. Apart from Off, the values match
+// the result levels of SARIF.
+type Severity string
+
+// Supported severities, from the least to the most severe.
+const (
+	// Off disables a rule.
+	Off Severity = "off"
+	// Note reports a diagnostic for information.
+	Note Severity = "note"
+	// Warning reports a diagnostic that should be fixed.
+	Warning Severity = "warning"
+	// Error reports a diagnostic that fails the code generation.
+	Error Severity = "error"
+)
+
+// rank orders the severities.
+func (s Severity) rank() int {
+	switch s {
+	case Note:
+		return 1
+	case Warning:
+		return 2
+	case Error:
+		return 3
+	default:
+		return 0
+	}
+}
+
+// LintRule checks a convention on the schema graph.
+//
+//	type NoPluralTables struct{}
+//
+//	func (NoPluralTables) Name() string                 { return "no-plural-tables" }
+//	func (NoPluralTables) Description() string          { return "Table names are singular." }
+//	func (NoPluralTables) Severity() schemalint.Severity { return schemalint.Warning }
+//	func (NoPluralTables) Check(g *gen.Graph) []schemalint.Diagnostic { ... }
+type LintRule interface {
+	// Name returns the identifier of the rule, e.g. "fk-index". It is used
+	// for severity overrides and as the SARIF rule ID.
+	Name() string
+	// Description returns a one-line description of the convention.
+	Description() string
+	// Severity returns the default severity of the rule diagnostics.
+	Severity() Severity
+	// Check returns the violations of the rule in the graph. The Rule and
+	// Severity of the returned diagnostics are set by the linter.
+	Check(g *gen.Graph) []Diagnostic
+}
+
+// Diagnostic is a violation of a lint rule.
+type Diagnostic struct {
+	Rule     string   // Name of the rule.
+	Severity Severity // Severity of the diagnostic.
+	Type     string   // Schema type, e.g. "User".
+	Field    string   // Field or edge name, if any.
+	Message  string   // Description of the violation.
+	Pos      string   // "filename:line" of the schema type, if known.
+}
+
+// String formats the diagnostic as "pos: Type.field: message (rule)".
+func (d Diagnostic) String() string {
+	var b bytes.Buffer
+	if d.Pos != "" {
+		b.WriteString(d.Pos + ": ")
+	}
+	b.WriteString(d.path())
+	fmt.Fprintf(&b, ": %s (%s)", d.Message, d.Rule)
+	return b.String()
+}
+
+// path returns the schema path of the diagnostic, e.g. "User.email".
+func (d Diagnostic) path() string {
+	if d.Field == "" {
+		return d.Type
+	}
+	return d.Type + "." + d.Field
+}
+
+// Lint runs the rules on the graph with their default severity, and returns
+// the diagnostics sorted by position.
+func Lint(g *gen.Graph, rules ...LintRule) []Diagnostic {
+	return lint(g, rules, nil)
+}
+
+func lint(g *gen.Graph, rules []LintRule, severity map[string]Severity) []Diagnostic {
+	pos := make(map[string]string, len(g.Nodes))
+	for _, t := range g.Nodes {
+		pos[t.Name] = t.Pos()
+	}
+	var diags []Diagnostic
+	for _, r := range rules {
+		s, ok := severity[r.Name()]
+		if !ok {
+			s = r.Severity()
+		}
+		if s.rank() == 0 {
+			continue
+		}
+		for _, d := range r.Check(g) {
+			d.Rule, d.Severity = r.Name(), s
+			if d.Pos == "" {
+				d.Pos = pos[d.Type]
+			}
+			diags = append(diags, d)
+		}
+	}
+	slices.SortStableFunc(diags, func(a, b Diagnostic) int {
+		if a.Type != b.Type {
+			return indexOf(g, a.Type) - indexOf(g, b.Type)
+		}
+		return 0
+	})
+	return diags
+}
+
+// indexOf returns the position of the type in the graph.
+func indexOf(g *gen.Graph, name string) int {
+	return slices.IndexFunc(g.Nodes, func(t *gen.Type) bool { return t.Name == name })
+}
+
+// Extension implements the compiler.Extension interface. It lints the graph
+// before the code generation.
+type Extension struct {
+	compiler.DefaultExtension
+	rules    []LintRule
+	severity map[string]Severity
+	sarif    string
+	failOn   Severity
+}
+
+// ExtensionOption configures the Extension.
+type ExtensionOption func(*Extension) error
+
+// WithRules adds custom rules to the built-in rules.
+func WithRules(rules ...LintRule) ExtensionOption {
+	return func(e *Extension) error {
+		for _, r := range rules {
+			if e.rule(r.Name()) != nil {
+				return fmt.Errorf("schemalint: duplicate rule %q", r.Name())
+			}
+			e.rules = append(e.rules, r)
+		}
+		return nil
+	}
+}
+
+// WithSeverity overrides the severity of the named rule. Off disables it.
+func WithSeverity(rule string, s Severity) ExtensionOption {
+	return func(e *Extension) error {
+		if s != Off && s.rank() == 0 {
+			return fmt.Errorf("schemalint: unknown severity %q", s)
+		}
+		e.severity[rule] = s
+		return nil
+	}
+}
+
+// WithSARIF writes the diagnostics as a SARIF report to the given path.
+func WithSARIF(path string) ExtensionOption {
+	return func(e *Extension) error {
+		e.sarif = path
+		return nil
+	}
+}
+
+// WithFailOn sets the minimal severity that fails the code generation.
+// Defaults to Error. Off never fails it.
+func WithFailOn(s Severity) ExtensionOption {
+	return func(e *Extension) error {
+		if s != Off && s.rank() == 0 {
+			return fmt.Errorf("schemalint: unknown severity %q", s)
+		}
+		e.failOn = s
+		return nil
+	}
+}
+
+// NewExtension creates a new schemalint extension with the built-in rules
+// and the given options.
+func NewExtension(opts ...ExtensionOption) (*Extension, error) {
+	ex := &Extension{
+		rules:    DefaultRules(),
+		severity: make(map[string]Severity),
+		failOn:   Error,
+	}
+	for _, opt := range opts {
+		if err := opt(ex); err != nil {
+			return nil, err
+		}
+	}
+	for name := range ex.severity {
+		if ex.rule(name) == nil {
+			return nil, fmt.Errorf("schemalint: unknown rule %q", name)
+		}
+	}
+	return ex, nil
+}
+
+// rule returns the rule with the given name, or nil.
+func (e *Extension) rule(name string) LintRule {
+	for _, r := range e.rules {
+		if r.Name() == name {
+			return r
+		}
+	}
+	return nil
+}
+
+// Lint runs the rules of the extension on the graph.
+func (e *Extension) Lint(g *gen.Graph) []Diagnostic {
+	return lint(g, e.rules, e.severity)
+}
+
+// Hooks returns the hook that lints the graph.
+func (e *Extension) Hooks() []gen.Hook {
+	return []gen.Hook{
+		func(next gen.Generator) gen.Generator {
+			return gen.GenerateFunc(func(g *gen.Graph) error {
+				if err := e.check(g); err != nil {
+					return err
+				}
+				return next.Generate(g)
+			})
+		},
+	}
+}
+
+// check lints the graph, logs and reports the diagnostics, and returns an
+// error if any of them reaches the failOn severity.
+func (e *Extension) check(g *gen.Graph) error {
+	diags := e.Lint(g)
+	var failed []error
+	for _, d := range diags {
+		switch {
+		case e.failOn.rank() > 0 && d.Severity.rank() >= e.failOn.rank():
+			failed = append(failed, errors.New(d.String()))
+		case d.Severity == Note:
+			slog.Info("schemalint: " + d.String())
+		default:
+			slog.Warn("schemalint: " + d.String())
+		}
+	}
+	if e.sarif != "" {
+		if err := os.MkdirAll(filepath.Dir(e.sarif), 0o755); err != nil {
+			return fmt.Errorf("schemalint: %w", err)
+		}
+		var b bytes.Buffer
+		if err := WriteSARIF(&b, e.rules, e.severity, diags); err != nil {
+			return err
+		}
+		if _, err := gen.WriteFileIfChanged(e.sarif, b.Bytes(), 0o644); err != nil {
+			return fmt.Errorf("schemalint: write %s: %w", e.sarif, err)
+		}
+	}
+	if len(failed) > 0 {
+		return fmt.Errorf("schemalint: %d violation(s):\n%w", len(failed), errors.Join(failed...))
+	}
+	return nil
+}
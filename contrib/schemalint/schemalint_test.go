@@ -0,0 +1,256 @@
+package schemalint
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/syssam/velox"
+	"github.com/syssam/velox/compiler/gen"
+	"github.com/syssam/velox/compiler/load"
+	"github.com/syssam/velox/contrib/graphql"
+	"github.com/syssam/velox/schema"
+	"github.com/syssam/velox/schema/edge"
+	"github.com/syssam/velox/schema/field"
+	"github.com/syssam/velox/schema/index"
+)
+
+type User struct{ velox.Schema }
+
+func (User) Fields() []velox.Field {
+	return []velox.Field{
+		field.String("name").MaxLen(100),
+		field.String("email").Unique().Sensitive(),
+		field.String("password").MaxLen(72).Sensitive().
+			Annotations(graphql.Skip(graphql.SkipType)),
+		field.Enum("status").Values("ACTIVE", "on_hold").Default("ACTIVE"),
+		field.String("nick").MaxLen(50).Optional(),
+		field.Text("bio").Optional().Nillable(),
+		field.Time("created_at"),
+		field.Time("updated_at"),
+	}
+}
+
+func (User) Edges() []velox.Edge {
+	return []velox.Edge{
+		edge.To("posts", Post.Type),
+		edge.To("groups", Group.Type),
+	}
+}
+
+type Post struct{ velox.Schema }
+
+func (Post) Fields() []velox.Field {
+	return []velox.Field{
+		field.String("title").MaxLen(200),
+		field.Int("author_id"),
+	}
+}
+
+func (Post) Edges() []velox.Edge {
+	return []velox.Edge{
+		edge.From("author", User.Type).Ref("posts").Unique().Required().Field("author_id"),
+	}
+}
+
+type Group struct{ velox.Schema }
+
+func (Group) Fields() []velox.Field {
+	return []velox.Field{
+		field.String("title").MaxLen(10),
+		field.Time("created_at"),
+		field.Time("updated_at"),
+	}
+}
+
+func (Group) Edges() []velox.Edge {
+	return []velox.Edge{
+		edge.From("users", User.Type).Ref("groups"),
+		edge.To("owner", User.Type).Unique(),
+	}
+}
+
+func (Group) Indexes() []velox.Index {
+	return []velox.Index{index.Edges("owner")}
+}
+
+func (Group) Annotations() []schema.Annotation {
+	return []schema.Annotation{graphql.Skip(graphql.SkipType)}
+}
+
+func testGraph(t *testing.T) *gen.Graph {
+	t.Helper()
+	var schemas []*load.Schema
+	for _, s := range []velox.Interface{User{}, Post{}, Group{}} {
+		b, err := load.MarshalSchema(s)
+		require.NoError(t, err)
+		ls, err := load.UnmarshalSchema(b)
+		require.NoError(t, err)
+		ls.Pos = filepath.Join("schema", strings.ToLower(ls.Name)+".go") + ":10"
+		schemas = append(schemas, ls)
+	}
+	storage, err := gen.NewStorage("sql")
+	require.NoError(t, err)
+	g, err := gen.NewGraph(&gen.Config{
+		Package:     "example.com/velox",
+		Target:      t.TempDir(),
+		Storage:     storage,
+		IDType:      &field.TypeInfo{Type: field.TypeInt},
+		Annotations: gen.Annotations{"GraphQL": struct{}{}},
+	}, schemas...)
+	require.NoError(t, err)
+	return g
+}
+
+// report returns the diagnostics as "rule Type.field" strings.
+func report(diags []Diagnostic) []string {
+	var lines []string
+	for _, d := range diags {
+		lines = append(lines, d.Rule+" "+d.path())
+	}
+	return lines
+}
+
+func TestLint(t *testing.T) {
+	g := testGraph(t)
+	diags := Lint(g, DefaultRules()...)
+	assert.Equal(t, []string{
+		"string-max-len User.email",
+		"optional-nillable User.nick",
+		"m2m-through User.groups",
+		"enum-upper-snake User.status",
+		"sensitive-graphql User.email",
+		"fk-index Post.author_id",
+		"timestamps Post",
+		"timestamps Post",
+	}, report(diags))
+	assert.Equal(t, Error, diags[4].Severity)
+	assert.Equal(t, `schema/post.go:10: Post.author_id: foreign-key column "author_id" has no index (fk-index)`, diags[5].String())
+
+	t.Run("Timestamps", func(t *testing.T) {
+		diags := Lint(g, Timestamps("deleted_at"))
+		assert.Equal(t, []string{"timestamps User", "timestamps Post", "timestamps Group"}, report(diags))
+		assert.Equal(t, `table "users" has no "deleted_at" timestamp field`, diags[0].Message)
+	})
+
+	t.Run("NoGraphQL", func(t *testing.T) {
+		g := testGraph(t)
+		delete(g.Annotations, "GraphQL")
+		assert.Empty(t, Lint(g, SensitiveGraphQL()))
+	})
+}
+
+// noPosts is a custom rule reporting every Post type.
+type noPosts struct{}
+
+func (noPosts) Name() string        { return "no-posts" }
+func (noPosts) Description() string { return "No posts." }
+func (noPosts) Severity() Severity  { return Warning }
+func (noPosts) Check(g *gen.Graph) []Diagnostic {
+	return []Diagnostic{{Type: "Post", Message: "posts are not allowed"}}
+}
+
+func TestExtension(t *testing.T) {
+	_, err := NewExtension(WithSeverity("fk-index", "fatal"))
+	require.EqualError(t, err, `schemalint: unknown severity "fatal"`)
+	_, err = NewExtension(WithSeverity("no-posts", Off))
+	require.EqualError(t, err, `schemalint: unknown rule "no-posts"`)
+	_, err = NewExtension(WithRules(FKIndex()))
+	require.EqualError(t, err, `schemalint: duplicate rule "fk-index"`)
+
+	generate := func(t *testing.T, ex *Extension) (bool, error) {
+		hooks := ex.Hooks()
+		require.Len(t, hooks, 1)
+		var called bool
+		err := hooks[0](gen.GenerateFunc(func(*gen.Graph) error {
+			called = true
+			return nil
+		})).Generate(testGraph(t))
+		return called, err
+	}
+
+	t.Run("Fail", func(t *testing.T) {
+		ex, err := NewExtension()
+		require.NoError(t, err)
+		called, err := generate(t, ex)
+		require.EqualError(t, err, "schemalint: 1 violation(s):\n"+
+			`schema/user.go:10: User.email: sensitive field is exposed in the GraphQL output; skip it with graphql.Skip(graphql.SkipType) (sensitive-graphql)`)
+		assert.False(t, called)
+
+		ex, err = NewExtension(WithSeverity("sensitive-graphql", Off), WithFailOn(Warning))
+		require.NoError(t, err)
+		_, err = generate(t, ex)
+		require.ErrorContains(t, err, "schemalint: 3 violation(s):")
+	})
+
+	t.Run("SARIF", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "lint", "schema.sarif")
+		ex, err := NewExtension(
+			WithRules(noPosts{}),
+			WithSeverity("sensitive-graphql", Warning),
+			WithSeverity("timestamps", Off),
+			WithSARIF(path),
+		)
+		require.NoError(t, err)
+		called, err := generate(t, ex)
+		require.NoError(t, err)
+		assert.True(t, called)
+
+		b, err := os.ReadFile(path)
+		require.NoError(t, err)
+		var log struct {
+			Version string `json:"version"`
+			Runs    []struct {
+				Tool struct {
+					Driver struct {
+						Rules []struct {
+							ID                   string `json:"id"`
+							DefaultConfiguration struct {
+								Level string `json:"level"`
+							} `json:"defaultConfiguration"`
+						} `json:"rules"`
+					} `json:"driver"`
+				} `json:"tool"`
+				Results []json.RawMessage `json:"results"`
+			} `json:"runs"`
+		}
+		require.NoError(t, json.Unmarshal(b, &log))
+		assert.Equal(t, "2.1.0", log.Version)
+		require.Len(t, log.Runs, 1)
+		var rules []string
+		for _, r := range log.Runs[0].Tool.Driver.Rules {
+			rules = append(rules, r.ID+"="+r.DefaultConfiguration.Level)
+		}
+		assert.Equal(t, []string{
+			"fk-index=warning", "string-max-len=warning", "optional-nillable=note", "m2m-through=note",
+			"enum-upper-snake=warning", "sensitive-graphql=warning", "no-posts=warning",
+		}, rules)
+		require.Len(t, log.Runs[0].Results, 7)
+		assert.JSONEq(t, `{
+			"ruleId": "fk-index",
+			"ruleIndex": 0,
+			"level": "warning",
+			"message": {"text": "foreign-key column \"author_id\" has no index"},
+			"locations": [{
+				"physicalLocation": {"artifactLocation": {"uri": "schema/post.go"}, "region": {"startLine": 10}},
+				"logicalLocations": [{"fullyQualifiedName": "Post.author_id", "kind": "member"}]
+			}]
+		}`, string(log.Runs[0].Results[5]))
+	})
+}
+
+func TestWriteSARIF(t *testing.T) {
+	var b bytes.Buffer
+	err := WriteSARIF(&b, DefaultRules(), nil, []Diagnostic{{Rule: "no-posts", Type: "Post"}})
+	require.EqualError(t, err, `schemalint: diagnostic of unknown rule "no-posts"`)
+
+	b.Reset()
+	require.NoError(t, WriteSARIF(&b, nil, nil, nil))
+	assert.Contains(t, b.String(), `"results": []`)
+}
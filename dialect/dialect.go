@@ -84,7 +84,7 @@ func DebugWithContext(d Driver, logger func(context.Context, ...any)) Driver {
 
 // Exec logs its params and calls the underlying driver Exec method.
 func (d *DebugDriver) Exec(ctx context.Context, query string, args, v any) error {
-	d.log(ctx, fmt.Sprintf("driver.Exec: query=%v args=%v", query, args))
+	d.log(ctx, fmt.Sprintf("driver.Exec: query=%v args=%v", query, redactArgs(query, args)))
 	return d.Driver.Exec(ctx, query, args, v)
 }
 
@@ -96,13 +96,13 @@ func (d *DebugDriver) ExecContext(ctx context.Context, query string, args ...any
 	if !ok {
 		return nil, fmt.Errorf("Driver.ExecContext is not supported")
 	}
-	d.log(ctx, fmt.Sprintf("driver.ExecContext: query=%v args=%v", query, args))
+	d.log(ctx, fmt.Sprintf("driver.ExecContext: query=%v args=%v", query, RedactArgs(query, args)))
 	return drv.ExecContext(ctx, query, args...)
 }
 
 // Query logs its params and calls the underlying driver Query method.
 func (d *DebugDriver) Query(ctx context.Context, query string, args, v any) error {
-	d.log(ctx, fmt.Sprintf("driver.Query: query=%v args=%v", query, args))
+	d.log(ctx, fmt.Sprintf("driver.Query: query=%v args=%v", query, redactArgs(query, args)))
 	return d.Driver.Query(ctx, query, args, v)
 }
 
@@ -114,7 +114,7 @@ func (d *DebugDriver) QueryContext(ctx context.Context, query string, args ...an
 	if !ok {
 		return nil, fmt.Errorf("Driver.QueryContext is not supported")
 	}
-	d.log(ctx, fmt.Sprintf("driver.QueryContext: query=%v args=%v", query, args))
+	d.log(ctx, fmt.Sprintf("driver.QueryContext: query=%v args=%v", query, RedactArgs(query, args)))
 	return drv.QueryContext(ctx, query, args...)
 }
 
@@ -155,7 +155,7 @@ type DebugTx struct {
 
 // Exec logs its params and calls the underlying transaction Exec method.
 func (d *DebugTx) Exec(ctx context.Context, query string, args, v any) error {
-	d.log(ctx, fmt.Sprintf("Tx(%s).Exec: query=%v args=%v", d.id, query, args))
+	d.log(ctx, fmt.Sprintf("Tx(%s).Exec: query=%v args=%v", d.id, query, redactArgs(query, args)))
 	return d.Tx.Exec(ctx, query, args, v)
 }
 
@@ -167,13 +167,13 @@ func (d *DebugTx) ExecContext(ctx context.Context, query string, args ...any) (s
 	if !ok {
 		return nil, fmt.Errorf("Tx.ExecContext is not supported")
 	}
-	d.log(ctx, fmt.Sprintf("Tx(%s).ExecContext: query=%v args=%v", d.id, query, args))
+	d.log(ctx, fmt.Sprintf("Tx(%s).ExecContext: query=%v args=%v", d.id, query, RedactArgs(query, args)))
 	return drv.ExecContext(ctx, query, args...)
 }
 
 // Query logs its params and calls the underlying transaction Query method.
 func (d *DebugTx) Query(ctx context.Context, query string, args, v any) error {
-	d.log(ctx, fmt.Sprintf("Tx(%s).Query: query=%v args=%v", d.id, query, args))
+	d.log(ctx, fmt.Sprintf("Tx(%s).Query: query=%v args=%v", d.id, query, redactArgs(query, args)))
 	return d.Tx.Query(ctx, query, args, v)
 }
 
@@ -185,7 +185,7 @@ func (d *DebugTx) QueryContext(ctx context.Context, query string, args ...any) (
 	if !ok {
 		return nil, fmt.Errorf("Tx.QueryContext is not supported")
 	}
-	d.log(ctx, fmt.Sprintf("Tx(%s).QueryContext: query=%v args=%v", d.id, query, args))
+	d.log(ctx, fmt.Sprintf("Tx(%s).QueryContext: query=%v args=%v", d.id, query, RedactArgs(query, args)))
 	return drv.QueryContext(ctx, query, args...)
 }
 
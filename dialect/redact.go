@@ -0,0 +1,517 @@
+package dialect
+
+import (
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// Redacted replaces the arguments bound to sensitive columns in the
+// statements printed by the logging drivers.
+const Redacted = "[REDACTED]"
+
+var (
+	sensitiveMu   sync.RWMutex
+	sensitiveCols = map[string]map[string]bool{}
+	showSensitive atomic.Bool
+)
+
+// RegisterSensitiveColumns registers the sensitive columns of a table. The
+// logging drivers (DebugDriver, and the LogDriver and slow query log of the
+// sql package) print Redacted instead of the arguments bound to them. The
+// generated code registers the columns of the fields marked as Sensitive.
+func RegisterSensitiveColumns(table string, columns ...string) {
+	if len(columns) == 0 {
+		return
+	}
+	sensitiveMu.Lock()
+	defer sensitiveMu.Unlock()
+	cols := maps.Clone(sensitiveCols[table])
+	if cols == nil {
+		cols = make(map[string]bool, len(columns))
+	}
+	for _, c := range columns {
+		cols[c] = true
+	}
+	sensitiveCols[table] = cols
+}
+
+// ShowSensitiveArgs makes the logging drivers print the arguments of the
+// sensitive columns as they are, e.g. in local development. It should not
+// be enabled in production.
+func ShowSensitiveArgs(show bool) {
+	showSensitive.Store(show)
+}
+
+// RedactArgs returns the arguments of the query with the ones bound to
+// sensitive columns replaced by Redacted. The arguments are returned as
+// they are if none of them is redacted.
+//
+// The arguments are matched to columns by reading the query: the column
+// lists of INSERT and VALUES statements, assignments and comparisons
+// (c = ?, c IN (?, ?), c BETWEEN ? AND ?) and the branches of CASE
+// expressions. The arguments that cannot be matched to a column are
+// redacted if the query references a table with sensitive columns.
+func RedactArgs(query string, args []any) []any {
+	if len(args) == 0 || showSensitive.Load() {
+		return args
+	}
+	tables := sensitiveTables(query)
+	if len(tables) == 0 {
+		return args
+	}
+	s := &argScanner{tables: tables, aliases: make(map[string]string)}
+	redact := s.scan(query, len(args))
+	if !slices.Contains(redact, true) {
+		return args
+	}
+	vs := slices.Clone(args)
+	for i, r := range redact {
+		if r {
+			vs[i] = Redacted
+		}
+	}
+	return vs
+}
+
+// redactArgs is RedactArgs for the arguments of an ExecQuerier.
+func redactArgs(query string, args any) any {
+	if vs, ok := args.([]any); ok {
+		return RedactArgs(query, vs)
+	}
+	return args
+}
+
+// sensitiveTables returns the sensitive columns of the
+// registered tables whose names appear in the query.
+func sensitiveTables(query string) map[string]map[string]bool {
+	sensitiveMu.RLock()
+	defer sensitiveMu.RUnlock()
+	var tables map[string]map[string]bool
+	for t, cols := range sensitiveCols {
+		if strings.Contains(query, t) {
+			if tables == nil {
+				tables = make(map[string]map[string]bool)
+			}
+			tables[t] = cols
+		}
+	}
+	return tables
+}
+
+type (
+	// sqlToken is a token of an SQL statement.
+	sqlToken struct {
+		kind tokenKind
+		text string // name of identifiers and words, operators and punctuation.
+		arg  int    // index of the argument of placeholders.
+	}
+	tokenKind uint8
+)
+
+const (
+	tokIdent tokenKind = iota // quoted identifier.
+	tokWord                   // keyword or unquoted identifier.
+	tokArg                    // placeholder.
+	tokQMark                  // ? placeholder, or a PostgreSQL operator.
+	tokPunct                  // ( ) , . ;
+	tokOther                  // literals and operators.
+)
+
+// tokenize returns the tokens of the query, without comments. The question
+// marks are placeholders, unless the query has numbered placeholders ($1).
+func tokenize(query string) []sqlToken {
+	var (
+		tokens   []sqlToken
+		numbered bool
+	)
+	for i := 0; i < len(query); {
+		c := query[i]
+		switch {
+		case c == ' ', c == '\t', c == '\n', c == '\r':
+			i++
+		case c == '-' && strings.HasPrefix(query[i:], "--"):
+			if j := strings.IndexByte(query[i:], '\n'); j >= 0 {
+				i += j + 1
+			} else {
+				i = len(query)
+			}
+		case c == '/' && strings.HasPrefix(query[i:], "/*"):
+			if j := strings.Index(query[i+2:], "*/"); j >= 0 {
+				i += j + 4
+			} else {
+				i = len(query)
+			}
+		case c == '\'', c == '"', c == '`':
+			j := quoted(query, i)
+			if c == '\'' {
+				tokens = append(tokens, sqlToken{kind: tokOther})
+			} else {
+				name := strings.ReplaceAll(query[i+1:j-1], string([]byte{c, c}), string(c))
+				tokens = append(tokens, sqlToken{kind: tokIdent, text: name})
+			}
+			i = j
+		case c == '$' && i+1 < len(query) && isDigit(query[i+1]):
+			j, n := i+1, 0
+			for ; j < len(query) && isDigit(query[j]); j++ {
+				n = n*10 + int(query[j]-'0')
+			}
+			tokens = append(tokens, sqlToken{kind: tokArg, arg: n - 1})
+			numbered = true
+			i = j
+		case c == '?':
+			tokens = append(tokens, sqlToken{kind: tokQMark, text: "?"})
+			i++
+		case isWordStart(c):
+			j := i + 1
+			for j < len(query) && (isWordStart(query[j]) || isDigit(query[j])) {
+				j++
+			}
+			tokens = append(tokens, sqlToken{kind: tokWord, text: query[i:j]})
+			i = j
+		case strings.IndexByte("(),.;", c) >= 0:
+			tokens = append(tokens, sqlToken{kind: tokPunct, text: string(c)})
+			i++
+		default:
+			tokens = append(tokens, sqlToken{kind: tokOther, text: string(c)})
+			i++
+		}
+	}
+	n := 0
+	for i, t := range tokens {
+		if t.kind != tokQMark {
+			continue
+		}
+		if numbered {
+			tokens[i].kind = tokOther
+		} else {
+			tokens[i] = sqlToken{kind: tokArg, arg: n}
+			n++
+		}
+	}
+	return tokens
+}
+
+// quoted returns the end of the quoted string or identifier that starts at i.
+func quoted(s string, i int) int {
+	q := s[i]
+	for j := i + 1; j < len(s); j++ {
+		if s[j] != q {
+			continue
+		}
+		if j+1 < len(s) && s[j+1] == q {
+			j++
+			continue
+		}
+		return j + 1
+	}
+	return len(s)
+}
+
+func isDigit(c byte) bool { return c >= '0' && c <= '9' }
+
+func isWordStart(c byte) bool {
+	return c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
+
+type (
+	// argScanner matches the placeholders of a statement to columns.
+	argScanner struct {
+		tables  map[string]map[string]bool // sensitive columns of the registered tables.
+		aliases map[string]string          // table aliases.
+		stmt    []string                   // tables of the statement.
+		frames  []*argFrame
+	}
+	// argFrame is the state of a parenthesized part of a statement.
+	argFrame struct {
+		col       *colRef   // column of the current expression.
+		last      *colRef   // last column referenced in the frame.
+		list      []*colRef // the SELECT or INSERT column list of the frame.
+		listing   bool      // reading a SELECT list.
+		colList   bool      // the frame is an INSERT column list.
+		values    []*colRef // columns of the VALUES tuples of the frame.
+		tuple     bool      // the frame is a VALUES tuple.
+		pos       int       // position in the list or tuple.
+		query     bool      // the frame is a subquery.
+		between   bool
+		cases     []*caseExpr
+		intoTable bool // an INSERT column list may follow.
+	}
+	// caseExpr is a CASE expression of a frame.
+	caseExpr struct {
+		target  *colRef // the column that is compared to, or assigned, the CASE expression.
+		operand *colRef // the column of a simple CASE expression (CASE c WHEN ...).
+	}
+	// colRef is a (possibly qualified) column reference.
+	colRef struct {
+		table, name string
+	}
+)
+
+// safeRef is the column of placeholders that are not column values (e.g. LIMIT ?).
+var safeRef = &colRef{}
+
+// resetWords are the keywords that end the expression of a column.
+var resetWords = map[string]bool{
+	"OR": true, "WHERE": true, "SET": true, "ON": true, "HAVING": true, "RETURNING": true,
+	"ORDER": true, "GROUP": true, "UNION": true, "DO": true, "CONFLICT": true, "DUPLICATE": true,
+}
+
+// scan returns which of the n arguments of the query are bound to sensitive columns.
+func (s *argScanner) scan(query string, n int) []bool {
+	var (
+		tokens = tokenize(query)
+		redact = make([]bool, n)
+		top    = &argFrame{}
+	)
+	s.frames = []*argFrame{top}
+	s.stmtTables(tokens)
+	for i := 0; i < len(tokens); i++ {
+		t, f := tokens[i], s.frames[len(s.frames)-1]
+		switch t.kind {
+		case tokArg:
+			if t.arg < 0 || t.arg >= n {
+				continue
+			}
+			col := s.argColumn()
+			redact[t.arg] = redact[t.arg] || col == nil || s.sensitive(col)
+		case tokIdent:
+			i = s.columnRef(tokens, i)
+		case tokWord:
+			i = s.word(tokens, i)
+		case tokPunct:
+			switch t.text {
+			case "(":
+				child := &argFrame{colList: f.intoTable}
+				if f.values != nil {
+					child.tuple, child.values = true, f.values
+				}
+				f.intoTable = false
+				s.frames = append(s.frames, child)
+			case ")":
+				if len(s.frames) == 1 {
+					continue
+				}
+				s.frames = s.frames[:len(s.frames)-1]
+				parent := s.frames[len(s.frames)-1]
+				switch {
+				case f.colList:
+					parent.list = f.list
+				case f.tuple:
+				case parent.col == nil && !f.query && f.last != nil:
+					// The column of a function call, e.g. LOWER(c) = LOWER(?).
+					parent.col, parent.last = f.last, f.last
+				}
+			case ",":
+				f.pos++
+				f.col = nil
+			case ";":
+				s.frames = []*argFrame{{}}
+			}
+		}
+	}
+	return redact
+}
+
+// argColumn returns the column of the current placeholder, or nil if it is unknown.
+func (s *argScanner) argColumn() *colRef {
+	f := s.frames[len(s.frames)-1]
+	if f.tuple {
+		if f.pos < len(f.values) {
+			return f.values[f.pos]
+		}
+		return nil
+	}
+	for i := len(s.frames) - 1; i >= 0; i-- {
+		if c := s.frames[i].col; c != nil {
+			return c
+		}
+		if s.frames[i].query {
+			break
+		}
+	}
+	return nil
+}
+
+// columnRef reads the (possibly qualified) column reference at i, and
+// returns the position of its last token.
+func (s *argScanner) columnRef(tokens []sqlToken, i int) int {
+	f := s.frames[len(s.frames)-1]
+	ref := &colRef{name: tokens[i].text}
+	for i+2 < len(tokens) && tokens[i+1].text == "." && (tokens[i+2].kind == tokIdent || tokens[i+2].kind == tokWord) {
+		ref = &colRef{table: ref.name, name: tokens[i+2].text}
+		i += 2
+	}
+	// A star, e.g. "t".*, is not a column.
+	if i+2 < len(tokens) && tokens[i+1].text == "." && tokens[i+2].text == "*" {
+		return i + 2
+	}
+	f.col, f.last = ref, ref
+	if f.listing || f.colList {
+		for len(f.list) <= f.pos {
+			f.list = append(f.list, nil)
+		}
+		if f.list[f.pos] == nil {
+			f.list[f.pos] = ref
+		}
+	}
+	return i
+}
+
+// word handles the keyword or identifier at i, and returns the position of its last token.
+func (s *argScanner) word(tokens []sqlToken, i int) int {
+	f := s.frames[len(s.frames)-1]
+	switch w := strings.ToUpper(tokens[i].text); w {
+	case "SELECT":
+		f.listing, f.list, f.pos, f.query = true, nil, 0, true
+		f.values = nil
+	case "FROM", "JOIN", "INTO", "UPDATE":
+		f.listing, f.values, f.col = false, nil, nil
+		return s.table(tokens, i, w == "INTO")
+	case "VALUES":
+		f.values = f.list
+		if f.values == nil {
+			// Unknown columns, e.g. VALUES without a column list.
+			f.values = []*colRef{}
+		}
+		f.pos = 0
+	case "AND":
+		if f.between {
+			f.between = false
+		} else {
+			f.col = nil
+		}
+	case "BETWEEN":
+		f.between = true
+	case "LIMIT", "OFFSET":
+		f.col = safeRef
+	case "CASE":
+		c := &caseExpr{target: f.col}
+		f.cases = append(f.cases, c)
+		f.col = nil
+		if i+1 < len(tokens) && tokens[i+1].kind == tokIdent {
+			i = s.columnRef(tokens, i+1)
+			c.operand = f.col
+		}
+	case "WHEN":
+		if len(f.cases) > 0 {
+			f.col = f.cases[len(f.cases)-1].operand
+		}
+	case "THEN", "ELSE":
+		if len(f.cases) > 0 {
+			f.col = f.cases[len(f.cases)-1].target
+		}
+	case "END":
+		if len(f.cases) > 0 {
+			f.col = f.cases[len(f.cases)-1].target
+			f.cases = f.cases[:len(f.cases)-1]
+		}
+	default:
+		switch {
+		case resetWords[w]:
+			f.col, f.values = nil, nil
+		// Function calls are not columns.
+		case i+1 < len(tokens) && tokens[i+1].text == "(":
+		case !keywords[w]:
+			return s.columnRef(tokens, i)
+		}
+	}
+	return i
+}
+
+// table reads the table reference (and its alias) that follows the keyword at i.
+func (s *argScanner) table(tokens []sqlToken, i int, into bool) int {
+	name, ok := func() (string, bool) {
+		if i+1 >= len(tokens) || !isName(tokens[i+1]) {
+			return "", false
+		}
+		i++
+		name := tokens[i].text
+		for i+2 < len(tokens) && tokens[i+1].text == "." && isName(tokens[i+2]) {
+			name = tokens[i+2].text
+			i += 2
+		}
+		return name, true
+	}()
+	if !ok {
+		return i
+	}
+	if i+1 < len(tokens) && strings.EqualFold(tokens[i+1].text, "AS") {
+		i++
+	}
+	if i+1 < len(tokens) && isName(tokens[i+1]) {
+		s.aliases[tokens[i+1].text] = name
+		i++
+	}
+	s.frames[len(s.frames)-1].intoTable = into
+	return i
+}
+
+// stmtTables collects the tables that follow FROM, JOIN, INTO and UPDATE in the statement.
+func (s *argScanner) stmtTables(tokens []sqlToken) {
+	for i := 0; i+1 < len(tokens); i++ {
+		if tokens[i].kind != tokWord {
+			continue
+		}
+		switch strings.ToUpper(tokens[i].text) {
+		case "FROM", "JOIN", "INTO", "UPDATE":
+			if j := i + 1; isName(tokens[j]) {
+				// Schema-qualified tables.
+				for j+2 < len(tokens) && tokens[j+1].text == "." && isName(tokens[j+2]) {
+					j += 2
+				}
+				s.stmt = append(s.stmt, tokens[j].text)
+			}
+		}
+	}
+}
+
+// sensitive reports if the column is a sensitive column of the tables of the statement.
+func (s *argScanner) sensitive(c *colRef) bool {
+	if c == safeRef {
+		return false
+	}
+	table := c.table
+	if t, ok := s.aliases[table]; ok {
+		table = t
+	}
+	if cols, ok := s.tables[table]; ok {
+		return cols[c.name]
+	}
+	// Unqualified columns, or unknown qualifiers (e.g. of derived tables).
+	for _, t := range s.stmt {
+		if s.tables[t][c.name] {
+			return true
+		}
+	}
+	if len(s.stmt) > 0 {
+		return false
+	}
+	for _, cols := range s.tables {
+		if cols[c.name] {
+			return true
+		}
+	}
+	return false
+}
+
+// isName reports if the token is a (quoted or unquoted) identifier.
+func isName(t sqlToken) bool {
+	return t.kind == tokIdent || t.kind == tokWord && !keywords[strings.ToUpper(t.text)]
+}
+
+// keywords are the SQL keywords that are not identifiers.
+var keywords = map[string]bool{
+	"ALL": true, "AND": true, "AS": true, "ASC": true, "BETWEEN": true, "BY": true, "CASE": true, "CAST": true,
+	"CONFLICT": true, "CROSS": true, "DEFAULT": true, "DELETE": true, "DESC": true, "DISTINCT": true,
+	"DO": true, "DUPLICATE": true, "ELSE": true, "END": true, "ESCAPE": true, "EXCEPT": true, "EXISTS": true,
+	"FALSE": true, "FOR": true, "FROM": true, "FULL": true, "GROUP": true, "HAVING": true, "ILIKE": true,
+	"IN": true, "INNER": true, "INSERT": true, "INTERSECT": true, "INTO": true, "IS": true, "JOIN": true,
+	"KEY": true, "LEFT": true, "LIKE": true, "LIMIT": true, "LOCKED": true, "NOT": true, "NOTHING": true,
+	"NULL": true, "NULLS": true, "OFFSET": true, "ON": true, "OR": true, "ORDER": true, "OUTER": true,
+	"RETURNING": true, "RIGHT": true, "SELECT": true, "SET": true, "SHARE": true, "SKIP": true, "THEN": true,
+	"TRUE": true, "UNION": true, "UPDATE": true, "USING": true, "VALUES": true, "WHEN": true, "WHERE": true,
+	"WITH": true,
+}
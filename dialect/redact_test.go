@@ -0,0 +1,150 @@
+package dialect
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func init() {
+	RegisterSensitiveColumns("redact_users", "password")
+	RegisterSensitiveColumns("redact_users", "token")
+	RegisterSensitiveColumns("redact_cards", "number")
+}
+
+func TestRedactArgs(t *testing.T) {
+	t.Parallel()
+	const r = Redacted
+	tests := []struct {
+		name  string
+		query string
+		args  []any
+		want  []any
+	}{
+		{
+			name:  "insert",
+			query: "INSERT INTO `redact_users` (`name`, `password`, `age`) VALUES (?, ?, ?)",
+			args:  []any{"a8m", "secret", 30},
+			want:  []any{"a8m", r, 30},
+		},
+		{
+			name:  "insert multiple rows",
+			query: `INSERT INTO "redact_users" ("name", "password") VALUES ($1, $2), ($3, $4) RETURNING "id"`,
+			args:  []any{"a8m", "s1", "nati", "s2"},
+			want:  []any{"a8m", r, "nati", r},
+		},
+		{
+			name:  "upsert",
+			query: "INSERT INTO `redact_users` (`name`, `token`) VALUES (?, ?) ON DUPLICATE KEY UPDATE `token` = VALUES(`token`), `name` = ?",
+			args:  []any{"a8m", "t1", "a8m"},
+			want:  []any{"a8m", r, "a8m"},
+		},
+		{
+			name:  "update",
+			query: "UPDATE `redact_users` SET `password` = ?, `name` = ? WHERE `redact_users`.`id` = ? AND `token` = ?",
+			args:  []any{"secret", "a8m", 1, "t1"},
+			want:  []any{r, "a8m", 1, r},
+		},
+		{
+			name:  "where",
+			query: `SELECT "t1"."id", "t1"."name" FROM "redact_users" AS "t1" WHERE "t1"."name" = $1 AND "t1"."token" IN ($2, $3) OR "t1"."age" BETWEEN $4 AND $5 LIMIT $6 OFFSET $7`,
+			args:  []any{"a8m", "t1", "t2", 10, 20, 10, 0},
+			want:  []any{"a8m", r, r, 10, 20, 10, 0},
+		},
+		{
+			name:  "between",
+			query: "SELECT * FROM `redact_users` WHERE `password` BETWEEN ? AND ? AND `age` > ?",
+			args:  []any{"a", "b", 1},
+			want:  []any{r, r, 1},
+		},
+		{
+			name:  "function",
+			query: "SELECT * FROM `redact_users` WHERE LOWER(`token`) = LOWER(?) AND `name` = ?",
+			args:  []any{"t1", "a8m"},
+			want:  []any{r, "a8m"},
+		},
+		{
+			name:  "join",
+			query: "SELECT `t1`.`id` FROM `redact_users` AS `t1` JOIN `redact_cards` AS `t2` ON `t1`.`id` = `t2`.`owner_id` WHERE `t2`.`number` = ? AND `t1`.`name` = ?",
+			args:  []any{"4580", "a8m"},
+			want:  []any{r, "a8m"},
+		},
+		{
+			name:  "column of another table",
+			query: "SELECT * FROM `redact_cards` WHERE `password` = ?",
+			args:  []any{"secret"},
+			want:  []any{"secret"},
+		},
+		{
+			name:  "case",
+			query: "UPDATE `redact_users` SET `password` = CASE `id` WHEN ? THEN ? WHEN ? THEN ? ELSE `password` END, `name` = CASE WHEN `id` = ? THEN ? END WHERE `id` IN (?, ?)",
+			args:  []any{1, "s1", 2, "s2", 1, "a8m", 1, 2},
+			want:  []any{1, r, 2, r, 1, "a8m", 1, 2},
+		},
+		{
+			name:  "values list",
+			query: `UPDATE "redact_users" SET "token" = "v"."token", "name" = "v"."name" FROM (SELECT "id", "token", "name" FROM "redact_users" WHERE FALSE UNION ALL VALUES ($1, $2, $3), ($4, $5, $6)) AS "v" ("id", "token", "name") WHERE "redact_users"."id" = "v"."id"`,
+			args:  []any{1, "t1", "a8m", 2, "t2", "nati"},
+			want:  []any{1, r, "a8m", 2, r, "nati"},
+		},
+		{
+			name:  "subquery",
+			query: "SELECT * FROM `redact_cards` WHERE `owner_id` IN (SELECT `id` FROM `redact_users` WHERE `token` = ?) AND `number` <> ?",
+			args:  []any{"t1", "4580"},
+			want:  []any{r, r},
+		},
+		{
+			name:  "unknown column",
+			query: "SELECT * FROM `redact_users` WHERE ? = 1",
+			args:  []any{"t1"},
+			want:  []any{r},
+		},
+		{
+			name:  "unregistered table",
+			query: "SELECT * FROM `pets` WHERE `password` = ?",
+			args:  []any{"secret"},
+			want:  []any{"secret"},
+		},
+		{
+			name:  "string literals",
+			query: "SELECT * FROM `redact_users` WHERE `name` = '`password` = ?' AND `name` = ?",
+			args:  []any{"a8m"},
+			want:  []any{"a8m"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got := RedactArgs(tt.query, tt.args)
+			if fmt.Sprint(got) != fmt.Sprint(tt.want) {
+				t.Errorf("RedactArgs() = %v, want %v", got, tt.want)
+			}
+			if fmt.Sprint(tt.args) == fmt.Sprint(got) && &got[0] != &tt.args[0] {
+				t.Error("RedactArgs() should return the arguments as they are if none is redacted")
+			}
+		})
+	}
+}
+
+func TestDebugDriver_RedactArgs(t *testing.T) {
+	t.Parallel()
+	var logs []string
+	drv := Debug(&mockDriver{dialect: SQLite}, func(v ...any) { logs = append(logs, fmt.Sprint(v...)) })
+	const query = "UPDATE `redact_users` SET `password` = ? WHERE `id` = ?"
+	if err := drv.Exec(context.Background(), query, []any{"secret", 1}, nil); err != nil {
+		t.Fatal(err)
+	}
+	if len(logs) != 1 || strings.Contains(logs[0], "secret") || !strings.Contains(logs[0], "args=["+Redacted+" 1]") {
+		t.Errorf("unexpected log: %v", logs)
+	}
+}
+
+func TestShowSensitiveArgs(t *testing.T) {
+	ShowSensitiveArgs(true)
+	t.Cleanup(func() { ShowSensitiveArgs(false) })
+	args := []any{"secret"}
+	if got := RedactArgs("UPDATE `redact_users` SET `password` = ?", args); got[0] != "secret" {
+		t.Errorf("RedactArgs() = %v, want the arguments as they are", got)
+	}
+}
@@ -373,6 +373,17 @@ func (i *InsertBuilder) OnConflict(opts ...ConflictOption) *InsertBuilder {
 	return i
 }
 
+// ConflictTarget returns the columns of the conflict target that are set by
+// the given options, and reports whether the options update the conflicting
+// rows, rather than ignoring them (DoNothing).
+func ConflictTarget(opts ...ConflictOption) (columns []string, update bool) {
+	c := &conflict{}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c.target.columns, !c.action.nothing
+}
+
 // UpdateSet describes a set of changes of the `DO UPDATE` clause.
 type UpdateSet struct {
 	*UpdateBuilder
@@ -2511,6 +2522,34 @@ func (s *Selector) OrderColumns() []string {
 	return columns
 }
 
+// OrderTerms returns the terms of the `ORDER BY` clause in the Selector,
+// with the expression terms rendered as SQL (e.g. "`users`.`name` DESC").
+func (s *Selector) OrderTerms() []string {
+	terms := make([]string, 0, len(s.order))
+	for i := range s.order {
+		switch t := s.order[i].(type) {
+		case string:
+			terms = append(terms, t)
+		case Querier:
+			query, _ := t.Query()
+			terms = append(terms, query)
+		}
+	}
+	return terms
+}
+
+// LimitOffset returns the `LIMIT` and `OFFSET` of the Selector,
+// or 0 for the clauses that are not set.
+func (s *Selector) LimitOffset() (limit, offset int) {
+	if s.limit != nil {
+		limit = *s.limit
+	}
+	if s.offset != nil {
+		offset = *s.offset
+	}
+	return limit, offset
+}
+
 // OrderExpr appends the `ORDER BY` clause to the `SELECT`
 // statement with custom list of expressions.
 func (s *Selector) OrderExpr(exprs ...Querier) *Selector {
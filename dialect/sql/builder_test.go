@@ -1537,6 +1537,24 @@ func TestSelector_ClearOrder(t *testing.T) {
 	require.Empty(t, args)
 }
 
+func TestSelector_OrderTerms(t *testing.T) {
+	s := Dialect(dialect.Postgres).Select("*").From(Table("users"))
+	s.OrderBy("id")
+	OrderByField("name", OrderDesc(), OrderNullsLast()).ToFunc()(s)
+	require.Equal(t, []string{"id", `"users"."name" DESC NULLS LAST`}, s.OrderTerms())
+	require.Equal(t, []string{"id"}, s.OrderColumns())
+}
+
+func TestSelector_LimitOffset(t *testing.T) {
+	s := Select("*").From(Table("users"))
+	limit, offset := s.LimitOffset()
+	require.Zero(t, limit)
+	require.Zero(t, offset)
+	limit, offset = s.Limit(10).Offset(20).LimitOffset()
+	require.Equal(t, 10, limit)
+	require.Equal(t, 20, offset)
+}
+
 func TestSelector_SelectExpr(t *testing.T) {
 	query, args := SelectExpr(
 		Expr("?", "a"),
@@ -1959,6 +1977,15 @@ func TestInsert_OnConflict(t *testing.T) {
 	})
 }
 
+func TestConflictTarget(t *testing.T) {
+	columns, update := ConflictTarget(ConflictColumns("email"), ResolveWithNewValues())
+	require.Equal(t, []string{"email"}, columns)
+	require.True(t, update)
+	columns, update = ConflictTarget(ConflictConstraint("users_pkey"), DoNothing())
+	require.Empty(t, columns)
+	require.False(t, update)
+}
+
 func TestEscapePatterns(t *testing.T) {
 	q, args := Dialect(dialect.MySQL).
 		Update("users").
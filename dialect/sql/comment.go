@@ -0,0 +1,209 @@
+package sql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"maps"
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/syssam/velox/dialect"
+)
+
+// ctxCommentsKey is the key used for attaching the comment tags.
+type ctxCommentsKey struct{}
+
+// WithComment returns a new context that holds a tag to be added to the
+// comment of every statement executed by a CommentDriver. For example,
+// the route of an HTTP request:
+//
+//	ctx = sql.WithComment(ctx, "route", r.Pattern)
+func WithComment(ctx context.Context, key, value string) context.Context {
+	tags, _ := ctx.Value(ctxCommentsKey{}).(map[string]string)
+	tags = maps.Clone(tags)
+	if tags == nil {
+		tags = make(map[string]string, 1)
+	}
+	tags[key] = value
+	return context.WithValue(ctx, ctxCommentsKey{}, tags)
+}
+
+// CommentFromContext returns the comment tag value from the context.
+func CommentFromContext(ctx context.Context, key string) (string, bool) {
+	tags, _ := ctx.Value(ctxCommentsKey{}).(map[string]string)
+	v, ok := tags[key]
+	return v, ok
+}
+
+// FormatComment formats the tags as a sqlcommenter comment. Keys and values
+// are URL-encoded, values are quoted, and the tags are sorted by key:
+//
+//	/*op='QueryAll',route='%2Fusers'*/
+//
+// It returns an empty string if there are no tags.
+func FormatComment(tags map[string]string) string {
+	if len(tags) == 0 {
+		return ""
+	}
+	var b strings.Builder
+	b.WriteString("/*")
+	for i, k := range slices.Sorted(maps.Keys(tags)) {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteString(commentEscape(k))
+		b.WriteString("='")
+		b.WriteString(commentEscape(tags[k]))
+		b.WriteByte('\'')
+	}
+	b.WriteString("*/")
+	return b.String()
+}
+
+// commentEscape URL-encodes s. Quotes and the "*/" delimiter are
+// encoded as well, so values cannot terminate the comment.
+func commentEscape(s string) string {
+	return url.PathEscape(s)
+}
+
+// CommentDriver wraps a Driver and appends a sqlcommenter comment to every
+// statement, such that the origin of a statement shows up in pg_stat_statements,
+// the slow query log, or the query insights of the database. The comment holds
+// the tags added with WithComment, and the tags of the CommentWithTags functions.
+type CommentDriver struct {
+	*Driver
+	tags []func(context.Context) map[string]string
+}
+
+// CommentOption configures the CommentDriver.
+type CommentOption func(*CommentDriver)
+
+// CommentWithTags adds a function that returns tags for the comment of a
+// statement, given its context. For example, velox.CommentTags adds the
+// entity and operation of the statement, and a function that formats the
+// W3C traceparent of the span in the context links statements to traces.
+// Tags added with WithComment take precedence over the tags of the functions.
+func CommentWithTags(fn func(context.Context) map[string]string) CommentOption {
+	return func(d *CommentDriver) {
+		d.tags = append(d.tags, fn)
+	}
+}
+
+// NewCommentDriver wraps a Driver with sqlcommenter comments.
+//
+// Example:
+//
+//	drv, _ := sql.Open("postgres", dsn)
+//	commentDriver := sql.NewCommentDriver(drv,
+//	    sql.CommentWithTags(velox.CommentTags),
+//	)
+//	client := ent.NewClient(ent.Driver(commentDriver))
+func NewCommentDriver(drv *Driver, opts ...CommentOption) *CommentDriver {
+	d := &CommentDriver{Driver: drv}
+	for _, opt := range opts {
+		opt(d)
+	}
+	return d
+}
+
+// Query executes a query with the comment of its context.
+func (d *CommentDriver) Query(ctx context.Context, query string, args, v any) error {
+	return d.Driver.Query(ctx, d.comment(ctx, query), args, v)
+}
+
+// Exec executes a statement with the comment of its context.
+func (d *CommentDriver) Exec(ctx context.Context, query string, args, v any) error {
+	return d.Driver.Exec(ctx, d.comment(ctx, query), args, v)
+}
+
+// ExecContext executes a statement with the comment of its context.
+func (d *CommentDriver) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	return d.Driver.ExecContext(ctx, d.comment(ctx, query), args...)
+}
+
+// QueryContext executes a query with the comment of its context.
+func (d *CommentDriver) QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error) {
+	return d.Driver.QueryContext(ctx, d.comment(ctx, query), args...)
+}
+
+// Tx starts a transaction whose statements are commented.
+func (d *CommentDriver) Tx(ctx context.Context) (dialect.Tx, error) {
+	tx, err := d.Driver.Tx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &CommentTx{Tx: tx, driver: d}, nil
+}
+
+// BeginTx starts a transaction with options whose statements are commented.
+func (d *CommentDriver) BeginTx(ctx context.Context, opts *TxOptions) (dialect.Tx, error) {
+	tx, err := d.Driver.BeginTx(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	return &CommentTx{Tx: tx, driver: d}, nil
+}
+
+// comment appends the comment of the context to the query. Queries that
+// already end with a comment are left as is, as sqlcommenter requires.
+func (d *CommentDriver) comment(ctx context.Context, query string) string {
+	tags := make(map[string]string)
+	for _, fn := range d.tags {
+		maps.Copy(tags, fn(ctx))
+	}
+	if ctxTags, ok := ctx.Value(ctxCommentsKey{}).(map[string]string); ok {
+		maps.Copy(tags, ctxTags)
+	}
+	c := FormatComment(tags)
+	trimmed := strings.TrimRight(query, " \t\n;")
+	if c == "" || strings.HasSuffix(trimmed, "*/") {
+		return query
+	}
+	return trimmed + " " + c + query[len(trimmed):]
+}
+
+// CommentTx wraps a transaction with sqlcommenter comments.
+type CommentTx struct {
+	dialect.Tx
+	driver *CommentDriver
+}
+
+// Query executes a query within the transaction with the comment of its context.
+func (tx *CommentTx) Query(ctx context.Context, query string, args, v any) error {
+	return tx.Tx.Query(ctx, tx.driver.comment(ctx, query), args, v)
+}
+
+// Exec executes a statement within the transaction with the comment of its context.
+func (tx *CommentTx) Exec(ctx context.Context, query string, args, v any) error {
+	return tx.Tx.Exec(ctx, tx.driver.comment(ctx, query), args, v)
+}
+
+// ExecContext executes a statement within the transaction with the comment of its context.
+func (tx *CommentTx) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	ex, ok := tx.Tx.(interface {
+		ExecContext(context.Context, string, ...any) (sql.Result, error)
+	})
+	if !ok {
+		return nil, errors.New("Tx.ExecContext is not supported")
+	}
+	return ex.ExecContext(ctx, tx.driver.comment(ctx, query), args...)
+}
+
+// QueryContext executes a query within the transaction with the comment of its context.
+func (tx *CommentTx) QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error) {
+	q, ok := tx.Tx.(interface {
+		QueryContext(context.Context, string, ...any) (*sql.Rows, error)
+	})
+	if !ok {
+		return nil, errors.New("Tx.QueryContext is not supported")
+	}
+	return q.QueryContext(ctx, tx.driver.comment(ctx, query), args...)
+}
+
+// Ensure interfaces are implemented.
+var (
+	_ dialect.Driver = (*CommentDriver)(nil)
+	_ dialect.Tx     = (*CommentTx)(nil)
+)
@@ -0,0 +1,95 @@
+package sql
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/syssam/velox/dialect"
+)
+
+func TestFormatComment(t *testing.T) {
+	t.Parallel()
+
+	assert.Empty(t, FormatComment(nil))
+	assert.Equal(t,
+		`/*entity='User',op='QueryAll',route='%2Fusers%2F%7Bid%7D'*/`,
+		FormatComment(map[string]string{"route": "/users/{id}", "op": "QueryAll", "entity": "User"}),
+	)
+	// Values cannot terminate the comment or the quoted value.
+	assert.Equal(t, `/*route='%27%2A%2F%20DROP'*/`, FormatComment(map[string]string{"route": "'*/ DROP"}))
+}
+
+func TestWithComment(t *testing.T) {
+	t.Parallel()
+
+	ctx := WithComment(context.Background(), "route", "/users")
+	child := WithComment(ctx, "route", "/pets")
+	v, ok := CommentFromContext(ctx, "route")
+	require.True(t, ok)
+	assert.Equal(t, "/users", v, "parent context must not be modified")
+	v, ok = CommentFromContext(child, "route")
+	require.True(t, ok)
+	assert.Equal(t, "/pets", v)
+	_, ok = CommentFromContext(context.Background(), "route")
+	assert.False(t, ok)
+}
+
+func TestCommentDriver(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	require.NoError(t, err)
+	drv := NewCommentDriver(OpenDB(dialect.Postgres, db), CommentWithTags(func(context.Context) map[string]string {
+		return map[string]string{"application": "api", "route": "unknown"}
+	}))
+	ctx := WithComment(context.Background(), "route", "/users")
+
+	mock.ExpectQuery(`SELECT "id" FROM "users" /*application='api',route='%2Fusers'*/`).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	rows := &Rows{}
+	require.NoError(t, drv.Query(ctx, `SELECT "id" FROM "users"`, []any{}, rows))
+	require.NoError(t, rows.Close())
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`DELETE FROM "users" /*application='api',route='%2Fusers'*/;`).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`SELECT 1 /* already commented */`).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectCommit()
+	tx, err := drv.Tx(ctx)
+	require.NoError(t, err)
+	require.NoError(t, tx.Exec(ctx, `DELETE FROM "users";`, []any{}, nil))
+	require.NoError(t, tx.Exec(ctx, `SELECT 1 /* already commented */`, []any{}, nil))
+	require.NoError(t, tx.Commit())
+	require.NoError(t, mock.ExpectationsWereMet())
+
+	// The database/sql methods comment the statements as well.
+	mock.ExpectQuery(`SELECT "id" FROM "users" /*application='api',route='%2Fusers'*/`).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	mock.ExpectExec(`DELETE FROM "users" /*application='api',route='%2Fusers'*/`).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	sr, err := drv.QueryContext(ctx, `SELECT "id" FROM "users"`)
+	require.NoError(t, err)
+	require.NoError(t, sr.Close())
+	_, err = drv.ExecContext(ctx, `DELETE FROM "users"`)
+	require.NoError(t, err)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT "id" FROM "users" /*application='api',route='%2Fusers'*/`).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	mock.ExpectExec(`DELETE FROM "users" /*application='api',route='%2Fusers'*/`).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	tx, err = drv.Tx(ctx)
+	require.NoError(t, err)
+	commentTx := tx.(*CommentTx)
+	sr, err = commentTx.QueryContext(ctx, `SELECT "id" FROM "users"`)
+	require.NoError(t, err)
+	require.NoError(t, sr.Close())
+	_, err = commentTx.ExecContext(ctx, `DELETE FROM "users"`)
+	require.NoError(t, err)
+	require.NoError(t, tx.Commit())
+	require.NoError(t, mock.ExpectationsWereMet())
+}
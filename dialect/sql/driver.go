@@ -122,6 +122,11 @@ type sessionVars struct {
 }
 
 // WithVar returns a new context that holds the session variable to be executed before every query.
+//
+// On PostgreSQL, the variable is set with set_config. In a transaction, it is
+// local to the transaction. Otherwise, the statement runs on a dedicated
+// connection of the pool, and the variable is reset after the statement,
+// which costs two extra round trips per statement.
 func WithVar(ctx context.Context, name, value string) context.Context {
 	sv, _ := ctx.Value(ctxVarsKey{}).(sessionVars)
 	sv.vars = append(sv.vars, struct {
@@ -149,6 +154,24 @@ func WithIntVar(ctx context.Context, name string, value int) context.Context {
 	return WithVar(ctx, name, strconv.Itoa(value))
 }
 
+// ctxTimeoutKey is the key used for attaching the statement timeout.
+type ctxTimeoutKey struct{}
+
+// WithTimeout returns a new context that limits the execution time of every
+// statement executed with it. On PostgreSQL, statement_timeout is set for the
+// statement with SET LOCAL. On MySQL, SELECT statements get the
+// MAX_EXECUTION_TIME optimizer hint. Other statements and dialects run with
+// a context deadline.
+func WithTimeout(ctx context.Context, d time.Duration) context.Context {
+	return context.WithValue(ctx, ctxTimeoutKey{}, d)
+}
+
+// TimeoutFromContext returns the statement timeout stored in the context, if any.
+func TimeoutFromContext(ctx context.Context) (time.Duration, bool) {
+	d, ok := ctx.Value(ctxTimeoutKey{}).(time.Duration)
+	return d, ok && d > 0
+}
+
 // ExecQuerier wraps the standard Exec and Query methods.
 type ExecQuerier interface {
 	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
@@ -174,6 +197,13 @@ func (c Conn) Exec(ctx context.Context, query string, args, v any) (rerr error)
 	if cf != nil {
 		defer func() { rerr = errors.Join(rerr, cf()) }()
 	}
+	ctx, ex, query, tf, err := c.mayTimeout(ctx, ex, query)
+	if err != nil {
+		return fmt.Errorf("dialect/sql: exec: set statement timeout: %w", err)
+	}
+	if tf != nil {
+		defer func() { rerr = errors.Join(rerr, tf(rerr)) }()
+	}
 	switch v := v.(type) {
 	case nil:
 		if _, err := ex.ExecContext(ctx, query, argv...); err != nil {
@@ -205,15 +235,36 @@ func (c Conn) Query(ctx context.Context, query string, args, v any) error {
 	if err != nil {
 		return fmt.Errorf("dialect/sql: query: set session vars: %w", err)
 	}
+	ctx, ex, query, tf, err := c.mayTimeout(ctx, ex, query)
+	if err != nil {
+		if cf != nil {
+			err = errors.Join(err, cf())
+		}
+		return fmt.Errorf("dialect/sql: query: set statement timeout: %w", err)
+	}
 	rows, err := ex.QueryContext(ctx, query, argv...)
 	if err != nil {
+		if tf != nil {
+			err = errors.Join(err, tf(err))
+		}
 		if cf != nil {
 			err = errors.Join(err, cf())
 		}
 		return fmt.Errorf("dialect/sql: query: %w", err)
 	}
 	*vr = Rows{rows}
-	if cf != nil {
+	switch {
+	case tf != nil:
+		// The statement timeout is released before the
+		// connection of the session variables, if any.
+		vr.ColumnScanner = rowsWithCloser{rows, func() error {
+			err := tf(rows.Err())
+			if cf != nil {
+				err = errors.Join(err, cf())
+			}
+			return err
+		}}
+	case cf != nil:
 		vr.ColumnScanner = rowsWithCloser{rows, cf}
 	}
 	return nil
@@ -229,11 +280,15 @@ func (c Conn) maySetVars(ctx context.Context) (ExecQuerier, func() error, error)
 		ex    ExecQuerier  // Underlying ExecQuerier.
 		cf    func() error // Close function.
 		reset []string     // Reset variables.
+		set   = "SELECT set_config($1, $2, false)"
 		seen  = make(map[string]struct{}, len(sv.vars))
 	)
 	switch e := c.ExecQuerier.(type) {
 	case *sql.Tx:
-		ex = e
+		// PostgreSQL variables of transactions are local to the transaction,
+		// such that they are reset on commit or rollback, and do not leak to
+		// the next transactions of the pooled connection.
+		ex, set = e, "SELECT set_config($1, $2, true)"
 	case *sql.DB:
 		conn, err := e.Conn(ctx)
 		if err != nil {
@@ -287,8 +342,8 @@ func (c Conn) maySetVars(ctx context.Context) (ExecQuerier, func() error, error)
 		// The identifier (s.k) is validated by isValidIdentifier() above.
 		switch c.dialect {
 		case dialect.Postgres:
-			// PostgreSQL: SET <ident> TO $1
-			if _, err := ex.ExecContext(ctx, fmt.Sprintf("SET %s TO $1", s.k), s.v); err != nil {
+			// PostgreSQL: SET does not accept bind parameters, unlike set_config.
+			if _, err := ex.ExecContext(ctx, set, s.k, s.v); err != nil {
 				if cf != nil {
 					err = errors.Join(err, resetAndClose(reset, cf))
 				}
@@ -323,6 +378,87 @@ func (c Conn) maySetVars(ctx context.Context) (ExecQuerier, func() error, error)
 	return ex, cf, nil
 }
 
+// mayTimeout applies the statement timeout of the context, if any, to the
+// statement. The returned function releases the timeout, given the error of
+// the statement, and must be called after the statement (and its rows) are done.
+func (c Conn) mayTimeout(ctx context.Context, ex ExecQuerier, query string) (context.Context, ExecQuerier, string, func(error) error, error) {
+	d, ok := TimeoutFromContext(ctx)
+	if !ok {
+		return ctx, ex, query, nil, nil
+	}
+	// Milliseconds is the unit of both statement_timeout and MAX_EXECUTION_TIME.
+	// Round up, as zero disables the timeout.
+	ms := max((d+time.Millisecond-1)/time.Millisecond, 1)
+	switch {
+	case c.dialect == dialect.Postgres:
+		return c.statementTimeout(ctx, ex, query, int64(ms))
+	case c.dialect == dialect.MySQL && isSelect(query):
+		i := len(query) - len(strings.TrimLeft(query, " \t\n")) + len("SELECT")
+		return ctx, ex, fmt.Sprintf("%s /*+ MAX_EXECUTION_TIME(%d) */%s", query[:i], ms, query[i:]), nil, nil
+	default:
+		ctx, cancel := context.WithTimeout(ctx, d)
+		return ctx, ex, query, func(error) error {
+			cancel()
+			return nil
+		}, nil
+	}
+}
+
+// statementTimeout sets the PostgreSQL statement_timeout of the statement with
+// SET LOCAL. Outside a transaction, the statement runs in its own transaction,
+// that resets the timeout on commit. In a transaction, the previous timeout
+// is restored after the statement.
+func (c Conn) statementTimeout(ctx context.Context, ex ExecQuerier, query string, ms int64) (context.Context, ExecQuerier, string, func(error) error, error) {
+	if conn, ok := ex.(Conn); ok {
+		ex = conn.ExecQuerier
+	}
+	set := fmt.Sprintf("SET LOCAL statement_timeout = %d", ms)
+	switch e := ex.(type) {
+	case *sql.Tx:
+		var prev string
+		if err := e.QueryRowContext(ctx, "SHOW statement_timeout").Scan(&prev); err != nil {
+			return nil, nil, "", nil, err
+		}
+		if _, err := e.ExecContext(ctx, set); err != nil {
+			return nil, nil, "", nil, err
+		}
+		return ctx, e, query, func(serr error) error {
+			// A failed statement aborts the transaction,
+			// and the timeout is reset on rollback.
+			if serr != nil {
+				return nil
+			}
+			_, err := e.ExecContext(context.WithoutCancel(ctx), fmt.Sprintf("SET LOCAL statement_timeout = '%s'", escapeStringValue(prev, c.dialect)))
+			return err
+		}, nil
+	case interface {
+		BeginTx(context.Context, *sql.TxOptions) (*sql.Tx, error)
+	}:
+		tx, err := e.BeginTx(ctx, nil)
+		if err != nil {
+			return nil, nil, "", nil, err
+		}
+		if _, err := tx.ExecContext(ctx, set); err != nil {
+			return nil, nil, "", nil, errors.Join(err, tx.Rollback())
+		}
+		return ctx, tx, query, func(serr error) error {
+			if serr != nil {
+				return tx.Rollback()
+			}
+			return tx.Commit()
+		}, nil
+	default:
+		return nil, nil, "", nil, fmt.Errorf("unsupported ExecQuerier type: %T", ex)
+	}
+}
+
+// isSelect reports if the query is a SELECT statement.
+func isSelect(query string) bool {
+	query = strings.TrimLeft(query, " \t\n")
+	return len(query) > len("SELECT") && strings.EqualFold(query[:len("SELECT")], "SELECT") &&
+		strings.ContainsRune(" \t\n", rune(query[len("SELECT")]))
+}
+
 var _ dialect.Driver = (*Driver)(nil)
 
 type (
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/syssam/velox/dialect"
 
@@ -18,7 +19,7 @@ func TestWithVars(t *testing.T) {
 	require.NoError(t, err)
 	db.SetMaxOpenConns(1)
 	drv := OpenDB(dialect.Postgres, db)
-	mock.ExpectExec(`SET foo TO \$1`).WithArgs("bar").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(`SELECT set_config\(\$1, \$2, false\)`).WithArgs("foo", "bar").WillReturnResult(sqlmock.NewResult(0, 0))
 	mock.ExpectQuery("SELECT 1").WillReturnRows(sqlmock.NewRows([]string{"1"}).AddRow(1))
 	mock.ExpectExec("RESET foo").WillReturnResult(sqlmock.NewResult(0, 0))
 	rows := &Rows{}
@@ -32,8 +33,8 @@ func TestWithVars(t *testing.T) {
 	require.NoError(t, rows.Close(), "rows should be closed to release the connection")
 	require.NoError(t, mock.ExpectationsWereMet())
 
-	mock.ExpectExec(`SET foo TO \$1`).WithArgs("bar").WillReturnResult(sqlmock.NewResult(0, 0))
-	mock.ExpectExec(`SET foo TO \$1`).WithArgs("baz").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(`SELECT set_config\(\$1, \$2, false\)`).WithArgs("foo", "bar").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(`SELECT set_config\(\$1, \$2, false\)`).WithArgs("foo", "baz").WillReturnResult(sqlmock.NewResult(0, 0))
 	mock.ExpectQuery("SELECT 1").WillReturnRows(sqlmock.NewRows([]string{"1"}).AddRow(1))
 	mock.ExpectExec("RESET foo").WillReturnResult(sqlmock.NewResult(0, 0))
 	err = drv.Query(
@@ -47,7 +48,7 @@ func TestWithVars(t *testing.T) {
 	require.NoError(t, mock.ExpectationsWereMet())
 
 	mock.ExpectBegin()
-	mock.ExpectExec(`SET foo TO \$1`).WithArgs("bar").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(`SELECT set_config\(\$1, \$2, true\)`).WithArgs("foo", "bar").WillReturnResult(sqlmock.NewResult(0, 0))
 	mock.ExpectQuery("SELECT 1").WillReturnRows(sqlmock.NewRows([]string{"1"}).AddRow(1))
 	mock.ExpectCommit()
 	tx, err := drv.Tx(context.Background())
@@ -64,7 +65,7 @@ func TestWithVars(t *testing.T) {
 	// Rows should not be closed to release the session,
 	// as a transaction is always scoped to a single connection.
 
-	mock.ExpectExec(`SET foo TO \$1`).WithArgs("qux").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(`SELECT set_config\(\$1, \$2, false\)`).WithArgs("foo", "qux").WillReturnResult(sqlmock.NewResult(0, 0))
 	mock.ExpectExec("INSERT INTO users DEFAULT VALUES").WillReturnResult(sqlmock.NewResult(0, 0))
 	mock.ExpectExec("RESET foo").WillReturnResult(sqlmock.NewResult(0, 0))
 	err = drv.Exec(
@@ -77,7 +78,7 @@ func TestWithVars(t *testing.T) {
 	require.NoError(t, mock.ExpectationsWereMet())
 	// No rows are returned, so no need to close them.
 
-	mock.ExpectExec(`SET foo TO \$1`).WithArgs("foo").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(`SELECT set_config\(\$1, \$2, false\)`).WithArgs("foo", "foo").WillReturnResult(sqlmock.NewResult(0, 0))
 	mock.ExpectExec("INSERT INTO users DEFAULT VALUES").WillReturnResult(sqlmock.NewResult(0, 0))
 	mock.ExpectExec("RESET foo").WillReturnResult(sqlmock.NewResult(0, 0))
 	err = drv.Exec(
@@ -563,7 +564,7 @@ func TestWithVarsEscapedValue(t *testing.T) {
 	drv := OpenDB(dialect.Postgres, db)
 
 	// Parameterized queries handle special characters safely
-	mock.ExpectExec(`SET foo TO \$1`).WithArgs("it's escaped").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(`SELECT set_config\(\$1, \$2, false\)`).WithArgs("foo", "it's escaped").WillReturnResult(sqlmock.NewResult(0, 0))
 	mock.ExpectQuery("SELECT 1").WillReturnRows(sqlmock.NewRows([]string{"1"}).AddRow(1))
 	mock.ExpectExec("RESET foo").WillReturnResult(sqlmock.NewResult(0, 0))
 
@@ -610,8 +611,8 @@ func TestWithVars_SpecialCharacters(t *testing.T) {
 	drv := OpenDB(dialect.Postgres, db)
 
 	// SQL injection attempt in value should be safe via parameterization
-	mock.ExpectExec(`SET app\.data TO \$1`).
-		WithArgs("O'Brien; DROP TABLE users--").
+	mock.ExpectExec(`SELECT set_config\(\$1, \$2, false\)`).
+		WithArgs("app.data", "O'Brien; DROP TABLE users--").
 		WillReturnResult(sqlmock.NewResult(0, 0))
 	mock.ExpectQuery("SELECT 1").
 		WillReturnRows(sqlmock.NewRows([]string{"1"}).AddRow(1))
@@ -627,3 +628,73 @@ func TestWithVars_SpecialCharacters(t *testing.T) {
 	require.NoError(t, rows.Close())
 	require.NoError(t, mock.ExpectationsWereMet())
 }
+
+func TestWithTimeout_Postgres(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	drv := OpenDB(dialect.Postgres, db)
+	ctx := WithTimeout(context.Background(), 1500*time.Microsecond)
+
+	// Outside a transaction, the statement runs in its own transaction.
+	mock.ExpectBegin()
+	mock.ExpectExec("SET LOCAL statement_timeout = 2").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery("SELECT 1").WillReturnRows(sqlmock.NewRows([]string{"1"}).AddRow(1))
+	mock.ExpectCommit()
+	rows := &Rows{}
+	require.NoError(t, drv.Query(ctx, "SELECT 1", []any{}, rows))
+	require.NoError(t, rows.Close())
+	require.NoError(t, mock.ExpectationsWereMet())
+
+	mock.ExpectBegin()
+	mock.ExpectExec("SET LOCAL statement_timeout = 2").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("DELETE FROM users").WillReturnError(errors.New("canceling statement due to statement timeout"))
+	mock.ExpectRollback()
+	require.Error(t, drv.Exec(ctx, "DELETE FROM users", []any{}, nil))
+	require.NoError(t, mock.ExpectationsWereMet())
+
+	// In a transaction, the previous timeout is restored after the statement.
+	mock.ExpectBegin()
+	mock.ExpectQuery("SHOW statement_timeout").WillReturnRows(sqlmock.NewRows([]string{"statement_timeout"}).AddRow("30s"))
+	mock.ExpectExec("SET LOCAL statement_timeout = 2").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("DELETE FROM users").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("SET LOCAL statement_timeout = '30s'").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectCommit()
+	tx, err := drv.Tx(context.Background())
+	require.NoError(t, err)
+	require.NoError(t, tx.Exec(ctx, "DELETE FROM users", []any{}, nil))
+	require.NoError(t, tx.Commit())
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestWithTimeout_MySQL(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	require.NoError(t, err)
+	drv := OpenDB(dialect.MySQL, db)
+	ctx := WithTimeout(context.Background(), time.Second)
+
+	mock.ExpectQuery("SELECT /*+ MAX_EXECUTION_TIME(1000) */ `id` FROM `users`").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	rows := &Rows{}
+	require.NoError(t, drv.Query(ctx, "SELECT `id` FROM `users`", []any{}, rows))
+	require.NoError(t, rows.Close())
+
+	// Other statements run with a context deadline.
+	mock.ExpectExec("DELETE FROM `users`").WillReturnResult(sqlmock.NewResult(0, 1))
+	require.NoError(t, drv.Exec(ctx, "DELETE FROM `users`", []any{}, nil))
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestWithTimeout_SQLite(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	drv := OpenDB(dialect.SQLite, db)
+
+	mock.ExpectQuery("SELECT 1").
+		WillDelayFor(time.Second).
+		WillReturnRows(sqlmock.NewRows([]string{"1"}).AddRow(1))
+	err = drv.Query(WithTimeout(context.Background(), 10*time.Millisecond), "SELECT 1", []any{}, &Rows{})
+	require.ErrorContains(t, err, "canceling query")
+
+	_, ok := TimeoutFromContext(WithTimeout(context.Background(), 0))
+	require.False(t, ok, "zero timeout should be ignored")
+}
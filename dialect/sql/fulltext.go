@@ -0,0 +1,165 @@
+package sql
+
+import (
+	"strings"
+
+	"github.com/syssam/velox/dialect"
+)
+
+// DefaultFullTextConfig is the text search configuration of the
+// PostgreSQL full-text indexes that do not set one.
+const DefaultFullTextConfig = "english"
+
+// FullText is the full-text index of a table. It builds the search predicates
+// and the relevance orders of the generated entities:
+//
+//	var SearchIndex = sql.FullText[predicate.Post]{Index: "post_title_body", Columns: []string{"title", "body"}}
+//	query.Where(post.Search("velox orm")).Order(post.ByRelevance("velox orm"))
+//
+// The statements depend on the dialect of the query:
+//
+//   - PostgreSQL matches the tsvector of the columns with websearch_to_tsquery,
+//     and orders by ts_rank.
+//   - MySQL uses MATCH ... AGAINST in natural language mode.
+//   - SQLite matches the FTS5 table named after the index, and orders by bm25.
+type FullText[P PredicateFunc] struct {
+	// Index is the name of the index. In SQLite, it is also
+	// the name of the FTS5 table that holds the index.
+	Index string
+	// Columns are the indexed columns.
+	Columns []string
+	// Config is the text search configuration (PostgreSQL).
+	// Defaults to DefaultFullTextConfig.
+	Config string
+}
+
+// Search returns a predicate that matches the rows of the index with the
+// given query. An empty query matches no rows.
+func (f FullText[P]) Search(query string) P {
+	return P(fullTextIndex(f).search(query))
+}
+
+// OrderByRelevance returns an order option that orders the rows by their
+// relevance to the given query, the most relevant first.
+func (f FullText[P]) OrderByRelevance(query string) func(*Selector) {
+	return fullTextIndex(f).orderByRelevance(query)
+}
+
+// fullTextIndex builds the statements of a FullText index.
+type fullTextIndex struct {
+	Index   string
+	Columns []string
+	Config  string
+}
+
+func (f fullTextIndex) search(query string) func(*Selector) {
+	return func(s *Selector) {
+		if strings.TrimSpace(query) == "" {
+			s.Where(False())
+			return
+		}
+		s.Where(P().Append(func(b *Builder) {
+			switch s.Dialect() {
+			case dialect.Postgres:
+				b.WriteString(TSVector(f.Config, f.columns(s)...))
+				b.WriteString(" @@ ")
+				f.tsquery(b, query)
+			case dialect.MySQL:
+				f.match(b, s, query)
+			default:
+				b.Ident(s.C("rowid")).WriteString(" IN ")
+				b.Wrap(func(b *Builder) {
+					b.WriteString("SELECT ").Ident("rowid").WriteString(" FROM ").Ident(f.Index).
+						WriteString(" WHERE ").Ident(f.Index).WriteString(" MATCH ").Arg(fts5Query(query))
+				})
+			}
+		}))
+	}
+}
+
+func (f fullTextIndex) orderByRelevance(query string) func(*Selector) {
+	return func(s *Selector) {
+		if strings.TrimSpace(query) == "" {
+			return
+		}
+		s.OrderExpr(ExprFunc(func(b *Builder) {
+			switch s.Dialect() {
+			case dialect.Postgres:
+				b.WriteString("ts_rank(").WriteString(TSVector(f.Config, f.columns(s)...)).Comma()
+				f.tsquery(b, query)
+				b.WriteString(") DESC")
+			case dialect.MySQL:
+				f.match(b, s, query)
+				b.WriteString(" DESC")
+			default:
+				// bm25 scores are negative, the better matches are lower.
+				b.WriteString("COALESCE(")
+				b.Wrap(func(b *Builder) {
+					b.WriteString("SELECT bm25(").Ident(f.Index).WriteString(") FROM ").Ident(f.Index).
+						WriteString(" WHERE ").Ident(f.Index).WriteString(" MATCH ").Arg(fts5Query(query)).
+						WriteString(" AND ").Ident(f.Index).Byte('.').Ident("rowid").WriteString(" = ").Ident(s.C("rowid"))
+				})
+				b.WriteString(", 0)")
+			}
+		}))
+	}
+}
+
+// columns returns the qualified columns of the index.
+func (f fullTextIndex) columns(s *Selector) []string {
+	columns := make([]string, len(f.Columns))
+	for i, c := range f.Columns {
+		columns[i] = s.C(c)
+	}
+	return columns
+}
+
+// tsquery writes the PostgreSQL tsquery of the given query.
+func (f fullTextIndex) tsquery(b *Builder, query string) {
+	b.WriteString("websearch_to_tsquery(").WriteString(fullTextConfig(f.Config)).Comma().Arg(query).Byte(')')
+}
+
+// match writes the MySQL MATCH expression of the given query.
+func (f fullTextIndex) match(b *Builder, s *Selector, query string) {
+	b.WriteString("MATCH ")
+	b.Wrap(func(b *Builder) {
+		b.IdentComma(f.columns(s)...)
+	})
+	b.WriteString(" AGAINST ")
+	b.Wrap(func(b *Builder) {
+		b.Arg(query).WriteString(" IN NATURAL LANGUAGE MODE")
+	})
+}
+
+// TSVector returns the PostgreSQL tsvector expression of the given (quoted)
+// columns, as it is indexed by full-text indexes. NULL values are indexed as
+// empty strings.
+//
+//	TSVector("english", `"title"`, `"body"`)
+//	// to_tsvector('english', coalesce("title", '') || ' ' || coalesce("body", ''))
+func TSVector(config string, columns ...string) string {
+	parts := make([]string, len(columns))
+	for i, c := range columns {
+		parts[i] = "coalesce(" + c + ", '')"
+	}
+	return "to_tsvector(" + fullTextConfig(config) + ", " + strings.Join(parts, " || ' ' || ") + ")"
+}
+
+// fullTextConfig returns the quoted text search configuration.
+func fullTextConfig(config string) string {
+	if config == "" {
+		config = DefaultFullTextConfig
+	}
+	return "'" + strings.ReplaceAll(config, "'", "''") + "'"
+}
+
+// fts5Query returns an SQLite FTS5 query that matches the rows containing
+// all the words of the given query. The words are quoted, such that the
+// query syntax of FTS5 (e.g. AND, NOT, *) is matched literally.
+func fts5Query(query string) string {
+	words := strings.Fields(query)
+	for i, w := range words {
+		words[i] = `"` + strings.ReplaceAll(w, `"`, `""`) + `"`
+	}
+	return strings.Join(words, " ")
+}
@@ -0,0 +1,64 @@
+package sql
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/syssam/velox/dialect"
+)
+
+func TestFullText(t *testing.T) {
+	idx := FullText[func(*Selector)]{Index: "post_title_body", Columns: []string{"title", "body"}}
+	tests := []struct {
+		dialect   string
+		wantQuery string
+		wantArgs  []any
+	}{
+		{
+			dialect:   dialect.Postgres,
+			wantQuery: `SELECT * FROM "posts" WHERE to_tsvector('english', coalesce("posts"."title", '') || ' ' || coalesce("posts"."body", '')) @@ websearch_to_tsquery('english', $1) ORDER BY ts_rank(to_tsvector('english', coalesce("posts"."title", '') || ' ' || coalesce("posts"."body", '')), websearch_to_tsquery('english', $2)) DESC`,
+			wantArgs:  []any{"velox orm", "velox orm"},
+		},
+		{
+			dialect:   dialect.MySQL,
+			wantQuery: "SELECT * FROM `posts` WHERE MATCH (`posts`.`title`, `posts`.`body`) AGAINST (? IN NATURAL LANGUAGE MODE) ORDER BY MATCH (`posts`.`title`, `posts`.`body`) AGAINST (? IN NATURAL LANGUAGE MODE) DESC",
+			wantArgs:  []any{"velox orm", "velox orm"},
+		},
+		{
+			dialect:   dialect.SQLite,
+			wantQuery: "SELECT * FROM `posts` WHERE `posts`.`rowid` IN (SELECT `rowid` FROM `post_title_body` WHERE `post_title_body` MATCH ?) ORDER BY COALESCE((SELECT bm25(`post_title_body`) FROM `post_title_body` WHERE `post_title_body` MATCH ? AND `post_title_body`.`rowid` = `posts`.`rowid`), 0)",
+			wantArgs:  []any{`"velox" "orm"`, `"velox" "orm"`},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.dialect, func(t *testing.T) {
+			s := Dialect(tt.dialect).Select("*").From(Table("posts"))
+			idx.Search("velox orm")(s)
+			idx.OrderByRelevance("velox orm")(s)
+			query, args := s.Query()
+			require.Equal(t, tt.wantQuery, query)
+			require.Equal(t, tt.wantArgs, args)
+		})
+	}
+}
+
+func TestFullText_EmptyQuery(t *testing.T) {
+	idx := FullText[func(*Selector)]{Index: "post_title", Columns: []string{"title"}, Config: "simple"}
+	s := Dialect(dialect.Postgres).Select("*").From(Table("posts"))
+	idx.Search(" ")(s)
+	idx.OrderByRelevance("")(s)
+	query, args := s.Query()
+	require.Equal(t, `SELECT * FROM "posts" WHERE FALSE`, query)
+	require.Empty(t, args)
+}
+
+func TestTSVector(t *testing.T) {
+	require.Equal(t, `to_tsvector('simple', coalesce("title", ''))`, TSVector("simple", `"title"`))
+	require.Equal(t, `to_tsvector('english', coalesce(a, '') || ' ' || coalesce(b, ''))`, TSVector("", "a", "b"))
+}
+
+func TestFTS5Query(t *testing.T) {
+	require.Equal(t, `"velox" "NOT" "a""b" "c*"`, fts5Query(`velox NOT a"b  c*`))
+	require.Equal(t, "", fts5Query(" "))
+}
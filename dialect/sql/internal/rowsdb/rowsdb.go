@@ -0,0 +1,157 @@
+// Package rowsdb serves rows that are not read from a database connection,
+// such as buffered or merged rows, as *sql.Rows of database/sql. Scan then
+// applies the standard conversions of database/sql to their values.
+package rowsdb
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+)
+
+// Scanner is implemented by the rows of database/sql and dialect/sql.
+type Scanner interface {
+	Columns() ([]string, error)
+	Next() bool
+	Scan(dest ...any) error
+	Err() error
+	Close() error
+}
+
+// Result holds buffered rows.
+type Result struct {
+	Columns []string
+	Rows    [][]driver.Value
+}
+
+// Collect buffers and closes the rows.
+func Collect(rows Scanner) (_ *Result, rerr error) {
+	defer func() { rerr = errors.Join(rerr, rows.Close()) }()
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+	r := &Result{Columns: columns}
+	for rows.Next() {
+		row, err := scanRow(rows, len(columns))
+		if err != nil {
+			return nil, err
+		}
+		r.Rows = append(r.Rows, row)
+	}
+	return r, rows.Err()
+}
+
+// Open returns the buffered rows as *sql.Rows.
+func (r *Result) Open(ctx context.Context) (*sql.Rows, error) {
+	return Open(ctx, &memRows{r: r})
+}
+
+// Stream returns rows that are read from the given rows as they are
+// iterated, and closes them when closed.
+func Stream(rows Scanner) (driver.Rows, error) {
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, errors.Join(err, rows.Close())
+	}
+	return &streamRows{rows: rows, columns: columns}, nil
+}
+
+// Open serves the given driver rows as *sql.Rows. Closing the returned rows
+// closes the driver rows.
+func Open(ctx context.Context, rows driver.Rows) (*sql.Rows, error) {
+	db := sql.OpenDB(connector{rows})
+	sr, err := db.QueryContext(ctx, "")
+	// The rows hold the only connection of the database, which is
+	// closed once the rows are closed and release it.
+	if cerr := db.Close(); err == nil && cerr != nil {
+		err = errors.Join(cerr, sr.Close())
+	}
+	if err != nil {
+		return nil, errors.Join(err, rows.Close())
+	}
+	return sr, nil
+}
+
+// scanRow scans the current row into driver values.
+func scanRow(rows Scanner, n int) ([]driver.Value, error) {
+	values := make([]any, n)
+	ptrs := make([]any, n)
+	for i := range values {
+		ptrs[i] = &values[i]
+	}
+	if err := rows.Scan(ptrs...); err != nil {
+		return nil, err
+	}
+	row := make([]driver.Value, n)
+	for i, v := range values {
+		row[i] = v
+	}
+	return row, nil
+}
+
+// connector and conn serve one query with the given rows.
+type (
+	connector struct{ rows driver.Rows }
+	conn      struct{ rows driver.Rows }
+)
+
+func (c connector) Connect(context.Context) (driver.Conn, error) { return conn(c), nil }
+func (c connector) Driver() driver.Driver                        { return c }
+func (c connector) Open(string) (driver.Conn, error)             { return conn(c), nil }
+
+func (conn) Prepare(string) (driver.Stmt, error) {
+	return nil, errors.New("rowsdb: prepare is not supported")
+}
+func (conn) Close() error { return nil }
+func (conn) Begin() (driver.Tx, error) {
+	return nil, errors.New("rowsdb: transactions are not supported")
+}
+
+func (c conn) QueryContext(context.Context, string, []driver.NamedValue) (driver.Rows, error) {
+	return c.rows, nil
+}
+
+// memRows iterates over buffered rows.
+type memRows struct {
+	r *Result
+	i int
+}
+
+func (m *memRows) Columns() []string { return m.r.Columns }
+func (m *memRows) Close() error      { return nil }
+
+func (m *memRows) Next(dest []driver.Value) error {
+	if m.i >= len(m.r.Rows) {
+		return io.EOF
+	}
+	copy(dest, m.r.Rows[m.i])
+	m.i++
+	return nil
+}
+
+// streamRows iterates over the rows of a Scanner.
+type streamRows struct {
+	rows    Scanner
+	columns []string
+}
+
+func (s *streamRows) Columns() []string { return s.columns }
+func (s *streamRows) Close() error      { return s.rows.Close() }
+
+func (s *streamRows) Next(dest []driver.Value) error {
+	if !s.rows.Next() {
+		if err := s.rows.Err(); err != nil {
+			return err
+		}
+		return io.EOF
+	}
+	row, err := scanRow(s.rows, len(s.columns))
+	if err != nil {
+		return err
+	}
+	copy(dest, row)
+	return nil
+}
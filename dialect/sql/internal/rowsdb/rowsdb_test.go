@@ -0,0 +1,103 @@
+package rowsdb
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// sliceRows is a Scanner over in-memory rows.
+type sliceRows struct {
+	columns []string
+	rows    [][]any
+	i       int
+	err     error
+	closed  bool
+}
+
+func (s *sliceRows) Columns() ([]string, error) { return s.columns, nil }
+func (s *sliceRows) Next() bool {
+	if s.i >= len(s.rows) || s.err != nil && s.i > 0 {
+		return false
+	}
+	s.i++
+	return true
+}
+func (s *sliceRows) Err() error   { return s.err }
+func (s *sliceRows) Close() error { s.closed = true; return nil }
+func (s *sliceRows) Scan(dest ...any) error {
+	for i, v := range s.rows[s.i-1] {
+		*dest[i].(*any) = v
+	}
+	return nil
+}
+
+func TestCollect(t *testing.T) {
+	src := &sliceRows{columns: []string{"id", "name"}, rows: [][]any{{int64(1), "a"}, {int64(2), []byte("b")}}}
+	r, err := Collect(src)
+	require.NoError(t, err)
+	require.True(t, src.closed)
+	require.Equal(t, &Result{
+		Columns: []string{"id", "name"},
+		Rows:    [][]driver.Value{{int64(1), "a"}, {int64(2), []byte("b")}},
+	}, r)
+
+	rows, err := r.Open(context.Background())
+	require.NoError(t, err)
+	var (
+		ids   []int
+		names []string
+	)
+	for rows.Next() {
+		var (
+			id   int
+			name string
+		)
+		require.NoError(t, rows.Scan(&id, &name))
+		ids, names = append(ids, id), append(names, name)
+	}
+	require.NoError(t, rows.Err())
+	require.NoError(t, rows.Close())
+	require.Equal(t, []int{1, 2}, ids)
+	require.Equal(t, []string{"a", "b"}, names)
+}
+
+func TestStream(t *testing.T) {
+	src := &sliceRows{columns: []string{"n"}, rows: [][]any{{int64(1)}, {int64(2)}}}
+	dr, err := Stream(src)
+	require.NoError(t, err)
+	rows, err := Open(context.Background(), dr)
+	require.NoError(t, err)
+	var sum int
+	for rows.Next() {
+		var n int
+		require.NoError(t, rows.Scan(&n))
+		sum += n
+	}
+	require.NoError(t, rows.Err())
+	require.Equal(t, 3, sum)
+	require.True(t, src.closed, "the source is closed with the rows")
+
+	src = &sliceRows{columns: []string{"n"}, rows: [][]any{{int64(1)}, {int64(2)}}}
+	dr, err = Stream(src)
+	require.NoError(t, err)
+	rows, err = Open(context.Background(), dr)
+	require.NoError(t, err)
+	require.True(t, rows.Next())
+	require.False(t, src.closed)
+	require.NoError(t, rows.Close())
+	require.True(t, src.closed, "closing the rows early closes the source")
+
+	src = &sliceRows{columns: []string{"n"}, rows: [][]any{{int64(1)}, {int64(2)}}, err: errors.New("broken pipe")}
+	dr, err = Stream(src)
+	require.NoError(t, err)
+	rows, err = Open(context.Background(), dr)
+	require.NoError(t, err)
+	for rows.Next() {
+	}
+	require.EqualError(t, rows.Err(), "broken pipe")
+	require.True(t, src.closed)
+}
@@ -0,0 +1,54 @@
+// Package scatter holds the context of the queries that run on all shards,
+// and how their rows are merged. It is shared by the shard driver and by
+// sqlgraph, that describes the merge of the node queries.
+package scatter
+
+import "context"
+
+type (
+	ctxKey      struct{}
+	mergeCtxKey struct{}
+)
+
+// With returns a new context that runs the statements on all shards
+// when the context has no shard key.
+func With(ctx context.Context) context.Context {
+	return context.WithValue(ctx, ctxKey{}, true)
+}
+
+// Is reports if the context is marked for scatter-gather.
+func Is(ctx context.Context) bool {
+	v, _ := ctx.Value(ctxKey{}).(bool)
+	return v
+}
+
+// Merge describes how the rows of a query that runs on all shards are
+// merged. It is attached to the context by sqlgraph for node queries
+// and counts.
+type Merge struct {
+	// Count reports if the query returns one count per shard,
+	// that are summed.
+	Count bool
+	// Order holds the ORDER BY terms of the query, as rendered by
+	// sql.Selector.OrderTerms.
+	Order []string
+	// Limit and Offset are applied to the merged rows.
+	Limit, Offset int
+	// Query and Args, if set, replace the query on the shards.
+	// For example, a query with OFFSET n LIMIT m runs with LIMIT n+m
+	// and no offset on every shard.
+	Query string
+	Args  []any
+}
+
+// WithMerge returns a new context that holds the merge of the rows of
+// a scatter-gather query.
+func WithMerge(ctx context.Context, m *Merge) context.Context {
+	return context.WithValue(ctx, mergeCtxKey{}, m)
+}
+
+// MergeFrom returns the Merge stored in the context, if any.
+func MergeFrom(ctx context.Context) *Merge {
+	m, _ := ctx.Value(mergeCtxKey{}).(*Merge)
+	return m
+}
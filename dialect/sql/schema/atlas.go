@@ -136,43 +136,18 @@ func (a *Atlas) NamedDiff(ctx context.Context, name string, tables ...*Table) er
 		return fmt.Errorf("validating migration directory: %w", err)
 	}
 	a.setupTables(tables)
-	// Set up connections.
-	if a.driver != nil {
-		var err error
-		a.sqlDialect, err = a.entDialect(ctx, a.driver)
-		if err != nil {
-			return err
-		}
-		a.atDriver, err = a.sqlDialect.atOpen(a.sqlDialect)
-		if err != nil {
-			return err
-		}
-	} else {
-		c, err := sqlclient.OpenURL(ctx, a.url)
-		if err != nil {
-			return err
-		}
-		defer c.Close()
-		a.sqlDialect, err = a.entDialect(ctx, entsql.OpenDB(a.dialect, c.DB))
-		if err != nil {
-			return err
-		}
-		a.atDriver = c.Driver
+	closeFn, err := a.connect(ctx)
+	if err != nil {
+		return err
 	}
-	defer func() {
-		a.sqlDialect = nil
-		a.atDriver = nil
-	}()
+	defer closeFn()
 	if err := a.sqlDialect.init(ctx); err != nil {
 		return err
 	}
 	if a.universalID {
 		tables = append(tables, newTypesTable(a.typeTable))
 	}
-	var (
-		err  error
-		plan *migrate.Plan
-	)
+	var plan *migrate.Plan
 	switch a.mode {
 	case ModeInspect:
 		plan, err = a.planInspect(ctx, a.sqlDialect, name, tables)
@@ -194,6 +169,58 @@ func (a *Atlas) NamedDiff(ctx context.Context, name string, tables ...*Table) er
 	}
 }
 
+// InspectSchema inspects the connected database with the Atlas inspector of
+// its dialect and returns the given tables, or all tables if none is given.
+func (a *Atlas) InspectSchema(ctx context.Context, tables ...string) (*schema.Schema, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	closeFn, err := a.connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer closeFn()
+	return a.atDriver.InspectSchema(ctx, a.schema, &schema.InspectOptions{
+		Tables: tables,
+		Mode:   schema.InspectSchemas | schema.InspectTables,
+	})
+}
+
+// connect sets up the Ent and Atlas drivers used by a single operation.
+// The returned function releases the connection and clears both drivers.
+func (a *Atlas) connect(ctx context.Context) (func(), error) {
+	release := func() {
+		a.sqlDialect = nil
+		a.atDriver = nil
+	}
+	if a.driver != nil {
+		var err error
+		a.sqlDialect, err = a.entDialect(ctx, a.driver)
+		if err != nil {
+			return nil, err
+		}
+		a.atDriver, err = a.sqlDialect.atOpen(a.sqlDialect)
+		if err != nil {
+			release()
+			return nil, err
+		}
+		return release, nil
+	}
+	c, err := sqlclient.OpenURL(ctx, a.url)
+	if err != nil {
+		return nil, err
+	}
+	a.sqlDialect, err = a.entDialect(ctx, entsql.OpenDB(a.dialect, c.DB))
+	if err != nil {
+		c.Close()
+		return nil, err
+	}
+	a.atDriver = c.Driver
+	return func() {
+		release()
+		c.Close()
+	}, nil
+}
+
 func (a *Atlas) cleanSchema(ctx context.Context, name string, err0 error) (err error) {
 	defer func() {
 		if err0 != nil {
@@ -681,12 +708,7 @@ var noQualifierOpt = func(opts *migrate.PlanOptions) {
 // and proceeds to diff the changes to create a migration plan.
 func (a *Atlas) planInspect(ctx context.Context, conn dialect.ExecQuerier, name string, tables []*Table) (*migrate.Plan, error) {
 	current, err := a.atDriver.InspectSchema(ctx, a.schema, &schema.InspectOptions{
-		Tables: func() (t []string) {
-			for i := range tables {
-				t = append(t, tables[i].Name)
-			}
-			return t
-		}(),
+		Tables: a.inspectTables(tables),
 		// Ent supports table-level inspection only.
 		Mode: schema.InspectSchemas | schema.InspectTables,
 	})
@@ -713,7 +735,15 @@ func (a *Atlas) planInspect(ctx context.Context, conn dialect.ExecQuerier, name
 		desired = &schema.Schema{}
 	}
 	desired.Name, desired.Attrs = current.Name, current.Attrs
-	return a.diff(ctx, name, current, desired, a.types[len(types):], noQualifierOpt)
+	views, err := a.inspectMaterialized(ctx, conn, tables)
+	if err != nil {
+		return nil, err
+	}
+	policies, err := a.inspectPolicies(ctx, conn, tables)
+	if err != nil {
+		return nil, err
+	}
+	return a.diff(ctx, name, current, desired, tables, views, policies, a.types[len(types):], noQualifierOpt)
 }
 
 func (a *Atlas) planReplay(ctx context.Context, name string, tables []*Table) (*migrate.Plan, error) {
@@ -745,6 +775,14 @@ func (a *Atlas) planReplay(ctx context.Context, name string, tables []*Table) (*
 		}
 		a.types = types
 	}
+	views, err := a.inspectMaterialized(ctx, a.sqlDialect, tables)
+	if err != nil {
+		return nil, a.cleanSchema(ctx, a.schema, err)
+	}
+	policies, err := a.inspectPolicies(ctx, a.sqlDialect, tables)
+	if err != nil {
+		return nil, a.cleanSchema(ctx, a.schema, err)
+	}
 	if err = a.cleanSchema(ctx, a.schema, nil); err != nil {
 		return nil, fmt.Errorf("clean schemas after migration replaying: %w", err)
 	}
@@ -771,16 +809,36 @@ func (a *Atlas) planReplay(ctx context.Context, name string, tables []*Table) (*
 		}
 	}
 	return a.diff(ctx, name, current,
-		&schema.Schema{Name: current.Name, Attrs: current.Attrs, Tables: desired}, a.types[len(types):],
+		&schema.Schema{Name: current.Name, Attrs: current.Attrs, Tables: desired}, tables, views, policies, a.types[len(types):],
 		noQualifierOpt,
 	)
 }
 
-func (a *Atlas) diff(ctx context.Context, name string, current, desired *schema.Schema, newTypes []string, opts ...migrate.PlanOption) (*migrate.Plan, error) {
+func (a *Atlas) diff(ctx context.Context, name string, current, desired *schema.Schema, tables []*Table, views, policies map[string]map[string]bool, newTypes []string, opts ...migrate.PlanOption) (*migrate.Plan, error) {
+	keepGenerated(current, desired)
+	keepFullText(current, desired)
 	changes, err := (&diffDriver{a.atDriver, a.diffHooks}).SchemaDiff(current, desired, a.diffOptions...)
 	if err != nil {
 		return nil, err
 	}
+	plan, err := a.plan(ctx, name, changes, opts...)
+	if err != nil {
+		return nil, err
+	}
+	if len(newTypes) > 0 {
+		plan.Changes = append(plan.Changes, &migrate.Change{
+			Cmd:     a.sqlDialect.atTypeRangeSQL(a.typeTable, newTypes...),
+			Comment: fmt.Sprintf("add pk ranges for %s tables", strings.Join(newTypes, ",")),
+		})
+	}
+	plan.Changes = append(plan.Changes, a.fullTextChanges(current, tables)...)
+	plan.Changes = append(plan.Changes, a.materializedChanges(current, views, tables)...)
+	plan.Changes = append(plan.Changes, a.rowSecurityChanges(policies, tables)...)
+	return plan, nil
+}
+
+// plan creates a migration plan from the table changes of a schema diff.
+func (a *Atlas) plan(ctx context.Context, name string, changes []schema.Change, opts ...migrate.PlanOption) (*migrate.Plan, error) {
 	filtered := make([]schema.Change, 0, len(changes))
 	for _, c := range changes {
 		switch c.(type) {
@@ -797,17 +855,7 @@ func (a *Atlas) diff(ctx context.Context, name string, current, desired *schema.
 			opts.Indent = a.indent
 		})
 	}
-	plan, err := a.atDriver.PlanChanges(ctx, name, filtered, opts...)
-	if err != nil {
-		return nil, err
-	}
-	if len(newTypes) > 0 {
-		plan.Changes = append(plan.Changes, &migrate.Change{
-			Cmd:     a.sqlDialect.atTypeRangeSQL(a.typeTable, newTypes...),
-			Comment: fmt.Sprintf("add pk ranges for %s tables", strings.Join(newTypes, ",")),
-		})
-	}
-	return plan, nil
+	return a.atDriver.PlanChanges(ctx, name, filtered, opts...)
 }
 
 var errTypeTableNotFound = errors.New("type table not found")
@@ -869,13 +917,17 @@ func (a *Atlas) realm(tables []*Table) (*schema.Realm, error) {
 			sm[et.Schema] = schema.New(et.Schema)
 		}
 		s := sm[et.Schema]
-		if et.View {
-			if et.Annotation == nil || et.Annotation.ViewAs == "" && et.Annotation.ViewFor[a.dialect] == "" {
+		switch {
+		case et.snapshot(a.dialect):
+			// Materialized views are snapshot tables in MySQL and SQLite.
+		case et.View:
+			def := et.viewDef(a.dialect)
+			if def == "" {
 				continue // defined externally
 			}
-			def := et.Annotation.ViewFor[a.dialect]
-			if def == "" {
-				def = et.Annotation.ViewAs
+			// Materialized views are created by velox, see materializedChanges.
+			if et.materialized() {
+				continue
 			}
 			av := schema.NewView(et.Name, def)
 			if et.Comment != "" {
@@ -887,6 +939,9 @@ func (a *Atlas) realm(tables []*Table) (*schema.Realm, error) {
 			s.AddViews(av)
 			continue
 		}
+		if err := a.checkPartition(et); err != nil {
+			return nil, err
+		}
 		at := schema.NewTable(et.Name)
 		if et.Comment != "" {
 			at.SetComment(et.Comment)
@@ -916,11 +971,14 @@ func (a *Atlas) realm(tables []*Table) (*schema.Realm, error) {
 		byT[et] = at
 	}
 	for _, t1 := range tables {
-		if t1.View {
+		t2, ok := byT[t1]
+		if !ok {
 			continue
 		}
-		t2 := byT[t1]
 		for _, fk1 := range t1.ForeignKeys {
+			if a.skipPartitionFK(t1, fk1) {
+				continue
+			}
 			fk2 := schema.NewForeignKey(fk1.Symbol).
 				SetTable(t2).
 				SetOnUpdate(schema.ReferenceOption(fk1.OnUpdate)).
@@ -988,6 +1046,9 @@ func (a *Atlas) aColumns(et *Table, at *schema.Table) error {
 		if err := a.atDefault(c1, c2); err != nil {
 			return err
 		}
+		if err := a.atGenerated(c1, c2); err != nil {
+			return err
+		}
 		if c1.Unique && (len(et.PrimaryKey) != 1 || et.PrimaryKey[0] != c1) {
 			a.sqlDialect.atUniqueC(et, c1, at, c2)
 		}
@@ -1062,6 +1123,57 @@ func (a *Atlas) atDefault(c1 *Column, c2 *schema.Column) error {
 	return nil
 }
 
+// atGenerated sets the expression of a generated column.
+func (a *Atlas) atGenerated(c1 *Column, c2 *schema.Column) error {
+	if c1.Generated == nil {
+		return nil
+	}
+	x := c1.Generated.ExprFor(a.sqlDialect.Dialect())
+	if x == "" {
+		return fmt.Errorf("generated column %q has no expression for dialect %q", c1.Name, a.sqlDialect.Dialect())
+	}
+	c2.SetGeneratedExpr(&schema.GeneratedExpr{Expr: x, Type: c1.Generated.Type})
+	return nil
+}
+
+// keepGenerated makes the generated columns of the desired schema read-only
+// for the diff: a generated column that already exists keeps its current
+// expression and type. Databases store expressions in a normalized form that
+// differs from the schema, and most of them cannot alter the expression of a
+// column, so changing one requires a hand-written migration.
+func keepGenerated(current, desired *schema.Schema) {
+	for _, t2 := range desired.Tables {
+		t1, ok := current.Table(t2.Name)
+		if !ok {
+			continue
+		}
+		for _, c2 := range t2.Columns {
+			x2 := generatedExpr(c2)
+			if x2 == nil {
+				continue
+			}
+			c1, ok := t1.Column(c2.Name)
+			if !ok {
+				continue
+			}
+			if x1 := generatedExpr(c1); x1 != nil {
+				*x2 = *x1
+				c2.Type = c1.Type
+			}
+		}
+	}
+}
+
+// generatedExpr returns the generated expression of the column, or nil if it is not generated.
+func generatedExpr(c *schema.Column) *schema.GeneratedExpr {
+	for _, a := range c.Attrs {
+		if x, ok := a.(*schema.GeneratedExpr); ok {
+			return x
+		}
+	}
+	return nil
+}
+
 func (a *Atlas) aIndexes(et *Table, at *schema.Table) error {
 	// Primary-key index.
 	pk := make([]*schema.Column, 0, len(et.PrimaryKey))
@@ -1072,12 +1184,17 @@ func (a *Atlas) aIndexes(et *Table, at *schema.Table) error {
 		}
 		pk = append(pk, c2)
 	}
+	pk = a.partitionPK(et, at, pk)
 	// CreateFunc might clear the primary keys.
 	if len(pk) > 0 {
 		at.SetPrimaryKey(schema.NewPrimaryKey(pk...))
 	}
 	// Rest of indexes.
 	for _, idx1 := range et.Indexes {
+		// SQLite full-text indexes are FTS5 tables, see fullTextChanges.
+		if idx1.fullText() && a.sqlDialect.Dialect() == dialect.SQLite {
+			continue
+		}
 		idx2 := schema.NewIndex(idx1.Name).
 			SetUnique(idx1.Unique)
 		if err := a.sqlDialect.atIndex(idx1, at, idx2); err != nil {
@@ -11,6 +11,7 @@ import (
 	"time"
 
 	"ariga.io/atlas/sql/migrate"
+	"ariga.io/atlas/sql/mysql"
 	"ariga.io/atlas/sql/postgres"
 	"ariga.io/atlas/sql/schema"
 	"ariga.io/atlas/sql/sqlite"
@@ -934,6 +935,22 @@ func TestPostgres_AtIndex(t *testing.T) {
 		assert.Equal(t, "status = 'active'", pred.P)
 	})
 
+	t.Run("full-text index", func(t *testing.T) {
+		at := schema.NewTable("test")
+		at.AddColumns(schema.NewColumn("title"), schema.NewColumn("body"))
+		idx1 := &Index{
+			Name:       "idx_search",
+			Columns:    []*Column{{Name: "title"}, {Name: "body"}},
+			Annotation: sqlschema.FullTextConfig("simple"),
+		}
+		idx2 := schema.NewIndex("idx_search")
+		err := d.atIndex(idx1, at, idx2)
+		require.NoError(t, err)
+		require.Len(t, idx2.Parts, 1)
+		assert.Equal(t, `to_tsvector('simple', coalesce("title", '') || ' ' || coalesce("body", ''))`, idx2.Parts[0].X.(*schema.RawExpr).X)
+		assert.Contains(t, idx2.Attrs, &postgres.IndexType{T: "GIN"})
+	})
+
 	t.Run("index with missing column", func(t *testing.T) {
 		at := schema.NewTable("test")
 		idx1 := &Index{
@@ -1087,6 +1104,21 @@ func TestSQLite_AtIndex(t *testing.T) {
 func TestMySQL_AtIndex(t *testing.T) {
 	d := &MySQL{version: "8.0.0"}
 
+	t.Run("full-text index", func(t *testing.T) {
+		at := schema.NewTable("test")
+		at.AddColumns(schema.NewColumn("title"), schema.NewColumn("body"))
+		idx1 := &Index{
+			Name:       "idx_search",
+			Columns:    []*Column{{Name: "title"}, {Name: "body"}},
+			Annotation: sqlschema.FullText(),
+		}
+		idx2 := schema.NewIndex("idx_search")
+		err := d.atIndex(idx1, at, idx2)
+		require.NoError(t, err)
+		require.Len(t, idx2.Parts, 2)
+		assert.Contains(t, idx2.Attrs, &mysql.IndexType{T: "FULLTEXT"})
+	})
+
 	t.Run("with index type", func(t *testing.T) {
 		at := schema.NewTable("test")
 		at.AddColumns(schema.NewColumn("data"))
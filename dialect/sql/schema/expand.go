@@ -0,0 +1,396 @@
+package schema
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"ariga.io/atlas/sql/migrate"
+	"ariga.io/atlas/sql/schema"
+
+	"github.com/syssam/velox/dialect"
+	entsql "github.com/syssam/velox/dialect/sql"
+	"github.com/syssam/velox/schema/field"
+)
+
+// TransitionKind describes why a column change needs more than one migration.
+type TransitionKind uint
+
+// List of transition kinds.
+const (
+	// TransitionRename moves the data of a column to a new column name.
+	TransitionRename TransitionKind = iota + 1
+	// TransitionRetype is a rename that also changes the column type.
+	TransitionRetype
+	// TransitionNotNull adds a NOT NULL column without a default,
+	// or makes a nullable column NOT NULL.
+	TransitionNotNull
+)
+
+// String implements fmt.Stringer.
+func (k TransitionKind) String() string {
+	switch k {
+	case TransitionRename:
+		return "rename"
+	case TransitionRetype:
+		return "retype"
+	case TransitionNotNull:
+		return "not null"
+	default:
+		return fmt.Sprintf("TransitionKind(%d)", uint(k))
+	}
+}
+
+// Transition is a column change that cannot be applied in one step without
+// breaking the application instances that still run against the old schema.
+type Transition struct {
+	Kind   TransitionKind
+	Table  string
+	Column string // desired column name.
+	From   string // current column name. Equals Column, unless renamed.
+}
+
+// Phase names an ordered step of an expand/contract plan.
+type Phase string
+
+// List of phases, in the order they are applied.
+const (
+	// PhaseExpand adds the new columns. NOT NULL constraints of the
+	// transitioned columns, and of the old columns of retyped renames,
+	// are deferred to the contract phase.
+	PhaseExpand Phase = "expand"
+	// PhaseBackfill copies renamed columns and fills NULL values.
+	PhaseBackfill Phase = "backfill"
+	// PhaseDualWrite is the window in which application code, generated
+	// with the sqlschema.RenamedFrom annotations, writes both columns.
+	// It has no statements.
+	PhaseDualWrite Phase = "dual_write"
+	// PhaseContract drops the old columns and applies the NOT NULL constraints.
+	PhaseContract Phase = "contract"
+)
+
+type (
+	// ExpandContractPlan is a zero-downtime migration plan, as returned by
+	// Atlas.PlanExpandContract.
+	ExpandContractPlan struct {
+		Name        string
+		Transitions []*Transition
+		Phases      []*PhasePlan
+	}
+
+	// PhasePlan holds the statements of a single phase.
+	PhasePlan struct {
+		Phase   Phase
+		Changes []*migrate.Change
+	}
+)
+
+// Phase returns the plan of the given phase, or nil if it is not part of the plan.
+func (p *ExpandContractPlan) Phase(phase Phase) *PhasePlan {
+	for _, pp := range p.Phases {
+		if pp.Phase == phase {
+			return pp
+		}
+	}
+	return nil
+}
+
+// WriteDir writes every phase that has statements as a separate migration
+// file to the directory of w, named "<name>_<phase>". Files get consecutive
+// versions, so the formatter of w must use the plan version, like Atlas'
+// default formatter does.
+func (p *ExpandContractPlan) WriteDir(w *DirWriter) error {
+	if w.b.Len() != 0 || len(w.changes) != 0 {
+		return errors.New("writer has pending changes")
+	}
+	defer func() { w.changes = nil }()
+	base := time.Now().UTC()
+	var n int
+	for _, pp := range p.Phases {
+		if len(pp.Changes) == 0 {
+			continue
+		}
+		w.changes = pp.Changes
+		version := base.Add(time.Duration(n) * time.Second).Format("20060102150405")
+		if err := w.flush(version, fmt.Sprintf("%s_%s", p.Name, pp.Phase)); err != nil {
+			return fmt.Errorf("write %s phase: %w", pp.Phase, err)
+		}
+		n++
+	}
+	if n == 0 {
+		return errors.New("plan has no changes to write")
+	}
+	return nil
+}
+
+// PlanExpandContract compares the connected database with the given tables and
+// splits the changes that would break running application instances into
+// ordered phases: expand, backfill, dual-write and contract.
+//
+// Three kinds of changes are planned this way:
+//
+//   - Columns annotated with sqlschema.RenamedFrom are added next to the old
+//     column, backfilled from it, and the old column is dropped on contract.
+//     A rename may also change the column type.
+//   - NOT NULL columns without a default are added as nullable, backfilled with
+//     the zero value of their type and made NOT NULL on contract. The same
+//     applies to nullable columns that become NOT NULL.
+//   - In-place type changes are rejected; rename the column instead, so both
+//     versions can be written during the dual-write window.
+//
+// All other changes are part of the expand phase.
+//
+//	m, err := schema.NewMigrate(drv)
+//	plan, err := m.PlanExpandContract(ctx, "rename_user_name", migrate.Tables...)
+//	err = plan.WriteDir(&schema.DirWriter{Dir: dir})
+func (a *Atlas) PlanExpandContract(ctx context.Context, name string, tables ...*Table) (*ExpandContractPlan, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.setupTables(tables)
+	closeFn, err := a.connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer closeFn()
+	if err := a.sqlDialect.init(ctx); err != nil {
+		return nil, err
+	}
+	current, err := a.atDriver.InspectSchema(ctx, a.schema, &schema.InspectOptions{
+		Tables: a.inspectTables(tables),
+		Mode:   schema.InspectSchemas | schema.InspectTables,
+	})
+	if err != nil {
+		return nil, err
+	}
+	// The desired state and the expanded (intermediate) state
+	// are converted separately, as the latter is modified below.
+	desired, err := a.desiredSchema(ctx, current, tables)
+	if err != nil {
+		return nil, err
+	}
+	expanded, err := a.desiredSchema(ctx, current, tables)
+	if err != nil {
+		return nil, err
+	}
+	plan := &ExpandContractPlan{Name: name}
+	backfill := &PhasePlan{Phase: PhaseBackfill}
+	for _, et := range tables {
+		ct, ok := current.Table(et.Name)
+		if et.View || !ok {
+			continue
+		}
+		xt, ok := expanded.Table(et.Name)
+		if !ok {
+			continue
+		}
+		for _, ec := range et.Columns {
+			tr, err := a.transition(et, ec, ct, xt)
+			if err != nil {
+				return nil, err
+			}
+			if tr == nil {
+				continue
+			}
+			c, err := a.backfill(ctx, et, ec, xt, tr)
+			if err != nil {
+				return nil, err
+			}
+			plan.Transitions = append(plan.Transitions, tr)
+			backfill.Changes = append(backfill.Changes, c)
+		}
+	}
+	views, err := a.inspectMaterialized(ctx, a.sqlDialect, tables)
+	if err != nil {
+		return nil, err
+	}
+	policies, err := a.inspectPolicies(ctx, a.sqlDialect, tables)
+	if err != nil {
+		return nil, err
+	}
+	expand, err := a.diff(ctx, name, current, expanded, tables, views, policies, nil, noQualifierOpt)
+	if err != nil {
+		return nil, err
+	}
+	// The contract phase drops columns, which the configured diff hooks
+	// skip by default. Hence, the raw diff is used here.
+	keepGenerated(expanded, desired)
+	changes, err := a.atDriver.SchemaDiff(expanded, desired, a.diffOptions...)
+	if err != nil {
+		return nil, err
+	}
+	contract, err := a.plan(ctx, name, changes, noQualifierOpt)
+	if err != nil {
+		return nil, err
+	}
+	plan.Phases = []*PhasePlan{
+		{Phase: PhaseExpand, Changes: expand.Changes},
+		backfill,
+		{Phase: PhaseDualWrite},
+		{Phase: PhaseContract, Changes: contract.Changes},
+	}
+	return plan, nil
+}
+
+// desiredSchema returns the Atlas schema of the given tables.
+func (a *Atlas) desiredSchema(ctx context.Context, current *schema.Schema, tables []*Table) (*schema.Schema, error) {
+	realm, err := a.StateReader(tables...).ReadState(ctx)
+	if err != nil {
+		return nil, err
+	}
+	desired := &schema.Schema{}
+	if realm != nil && len(realm.Schemas) > 0 {
+		desired = realm.Schemas[0]
+	}
+	desired.Name, desired.Attrs = current.Name, current.Attrs
+	return desired, nil
+}
+
+// transition reports the transition of the given column, if any, and relaxes
+// the column in the expanded table (xt) accordingly. ct is the current table.
+func (a *Atlas) transition(et *Table, ec *Column, ct, xt *schema.Table) (*Transition, error) {
+	xc, ok := xt.Column(ec.Name)
+	if !ok {
+		return nil, nil
+	}
+	cc, exists := ct.Column(ec.Name)
+	if ec.RenamedFrom != "" {
+		if oc, ok := ct.Column(ec.RenamedFrom); ok {
+			kind := TransitionRename
+			if !a.sameType(oc.Type.Type, xc.Type.Type) {
+				kind = TransitionRetype
+			}
+			// The generated code writes the old column of renamed
+			// columns, which fails or truncates values of another type.
+			if kind == TransitionRetype && !ec.Retyped {
+				return nil, fmt.Errorf("sql/schema: column %q.%q is renamed from %q, which has another type. Rename it with sqlschema.RetypedFrom(%q) instead", et.Name, ec.Name, ec.RenamedFrom, ec.RenamedFrom)
+			}
+			// Keep the old column until the contract phase. The old column
+			// of a retyped rename is not written by the new release, so it
+			// must accept the rows inserted without it during the rollout.
+			if kind == TransitionRetype && !oc.Type.Null {
+				rc, rt := *oc, *oc.Type
+				rc.Type = &rt
+				rc.SetNull(true)
+				oc = &rc
+			}
+			xc.SetNull(true)
+			xt.AddColumns(oc)
+			return &Transition{Kind: kind, Table: et.Name, Column: ec.Name, From: oc.Name}, nil
+		}
+	}
+	switch {
+	case ec.Generated != nil:
+		// Generated columns are computed by the database.
+		return nil, nil
+	case !exists:
+		if ec.Nullable || ec.Default != nil || ec.Increment || et.isPrimaryKey(ec) {
+			return nil, nil
+		}
+	case !a.sameType(cc.Type.Type, xc.Type.Type):
+		return nil, fmt.Errorf("sql/schema: column %q.%q changes its type in place. Rename it with sqlschema.RenamedFrom(%q) to plan it in phases", et.Name, ec.Name, ec.Name)
+	case !cc.Type.Null || ec.Nullable:
+		return nil, nil
+	}
+	xc.SetNull(true)
+	return &Transition{Kind: TransitionNotNull, Table: et.Name, Column: ec.Name, From: ec.Name}, nil
+}
+
+// isPrimaryKey reports if the column is part of the table primary key.
+func (t *Table) isPrimaryKey(c *Column) bool {
+	for _, pk := range t.PrimaryKey {
+		if pk.Name == c.Name {
+			return true
+		}
+	}
+	return false
+}
+
+// sameType reports if the two column types are formatted the same
+// by the database driver.
+func (a *Atlas) sameType(t1, t2 schema.Type) bool {
+	f, ok := a.atDriver.(schema.TypeFormatter)
+	if !ok {
+		return true
+	}
+	s1, err1 := f.FormatType(t1)
+	s2, err2 := f.FormatType(t2)
+	return err1 == nil && err2 == nil && strings.EqualFold(s1, s2)
+}
+
+// backfill returns the statement that fills the column of the transition.
+// xt is the expanded table.
+func (a *Atlas) backfill(ctx context.Context, et *Table, ec *Column, xt *schema.Table, tr *Transition) (*migrate.Change, error) {
+	u := entsql.Dialect(a.dialect).Update(et.Name)
+	var comment string
+	switch tr.Kind {
+	case TransitionRename, TransitionRetype:
+		typ := ""
+		if tr.Kind == TransitionRetype && a.dialect == dialect.Postgres {
+			xc, _ := xt.Column(ec.Name)
+			f, ok := a.atDriver.(schema.TypeFormatter)
+			if !ok {
+				return nil, fmt.Errorf("sql/schema: driver %T cannot format column types", a.atDriver)
+			}
+			t, err := f.FormatType(xc.Type.Type)
+			if err != nil {
+				return nil, err
+			}
+			typ = t
+		}
+		u.Set(ec.Name, entsql.ExprFunc(func(b *entsql.Builder) {
+			if typ == "" {
+				b.Ident(tr.From)
+				return
+			}
+			b.WriteString("CAST(").Ident(tr.From).WriteString(" AS " + typ + ")")
+		}))
+		// Running the statement again after the new release is deployed
+		// copies only the rows written without the new column.
+		u.Where(entsql.IsNull(ec.Name))
+		comment = fmt.Sprintf("copy %q.%q to %q", et.Name, tr.From, ec.Name)
+	default:
+		v, err := backfillValue(a.dialect, ec)
+		if err != nil {
+			return nil, fmt.Errorf("sql/schema: backfill %q.%q: %w", et.Name, ec.Name, err)
+		}
+		u.Set(ec.Name, v).Where(entsql.IsNull(ec.Name))
+		comment = fmt.Sprintf("fill NULL values of %q.%q", et.Name, ec.Name)
+	}
+	query, args := u.Query()
+	var b strings.Builder
+	if err := NewWriteDriver(a.dialect, &b).Exec(ctx, query, args, nil); err != nil {
+		return nil, err
+	}
+	return &migrate.Change{Cmd: strings.TrimSuffix(strings.TrimSpace(b.String()), ";"), Comment: comment}, nil
+}
+
+// backfillValue returns the value used to fill the NULL values of a column
+// that becomes NOT NULL: its default value, or the zero value of its type
+// (the first value for enums).
+func backfillValue(d string, c *Column) (any, error) {
+	switch v := c.Default.(type) {
+	case Expr:
+		return entsql.Expr(string(v)), nil
+	case map[string]Expr:
+		if x, ok := v[d]; ok {
+			return entsql.Expr(string(x)), nil
+		}
+	case nil:
+	default:
+		return v, nil
+	}
+	switch {
+	case c.Type == field.TypeEnum && len(c.Enums) > 0:
+		return c.Enums[0], nil
+	case c.Type == field.TypeString || c.Type == field.TypeEnum:
+		return "", nil
+	case c.Type == field.TypeBool:
+		return false, nil
+	case c.Type.Numeric():
+		return 0, nil
+	default:
+		return nil, fmt.Errorf("no zero value for column type %s. Set a default value", c.Type)
+	}
+}
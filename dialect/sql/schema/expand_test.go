@@ -0,0 +1,193 @@
+package schema
+
+import (
+	"context"
+	"testing"
+
+	"ariga.io/atlas/sql/migrate"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/syssam/velox/dialect"
+	entsql "github.com/syssam/velox/dialect/sql"
+	"github.com/syssam/velox/schema/field"
+)
+
+func TestAtlas_PlanExpandContract(t *testing.T) {
+	ctx := context.Background()
+	db := openVersionedSQLite(t)
+	for _, stmt := range []string{
+		"CREATE TABLE `users` (`id` integer NOT NULL PRIMARY KEY AUTOINCREMENT, `name` text NOT NULL, `nickname` text NULL)",
+		"INSERT INTO `users` (`name`, `nickname`) VALUES ('a8m', NULL), ('neta', 'n')",
+	} {
+		_, err := db.Exec(stmt)
+		require.NoError(t, err)
+	}
+	id := &Column{Name: "id", Type: field.TypeInt, Increment: true}
+	users := &Table{
+		Name: "users",
+		Columns: []*Column{
+			id,
+			{Name: "full_name", Type: field.TypeString, RenamedFrom: "name"},
+			{Name: "nickname", Type: field.TypeString},
+			{Name: "age", Type: field.TypeInt},
+			{Name: "bio", Type: field.TypeString, Nullable: true},
+		},
+		PrimaryKey: []*Column{id},
+	}
+	m, err := NewMigrate(entsql.OpenDB(dialect.SQLite, db))
+	require.NoError(t, err)
+	plan, err := m.PlanExpandContract(ctx, "users", users)
+	require.NoError(t, err)
+
+	require.Len(t, plan.Transitions, 3)
+	assert.Equal(t, &Transition{Kind: TransitionRename, Table: "users", Column: "full_name", From: "name"}, plan.Transitions[0])
+	assert.Equal(t, &Transition{Kind: TransitionNotNull, Table: "users", Column: "nickname", From: "nickname"}, plan.Transitions[1])
+	assert.Equal(t, &Transition{Kind: TransitionNotNull, Table: "users", Column: "age", From: "age"}, plan.Transitions[2])
+
+	require.Len(t, plan.Phases, 4)
+	for i, phase := range []Phase{PhaseExpand, PhaseBackfill, PhaseDualWrite, PhaseContract} {
+		assert.Equal(t, phase, plan.Phases[i].Phase)
+	}
+	assert.Empty(t, plan.Phase(PhaseDualWrite).Changes)
+	backfill := plan.Phase(PhaseBackfill).Changes
+	require.Len(t, backfill, 3)
+	assert.Equal(t, "UPDATE `users` SET `full_name` = `name` WHERE `full_name` IS NULL", backfill[0].Cmd)
+	assert.Equal(t, "UPDATE `users` SET `nickname` = '' WHERE `nickname` IS NULL", backfill[1].Cmd)
+	assert.Equal(t, "UPDATE `users` SET `age` = 0 WHERE `age` IS NULL", backfill[2].Cmd)
+
+	mem := &migrate.MemDir{}
+	require.NoError(t, plan.WriteDir(&DirWriter{Dir: mem}))
+	files, err := mem.Files()
+	require.NoError(t, err)
+	require.Len(t, files, 3)
+	dir := make(mapDir)
+	for _, f := range files {
+		dir[f.Name()] = string(f.Bytes())
+	}
+	assert.Contains(t, files[0].Name(), "_users_expand.sql")
+	assert.Contains(t, files[1].Name(), "_users_backfill.sql")
+	assert.Contains(t, files[2].Name(), "_users_contract.sql")
+
+	// Apply the expand and backfill phases. The old column is kept.
+	r, err := NewVersionedRunner(db, dialect.SQLite, dir)
+	require.NoError(t, err)
+	_, err = r.Up(ctx, 2)
+	require.NoError(t, err)
+	var name, fullName, nickname string
+	require.NoError(t, db.QueryRow("SELECT `name`, `full_name`, `nickname` FROM `users` WHERE `id` = 1").Scan(&name, &fullName, &nickname))
+	assert.Equal(t, "a8m", name)
+	assert.Equal(t, "a8m", fullName)
+	assert.Equal(t, "", nickname)
+
+	// Contract drops the old column and applies the NOT NULL constraints.
+	_, err = r.Up(ctx, 0)
+	require.NoError(t, err)
+	cols := queryPragma(t, db, "PRAGMA table_info(`users`)")
+	notnull := make(map[string]string)
+	for _, c := range cols {
+		notnull[c["name"]] = c["notnull"]
+	}
+	assert.Equal(t, map[string]string{"id": "1", "full_name": "1", "nickname": "1", "age": "1", "bio": "0"}, notnull)
+
+	// Planning again is a no-op.
+	plan, err = m.PlanExpandContract(ctx, "users", users)
+	require.NoError(t, err)
+	assert.Empty(t, plan.Transitions)
+	require.Error(t, plan.WriteDir(&DirWriter{Dir: &migrate.MemDir{}}))
+}
+
+func TestAtlas_PlanExpandContractTypeChange(t *testing.T) {
+	ctx := context.Background()
+	db := openVersionedSQLite(t)
+	_, err := db.Exec("CREATE TABLE `users` (`id` integer NOT NULL PRIMARY KEY AUTOINCREMENT, `age` text NOT NULL)")
+	require.NoError(t, err)
+	m, err := NewMigrate(entsql.OpenDB(dialect.SQLite, db))
+	require.NoError(t, err)
+	id := &Column{Name: "id", Type: field.TypeInt, Increment: true}
+	_, err = m.PlanExpandContract(ctx, "users", &Table{
+		Name:       "users",
+		Columns:    []*Column{id, {Name: "age", Type: field.TypeInt}},
+		PrimaryKey: []*Column{id},
+	})
+	require.ErrorContains(t, err, "changes its type in place")
+
+	// The old column of a renamed column is written with the new values.
+	id = &Column{Name: "id", Type: field.TypeInt, Increment: true}
+	_, err = m.PlanExpandContract(ctx, "users", &Table{
+		Name:       "users",
+		Columns:    []*Column{id, {Name: "age_years", Type: field.TypeInt, RenamedFrom: "age"}},
+		PrimaryKey: []*Column{id},
+	})
+	require.ErrorContains(t, err, `sqlschema.RetypedFrom("age")`)
+
+	id = &Column{Name: "id", Type: field.TypeInt, Increment: true}
+	plan, err := m.PlanExpandContract(ctx, "users", &Table{
+		Name:       "users",
+		Columns:    []*Column{id, {Name: "age_years", Type: field.TypeInt, RenamedFrom: "age", Retyped: true}},
+		PrimaryKey: []*Column{id},
+	})
+	require.NoError(t, err)
+	require.Len(t, plan.Transitions, 1)
+	assert.Equal(t, TransitionRetype, plan.Transitions[0].Kind)
+	backfill := plan.Phase(PhaseBackfill).Changes
+	require.Len(t, backfill, 1)
+	assert.Equal(t, "UPDATE `users` SET `age_years` = `age` WHERE `age_years` IS NULL", backfill[0].Cmd)
+
+	_, err = db.Exec("INSERT INTO `users` (`age`) VALUES ('30')")
+	require.NoError(t, err)
+	mem := &migrate.MemDir{}
+	require.NoError(t, plan.WriteDir(&DirWriter{Dir: mem}))
+	files, err := mem.Files()
+	require.NoError(t, err)
+	dir := make(mapDir)
+	for _, f := range files {
+		dir[f.Name()] = string(f.Bytes())
+	}
+	r, err := NewVersionedRunner(db, dialect.SQLite, dir)
+	require.NoError(t, err)
+	_, err = r.Up(ctx, 2)
+	require.NoError(t, err)
+
+	// The new release does not write the old column, which is relaxed
+	// to NULL by the expand phase.
+	_, err = db.Exec("INSERT INTO `users` (`age_years`) VALUES (40)")
+	require.NoError(t, err)
+	_, err = db.Exec("UPDATE `users` SET `age_years` = 31 WHERE `id` = 1")
+	require.NoError(t, err)
+	// Running the backfill again keeps the values of the new release.
+	_, err = db.Exec(backfill[0].Cmd)
+	require.NoError(t, err)
+	var ages []int
+	rows, err := db.Query("SELECT `age_years` FROM `users` ORDER BY `id`")
+	require.NoError(t, err)
+	defer rows.Close()
+	for rows.Next() {
+		var age int
+		require.NoError(t, rows.Scan(&age))
+		ages = append(ages, age)
+	}
+	require.NoError(t, rows.Err())
+	assert.Equal(t, []int{31, 40}, ages)
+}
+
+func TestBackfillValue(t *testing.T) {
+	tests := []struct {
+		col  *Column
+		want any
+	}{
+		{col: &Column{Type: field.TypeString}, want: ""},
+		{col: &Column{Type: field.TypeEnum, Enums: []string{"a", "b"}}, want: "a"},
+		{col: &Column{Type: field.TypeBool}, want: false},
+		{col: &Column{Type: field.TypeFloat64}, want: 0},
+		{col: &Column{Type: field.TypeString, Default: "x"}, want: "x"},
+		{col: &Column{Type: field.TypeTime, Default: Expr("CURRENT_TIMESTAMP")}, want: entsql.Expr("CURRENT_TIMESTAMP")},
+	}
+	for _, tt := range tests {
+		v, err := backfillValue(dialect.SQLite, tt.col)
+		require.NoError(t, err)
+		assert.Equal(t, tt.want, v)
+	}
+	_, err := backfillValue(dialect.SQLite, &Column{Type: field.TypeTime})
+	require.Error(t, err)
+}
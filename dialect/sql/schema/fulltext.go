@@ -0,0 +1,168 @@
+package schema
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"ariga.io/atlas/sql/migrate"
+	"ariga.io/atlas/sql/schema"
+
+	"github.com/syssam/velox/dialect"
+	entsql "github.com/syssam/velox/dialect/sql"
+)
+
+// fullText reports if the index is a full-text index.
+func (i *Index) fullText() bool {
+	return i.Annotation != nil && i.Annotation.FullText
+}
+
+// inspectTables returns the names of the tables to inspect for the given
+// tables: the tables themselves, and the FTS5 tables of their full-text
+// indexes in SQLite.
+func (a *Atlas) inspectTables(tables []*Table) []string {
+	names := make([]string, 0, len(tables))
+	for _, t := range tables {
+		names = append(names, t.Name)
+		if a.sqlDialect.Dialect() != dialect.SQLite {
+			continue
+		}
+		for _, idx := range t.Indexes {
+			if idx.fullText() {
+				names = append(names, idx.Name)
+			}
+		}
+	}
+	return names
+}
+
+// fullTextChanges returns the changes that create the SQLite full-text indexes
+// that do not exist in the current schema. An index is an external-content FTS5
+// table named after the index, kept in sync with its table by triggers:
+//
+//	CREATE VIRTUAL TABLE `post_title_body` USING fts5(`title`, `body`, content=`posts`)
+//	CREATE TRIGGER `post_title_body_insert` AFTER INSERT ON `posts` BEGIN ... END
+//	...
+//	INSERT INTO `post_title_body`(`post_title_body`) VALUES ('rebuild')
+//
+// The FTS5 table references the rows of the table by their rowid. Hence, the
+// table should have an integer primary key, as other rowids are not stable.
+func (a *Atlas) fullTextChanges(current *schema.Schema, tables []*Table) []*migrate.Change {
+	if a.sqlDialect.Dialect() != dialect.SQLite {
+		return nil
+	}
+	var changes []*migrate.Change
+	for _, t := range tables {
+		for _, idx := range t.Indexes {
+			if !idx.fullText() {
+				continue
+			}
+			if _, ok := current.Table(idx.Name); ok {
+				continue
+			}
+			for _, cmd := range sqliteFullText(t, idx) {
+				changes = append(changes, &migrate.Change{
+					Cmd:     cmd,
+					Comment: fmt.Sprintf("create full-text index %q", idx.Name),
+				})
+			}
+		}
+	}
+	return changes
+}
+
+// sqliteFullText returns the statements that create the FTS5 table
+// of a full-text index, its triggers, and index the existing rows.
+func sqliteFullText(t *Table, idx *Index) []string {
+	b := entsql.Dialect(dialect.SQLite)
+	q := func(f func(*entsql.Builder)) string { return b.String(f) }
+	columns := make([]string, len(idx.Columns))
+	for i, c := range idx.Columns {
+		columns[i] = c.Name
+	}
+	values := func(row string) string {
+		vs := make([]string, len(columns))
+		for i, c := range columns {
+			vs[i] = row + "." + q(func(b *entsql.Builder) { b.Ident(c) })
+		}
+		return strings.Join(vs, ", ")
+	}
+	fts, table := q(func(b *entsql.Builder) { b.Ident(idx.Name) }), q(func(b *entsql.Builder) { b.Ident(t.Name) })
+	list := q(func(b *entsql.Builder) { b.IdentComma(columns...) })
+	insert := fmt.Sprintf("INSERT INTO %s(rowid, %s) VALUES (new.rowid, %s);", fts, list, values("new"))
+	remove := fmt.Sprintf("INSERT INTO %s(%s, rowid, %s) VALUES ('delete', old.rowid, %s);", fts, fts, list, values("old"))
+	trigger := func(event, body string) string {
+		name := q(func(b *entsql.Builder) { b.Ident(idx.Name + "_" + strings.ToLower(event)) })
+		return fmt.Sprintf("CREATE TRIGGER %s AFTER %s ON %s BEGIN %s END", name, event, table, body)
+	}
+	return []string{
+		fmt.Sprintf("CREATE VIRTUAL TABLE %s USING fts5(%s, content=%s)", fts, list, table),
+		trigger("INSERT", insert),
+		trigger("DELETE", remove),
+		trigger("UPDATE", remove+" "+insert),
+		fmt.Sprintf("INSERT INTO %s(%s) VALUES ('rebuild')", fts, fts),
+	}
+}
+
+var (
+	// tsvectorConfig matches the text search configuration of a tsvector expression.
+	tsvectorConfig = regexp.MustCompile(`to_tsvector\('([^']*)'`)
+	// coalesceColumn matches the columns of a tsvector expression.
+	coalesceColumn = regexp.MustCompile(`(?i)coalesce\(\(*"?([^",()]+)"?`)
+)
+
+// keepFullText makes the full-text indexes of the desired schema keep their
+// current expression if they index the same columns with the same text search
+// configuration. PostgreSQL stores the tsvector expression in a normalized
+// form (with casts) that differs from the one in the schema.
+func keepFullText(current, desired *schema.Schema) {
+	for _, t2 := range desired.Tables {
+		t1, ok := current.Table(t2.Name)
+		if !ok {
+			continue
+		}
+		for _, idx2 := range t2.Indexes {
+			x2 := tsvectorExpr(idx2)
+			if x2 == nil {
+				continue
+			}
+			idx1, ok := t1.Index(idx2.Name)
+			if !ok {
+				continue
+			}
+			if x1 := tsvectorExpr(idx1); x1 != nil && sameTSVector(x1.X, x2.X) {
+				x2.X = x1.X
+			}
+		}
+	}
+}
+
+// tsvectorExpr returns the expression of a tsvector index, or nil if it is not one.
+func tsvectorExpr(idx *schema.Index) *schema.RawExpr {
+	if len(idx.Parts) != 1 {
+		return nil
+	}
+	x, ok := idx.Parts[0].X.(*schema.RawExpr)
+	if !ok || !strings.Contains(x.X, "to_tsvector(") {
+		return nil
+	}
+	return x
+}
+
+// sameTSVector reports if the tsvector expressions index the same columns
+// with the same text search configuration.
+func sameTSVector(x1, x2 string) bool {
+	if tsvectorConfig.FindString(x1) != tsvectorConfig.FindString(x2) {
+		return false
+	}
+	c1, c2 := coalesceColumn.FindAllStringSubmatch(x1, -1), coalesceColumn.FindAllStringSubmatch(x2, -1)
+	if len(c1) != len(c2) {
+		return false
+	}
+	for i := range c1 {
+		if c1[i][1] != c2[i][1] {
+			return false
+		}
+	}
+	return true
+}
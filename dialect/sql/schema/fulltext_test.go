@@ -0,0 +1,48 @@
+package schema
+
+import (
+	"testing"
+
+	"ariga.io/atlas/sql/schema"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestKeepFullText(t *testing.T) {
+	index := func(x string) *schema.Schema {
+		t := schema.NewTable("posts").AddIndexes(schema.NewIndex("post_title_body").AddParts(&schema.IndexPart{X: &schema.RawExpr{X: x}}))
+		return schema.New("public").AddTables(t)
+	}
+	expr := func(s *schema.Schema) string {
+		return s.Tables[0].Indexes[0].Parts[0].X.(*schema.RawExpr).X
+	}
+	// The expression as it is inspected from the database.
+	const inspected = `to_tsvector('english'::regconfig, ((COALESCE((title)::text, ''::text) || ' '::text) || COALESCE(body, ''::text)))`
+
+	desired := index(`to_tsvector('english', coalesce("title", '') || ' ' || coalesce("body", ''))`)
+	keepFullText(index(inspected), desired)
+	require.Equal(t, inspected, expr(desired))
+
+	// Other columns or configurations are changes.
+	for _, x := range []string{
+		`to_tsvector('english', coalesce("title", ''))`,
+		`to_tsvector('english', coalesce("body", '') || ' ' || coalesce("title", ''))`,
+		`to_tsvector('simple', coalesce("title", '') || ' ' || coalesce("body", ''))`,
+	} {
+		desired := index(x)
+		keepFullText(index(inspected), desired)
+		require.Equal(t, x, expr(desired))
+	}
+}
+
+func TestSQLiteFullText(t *testing.T) {
+	title, body := &Column{Name: "title"}, &Column{Name: "body"}
+	stmts := sqliteFullText(&Table{Name: "posts"}, &Index{Name: "post_search", Columns: []*Column{title, body}})
+	require.Equal(t, []string{
+		"CREATE VIRTUAL TABLE `post_search` USING fts5(`title`, `body`, content=`posts`)",
+		"CREATE TRIGGER `post_search_insert` AFTER INSERT ON `posts` BEGIN INSERT INTO `post_search`(rowid, `title`, `body`) VALUES (new.rowid, new.`title`, new.`body`); END",
+		"CREATE TRIGGER `post_search_delete` AFTER DELETE ON `posts` BEGIN INSERT INTO `post_search`(`post_search`, rowid, `title`, `body`) VALUES ('delete', old.rowid, old.`title`, old.`body`); END",
+		"CREATE TRIGGER `post_search_update` AFTER UPDATE ON `posts` BEGIN INSERT INTO `post_search`(`post_search`, rowid, `title`, `body`) VALUES ('delete', old.rowid, old.`title`, old.`body`); INSERT INTO `post_search`(rowid, `title`, `body`) VALUES (new.rowid, new.`title`, new.`body`); END",
+		"INSERT INTO `post_search`(`post_search`) VALUES ('rebuild')",
+	}, stmts)
+}
@@ -0,0 +1,219 @@
+package schema
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"ariga.io/atlas/sql/migrate"
+	"ariga.io/atlas/sql/schema"
+
+	"github.com/syssam/velox/dialect"
+	entsql "github.com/syssam/velox/dialect/sql"
+)
+
+// viewDef returns the definition of the view in the given dialect,
+// or an empty string if the view is defined externally.
+func (t *Table) viewDef(d string) string {
+	if !t.View || t.Annotation == nil {
+		return ""
+	}
+	if def := t.Annotation.ViewFor[d]; def != "" {
+		return def
+	}
+	return t.Annotation.ViewAs
+}
+
+// materialized reports if the table is a materialized view (sqlschema.Materialized).
+func (t *Table) materialized() bool {
+	return t.View && t.Annotation != nil && t.Annotation.Materialized
+}
+
+// snapshot reports if the table is a materialized view that is stored as a
+// snapshot table in the given dialect, as only PostgreSQL has materialized views.
+func (t *Table) snapshot(d string) bool {
+	return t.materialized() && d != dialect.Postgres && t.viewDef(d) != ""
+}
+
+// RefreshView recomputes the rows of a materialized view. On PostgreSQL, it runs
+// REFRESH MATERIALIZED VIEW, and concurrently makes it not lock out the readers
+// of the view (it requires a unique index on the view). On MySQL and SQLite, the
+// rows of the snapshot table are replaced in a transaction, and concurrently has
+// no effect.
+func RefreshView(ctx context.Context, drv dialect.Driver, t *Table, concurrently bool) error {
+	d := drv.Dialect()
+	def := t.viewDef(d)
+	if !t.materialized() || def == "" {
+		return fmt.Errorf("sql/schema: %q is not a materialized view", t.Name)
+	}
+	b := entsql.Dialect(d)
+	if d == dialect.Postgres {
+		query := b.String(func(b *entsql.Builder) {
+			b.WriteString("REFRESH MATERIALIZED VIEW ")
+			if concurrently {
+				b.WriteString("CONCURRENTLY ")
+			}
+			tableIdent(b, t)
+		})
+		return drv.Exec(ctx, query, []any{}, nil)
+	}
+	tx, err := drv.Tx(ctx)
+	if err != nil {
+		return err
+	}
+	for _, query := range []string{
+		b.String(func(b *entsql.Builder) { b.WriteString("DELETE FROM "); tableIdent(b, t) }),
+		fillSnapshot(d, t),
+	} {
+		if err := tx.Exec(ctx, query, []any{}, nil); err != nil {
+			return errors.Join(fmt.Errorf("sql/schema: refresh %q: %w", t.Name, err), tx.Rollback())
+		}
+	}
+	return tx.Commit()
+}
+
+// inspectMaterialized returns the materialized views of the connected PostgreSQL
+// schema, and the names of their indexes. It returns nil for the other dialects,
+// or if there are no materialized views in the tables.
+func (a *Atlas) inspectMaterialized(ctx context.Context, conn dialect.ExecQuerier, tables []*Table) (map[string]map[string]bool, error) {
+	if a.sqlDialect.Dialect() != dialect.Postgres || !hasMaterialized(tables) {
+		return nil, nil
+	}
+	rows := &entsql.Rows{}
+	query := "SELECT m.matviewname, COALESCE(i.indexname, '') FROM pg_matviews m LEFT JOIN pg_indexes i ON i.schemaname = m.schemaname AND i.tablename = m.matviewname WHERE m.schemaname = CURRENT_SCHEMA()"
+	if err := conn.Query(ctx, query, []any{}, rows); err != nil {
+		return nil, fmt.Errorf("query materialized views: %w", err)
+	}
+	defer rows.Close()
+	views := make(map[string]map[string]bool)
+	for rows.Next() {
+		var name, index string
+		if err := rows.Scan(&name, &index); err != nil {
+			return nil, err
+		}
+		if views[name] == nil {
+			views[name] = make(map[string]bool)
+		}
+		if index != "" {
+			views[name][index] = true
+		}
+	}
+	return views, rows.Err()
+}
+
+// hasMaterialized reports if any of the tables is a materialized view.
+func hasMaterialized(tables []*Table) bool {
+	for _, t := range tables {
+		if t.materialized() {
+			return true
+		}
+	}
+	return false
+}
+
+// materializedChanges returns the changes that create the materialized views
+// that do not exist in the current schema, and their missing indexes. On
+// PostgreSQL, a view is created with CREATE MATERIALIZED VIEW:
+//
+//	CREATE MATERIALIZED VIEW "post_stats" ("author_id", "posts") AS SELECT ...
+//	CREATE UNIQUE INDEX "poststats_author_id" ON "post_stats" ("author_id")
+//
+// On MySQL and SQLite, the snapshot table is created (and altered) with the
+// other tables, and the changes fill the new snapshot tables:
+//
+//	INSERT INTO `post_stats` (`author_id`, `posts`) SELECT ...
+//
+// A changed definition is not migrated. The view should be dropped (or renamed)
+// to be created with its new definition.
+func (a *Atlas) materializedChanges(current *schema.Schema, views map[string]map[string]bool, tables []*Table) []*migrate.Change {
+	d := a.sqlDialect.Dialect()
+	var changes []*migrate.Change
+	for _, t := range tables {
+		if !t.materialized() || t.viewDef(d) == "" {
+			continue
+		}
+		if d != dialect.Postgres {
+			if _, ok := current.Table(t.Name); !ok {
+				changes = append(changes, &migrate.Change{
+					Cmd:     fillSnapshot(d, t),
+					Comment: fmt.Sprintf("fill the snapshot table of materialized view %q", t.Name),
+				})
+			}
+			continue
+		}
+		indexes, ok := views[t.Name]
+		if !ok {
+			changes = append(changes, &migrate.Change{
+				Cmd:     createMaterialized(t),
+				Comment: fmt.Sprintf("create materialized view %q", t.Name),
+			})
+		}
+		for _, idx := range t.Indexes {
+			if indexes[idx.Name] {
+				continue
+			}
+			changes = append(changes, &migrate.Change{
+				Cmd:     createViewIndex(t, idx),
+				Comment: fmt.Sprintf("create index %q on materialized view %q", idx.Name, t.Name),
+			})
+		}
+	}
+	return changes
+}
+
+// createMaterialized returns the PostgreSQL statement that creates the materialized view.
+func createMaterialized(t *Table) string {
+	return entsql.Dialect(dialect.Postgres).String(func(b *entsql.Builder) {
+		b.WriteString("CREATE MATERIALIZED VIEW ")
+		tableIdent(b, t)
+		b.Pad().Wrap(func(b *entsql.Builder) { b.IdentComma(viewColumns(t)...) })
+		b.WriteString(" AS ").WriteString(t.viewDef(dialect.Postgres))
+	})
+}
+
+// createViewIndex returns the PostgreSQL statement that creates an index of the materialized view.
+func createViewIndex(t *Table, idx *Index) string {
+	return entsql.Dialect(dialect.Postgres).String(func(b *entsql.Builder) {
+		b.WriteString("CREATE ")
+		if idx.Unique {
+			b.WriteString("UNIQUE ")
+		}
+		b.WriteString("INDEX ").Ident(idx.Name).WriteString(" ON ")
+		tableIdent(b, t)
+		columns := make([]string, len(idx.Columns))
+		for i, c := range idx.Columns {
+			columns[i] = c.Name
+		}
+		b.Pad().Wrap(func(b *entsql.Builder) { b.IdentComma(columns...) })
+		if ant := idx.Annotation; ant != nil && ant.Where != "" {
+			b.WriteString(" WHERE ").WriteString(ant.Where)
+		}
+	})
+}
+
+// fillSnapshot returns the statement that inserts the rows of the view query into its snapshot table.
+func fillSnapshot(d string, t *Table) string {
+	return entsql.Dialect(d).String(func(b *entsql.Builder) {
+		b.WriteString("INSERT INTO ")
+		tableIdent(b, t)
+		b.Pad().Wrap(func(b *entsql.Builder) { b.IdentComma(viewColumns(t)...) })
+		b.Pad().WriteString(t.viewDef(d))
+	})
+}
+
+// tableIdent writes the (schema-qualified) name of the table or view.
+func tableIdent(b *entsql.Builder, t *Table) {
+	if t.Schema != "" && b.Dialect() != dialect.SQLite {
+		b.Ident(t.Schema).Byte('.')
+	}
+	b.Ident(t.Name)
+}
+
+// viewColumns returns the column names of the view.
+func viewColumns(t *Table) []string {
+	columns := make([]string, len(t.Columns))
+	for i, c := range t.Columns {
+		columns[i] = c.Name
+	}
+	return columns
+}
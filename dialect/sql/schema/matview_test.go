@@ -0,0 +1,131 @@
+package schema
+
+import (
+	"context"
+	"testing"
+
+	"ariga.io/atlas/sql/schema"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/syssam/velox/dialect"
+	entsql "github.com/syssam/velox/dialect/sql"
+	"github.com/syssam/velox/dialect/sqlschema"
+	"github.com/syssam/velox/schema/field"
+)
+
+// postStatsTables returns a "posts" table, and a "post_stats" materialized view
+// with the number of posts per author.
+func postStatsTables() (posts, stats *Table) {
+	posts = NewTable("posts").
+		AddPrimary(&Column{Name: "id", Type: field.TypeInt, Increment: true}).
+		AddColumn(&Column{Name: "author_id", Type: field.TypeInt})
+	stats = NewView("post_stats").
+		AddColumn(&Column{Name: "author_id", Type: field.TypeInt}).
+		AddColumn(&Column{Name: "posts", Type: field.TypeInt}).
+		SetAnnotation(&sqlschema.Annotation{
+			ViewAs:       "SELECT author_id, COUNT(*) FROM posts GROUP BY author_id",
+			Materialized: true,
+		})
+	stats.AddIndex("poststats_author_id", true, []string{"author_id"})
+	return posts, stats
+}
+
+func TestAtlas_Realm_Materialized(t *testing.T) {
+	posts, stats := postStatsTables()
+	a := &Atlas{sqlDialect: &SQLite{Driver: nopDriver{dialect: dialect.SQLite}}, dialect: dialect.SQLite}
+	realm, err := a.realm([]*Table{posts, stats})
+	require.NoError(t, err)
+	require.Len(t, realm.Schemas[0].Tables, 2, "the view is a snapshot table")
+	st, ok := realm.Schemas[0].Table("post_stats")
+	require.True(t, ok)
+	_, ok = st.Index("poststats_author_id")
+	assert.True(t, ok)
+
+	a = &Atlas{sqlDialect: &Postgres{Driver: nopDriver{dialect: dialect.Postgres}}, dialect: dialect.Postgres}
+	realm, err = a.realm([]*Table{posts, stats})
+	require.NoError(t, err)
+	assert.Len(t, realm.Schemas[0].Tables, 1)
+	assert.Empty(t, realm.Schemas[0].Views, "materialized views are created by velox")
+}
+
+func TestAtlas_MaterializedChanges(t *testing.T) {
+	posts, stats := postStatsTables()
+	tables := []*Table{posts, stats}
+	a := &Atlas{sqlDialect: &Postgres{Driver: nopDriver{dialect: dialect.Postgres}}, dialect: dialect.Postgres}
+
+	changes := a.materializedChanges(schema.New("public"), map[string]map[string]bool{}, tables)
+	require.Len(t, changes, 2)
+	assert.Equal(t, `CREATE MATERIALIZED VIEW "post_stats" ("author_id", "posts") AS SELECT author_id, COUNT(*) FROM posts GROUP BY author_id`, changes[0].Cmd)
+	assert.Equal(t, `CREATE UNIQUE INDEX "poststats_author_id" ON "post_stats" ("author_id")`, changes[1].Cmd)
+
+	// Only the missing indexes of existing views are created.
+	changes = a.materializedChanges(schema.New("public"), map[string]map[string]bool{"post_stats": {}}, tables)
+	require.Len(t, changes, 1)
+	assert.Contains(t, changes[0].Cmd, "CREATE UNIQUE INDEX")
+	changes = a.materializedChanges(schema.New("public"), map[string]map[string]bool{"post_stats": {"poststats_author_id": true}}, tables)
+	assert.Empty(t, changes)
+
+	// Snapshot tables are filled when they are created.
+	a = &Atlas{sqlDialect: &MySQL{Driver: nopDriver{dialect: dialect.MySQL}}, dialect: dialect.MySQL}
+	changes = a.materializedChanges(schema.New("test"), nil, tables)
+	require.Len(t, changes, 1)
+	assert.Equal(t, "INSERT INTO `post_stats` (`author_id`, `posts`) SELECT author_id, COUNT(*) FROM posts GROUP BY author_id", changes[0].Cmd)
+	changes = a.materializedChanges(schema.New("test").AddTables(schema.NewTable("post_stats")), nil, tables)
+	assert.Empty(t, changes)
+}
+
+func TestDDL_Materialized(t *testing.T) {
+	posts, stats := postStatsTables()
+	ddl, err := DDL(context.Background(), DDLArgs{Dialect: dialect.Postgres, Tables: []*Table{posts, stats}})
+	require.NoError(t, err)
+	assert.Contains(t, ddl, `CREATE TABLE "posts"`)
+	assert.NotContains(t, ddl, `CREATE TABLE "post_stats"`)
+	assert.Contains(t, ddl, `CREATE MATERIALIZED VIEW "post_stats" ("author_id", "posts") AS SELECT`)
+	assert.Contains(t, ddl, `CREATE UNIQUE INDEX "poststats_author_id" ON "post_stats" ("author_id")`)
+}
+
+func TestSQLiteIntegration_Materialized(t *testing.T) {
+	ctx := context.Background()
+	db, drv := openSQLite(t)
+	posts, stats := postStatsTables()
+	_, err := db.Exec("CREATE TABLE posts (id INTEGER PRIMARY KEY AUTOINCREMENT, author_id INTEGER NOT NULL)")
+	require.NoError(t, err)
+	_, err = db.Exec("INSERT INTO posts (author_id) VALUES (1), (1), (2)")
+	require.NoError(t, err)
+
+	m := newSQLiteMigrate(t, drv)
+	require.NoError(t, m.Create(ctx, posts, stats))
+	count := func(author int) (n int) {
+		require.NoError(t, db.QueryRow("SELECT posts FROM post_stats WHERE author_id = ?", author).Scan(&n))
+		return n
+	}
+	assert.Equal(t, 2, count(1), "the snapshot table is filled on creation")
+
+	_, err = db.Exec("INSERT INTO posts (author_id) VALUES (1)")
+	require.NoError(t, err)
+	assert.Equal(t, 2, count(1))
+	require.NoError(t, RefreshView(ctx, drv, stats, false))
+	assert.Equal(t, 3, count(1))
+	assert.Equal(t, 1, count(2))
+
+	// The snapshot table is not filled again.
+	require.NoError(t, m.Create(ctx, posts, stats))
+	var n int
+	require.NoError(t, db.QueryRow("SELECT COUNT(*) FROM post_stats").Scan(&n))
+	assert.Equal(t, 2, n)
+	_, err = db.Exec("INSERT INTO post_stats (author_id, posts) VALUES (1, 1)")
+	assert.Error(t, err, "the unique index of the view is created")
+
+	assert.Error(t, RefreshView(ctx, drv, posts, false))
+}
+
+func TestRefreshView_Postgres(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	_, stats := postStatsTables()
+	mock.ExpectExec(`REFRESH MATERIALIZED VIEW CONCURRENTLY "post_stats"`).WillReturnResult(sqlmock.NewResult(0, 0))
+	require.NoError(t, RefreshView(context.Background(), entsql.OpenDB(dialect.Postgres, db), stats, true))
+	require.NoError(t, mock.ExpectationsWereMet())
+}
@@ -85,7 +85,8 @@ func (d *MySQL) atTable(t1 *Table, t2 *schema.Table) {
 	if collate := t1.Annotation.Collation; collate != "" {
 		t2.SetCollation(collate)
 	}
-	if opts := t1.Annotation.Options; opts != "" {
+	// The partition options follow the other table options.
+	if opts := strings.TrimSpace(t1.Annotation.Options + " " + mysqlPartition(t1)); opts != "" {
 		t2.AddAttrs(&mysql.CreateOptions{
 			V: opts,
 		})
@@ -270,6 +271,8 @@ func (d *MySQL) atIndex(idx1 *Index, t2 *schema.Table, idx2 *schema.Index) error
 	}
 	if t, ok := indexType(idx1, dialect.MySQL); ok {
 		idx2.AddAttrs(&mysql.IndexType{T: t})
+	} else if idx1.fullText() {
+		idx2.AddAttrs(&mysql.IndexType{T: mysql.IndexTypeFullText})
 	}
 	return nil
 }
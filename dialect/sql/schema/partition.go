@@ -0,0 +1,341 @@
+package schema
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"ariga.io/atlas/sql/postgres"
+	"ariga.io/atlas/sql/schema"
+
+	"github.com/syssam/velox/dialect"
+	entsql "github.com/syssam/velox/dialect/sql"
+	"github.com/syssam/velox/dialect/sqlschema"
+	"github.com/syssam/velox/schema/field"
+)
+
+// maxPartition is the name of the MySQL catch-all partition of RANGE tables.
+const maxPartition = "pmax"
+
+// partition returns the partition key of the table (sqlschema.PartitionBy), or nil.
+func (t *Table) partition() *sqlschema.Partition {
+	if t.View || t.Annotation == nil {
+		return nil
+	}
+	return t.Annotation.Partition
+}
+
+// checkPartition validates the partition key of the table in the dialect.
+// SQLite tables are not partitioned.
+func (a *Atlas) checkPartition(t *Table) error {
+	p := t.partition()
+	if p == nil || a.dialect == dialect.SQLite {
+		return nil
+	}
+	switch p.Type {
+	case sqlschema.Range, sqlschema.Hash:
+	case sqlschema.List:
+		if a.dialect == dialect.MySQL {
+			return fmt.Errorf("table %q: LIST partitioning is not supported by MySQL", t.Name)
+		}
+	default:
+		return fmt.Errorf("table %q: unknown partition type %q", t.Name, p.Type)
+	}
+	if len(p.Columns) == 0 {
+		return fmt.Errorf("table %q: missing columns for partition key", t.Name)
+	}
+	for _, c := range p.Columns {
+		if !t.HasColumn(c) {
+			return fmt.Errorf("table %q: unknown partition key column %q", t.Name, c)
+		}
+	}
+	return nil
+}
+
+// skipPartitionFK reports if the foreign key of table t is left out of the
+// MySQL schema. InnoDB does not support foreign keys on partitioned tables,
+// neither held by them nor referencing them (error 1506).
+func (a *Atlas) skipPartitionFK(t *Table, fk *ForeignKey) bool {
+	return a.dialect == dialect.MySQL && (t.partition() != nil || fk.RefTable.partition() != nil)
+}
+
+// partitionPK extends the primary key of a partitioned table with the columns
+// of the partition key, as PostgreSQL and MySQL require it.
+func (a *Atlas) partitionPK(et *Table, at *schema.Table, pk []*schema.Column) []*schema.Column {
+	p := et.partition()
+	if p == nil || a.dialect == dialect.SQLite || len(pk) == 0 {
+		return pk
+	}
+	for _, name := range p.Columns {
+		if c, ok := at.Column(name); ok && !slices.Contains(pk, c) {
+			pk = append(pk, c)
+		}
+	}
+	return pk
+}
+
+// pgPartition returns the Atlas attribute of the PostgreSQL partition key.
+func pgPartition(p *sqlschema.Partition) *postgres.Partition {
+	parts := make([]*postgres.PartitionPart, len(p.Columns))
+	for i, c := range p.Columns {
+		parts[i] = &postgres.PartitionPart{C: schema.NewColumn(c)}
+	}
+	return &postgres.Partition{T: string(p.Type), Parts: parts}
+}
+
+// mysqlPartition returns the MySQL partition options of the table. RANGE tables
+// are created with a catch-all partition that CreatePartitions splits, and
+// TIMESTAMP columns are partitioned by their UNIX_TIMESTAMP, as RANGE COLUMNS
+// does not support them.
+func mysqlPartition(t *Table) string {
+	p := t.partition()
+	if p == nil {
+		return ""
+	}
+	return entsql.Dialect(dialect.MySQL).String(func(b *entsql.Builder) {
+		b.WriteString("PARTITION BY ")
+		switch {
+		case p.Type == sqlschema.Hash:
+			b.WriteString("KEY ").Wrap(func(b *entsql.Builder) { b.IdentComma(p.Columns...) })
+		case mysqlUnixRange(t):
+			b.WriteString("RANGE ").Wrap(func(b *entsql.Builder) {
+				b.WriteString("UNIX_TIMESTAMP").Wrap(func(b *entsql.Builder) { b.Ident(p.Columns[0]) })
+			})
+			b.WriteString(" (PARTITION ").Ident(maxPartition).WriteString(" VALUES LESS THAN MAXVALUE)")
+		default:
+			b.WriteString("RANGE COLUMNS").Wrap(func(b *entsql.Builder) { b.IdentComma(p.Columns...) })
+			b.WriteString(" (PARTITION ").Ident(maxPartition).WriteString(" VALUES LESS THAN ")
+			b.Wrap(func(b *entsql.Builder) {
+				b.WriteString(strings.Repeat("MAXVALUE, ", len(p.Columns)-1) + "MAXVALUE")
+			})
+			b.WriteString(")")
+		}
+	})
+}
+
+// mysqlUnixRange reports if the table is partitioned by a range of one TIMESTAMP column in MySQL.
+func mysqlUnixRange(t *Table) bool {
+	p := t.partition()
+	if p == nil || p.Type != sqlschema.Range || len(p.Columns) != 1 {
+		return false
+	}
+	c, ok := t.column(p.Columns[0])
+	if !ok {
+		return false
+	}
+	if st := strings.ToLower(c.SchemaType[dialect.MySQL]); st != "" {
+		return strings.HasPrefix(st, "timestamp")
+	}
+	return c.Type == field.TypeTime
+}
+
+// PartitionPeriod is the time range of the partitions returned by TimePartitions.
+type PartitionPeriod int
+
+// Partition periods.
+const (
+	Daily PartitionPeriod = iota + 1
+	Monthly
+	Yearly
+)
+
+// RangePartition is a partition of a table partitioned by a time range
+// (sqlschema.Range), holding the rows with a partition key in [From, To).
+type RangePartition struct {
+	Name     string
+	From, To time.Time
+}
+
+// TimePartitions returns n consecutive partitions of the table, starting with
+// the period that contains start, in the location of start. The partitions are
+// named after the table and the period, for example, events_20261018 (Daily),
+// events_202610 (Monthly) or events_2026 (Yearly).
+//
+//	parts := schema.TimePartitions(migrate.EventsTable, time.Now(), schema.Monthly, 3)
+//	if err := schema.CreatePartitions(ctx, drv, migrate.EventsTable, parts...); err != nil {
+//		return err
+//	}
+func TimePartitions(t *Table, start time.Time, period PartitionPeriod, n int) []RangePartition {
+	y, m, d := start.Date()
+	var (
+		from   time.Time
+		next   func(time.Time) time.Time
+		layout string
+	)
+	switch period {
+	case Daily:
+		from, layout = time.Date(y, m, d, 0, 0, 0, 0, start.Location()), "20060102"
+		next = func(t time.Time) time.Time { return t.AddDate(0, 0, 1) }
+	case Yearly:
+		from, layout = time.Date(y, 1, 1, 0, 0, 0, 0, start.Location()), "2006"
+		next = func(t time.Time) time.Time { return t.AddDate(1, 0, 0) }
+	default:
+		from, layout = time.Date(y, m, 1, 0, 0, 0, 0, start.Location()), "200601"
+		next = func(t time.Time) time.Time { return t.AddDate(0, 1, 0) }
+	}
+	parts := make([]RangePartition, 0, n)
+	for range n {
+		to := next(from)
+		parts = append(parts, RangePartition{Name: t.Name + "_" + from.Format(layout), From: from, To: to})
+		from = to
+	}
+	return parts
+}
+
+// CreatePartitions creates the partitions of a table that is partitioned by a
+// time range, and skips the ones that exist. On PostgreSQL, each partition is
+// a table (PARTITION OF). On MySQL, the partitions are split from the catch-all
+// partition of the table, and must follow the existing partitions.
+func CreatePartitions(ctx context.Context, drv dialect.Driver, t *Table, parts ...RangePartition) error {
+	if p := t.partition(); p == nil || p.Type != sqlschema.Range || len(p.Columns) != 1 {
+		return fmt.Errorf("sql/schema: %q is not partitioned by a range of one column", t.Name)
+	}
+	if len(parts) == 0 {
+		return nil
+	}
+	switch d := drv.Dialect(); d {
+	case dialect.Postgres:
+		tx, err := drv.Tx(ctx)
+		if err != nil {
+			return err
+		}
+		for _, part := range parts {
+			query := entsql.Dialect(d).String(func(b *entsql.Builder) {
+				b.WriteString("CREATE TABLE IF NOT EXISTS ")
+				tableIdent(b, &Table{Name: part.Name, Schema: t.Schema})
+				b.WriteString(" PARTITION OF ")
+				tableIdent(b, t)
+				b.WriteString(" FOR VALUES FROM ('" + pgTime(part.From) + "') TO ('" + pgTime(part.To) + "')")
+			})
+			if err := tx.Exec(ctx, query, []any{}, nil); err != nil {
+				return errors.Join(fmt.Errorf("sql/schema: create partition %q: %w", part.Name, err), tx.Rollback())
+			}
+		}
+		return tx.Commit()
+	case dialect.MySQL:
+		exist, err := mysqlPartitions(ctx, drv, t)
+		if err != nil {
+			return err
+		}
+		parts = slices.DeleteFunc(slices.Clone(parts), func(p RangePartition) bool { return exist[p.Name] })
+		if len(parts) == 0 {
+			return nil
+		}
+		unix := mysqlUnixRange(t)
+		query := entsql.Dialect(d).String(func(b *entsql.Builder) {
+			b.WriteString("ALTER TABLE ")
+			tableIdent(b, t)
+			b.WriteString(" REORGANIZE PARTITION ").Ident(maxPartition).WriteString(" INTO (")
+			for _, part := range parts {
+				b.WriteString("PARTITION ").Ident(part.Name).WriteString(" VALUES LESS THAN ")
+				if unix {
+					b.WriteString(fmt.Sprintf("(%d)", part.To.Unix()))
+				} else {
+					b.WriteString("('" + part.To.Format(time.DateTime) + "')")
+				}
+				b.WriteString(", ")
+			}
+			b.WriteString("PARTITION ").Ident(maxPartition).WriteString(" VALUES LESS THAN ")
+			if unix {
+				b.WriteString("MAXVALUE)")
+			} else {
+				b.WriteString("(MAXVALUE))")
+			}
+		})
+		return drv.Exec(ctx, query, []any{}, nil)
+	default:
+		return fmt.Errorf("sql/schema: partitions are not supported by %s", d)
+	}
+}
+
+// DetachPartition detaches a partition from its table, and keeps its rows in
+// a standalone table with the name of the partition, to be archived or dropped.
+// On MySQL, the rows of the partition are exchanged with a new table, and the
+// partition is dropped.
+func DetachPartition(ctx context.Context, drv dialect.Driver, t *Table, name string) error {
+	if t.partition() == nil {
+		return fmt.Errorf("sql/schema: %q is not partitioned", t.Name)
+	}
+	var (
+		d     = drv.Dialect()
+		part  = &Table{Name: name, Schema: t.Schema}
+		stmts []func(*entsql.Builder)
+	)
+	switch d {
+	case dialect.Postgres:
+		stmts = append(stmts, func(b *entsql.Builder) {
+			b.WriteString("ALTER TABLE ")
+			tableIdent(b, t)
+			b.WriteString(" DETACH PARTITION ")
+			tableIdent(b, part)
+		})
+	case dialect.MySQL:
+		stmts = append(stmts,
+			func(b *entsql.Builder) {
+				b.WriteString("CREATE TABLE ")
+				tableIdent(b, part)
+				b.WriteString(" LIKE ")
+				tableIdent(b, t)
+			},
+			func(b *entsql.Builder) {
+				b.WriteString("ALTER TABLE ")
+				tableIdent(b, part)
+				b.WriteString(" REMOVE PARTITIONING")
+			},
+			func(b *entsql.Builder) {
+				b.WriteString("ALTER TABLE ")
+				tableIdent(b, t)
+				b.WriteString(" EXCHANGE PARTITION ").Ident(name).WriteString(" WITH TABLE ")
+				tableIdent(b, part)
+			},
+			func(b *entsql.Builder) {
+				b.WriteString("ALTER TABLE ")
+				tableIdent(b, t)
+				b.WriteString(" DROP PARTITION ").Ident(name)
+			},
+		)
+	default:
+		return fmt.Errorf("sql/schema: partitions are not supported by %s", d)
+	}
+	for _, stmt := range stmts {
+		if err := drv.Exec(ctx, entsql.Dialect(d).String(stmt), []any{}, nil); err != nil {
+			return fmt.Errorf("sql/schema: detach partition %q: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// mysqlPartitions returns the names of the partitions of the MySQL table.
+func mysqlPartitions(ctx context.Context, drv dialect.Driver, t *Table) (map[string]bool, error) {
+	query, args := entsql.Dialect(dialect.MySQL).
+		Select("PARTITION_NAME").
+		From(entsql.Table("PARTITIONS").Schema("INFORMATION_SCHEMA")).
+		Where(entsql.And(
+			(&MySQL{schema: t.Schema}).matchSchema(),
+			entsql.EQ("TABLE_NAME", t.Name),
+			entsql.NotNull("PARTITION_NAME"),
+		)).
+		Query()
+	rows := &entsql.Rows{}
+	if err := drv.Query(ctx, query, args, rows); err != nil {
+		return nil, fmt.Errorf("query partitions: %w", err)
+	}
+	defer rows.Close()
+	names := make(map[string]bool)
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, err
+		}
+		names[name] = true
+	}
+	return names, rows.Err()
+}
+
+// pgTime formats a partition bound of a PostgreSQL timestamp (or date) column.
+func pgTime(t time.Time) string {
+	return t.Format("2006-01-02 15:04:05-07:00")
+}
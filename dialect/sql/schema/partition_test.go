@@ -0,0 +1,193 @@
+package schema
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/syssam/velox/dialect"
+	entsql "github.com/syssam/velox/dialect/sql"
+	"github.com/syssam/velox/dialect/sqlschema"
+	"github.com/syssam/velox/schema/field"
+)
+
+// eventsTable returns an "events" table that is partitioned by the given key.
+func eventsTable(p *sqlschema.Partition) *Table {
+	return NewTable("events").
+		AddPrimary(&Column{Name: "id", Type: field.TypeInt, Increment: true}).
+		AddColumn(&Column{Name: "tenant_id", Type: field.TypeInt}).
+		AddColumn(&Column{Name: "created_at", Type: field.TypeTime}).
+		SetAnnotation(&sqlschema.Annotation{Partition: p})
+}
+
+func TestDDL_Partition(t *testing.T) {
+	ctx := context.Background()
+	events := eventsTable(&sqlschema.Partition{Type: sqlschema.Range, Columns: []string{"created_at"}})
+	ddl, err := DDL(ctx, DDLArgs{Dialect: dialect.Postgres, Tables: []*Table{events}})
+	require.NoError(t, err)
+	assert.Contains(t, ddl, `PRIMARY KEY ("id", "created_at")`)
+	assert.Contains(t, ddl, `) PARTITION BY RANGE ("created_at");`)
+
+	ddl, err = DDL(ctx, DDLArgs{Dialect: dialect.MySQL, Version: "8.0.19", Tables: []*Table{events}})
+	require.NoError(t, err)
+	assert.Contains(t, ddl, "PRIMARY KEY (`id`, `created_at`)")
+	assert.Contains(t, ddl, "PARTITION BY RANGE (UNIX_TIMESTAMP(`created_at`)) (PARTITION `pmax` VALUES LESS THAN MAXVALUE);")
+
+	events = eventsTable(&sqlschema.Partition{Type: sqlschema.Hash, Columns: []string{"tenant_id"}})
+	ddl, err = DDL(ctx, DDLArgs{Dialect: dialect.MySQL, Version: "8.0.19", Tables: []*Table{events}})
+	require.NoError(t, err)
+	assert.Contains(t, ddl, "PRIMARY KEY (`id`, `tenant_id`)")
+	assert.Contains(t, ddl, "PARTITION BY KEY (`tenant_id`);")
+
+	// SQLite tables are not partitioned.
+	ddl, err = DDL(ctx, DDLArgs{Dialect: dialect.SQLite, Tables: []*Table{events}})
+	require.NoError(t, err)
+	assert.NotContains(t, ddl, "PARTITION")
+	assert.NotContains(t, ddl, "tenant_id`)")
+}
+
+func TestDDL_PartitionForeignKeys(t *testing.T) {
+	ctx := context.Background()
+	users := NewTable("users").
+		AddPrimary(&Column{Name: "id", Type: field.TypeInt, Increment: true})
+	events := eventsTable(&sqlschema.Partition{Type: sqlschema.Range, Columns: []string{"created_at"}})
+	// The M2O edge from events to users.
+	events.AddColumn(&Column{Name: "user_id", Type: field.TypeInt, Nullable: true})
+	c, _ := events.Column("user_id")
+	events.AddForeignKey(&ForeignKey{
+		Symbol:     "events_users_events",
+		Columns:    []*Column{c},
+		RefTable:   users,
+		RefColumns: []*Column{users.PrimaryKey[0]},
+		OnDelete:   SetNull,
+	})
+	ddl, err := DDL(ctx, DDLArgs{Dialect: dialect.MySQL, Version: "8.0.19", Tables: []*Table{users, events}})
+	require.NoError(t, err)
+	assert.Contains(t, ddl, "PARTITION BY RANGE")
+	assert.NotContains(t, ddl, "FOREIGN KEY")
+
+	ddl, err = DDL(ctx, DDLArgs{Dialect: dialect.Postgres, Tables: []*Table{users, events}})
+	require.NoError(t, err)
+	assert.Contains(t, ddl, `CONSTRAINT "events_users_events" FOREIGN KEY ("user_id") REFERENCES "users" ("id")`)
+}
+
+func TestMySQLPartition(t *testing.T) {
+	events := eventsTable(&sqlschema.Partition{Type: sqlschema.Range, Columns: []string{"tenant_id", "created_at"}})
+	assert.Equal(t, "PARTITION BY RANGE COLUMNS(`tenant_id`, `created_at`) (PARTITION `pmax` VALUES LESS THAN (MAXVALUE, MAXVALUE))", mysqlPartition(events))
+
+	events = eventsTable(&sqlschema.Partition{Type: sqlschema.Range, Columns: []string{"created_at"}})
+	c, _ := events.Column("created_at")
+	c.SchemaType = map[string]string{dialect.MySQL: "datetime"}
+	assert.Equal(t, "PARTITION BY RANGE COLUMNS(`created_at`) (PARTITION `pmax` VALUES LESS THAN (MAXVALUE))", mysqlPartition(events))
+	assert.Empty(t, mysqlPartition(NewTable("users")))
+}
+
+func TestAtlas_CheckPartition(t *testing.T) {
+	tests := []struct {
+		dialect string
+		p       *sqlschema.Partition
+		wantErr string
+	}{
+		{dialect: dialect.Postgres, p: &sqlschema.Partition{Type: sqlschema.List, Columns: []string{"tenant_id"}}},
+		{dialect: dialect.MySQL, p: &sqlschema.Partition{Type: sqlschema.List, Columns: []string{"tenant_id"}}, wantErr: "LIST partitioning is not supported by MySQL"},
+		{dialect: dialect.Postgres, p: &sqlschema.Partition{Type: "INTERVAL", Columns: []string{"tenant_id"}}, wantErr: `unknown partition type "INTERVAL"`},
+		{dialect: dialect.Postgres, p: &sqlschema.Partition{Type: sqlschema.Hash}, wantErr: "missing columns"},
+		{dialect: dialect.MySQL, p: &sqlschema.Partition{Type: sqlschema.Hash, Columns: []string{"region"}}, wantErr: `unknown partition key column "region"`},
+		{dialect: dialect.SQLite, p: &sqlschema.Partition{Type: sqlschema.List, Columns: []string{"region"}}},
+	}
+	for _, tt := range tests {
+		err := (&Atlas{dialect: tt.dialect}).checkPartition(eventsTable(tt.p))
+		if tt.wantErr == "" {
+			assert.NoError(t, err)
+		} else {
+			assert.ErrorContains(t, err, tt.wantErr)
+		}
+	}
+}
+
+func TestTimePartitions(t *testing.T) {
+	events := eventsTable(&sqlschema.Partition{Type: sqlschema.Range, Columns: []string{"created_at"}})
+	start := time.Date(2026, time.December, 18, 15, 4, 5, 0, time.UTC)
+
+	parts := TimePartitions(events, start, Monthly, 2)
+	require.Len(t, parts, 2)
+	assert.Equal(t, RangePartition{Name: "events_202612", From: time.Date(2026, 12, 1, 0, 0, 0, 0, time.UTC), To: time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC)}, parts[0])
+	assert.Equal(t, "events_202701", parts[1].Name)
+	assert.Equal(t, parts[0].To, parts[1].From)
+
+	parts = TimePartitions(events, start, Daily, 14)
+	assert.Equal(t, "events_20261218", parts[0].Name)
+	assert.Equal(t, "events_20261231", parts[13].Name)
+
+	parts = TimePartitions(events, start, Yearly, 1)
+	assert.Equal(t, "events_2026", parts[0].Name)
+	assert.Equal(t, time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC), parts[0].To)
+}
+
+func TestCreatePartitions(t *testing.T) {
+	ctx := context.Background()
+	events := eventsTable(&sqlschema.Partition{Type: sqlschema.Range, Columns: []string{"created_at"}})
+	parts := TimePartitions(events, time.Date(2026, time.October, 1, 0, 0, 0, 0, time.UTC), Monthly, 2)
+
+	t.Run("Postgres", func(t *testing.T) {
+		db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		require.NoError(t, err)
+		mock.ExpectBegin()
+		mock.ExpectExec(`CREATE TABLE IF NOT EXISTS "events_202610" PARTITION OF "events" FOR VALUES FROM ('2026-10-01 00:00:00+00:00') TO ('2026-11-01 00:00:00+00:00')`).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(`CREATE TABLE IF NOT EXISTS "events_202611" PARTITION OF "events" FOR VALUES FROM ('2026-11-01 00:00:00+00:00') TO ('2026-12-01 00:00:00+00:00')`).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectCommit()
+		require.NoError(t, CreatePartitions(ctx, entsql.OpenDB(dialect.Postgres, db), events, parts...))
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("MySQL", func(t *testing.T) {
+		db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		require.NoError(t, err)
+		mock.ExpectQuery("SELECT `PARTITION_NAME` FROM `INFORMATION_SCHEMA`.`PARTITIONS` WHERE `TABLE_SCHEMA` = (SELECT DATABASE()) AND `TABLE_NAME` = ? AND `PARTITION_NAME` IS NOT NULL").
+			WithArgs("events").
+			WillReturnRows(sqlmock.NewRows([]string{"PARTITION_NAME"}).AddRow("events_202610").AddRow("pmax"))
+		mock.ExpectExec("ALTER TABLE `events` REORGANIZE PARTITION `pmax` INTO (PARTITION `events_202611` VALUES LESS THAN (1796083200), PARTITION `pmax` VALUES LESS THAN MAXVALUE)").
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		require.NoError(t, CreatePartitions(ctx, entsql.OpenDB(dialect.MySQL, db), events, parts...))
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Errors", func(t *testing.T) {
+		drv := entsql.OpenDB(dialect.SQLite, nil)
+		assert.ErrorContains(t, CreatePartitions(ctx, drv, events, parts...), "not supported by sqlite")
+		hash := eventsTable(&sqlschema.Partition{Type: sqlschema.Hash, Columns: []string{"tenant_id"}})
+		assert.ErrorContains(t, CreatePartitions(ctx, drv, hash, parts...), "is not partitioned by a range of one column")
+	})
+}
+
+func TestDetachPartition(t *testing.T) {
+	ctx := context.Background()
+	events := eventsTable(&sqlschema.Partition{Type: sqlschema.Range, Columns: []string{"created_at"}})
+
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	require.NoError(t, err)
+	mock.ExpectExec(`ALTER TABLE "events" DETACH PARTITION "events_202601"`).WillReturnResult(sqlmock.NewResult(0, 0))
+	require.NoError(t, DetachPartition(ctx, entsql.OpenDB(dialect.Postgres, db), events, "events_202601"))
+	require.NoError(t, mock.ExpectationsWereMet())
+
+	db, mock, err = sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	require.NoError(t, err)
+	for _, query := range []string{
+		"CREATE TABLE `events_202601` LIKE `events`",
+		"ALTER TABLE `events_202601` REMOVE PARTITIONING",
+		"ALTER TABLE `events` EXCHANGE PARTITION `events_202601` WITH TABLE `events_202601`",
+		"ALTER TABLE `events` DROP PARTITION `events_202601`",
+	} {
+		mock.ExpectExec(query).WillReturnResult(sqlmock.NewResult(0, 0))
+	}
+	require.NoError(t, DetachPartition(ctx, entsql.OpenDB(dialect.MySQL, db), events, "events_202601"))
+	require.NoError(t, mock.ExpectationsWereMet())
+
+	assert.ErrorContains(t, DetachPartition(ctx, entsql.OpenDB(dialect.Postgres, nil), NewTable("users"), "users_1"), "is not partitioned")
+}
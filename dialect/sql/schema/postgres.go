@@ -89,6 +89,9 @@ func (d *Postgres) atTable(t1 *Table, t2 *schema.Table) {
 	if t1.Annotation != nil {
 		setAtChecks(t1, t2)
 	}
+	if p := t1.partition(); p != nil {
+		t2.AddAttrs(pgPartition(p))
+	}
 }
 
 func (d *Postgres) supportsDefault(*Column) bool {
@@ -210,6 +213,9 @@ func indexOpClass(idx *Index) map[string]string {
 }
 
 func (d *Postgres) atIndex(idx1 *Index, t2 *schema.Table, idx2 *schema.Index) error {
+	if idx1.fullText() {
+		return d.atFullTextIndex(idx1, t2, idx2)
+	}
 	opc := indexOpClass(idx1)
 	for _, c1 := range idx1.Columns {
 		c2, ok := t2.Column(c1.Name)
@@ -246,6 +252,24 @@ func (d *Postgres) atIndex(idx1 *Index, t2 *schema.Table, idx2 *schema.Index) er
 	return nil
 }
 
+// atFullTextIndex converts a full-text index to a GIN index on the tsvector
+// of its columns, the same expression that is matched by the Search predicates.
+func (d *Postgres) atFullTextIndex(idx1 *Index, t2 *schema.Table, idx2 *schema.Index) error {
+	columns := make([]string, len(idx1.Columns))
+	for i, c1 := range idx1.Columns {
+		if _, ok := t2.Column(c1.Name); !ok {
+			return fmt.Errorf("unexpected index %q column: %q", idx1.Name, c1.Name)
+		}
+		columns[i] = sql.Dialect(dialect.Postgres).String(func(b *sql.Builder) { b.Ident(c1.Name) })
+	}
+	idx2.AddParts(&schema.IndexPart{X: &schema.RawExpr{X: sql.TSVector(idx1.Annotation.FullTextConfig, columns...)}})
+	idx2.AddAttrs(&postgres.IndexType{T: "GIN"})
+	if idx1.Annotation.Where != "" {
+		idx2.AddAttrs(&postgres.IndexPredicate{P: idx1.Annotation.Where})
+	}
+	return nil
+}
+
 func (*Postgres) atTypeRangeSQL(table string, ts ...string) string {
 	for i := range ts {
 		ts[i] = fmt.Sprintf("('%s')", ts[i])
@@ -0,0 +1,135 @@
+package schema
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"ariga.io/atlas/sql/migrate"
+
+	"github.com/syssam/velox/dialect"
+	entsql "github.com/syssam/velox/dialect/sql"
+	"github.com/syssam/velox/dialect/sqlschema"
+	"github.com/syssam/velox/schema/field"
+)
+
+// rowSecurity returns the row-level security policy of the table (sqlschema.TenantPolicy),
+// with its default setting, or nil if the table has no policy.
+func (t *Table) rowSecurity() *sqlschema.RowSecurity {
+	if t.View || t.Annotation == nil || t.Annotation.RowSecurity == nil {
+		return nil
+	}
+	rs := *t.Annotation.RowSecurity
+	if rs.Setting == "" {
+		rs.Setting = sqlschema.TenantSetting
+	}
+	return &rs
+}
+
+// policyName returns the name of the row-level security policy of the table.
+func policyName(t *Table) string {
+	return t.Name + "_tenant_isolation"
+}
+
+// inspectPolicies returns the row-level security policies of the connected
+// PostgreSQL schema, by table name. It returns nil for the other dialects,
+// or if none of the tables has a policy.
+func (a *Atlas) inspectPolicies(ctx context.Context, conn dialect.ExecQuerier, tables []*Table) (map[string]map[string]bool, error) {
+	if a.sqlDialect.Dialect() != dialect.Postgres || !hasRowSecurity(tables) {
+		return nil, nil
+	}
+	rows := &entsql.Rows{}
+	query := "SELECT tablename, policyname FROM pg_policies WHERE schemaname = CURRENT_SCHEMA()"
+	if err := conn.Query(ctx, query, []any{}, rows); err != nil {
+		return nil, fmt.Errorf("query row-level security policies: %w", err)
+	}
+	defer rows.Close()
+	policies := make(map[string]map[string]bool)
+	for rows.Next() {
+		var table, name string
+		if err := rows.Scan(&table, &name); err != nil {
+			return nil, err
+		}
+		if policies[table] == nil {
+			policies[table] = make(map[string]bool)
+		}
+		policies[table][name] = true
+	}
+	return policies, rows.Err()
+}
+
+// hasRowSecurity reports if any of the tables has a row-level security policy.
+func hasRowSecurity(tables []*Table) bool {
+	for _, t := range tables {
+		if t.rowSecurity() != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// rowSecurityChanges returns the changes that enable the row-level security of
+// the PostgreSQL tables whose policy does not exist in the current schema:
+//
+//	ALTER TABLE "users" ENABLE ROW LEVEL SECURITY
+//	ALTER TABLE "users" FORCE ROW LEVEL SECURITY
+//	CREATE POLICY "users_tenant_isolation" ON "users" USING ("tenant_id" = current_setting('app.tenant_id', true))
+//
+// A changed policy is not migrated. The policy should be dropped to be created
+// with its new definition.
+func (a *Atlas) rowSecurityChanges(policies map[string]map[string]bool, tables []*Table) []*migrate.Change {
+	if a.sqlDialect.Dialect() != dialect.Postgres {
+		return nil
+	}
+	var changes []*migrate.Change
+	for _, t := range tables {
+		rs := t.rowSecurity()
+		if rs == nil || policies[t.Name][policyName(t)] {
+			continue
+		}
+		alter := func(action string) string {
+			return entsql.Dialect(dialect.Postgres).String(func(b *entsql.Builder) {
+				b.WriteString("ALTER TABLE ")
+				tableIdent(b, t)
+				b.WriteString(" " + action + " ROW LEVEL SECURITY")
+			})
+		}
+		changes = append(changes, &migrate.Change{
+			Cmd:     alter("ENABLE"),
+			Comment: fmt.Sprintf("enable row-level security on table %q", t.Name),
+		})
+		if rs.Forced() {
+			changes = append(changes, &migrate.Change{
+				Cmd:     alter("FORCE"),
+				Comment: fmt.Sprintf("apply row-level security to the owner of table %q", t.Name),
+			})
+		}
+		changes = append(changes, &migrate.Change{
+			Cmd:     createPolicy(t, rs),
+			Comment: fmt.Sprintf("create tenant isolation policy on table %q", t.Name),
+		})
+	}
+	return changes
+}
+
+// createPolicy returns the PostgreSQL statement that creates the tenant isolation
+// policy of the table. The setting is cast to the type of non-string columns, and
+// an unset (or reset) setting matches no rows.
+func createPolicy(t *Table, rs *sqlschema.RowSecurity) string {
+	setting := fmt.Sprintf("current_setting('%s', true)", strings.ReplaceAll(rs.Setting, "'", "''"))
+	if c, ok := t.Column(rs.Column); ok {
+		switch {
+		case c.IntType(), c.UintType():
+			setting = fmt.Sprintf("NULLIF(%s, '')::bigint", setting)
+		case c.Type == field.TypeUUID:
+			setting = fmt.Sprintf("NULLIF(%s, '')::uuid", setting)
+		}
+	}
+	return entsql.Dialect(dialect.Postgres).String(func(b *entsql.Builder) {
+		b.WriteString("CREATE POLICY ").Ident(policyName(t)).WriteString(" ON ")
+		tableIdent(b, t)
+		b.WriteString(" USING ").Wrap(func(b *entsql.Builder) {
+			b.Ident(rs.Column).WriteString(" = ").WriteString(setting)
+		})
+	})
+}
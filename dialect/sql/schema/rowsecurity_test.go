@@ -0,0 +1,62 @@
+package schema
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/syssam/velox/dialect"
+	"github.com/syssam/velox/dialect/sqlschema"
+	"github.com/syssam/velox/schema/field"
+)
+
+// documentsTable returns a "documents" table that is isolated by the given policy.
+func documentsTable(rs *sqlschema.RowSecurity) *Table {
+	return NewTable("documents").
+		AddPrimary(&Column{Name: "id", Type: field.TypeInt, Increment: true}).
+		AddColumn(&Column{Name: "tenant_id", Type: field.TypeString}).
+		AddColumn(&Column{Name: "org_id", Type: field.TypeInt64}).
+		SetAnnotation(&sqlschema.Annotation{RowSecurity: rs})
+}
+
+func TestAtlas_RowSecurityChanges(t *testing.T) {
+	docs := documentsTable(sqlschema.TenantPolicy("tenant_id").RowSecurity)
+	a := &Atlas{sqlDialect: &Postgres{Driver: nopDriver{dialect: dialect.Postgres}}, dialect: dialect.Postgres}
+
+	changes := a.rowSecurityChanges(nil, []*Table{docs, NewTable("users")})
+	require.Len(t, changes, 3)
+	assert.Equal(t, `ALTER TABLE "documents" ENABLE ROW LEVEL SECURITY`, changes[0].Cmd)
+	assert.Equal(t, `ALTER TABLE "documents" FORCE ROW LEVEL SECURITY`, changes[1].Cmd)
+	assert.Equal(t, `CREATE POLICY "documents_tenant_isolation" ON "documents" USING ("tenant_id" = current_setting('app.tenant_id', true))`, changes[2].Cmd)
+
+	// Existing policies are not created again.
+	assert.Empty(t, a.rowSecurityChanges(map[string]map[string]bool{"documents": {"documents_tenant_isolation": true}}, []*Table{docs}))
+	assert.Len(t, a.rowSecurityChanges(map[string]map[string]bool{"documents": {"documents_owner": true}}, []*Table{docs}), 3)
+
+	// The setting is cast to the type of the column, and the owner may be exempted.
+	docs = documentsTable(&sqlschema.RowSecurity{Column: "org_id", Setting: "app.org_id", Force: new(bool)})
+	changes = a.rowSecurityChanges(nil, []*Table{docs})
+	require.Len(t, changes, 2)
+	assert.Equal(t, `ALTER TABLE "documents" ENABLE ROW LEVEL SECURITY`, changes[0].Cmd)
+	assert.Equal(t, `CREATE POLICY "documents_tenant_isolation" ON "documents" USING ("org_id" = NULLIF(current_setting('app.org_id', true), '')::bigint)`, changes[1].Cmd)
+
+	a = &Atlas{sqlDialect: &MySQL{Driver: nopDriver{dialect: dialect.MySQL}}, dialect: dialect.MySQL}
+	assert.Empty(t, a.rowSecurityChanges(nil, []*Table{docs}))
+}
+
+func TestDDL_RowSecurity(t *testing.T) {
+	ctx := context.Background()
+	docs := documentsTable(&sqlschema.RowSecurity{Column: "tenant_id"})
+	ddl, err := DDL(ctx, DDLArgs{Dialect: dialect.Postgres, Tables: []*Table{docs}})
+	require.NoError(t, err)
+	assert.Contains(t, ddl, `CREATE TABLE "documents"`)
+	assert.Contains(t, ddl, `ALTER TABLE "documents" ENABLE ROW LEVEL SECURITY;`)
+	assert.Contains(t, ddl, `ALTER TABLE "documents" FORCE ROW LEVEL SECURITY;`)
+	assert.Contains(t, ddl, `CREATE POLICY "documents_tenant_isolation" ON "documents" USING ("tenant_id" = current_setting('app.tenant_id', true));`)
+
+	ddl, err = DDL(ctx, DDLArgs{Dialect: dialect.SQLite, Tables: []*Table{docs}})
+	require.NoError(t, err)
+	assert.NotContains(t, ddl, "POLICY")
+}
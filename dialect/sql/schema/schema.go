@@ -308,12 +308,35 @@ type Column struct {
 	indexes    Indexes           // linked indexes.
 	foreign    *ForeignKey       // linked foreign-key.
 	Comment    string            // optional column comment.
+	// RenamedFrom holds the previous name of a renamed column. Used by the
+	// expand/contract planner; see sqlschema.RenamedFrom.
+	RenamedFrom string
+	// Retyped reports that the renamed column changes its type, and its
+	// old column is not written; see sqlschema.RetypedFrom.
+	Retyped bool
+	// Generated holds the expression of a generated column.
+	Generated *GeneratedExpr
 }
 
 // Expr represents a raw expression. It is used to distinguish between
 // literal values and raw expressions when defining default values.
 type Expr string
 
+// GeneratedExpr describes the expression of a generated column.
+type GeneratedExpr struct {
+	Expr  string            // SQL expression.
+	Exprs map[string]string // SQL expressions per dialect, overriding Expr.
+	Type  string            // VIRTUAL or STORED.
+}
+
+// ExprFor returns the expression of the given dialect, or "" if there is none.
+func (x *GeneratedExpr) ExprFor(dialect string) string {
+	if e, ok := x.Exprs[dialect]; ok {
+		return e
+	}
+	return x.Expr
+}
+
 // UniqueKey returns boolean indicates if this column is a unique key.
 // Used by the migration tool when parsing the `DESCRIBE TABLE` output Go objects.
 func (c *Column) UniqueKey() bool { return c.Key == UniqueKey }
@@ -651,6 +674,8 @@ func DDL(ctx context.Context, args DDLArgs) (string, error) {
 			Comment: fmt.Sprintf("Add %q view", v.Name),
 		})
 	}
+	p.Changes = append(p.Changes, a.materializedChanges(&schema.Schema{}, nil, args.Tables)...)
+	p.Changes = append(p.Changes, a.rowSecurityChanges(nil, args.Tables)...)
 	for _, t := range args.Tables {
 		p.Directives = append(p.Directives, fmt.Sprintf(
 			"-- atlas:pos %s%s[type=%s] %s",
@@ -11,6 +11,7 @@ import (
 
 	"github.com/syssam/velox/dialect"
 	entsql "github.com/syssam/velox/dialect/sql"
+	"github.com/syssam/velox/dialect/sqlschema"
 	"github.com/syssam/velox/schema/field"
 
 	_ "modernc.org/sqlite"
@@ -676,3 +677,108 @@ func TestSQLiteIntegration_IdempotentCreate(t *testing.T) {
 	m2 := newSQLiteMigrate(t, drv)
 	require.NoError(t, m2.Create(ctx, table))
 }
+
+func TestSQLiteIntegration_GeneratedColumns(t *testing.T) {
+	db, drv := openSQLite(t)
+	ctx := context.Background()
+	users := func(fullName string) *Table {
+		id := &Column{Name: "id", Type: field.TypeInt64, Increment: true}
+		return &Table{
+			Name: "users",
+			Columns: []*Column{
+				id,
+				{Name: "first_name", Type: field.TypeString},
+				{Name: "last_name", Type: field.TypeString},
+				{Name: "full_name", Type: field.TypeString, Generated: &GeneratedExpr{
+					Expr:  fullName,
+					Exprs: map[string]string{dialect.MySQL: "CONCAT(first_name, ' ', last_name)"},
+					Type:  "VIRTUAL",
+				}},
+				{Name: "initials", Type: field.TypeString, Generated: &GeneratedExpr{
+					Expr: "substr(first_name, 1, 1) || substr(last_name, 1, 1)",
+					Type: "STORED",
+				}},
+			},
+			PrimaryKey: []*Column{id},
+		}
+	}
+	require.NoError(t, newSQLiteMigrate(t, drv).Create(ctx, users("first_name || ' ' || last_name")))
+	_, err := db.ExecContext(ctx, "INSERT INTO users (first_name, last_name) VALUES ('Ariel', 'Mashraki')")
+	require.NoError(t, err)
+	var fullName, initials string
+	require.NoError(t, db.QueryRowContext(ctx, "SELECT full_name, initials FROM users").Scan(&fullName, &initials))
+	assert.Equal(t, "Ariel Mashraki", fullName)
+	assert.Equal(t, "AM", initials)
+
+	// Generated columns are read-only for the diff: a changed expression
+	// does not alter the existing column.
+	require.NoError(t, newSQLiteMigrate(t, drv).Create(ctx, users("last_name || ', ' || first_name")))
+	require.NoError(t, db.QueryRowContext(ctx, "SELECT full_name FROM users").Scan(&fullName))
+	assert.Equal(t, "Ariel Mashraki", fullName)
+
+	// A dialect without an expression fails the migration.
+	noExpr := &Table{
+		Name: "pets",
+		Columns: []*Column{
+			{Name: "id", Type: field.TypeInt64, Increment: true},
+			{Name: "age", Type: field.TypeInt, Generated: &GeneratedExpr{Exprs: map[string]string{dialect.MySQL: "1"}, Type: "VIRTUAL"}},
+		},
+	}
+	noExpr.PrimaryKey = noExpr.Columns[:1]
+	err = newSQLiteMigrate(t, drv).Create(ctx, noExpr)
+	require.ErrorContains(t, err, `generated column "age" has no expression for dialect "sqlite"`)
+}
+
+func TestSQLiteIntegration_FullText(t *testing.T) {
+	db, drv := openSQLite(t)
+	ctx := context.Background()
+	id := &Column{Name: "id", Type: field.TypeInt64, Increment: true}
+	title := &Column{Name: "title", Type: field.TypeString}
+	body := &Column{Name: "body", Type: field.TypeString, Nullable: true}
+	posts := &Table{Name: "posts", Columns: []*Column{id, title, body}, PrimaryKey: []*Column{id}}
+	require.NoError(t, newSQLiteMigrate(t, drv).Create(ctx, posts))
+	_, err := db.ExecContext(ctx, "INSERT INTO posts (title, body) VALUES ('Hello', 'full-text search in velox'), ('Other', NULL)")
+	require.NoError(t, err)
+
+	// Adding the index creates the FTS5 table and indexes the existing rows.
+	posts.Indexes = []*Index{{Name: "post_title_body", Columns: []*Column{title, body}, Annotation: sqlschema.FullText()}}
+	require.NoError(t, newSQLiteMigrate(t, drv).Create(ctx, posts))
+	assert.True(t, tableExists(t, db, "post_title_body"))
+	// The FTS5 table exists, hence a second migration has no changes.
+	require.NoError(t, newSQLiteMigrate(t, drv).Create(ctx, posts))
+
+	search := func(query string) []string {
+		t.Helper()
+		idx := entsql.FullText[func(*entsql.Selector)]{Index: "post_title_body", Columns: []string{"title", "body"}}
+		s := entsql.Dialect(dialect.SQLite).Select("title").From(entsql.Table("posts"))
+		idx.Search(query)(s)
+		idx.OrderByRelevance(query)(s)
+		q, args := s.Query()
+		rows, err := db.QueryContext(ctx, q, args...)
+		require.NoError(t, err)
+		defer rows.Close()
+		var titles []string
+		for rows.Next() {
+			var title string
+			require.NoError(t, rows.Scan(&title))
+			titles = append(titles, title)
+		}
+		require.NoError(t, rows.Err())
+		return titles
+	}
+	assert.Equal(t, []string{"Hello"}, search("velox"))
+	assert.Empty(t, search("postgres"))
+
+	// The triggers keep the index in sync with the table.
+	_, err = db.ExecContext(ctx, "INSERT INTO posts (title, body) VALUES ('Velox', 'an ORM')")
+	require.NoError(t, err)
+	_, err = db.ExecContext(ctx, "UPDATE posts SET body = 'postgres' WHERE title = 'Hello'")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Velox"}, search("velox"))
+	assert.Equal(t, []string{"Hello"}, search("postgres"))
+	_, err = db.ExecContext(ctx, "DELETE FROM posts WHERE title = 'Hello'")
+	require.NoError(t, err)
+	assert.Empty(t, search("postgres"))
+	// The query syntax of FTS5 is matched literally.
+	assert.Empty(t, search(`NOT "velox`))
+}
@@ -0,0 +1,633 @@
+package schema
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/syssam/velox/dialect"
+)
+
+// DefaultRevisionsTable is the default name of the table that records the
+// applied versioned migrations.
+const DefaultRevisionsTable = "velox_revisions"
+
+// NoTransactionDirective marks a migration file that must not run inside a
+// transaction, e.g. for "CREATE INDEX CONCURRENTLY" on Postgres:
+//
+//	-- +velox NoTransaction
+//	CREATE INDEX CONCURRENTLY users_email ON users (email);
+const NoTransactionDirective = "-- +velox NoTransaction"
+
+// ErrChecksumMismatch is returned when the file of an applied migration was
+// edited after it was applied.
+var ErrChecksumMismatch = errors.New("velox/migrate: checksum mismatch")
+
+// VersionedDir is a directory of versioned migration files. Files are named
+// "<version>_<name>.sql" (or "<version>_<name>.up.sql"), with an optional
+// "<version>_<name>.down.sql" that reverts it. Versions are ordered as strings,
+// so they should have a fixed width, e.g. "20060102150405".
+type VersionedDir interface {
+	// Files returns the names of all files in the directory.
+	Files() ([]string, error)
+	// ReadFile reads a file by name.
+	ReadFile(name string) ([]byte, error)
+}
+
+// Revision describes the state of one versioned migration.
+type Revision struct {
+	// Version and Name identify the migration, Name being the file
+	// name without its ".up.sql", ".down.sql" or ".sql" suffix.
+	Version string `json:"version"`
+	Name    string `json:"name"`
+	// Checksum is the hex-encoded SHA-256 of the up file. For applied
+	// migrations, it is the checksum recorded when it was applied.
+	Checksum string `json:"checksum"`
+	// Applied is the time the migration was applied. It is zero for
+	// pending migrations.
+	Applied time.Time `json:"applied,omitempty"`
+	// Baseline reports if the migration was marked as applied by Baseline
+	// without being executed.
+	Baseline bool `json:"baseline,omitempty"`
+	// Modified reports if the up file was edited after it was applied.
+	Modified bool `json:"modified,omitempty"`
+	// Missing reports if the migration was applied, but its up file no
+	// longer exists in the directory.
+	Missing bool `json:"missing,omitempty"`
+}
+
+// Pending reports if the migration was not applied yet.
+func (r *Revision) Pending() bool {
+	return r.Applied.IsZero()
+}
+
+// RunnerOption allows configuring a VersionedRunner using functional arguments.
+type RunnerOption func(*VersionedRunner)
+
+// WithRevisionsTable sets the name of the table that records the applied
+// migrations. Defaults to DefaultRevisionsTable.
+func WithRevisionsTable(name string) RunnerOption {
+	return func(r *VersionedRunner) {
+		r.table = name
+	}
+}
+
+// WithLockTimeout sets how long the runner waits for the migration lock held
+// by another runner. Defaults to waiting until the context is done.
+func WithLockTimeout(d time.Duration) RunnerOption {
+	return func(r *VersionedRunner) {
+		r.lockTimeout = d
+	}
+}
+
+// VersionedRunner applies and reverts the migration files of a VersionedDir,
+// recording the applied ones with their checksums in a revisions table.
+//
+// Every operation runs on a single connection that holds a dialect-specific
+// lock for its duration (pg_advisory_lock on Postgres, GET_LOCK on MySQL and a
+// lock row on SQLite), so concurrent runners, e.g. several pods starting at
+// once, apply each migration exactly once. Operations refuse to run when an
+// applied file was edited (see ErrChecksumMismatch).
+//
+// Each file runs in its own transaction, unless it contains the
+// NoTransactionDirective. Statements are split on semicolons outside of quotes,
+// comments and Postgres dollar-quoted strings.
+type VersionedRunner struct {
+	db          *sql.DB
+	dialect     string
+	dir         VersionedDir
+	table       string
+	lockTimeout time.Duration
+}
+
+// NewVersionedRunner returns a VersionedRunner that runs the migrations of dir
+// against db, a database of the given dialect.
+func NewVersionedRunner(db *sql.DB, dialectName string, dir VersionedDir, opts ...RunnerOption) (*VersionedRunner, error) {
+	switch dialectName {
+	case dialect.Postgres, dialect.MySQL, dialect.SQLite:
+	default:
+		return nil, fmt.Errorf("velox/migrate: unsupported dialect %q", dialectName)
+	}
+	if db == nil || dir == nil {
+		return nil, errors.New("velox/migrate: database and migration directory are required")
+	}
+	r := &VersionedRunner{db: db, dialect: dialectName, dir: dir, table: DefaultRevisionsTable}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r, nil
+}
+
+// Status returns all migrations, applied or pending, ordered by version.
+func (r *VersionedRunner) Status(ctx context.Context) ([]*Revision, error) {
+	var revs []*Revision
+	err := r.locked(ctx, func(conn *sql.Conn, files map[string]*migrationFile) (err error) {
+		revs, err = r.status(ctx, conn, files)
+		return err
+	})
+	return revs, err
+}
+
+// Up applies the next n pending migrations, or all of them if n <= 0, and
+// returns the applied ones.
+func (r *VersionedRunner) Up(ctx context.Context, n int) ([]*Revision, error) {
+	var applied []*Revision
+	err := r.locked(ctx, func(conn *sql.Conn, files map[string]*migrationFile) error {
+		revs, err := r.status(ctx, conn, files)
+		if err != nil {
+			return err
+		}
+		if err := checkIntegrity(revs); err != nil {
+			return err
+		}
+		for _, rev := range revs {
+			if !rev.Pending() {
+				continue
+			}
+			if n > 0 && len(applied) == n {
+				break
+			}
+			f := files[rev.Version]
+			stmts, err := r.readStatements(f.up)
+			if err != nil {
+				return err
+			}
+			rev.Applied = time.Now().UTC()
+			record := func(ctx context.Context, ex execer) error {
+				_, err := ex.ExecContext(ctx, r.insertQuery(), rev.Version, rev.Name, rev.Checksum, false, rev.Applied)
+				return err
+			}
+			if err := r.exec(ctx, conn, stmts, record); err != nil {
+				return fmt.Errorf("velox/migrate: apply %s: %w", f.up, err)
+			}
+			applied = append(applied, rev)
+		}
+		return nil
+	})
+	return applied, err
+}
+
+// Down reverts the last n applied migrations using their down files, and
+// returns the reverted ones. Baseline migrations cannot be reverted.
+func (r *VersionedRunner) Down(ctx context.Context, n int) ([]*Revision, error) {
+	if n <= 0 {
+		return nil, fmt.Errorf("velox/migrate: invalid number of migrations to revert: %d", n)
+	}
+	var reverted []*Revision
+	err := r.locked(ctx, func(conn *sql.Conn, files map[string]*migrationFile) error {
+		revs, err := r.status(ctx, conn, files)
+		if err != nil {
+			return err
+		}
+		if err := checkIntegrity(revs); err != nil {
+			return err
+		}
+		// Validate all migrations to revert before reverting any.
+		var targets []*Revision
+		for i := len(revs) - 1; i >= 0 && len(targets) < n; i-- {
+			switch rev := revs[i]; {
+			case rev.Pending():
+			case rev.Baseline:
+				return fmt.Errorf("velox/migrate: cannot revert baseline migration %s", rev.Version)
+			case files[rev.Version].down == "":
+				return fmt.Errorf("velox/migrate: migration %s has no down file", rev.Version)
+			default:
+				targets = append(targets, rev)
+			}
+		}
+		for _, rev := range targets {
+			f := files[rev.Version]
+			stmts, err := r.readStatements(f.down)
+			if err != nil {
+				return err
+			}
+			record := func(ctx context.Context, ex execer) error {
+				_, err := ex.ExecContext(ctx, r.deleteQuery(), rev.Version)
+				return err
+			}
+			if err := r.exec(ctx, conn, stmts, record); err != nil {
+				return fmt.Errorf("velox/migrate: revert %s: %w", f.down, err)
+			}
+			rev.Applied = time.Time{}
+			reverted = append(reverted, rev)
+		}
+		return nil
+	})
+	return reverted, err
+}
+
+// Baseline marks all pending migrations up to and including the given version
+// as applied, without running them. It is used to adopt the runner on a
+// database whose schema already matches these migrations.
+func (r *VersionedRunner) Baseline(ctx context.Context, version string) error {
+	return r.locked(ctx, func(conn *sql.Conn, files map[string]*migrationFile) error {
+		if _, ok := files[version]; !ok {
+			return fmt.Errorf("velox/migrate: unknown migration version %q", version)
+		}
+		revs, err := r.status(ctx, conn, files)
+		if err != nil {
+			return err
+		}
+		tx, err := conn.BeginTx(ctx, nil)
+		if err != nil {
+			return err
+		}
+		now := time.Now().UTC()
+		for _, rev := range revs {
+			if rev.Version > version {
+				break
+			}
+			if !rev.Pending() {
+				continue
+			}
+			if _, err := tx.ExecContext(ctx, r.insertQuery(), rev.Version, rev.Name, rev.Checksum, true, now); err != nil {
+				return rollback(tx, err)
+			}
+		}
+		return tx.Commit()
+	})
+}
+
+// migrationFile holds the up and down file names of a migration.
+type migrationFile struct {
+	version, name, up, down string
+	checksum                string
+}
+
+// files reads the migration files of the directory, keyed by version.
+func (r *VersionedRunner) files() (map[string]*migrationFile, error) {
+	names, err := r.dir.Files()
+	if err != nil {
+		return nil, fmt.Errorf("velox/migrate: read migration directory: %w", err)
+	}
+	files := make(map[string]*migrationFile, len(names))
+	for _, fn := range names {
+		if !strings.HasSuffix(fn, ".sql") {
+			continue
+		}
+		name, down := strings.TrimSuffix(fn, ".sql"), false
+		switch {
+		case strings.HasSuffix(name, ".down"):
+			name, down = strings.TrimSuffix(name, ".down"), true
+		case strings.HasSuffix(name, ".up"):
+			name = strings.TrimSuffix(name, ".up")
+		}
+		version, _, _ := strings.Cut(name, "_")
+		f, ok := files[version]
+		switch {
+		case !ok:
+			f = &migrationFile{version: version, name: name}
+			files[version] = f
+		case f.name != name:
+			return nil, fmt.Errorf("velox/migrate: duplicate migration version %s: %s and %s", version, f.name, name)
+		}
+		if down {
+			f.down = fn
+			continue
+		}
+		if f.up != "" {
+			return nil, fmt.Errorf("velox/migrate: duplicate up files for migration %s", version)
+		}
+		b, err := r.dir.ReadFile(fn)
+		if err != nil {
+			return nil, fmt.Errorf("velox/migrate: read %s: %w", fn, err)
+		}
+		sum := sha256.Sum256(b)
+		f.up, f.checksum = fn, hex.EncodeToString(sum[:])
+	}
+	for v, f := range files {
+		if f.up == "" {
+			return nil, fmt.Errorf("velox/migrate: migration %s has a down file but no up file", v)
+		}
+	}
+	return files, nil
+}
+
+// status merges the migration files with the recorded revisions.
+func (r *VersionedRunner) status(ctx context.Context, conn *sql.Conn, files map[string]*migrationFile) ([]*Revision, error) {
+	rows, err := conn.QueryContext(ctx, fmt.Sprintf("SELECT version, name, checksum, baseline, applied_at FROM %s", r.quote(r.table)))
+	if err != nil {
+		return nil, fmt.Errorf("velox/migrate: query revisions: %w", err)
+	}
+	defer rows.Close()
+	revs := make(map[string]*Revision, len(files))
+	for rows.Next() {
+		rev := &Revision{}
+		if err := rows.Scan(&rev.Version, &rev.Name, &rev.Checksum, &rev.Baseline, &rev.Applied); err != nil {
+			return nil, fmt.Errorf("velox/migrate: scan revision: %w", err)
+		}
+		f, ok := files[rev.Version]
+		rev.Missing = !ok
+		rev.Modified = ok && f.checksum != rev.Checksum
+		revs[rev.Version] = rev
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	for v, f := range files {
+		if _, ok := revs[v]; !ok {
+			revs[v] = &Revision{Version: v, Name: f.name, Checksum: f.checksum}
+		}
+	}
+	list := make([]*Revision, 0, len(revs))
+	for _, rev := range revs {
+		list = append(list, rev)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Version < list[j].Version })
+	return list, nil
+}
+
+// checkIntegrity fails if an applied migration was edited or removed.
+func checkIntegrity(revs []*Revision) error {
+	for _, rev := range revs {
+		switch {
+		case rev.Modified:
+			return fmt.Errorf("%w: migration %s was edited after it was applied", ErrChecksumMismatch, rev.Version)
+		case rev.Missing:
+			return fmt.Errorf("velox/migrate: applied migration %s is missing from the directory", rev.Version)
+		}
+	}
+	return nil
+}
+
+// execer is implemented by *sql.Conn and *sql.Tx.
+type execer interface {
+	ExecContext(context.Context, string, ...any) (sql.Result, error)
+}
+
+// exec runs the statements of a file followed by record, in a transaction
+// unless the file opted out of it.
+func (r *VersionedRunner) exec(ctx context.Context, conn *sql.Conn, f *statements, record func(context.Context, execer) error) error {
+	if f.noTx {
+		for _, stmt := range f.stmts {
+			if _, err := conn.ExecContext(ctx, stmt); err != nil {
+				return err
+			}
+		}
+		return record(ctx, conn)
+	}
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	for _, stmt := range f.stmts {
+		if _, err := tx.ExecContext(ctx, stmt); err != nil {
+			return rollback(tx, err)
+		}
+	}
+	if err := record(ctx, tx); err != nil {
+		return rollback(tx, err)
+	}
+	return tx.Commit()
+}
+
+// statements are the parsed statements of a migration file.
+type statements struct {
+	stmts []string
+	noTx  bool
+}
+
+func (r *VersionedRunner) readStatements(name string) (*statements, error) {
+	b, err := r.dir.ReadFile(name)
+	if err != nil {
+		return nil, fmt.Errorf("velox/migrate: read %s: %w", name, err)
+	}
+	content := string(b)
+	s := &statements{stmts: SplitStatements(content)}
+	for _, line := range strings.Split(content, "\n") {
+		if strings.EqualFold(strings.TrimSpace(line), NoTransactionDirective) {
+			s.noTx = true
+			break
+		}
+	}
+	return s, nil
+}
+
+// locked runs fn on a single connection, holding the migration lock and
+// after making sure the revisions table exists.
+func (r *VersionedRunner) locked(ctx context.Context, fn func(*sql.Conn, map[string]*migrationFile) error) (err error) {
+	files, err := r.files()
+	if err != nil {
+		return err
+	}
+	conn, err := r.db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	lockCtx := ctx
+	if r.lockTimeout > 0 {
+		var cancel context.CancelFunc
+		lockCtx, cancel = context.WithTimeout(ctx, r.lockTimeout)
+		defer cancel()
+	}
+	unlock, err := r.lock(lockCtx, conn)
+	if err != nil {
+		return fmt.Errorf("velox/migrate: acquire lock: %w", err)
+	}
+	defer func() {
+		// Release the lock even if ctx was canceled in the meantime.
+		if uerr := unlock(context.WithoutCancel(ctx)); uerr != nil && err == nil {
+			err = fmt.Errorf("velox/migrate: release lock: %w", uerr)
+		}
+	}()
+	if err := r.ensureTable(ctx, conn); err != nil {
+		return err
+	}
+	return fn(conn, files)
+}
+
+// lock acquires the dialect-specific migration lock on conn.
+func (r *VersionedRunner) lock(ctx context.Context, conn *sql.Conn) (func(context.Context) error, error) {
+	switch r.dialect {
+	case dialect.Postgres:
+		key := r.lockKey()
+		if _, err := conn.ExecContext(ctx, "SELECT pg_advisory_lock($1)", key); err != nil {
+			return nil, err
+		}
+		return func(ctx context.Context) error {
+			_, err := conn.ExecContext(ctx, "SELECT pg_advisory_unlock($1)", key)
+			return err
+		}, nil
+	case dialect.MySQL:
+		// GET_LOCK takes its timeout in seconds, and a negative
+		// value waits forever.
+		timeout := -1
+		if r.lockTimeout > 0 {
+			timeout = int(math.Ceil(r.lockTimeout.Seconds()))
+		}
+		var ok sql.NullInt64
+		if err := conn.QueryRowContext(ctx, "SELECT GET_LOCK(?, ?)", r.table, timeout).Scan(&ok); err != nil {
+			return nil, err
+		}
+		if ok.Int64 != 1 {
+			return nil, fmt.Errorf("lock %q is held by another session", r.table)
+		}
+		return func(ctx context.Context) error {
+			_, err := conn.ExecContext(ctx, "SELECT RELEASE_LOCK(?)", r.table)
+			return err
+		}, nil
+	default:
+		return r.lockRow(ctx, conn)
+	}
+}
+
+// lockRow implements the lock on SQLite, which has no advisory locks, with a
+// single-row table: the runner holding the lock is the one that inserted it.
+// A runner that crashes leaves the row behind; deleting it releases the lock.
+func (r *VersionedRunner) lockRow(ctx context.Context, conn *sql.Conn) (func(context.Context) error, error) {
+	table := r.quote(r.table + "_lock")
+	if _, err := conn.ExecContext(ctx, fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s (id INTEGER PRIMARY KEY CHECK (id = 1), locked_at TIMESTAMP NOT NULL)", table)); err != nil {
+		return nil, err
+	}
+	insert := fmt.Sprintf("INSERT INTO %s (id, locked_at) VALUES (1, ?) ON CONFLICT (id) DO NOTHING", table)
+	for {
+		res, err := conn.ExecContext(ctx, insert, time.Now().UTC())
+		if err != nil {
+			return nil, err
+		}
+		if n, err := res.RowsAffected(); err != nil {
+			return nil, err
+		} else if n == 1 {
+			break
+		}
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("lock %s is held by another runner: %w", table, ctx.Err())
+		case <-time.After(100 * time.Millisecond):
+		}
+	}
+	return func(ctx context.Context) error {
+		_, err := conn.ExecContext(ctx, fmt.Sprintf("DELETE FROM %s WHERE id = 1", table))
+		return err
+	}, nil
+}
+
+// lockKey returns the Postgres advisory lock key of the revisions table.
+func (r *VersionedRunner) lockKey() int64 {
+	h := fnv.New64a()
+	h.Write([]byte("velox/migrate:" + r.table))
+	return int64(h.Sum64() >> 1)
+}
+
+// ensureTable creates the revisions table if it does not exist.
+func (r *VersionedRunner) ensureTable(ctx context.Context, conn *sql.Conn) error {
+	query := fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+	version VARCHAR(255) NOT NULL PRIMARY KEY,
+	name VARCHAR(255) NOT NULL,
+	checksum VARCHAR(64) NOT NULL,
+	baseline BOOLEAN NOT NULL DEFAULT FALSE,
+	applied_at TIMESTAMP NOT NULL
+)`, r.quote(r.table))
+	if _, err := conn.ExecContext(ctx, query); err != nil {
+		return fmt.Errorf("velox/migrate: create revisions table: %w", err)
+	}
+	return nil
+}
+
+func (r *VersionedRunner) insertQuery() string {
+	if r.dialect == dialect.Postgres {
+		return fmt.Sprintf("INSERT INTO %s (version, name, checksum, baseline, applied_at) VALUES ($1, $2, $3, $4, $5)", r.quote(r.table))
+	}
+	return fmt.Sprintf("INSERT INTO %s (version, name, checksum, baseline, applied_at) VALUES (?, ?, ?, ?, ?)", r.quote(r.table))
+}
+
+func (r *VersionedRunner) deleteQuery() string {
+	if r.dialect == dialect.Postgres {
+		return fmt.Sprintf("DELETE FROM %s WHERE version = $1", r.quote(r.table))
+	}
+	return fmt.Sprintf("DELETE FROM %s WHERE version = ?", r.quote(r.table))
+}
+
+// quote quotes an identifier for the runner's dialect.
+func (r *VersionedRunner) quote(ident string) string {
+	if r.dialect == dialect.MySQL {
+		return "`" + strings.ReplaceAll(ident, "`", "``") + "`"
+	}
+	return `"` + strings.ReplaceAll(ident, `"`, `""`) + `"`
+}
+
+func rollback(tx *sql.Tx, err error) error {
+	if rerr := tx.Rollback(); rerr != nil {
+		return fmt.Errorf("%w: rollback: %v", err, rerr)
+	}
+	return err
+}
+
+// SplitStatements splits the content of a migration file into statements on
+// the semicolons outside of string literals, quoted identifiers, comments and
+// Postgres dollar-quoted strings. Statements with only comments are dropped.
+func SplitStatements(content string) []string {
+	var (
+		stmts   []string
+		start   int
+		hasCode bool
+	)
+	flush := func(end int) {
+		if stmt := strings.TrimSpace(content[start:end]); hasCode && stmt != "" {
+			stmts = append(stmts, stmt)
+		}
+		start, hasCode = end+1, false
+	}
+	for i := 0; i < len(content); i++ {
+		switch c := content[i]; {
+		case c == '-' && strings.HasPrefix(content[i:], "--"):
+			if j := strings.IndexByte(content[i:], '\n'); j >= 0 {
+				i += j
+			} else {
+				i = len(content)
+			}
+		case c == '/' && strings.HasPrefix(content[i:], "/*"):
+			if j := strings.Index(content[i+2:], "*/"); j >= 0 {
+				i += j + 3
+			} else {
+				i = len(content)
+			}
+		case c == '\'' || c == '"' || c == '`':
+			hasCode = true
+			if _, i = skipQuoted(content, i); i == -1 {
+				i = len(content)
+			}
+		case c == '$':
+			hasCode = true
+			if tag, ok := dollarTag(content[i:]); ok {
+				if j := strings.Index(content[i+len(tag):], tag); j >= 0 {
+					i += len(tag) + j + len(tag) - 1
+				} else {
+					i = len(content)
+				}
+			}
+		case c == ';':
+			flush(i)
+		case c != ' ' && c != '\t' && c != '\n' && c != '\r':
+			hasCode = true
+		}
+	}
+	if start < len(content) {
+		flush(len(content))
+	}
+	return stmts
+}
+
+// dollarTag returns the Postgres dollar-quote tag ("$$" or "$tag$") at the
+// start of s, if any.
+func dollarTag(s string) (string, bool) {
+	for j := 1; j < len(s); j++ {
+		switch c := s[j]; {
+		case c == '$':
+			return s[:j+1], true
+		case c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || j > 1 && c >= '0' && c <= '9':
+		default:
+			return "", false
+		}
+	}
+	return "", false
+}
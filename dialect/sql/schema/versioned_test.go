@@ -0,0 +1,302 @@
+package schema
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"path/filepath"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/syssam/velox/dialect"
+
+	_ "modernc.org/sqlite"
+)
+
+// mapDir is an in-memory VersionedDir.
+type mapDir map[string]string
+
+func (d mapDir) Files() ([]string, error) {
+	names := make([]string, 0, len(d))
+	for name := range d {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+func (d mapDir) ReadFile(name string) ([]byte, error) {
+	content, ok := d[name]
+	if !ok {
+		return nil, errors.New("file does not exist")
+	}
+	return []byte(content), nil
+}
+
+// openVersionedSQLite opens a file-backed SQLite database, so all pooled
+// connections (and concurrent runners) share it.
+func openVersionedSQLite(t *testing.T) *sql.DB {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "versioned.db")
+	db, err := sql.Open("sqlite", path+"?_pragma=busy_timeout(10000)&_pragma=foreign_keys(1)")
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+func testMigrations() mapDir {
+	return mapDir{
+		"20240101000000_users.up.sql":   "CREATE TABLE users (id INTEGER PRIMARY KEY, name TEXT);",
+		"20240101000000_users.down.sql": "DROP TABLE users;",
+		"20240102000000_posts.up.sql": `-- posts of users.
+CREATE TABLE posts (id INTEGER PRIMARY KEY, title TEXT);
+INSERT INTO posts (title) VALUES ('a;b');`,
+		"20240102000000_posts.down.sql": "DROP TABLE posts;",
+		"20240103000000_tags.sql":       "CREATE TABLE tags (id INTEGER PRIMARY KEY);",
+	}
+}
+
+func TestVersionedRunner_UpDownStatus(t *testing.T) {
+	ctx := context.Background()
+	db := openVersionedSQLite(t)
+	r, err := NewVersionedRunner(db, dialect.SQLite, testMigrations())
+	require.NoError(t, err)
+
+	applied, err := r.Up(ctx, 1)
+	require.NoError(t, err)
+	require.Len(t, applied, 1)
+	assert.Equal(t, "20240101000000", applied[0].Version)
+	assert.True(t, tableExists(t, db, "users"))
+	assert.False(t, tableExists(t, db, "posts"))
+
+	applied, err = r.Up(ctx, 0)
+	require.NoError(t, err)
+	require.Len(t, applied, 2)
+	var title string
+	require.NoError(t, db.QueryRow("SELECT title FROM posts").Scan(&title))
+	assert.Equal(t, "a;b", title)
+
+	revs, err := r.Status(ctx)
+	require.NoError(t, err)
+	require.Len(t, revs, 3)
+	for _, rev := range revs {
+		assert.False(t, rev.Pending(), rev.Version)
+		assert.Len(t, rev.Checksum, 64)
+	}
+	assert.Equal(t, "20240103000000_tags", revs[2].Name)
+
+	// The last migration has no down file.
+	_, err = r.Down(ctx, 1)
+	require.ErrorContains(t, err, "has no down file")
+
+	require.NoError(t, db.Close())
+	db = openVersionedSQLite(t)
+	r, err = NewVersionedRunner(db, dialect.SQLite, mapDir{
+		"1_users.up.sql":   "CREATE TABLE users (id INTEGER PRIMARY KEY);",
+		"1_users.down.sql": "DROP TABLE users;",
+		"2_posts.up.sql":   "CREATE TABLE posts (id INTEGER PRIMARY KEY);",
+		"2_posts.down.sql": "DROP TABLE posts;",
+	})
+	require.NoError(t, err)
+	_, err = r.Up(ctx, 0)
+	require.NoError(t, err)
+	reverted, err := r.Down(ctx, 2)
+	require.NoError(t, err)
+	require.Len(t, reverted, 2)
+	assert.Equal(t, "2", reverted[0].Version)
+	assert.False(t, tableExists(t, db, "users"))
+	revs, err = r.Status(ctx)
+	require.NoError(t, err)
+	assert.True(t, revs[0].Pending())
+	assert.True(t, revs[1].Pending())
+
+	_, err = r.Down(ctx, 0)
+	require.Error(t, err)
+}
+
+func TestVersionedRunner_ChecksumMismatch(t *testing.T) {
+	ctx := context.Background()
+	db := openVersionedSQLite(t)
+	dir := testMigrations()
+	r, err := NewVersionedRunner(db, dialect.SQLite, dir)
+	require.NoError(t, err)
+	_, err = r.Up(ctx, 1)
+	require.NoError(t, err)
+
+	dir["20240101000000_users.up.sql"] = "CREATE TABLE users (id INTEGER PRIMARY KEY, email TEXT);"
+	_, err = r.Up(ctx, 0)
+	require.ErrorIs(t, err, ErrChecksumMismatch)
+	assert.False(t, tableExists(t, db, "posts"))
+
+	revs, err := r.Status(ctx)
+	require.NoError(t, err)
+	assert.True(t, revs[0].Modified)
+
+	delete(dir, "20240101000000_users.up.sql")
+	delete(dir, "20240101000000_users.down.sql")
+	revs, err = r.Status(ctx)
+	require.NoError(t, err)
+	assert.True(t, revs[0].Missing)
+	_, err = r.Up(ctx, 0)
+	require.ErrorContains(t, err, "is missing")
+}
+
+func TestVersionedRunner_Baseline(t *testing.T) {
+	ctx := context.Background()
+	db := openVersionedSQLite(t)
+	_, err := db.Exec("CREATE TABLE users (id INTEGER PRIMARY KEY, name TEXT)")
+	require.NoError(t, err)
+
+	dir := testMigrations()
+	dir["20240103000000_tags.down.sql"] = "DROP TABLE tags;"
+	r, err := NewVersionedRunner(db, dialect.SQLite, dir, WithRevisionsTable("revisions"))
+	require.NoError(t, err)
+	require.Error(t, r.Baseline(ctx, "20990101000000"))
+	require.NoError(t, r.Baseline(ctx, "20240101000000"))
+
+	applied, err := r.Up(ctx, 0)
+	require.NoError(t, err)
+	require.Len(t, applied, 2)
+	assert.Equal(t, "20240102000000", applied[0].Version)
+
+	revs, err := r.Status(ctx)
+	require.NoError(t, err)
+	assert.True(t, revs[0].Baseline)
+	assert.False(t, revs[1].Baseline)
+	assert.True(t, tableExists(t, db, "revisions"))
+
+	_, err = r.Down(ctx, 3)
+	require.ErrorContains(t, err, "cannot revert baseline")
+	assert.True(t, tableExists(t, db, "tags"), "nothing is reverted if one migration cannot be")
+}
+
+func TestVersionedRunner_NoTransaction(t *testing.T) {
+	ctx := context.Background()
+	db := openVersionedSQLite(t)
+	r, err := NewVersionedRunner(db, dialect.SQLite, mapDir{
+		// VACUUM cannot run inside a transaction.
+		"1_vacuum.sql": NoTransactionDirective + "\nCREATE TABLE t (id INTEGER);\nVACUUM;",
+	})
+	require.NoError(t, err)
+	_, err = r.Up(ctx, 0)
+	require.NoError(t, err)
+
+	r, err = NewVersionedRunner(db, dialect.SQLite, mapDir{"1_vacuum.sql": "VACUUM;"}, WithRevisionsTable("other"))
+	require.NoError(t, err)
+	_, err = r.Up(ctx, 0)
+	require.Error(t, err)
+}
+
+func TestVersionedRunner_Concurrent(t *testing.T) {
+	ctx := context.Background()
+	db := openVersionedSQLite(t)
+	var (
+		wg    sync.WaitGroup
+		mu    sync.Mutex
+		total int
+		errs  []error
+	)
+	for range 4 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			r, err := NewVersionedRunner(db, dialect.SQLite, testMigrations(), WithLockTimeout(30*time.Second))
+			if err == nil {
+				var applied []*Revision
+				applied, err = r.Up(ctx, 0)
+				mu.Lock()
+				total += len(applied)
+				mu.Unlock()
+			}
+			mu.Lock()
+			errs = append(errs, err)
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+	for _, err := range errs {
+		require.NoError(t, err)
+	}
+	assert.Equal(t, 3, total, "each migration must be applied exactly once")
+}
+
+func TestVersionedRunner_LockTimeout(t *testing.T) {
+	ctx := context.Background()
+	db := openVersionedSQLite(t)
+	r, err := NewVersionedRunner(db, dialect.SQLite, testMigrations(), WithLockTimeout(200*time.Millisecond))
+	require.NoError(t, err)
+	_, err = db.Exec(`CREATE TABLE "velox_revisions_lock" (id INTEGER PRIMARY KEY CHECK (id = 1), locked_at TIMESTAMP NOT NULL)`)
+	require.NoError(t, err)
+	_, err = db.Exec(`INSERT INTO "velox_revisions_lock" (id, locked_at) VALUES (1, CURRENT_TIMESTAMP)`)
+	require.NoError(t, err)
+
+	_, err = r.Up(ctx, 0)
+	require.ErrorContains(t, err, "acquire lock")
+	assert.False(t, tableExists(t, db, "users"))
+}
+
+func TestVersionedRunner_InvalidDir(t *testing.T) {
+	ctx := context.Background()
+	db := openVersionedSQLite(t)
+	_, err := NewVersionedRunner(db, "oracle", mapDir{})
+	require.Error(t, err)
+
+	for name, dir := range map[string]mapDir{
+		"duplicate version": {"1_a.sql": "SELECT 1;", "1_b.sql": "SELECT 1;"},
+		"down without up":   {"1_a.down.sql": "SELECT 1;"},
+	} {
+		r, err := NewVersionedRunner(db, dialect.SQLite, dir)
+		require.NoError(t, err)
+		_, err = r.Up(ctx, 0)
+		assert.Error(t, err, name)
+	}
+}
+
+func TestSplitStatements(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    []string
+	}{
+		{
+			name:    "simple",
+			content: "CREATE TABLE a (id int);\nCREATE TABLE b (id int);\n",
+			want:    []string{"CREATE TABLE a (id int)", "CREATE TABLE b (id int)"},
+		},
+		{
+			name:    "no trailing semicolon",
+			content: "SELECT 1",
+			want:    []string{"SELECT 1"},
+		},
+		{
+			name:    "quotes and comments",
+			content: "-- a; comment\nINSERT INTO t VALUES ('x;y', \"c;d\", `e;f`); /* g; */ SELECT 1;\n-- trailing;",
+			want:    []string{"-- a; comment\nINSERT INTO t VALUES ('x;y', \"c;d\", `e;f`)", "/* g; */ SELECT 1"},
+		},
+		{
+			name:    "escaped quote",
+			content: "SELECT 'it''s; fine'; SELECT 2;",
+			want:    []string{"SELECT 'it''s; fine'", "SELECT 2"},
+		},
+		{
+			name:    "dollar quoted",
+			content: "CREATE FUNCTION f() RETURNS int AS $body$ BEGIN RETURN 1; END; $body$ LANGUAGE plpgsql;\nSELECT $1;",
+			want:    []string{"CREATE FUNCTION f() RETURNS int AS $body$ BEGIN RETURN 1; END; $body$ LANGUAGE plpgsql", "SELECT $1"},
+		},
+		{
+			name:    "comments only",
+			content: NoTransactionDirective + "\n",
+			want:    nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, SplitStatements(tt.content))
+		})
+	}
+}
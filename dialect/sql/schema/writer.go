@@ -53,6 +53,12 @@ func (d *DirWriter) Change(comment string) {
 
 // Flush flushes the written statements to the directory.
 func (d *DirWriter) Flush(name string) error {
+	return d.flush("", name)
+}
+
+// flush writes the pending changes as a migration file with the given
+// version. An empty version lets the formatter pick one.
+func (d *DirWriter) flush(version, name string) error {
 	switch {
 	case d.b.Len() != 0:
 		return fmt.Errorf("writer has undocumented change. Use Change or FlushChange instead")
@@ -61,6 +67,7 @@ func (d *DirWriter) Flush(name string) error {
 	default:
 		return migrate.NewPlanner(nil, d.Dir, migrate.PlanFormat(d.Formatter)).
 			WritePlan(&migrate.Plan{
+				Version: version,
 				Name:    name,
 				Changes: d.changes,
 			})
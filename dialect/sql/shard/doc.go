@@ -0,0 +1,47 @@
+// Package shard routes the statements of a Velox client to one of several
+// databases by a shard key.
+//
+// sql/schemaconfig and sql/multischema spread entities across the schemas
+// of one database. A Driver spreads the rows of one entity across several
+// databases that share the same schema, such as one database per group of
+// tenants:
+//
+//	drv, err := shard.NewDriver([]dialect.Driver{db0, db1, db2})
+//	client := ent.NewClient(ent.Driver(drv))
+//
+// # Routing
+//
+// Every statement runs on the shard picked from the shard key in the
+// context, by the Strategy of the driver (Hash by default):
+//
+//	ctx = shard.WithKey(ctx, tenantID)
+//	users, err := client.User.Query().All(ctx)
+//
+// Statements without a shard key fail with ErrNoShardKey. WithIndex pins
+// a shard directly, e.g. for maintenance jobs.
+//
+// # Shard Key Fields
+//
+// With the sql/shard feature enabled, a type annotated with Key derives the
+// shard of its create builders from the key field, so creates need no shard
+// key in the context:
+//
+//	func (User) Annotations() []schema.Annotation {
+//	    return []schema.Annotation{
+//	        shard.Key("tenant_id"),
+//	    }
+//	}
+//
+// All the builders of a CreateBulk must have the same key.
+//
+// # Scatter-Gather
+//
+// Queries in a context marked with WithScatter run on all shards when the
+// context has no shard key. The rows of node queries (All, First, Only, IDs)
+// are merged by their ORDER BY terms, and LIMIT/OFFSET are applied to the
+// merged rows. Counts are summed, and edges are concatenated. Other queries,
+// such as aggregations, fail with ErrNoMerge, unless the context is marked
+// with WithRawScatter, that returns the rows of all shards one after the
+// other. Updates and deletes run on all
+// shards, each in its own statement, and return the sum of the affected rows.
+package shard
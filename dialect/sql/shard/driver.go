@@ -0,0 +1,202 @@
+package shard
+
+import (
+	"context"
+	stdsql "database/sql"
+	"errors"
+	"fmt"
+
+	"golang.org/x/sync/errgroup"
+
+	"github.com/syssam/velox/dialect"
+	"github.com/syssam/velox/dialect/sql"
+)
+
+// Driver is a dialect.Driver that routes every statement to one of its
+// shards, picked from the shard key in the context. See the package
+// documentation for the routing rules.
+type Driver struct {
+	shards   []dialect.Driver
+	dialect  string
+	strategy Strategy
+}
+
+var _ dialect.Driver = (*Driver)(nil)
+
+// Option configures a Driver.
+type Option func(*Driver)
+
+// WithStrategy sets the Strategy that picks the shard of a key.
+// Defaults to Hash.
+func WithStrategy(s Strategy) Option {
+	return func(d *Driver) {
+		d.strategy = s
+	}
+}
+
+// NewDriver returns a Driver over the given shards. The shards must
+// share the same dialect, and their order must be stable across
+// restarts, as the Strategy maps keys to indexes.
+func NewDriver(shards []dialect.Driver, opts ...Option) (*Driver, error) {
+	if len(shards) == 0 {
+		return nil, errors.New("shard: no shards")
+	}
+	d := &Driver{
+		shards:   shards,
+		dialect:  shards[0].Dialect(),
+		strategy: Hash,
+	}
+	for i, s := range shards[1:] {
+		if s.Dialect() != d.dialect {
+			return nil, fmt.Errorf("shard: dialect %q of shard %d does not match dialect %q of shard 0", s.Dialect(), i+1, d.dialect)
+		}
+	}
+	for _, opt := range opts {
+		opt(d)
+	}
+	return d, nil
+}
+
+// Shards returns the underlying drivers, e.g. for running
+// the migrations on every shard.
+func (d *Driver) Shards() []dialect.Driver {
+	return d.shards
+}
+
+// Shard returns the shard of the given context, or ErrNoShardKey if the
+// context has neither a shard key nor a shard index.
+func (d *Driver) Shard(ctx context.Context) (dialect.Driver, error) {
+	if i, ok := ctx.Value(indexCtxKey{}).(int); ok {
+		if i < 0 || i >= len(d.shards) {
+			return nil, fmt.Errorf("shard: index %d out of range [0, %d)", i, len(d.shards))
+		}
+		return d.shards[i], nil
+	}
+	key, ok := KeyFromContext(ctx)
+	if !ok {
+		return nil, ErrNoShardKey
+	}
+	i, err := d.strategy(key, len(d.shards))
+	if err != nil {
+		return nil, fmt.Errorf("shard: pick shard of key %v: %w", key, err)
+	}
+	if i < 0 || i >= len(d.shards) {
+		return nil, fmt.Errorf("shard: strategy returned index %d out of range [0, %d)", i, len(d.shards))
+	}
+	return d.shards[i], nil
+}
+
+// Exec implements the dialect.Driver interface. Without a shard key, it
+// runs the statement on all shards if the context is marked with WithScatter.
+func (d *Driver) Exec(ctx context.Context, query string, args, v any) error {
+	drv, err := d.Shard(ctx)
+	switch {
+	case err == nil:
+		return drv.Exec(ctx, query, args, v)
+	case errors.Is(err, ErrNoShardKey) && IsScatter(ctx):
+		return d.scatterExec(ctx, query, args, v)
+	default:
+		return err
+	}
+}
+
+// Query implements the dialect.Driver interface. Without a shard key, it
+// runs the query on all shards if the context is marked with WithScatter.
+func (d *Driver) Query(ctx context.Context, query string, args, v any) error {
+	drv, err := d.Shard(ctx)
+	switch {
+	case err == nil:
+		return drv.Query(ctx, query, args, v)
+	case errors.Is(err, ErrNoShardKey) && IsScatter(ctx):
+		return d.scatterQuery(ctx, query, args, v)
+	default:
+		return err
+	}
+}
+
+// Tx starts a transaction on the shard of the context.
+// Transactions never span several shards.
+func (d *Driver) Tx(ctx context.Context) (dialect.Tx, error) {
+	drv, err := d.Shard(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return drv.Tx(ctx)
+}
+
+// BeginTx starts a transaction with options on the shard of the context.
+func (d *Driver) BeginTx(ctx context.Context, opts *stdsql.TxOptions) (dialect.Tx, error) {
+	drv, err := d.Shard(ctx)
+	if err != nil {
+		return nil, err
+	}
+	btx, ok := drv.(interface {
+		BeginTx(context.Context, *stdsql.TxOptions) (dialect.Tx, error)
+	})
+	if !ok {
+		return nil, fmt.Errorf("shard: driver %T does not support BeginTx", drv)
+	}
+	return btx.BeginTx(ctx, opts)
+}
+
+// Close closes all shards.
+func (d *Driver) Close() error {
+	var errs []error
+	for _, s := range d.shards {
+		errs = append(errs, s.Close())
+	}
+	return errors.Join(errs...)
+}
+
+// Dialect returns the dialect of the shards.
+func (d *Driver) Dialect() string {
+	return d.dialect
+}
+
+// scatterExec runs the statement on all shards.
+func (d *Driver) scatterExec(ctx context.Context, query string, args, v any) error {
+	affected := make([]int64, len(d.shards))
+	g, gctx := errgroup.WithContext(ctx)
+	for i, s := range d.shards {
+		g.Go(func() error {
+			var res sql.Result
+			if err := s.Exec(gctx, query, args, &res); err != nil {
+				return fmt.Errorf("shard %d: %w", i, err)
+			}
+			n, err := res.RowsAffected()
+			if err != nil {
+				return fmt.Errorf("shard %d: %w", i, err)
+			}
+			affected[i] = n
+			return nil
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return err
+	}
+	switch v := v.(type) {
+	case nil:
+	case *sql.Result:
+		var n int64
+		for _, a := range affected {
+			n += a
+		}
+		*v = scatterResult(n)
+	default:
+		return fmt.Errorf("shard: invalid type %T. expect *sql.Result", v)
+	}
+	return nil
+}
+
+// scatterResult is the sql.Result of a statement that ran on all shards.
+type scatterResult int64
+
+// LastInsertId implements the sql.Result interface.
+func (scatterResult) LastInsertId() (int64, error) {
+	return 0, errors.New("shard: LastInsertId is not supported for statements on all shards")
+}
+
+// RowsAffected implements the sql.Result interface.
+func (r scatterResult) RowsAffected() (int64, error) {
+	return int64(r), nil
+}
@@ -0,0 +1,284 @@
+package shard
+
+import (
+	"bytes"
+	"context"
+	"database/sql/driver"
+	"fmt"
+	"math/big"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/sync/errgroup"
+
+	"github.com/syssam/velox/dialect"
+	"github.com/syssam/velox/dialect/sql"
+	"github.com/syssam/velox/dialect/sql/internal/rowsdb"
+)
+
+// scatterQuery runs the query on all shards, and merges their rows
+// as described by the Merge of the context.
+func (d *Driver) scatterQuery(ctx context.Context, query string, args, v any) error {
+	rows, ok := v.(*sql.Rows)
+	if !ok {
+		return fmt.Errorf("shard: invalid type %T. expect *sql.Rows", v)
+	}
+	m := MergeFromContext(ctx)
+	if m == nil && !isRawScatter(ctx) {
+		return fmt.Errorf("%w: %s", ErrNoMerge, query)
+	}
+	if m != nil && m.Query != "" {
+		query, args = m.Query, m.Args
+	}
+	results := make([]*rowsdb.Result, len(d.shards))
+	g, gctx := errgroup.WithContext(ctx)
+	for i, s := range d.shards {
+		g.Go(func() error {
+			r, err := collect(gctx, s, query, args)
+			if err != nil {
+				return fmt.Errorf("shard %d: %w", i, err)
+			}
+			results[i] = r
+			return nil
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return err
+	}
+	merged, err := d.merge(results, m)
+	if err != nil {
+		return err
+	}
+	sr, err := merged.Open(ctx)
+	if err != nil {
+		return err
+	}
+	rows.ColumnScanner = sr
+	return nil
+}
+
+// collect runs the query on the given shard and buffers its rows.
+func collect(ctx context.Context, drv dialect.Driver, query string, args any) (*rowsdb.Result, error) {
+	rows := &sql.Rows{}
+	if err := drv.Query(ctx, query, args, rows); err != nil {
+		return nil, err
+	}
+	return rowsdb.Collect(rows)
+}
+
+// merge merges the results of all shards.
+func (d *Driver) merge(results []*rowsdb.Result, m *Merge) (*rowsdb.Result, error) {
+	merged := &rowsdb.Result{Columns: results[0].Columns}
+	for _, r := range results {
+		merged.Rows = append(merged.Rows, r.Rows...)
+	}
+	switch {
+	case m == nil:
+		return merged, nil
+	case m.Count:
+		var n int64
+		for _, row := range merged.Rows {
+			c, err := toInt64(row[0])
+			if err != nil {
+				return nil, fmt.Errorf("shard: merge counts: %w", err)
+			}
+			n += c
+		}
+		merged.Rows = [][]driver.Value{{n}}
+		return merged, nil
+	}
+	if len(m.Order) > 0 {
+		terms := make([]orderTerm, len(m.Order))
+		for i, t := range m.Order {
+			term, err := parseOrderTerm(t, merged.Columns, d.dialect)
+			if err != nil {
+				return nil, err
+			}
+			terms[i] = term
+		}
+		slices.SortStableFunc(merged.Rows, func(a, b []driver.Value) int {
+			for _, t := range terms {
+				if c := t.compare(a[t.index], b[t.index]); c != 0 {
+					return c
+				}
+			}
+			return 0
+		})
+	}
+	if m.Offset > 0 {
+		merged.Rows = merged.Rows[min(m.Offset, len(merged.Rows)):]
+	}
+	if m.Limit > 0 && m.Limit < len(merged.Rows) {
+		merged.Rows = merged.Rows[:m.Limit]
+	}
+	return merged, nil
+}
+
+// orderTerm is an ORDER BY term of a merged query.
+type orderTerm struct {
+	index      int  // index of the column in the rows.
+	desc       bool // descending order.
+	nullsFirst bool // NULL values sort before other values.
+}
+
+// parseOrderTerm parses a term rendered by sql.Selector.OrderTerms, such as
+// "`users`.`name` DESC NULLS LAST", into the column it orders by.
+func parseOrderTerm(term string, columns []string, dialectName string) (orderTerm, error) {
+	var (
+		t     orderTerm
+		s     = strings.TrimSpace(term)
+		nulls = ""
+	)
+	for _, suffix := range []string{" NULLS FIRST", " NULLS LAST"} {
+		if strings.HasSuffix(strings.ToUpper(s), suffix) {
+			nulls, s = suffix, strings.TrimSpace(s[:len(s)-len(suffix)])
+		}
+	}
+	switch upper := strings.ToUpper(s); {
+	case strings.HasSuffix(upper, " DESC"):
+		t.desc, s = true, strings.TrimSpace(s[:len(s)-len(" DESC")])
+	case strings.HasSuffix(upper, " ASC"):
+		s = strings.TrimSpace(s[:len(s)-len(" ASC")])
+	}
+	// NULL values are the largest values on PostgreSQL, and
+	// the smallest ones on MySQL and SQLite.
+	largest := dialectName == dialect.Postgres
+	t.nullsFirst = largest == t.desc
+	switch nulls {
+	case " NULLS FIRST":
+		t.nullsFirst = true
+	case " NULLS LAST":
+		t.nullsFirst = false
+	}
+	if i := strings.LastIndexByte(s, '.'); i >= 0 {
+		s = s[i+1:]
+	}
+	s = strings.Trim(s, "`\"")
+	if strings.ContainsAny(s, " ()`\"") {
+		return t, fmt.Errorf("shard: cannot merge rows ordered by expression %q", term)
+	}
+	t.index = slices.Index(columns, s)
+	if t.index == -1 {
+		return t, fmt.Errorf("shard: cannot merge rows ordered by %q: column %q is not selected", term, s)
+	}
+	return t, nil
+}
+
+// compare compares two values of the term column.
+func (t orderTerm) compare(a, b driver.Value) int {
+	switch {
+	case a == nil && b == nil:
+		return 0
+	case a == nil:
+		if t.nullsFirst {
+			return -1
+		}
+		return 1
+	case b == nil:
+		if t.nullsFirst {
+			return 1
+		}
+		return -1
+	}
+	c := compareValues(a, b)
+	if t.desc {
+		return -c
+	}
+	return c
+}
+
+// compareValues compares two non-NULL driver values. DECIMAL and NUMERIC
+// values, and the numbers read with the MySQL text protocol, are returned
+// as []byte, and are compared as numbers if both values are numbers.
+func compareValues(a, b driver.Value) int {
+	if c, ok := compareNumeric(a, b); ok {
+		return c
+	}
+	if ba, ok := a.([]byte); ok {
+		a = string(ba)
+	}
+	if bb, ok := b.([]byte); ok {
+		b = string(bb)
+	}
+	switch a := a.(type) {
+	case int64:
+		switch b := b.(type) {
+		case int64:
+			return cmpOrdered(a, b)
+		case float64:
+			return cmpOrdered(float64(a), b)
+		}
+	case float64:
+		switch b := b.(type) {
+		case float64:
+			return cmpOrdered(a, b)
+		case int64:
+			return cmpOrdered(a, float64(b))
+		}
+	case string:
+		if b, ok := b.(string); ok {
+			return strings.Compare(a, b)
+		}
+	case bool:
+		if b, ok := b.(bool); ok {
+			switch {
+			case a == b:
+				return 0
+			case !a:
+				return -1
+			default:
+				return 1
+			}
+		}
+	case time.Time:
+		if b, ok := b.(time.Time); ok {
+			return a.Compare(b)
+		}
+	}
+	return strings.Compare(fmt.Sprint(a), fmt.Sprint(b))
+}
+
+// compareNumeric compares two []byte values that hold numbers. It reports
+// false if one of the values is not a []byte number.
+func compareNumeric(a, b driver.Value) (int, bool) {
+	ba, ok1 := a.([]byte)
+	bb, ok2 := b.([]byte)
+	if !ok1 || !ok2 {
+		return 0, false
+	}
+	x, ok1 := new(big.Rat).SetString(string(ba))
+	y, ok2 := new(big.Rat).SetString(string(bb))
+	if !ok1 || !ok2 {
+		return 0, false
+	}
+	return x.Cmp(y), true
+}
+
+func cmpOrdered[T int64 | float64](a, b T) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// toInt64 converts the value of a COUNT column to int64.
+func toInt64(v driver.Value) (int64, error) {
+	switch v := v.(type) {
+	case int64:
+		return v, nil
+	case float64:
+		return int64(v), nil
+	case []byte:
+		return strconv.ParseInt(string(bytes.TrimSpace(v)), 10, 64)
+	case string:
+		return strconv.ParseInt(strings.TrimSpace(v), 10, 64)
+	default:
+		return 0, fmt.Errorf("unexpected count type %T", v)
+	}
+}
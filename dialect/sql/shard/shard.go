@@ -0,0 +1,150 @@
+package shard
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"reflect"
+
+	"github.com/syssam/velox/dialect/sql/internal/scatter"
+)
+
+// AnnotationName is the annotation name of Annotation.
+const AnnotationName = "Shard"
+
+// Annotation is a schema annotation that declares the shard key field of a
+// type. It requires the sql/shard feature in codegen:
+//
+//	func (User) Annotations() []schema.Annotation {
+//	    return []schema.Annotation{
+//	        shard.Key("tenant_id"),
+//	    }
+//	}
+type Annotation struct {
+	// Field is the name of the shard key field.
+	Field string `json:"field,omitempty"`
+}
+
+// Name implements the schema.Annotation interface.
+func (Annotation) Name() string {
+	return AnnotationName
+}
+
+// Key returns an Annotation that declares the given field as
+// the shard key of the type.
+func Key(field string) *Annotation {
+	return &Annotation{Field: field}
+}
+
+var (
+	// ErrNoShardKey is returned for statements whose context has no
+	// shard key, and is not marked for scatter-gather.
+	ErrNoShardKey = errors.New("shard: no shard key in context")
+
+	// ErrMixedShardKeys is returned for bulk creates whose builders
+	// have different shard keys.
+	ErrMixedShardKeys = errors.New("shard: bulk create with different shard keys")
+
+	// ErrNoMerge is returned for queries that run on all shards without
+	// a Merge, such as aggregations and projections, unless the context
+	// is marked with WithRawScatter.
+	ErrNoMerge = errors.New("shard: scatter query without a merge")
+)
+
+type (
+	keyCtxKey   struct{}
+	indexCtxKey struct{}
+	rawCtxKey   struct{}
+)
+
+// WithKey returns a new context that routes the statements to
+// the shard of the given key.
+func WithKey(ctx context.Context, key any) context.Context {
+	return context.WithValue(ctx, keyCtxKey{}, key)
+}
+
+// KeyFromContext returns the shard key stored in the context, if any.
+func KeyFromContext(ctx context.Context) (any, bool) {
+	key := ctx.Value(keyCtxKey{})
+	return key, key != nil
+}
+
+// WithIndex returns a new context that routes the statements to the shard
+// at the given index, regardless of the shard key.
+func WithIndex(ctx context.Context, i int) context.Context {
+	return context.WithValue(ctx, indexCtxKey{}, i)
+}
+
+// WithScatter returns a new context that runs the statements on all shards
+// when the context has no shard key. Queries whose rows are not merged by
+// sqlgraph, such as aggregations, fail with ErrNoMerge.
+func WithScatter(ctx context.Context) context.Context {
+	return scatter.With(ctx)
+}
+
+// WithRawScatter is like WithScatter, but the rows of the queries that have
+// no Merge are returned as is, one shard after the other. For example, the
+// rows of a GROUP BY query hold one group per shard.
+func WithRawScatter(ctx context.Context) context.Context {
+	return context.WithValue(scatter.With(ctx), rawCtxKey{}, true)
+}
+
+// IsScatter reports if the context is marked for scatter-gather.
+func IsScatter(ctx context.Context) bool {
+	return scatter.Is(ctx)
+}
+
+// isRawScatter reports if the context is marked with WithRawScatter.
+func isRawScatter(ctx context.Context) bool {
+	v, _ := ctx.Value(rawCtxKey{}).(bool)
+	return v
+}
+
+// WithBulkKey returns a new context that routes a bulk create to the shard
+// of the given keys, one per builder. It fails with ErrMixedShardKeys if the
+// keys differ. Unset keys (nil) are skipped, and the context is returned
+// unchanged if all keys are unset.
+func WithBulkKey(ctx context.Context, keys ...any) (context.Context, error) {
+	var key any
+	for _, k := range keys {
+		switch {
+		case k == nil:
+		case key == nil:
+			key = k
+		case !reflect.DeepEqual(key, k):
+			return nil, fmt.Errorf("%w: %v and %v", ErrMixedShardKeys, key, k)
+		}
+	}
+	if key == nil {
+		return ctx, nil
+	}
+	return WithKey(ctx, key), nil
+}
+
+// Strategy picks the shard of a key, given the number of shards.
+type Strategy func(key any, n int) (int, error)
+
+// Hash is the default Strategy. It picks the shard by the FNV-1a hash
+// of the key formatted with fmt.Sprint.
+func Hash(key any, n int) (int, error) {
+	h := fnv.New32a()
+	_, _ = fmt.Fprint(h, key)
+	return int(h.Sum32() % uint32(n)), nil
+}
+
+// Merge describes how the rows of a query that runs on all shards are
+// merged. It is attached to the context by sqlgraph for node queries,
+// edge queries and counts.
+type Merge = scatter.Merge
+
+// WithMerge returns a new context that holds the merge of the rows of
+// a scatter-gather query.
+func WithMerge(ctx context.Context, m *Merge) context.Context {
+	return scatter.WithMerge(ctx, m)
+}
+
+// MergeFromContext returns the Merge stored in the context, if any.
+func MergeFromContext(ctx context.Context) *Merge {
+	return scatter.MergeFrom(ctx)
+}
@@ -0,0 +1,271 @@
+package shard
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/syssam/velox/dialect"
+	"github.com/syssam/velox/dialect/sql"
+)
+
+// newShards returns n MySQL drivers backed by sqlmock.
+func newShards(t *testing.T, n int) ([]dialect.Driver, []sqlmock.Sqlmock) {
+	t.Helper()
+	var (
+		shards []dialect.Driver
+		mocks  []sqlmock.Sqlmock
+	)
+	for range n {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		shards = append(shards, sql.OpenDB(dialect.MySQL, db))
+		mocks = append(mocks, mock)
+	}
+	return shards, mocks
+}
+
+func expectationsWereMet(t *testing.T, mocks []sqlmock.Sqlmock) {
+	t.Helper()
+	for _, mock := range mocks {
+		require.NoError(t, mock.ExpectationsWereMet())
+	}
+}
+
+func TestNewDriver(t *testing.T) {
+	_, err := NewDriver(nil)
+	require.Error(t, err)
+
+	shards, _ := newShards(t, 1)
+	db, _, err := sqlmock.New()
+	require.NoError(t, err)
+	_, err = NewDriver(append(shards, sql.OpenDB(dialect.Postgres, db)))
+	require.ErrorContains(t, err, `dialect "postgres" of shard 1`)
+
+	drv, err := NewDriver(shards)
+	require.NoError(t, err)
+	require.Equal(t, dialect.MySQL, drv.Dialect())
+	require.Len(t, drv.Shards(), 1)
+}
+
+func TestDriver_Route(t *testing.T) {
+	shards, mocks := newShards(t, 2)
+	drv, err := NewDriver(shards, WithStrategy(func(key any, _ int) (int, error) {
+		return key.(int) % 2, nil
+	}))
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	mocks[1].ExpectExec(regexp.QuoteMeta("UPDATE `users` SET `age` = 1")).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	require.NoError(t, drv.Exec(WithKey(ctx, 3), "UPDATE `users` SET `age` = 1", []any{}, nil))
+
+	mocks[0].ExpectQuery(regexp.QuoteMeta("SELECT `id` FROM `users`")).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	rows := &sql.Rows{}
+	require.NoError(t, drv.Query(WithIndex(ctx, 0), "SELECT `id` FROM `users`", []any{}, rows))
+	require.NoError(t, rows.Close())
+
+	mocks[0].ExpectBegin()
+	mocks[0].ExpectCommit()
+	tx, err := drv.Tx(WithKey(ctx, 2))
+	require.NoError(t, err)
+	require.NoError(t, tx.Commit())
+
+	require.ErrorIs(t, drv.Exec(ctx, "DELETE FROM `users`", []any{}, nil), ErrNoShardKey)
+	require.ErrorIs(t, drv.Query(ctx, "SELECT 1", []any{}, &sql.Rows{}), ErrNoShardKey)
+	_, err = drv.Tx(ctx)
+	require.ErrorIs(t, err, ErrNoShardKey)
+	require.ErrorContains(t, drv.Query(WithIndex(ctx, 2), "SELECT 1", []any{}, &sql.Rows{}), "out of range")
+	expectationsWereMet(t, mocks)
+}
+
+func TestDriver_ScatterExec(t *testing.T) {
+	shards, mocks := newShards(t, 3)
+	drv, err := NewDriver(shards)
+	require.NoError(t, err)
+	for i, mock := range mocks {
+		mock.ExpectExec(regexp.QuoteMeta("DELETE FROM `users` WHERE `age` < ?")).
+			WithArgs(18).
+			WillReturnResult(sqlmock.NewResult(0, int64(i+1)))
+	}
+	var res sql.Result
+	ctx := WithScatter(context.Background())
+	require.NoError(t, drv.Exec(ctx, "DELETE FROM `users` WHERE `age` < ?", []any{18}, &res))
+	n, err := res.RowsAffected()
+	require.NoError(t, err)
+	require.EqualValues(t, 6, n)
+	_, err = res.LastInsertId()
+	require.Error(t, err)
+	expectationsWereMet(t, mocks)
+}
+
+func TestDriver_ScatterQuery(t *testing.T) {
+	shards, mocks := newShards(t, 2)
+	drv, err := NewDriver(shards)
+	require.NoError(t, err)
+	const query = "SELECT `id`, `name` FROM `users` ORDER BY `users`.`name`"
+	mocks[0].ExpectQuery(regexp.QuoteMeta(query)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(1, nil).AddRow(3, "b"))
+	mocks[1].ExpectQuery(regexp.QuoteMeta(query)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(2, "a").AddRow(4, "c"))
+
+	ctx := WithMerge(WithScatter(context.Background()), &Merge{Order: []string{"`users`.`name`"}})
+	rows := &sql.Rows{}
+	require.NoError(t, drv.Query(ctx, query, []any{}, rows))
+	var (
+		ids   []int
+		names []sql.NullString
+	)
+	for rows.Next() {
+		var (
+			id   int
+			name sql.NullString
+		)
+		require.NoError(t, rows.Scan(&id, &name))
+		ids, names = append(ids, id), append(names, name)
+	}
+	require.NoError(t, rows.Err())
+	require.NoError(t, rows.Close())
+	// NULL values are the smallest values on MySQL.
+	require.Equal(t, []int{1, 2, 3, 4}, ids)
+	require.False(t, names[0].Valid)
+	require.Equal(t, "a", names[1].String)
+	expectationsWereMet(t, mocks)
+}
+
+func TestDriver_ScatterQueryDecimal(t *testing.T) {
+	shards, mocks := newShards(t, 2)
+	drv, err := NewDriver(shards)
+	require.NoError(t, err)
+	const query = "SELECT `id`, `price` FROM `products` ORDER BY `products`.`price` DESC"
+	// DECIMAL values are returned as []byte.
+	mocks[0].ExpectQuery(regexp.QuoteMeta(query)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "price"}).AddRow(1, []byte("10.50")).AddRow(3, []byte("9.99")))
+	mocks[1].ExpectQuery(regexp.QuoteMeta(query)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "price"}).AddRow(2, []byte("100")).AddRow(4, []byte("-2.5")))
+
+	ctx := WithMerge(WithScatter(context.Background()), &Merge{Order: []string{"`products`.`price` DESC"}, Limit: 3})
+	rows := &sql.Rows{}
+	require.NoError(t, drv.Query(ctx, query, []any{}, rows))
+	var ids []int
+	for rows.Next() {
+		var (
+			id    int
+			price string
+		)
+		require.NoError(t, rows.Scan(&id, &price))
+		ids = append(ids, id)
+	}
+	require.NoError(t, rows.Err())
+	require.NoError(t, rows.Close())
+	require.Equal(t, []int{2, 1, 3}, ids)
+	expectationsWereMet(t, mocks)
+}
+
+func TestDriver_ScatterUnmergeableOrder(t *testing.T) {
+	shards, mocks := newShards(t, 2)
+	drv, err := NewDriver(shards)
+	require.NoError(t, err)
+	for _, mock := range mocks {
+		mock.ExpectQuery(regexp.QuoteMeta("SELECT `id` FROM `users` ORDER BY RAND()")).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	}
+	ctx := WithMerge(WithScatter(context.Background()), &Merge{Order: []string{"RAND()"}})
+	err = drv.Query(ctx, "SELECT `id` FROM `users` ORDER BY RAND()", []any{}, &sql.Rows{})
+	require.ErrorContains(t, err, `cannot merge rows ordered by expression "RAND()"`)
+	expectationsWereMet(t, mocks)
+}
+
+func TestDriver_ScatterQueryNoMerge(t *testing.T) {
+	shards, mocks := newShards(t, 2)
+	drv, err := NewDriver(shards)
+	require.NoError(t, err)
+	const query = "SELECT `age`, COUNT(*) FROM `users` GROUP BY `age`"
+	err = drv.Query(WithScatter(context.Background()), query, []any{}, &sql.Rows{})
+	require.ErrorIs(t, err, ErrNoMerge)
+	expectationsWereMet(t, mocks)
+
+	// The rows of raw scatter queries are concatenated.
+	mocks[0].ExpectQuery(regexp.QuoteMeta(query)).
+		WillReturnRows(sqlmock.NewRows([]string{"age", "count"}).AddRow(30, 1))
+	mocks[1].ExpectQuery(regexp.QuoteMeta(query)).
+		WillReturnRows(sqlmock.NewRows([]string{"age", "count"}).AddRow(30, 2))
+	rows := &sql.Rows{}
+	require.NoError(t, drv.Query(WithRawScatter(context.Background()), query, []any{}, rows))
+	var counts []int
+	for rows.Next() {
+		var age, n int
+		require.NoError(t, rows.Scan(&age, &n))
+		counts = append(counts, n)
+	}
+	require.NoError(t, rows.Err())
+	require.NoError(t, rows.Close())
+	require.Equal(t, []int{1, 2}, counts)
+	expectationsWereMet(t, mocks)
+}
+
+func TestParseOrderTerm(t *testing.T) {
+	columns := []string{"id", "name", "age"}
+	tests := []struct {
+		term, dialect string
+		want          orderTerm
+	}{
+		{term: "`users`.`name`", dialect: dialect.MySQL, want: orderTerm{index: 1, nullsFirst: true}},
+		{term: "`users`.`name` DESC", dialect: dialect.MySQL, want: orderTerm{index: 1, desc: true}},
+		{term: `"users"."age"`, dialect: dialect.Postgres, want: orderTerm{index: 2}},
+		{term: `"users"."age" DESC`, dialect: dialect.Postgres, want: orderTerm{index: 2, desc: true, nullsFirst: true}},
+		{term: `"age" NULLS FIRST`, dialect: dialect.Postgres, want: orderTerm{index: 2, nullsFirst: true}},
+		{term: "id", dialect: dialect.SQLite, want: orderTerm{index: 0, nullsFirst: true}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.term, func(t *testing.T) {
+			got, err := parseOrderTerm(tt.term, columns, tt.dialect)
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+	_, err := parseOrderTerm("`users`.`email`", columns, dialect.MySQL)
+	require.ErrorContains(t, err, `column "email" is not selected`)
+}
+
+func TestWithBulkKey(t *testing.T) {
+	ctx := context.Background()
+	got, err := WithBulkKey(ctx, nil, nil)
+	require.NoError(t, err)
+	_, ok := KeyFromContext(got)
+	require.False(t, ok)
+
+	got, err = WithBulkKey(ctx, "acme", nil, "acme")
+	require.NoError(t, err)
+	key, ok := KeyFromContext(got)
+	require.True(t, ok)
+	require.Equal(t, "acme", key)
+
+	_, err = WithBulkKey(ctx, "acme", "globex")
+	require.ErrorIs(t, err, ErrMixedShardKeys)
+
+	// Keys that are not comparable with == are compared by value.
+	got, err = WithBulkKey(ctx, []byte("acme"), []byte("acme"))
+	require.NoError(t, err)
+	key, ok = KeyFromContext(got)
+	require.True(t, ok)
+	require.Equal(t, []byte("acme"), key)
+	_, err = WithBulkKey(ctx, []byte("acme"), []byte("globex"))
+	require.ErrorIs(t, err, ErrMixedShardKeys)
+}
+
+func TestHash(t *testing.T) {
+	for _, key := range []any{1, "acme", int64(42)} {
+		i, err := Hash(key, 4)
+		require.NoError(t, err)
+		require.GreaterOrEqual(t, i, 0)
+		require.Less(t, i, 4)
+		j, err := Hash(key, 4)
+		require.NoError(t, err)
+		require.Equal(t, i, j, "Hash must be stable")
+	}
+}
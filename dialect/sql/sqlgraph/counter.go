@@ -0,0 +1,365 @@
+package sqlgraph
+
+import (
+	"context"
+	"database/sql/driver"
+	"fmt"
+	"slices"
+
+	"github.com/syssam/velox/dialect"
+	"github.com/syssam/velox/dialect/sql"
+)
+
+// CounterSpec holds the information of a counter cache: an integer column of
+// the Table that holds the number of rows in the RefTable whose RefColumn
+// foreign-key references them.
+//
+// The counters attached to a spec are kept in sync, in the transaction of the
+// spec, by the statements that insert, update or delete rows of the RefTable,
+// and by the O2M edges of the Table that link or unlink them. Rows that are
+// changed outside of the specs (e.g. raw SQL, or database ON DELETE actions)
+// are not counted, and RecountCounter should be used to repair the counter.
+type CounterSpec struct {
+	Table    string // parent table, e.g. "users".
+	Schema   string
+	Column   string // counter column, e.g. "posts_count".
+	IDColumn string // primary key of the parent table.
+
+	RefTable  string // dependents table, e.g. "posts".
+	RefSchema string
+	RefColumn string // foreign-key column of the dependents table, e.g. "user_posts".
+}
+
+// RecountCounter recomputes the counter of the parent rows that match the
+// predicate, or of all rows if it is nil, and returns the number of updated rows.
+//
+//	UPDATE users SET posts_count = (SELECT COUNT(*) FROM posts WHERE posts.user_posts = users.id)
+func RecountCounter(ctx context.Context, drv dialect.Driver, spec *CounterSpec, pred func(*sql.Selector)) (int, error) {
+	builder := sql.Dialect(drv.Dialect())
+	update := builder.Update(spec.Table).Schema(spec.Schema).Set(spec.Column, spec.count(builder))
+	if pred != nil {
+		selector := builder.Select().From(builder.Table(spec.Table).Schema(spec.Schema))
+		pred(selector)
+		update.FromSelect(selector)
+	}
+	if err := update.Err(); err != nil {
+		return 0, err
+	}
+	var (
+		res         sql.Result
+		query, args = update.Query()
+	)
+	if err := drv.Exec(ctx, query, args, &res); err != nil {
+		return 0, err
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+	return int(affected), nil
+}
+
+// count returns the expression that counts the dependents of a parent row. The
+// dependents table is aliased, as it may be the parent table (self-reference).
+func (c *CounterSpec) count(builder *sql.DialectBuilder) sql.Querier {
+	var (
+		parent = builder.Table(c.Table).Schema(c.Schema)
+		ref    = builder.Table(c.RefTable).Schema(c.RefSchema).As("t1")
+		count  = builder.Select(sql.Count("*")).From(ref).Where(sql.ColumnsEQ(ref.C(c.RefColumn), parent.C(c.IDColumn)))
+	)
+	return sql.ExprFunc(func(b *sql.Builder) {
+		b.Wrap(func(b *sql.Builder) { b.Join(count) })
+	})
+}
+
+// counterRef holds the number of dependents that reference a parent row.
+type counterRef struct {
+	id driver.Value
+	n  int
+}
+
+// refCounters returns the counters that count the rows of the given table.
+func refCounters(counters []*CounterSpec, table string) []*CounterSpec {
+	var cs []*CounterSpec
+	for _, c := range counters {
+		if c.RefTable == table {
+			cs = append(cs, c)
+		}
+	}
+	return cs
+}
+
+// counts reports if the counter counts the links of the edge.
+func (c *CounterSpec) counts(e *EdgeSpec) bool {
+	return c.RefTable == e.Table && len(e.Columns) > 0 && c.RefColumn == e.Columns[0]
+}
+
+// touches reports if the update spec changes the foreign-key column of the counter.
+func (u *UpdateSpec) touches(c *CounterSpec) bool {
+	if c.RefTable != u.Node.Table {
+		return false
+	}
+	for _, fs := range [][]*FieldSpec{u.Fields.Set, u.Fields.Add, u.Fields.Clear} {
+		if slices.ContainsFunc(fs, func(f *FieldSpec) bool { return f.Column == c.RefColumn }) {
+			return true
+		}
+	}
+	for _, es := range [][]*EdgeSpec{u.Edges.Add, u.Edges.Clear} {
+		for _, e := range es {
+			if (e.Rel == M2O || e.Rel == O2O && (e.Inverse || e.Bidi)) && len(e.Columns) > 0 && e.Columns[0] == c.RefColumn {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// touchedCounters returns the counters whose foreign-key column is changed by the update spec.
+func (u *UpdateSpec) touchedCounters() []*CounterSpec {
+	var cs []*CounterSpec
+	for _, c := range u.Counters {
+		if u.touches(c) {
+			cs = append(cs, c)
+		}
+	}
+	return cs
+}
+
+// countRefs returns the number of dependents that match the predicate (or
+// all dependents if it is nil), grouped by the parent row they reference.
+func (g *graph) countRefs(ctx context.Context, c *CounterSpec, schema string, pred func(*sql.Selector)) ([]counterRef, error) {
+	t := g.builder.Table(c.RefTable).Schema(schema)
+	selector := g.builder.Select(t.C(c.RefColumn), sql.Count("*")).From(t)
+	if pred != nil {
+		pred(selector)
+	}
+	selector.Where(sql.NotNull(t.C(c.RefColumn))).GroupBy(t.C(c.RefColumn))
+	if err := selector.Err(); err != nil {
+		return nil, err
+	}
+	rows := &sql.Rows{}
+	query, args := selector.Query()
+	if err := g.tx.Query(ctx, query, args, rows); err != nil {
+		return nil, fmt.Errorf("count references of table %s: %w", c.RefTable, err)
+	}
+	defer rows.Close()
+	var refs []counterRef
+	for rows.Next() {
+		var r counterRef
+		if err := rows.Scan(&r.id, &r.n); err != nil {
+			return nil, err
+		}
+		refs = append(refs, r)
+	}
+	return refs, rows.Err()
+}
+
+// matchRefs returns a predicate function that matches the rows of the predicate.
+func matchRefs(p *sql.Predicate) func(*sql.Selector) {
+	return func(s *sql.Selector) { s.Where(p) }
+}
+
+// updateCounter applies the difference between the references before and
+// after a change to the counter. Parent rows with the same difference are
+// updated in one statement:
+//
+//	UPDATE users SET posts_count = COALESCE(posts_count, 0) + ? WHERE id IN (...)
+func (g *graph) updateCounter(ctx context.Context, c *CounterSpec, before, after []counterRef) error {
+	var (
+		keys   []string
+		ids    = make(map[string]driver.Value)
+		deltas = make(map[string]int)
+	)
+	apply := func(refs []counterRef, sign int) {
+		for _, r := range refs {
+			k := fmt.Sprint(r.id)
+			if _, ok := ids[k]; !ok {
+				keys = append(keys, k)
+				ids[k] = r.id
+			}
+			deltas[k] += sign * r.n
+		}
+	}
+	apply(before, -1)
+	apply(after, 1)
+	var (
+		ns     []int
+		groups = make(map[int][]driver.Value)
+	)
+	for _, k := range keys {
+		n := deltas[k]
+		if n == 0 {
+			continue
+		}
+		if _, ok := groups[n]; !ok {
+			ns = append(ns, n)
+		}
+		groups[n] = append(groups[n], ids[k])
+	}
+	slices.Sort(ns)
+	for _, n := range ns {
+		query, args := g.builder.Update(c.Table).
+			Schema(c.Schema).
+			Add(c.Column, n).
+			Where(matchID(c.IDColumn, groups[n])).
+			Query()
+		if err := g.tx.Exec(ctx, query, args, nil); err != nil {
+			return fmt.Errorf("update counter %s.%s: %w", c.Table, c.Column, err)
+		}
+	}
+	return nil
+}
+
+// recountRefs recomputes the counter of the referenced parent rows. It is used
+// by upserts, as the row of a conflicting insert may have referenced another row.
+func (g *graph) recountRefs(ctx context.Context, c *CounterSpec, refs []counterRef) error {
+	if len(refs) == 0 {
+		return nil
+	}
+	ids := make([]driver.Value, len(refs))
+	for i, r := range refs {
+		ids[i] = r.id
+	}
+	query, args := g.builder.Update(c.Table).
+		Schema(c.Schema).
+		Set(c.Column, c.count(g.builder)).
+		Where(matchID(c.IDColumn, ids)).
+		Query()
+	if err := g.tx.Exec(ctx, query, args, nil); err != nil {
+		return fmt.Errorf("recount counter %s.%s: %w", c.Table, c.Column, err)
+	}
+	return nil
+}
+
+// insertedRefs returns the parent rows that are referenced by the inserted
+// rows, as collected from their column values.
+func insertedRefs(c *CounterSpec, values []map[string]driver.Value) []counterRef {
+	var (
+		refs []counterRef
+		idx  = make(map[string]int)
+	)
+	for _, v := range values {
+		id := v[c.RefColumn]
+		if id == nil {
+			continue
+		}
+		k := fmt.Sprint(id)
+		if i, ok := idx[k]; ok {
+			refs[i].n++
+			continue
+		}
+		idx[k] = len(refs)
+		refs = append(refs, counterRef{id: id, n: 1})
+	}
+	return refs
+}
+
+// upsertRefs holds the parent rows that are referenced by the rows an upsert
+// may update, before the insert. If all is true, the conflicting rows are not
+// known, and the counters are recounted for all parent rows.
+type upsertRefs struct {
+	all  bool
+	refs map[*CounterSpec][]counterRef
+}
+
+// upsertRefs returns the parent rows that are referenced by the rows that
+// conflict with the inserted rows on the columns of the conflict target. The
+// update of a conflicting row may move it to another parent, whose counter is
+// recounted as well. It returns nil if the insert is not an upsert, or if the
+// conflicting rows are not updated.
+func (g *graph) upsertRefs(ctx context.Context, counters []*CounterSpec, opts []sql.ConflictOption, values []map[string]driver.Value) (*upsertRefs, error) {
+	if len(opts) == 0 || len(counters) == 0 {
+		return nil, nil
+	}
+	columns, update := sql.ConflictTarget(opts...)
+	switch {
+	case !update:
+		return nil, nil
+	case len(columns) == 0:
+		return &upsertRefs{all: true}, nil
+	}
+	var ors []*sql.Predicate
+	for _, v := range values {
+		ands := make([]*sql.Predicate, 0, len(columns))
+		for _, c := range columns {
+			// NULL values do not conflict.
+			if v[c] == nil {
+				break
+			}
+			ands = append(ands, sql.EQ(c, v[c]))
+		}
+		if len(ands) == len(columns) {
+			ors = append(ors, sql.And(ands...))
+		}
+	}
+	up := &upsertRefs{refs: make(map[*CounterSpec][]counterRef)}
+	if len(ors) == 0 {
+		return up, nil
+	}
+	for _, c := range counters {
+		refs, err := g.countRefs(ctx, c, c.RefSchema, matchRefs(sql.Or(ors...)))
+		if err != nil {
+			return nil, err
+		}
+		up.refs[c] = refs
+	}
+	return up, nil
+}
+
+// recountAll recomputes the counter of all parent rows.
+func (g *graph) recountAll(ctx context.Context, c *CounterSpec) error {
+	query, args := g.builder.Update(c.Table).
+		Schema(c.Schema).
+		Set(c.Column, c.count(g.builder)).
+		Query()
+	if err := g.tx.Exec(ctx, query, args, nil); err != nil {
+		return fmt.Errorf("recount counter %s.%s: %w", c.Table, c.Column, err)
+	}
+	return nil
+}
+
+// insertCounters updates the counters of the rows that were inserted to the
+// table. Upserts recount the parent rows that are referenced by the inserted
+// rows, and by the rows they may have updated before the insert.
+func (g *graph) insertCounters(ctx context.Context, counters []*CounterSpec, values []map[string]driver.Value, upsert bool, up *upsertRefs) error {
+	for _, c := range counters {
+		refs := insertedRefs(c, values)
+		var err error
+		switch {
+		case up != nil && up.all:
+			err = g.recountAll(ctx, c)
+		case up != nil:
+			err = g.recountRefs(ctx, c, append(up.refs[c], refs...))
+		case len(refs) == 0:
+		case upsert:
+			err = g.recountRefs(ctx, c, refs)
+		default:
+			err = g.updateCounter(ctx, c, nil, refs)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// hasInsertRefs reports if one of the inserted rows is counted by the counters.
+func hasInsertRefs(counters []*CounterSpec, values []map[string]driver.Value) bool {
+	for _, c := range counters {
+		if len(insertedRefs(c, values)) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// edgeCounters returns the counters that count the dependents of the edge.
+func (g *graph) edgeCounters(e *EdgeSpec) []*CounterSpec {
+	var cs []*CounterSpec
+	for _, c := range g.counters {
+		if c.counts(e) {
+			cs = append(cs, c)
+		}
+	}
+	return cs
+}
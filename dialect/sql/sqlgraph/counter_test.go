@@ -0,0 +1,397 @@
+package sqlgraph
+
+import (
+	"context"
+	"database/sql/driver"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/syssam/velox/dialect"
+	"github.com/syssam/velox/dialect/sql"
+	"github.com/syssam/velox/schema/field"
+)
+
+// postsCount counts the posts of a user through the "user_posts" foreign-key.
+var postsCount = &CounterSpec{
+	Table:     "users",
+	Column:    "posts_count",
+	IDColumn:  "id",
+	RefTable:  "posts",
+	RefColumn: "user_posts",
+}
+
+func postsEdge(nodes ...driver.Value) *EdgeSpec {
+	return &EdgeSpec{
+		Rel:     O2M,
+		Table:   "posts",
+		Columns: []string{"user_posts"},
+		Target:  &EdgeTarget{Nodes: nodes, IDSpec: &FieldSpec{Column: "id", Type: field.TypeInt}},
+	}
+}
+
+func authorEdge(nodes ...driver.Value) *EdgeSpec {
+	return &EdgeSpec{
+		Rel:     M2O,
+		Inverse: true,
+		Table:   "posts",
+		Columns: []string{"user_posts"},
+		Target:  &EdgeTarget{Nodes: nodes, IDSpec: &FieldSpec{Column: "id", Type: field.TypeInt}},
+	}
+}
+
+func expectRefs(m sqlmock.Sqlmock, where string, args []driver.Value, refs ...[2]int) {
+	rows := sqlmock.NewRows([]string{"user_posts", "count"})
+	for _, r := range refs {
+		rows.AddRow(r[0], r[1])
+	}
+	m.ExpectQuery(escape("SELECT `posts`.`user_posts`, COUNT(*) FROM `posts` WHERE " + where + " AND `posts`.`user_posts` IS NOT NULL GROUP BY `posts`.`user_posts`")).
+		WithArgs(args...).
+		WillReturnRows(rows)
+}
+
+func TestCounterCreate(t *testing.T) {
+	t.Run("Dependent", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		mock.ExpectBegin()
+		mock.ExpectExec(escape("INSERT INTO `posts` (`title`, `user_posts`) VALUES (?, ?)")).
+			WithArgs("title", 1).
+			WillReturnResult(sqlmock.NewResult(10, 1))
+		mock.ExpectExec(escape("UPDATE `users` SET `posts_count` = COALESCE(`users`.`posts_count`, 0) + ? WHERE `id` = ?")).
+			WithArgs(1, 1).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+		err = CreateNode(context.Background(), sql.OpenDB(dialect.MySQL, db), &CreateSpec{
+			Table:    "posts",
+			ID:       &FieldSpec{Column: "id", Type: field.TypeInt},
+			Fields:   []*FieldSpec{{Column: "title", Type: field.TypeString, Value: "title"}},
+			Edges:    []*EdgeSpec{authorEdge(1)},
+			Counters: []*CounterSpec{postsCount},
+		})
+		require.NoError(t, err)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("NoReference", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		// Rows that do not reference a parent are not counted, and no transaction is needed.
+		mock.ExpectExec(escape("INSERT INTO `posts` (`title`) VALUES (?)")).
+			WithArgs("title").
+			WillReturnResult(sqlmock.NewResult(10, 1))
+		err = CreateNode(context.Background(), sql.OpenDB(dialect.MySQL, db), &CreateSpec{
+			Table:    "posts",
+			ID:       &FieldSpec{Column: "id", Type: field.TypeInt},
+			Fields:   []*FieldSpec{{Column: "title", Type: field.TypeString, Value: "title"}},
+			Counters: []*CounterSpec{postsCount},
+		})
+		require.NoError(t, err)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Parent", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		mock.ExpectBegin()
+		mock.ExpectExec(escape("INSERT INTO `users` (`name`) VALUES (?)")).
+			WithArgs("a8m").
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectExec(escape("UPDATE `posts` SET `user_posts` = ? WHERE `id` IN (?, ?) AND `user_posts` IS NULL")).
+			WithArgs(1, 2, 3).
+			WillReturnResult(sqlmock.NewResult(0, 2))
+		mock.ExpectExec(escape("UPDATE `users` SET `posts_count` = COALESCE(`users`.`posts_count`, 0) + ? WHERE `id` = ?")).
+			WithArgs(2, 1).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+		err = CreateNode(context.Background(), sql.OpenDB(dialect.MySQL, db), &CreateSpec{
+			Table:    "users",
+			ID:       &FieldSpec{Column: "id", Type: field.TypeInt},
+			Fields:   []*FieldSpec{{Column: "name", Type: field.TypeString, Value: "a8m"}},
+			Edges:    []*EdgeSpec{postsEdge(2, 3)},
+			Counters: []*CounterSpec{postsCount},
+		})
+		require.NoError(t, err)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Batch", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		mock.ExpectBegin()
+		mock.ExpectExec(escape("INSERT INTO `posts` (`title`, `user_posts`) VALUES (?, ?), (?, ?), (?, ?), (?, NULL)")).
+			WithArgs("a", 1, "b", 2, "c", 1, "d").
+			WillReturnResult(sqlmock.NewResult(10, 4))
+		// Parents with the same number of new dependents are updated together.
+		mock.ExpectExec(escape("UPDATE `users` SET `posts_count` = COALESCE(`users`.`posts_count`, 0) + ? WHERE `id` = ?")).
+			WithArgs(1, 2).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(escape("UPDATE `users` SET `posts_count` = COALESCE(`users`.`posts_count`, 0) + ? WHERE `id` = ?")).
+			WithArgs(2, 1).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+		nodes := make([]*CreateSpec, 0, 4)
+		for _, v := range [][2]any{{"a", 1}, {"b", 2}, {"c", 1}, {"d", nil}} {
+			node := &CreateSpec{
+				Table:    "posts",
+				ID:       &FieldSpec{Column: "id", Type: field.TypeInt},
+				Fields:   []*FieldSpec{{Column: "title", Type: field.TypeString, Value: v[0]}},
+				Counters: []*CounterSpec{postsCount},
+			}
+			if v[1] != nil {
+				node.Edges = []*EdgeSpec{authorEdge(v[1])}
+			}
+			nodes = append(nodes, node)
+		}
+		err = BatchCreate(context.Background(), sql.OpenDB(dialect.MySQL, db), &BatchCreateSpec{Nodes: nodes})
+		require.NoError(t, err)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Upsert", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		mock.ExpectBegin()
+		mock.ExpectExec(escape("INSERT INTO `posts` (`user_posts`, `id`) VALUES (?, ?) ON DUPLICATE KEY UPDATE `user_posts` = `posts`.`user_posts`, `id` = `posts`.`id`")).
+			WithArgs(1, 10).
+			WillReturnResult(sqlmock.NewResult(10, 1))
+		// The inserted row may have existed, and hence, the parent is recounted.
+		mock.ExpectExec(escape("UPDATE `users` SET `posts_count` = (SELECT COUNT(*) FROM `posts` AS `t1` WHERE `t1`.`user_posts` = `users`.`id`) WHERE `id` = ?")).
+			WithArgs(1).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+		err = CreateNode(context.Background(), sql.OpenDB(dialect.MySQL, db), &CreateSpec{
+			Table:      "posts",
+			ID:         &FieldSpec{Column: "id", Type: field.TypeInt, Value: 10},
+			Edges:      []*EdgeSpec{authorEdge(1)},
+			OnConflict: []sql.ConflictOption{sql.DoNothing()},
+			Counters:   []*CounterSpec{postsCount},
+		})
+		require.NoError(t, err)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("UpsertMove", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		mock.ExpectBegin()
+		// The conflicting row references another parent before the insert.
+		expectRefs(mock, "`id` = ?", []driver.Value{10}, [2]int{1, 1})
+		mock.ExpectExec(escape("INSERT INTO `posts` (`user_posts`, `id`) VALUES (?, ?) ON DUPLICATE KEY UPDATE `user_posts` = VALUES(`user_posts`), `id` = VALUES(`id`)")).
+			WithArgs(2, 10).
+			WillReturnResult(sqlmock.NewResult(10, 2))
+		mock.ExpectExec(escape("UPDATE `users` SET `posts_count` = (SELECT COUNT(*) FROM `posts` AS `t1` WHERE `t1`.`user_posts` = `users`.`id`) WHERE `id` IN (?, ?)")).
+			WithArgs(1, 2).
+			WillReturnResult(sqlmock.NewResult(0, 2))
+		mock.ExpectCommit()
+		err = CreateNode(context.Background(), sql.OpenDB(dialect.MySQL, db), &CreateSpec{
+			Table:      "posts",
+			ID:         &FieldSpec{Column: "id", Type: field.TypeInt, Value: 10},
+			Edges:      []*EdgeSpec{authorEdge(2)},
+			OnConflict: []sql.ConflictOption{sql.ConflictColumns("id"), sql.ResolveWithNewValues()},
+			Counters:   []*CounterSpec{postsCount},
+		})
+		require.NoError(t, err)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("UpsertNoTarget", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		mock.ExpectBegin()
+		mock.ExpectExec(escape("INSERT INTO `posts` (`title`, `user_posts`) VALUES (?, ?), (?, NULL) ON DUPLICATE KEY UPDATE `title` = VALUES(`title`), `user_posts` = VALUES(`user_posts`)")).
+			WithArgs("a", 1, "b").
+			WillReturnResult(sqlmock.NewResult(10, 2))
+		// The conflicting rows are unknown, and all parents are recounted.
+		mock.ExpectExec(escape("UPDATE `users` SET `posts_count` = (SELECT COUNT(*) FROM `posts` AS `t1` WHERE `t1`.`user_posts` = `users`.`id`)")).
+			WillReturnResult(sqlmock.NewResult(0, 2))
+		mock.ExpectCommit()
+		nodes := make([]*CreateSpec, 0, 2)
+		for _, v := range [][2]any{{"a", 1}, {"b", nil}} {
+			node := &CreateSpec{
+				Table:    "posts",
+				ID:       &FieldSpec{Column: "id", Type: field.TypeInt},
+				Fields:   []*FieldSpec{{Column: "title", Type: field.TypeString, Value: v[0]}},
+				Counters: []*CounterSpec{postsCount},
+			}
+			if v[1] != nil {
+				node.Edges = []*EdgeSpec{authorEdge(v[1])}
+			}
+			nodes = append(nodes, node)
+		}
+		err = BatchCreate(context.Background(), sql.OpenDB(dialect.MySQL, db), &BatchCreateSpec{
+			Nodes:      nodes,
+			OnConflict: []sql.ConflictOption{sql.ResolveWithNewValues()},
+		})
+		require.NoError(t, err)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestCounterUpdate(t *testing.T) {
+	t.Run("Node", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		mock.ExpectBegin()
+		expectRefs(mock, "`id` = ?", []driver.Value{10}, [2]int{1, 1})
+		mock.ExpectExec(escape("UPDATE `posts` SET `user_posts` = ? WHERE `id` = ?")).
+			WithArgs(2, 10).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		expectRefs(mock, "`id` = ?", []driver.Value{10}, [2]int{2, 1})
+		mock.ExpectExec(escape("UPDATE `users` SET `posts_count` = COALESCE(`users`.`posts_count`, 0) + ? WHERE `id` = ?")).
+			WithArgs(-1, 1).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(escape("UPDATE `users` SET `posts_count` = COALESCE(`users`.`posts_count`, 0) + ? WHERE `id` = ?")).
+			WithArgs(1, 2).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+		spec := NewUpdateSpec("posts", nil, &FieldSpec{Column: "id", Type: field.TypeInt, Value: 10})
+		spec.Edges.Clear = []*EdgeSpec{authorEdge()}
+		spec.Edges.Add = []*EdgeSpec{authorEdge(2)}
+		spec.Counters = []*CounterSpec{postsCount}
+		err = UpdateNode(context.Background(), sql.OpenDB(dialect.MySQL, db), spec)
+		require.NoError(t, err)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Untouched", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		// Updates that do not change the foreign-key do not count the references.
+		mock.ExpectExec(escape("UPDATE `posts` SET `title` = ?")).
+			WithArgs("title").
+			WillReturnResult(sqlmock.NewResult(0, 3))
+		spec := NewUpdateSpec("posts", nil, &FieldSpec{Column: "id", Type: field.TypeInt})
+		spec.SetField("title", field.TypeString, "title")
+		spec.Counters = []*CounterSpec{postsCount}
+		affected, err := UpdateNodes(context.Background(), sql.OpenDB(dialect.MySQL, db), spec)
+		require.NoError(t, err)
+		require.Equal(t, 3, affected)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Nodes", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		mock.ExpectBegin()
+		mock.ExpectQuery(escape("SELECT `id` FROM `posts` WHERE `title` = ?")).
+			WithArgs("title").
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(10).AddRow(20).AddRow(30))
+		expectRefs(mock, "`id` IN (?, ?, ?)", []driver.Value{10, 20, 30}, [2]int{1, 2}, [2]int{2, 1})
+		mock.ExpectExec(escape("UPDATE `posts` SET `user_posts` = NULL WHERE `id` IN (?, ?, ?)")).
+			WithArgs(10, 20, 30).
+			WillReturnResult(sqlmock.NewResult(0, 3))
+		expectRefs(mock, "`id` IN (?, ?, ?)", []driver.Value{10, 20, 30})
+		mock.ExpectExec(escape("UPDATE `users` SET `posts_count` = COALESCE(`users`.`posts_count`, 0) + ? WHERE `id` = ?")).
+			WithArgs(-2, 1).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(escape("UPDATE `users` SET `posts_count` = COALESCE(`users`.`posts_count`, 0) + ? WHERE `id` = ?")).
+			WithArgs(-1, 2).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+		spec := NewUpdateSpec("posts", nil, &FieldSpec{Column: "id", Type: field.TypeInt})
+		spec.ClearField("user_posts", field.TypeInt)
+		spec.Predicate = func(s *sql.Selector) { s.Where(sql.EQ("title", "title")) }
+		spec.Counters = []*CounterSpec{postsCount}
+		affected, err := UpdateNodes(context.Background(), sql.OpenDB(dialect.MySQL, db), spec)
+		require.NoError(t, err)
+		require.Equal(t, 3, affected)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("ParentEdges", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		mock.ExpectBegin()
+		expectRefs(mock, "(`id` = ? AND `user_posts` = ?)", []driver.Value{2, 1}, [2]int{1, 1})
+		mock.ExpectExec(escape("UPDATE `users` SET `posts_count` = COALESCE(`users`.`posts_count`, 0) + ? WHERE `id` = ?")).
+			WithArgs(-1, 1).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(escape("UPDATE `posts` SET `user_posts` = NULL WHERE (`id` = ? AND `user_posts` = ?)")).
+			WithArgs(2, 1).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(escape("UPDATE `posts` SET `user_posts` = ? WHERE `id` IN (?, ?) AND `user_posts` IS NULL")).
+			WithArgs(1, 3, 4).
+			WillReturnResult(sqlmock.NewResult(0, 2))
+		mock.ExpectExec(escape("UPDATE `users` SET `posts_count` = COALESCE(`users`.`posts_count`, 0) + ? WHERE `id` = ?")).
+			WithArgs(2, 1).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+		spec := NewUpdateSpec("users", nil, &FieldSpec{Column: "id", Type: field.TypeInt, Value: 1})
+		spec.Edges.Clear = []*EdgeSpec{postsEdge(2)}
+		spec.Edges.Add = []*EdgeSpec{postsEdge(3, 4)}
+		spec.Counters = []*CounterSpec{postsCount}
+		err = UpdateNode(context.Background(), sql.OpenDB(dialect.MySQL, db), spec)
+		require.NoError(t, err)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Batch", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		mock.ExpectBegin()
+		mock.ExpectExec(escape("UPDATE `posts` SET `title` = CASE `id` WHEN ? THEN ? ELSE `title` END WHERE `id` = ?")).
+			WithArgs(10, "a", 10).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		// Nodes that change a counted foreign-key are updated one by one.
+		expectRefs(mock, "`id` = ?", []driver.Value{20})
+		mock.ExpectExec(escape("UPDATE `posts` SET `user_posts` = ? WHERE `id` = ?")).
+			WithArgs(1, 20).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		expectRefs(mock, "`id` = ?", []driver.Value{20}, [2]int{1, 1})
+		mock.ExpectExec(escape("UPDATE `users` SET `posts_count` = COALESCE(`users`.`posts_count`, 0) + ? WHERE `id` = ?")).
+			WithArgs(1, 1).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+		n1 := NewUpdateSpec("posts", nil, &FieldSpec{Column: "id", Type: field.TypeInt, Value: 10})
+		n1.SetField("title", field.TypeString, "a")
+		n2 := NewUpdateSpec("posts", nil, &FieldSpec{Column: "id", Type: field.TypeInt, Value: 20})
+		n2.SetField("user_posts", field.TypeInt, 1)
+		n1.Counters, n2.Counters = []*CounterSpec{postsCount}, []*CounterSpec{postsCount}
+		err = BatchUpdate(context.Background(), sql.OpenDB(dialect.MySQL, db), &BatchUpdateSpec{Nodes: []*UpdateSpec{n1, n2}})
+		require.NoError(t, err)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestCounterDelete(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	mock.ExpectBegin()
+	expectRefs(mock, "`posts`.`title` = ?", []driver.Value{"title"}, [2]int{1, 2}, [2]int{2, 2}, [2]int{3, 1})
+	mock.ExpectExec(escape("DELETE FROM `posts` WHERE `posts`.`title` = ?")).
+		WithArgs("title").
+		WillReturnResult(sqlmock.NewResult(0, 5))
+	mock.ExpectExec(escape("UPDATE `users` SET `posts_count` = COALESCE(`users`.`posts_count`, 0) + ? WHERE `id` IN (?, ?)")).
+		WithArgs(-2, 1, 2).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectExec(escape("UPDATE `users` SET `posts_count` = COALESCE(`users`.`posts_count`, 0) + ? WHERE `id` = ?")).
+		WithArgs(-1, 3).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	affected, err := DeleteNodes(context.Background(), sql.OpenDB(dialect.MySQL, db), &DeleteSpec{
+		Node: &NodeSpec{Table: "posts", ID: &FieldSpec{Column: "id", Type: field.TypeInt}},
+		Predicate: func(s *sql.Selector) {
+			s.Where(sql.EQ(s.C("title"), "title"))
+		},
+		Counters: []*CounterSpec{postsCount},
+	})
+	require.NoError(t, err)
+	require.Equal(t, 5, affected)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRecountCounter(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	mock.ExpectExec(escape("UPDATE `users` SET `posts_count` = (SELECT COUNT(*) FROM `posts` AS `t1` WHERE `t1`.`user_posts` = `users`.`id`) WHERE `users`.`name` = ?")).
+		WithArgs("a8m").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	affected, err := RecountCounter(context.Background(), sql.OpenDB(dialect.MySQL, db), postsCount, func(s *sql.Selector) {
+		s.Where(sql.EQ(s.C("name"), "a8m"))
+	})
+	require.NoError(t, err)
+	require.Equal(t, 1, affected)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
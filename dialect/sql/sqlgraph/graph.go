@@ -14,6 +14,7 @@ import (
 
 	"github.com/syssam/velox/dialect"
 	"github.com/syssam/velox/dialect/sql"
+	"github.com/syssam/velox/dialect/sql/internal/scatter"
 	"github.com/syssam/velox/schema/field"
 )
 
@@ -710,6 +711,10 @@ type (
 		//	}
 		//
 		OnConflict []sql.ConflictOption
+
+		// Counters holds the counter caches that count the rows of
+		// the table, or the dependents of its edges. See CounterSpec.
+		Counters []*CounterSpec
 	}
 
 	// BatchCreateSpec holds the information for creating
@@ -747,7 +752,7 @@ func (u *CreateSpec) SetField(column string, t field.Type, value driver.Value) {
 // CreateNode applies the CreateSpec on the graph. The operation creates a new
 // record in the database, and connects it to other nodes specified in spec.Edges.
 func CreateNode(ctx context.Context, drv dialect.Driver, spec *CreateSpec) error {
-	gr := graph{tx: drv, builder: sql.Dialect(drv.Dialect())}
+	gr := graph{tx: drv, builder: sql.Dialect(drv.Dialect()), counters: spec.Counters}
 	cr := &creator{CreateSpec: spec, graph: gr}
 	return cr.node(ctx, drv)
 }
@@ -755,6 +760,9 @@ func CreateNode(ctx context.Context, drv dialect.Driver, spec *CreateSpec) error
 // BatchCreate applies the BatchCreateSpec on the graph.
 func BatchCreate(ctx context.Context, drv dialect.Driver, spec *BatchCreateSpec) error {
 	gr := graph{tx: drv, builder: sql.Dialect(drv.Dialect())}
+	if len(spec.Nodes) > 0 {
+		gr.counters = spec.Nodes[0].Counters
+	}
 	cr := &batchCreator{BatchCreateSpec: spec, graph: gr}
 	return cr.nodes(ctx, drv)
 }
@@ -784,6 +792,10 @@ type (
 
 		ScanValues func(columns []string) ([]any, error)
 		Assign     func(columns []string, values []any) error
+
+		// Counters holds the counter caches that count the rows of
+		// the table, or the dependents of its edges. See CounterSpec.
+		Counters []*CounterSpec
 	}
 )
 
@@ -843,7 +855,7 @@ func UpdateNode(ctx context.Context, drv dialect.Driver, spec *UpdateSpec) error
 	if err != nil {
 		return err
 	}
-	gr := graph{tx: tx, builder: sql.Dialect(drv.Dialect())}
+	gr := graph{tx: tx, builder: sql.Dialect(drv.Dialect()), counters: spec.Counters}
 	cr := &updater{UpdateSpec: spec, graph: gr}
 	if err := cr.node(ctx, tx); err != nil {
 		return rollback(tx, err)
@@ -853,7 +865,7 @@ func UpdateNode(ctx context.Context, drv dialect.Driver, spec *UpdateSpec) error
 
 // UpdateNodes applies the UpdateSpec on a set of nodes in the graph.
 func UpdateNodes(ctx context.Context, drv dialect.Driver, spec *UpdateSpec) (int, error) {
-	gr := graph{tx: drv, builder: sql.Dialect(drv.Dialect())}
+	gr := graph{tx: drv, builder: sql.Dialect(drv.Dialect()), counters: spec.Counters}
 	cr := &updater{UpdateSpec: spec, graph: gr}
 	return cr.nodes(ctx, drv)
 }
@@ -874,6 +886,10 @@ func (e *NotFoundError) Error() string {
 type DeleteSpec struct {
 	Node      *NodeSpec
 	Predicate func(*sql.Selector)
+
+	// Counters holds the counter caches that count
+	// the rows of the table. See CounterSpec.
+	Counters []*CounterSpec
 }
 
 // NewDeleteSpec creates a new node deletion spec.
@@ -881,20 +897,56 @@ func NewDeleteSpec(table string, id *FieldSpec) *DeleteSpec {
 	return &DeleteSpec{Node: &NodeSpec{Table: table, ID: id}}
 }
 
-// DeleteNodes applies the DeleteSpec on the graph.
+// DeleteNodes applies the DeleteSpec on the graph. In case the rows of the
+// table are counted by one of the spec counters, the counters are updated
+// in the transaction of the deletion.
 func DeleteNodes(ctx context.Context, drv dialect.Driver, spec *DeleteSpec) (int, error) {
+	gr := graph{tx: drv, builder: sql.Dialect(drv.Dialect())}
+	counters := refCounters(spec.Counters, spec.Node.Table)
+	if len(counters) == 0 {
+		return gr.deleteNodes(ctx, spec)
+	}
+	tx, err := drv.Tx(ctx)
+	if err != nil {
+		return 0, err
+	}
+	gr.tx = tx
+	affected, err := func() (int, error) {
+		refs := make([][]counterRef, len(counters))
+		for i, c := range counters {
+			if refs[i], err = gr.countRefs(ctx, c, spec.Node.Schema, spec.Predicate); err != nil {
+				return 0, err
+			}
+		}
+		affected, err := gr.deleteNodes(ctx, spec)
+		if err != nil {
+			return 0, err
+		}
+		for i, c := range counters {
+			if err := gr.updateCounter(ctx, c, refs[i], nil); err != nil {
+				return 0, err
+			}
+		}
+		return affected, nil
+	}()
+	if err != nil {
+		return 0, rollback(tx, err)
+	}
+	return affected, tx.Commit()
+}
+
+func (g *graph) deleteNodes(ctx context.Context, spec *DeleteSpec) (int, error) {
 	var (
-		res     sql.Result
-		builder = sql.Dialect(drv.Dialect())
+		res      sql.Result
+		selector = g.builder.Select().
+				From(g.builder.Table(spec.Node.Table).Schema(spec.Node.Schema)).
+				WithContext(ctx)
 	)
-	selector := builder.Select().
-		From(builder.Table(spec.Node.Table).Schema(spec.Node.Schema)).
-		WithContext(ctx)
 	if pred := spec.Predicate; pred != nil {
 		pred(selector)
 	}
-	query, args := builder.Delete(spec.Node.Table).Schema(spec.Node.Schema).FromSelect(selector).Query()
-	if err := drv.Exec(ctx, query, args, &res); err != nil {
+	query, args := g.builder.Delete(spec.Node.Table).Schema(spec.Node.Schema).FromSelect(selector).Query()
+	if err := g.tx.Exec(ctx, query, args, &res); err != nil {
 		return 0, err
 	}
 	affected, err := res.RowsAffected()
@@ -972,6 +1024,10 @@ func QueryEdges(ctx context.Context, drv dialect.Driver, spec *EdgeQuerySpec) er
 	}
 	rows := &sql.Rows{}
 	query, args := selector.Query()
+	// The edges of all shards are concatenated.
+	if scatter.Is(ctx) {
+		ctx = scatter.WithMerge(ctx, &scatter.Merge{})
+	}
 	if err := drv.Query(ctx, query, args, rows); err != nil {
 		return err
 	}
@@ -1000,6 +1056,9 @@ func (q *query) nodes(ctx context.Context, drv dialect.Driver) error {
 		return err
 	}
 	query, args := selector.Query()
+	if scatter.Is(ctx) {
+		ctx = scatter.WithMerge(ctx, ScatterMerge(selector))
+	}
 	if err = drv.Query(ctx, query, args, rows); err != nil {
 		return err
 	}
@@ -1055,6 +1114,9 @@ func (q *query) count(ctx context.Context, drv dialect.Driver) (int, error) {
 		selector.Count(columns...)
 	}
 	query, args := selector.Query()
+	if scatter.Is(ctx) {
+		ctx = scatter.WithMerge(ctx, &scatter.Merge{Count: true})
+	}
 	if err := drv.Query(ctx, query, args, rows); err != nil {
 		return 0, err
 	}
@@ -1062,6 +1124,23 @@ func (q *query) count(ctx context.Context, drv dialect.Driver) (int, error) {
 	return sql.ScanInt(rows)
 }
 
+// ScatterMerge returns the shard.Merge of the rows of a node query that
+// runs on all shards. The offset is applied to the merged rows, so every
+// shard returns the first Offset+Limit rows. It is used by QueryNodes and
+// by the typed scanners of the generated queries.
+func ScatterMerge(selector *sql.Selector) *scatter.Merge {
+	limit, offset := selector.LimitOffset()
+	m := &scatter.Merge{Order: selector.OrderTerms(), Limit: limit, Offset: offset}
+	if offset != 0 {
+		n := math.MaxInt32
+		if limit != 0 && limit < math.MaxInt32-offset {
+			n = limit + offset
+		}
+		m.Query, m.Args = selector.Clone().Offset(0).Limit(n).Query()
+	}
+	return m
+}
+
 func (q *query) selector(ctx context.Context) (*sql.Selector, error) {
 	selector := q.builder.
 		Select().
@@ -1140,6 +1219,16 @@ func (u *updater) node(ctx context.Context, tx dialect.ExecQuerier) error {
 	if err := update.Err(); err != nil {
 		return err
 	}
+	// The references of the counters whose foreign-key
+	// is changed, before and after the update.
+	counters := u.touchedCounters()
+	refs := make([][]counterRef, len(counters))
+	for i, c := range counters {
+		var err error
+		if refs[i], err = u.countRefs(ctx, c, u.Node.Schema, matchRefs(idp)); err != nil {
+			return err
+		}
+	}
 	if !update.Empty() {
 		var res sql.Result
 		query, args := update.Query()
@@ -1158,6 +1247,15 @@ func (u *updater) node(ctx context.Context, tx dialect.ExecQuerier) error {
 			}
 		}
 	}
+	for i, c := range counters {
+		after, err := u.countRefs(ctx, c, u.Node.Schema, matchRefs(idp))
+		if err != nil {
+			return err
+		}
+		if err := u.updateCounter(ctx, c, refs[i], after); err != nil {
+			return err
+		}
+	}
 	if id != nil {
 		// Not an edge schema.
 		if err := u.setExternalEdges(ctx, []driver.Value{id}, addEdges, clearEdges); err != nil {
@@ -1187,7 +1285,8 @@ func (u *updater) nodes(ctx context.Context, drv dialect.Driver) (int, error) {
 	var (
 		addEdges   = EdgeSpecs(u.Edges.Add).GroupRel()
 		clearEdges = EdgeSpecs(u.Edges.Clear).GroupRel()
-		multiple   = hasExternalEdges(addEdges, clearEdges)
+		counters   = u.touchedCounters()
+		multiple   = hasExternalEdges(addEdges, clearEdges) || len(counters) > 0
 		update     = u.builder.Update(u.Node.Table).Schema(u.Node.Schema)
 		selector   = u.builder.Select().
 				From(u.builder.Table(u.Node.Table).Schema(u.Node.Schema)).
@@ -1247,12 +1346,27 @@ func (u *updater) nodes(ctx context.Context, drv dialect.Driver) (int, error) {
 			return 0, nil
 		}
 		update.Where(matchID(u.Node.ID.Column, ids))
+		refs := make([][]counterRef, len(counters))
+		for i, c := range counters {
+			if refs[i], qerr = u.countRefs(ctx, c, u.Node.Schema, matchRefs(matchID(u.Node.ID.Column, ids))); qerr != nil {
+				return 0, qerr
+			}
+		}
 		// In case of multi statement update, that change can
 		// affect more than 1 table, and therefore, we return
 		// the list of ids as number of affected records.
 		if _, qerr = u.updateTable(ctx, update); qerr != nil {
 			return 0, qerr
 		}
+		for i, c := range counters {
+			after, qerr := u.countRefs(ctx, c, u.Node.Schema, matchRefs(matchID(u.Node.ID.Column, ids)))
+			if qerr != nil {
+				return 0, qerr
+			}
+			if qerr = u.updateCounter(ctx, c, refs[i], after); qerr != nil {
+				return 0, qerr
+			}
+		}
 		if qerr = u.setExternalEdges(ctx, ids, addEdges, clearEdges); qerr != nil {
 			return 0, qerr
 		}
@@ -1395,13 +1509,21 @@ type creator struct {
 
 func (c *creator) node(ctx context.Context, drv dialect.Driver) error {
 	var (
-		edges  = EdgeSpecs(c.Edges).GroupRel()
-		insert = c.builder.Insert(c.Table).Schema(c.Schema).Default()
+		edges    = EdgeSpecs(c.Edges).GroupRel()
+		insert   = c.builder.Insert(c.Table).Schema(c.Schema).Default()
+		values   = make(map[string]driver.Value)
+		counters = refCounters(c.Counters, c.Table)
 	)
-	if err := c.setTableColumns(insert, edges); err != nil {
+	err := setTableColumns(c.Fields, edges, func(column string, value driver.Value) {
+		insert.Set(column, value)
+		values[column] = value
+	})
+	if err != nil {
 		return err
 	}
-	tx, err := c.mayTx(ctx, drv, edges)
+	inserted := []map[string]driver.Value{values}
+	upsert := len(c.OnConflict) > 0 && len(counters) > 0
+	tx, err := c.mayTx(ctx, drv, edges, upsert || hasInsertRefs(counters, inserted))
 	if err != nil {
 		return err
 	}
@@ -1416,9 +1538,20 @@ func (c *creator) node(ctx context.Context, drv dialect.Driver) error {
 			}
 			return c.tx.Exec(ctx, query, args, nil)
 		}
+		// The ID is set by insert, and may be the conflict target.
+		if upsert && c.ID.Value != nil {
+			values[c.ID.Column] = c.ID.Value
+		}
+		up, err := c.upsertRefs(ctx, counters, c.OnConflict, inserted)
+		if err != nil {
+			return err
+		}
 		if err := c.insert(ctx, insert); err != nil {
 			return err
 		}
+		if err := c.insertCounters(ctx, counters, inserted, len(c.OnConflict) > 0, up); err != nil {
+			return err
+		}
 		if err := c.addM2MEdges(ctx, []driver.Value{c.ID.Value}, edges[M2M]); err != nil {
 			return err
 		}
@@ -1430,8 +1563,8 @@ func (c *creator) node(ctx context.Context, drv dialect.Driver) error {
 }
 
 // mayTx opens a new transaction if the create operation spans across multiple statements.
-func (c *creator) mayTx(ctx context.Context, drv dialect.Driver, edges map[Rel][]*EdgeSpec) (dialect.Tx, error) {
-	if !hasExternalEdges(edges, nil) {
+func (c *creator) mayTx(ctx context.Context, drv dialect.Driver, edges map[Rel][]*EdgeSpec, counted bool) (dialect.Tx, error) {
+	if !hasExternalEdges(edges, nil) && !counted {
 		return dialect.NopTx(drv), nil
 	}
 	tx, err := drv.Tx(ctx)
@@ -1442,14 +1575,6 @@ func (c *creator) mayTx(ctx context.Context, drv dialect.Driver, edges map[Rel][
 	return tx, nil
 }
 
-// setTableColumns sets the table columns and foreign_keys used in insert.
-func (c *creator) setTableColumns(insert *sql.InsertBuilder, edges map[Rel][]*EdgeSpec) error {
-	err := setTableColumns(c.Fields, edges, func(column string, value driver.Value) {
-		insert.Set(column, value)
-	})
-	return err
-}
-
 // insert a node to its table and sets its ID if it was not provided by the user.
 func (c *creator) insert(ctx context.Context, insert *sql.InsertBuilder) error {
 	c.ensureConflict(insert)
@@ -1561,7 +1686,9 @@ func (c *batchCreator) nodes(ctx context.Context, drv dialect.Driver) error {
 		}
 		insert.Values(vs...)
 	}
-	tx, err := c.mayTx(ctx, drv)
+	counters := refCounters(c.Nodes[0].Counters, c.Nodes[0].Table)
+	upsert := len(c.OnConflict) > 0 && len(counters) > 0
+	tx, err := c.mayTx(ctx, drv, upsert || hasInsertRefs(counters, values))
 	if err != nil {
 		return err
 	}
@@ -1574,9 +1701,16 @@ func (c *batchCreator) nodes(ctx context.Context, drv dialect.Driver) error {
 			query, args := insert.Query()
 			return tx.Exec(ctx, query, args, nil)
 		}
+		up, err := c.upsertRefs(ctx, counters, c.OnConflict, values)
+		if err != nil {
+			return err
+		}
 		if err := c.batchInsert(ctx, tx, insert); err != nil {
 			return fmt.Errorf("insert nodes to table %q: %w", c.Nodes[0].Table, err)
 		}
+		if err := c.insertCounters(ctx, counters, values, len(c.OnConflict) > 0, up); err != nil {
+			return err
+		}
 		if err := c.batchAddM2M(ctx, c.BatchCreateSpec); err != nil {
 			return err
 		}
@@ -1596,8 +1730,8 @@ func (c *batchCreator) nodes(ctx context.Context, drv dialect.Driver) error {
 }
 
 // mayTx opens a new transaction if the create operation spans across multiple statements.
-func (c *batchCreator) mayTx(ctx context.Context, drv dialect.Driver) (dialect.Tx, error) {
-	needsTx := slices.ContainsFunc(c.Nodes, func(node *CreateSpec) bool {
+func (c *batchCreator) mayTx(ctx context.Context, drv dialect.Driver, counted bool) (dialect.Tx, error) {
+	needsTx := counted || slices.ContainsFunc(c.Nodes, func(node *CreateSpec) bool {
 		return slices.ContainsFunc(node.Edges, isExternalEdge)
 	})
 	if needsTx {
@@ -1658,6 +1792,9 @@ func (es EdgeSpecs) FilterRel(r Rel) EdgeSpecs {
 type graph struct {
 	tx      dialect.ExecQuerier
 	builder *sql.DialectBuilder
+	// counters that count the dependents of
+	// the O2M edges. See CounterSpec.
+	counters []*CounterSpec
 }
 
 func (g *graph) clearM2MEdges(ctx context.Context, ids []driver.Value, edges EdgeSpecs) error {
@@ -1809,6 +1946,15 @@ func (g *graph) clearFKEdges(ctx context.Context, ids []driver.Value, edges []*E
 		if nodes := edge.Target.Nodes; len(nodes) > 0 {
 			pred = matchIDs(edge.Target.IDSpec.Column, edge.Target.Nodes, edge.Columns[0], ids)
 		}
+		for _, c := range g.edgeCounters(edge) {
+			refs, err := g.countRefs(ctx, c, edge.Schema, matchRefs(pred))
+			if err != nil {
+				return err
+			}
+			if err := g.updateCounter(ctx, c, refs, nil); err != nil {
+				return err
+			}
+		}
 		query, args := g.builder.Update(edge.Table).
 			SetNull(edge.Columns[0]).
 			Where(pred).
@@ -1855,6 +2001,11 @@ func (g *graph) addFKEdges(ctx context.Context, ids []driver.Value, edges []*Edg
 		if ids := edge.Target.Nodes; int(affected) < len(ids) {
 			return &ConstraintError{msg: fmt.Sprintf("one of %v is already connected to a different %s", ids, edge.Columns[0])}
 		}
+		for _, c := range g.edgeCounters(edge) {
+			if err := g.updateCounter(ctx, c, nil, []counterRef{{id: id, n: int(affected)}}); err != nil {
+				return err
+			}
+		}
 	}
 	return nil
 }
@@ -11,6 +11,7 @@ import (
 
 	"github.com/syssam/velox/dialect"
 	"github.com/syssam/velox/dialect/sql"
+	"github.com/syssam/velox/dialect/sql/shard"
 	"github.com/syssam/velox/schema/field"
 
 	"github.com/DATA-DOG/go-sqlmock"
@@ -2483,6 +2484,87 @@ func TestQueryNodesSchema(t *testing.T) {
 	require.Equal(t, &user{id: 3, age: 30, name: "a8m", edges: struct{ fk1, fk2 int }{1, 1}}, users[2])
 }
 
+func TestQueryNodesScatter(t *testing.T) {
+	var (
+		shards []dialect.Driver
+		mocks  []sqlmock.Sqlmock
+	)
+	for range 2 {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		shards = append(shards, sql.OpenDB(dialect.MySQL, db))
+		mocks = append(mocks, mock)
+	}
+	// Every shard returns its first Offset+Limit rows, and the merged
+	// rows are ordered, offset and limited.
+	for i, rows := range [][]int{{1, 4, 5}, {2, 3, 6}} {
+		r := sqlmock.NewRows([]string{"id", "age"})
+		for _, id := range rows {
+			r.AddRow(id, id*10)
+		}
+		mocks[i].ExpectQuery(escape("SELECT `users`.`id`, `users`.`age` FROM `users` ORDER BY `users`.`age` DESC LIMIT 3 OFFSET 0")).
+			WillReturnRows(r)
+		mocks[i].ExpectQuery(escape("SELECT COUNT(`users`.`id`) FROM `users`")).
+			WillReturnRows(sqlmock.NewRows([]string{"COUNT"}).AddRow(3))
+		mocks[i].ExpectQuery(escape("SELECT `group_id`, `user_id` FROM `user_groups`")).
+			WillReturnRows(sqlmock.NewRows([]string{"group_id", "user_id"}).AddRow(i+1, rows[0]))
+	}
+	drv, err := shard.NewDriver(shards)
+	require.NoError(t, err)
+	var (
+		users []*user
+		spec  = &QuerySpec{
+			Node: &NodeSpec{
+				Table:   "users",
+				Columns: []string{"id", "age"},
+				ID:      &FieldSpec{Column: "id", Type: field.TypeInt},
+			},
+			Limit:  2,
+			Offset: 1,
+			Order:  sql.OrderByField("age", sql.OrderDesc()).ToFunc(),
+			ScanValues: func(columns []string) ([]any, error) {
+				u := &user{}
+				users = append(users, u)
+				return u.values(columns)
+			},
+			Assign: func(columns []string, values []any) error {
+				return users[len(users)-1].assign(columns, values)
+			},
+		}
+	)
+	err = QueryNodes(context.Background(), drv, spec)
+	require.ErrorIs(t, err, shard.ErrNoShardKey)
+
+	ctx := shard.WithScatter(context.Background())
+	require.NoError(t, QueryNodes(ctx, drv, spec))
+	require.Equal(t, []*user{{id: 5, age: 50}, {id: 4, age: 40}}, users)
+
+	spec.Node.Columns = nil
+	spec.Limit, spec.Offset = 0, 0
+	n, err := CountNodes(ctx, drv, spec)
+	require.NoError(t, err)
+	require.Equal(t, 6, n)
+
+	// The edges of all shards are concatenated.
+	var edges [][]int64
+	err = QueryEdges(ctx, drv, &EdgeQuerySpec{
+		Edge: &EdgeSpec{Inverse: true, Table: "user_groups", Columns: []string{"user_id", "group_id"}},
+		ScanValues: func() [2]any {
+			return [2]any{&sql.NullInt64{}, &sql.NullInt64{}}
+		},
+		Assign: func(out, in any) error {
+			o, i := out.(*sql.NullInt64), in.(*sql.NullInt64)
+			edges = append(edges, []int64{o.Int64, i.Int64})
+			return nil
+		},
+	})
+	require.NoError(t, err)
+	require.Equal(t, [][]int64{{1, 1}, {2, 2}}, edges)
+	for _, mock := range mocks {
+		require.NoError(t, mock.ExpectationsWereMet())
+	}
+}
+
 func TestQueryEdges(t *testing.T) {
 	db, mock, err := sqlmock.New()
 	require.NoError(t, err)
@@ -0,0 +1,230 @@
+package sqlgraph
+
+import (
+	"context"
+	stdsql "database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/lib/pq"
+
+	"github.com/syssam/velox/dialect"
+	"github.com/syssam/velox/dialect/sql"
+)
+
+// DefaultLoadBatchSize is the default number of rows written per
+// transaction by Load.
+const DefaultLoadBatchSize = 10000
+
+// maxLoadParams holds the maximum number of bind parameters per statement
+// of each dialect, used to size the multi-row INSERT statements of Load.
+var maxLoadParams = map[string]int{
+	dialect.MySQL:    65535,
+	dialect.Postgres: 65535,
+	dialect.SQLite:   32766,
+}
+
+// LoadSpec holds the information for loading nodes in bulk.
+type LoadSpec struct {
+	// Next returns the next node to load, or nil when the input is exhausted.
+	Next func() (*CreateSpec, error)
+
+	// BatchSize is the number of rows written per transaction.
+	// Defaults to DefaultLoadBatchSize.
+	BatchSize int
+}
+
+// Load inserts the nodes returned by spec.Next in batches of spec.BatchSize
+// rows and returns the number of rows written. Unlike BatchCreate, the IDs of
+// the inserted rows are not read back, and only edges whose foreign key is
+// stored in the table of the node (M2O and inverse O2O) are supported.
+//
+// On PostgreSQL with the lib/pq driver, each batch is streamed with COPY FROM
+// STDIN. Otherwise, each batch is written with multi-row INSERT statements
+// sized to the parameter limit of the dialect. The counter caches of the
+// parent rows that the nodes reference are updated in the transaction of the
+// batch. Every batch runs in its own transaction, so a failure leaves the
+// previous batches committed unless drv is a transaction.
+func Load(ctx context.Context, drv dialect.Driver, spec *LoadSpec) (int, error) {
+	size := spec.BatchSize
+	if size <= 0 {
+		size = DefaultLoadBatchSize
+	}
+	var (
+		n     int
+		batch = make([]*CreateSpec, 0, min(size, 1024))
+	)
+	for {
+		node, err := spec.Next()
+		if err != nil {
+			return n, err
+		}
+		if node != nil {
+			batch = append(batch, node)
+		}
+		if len(batch) > 0 && (len(batch) == size || node == nil) {
+			if err := loadBatch(ctx, drv, batch); err != nil {
+				return n, err
+			}
+			n += len(batch)
+			batch = batch[:0]
+		}
+		if node == nil {
+			return n, nil
+		}
+	}
+}
+
+// loadBatch writes one batch of nodes in a transaction, and updates the
+// counter caches of the parent rows that the nodes reference.
+func loadBatch(ctx context.Context, drv dialect.Driver, nodes []*CreateSpec) error {
+	columns, rows, values, err := loadRows(nodes)
+	if err != nil {
+		return err
+	}
+	tx, err := drv.Tx(ctx)
+	if err != nil {
+		return err
+	}
+	if err := func() error {
+		if stx, ok := copyTx(drv, tx); ok {
+			if err := copyIn(ctx, stx, nodes[0], columns, rows); err != nil {
+				return fmt.Errorf("copy nodes to table %q: %w", nodes[0].Table, err)
+			}
+		} else if err := insertRows(ctx, tx, drv.Dialect(), nodes[0], columns, rows); err != nil {
+			return fmt.Errorf("insert nodes to table %q: %w", nodes[0].Table, err)
+		}
+		counters := refCounters(nodes[0].Counters, nodes[0].Table)
+		gr := graph{tx: tx, builder: sql.Dialect(drv.Dialect()), counters: counters}
+		return gr.insertCounters(ctx, counters, values, false, nil)
+	}(); err != nil {
+		return rollback(tx, err)
+	}
+	return tx.Commit()
+}
+
+// loadRows returns the sorted columns of the nodes, their rows, and the
+// values of each node by column. Columns that are not set on a node are
+// written as NULL.
+func loadRows(nodes []*CreateSpec) ([]string, [][]any, []map[string]driver.Value, error) {
+	columns := make(map[string]struct{})
+	values := make([]map[string]driver.Value, len(nodes))
+	for i, node := range nodes {
+		if node.Table != nodes[0].Table {
+			return nil, nil, nil, fmt.Errorf("more than 1 table for bulk load: %q != %q", node.Table, nodes[0].Table)
+		}
+		edges := EdgeSpecs(node.Edges).GroupRel()
+		if len(edges[O2M]) > 0 || len(edges[M2M]) > 0 || slices.ContainsFunc(edges[O2O], func(e *EdgeSpec) bool { return !e.Inverse && !e.Bidi }) {
+			return nil, nil, nil, fmt.Errorf("bulk load into table %q does not support edges stored in other tables", node.Table)
+		}
+		values[i] = make(map[string]driver.Value)
+		if node.ID != nil && node.ID.Value != nil {
+			columns[node.ID.Column] = struct{}{}
+			values[i][node.ID.Column] = node.ID.Value
+		}
+		err := setTableColumns(node.Fields, edges, func(column string, value driver.Value) {
+			columns[column] = struct{}{}
+			values[i][column] = value
+		})
+		if err != nil {
+			return nil, nil, nil, err
+		}
+	}
+	if len(columns) == 0 {
+		return nil, nil, nil, fmt.Errorf("bulk load into table %q: no columns to insert", nodes[0].Table)
+	}
+	sorted := keys(columns)
+	rows := make([][]any, len(values))
+	for i := range values {
+		rows[i] = make([]any, len(sorted))
+		for j, c := range sorted {
+			v, ok := values[i][c]
+			if !ok && nodes[i].ID != nil && c == nodes[i].ID.Column {
+				return nil, nil, nil, fmt.Errorf("inconsistent id values for bulk load")
+			}
+			rows[i][j] = v
+		}
+	}
+	return sorted, rows, values, nil
+}
+
+// insertRows writes the rows with multi-row INSERT statements.
+func insertRows(ctx context.Context, tx dialect.ExecQuerier, dialectName string, node *CreateSpec, columns []string, rows [][]any) error {
+	limit, ok := maxLoadParams[dialectName]
+	if !ok {
+		limit = maxLoadParams[dialect.SQLite]
+	}
+	per := max(limit/len(columns), 1)
+	b := sql.Dialect(dialectName)
+	for start := 0; start < len(rows); start += per {
+		insert := b.Insert(node.Table).Schema(node.Schema).Columns(columns...)
+		for _, row := range rows[start:min(start+per, len(rows))] {
+			insert.Values(row...)
+		}
+		query, args, err := insert.QueryErr()
+		if err != nil {
+			return err
+		}
+		if err := tx.Exec(ctx, query, args, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// copyTx returns the database transaction of tx if the rows can be streamed
+// with COPY FROM STDIN: a transaction of a PostgreSQL database opened with
+// the lib/pq driver.
+func copyTx(drv dialect.Driver, tx dialect.Tx) (*stdsql.Tx, bool) {
+	d, ok := drv.(*sql.Driver)
+	if !ok || d.Dialect() != dialect.Postgres {
+		return nil, false
+	}
+	if _, ok := d.DB().Driver().(*pq.Driver); !ok {
+		return nil, false
+	}
+	t, ok := tx.(*sql.Tx)
+	if !ok {
+		return nil, false
+	}
+	stx, ok := t.Tx.(*stdsql.Tx)
+	return stx, ok
+}
+
+// copyIn streams the rows with COPY FROM STDIN through the lib/pq driver,
+// in the given transaction.
+func copyIn(ctx context.Context, tx *stdsql.Tx, node *CreateSpec, columns []string, rows [][]any) error {
+	table := pq.QuoteIdentifier(node.Table)
+	if node.Schema != "" {
+		table = pq.QuoteIdentifier(node.Schema) + "." + table
+	}
+	quoted := make([]string, len(columns))
+	for i, c := range columns {
+		quoted[i] = pq.QuoteIdentifier(c)
+	}
+	stmt, err := tx.PrepareContext(ctx, fmt.Sprintf("COPY %s (%s) FROM STDIN", table, strings.Join(quoted, ", ")))
+	if err != nil {
+		return err
+	}
+	for _, row := range rows {
+		for i, v := range row {
+			// lib/pq encodes []byte values as bytea in COPY.
+			if raw, ok := v.(json.RawMessage); ok {
+				row[i] = string(raw)
+			}
+		}
+		if _, err := stmt.ExecContext(ctx, row...); err != nil {
+			stmt.Close()
+			return err
+		}
+	}
+	// An Exec without arguments flushes the buffered rows.
+	if _, err := stmt.ExecContext(ctx); err != nil {
+		stmt.Close()
+		return err
+	}
+	return stmt.Close()
+}
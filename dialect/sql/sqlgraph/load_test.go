@@ -0,0 +1,149 @@
+package sqlgraph
+
+import (
+	"context"
+	"database/sql/driver"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/syssam/velox/dialect"
+	"github.com/syssam/velox/dialect/sql"
+	"github.com/syssam/velox/schema/field"
+)
+
+// loadSpecs returns a LoadSpec that yields the given nodes.
+func loadSpecs(size int, nodes ...*CreateSpec) *LoadSpec {
+	return &LoadSpec{
+		BatchSize: size,
+		Next: func() (*CreateSpec, error) {
+			if len(nodes) == 0 {
+				return nil, nil
+			}
+			node := nodes[0]
+			nodes = nodes[1:]
+			return node, nil
+		},
+	}
+}
+
+func userSpec(name string, age any) *CreateSpec {
+	spec := NewCreateSpec("users", &FieldSpec{Column: "id", Type: field.TypeInt})
+	spec.SetField("name", field.TypeString, name)
+	if age != nil {
+		spec.SetField("age", field.TypeInt, age)
+	}
+	return spec
+}
+
+func TestLoad(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	// Two transactions of two rows and one row. Unset columns are NULL.
+	mock.ExpectBegin()
+	mock.ExpectExec(escape("INSERT INTO `users` (`age`, `name`) VALUES (?, ?), (NULL, ?)")).
+		WithArgs(30, "a8m", "nati").
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectCommit()
+	mock.ExpectBegin()
+	mock.ExpectExec(escape("INSERT INTO `users` (`name`) VALUES (?)")).
+		WithArgs("ariel").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	n, err := Load(context.Background(), sql.OpenDB(dialect.MySQL, db), loadSpecs(2,
+		userSpec("a8m", 30), userSpec("nati", nil), userSpec("ariel", nil),
+	))
+	require.NoError(t, err)
+	require.Equal(t, 3, n)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestLoad_ParamLimit(t *testing.T) {
+	defer func(limit int) { maxLoadParams[dialect.SQLite] = limit }(maxLoadParams[dialect.SQLite])
+	maxLoadParams[dialect.SQLite] = 5
+
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	// Two columns per row: at most two rows per statement.
+	mock.ExpectBegin()
+	mock.ExpectExec(escape("INSERT INTO `users` (`age`, `name`) VALUES (?, ?), (?, ?)")).
+		WithArgs(1, "a", 2, "b").
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectExec(escape("INSERT INTO `users` (`age`, `name`) VALUES (?, ?)")).
+		WithArgs(3, "c").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	n, err := Load(context.Background(), sql.OpenDB(dialect.SQLite, db), loadSpecs(0,
+		userSpec("a", 1), userSpec("b", 2), userSpec("c", 3),
+	))
+	require.NoError(t, err)
+	require.Equal(t, 3, n)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestLoad_Counters(t *testing.T) {
+	post := func(title string, user driver.Value) *CreateSpec {
+		spec := NewCreateSpec("posts", &FieldSpec{Column: "id", Type: field.TypeInt})
+		spec.SetField("title", field.TypeString, title)
+		spec.Edges = append(spec.Edges, authorEdge(user))
+		spec.Counters = []*CounterSpec{postsCount}
+		return spec
+	}
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	// The counters of the parent rows are updated in the transaction of each batch.
+	mock.ExpectBegin()
+	mock.ExpectExec(escape("INSERT INTO `posts` (`title`, `user_posts`) VALUES (?, ?), (?, ?), (?, ?)")).
+		WithArgs("a", 1, "b", 2, "c", 1).
+		WillReturnResult(sqlmock.NewResult(0, 3))
+	mock.ExpectExec(escape("UPDATE `users` SET `posts_count` = COALESCE(`users`.`posts_count`, 0) + ? WHERE `id` = ?")).
+		WithArgs(1, 2).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(escape("UPDATE `users` SET `posts_count` = COALESCE(`users`.`posts_count`, 0) + ? WHERE `id` = ?")).
+		WithArgs(2, 1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	mock.ExpectBegin()
+	mock.ExpectExec(escape("INSERT INTO `posts` (`title`, `user_posts`) VALUES (?, ?)")).
+		WithArgs("d", 2).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(escape("UPDATE `users` SET `posts_count` = COALESCE(`users`.`posts_count`, 0) + ? WHERE `id` = ?")).
+		WithArgs(1, 2).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	n, err := Load(context.Background(), sql.OpenDB(dialect.MySQL, db), loadSpecs(3,
+		post("a", 1), post("b", 2), post("c", 1), post("d", 2),
+	))
+	require.NoError(t, err)
+	require.Equal(t, 4, n)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestLoad_Errors(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	mock.ExpectBegin()
+	mock.ExpectExec(escape("INSERT INTO `users` (`name`) VALUES (?)")).
+		WithArgs("a8m").
+		WillReturnError(sqlmock.ErrCancelled)
+	mock.ExpectRollback()
+	drv := sql.OpenDB(dialect.MySQL, db)
+	n, err := Load(context.Background(), drv, loadSpecs(1, userSpec("a8m", nil), userSpec("nati", nil)))
+	require.ErrorContains(t, err, `insert nodes to table "users"`)
+	require.Zero(t, n)
+	require.NoError(t, mock.ExpectationsWereMet())
+
+	withEdge := userSpec("a8m", nil)
+	withEdge.Edges = []*EdgeSpec{{Rel: O2M, Table: "pets", Columns: []string{"owner_id"}, Target: &EdgeTarget{Nodes: []driver.Value{1}}}}
+	_, err = Load(context.Background(), drv, loadSpecs(0, withEdge))
+	require.ErrorContains(t, err, "does not support edges stored in other tables")
+
+	withID := userSpec("a8m", nil)
+	withID.ID.Value = 1
+	_, err = Load(context.Background(), drv, loadSpecs(0, withID, userSpec("nati", nil)))
+	require.ErrorContains(t, err, "inconsistent id values")
+}
@@ -0,0 +1,317 @@
+package sqlgraph
+
+import (
+	"context"
+	"database/sql/driver"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/syssam/velox/dialect"
+	"github.com/syssam/velox/dialect/sql"
+)
+
+// DefaultBatchUpdateSize is the default number of nodes updated per
+// BatchUpdate call by the generated UpdateBulk builders.
+const DefaultBatchUpdateSize = 10000
+
+// BatchUpdateSpec holds the information for updating
+// multiple nodes in the graph, each with its own values.
+type BatchUpdateSpec struct {
+	Nodes []*UpdateSpec
+}
+
+// BatchUpdate applies the BatchUpdateSpec on the graph in one transaction.
+//
+// Nodes that set, add and clear the same columns are updated with a single
+// statement per chunk: UPDATE ... FROM (VALUES ...) on PostgreSQL, and
+// CASE expressions keyed by the node id on MySQL and SQLite. Chunks are sized
+// to the parameter limit of the dialect. Nodes that have edges, modifiers or
+// a predicate, change a counted foreign-key, or whose id appears earlier in
+// the batch, are updated one by one after the batched statements, in their
+// input order.
+//
+// Like UpdateNodes, BatchUpdate does not report nodes that do not exist in the
+// database. Callers that need it should read the nodes back.
+func BatchUpdate(ctx context.Context, drv dialect.Driver, spec *BatchUpdateSpec) error {
+	if len(spec.Nodes) == 0 {
+		return nil
+	}
+	tx, err := drv.Tx(ctx)
+	if err != nil {
+		return err
+	}
+	gr := graph{tx: tx, builder: sql.Dialect(drv.Dialect()), counters: spec.Nodes[0].Counters}
+	bu := &batchUpdater{BatchUpdateSpec: spec, graph: gr, dialect: drv.Dialect()}
+	if err := bu.nodes(ctx, tx); err != nil {
+		return rollback(tx, err)
+	}
+	return tx.Commit()
+}
+
+type batchUpdater struct {
+	graph
+	*BatchUpdateSpec
+	dialect string
+}
+
+// updateGroup holds the nodes that update the same columns.
+type updateGroup struct {
+	set, add, clear []string
+	ids             []driver.Value
+	// values holds the set values, followed by the add values, of each node.
+	values [][]any
+}
+
+func (u *batchUpdater) nodes(ctx context.Context, tx dialect.ExecQuerier) error {
+	var (
+		first  = u.Nodes[0].Node
+		seen   = make(map[string]bool, len(u.Nodes))
+		keys   []string
+		groups = make(map[string]*updateGroup)
+		single []*UpdateSpec
+	)
+	for _, node := range u.Nodes {
+		switch {
+		case node.Node.Table != first.Table:
+			return fmt.Errorf("more than 1 table for batch update: %q != %q", node.Node.Table, first.Table)
+		case node.Node.ID == nil || node.Node.ID.Value == nil:
+			return fmt.Errorf("sql/sqlgraph: missing node id for batch update table %q", node.Node.Table)
+		}
+		id := fmt.Sprint(node.Node.ID.Value)
+		if seen[id] || len(node.Edges.Add) > 0 || len(node.Edges.Clear) > 0 || len(node.Modifiers) > 0 || node.Predicate != nil || len(node.touchedCounters()) > 0 {
+			seen[id] = true
+			single = append(single, node)
+			continue
+		}
+		seen[id] = true
+		g, err := groupNode(node)
+		if err != nil {
+			return err
+		}
+		if g == nil {
+			continue
+		}
+		key := strings.Join(g.set, ",") + "|" + strings.Join(g.add, ",") + "|" + strings.Join(g.clear, ",")
+		if prev, ok := groups[key]; ok {
+			prev.ids = append(prev.ids, g.ids...)
+			prev.values = append(prev.values, g.values...)
+			continue
+		}
+		keys = append(keys, key)
+		groups[key] = g
+	}
+	for _, key := range keys {
+		if err := u.updateGroup(ctx, tx, first, groups[key]); err != nil {
+			return err
+		}
+	}
+	for _, node := range single {
+		up := &updater{UpdateSpec: node, graph: u.graph}
+		if err := up.node(ctx, tx); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// groupNode returns the group of one node, or nil if the node has nothing to update.
+func groupNode(node *UpdateSpec) (*updateGroup, error) {
+	set := make(map[string]driver.Value, len(node.Fields.Set))
+	if err := setTableColumns(node.Fields.Set, nil, func(column string, value driver.Value) {
+		set[column] = value
+	}); err != nil {
+		return nil, err
+	}
+	add := make(map[string]driver.Value, len(node.Fields.Add))
+	for _, fi := range node.Fields.Add {
+		add[fi.Column] = fi.Value
+	}
+	cleared := make(map[string]struct{}, len(node.Fields.Clear))
+	for _, fi := range node.Fields.Clear {
+		cleared[fi.Column] = struct{}{}
+	}
+	if len(set) == 0 && len(add) == 0 && len(cleared) == 0 {
+		return nil, nil
+	}
+	g := &updateGroup{
+		set:   keys(toSet(set)),
+		add:   keys(toSet(add)),
+		clear: keys(cleared),
+		ids:   []driver.Value{node.Node.ID.Value},
+	}
+	row := make([]any, 0, len(g.set)+len(g.add))
+	for _, c := range g.set {
+		row = append(row, set[c])
+	}
+	for _, c := range g.add {
+		row = append(row, add[c])
+	}
+	g.values = [][]any{row}
+	return g, nil
+}
+
+func toSet(m map[string]driver.Value) map[string]struct{} {
+	s := make(map[string]struct{}, len(m))
+	for k := range m {
+		s[k] = struct{}{}
+	}
+	return s
+}
+
+// updateGroup updates the nodes of one group, in chunks sized to the
+// parameter limit of the dialect.
+func (u *batchUpdater) updateGroup(ctx context.Context, tx dialect.ExecQuerier, node *NodeSpec, g *updateGroup) error {
+	limit, ok := maxLoadParams[u.dialect]
+	if !ok {
+		limit = maxLoadParams[dialect.SQLite]
+	}
+	var (
+		pg     = u.dialect == dialect.Postgres
+		values = len(g.set) + len(g.add)
+		// Each row binds its id and values in a VALUES list, and its
+		// id twice per column plus once in the IN list with CASE.
+		params = 1 + values
+	)
+	if !pg {
+		params = 1 + 2*values
+	}
+	per := max(limit/params, 1)
+	for start := 0; start < len(g.ids); start += per {
+		end := min(start+per, len(g.ids))
+		var (
+			query string
+			args  []any
+			err   error
+		)
+		switch {
+		case values == 0:
+			query, args, err = u.clearQuery(node, g, start, end)
+		case pg:
+			query, args, err = u.valuesQuery(node, g, start, end)
+		default:
+			query, args, err = u.caseQuery(node, g, start, end)
+		}
+		if err != nil {
+			return err
+		}
+		if err := tx.Exec(ctx, query, args, nil); err != nil {
+			return fmt.Errorf("batch update table %q: %w", node.Table, err)
+		}
+	}
+	return nil
+}
+
+// clearQuery returns the statement of a group that only clears columns.
+func (u *batchUpdater) clearQuery(node *NodeSpec, g *updateGroup, start, end int) (string, []any, error) {
+	update := u.builder.Update(node.Table).Schema(node.Schema).Where(matchID(node.ID.Column, g.ids[start:end]))
+	for _, c := range g.clear {
+		update.SetNull(c)
+	}
+	if err := update.Err(); err != nil {
+		return "", nil, err
+	}
+	query, args := update.Query()
+	return query, args, nil
+}
+
+// caseQuery returns the statement of a group using CASE expressions:
+//
+//	UPDATE t SET c = CASE id WHEN ? THEN ? ... ELSE c END WHERE id IN (...)
+func (u *batchUpdater) caseQuery(node *NodeSpec, g *updateGroup, start, end int) (string, []any, error) {
+	var (
+		ids    = g.ids[start:end]
+		rows   = g.values[start:end]
+		update = u.builder.Update(node.Table).Schema(node.Schema).Where(matchID(node.ID.Column, ids))
+	)
+	for _, c := range g.clear {
+		update.SetNull(c)
+	}
+	for j, c := range append(slices.Clone(g.set), g.add...) {
+		isAdd := j >= len(g.set)
+		update.Set(c, sql.ExprFunc(func(b *sql.Builder) {
+			b.WriteString("CASE ").Ident(node.ID.Column)
+			for i, id := range ids {
+				b.WriteString(" WHEN ").Arg(id).WriteString(" THEN ")
+				if isAdd {
+					b.WriteString("COALESCE").Wrap(func(b *sql.Builder) {
+						b.Ident(c).Comma().Byte('0')
+					}).WriteString(" + ")
+				}
+				b.Arg(rows[i][j])
+			}
+			b.WriteString(" ELSE ").Ident(c).WriteString(" END")
+		}))
+	}
+	if err := update.Err(); err != nil {
+		return "", nil, err
+	}
+	query, args := update.Query()
+	return query, args, nil
+}
+
+// valuesQuery returns the statement of a group using a VALUES list:
+//
+//	UPDATE t SET c = v.c FROM (SELECT id, c FROM t WHERE FALSE UNION ALL VALUES ($1, $2), ...) AS v (id, c) WHERE t.id = v.id
+//
+// The empty SELECT gives the VALUES columns the types of the table columns.
+// Without it, PostgreSQL resolves the untyped parameters as text.
+func (u *batchUpdater) valuesQuery(node *NodeSpec, g *updateGroup, start, end int) (string, []any, error) {
+	const alias = "v"
+	var (
+		b       = &sql.Builder{}
+		columns = append(append([]string{node.ID.Column}, g.set...), g.add...)
+	)
+	b.SetDialect(u.dialect)
+	table := func() {
+		if node.Schema != "" {
+			b.Ident(node.Schema).Byte('.')
+		}
+		b.Ident(node.Table)
+	}
+	b.WriteString("UPDATE ")
+	table()
+	b.WriteString(" SET ")
+	for i, c := range g.clear {
+		if i > 0 {
+			b.Comma()
+		}
+		b.Ident(c).WriteString(" = NULL")
+	}
+	for i, c := range columns[1:] {
+		if i > 0 || len(g.clear) > 0 {
+			b.Comma()
+		}
+		b.Ident(c).WriteString(" = ")
+		if i >= len(g.set) {
+			b.WriteString("COALESCE").Wrap(func(b *sql.Builder) {
+				b.Ident(node.Table).Byte('.').Ident(c).Comma().Byte('0')
+			}).WriteString(" + ")
+		}
+		b.Ident(alias).Byte('.').Ident(c)
+	}
+	b.WriteString(" FROM (SELECT ").IdentComma(columns...).WriteString(" FROM ")
+	table()
+	b.WriteString(" WHERE FALSE UNION ALL VALUES ")
+	for i, id := range g.ids[start:end] {
+		if i > 0 {
+			b.Comma()
+		}
+		b.Wrap(func(b *sql.Builder) {
+			b.Arg(id)
+			for _, v := range g.values[start+i] {
+				b.Comma().Arg(v)
+			}
+		})
+	}
+	b.WriteString(") AS ").Ident(alias).Pad().Wrap(func(b *sql.Builder) {
+		b.IdentComma(columns...)
+	})
+	b.WriteString(" WHERE ").Ident(node.Table).Byte('.').Ident(node.ID.Column).
+		WriteString(" = ").Ident(alias).Byte('.').Ident(node.ID.Column)
+	if err := b.Err(); err != nil {
+		return "", nil, err
+	}
+	query, args := b.Query()
+	return query, args, nil
+}
@@ -0,0 +1,145 @@
+package sqlgraph
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/syssam/velox/dialect"
+	"github.com/syssam/velox/dialect/sql"
+	"github.com/syssam/velox/schema/field"
+)
+
+func userUpdateSpec(id int, name string, age any) *UpdateSpec {
+	spec := NewUpdateSpec("users", []string{"id", "name", "age"}, &FieldSpec{Column: "id", Type: field.TypeInt, Value: id})
+	spec.SetField("name", field.TypeString, name)
+	if age != nil {
+		spec.AddField("age", field.TypeInt, age)
+	}
+	return spec
+}
+
+func TestBatchUpdate(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	mock.ExpectBegin()
+	// Nodes 1 and 3 update the same columns.
+	mock.ExpectExec(escape("UPDATE `users` SET `name` = CASE `id` WHEN ? THEN ? WHEN ? THEN ? ELSE `name` END, `age` = CASE `id` WHEN ? THEN COALESCE(`age`, 0) + ? WHEN ? THEN COALESCE(`age`, 0) + ? ELSE `age` END WHERE `id` IN (?, ?)")).
+		WithArgs(1, "a8m", 3, "nati", 1, 1, 3, 2, 1, 3).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectExec(escape("UPDATE `users` SET `name` = CASE `id` WHEN ? THEN ? ELSE `name` END WHERE `id` = ?")).
+		WithArgs(2, "ariel", 2).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	// A repeated id is updated after the batched statements.
+	mock.ExpectExec(escape("UPDATE `users` SET `name` = ? WHERE `id` = ?")).
+		WithArgs("mashraki", 1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	err = BatchUpdate(context.Background(), sql.OpenDB(dialect.MySQL, db), &BatchUpdateSpec{
+		Nodes: []*UpdateSpec{
+			userUpdateSpec(1, "a8m", 1),
+			userUpdateSpec(2, "ariel", nil),
+			userUpdateSpec(3, "nati", 2),
+			userUpdateSpec(1, "mashraki", nil),
+		},
+	})
+	require.NoError(t, err)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestBatchUpdate_Postgres(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	cleared := userUpdateSpec(3, "nati", nil)
+	cleared.ClearField("nickname", field.TypeString)
+	mock.ExpectBegin()
+	mock.ExpectExec(escape(`UPDATE "users" SET "name" = "v"."name", "age" = COALESCE("users"."age", 0) + "v"."age" FROM (SELECT "id", "name", "age" FROM "users" WHERE FALSE UNION ALL VALUES ($1, $2, $3), ($4, $5, $6)) AS "v" ("id", "name", "age") WHERE "users"."id" = "v"."id"`)).
+		WithArgs(1, "a8m", 1, 2, "ariel", 2).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectExec(escape(`UPDATE "users" SET "nickname" = NULL, "name" = "v"."name" FROM (SELECT "id", "name" FROM "users" WHERE FALSE UNION ALL VALUES ($1, $2)) AS "v" ("id", "name") WHERE "users"."id" = "v"."id"`)).
+		WithArgs(3, "nati").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	err = BatchUpdate(context.Background(), sql.OpenDB(dialect.Postgres, db), &BatchUpdateSpec{
+		Nodes: []*UpdateSpec{
+			userUpdateSpec(1, "a8m", 1),
+			userUpdateSpec(2, "ariel", 2),
+			cleared,
+		},
+	})
+	require.NoError(t, err)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestBatchUpdate_ParamLimit(t *testing.T) {
+	defer func(limit int) { maxLoadParams[dialect.SQLite] = limit }(maxLoadParams[dialect.SQLite])
+	maxLoadParams[dialect.SQLite] = 6
+
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	// Three parameters per row: at most two rows per statement.
+	mock.ExpectBegin()
+	mock.ExpectExec(escape("UPDATE `users` SET `name` = CASE `id` WHEN ? THEN ? WHEN ? THEN ? ELSE `name` END WHERE `id` IN (?, ?)")).
+		WithArgs(1, "a", 2, "b", 1, 2).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectExec(escape("UPDATE `users` SET `name` = CASE `id` WHEN ? THEN ? ELSE `name` END WHERE `id` = ?")).
+		WithArgs(3, "c", 3).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	err = BatchUpdate(context.Background(), sql.OpenDB(dialect.SQLite, db), &BatchUpdateSpec{
+		Nodes: []*UpdateSpec{
+			userUpdateSpec(1, "a", nil),
+			userUpdateSpec(2, "b", nil),
+			userUpdateSpec(3, "c", nil),
+		},
+	})
+	require.NoError(t, err)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestBatchUpdate_Errors(t *testing.T) {
+	t.Run("Tables", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		mock.ExpectBegin()
+		mock.ExpectRollback()
+		other := NewUpdateSpec("pets", nil, &FieldSpec{Column: "id", Type: field.TypeInt, Value: 2})
+		err = BatchUpdate(context.Background(), sql.OpenDB(dialect.MySQL, db), &BatchUpdateSpec{
+			Nodes: []*UpdateSpec{userUpdateSpec(1, "a8m", nil), other},
+		})
+		require.EqualError(t, err, `more than 1 table for batch update: "pets" != "users"`)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+	t.Run("MissingID", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		mock.ExpectBegin()
+		mock.ExpectRollback()
+		spec := userUpdateSpec(1, "a8m", nil)
+		spec.Node.ID.Value = nil
+		err = BatchUpdate(context.Background(), sql.OpenDB(dialect.MySQL, db), &BatchUpdateSpec{
+			Nodes: []*UpdateSpec{spec},
+		})
+		require.EqualError(t, err, `sql/sqlgraph: missing node id for batch update table "users"`)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+	t.Run("Exec", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		mock.ExpectBegin()
+		mock.ExpectExec(escape("UPDATE `users` SET `name` = CASE `id` WHEN ? THEN ? ELSE `name` END WHERE `id` = ?")).
+			WithArgs(1, "a8m", 1).
+			WillReturnError(sqlmock.ErrCancelled)
+		mock.ExpectRollback()
+		err = BatchUpdate(context.Background(), sql.OpenDB(dialect.MySQL, db), &BatchUpdateSpec{
+			Nodes: []*UpdateSpec{userUpdateSpec(1, "a8m", nil)},
+		})
+		require.ErrorIs(t, err, sqlmock.ErrCancelled)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+}
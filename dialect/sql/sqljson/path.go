@@ -0,0 +1,265 @@
+package sqljson
+
+import (
+	"reflect"
+
+	"github.com/syssam/velox/dialect"
+	"github.com/syssam/velox/dialect/sql"
+)
+
+// PathField is a generic field that provides type-safe predicates on the
+// value at a path of a JSON column. The generated entity packages declare
+// one per value of a JSON field with a struct type:
+//
+//	var InfoField = infoPaths{
+//		Address: infoAddressPaths{
+//			City: sqljson.NewStringPathField[predicate.User](FieldInfo, "address", "city"),
+//		},
+//	}
+//	query.Where(user.InfoField.Address.City.EQ("Tel Aviv"))
+type PathField[P sql.PredicateFunc, T any] struct {
+	// Column is the JSON column.
+	Column string
+	// Path is the path of the value in the column (the JSON keys).
+	Path []string
+}
+
+// NewPathField returns the PathField of the value at the given path of the column.
+func NewPathField[P sql.PredicateFunc, T any](column string, path ...string) PathField[P, T] {
+	return PathField[P, T]{Column: column, Path: path}
+}
+
+// EQ returns a predicate that checks if the value equals the given value.
+func (f PathField[P, T]) EQ(v T) P {
+	return f.where(func(c string) *sql.Predicate { return ValueEQ(c, basicValue(v), Path(f.Path...)) })
+}
+
+// NEQ returns a predicate that checks if the value does not equal the given value.
+func (f PathField[P, T]) NEQ(v T) P {
+	return f.where(func(c string) *sql.Predicate { return ValueNEQ(c, basicValue(v), Path(f.Path...)) })
+}
+
+// GT returns a predicate that checks if the value is greater than the given value.
+func (f PathField[P, T]) GT(v T) P {
+	return f.where(func(c string) *sql.Predicate { return ValueGT(c, basicValue(v), Path(f.Path...)) })
+}
+
+// GTE returns a predicate that checks if the value is greater than or equal to the given value.
+func (f PathField[P, T]) GTE(v T) P {
+	return f.where(func(c string) *sql.Predicate { return ValueGTE(c, basicValue(v), Path(f.Path...)) })
+}
+
+// LT returns a predicate that checks if the value is less than the given value.
+func (f PathField[P, T]) LT(v T) P {
+	return f.where(func(c string) *sql.Predicate { return ValueLT(c, basicValue(v), Path(f.Path...)) })
+}
+
+// LTE returns a predicate that checks if the value is less than or equal to the given value.
+func (f PathField[P, T]) LTE(v T) P {
+	return f.where(func(c string) *sql.Predicate { return ValueLTE(c, basicValue(v), Path(f.Path...)) })
+}
+
+// In returns a predicate that checks if the value is in the given list.
+func (f PathField[P, T]) In(vs ...T) P {
+	args := make([]any, len(vs))
+	for i, v := range vs {
+		args[i] = basicValue(v)
+	}
+	return f.where(func(c string) *sql.Predicate { return ValueIn(c, args, Path(f.Path...)) })
+}
+
+// NotIn returns a predicate that checks if the value is not in the given list.
+func (f PathField[P, T]) NotIn(vs ...T) P {
+	args := make([]any, len(vs))
+	for i, v := range vs {
+		args[i] = basicValue(v)
+	}
+	return f.where(func(c string) *sql.Predicate { return ValueNotIn(c, args, Path(f.Path...)) })
+}
+
+// HasKey returns a predicate that checks if the path exists, and its value is not NULL.
+func (f PathField[P, T]) HasKey() P {
+	return f.where(func(c string) *sql.Predicate { return HasKey(c, Path(f.Path...)) })
+}
+
+// IsNull returns a predicate that checks if the value is the JSON null literal.
+func (f PathField[P, T]) IsNull() P {
+	return f.where(func(c string) *sql.Predicate { return ValueIsNull(c, Path(f.Path...)) })
+}
+
+// NotNull returns a predicate that checks if the value is not the JSON null literal.
+func (f PathField[P, T]) NotNull() P {
+	return f.where(func(c string) *sql.Predicate { return ValueIsNotNull(c, Path(f.Path...)) })
+}
+
+// where returns a predicate that applies the predicate of the qualified column.
+func (f PathField[P, T]) where(p func(string) *sql.Predicate) P {
+	return P(func(s *sql.Selector) {
+		s.Where(p(s.C(f.Column)))
+	})
+}
+
+// StringPathField is a PathField of string values, with the string predicates.
+type StringPathField[P sql.PredicateFunc] struct {
+	PathField[P, string]
+}
+
+// NewStringPathField returns the StringPathField of the value at the given path of the column.
+func NewStringPathField[P sql.PredicateFunc](column string, path ...string) StringPathField[P] {
+	return StringPathField[P]{PathField: NewPathField[P, string](column, path...)}
+}
+
+// HasPrefix returns a predicate that checks if the value has the given prefix.
+func (f StringPathField[P]) HasPrefix(v string) P {
+	return f.where(func(c string) *sql.Predicate { return StringHasPrefix(c, v, Path(f.Path...)) })
+}
+
+// HasSuffix returns a predicate that checks if the value has the given suffix.
+func (f StringPathField[P]) HasSuffix(v string) P {
+	return f.where(func(c string) *sql.Predicate { return StringHasSuffix(c, v, Path(f.Path...)) })
+}
+
+// Contains returns a predicate that checks if the value contains the given substring.
+func (f StringPathField[P]) Contains(v string) P {
+	return f.where(func(c string) *sql.Predicate { return StringContains(c, v, Path(f.Path...)) })
+}
+
+// basicValue converts values of named basic types (e.g. enums) to their
+// underlying type, as the dialects infer the JSON casts from the Go type.
+func basicValue(v any) any {
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.String:
+		return rv.String()
+	case reflect.Bool:
+		return rv.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return rv.Int()
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return rv.Uint()
+	case reflect.Float32, reflect.Float64:
+		return rv.Float()
+	default:
+		return v
+	}
+}
+
+// PathValue is a value to set at a path of a JSON column. See SetPaths.
+type PathValue struct {
+	Path  []string
+	Value any
+}
+
+// SetPaths sets the values at the given paths of a JSON object column in an
+// UPDATE statement, and keeps the other keys of the object. The objects on
+// the paths are created if they are missing (or are not objects), and the
+// values are encoded with encoding/json.
+//
+//	SetPaths(u, "info", PathValue{Path: []string{"address", "city"}, Value: "TLV"})
+//
+//	// PostgreSQL.
+//	UPDATE "users" SET "info" = jsonb_set(jsonb_set(CASE WHEN jsonb_typeof("info") = 'object' THEN "info" ELSE '{}'::jsonb END,
+//		'{address}', CASE WHEN jsonb_typeof("info"->'address') = 'object' THEN "info"->'address' ELSE '{}'::jsonb END, true),
+//		'{address, city}', $1::jsonb, true)
+//
+//	// MySQL.
+//	UPDATE `users` SET `info` = JSON_SET(IF(JSON_TYPE(`info`) = 'OBJECT', `info`, JSON_OBJECT()),
+//		'$.address', IF(JSON_TYPE(JSON_EXTRACT(`info`, '$.address')) = 'OBJECT', JSON_EXTRACT(`info`, '$.address'), JSON_OBJECT()),
+//		'$.address.city', CAST(? AS JSON))
+//
+//	// SQLite.
+//	UPDATE `users` SET `info` = json_set(CASE WHEN json_type(`info`) = 'object' THEN `info` ELSE '{}' END,
+//		'$.address', json(CASE WHEN json_type(`info`, '$.address') = 'object' THEN json_extract(`info`, '$.address') ELSE '{}' END),
+//		'$.address.city', json(?))
+//
+// The statement modifies the column in place, such that concurrent updates
+// of other keys of the same row are not lost.
+func SetPaths(u *sql.UpdateBuilder, column string, values ...PathValue) {
+	if len(values) == 0 {
+		return
+	}
+	// The objects on the paths, parents first.
+	var (
+		objects [][]string
+		seen    = make(map[string]bool)
+	)
+	for _, v := range values {
+		for i := 1; i < len(v.Path); i++ {
+			p := identPath(column, Path(v.Path[:i]...))
+			if k := p.key(); !seen[k] {
+				seen[k] = true
+				objects = append(objects, v.Path[:i])
+			}
+		}
+	}
+	u.Set(column, sql.ExprFunc(func(b *sql.Builder) {
+		switch b.Dialect() {
+		case dialect.Postgres:
+			for range len(objects) + len(values) {
+				b.WriteString("jsonb_set(")
+			}
+			b.WriteString("CASE WHEN jsonb_typeof(").Ident(column).WriteString(") = 'object' THEN ").
+				Ident(column).WriteString(" ELSE '{}'::jsonb END")
+			for _, o := range objects {
+				p := identPath(column, Path(o...))
+				b.Comma()
+				p.pgArrayPath(b)
+				b.WriteString(", CASE WHEN jsonb_typeof(")
+				p.pgTextPath(b)
+				b.WriteString(") = 'object' THEN ")
+				p.pgTextPath(b)
+				b.WriteString(" ELSE '{}'::jsonb END, true)")
+			}
+			for _, v := range values {
+				b.Comma()
+				identPath(column, Path(v.Path...)).pgArrayPath(b)
+				b.Comma().Arg(marshalArg(v.Value)).WriteString("::jsonb, true)")
+			}
+		case dialect.MySQL:
+			b.WriteString("JSON_SET(IF(JSON_TYPE(").Ident(column).WriteString(") = 'OBJECT', ").
+				Ident(column).WriteString(", JSON_OBJECT())")
+			for _, o := range objects {
+				p := identPath(column, Path(o...))
+				b.Comma()
+				p.mysqlPath(b)
+				b.WriteString(", IF(JSON_TYPE(")
+				p.mysqlFunc("JSON_EXTRACT", b)
+				b.WriteString(") = 'OBJECT', ")
+				p.mysqlFunc("JSON_EXTRACT", b)
+				b.WriteString(", JSON_OBJECT())")
+			}
+			for _, v := range values {
+				b.Comma()
+				identPath(column, Path(v.Path...)).mysqlPath(b)
+				b.Comma().Argf("CAST(? AS JSON)", marshalArg(v.Value))
+			}
+			b.Byte(')')
+		default:
+			b.WriteString("json_set(CASE WHEN json_type(").Ident(column).WriteString(") = 'object' THEN ").
+				Ident(column).WriteString(" ELSE '{}' END")
+			for _, o := range objects {
+				p := identPath(column, Path(o...))
+				b.Comma()
+				p.mysqlPath(b)
+				b.WriteString(", json(CASE WHEN ")
+				p.mysqlFunc("json_type", b)
+				b.WriteString(" = 'object' THEN ")
+				p.mysqlFunc("json_extract", b)
+				b.WriteString(" ELSE '{}' END)")
+			}
+			for _, v := range values {
+				b.Comma()
+				identPath(column, Path(v.Path...)).mysqlPath(b)
+				b.Comma().Argf("json(?)", marshalArg(v.Value))
+			}
+			b.Byte(')')
+		}
+	}))
+}
+
+// key returns the MySQL path of the options, used as a key of the path.
+func (p *PathOptions) key() string {
+	b := &sql.Builder{}
+	p.mysqlPath(b)
+	return b.String()
+}
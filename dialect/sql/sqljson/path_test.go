@@ -0,0 +1,135 @@
+package sqljson_test
+
+import (
+	stdsql "database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/syssam/velox/dialect"
+	"github.com/syssam/velox/dialect/sql"
+	"github.com/syssam/velox/dialect/sql/sqljson"
+
+	_ "modernc.org/sqlite"
+)
+
+type level int
+
+func TestPathField(t *testing.T) {
+	city := sqljson.NewStringPathField[func(*sql.Selector)]("info", "address", "city")
+	lvl := sqljson.NewPathField[func(*sql.Selector), level]("info", "level")
+	tests := []struct {
+		dialect   string
+		pred      func(*sql.Selector)
+		wantQuery string
+		wantArgs  []any
+	}{
+		{
+			dialect:   dialect.Postgres,
+			pred:      city.EQ("TLV"),
+			wantQuery: `SELECT * FROM "users" WHERE "users"."info"->'address'->>'city' = $1`,
+			wantArgs:  []any{"TLV"},
+		},
+		{
+			dialect:   dialect.Postgres,
+			pred:      lvl.GT(2),
+			wantQuery: `SELECT * FROM "users" WHERE ("users"."info"->>'level')::int > $1`,
+			wantArgs:  []any{int64(2)},
+		},
+		{
+			dialect:   dialect.MySQL,
+			pred:      lvl.In(1, 2),
+			wantQuery: "SELECT * FROM `users` WHERE JSON_EXTRACT(`users`.`info`, '$.level') IN (?, ?)",
+			wantArgs:  []any{int64(1), int64(2)},
+		},
+		{
+			dialect:   dialect.SQLite,
+			pred:      city.HasPrefix("T"),
+			wantQuery: "SELECT * FROM `users` WHERE JSON_EXTRACT(`users`.`info`, '$.address.city') LIKE ?",
+			wantArgs:  []any{"T%"},
+		},
+		{
+			dialect:   dialect.SQLite,
+			pred:      city.HasKey(),
+			wantQuery: "SELECT * FROM `users` WHERE JSON_TYPE(`users`.`info`, '$.address.city') IS NOT NULL",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.dialect, func(t *testing.T) {
+			s := sql.Dialect(tt.dialect).Select("*").From(sql.Table("users"))
+			tt.pred(s)
+			query, args := s.Query()
+			require.Equal(t, tt.wantQuery, query)
+			require.Equal(t, tt.wantArgs, args)
+		})
+	}
+}
+
+func TestSetPaths(t *testing.T) {
+	values := []sqljson.PathValue{
+		{Path: []string{"address", "city"}, Value: "TLV"},
+		{Path: []string{"address", "zip"}, Value: nil},
+		{Path: []string{"level"}, Value: 2},
+	}
+	tests := []struct {
+		dialect   string
+		wantQuery string
+	}{
+		{
+			dialect:   dialect.Postgres,
+			wantQuery: `UPDATE "users" SET "info" = jsonb_set(jsonb_set(jsonb_set(jsonb_set(CASE WHEN jsonb_typeof("info") = 'object' THEN "info" ELSE '{}'::jsonb END, '{address}', CASE WHEN jsonb_typeof("info"->'address') = 'object' THEN "info"->'address' ELSE '{}'::jsonb END, true), '{address, city}', $1::jsonb, true), '{address, zip}', $2::jsonb, true), '{level}', $3::jsonb, true)`,
+		},
+		{
+			dialect:   dialect.MySQL,
+			wantQuery: "UPDATE `users` SET `info` = JSON_SET(IF(JSON_TYPE(`info`) = 'OBJECT', `info`, JSON_OBJECT()), '$.address', IF(JSON_TYPE(JSON_EXTRACT(`info`, '$.address')) = 'OBJECT', JSON_EXTRACT(`info`, '$.address'), JSON_OBJECT()), '$.address.city', CAST(? AS JSON), '$.address.zip', CAST(? AS JSON), '$.level', CAST(? AS JSON))",
+		},
+		{
+			dialect:   dialect.SQLite,
+			wantQuery: "UPDATE `users` SET `info` = json_set(CASE WHEN json_type(`info`) = 'object' THEN `info` ELSE '{}' END, '$.address', json(CASE WHEN json_type(`info`, '$.address') = 'object' THEN json_extract(`info`, '$.address') ELSE '{}' END), '$.address.city', json(?), '$.address.zip', json(?), '$.level', json(?))",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.dialect, func(t *testing.T) {
+			u := sql.Dialect(tt.dialect).Update("users")
+			sqljson.SetPaths(u, "info", values...)
+			query, args := u.Query()
+			require.Equal(t, tt.wantQuery, query)
+			require.Equal(t, []any{`"TLV"`, "null", "2"}, args)
+		})
+	}
+}
+
+func TestSetPaths_SQLite(t *testing.T) {
+	db, err := stdsql.Open("sqlite", ":memory:")
+	require.NoError(t, err)
+	defer db.Close()
+	_, err = db.Exec("CREATE TABLE `users` (`id` integer PRIMARY KEY, `info` json)")
+	require.NoError(t, err)
+	_, err = db.Exec("INSERT INTO `users` VALUES (1, '{\"name\":\"a8m\",\"address\":{\"zip\":\"123\"}}'), (2, NULL), (3, '{\"address\":null}')")
+	require.NoError(t, err)
+
+	u := sql.Dialect(dialect.SQLite).Update("users")
+	sqljson.SetPaths(u, "info",
+		sqljson.PathValue{Path: []string{"address", "city"}, Value: "TLV"},
+		sqljson.PathValue{Path: []string{"level"}, Value: 2},
+	)
+	query, args := u.Query()
+	_, err = db.Exec(query, args...)
+	require.NoError(t, err)
+
+	rows, err := db.Query("SELECT `info` FROM `users` ORDER BY `id`")
+	require.NoError(t, err)
+	defer rows.Close()
+	var docs []string
+	for rows.Next() {
+		var doc string
+		require.NoError(t, rows.Scan(&doc))
+		docs = append(docs, doc)
+	}
+	require.NoError(t, rows.Err())
+	require.Equal(t, []string{
+		`{"name":"a8m","address":{"zip":"123","city":"TLV"},"level":2}`,
+		`{"address":{"city":"TLV"},"level":2}`,
+		`{"address":{"city":"TLV"},"level":2}`,
+	}, docs)
+}
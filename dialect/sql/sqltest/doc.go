@@ -0,0 +1,37 @@
+// Package sqltest records the SQL statements of a Velox client and replays
+// them without a database, for deterministic tests of the statements that a
+// code path emits.
+//
+// # Recording
+//
+// A RecordingDriver runs the statements on an underlying driver and records
+// the ordered exchanges: the statement, its arguments, and the returned rows
+// or result. Record compares them with a golden file at the end of the test:
+//
+//	drv := sqltest.Record(t, sql.OpenDB(dialect.SQLite, db), "testdata/users.json")
+//	client := ent.NewClient(ent.Driver(drv))
+//	resolver.Users(ctx, client)
+//
+// The test fails if the statements differ from the golden file. Run the tests
+// with the -sqltest.update flag to write the golden files instead:
+//
+//	go test ./... -sqltest.update
+//
+// # Replaying
+//
+// A ReplayDriver serves the exchanges of a golden file back, in order, without
+// a database. A statement that differs from the next recorded one fails with
+// an error that holds both:
+//
+//	drv := sqltest.Replay(t, "testdata/users.json")
+//	client := ent.NewClient(ent.Driver(drv))
+//
+// # Counting Statements
+//
+// Both drivers count the statements they run. AssertQueryCount catches N+1
+// regressions in resolvers and eager loading:
+//
+//	drv.Reset()
+//	client.User.Query().WithPets().AllX(ctx)
+//	drv.AssertQueryCount(t, 2)
+package sqltest
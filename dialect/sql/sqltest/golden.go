@@ -0,0 +1,56 @@
+package sqltest
+
+import (
+	"errors"
+	"flag"
+	"io/fs"
+	"testing"
+
+	"github.com/syssam/velox/dialect"
+)
+
+// update is set by the -sqltest.update flag to write the golden files.
+var update = flag.Bool("sqltest.update", false, "write the golden files of sqltest.Record")
+
+// Record returns a RecordingDriver that wraps the given driver. At the end of
+// the test, the recorded statements are compared with the golden file at path,
+// and the test fails if they differ. With the -sqltest.update flag, or if the
+// file does not exist, the golden file is written instead.
+func Record(t testing.TB, drv dialect.Driver, path string) *RecordingDriver {
+	t.Helper()
+	rec := NewRecordingDriver(drv)
+	t.Cleanup(func() {
+		got := rec.Recording()
+		want, err := ReadFile(path)
+		switch {
+		case *update || errors.Is(err, fs.ErrNotExist):
+			if err := got.WriteFile(path); err != nil {
+				t.Errorf("sqltest: writing %s: %v", path, err)
+			}
+		case err != nil:
+			t.Error(err)
+		default:
+			if diff := want.Diff(got); diff != "" {
+				t.Errorf("sqltest: statements differ from %s (run with -sqltest.update to update it):\n%s", path, diff)
+			}
+		}
+	})
+	return rec
+}
+
+// Replay returns a ReplayDriver that serves the exchanges of the golden file
+// at path. The test fails at its end if some exchanges were not served.
+func Replay(t testing.TB, path string) *ReplayDriver {
+	t.Helper()
+	r, err := ReadFile(path)
+	if err != nil {
+		t.Fatalf("sqltest: %v", err)
+	}
+	drv := NewReplayDriver(r)
+	t.Cleanup(func() {
+		if err := drv.ExpectationsWereMet(); err != nil {
+			t.Error(err)
+		}
+	})
+	return drv
+}
@@ -0,0 +1,173 @@
+package sqltest
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/syssam/velox/dialect"
+	"github.com/syssam/velox/dialect/sql"
+	"github.com/syssam/velox/dialect/sql/internal/rowsdb"
+)
+
+// RecordingDriver is a dialect.Driver that runs the statements on an
+// underlying driver, and records them with their outcomes.
+type RecordingDriver struct {
+	dialect.Driver
+	recorder
+	// all holds the exchanges since the start, including the ones before
+	// the last Reset, to be written to the golden file.
+	all []*Exchange
+}
+
+// NewRecordingDriver returns a RecordingDriver that wraps the given driver.
+func NewRecordingDriver(drv dialect.Driver) *RecordingDriver {
+	return &RecordingDriver{Driver: drv}
+}
+
+// Recording returns the exchanges since the start as a Recording.
+func (d *RecordingDriver) Recording() *Recording {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return &Recording{
+		Dialect:   d.Dialect(),
+		Exchanges: append([]*Exchange(nil), d.all...),
+	}
+}
+
+// WriteFile writes the exchanges since the start to a golden file.
+func (d *RecordingDriver) WriteFile(path string) error {
+	return d.Recording().WriteFile(path)
+}
+
+// add records the exchange, also for the golden file.
+func (d *RecordingDriver) add(e *Exchange) {
+	d.recorder.add(e)
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.all = append(d.all, e)
+}
+
+// Exec runs the statement on the underlying driver and records its result.
+func (d *RecordingDriver) Exec(ctx context.Context, query string, args, v any) error {
+	return execResult(ctx, d.Driver, d.add, query, args, v)
+}
+
+// Query runs the statement on the underlying driver and records its rows.
+func (d *RecordingDriver) Query(ctx context.Context, query string, args, v any) error {
+	return queryRows(ctx, d.Driver, d.add, query, args, v)
+}
+
+// Tx starts a transaction on the underlying driver and records it.
+func (d *RecordingDriver) Tx(ctx context.Context) (dialect.Tx, error) {
+	tx, err := d.Driver.Tx(ctx)
+	d.add(&Exchange{Kind: KindBegin, Err: errString(err)})
+	if err != nil {
+		return nil, err
+	}
+	return &recordingTx{Tx: tx, add: d.add}, nil
+}
+
+// BeginTx starts a transaction with the given options on the underlying driver,
+// if it is supported, and records it.
+func (d *RecordingDriver) BeginTx(ctx context.Context, opts *sql.TxOptions) (dialect.Tx, error) {
+	drv, ok := d.Driver.(interface {
+		BeginTx(context.Context, *sql.TxOptions) (dialect.Tx, error)
+	})
+	if !ok {
+		return nil, fmt.Errorf("sqltest: Driver.BeginTx is not supported")
+	}
+	tx, err := drv.BeginTx(ctx, opts)
+	d.add(&Exchange{Kind: KindBegin, Err: errString(err)})
+	if err != nil {
+		return nil, err
+	}
+	return &recordingTx{Tx: tx, add: d.add}, nil
+}
+
+// recordingTx records the statements of a transaction.
+type recordingTx struct {
+	dialect.Tx
+	add func(*Exchange)
+}
+
+func (t *recordingTx) Exec(ctx context.Context, query string, args, v any) error {
+	return execResult(ctx, t.Tx, t.add, query, args, v)
+}
+
+func (t *recordingTx) Query(ctx context.Context, query string, args, v any) error {
+	return queryRows(ctx, t.Tx, t.add, query, args, v)
+}
+
+func (t *recordingTx) Commit() error {
+	err := t.Tx.Commit()
+	t.add(&Exchange{Kind: KindCommit, Err: errString(err)})
+	return err
+}
+
+func (t *recordingTx) Rollback() error {
+	err := t.Tx.Rollback()
+	t.add(&Exchange{Kind: KindRollback, Err: errString(err)})
+	return err
+}
+
+// execResult runs an exec statement and records its result.
+func execResult(ctx context.Context, ex dialect.ExecQuerier, add func(*Exchange), query string, args, v any) error {
+	e := &Exchange{Kind: KindExec, Query: query, Args: newArgs(args)}
+	defer add(e)
+	var res sql.Result
+	rv, ok := v.(*sql.Result)
+	if !ok && v != nil {
+		return fmt.Errorf("sqltest: invalid type %T. expect *sql.Result", v)
+	}
+	if err := ex.Exec(ctx, query, args, &res); err != nil {
+		e.Err = err.Error()
+		return err
+	}
+	if id, err := res.LastInsertId(); err == nil {
+		e.LastInsertID = &id
+	}
+	if n, err := res.RowsAffected(); err == nil {
+		e.RowsAffected = &n
+	}
+	if rv != nil {
+		*rv = res
+	}
+	return nil
+}
+
+// queryRows runs a query statement, records its rows and serves them from memory.
+func queryRows(ctx context.Context, ex dialect.ExecQuerier, add func(*Exchange), query string, args, v any) error {
+	e := &Exchange{Kind: KindQuery, Query: query, Args: newArgs(args)}
+	defer add(e)
+	rows, ok := v.(*sql.Rows)
+	if !ok {
+		return fmt.Errorf("sqltest: invalid type %T. expect *sql.Rows", v)
+	}
+	if err := ex.Query(ctx, query, args, rows); err != nil {
+		e.Err = err.Error()
+		return err
+	}
+	values, err := rowsdb.Collect(rows)
+	if err != nil {
+		e.Err = err.Error()
+		return err
+	}
+	e.Columns, e.Rows = values.Columns, make([][]Value, len(values.Rows))
+	for i, row := range values.Rows {
+		e.Rows[i] = make([]Value, len(row))
+		for j, v := range row {
+			e.Rows[i][j] = newValue(v)
+		}
+	}
+	return serve(ctx, values, rows)
+}
+
+// serve sets the buffered rows as the rows of the query.
+func serve(ctx context.Context, r *rowsdb.Result, rows *sql.Rows) error {
+	sr, err := r.Open(ctx)
+	if err != nil {
+		return err
+	}
+	rows.ColumnScanner = sr
+	return nil
+}
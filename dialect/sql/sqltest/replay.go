@@ -0,0 +1,176 @@
+package sqltest
+
+import (
+	"context"
+	stdsql "database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/syssam/velox/dialect"
+	"github.com/syssam/velox/dialect/sql"
+	"github.com/syssam/velox/dialect/sql/internal/rowsdb"
+)
+
+// ReplayDriver is a dialect.Driver that serves the exchanges of a recording,
+// in order, without a database.
+type ReplayDriver struct {
+	recorder
+	dialect string
+	// pending holds the exchanges that were not served yet.
+	pending []*Exchange
+}
+
+// NewReplayDriver returns a ReplayDriver that serves the exchanges of the given recording.
+func NewReplayDriver(r *Recording) *ReplayDriver {
+	return &ReplayDriver{
+		dialect: r.Dialect,
+		pending: append([]*Exchange(nil), r.Exchanges...),
+	}
+}
+
+// Dialect returns the dialect of the recording.
+func (d *ReplayDriver) Dialect() string { return d.dialect }
+
+// Close implements the dialect.Driver interface.
+func (*ReplayDriver) Close() error { return nil }
+
+// ExpectationsWereMet returns an error if some recorded exchanges were not served.
+func (d *ReplayDriver) ExpectationsWereMet() error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if len(d.pending) > 0 {
+		return fmt.Errorf("sqltest: %d exchanges were not replayed, next: %s", len(d.pending), d.pending[0])
+	}
+	return nil
+}
+
+// next serves the next recorded exchange, if it runs the same statement as e.
+func (d *ReplayDriver) next(e *Exchange) (*Exchange, error) {
+	d.mu.Lock()
+	if len(d.pending) == 0 {
+		d.mu.Unlock()
+		return nil, fmt.Errorf("sqltest: unexpected %s: all exchanges were replayed", e)
+	}
+	next := d.pending[0]
+	if !next.sameStatement(e) {
+		d.mu.Unlock()
+		return nil, fmt.Errorf("sqltest: unexpected %s, want %s", e, next)
+	}
+	d.pending = d.pending[1:]
+	d.mu.Unlock()
+	d.add(next)
+	if next.Err != "" {
+		return nil, replayError(next.Err)
+	}
+	return next, nil
+}
+
+// sentinels are the errors that are matched by errors.Is on replay,
+// if a recorded error ends with their text.
+var sentinels = []error{
+	stdsql.ErrNoRows,
+	stdsql.ErrTxDone,
+	stdsql.ErrConnDone,
+	driver.ErrBadConn,
+	context.Canceled,
+	context.DeadlineExceeded,
+}
+
+// replayError returns the error of a recorded exchange. Golden files only
+// hold the text of errors, so the known sentinel errors are restored from it.
+func replayError(msg string) error {
+	for _, err := range sentinels {
+		if msg == err.Error() {
+			return err
+		}
+		if strings.HasSuffix(msg, ": "+err.Error()) {
+			return fmt.Errorf("%s: %w", strings.TrimSuffix(msg, ": "+err.Error()), err)
+		}
+	}
+	return errors.New(msg)
+}
+
+// Exec serves the result of the next recorded exchange.
+func (d *ReplayDriver) Exec(_ context.Context, query string, args, v any) error {
+	rv, ok := v.(*sql.Result)
+	if !ok && v != nil {
+		return fmt.Errorf("sqltest: invalid type %T. expect *sql.Result", v)
+	}
+	e, err := d.next(&Exchange{Kind: KindExec, Query: query, Args: newArgs(args)})
+	if err != nil {
+		return err
+	}
+	if rv != nil {
+		*rv = replayResult{e}
+	}
+	return nil
+}
+
+// Query serves the rows of the next recorded exchange.
+func (d *ReplayDriver) Query(ctx context.Context, query string, args, v any) error {
+	rows, ok := v.(*sql.Rows)
+	if !ok {
+		return fmt.Errorf("sqltest: invalid type %T. expect *sql.Rows", v)
+	}
+	e, err := d.next(&Exchange{Kind: KindQuery, Query: query, Args: newArgs(args)})
+	if err != nil {
+		return err
+	}
+	r := &rowsdb.Result{Columns: e.Columns, Rows: make([][]driver.Value, len(e.Rows))}
+	for i, row := range e.Rows {
+		r.Rows[i] = make([]driver.Value, len(row))
+		for j, v := range row {
+			r.Rows[i][j] = v.V
+		}
+	}
+	return serve(ctx, r, rows)
+}
+
+// Tx serves the next recorded transaction.
+func (d *ReplayDriver) Tx(context.Context) (dialect.Tx, error) {
+	if _, err := d.next(&Exchange{Kind: KindBegin}); err != nil {
+		return nil, err
+	}
+	return &replayTx{d}, nil
+}
+
+// BeginTx serves the next recorded transaction. The options are not recorded.
+func (d *ReplayDriver) BeginTx(ctx context.Context, _ *sql.TxOptions) (dialect.Tx, error) {
+	return d.Tx(ctx)
+}
+
+// replayTx serves the statements of a transaction.
+type replayTx struct {
+	*ReplayDriver
+}
+
+func (t *replayTx) Commit() error {
+	_, err := t.next(&Exchange{Kind: KindCommit})
+	return err
+}
+
+func (t *replayTx) Rollback() error {
+	_, err := t.next(&Exchange{Kind: KindRollback})
+	return err
+}
+
+// replayResult is the sql.Result of a recorded exec.
+type replayResult struct {
+	e *Exchange
+}
+
+func (r replayResult) LastInsertId() (int64, error) {
+	if r.e.LastInsertID == nil {
+		return 0, errUnsupported
+	}
+	return *r.e.LastInsertID, nil
+}
+
+func (r replayResult) RowsAffected() (int64, error) {
+	if r.e.RowsAffected == nil {
+		return 0, errUnsupported
+	}
+	return *r.e.RowsAffected, nil
+}
@@ -0,0 +1,307 @@
+package sqltest
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// Kinds of the exchanges.
+const (
+	KindQuery    = "query"
+	KindExec     = "exec"
+	KindBegin    = "begin"
+	KindCommit   = "commit"
+	KindRollback = "rollback"
+)
+
+// Recording is the content of a golden file.
+type Recording struct {
+	Dialect   string      `json:"dialect"`
+	Exchanges []*Exchange `json:"exchanges"`
+}
+
+// Exchange is one statement and its outcome.
+type Exchange struct {
+	Kind  string  `json:"kind"`
+	Query string  `json:"query,omitempty"`
+	Args  []Value `json:"args,omitempty"`
+	// Columns and Rows hold the rows returned by a query.
+	Columns []string  `json:"columns,omitempty"`
+	Rows    [][]Value `json:"rows,omitempty"`
+	// LastInsertID and RowsAffected hold the result of an exec.
+	// They are nil if the driver does not support them.
+	LastInsertID *int64 `json:"last_insert_id,omitempty"`
+	RowsAffected *int64 `json:"rows_affected,omitempty"`
+	// Err is the error of the statement.
+	Err string `json:"error,omitempty"`
+}
+
+// String returns the statement of the exchange, with its arguments.
+func (e *Exchange) String() string {
+	if e.Query == "" {
+		return e.Kind
+	}
+	if len(e.Args) == 0 {
+		return fmt.Sprintf("%s %q", e.Kind, e.Query)
+	}
+	args, _ := json.Marshal(e.Args)
+	return fmt.Sprintf("%s %q %s", e.Kind, e.Query, args)
+}
+
+// sameStatement reports if the exchanges run the same statement with the same arguments.
+func (e *Exchange) sameStatement(o *Exchange) bool {
+	if e.Kind != o.Kind || e.Query != o.Query || len(e.Args) != len(o.Args) {
+		return false
+	}
+	a, _ := json.Marshal(e.Args)
+	b, _ := json.Marshal(o.Args)
+	return bytes.Equal(a, b)
+}
+
+// Value is a database value that keeps its type in JSON. Integers, floats,
+// strings and booleans are plain JSON values, while bytes and times are
+// objects:
+//
+//	{"bytes": "aGVsbG8="}
+//	{"time": "2024-01-01T00:00:00Z"}
+//
+// Decoded numbers are int64 if they have no fraction, and float64 otherwise.
+type Value struct {
+	V driver.Value
+}
+
+// newValue returns the Value of a statement argument or a scanned column.
+// Arguments are converted to driver values first.
+func newValue(v any) Value {
+	if valuer, ok := v.(driver.Valuer); ok {
+		dv, err := valuer.Value()
+		if err != nil {
+			return Value{V: fmt.Sprint(v)}
+		}
+		v = dv
+	}
+	switch v.(type) {
+	case nil, int64, float64, bool, string, []byte, time.Time:
+		return Value{V: v}
+	}
+	dv, err := driver.DefaultParameterConverter.ConvertValue(v)
+	if err != nil {
+		// Values such as slices are recorded by their text.
+		return Value{V: fmt.Sprint(v)}
+	}
+	return Value{V: dv}
+}
+
+// MarshalJSON implements the json.Marshaler interface.
+func (v Value) MarshalJSON() ([]byte, error) {
+	switch x := v.V.(type) {
+	case []byte:
+		return json.Marshal(map[string]string{"bytes": base64.StdEncoding.EncodeToString(x)})
+	case time.Time:
+		return json.Marshal(map[string]string{"time": x.Format(time.RFC3339Nano)})
+	default:
+		return json.Marshal(x)
+	}
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (v *Value) UnmarshalJSON(data []byte) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var x any
+	if err := dec.Decode(&x); err != nil {
+		return err
+	}
+	switch x := x.(type) {
+	case json.Number:
+		if i, err := x.Int64(); err == nil {
+			v.V = i
+			return nil
+		}
+		f, err := x.Float64()
+		v.V = f
+		return err
+	case map[string]any:
+		switch {
+		case x["bytes"] != nil:
+			s, _ := x["bytes"].(string)
+			b, err := base64.StdEncoding.DecodeString(s)
+			v.V = b
+			return err
+		case x["time"] != nil:
+			s, _ := x["time"].(string)
+			t, err := time.Parse(time.RFC3339Nano, s)
+			v.V = t
+			return err
+		}
+		return fmt.Errorf("sqltest: unknown value %s", data)
+	default:
+		v.V = x
+		return nil
+	}
+}
+
+// newArgs returns the values of the arguments of a statement.
+func newArgs(args any) []Value {
+	list, ok := args.([]any)
+	if !ok {
+		if args == nil {
+			return nil
+		}
+		list = []any{args}
+	}
+	if len(list) == 0 {
+		return nil
+	}
+	values := make([]Value, len(list))
+	for i, a := range list {
+		values[i] = newValue(a)
+	}
+	return values
+}
+
+// errString returns the text of err, or "" if err is nil.
+func errString(err error) string {
+	if err == nil {
+		return ""
+	}
+	return err.Error()
+}
+
+// ReadFile reads a golden file.
+func ReadFile(path string) (*Recording, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	r := &Recording{}
+	if err := json.Unmarshal(data, r); err != nil {
+		return nil, fmt.Errorf("sqltest: reading %s: %w", path, err)
+	}
+	return r, nil
+}
+
+// WriteFile writes the recording to a golden file, and creates its directory.
+func (r *Recording) WriteFile(path string) error {
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o644)
+}
+
+// Diff returns a description of the first difference between the statements
+// of the recordings, or "" if they run the same statements. The outcomes of
+// the statements (rows, results and errors) are not compared.
+func (r *Recording) Diff(other *Recording) string {
+	var b strings.Builder
+	if r.Dialect != other.Dialect {
+		fmt.Fprintf(&b, "dialect: %q != %q\n", r.Dialect, other.Dialect)
+	}
+	for i := range max(len(r.Exchanges), len(other.Exchanges)) {
+		switch {
+		case i >= len(r.Exchanges):
+			fmt.Fprintf(&b, "exchange %d: unexpected %s", i, other.Exchanges[i])
+		case i >= len(other.Exchanges):
+			fmt.Fprintf(&b, "exchange %d: missing %s", i, r.Exchanges[i])
+		case !r.Exchanges[i].sameStatement(other.Exchanges[i]):
+			fmt.Fprintf(&b, "exchange %d:\n\twant: %s\n\tgot:  %s", i, r.Exchanges[i], other.Exchanges[i])
+		default:
+			continue
+		}
+		break
+	}
+	return b.String()
+}
+
+// recorder holds the exchanges of a driver, and implements the assertions.
+type recorder struct {
+	mu        sync.Mutex
+	exchanges []*Exchange
+}
+
+func (r *recorder) add(e *Exchange) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.exchanges = append(r.exchanges, e)
+}
+
+// Exchanges returns the exchanges since the start or the last Reset.
+func (r *recorder) Exchanges() []*Exchange {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]*Exchange(nil), r.exchanges...)
+}
+
+// Reset forgets the exchanges, such that the counts start again at zero.
+// The exchanges of a RecordingDriver are still written to its golden file.
+func (r *recorder) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.exchanges = nil
+}
+
+// QueryCount returns the number of queries since the start or the last Reset.
+func (r *recorder) QueryCount() int {
+	return r.count(KindQuery)
+}
+
+// ExecCount returns the number of execs since the start or the last Reset.
+func (r *recorder) ExecCount() int {
+	return r.count(KindExec)
+}
+
+func (r *recorder) count(kind string) int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var n int
+	for _, e := range r.exchanges {
+		if e.Kind == kind {
+			n++
+		}
+	}
+	return n
+}
+
+// AssertQueryCount fails the test if the number of queries since the start
+// or the last Reset is not n. The failure lists the queries.
+func (r *recorder) AssertQueryCount(t testing.TB, n int) {
+	t.Helper()
+	r.assertCount(t, KindQuery, n)
+}
+
+// AssertExecCount fails the test if the number of execs since the start
+// or the last Reset is not n. The failure lists the execs.
+func (r *recorder) AssertExecCount(t testing.TB, n int) {
+	t.Helper()
+	r.assertCount(t, KindExec, n)
+}
+
+func (r *recorder) assertCount(t testing.TB, kind string, n int) {
+	t.Helper()
+	if got := r.count(kind); got != n {
+		var b strings.Builder
+		for _, e := range r.Exchanges() {
+			if e.Kind == kind {
+				fmt.Fprintf(&b, "\n\t%s", e)
+			}
+		}
+		t.Errorf("sqltest: got %d %s statements, want %d:%s", got, kind, n, b.String())
+	}
+}
+
+// errUnsupported is returned by the results of drivers that do not support LastInsertId or RowsAffected.
+var errUnsupported = errors.New("sqltest: not supported by the recorded driver")
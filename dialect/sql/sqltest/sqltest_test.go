@@ -0,0 +1,230 @@
+package sqltest
+
+import (
+	"context"
+	stdsql "database/sql"
+	"errors"
+	"path/filepath"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/syssam/velox/dialect"
+	"github.com/syssam/velox/dialect/sql"
+)
+
+var errLocked = errors.New("locked")
+
+// fakeT records the failures of a test helper.
+type fakeT struct {
+	testing.TB
+	errors []string
+}
+
+func (t *fakeT) Helper() {}
+
+func (t *fakeT) Errorf(format string, args ...any) {
+	t.errors = append(t.errors, format)
+}
+
+// run runs the statements of the tests on the given driver.
+func run(t *testing.T, drv dialect.Driver) {
+	t.Helper()
+	ctx := context.Background()
+	rows := &sql.Rows{}
+	require.NoError(t, drv.Query(ctx, "SELECT id, name, data, created_at FROM users WHERE age > ?", []any{30}, rows))
+	var (
+		ids   []int
+		names []string
+	)
+	for rows.Next() {
+		var (
+			id        int
+			name      string
+			data      []byte
+			createdAt time.Time
+		)
+		require.NoError(t, rows.Scan(&id, &name, &data, &createdAt))
+		require.Equal(t, []byte{1, 2}, data)
+		require.Equal(t, time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC), createdAt.UTC())
+		ids, names = append(ids, id), append(names, name)
+	}
+	require.NoError(t, rows.Close())
+	require.Equal(t, []int{1, 2}, ids)
+	require.Equal(t, []string{"a8m", "nati"}, names)
+
+	tx, err := drv.Tx(ctx)
+	require.NoError(t, err)
+	var res sql.Result
+	require.NoError(t, tx.Exec(ctx, "UPDATE users SET name = ? WHERE id = ?", []any{"ariel", 1}, &res))
+	n, err := res.RowsAffected()
+	require.NoError(t, err)
+	require.Equal(t, int64(1), n)
+	require.NoError(t, tx.Commit())
+
+	err = drv.Exec(ctx, "DELETE FROM users", []any{}, nil)
+	require.EqualError(t, err, "dialect/sql: exec: locked")
+}
+
+func TestRecordReplay(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT id, name, data, created_at FROM users WHERE age > ?")).
+		WithArgs(30).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "data", "created_at"}).
+			AddRow(1, "a8m", []byte{1, 2}, time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)).
+			AddRow(2, "nati", []byte{1, 2}, time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)))
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE users SET name = ? WHERE id = ?")).
+		WithArgs("ariel", 1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	mock.ExpectExec("DELETE FROM users").WillReturnError(errLocked)
+
+	path := filepath.Join(t.TempDir(), "testdata", "users.json")
+	rec := NewRecordingDriver(sql.OpenDB(dialect.MySQL, db))
+	run(t, rec)
+	require.NoError(t, mock.ExpectationsWereMet())
+	rec.AssertQueryCount(t, 1)
+	rec.AssertExecCount(t, 2)
+	require.NoError(t, rec.WriteFile(path))
+
+	r, err := ReadFile(path)
+	require.NoError(t, err)
+	require.Equal(t, dialect.MySQL, r.Dialect)
+	require.Len(t, r.Exchanges, 5)
+	require.Empty(t, r.Diff(rec.Recording()))
+
+	// The golden file serves the same code path without a database.
+	rep := NewReplayDriver(r)
+	require.Equal(t, dialect.MySQL, rep.Dialect())
+	run(t, rep)
+	require.NoError(t, rep.ExpectationsWereMet())
+	rep.AssertQueryCount(t, 1)
+}
+
+func TestReplay_Mismatch(t *testing.T) {
+	rep := NewReplayDriver(&Recording{
+		Dialect: dialect.Postgres,
+		Exchanges: []*Exchange{
+			{Kind: KindQuery, Query: "SELECT id FROM users WHERE id = $1", Args: []Value{{V: int64(1)}}, Columns: []string{"id"}},
+			{Kind: KindExec, Query: "DELETE FROM users"},
+		},
+	})
+	ctx := context.Background()
+	err := rep.Query(ctx, "SELECT id FROM users WHERE id = $1", []any{2}, &sql.Rows{})
+	require.ErrorContains(t, err, "sqltest: unexpected query")
+	require.ErrorContains(t, err, `[2]`)
+
+	rows := &sql.Rows{}
+	require.NoError(t, rep.Query(ctx, "SELECT id FROM users WHERE id = $1", []any{1}, rows))
+	require.False(t, rows.Next())
+	require.NoError(t, rows.Close())
+	require.ErrorContains(t, rep.ExpectationsWereMet(), "1 exchanges were not replayed")
+
+	var res sql.Result
+	require.NoError(t, rep.Exec(ctx, "DELETE FROM users", []any{}, &res))
+	_, err = res.LastInsertId()
+	require.ErrorIs(t, err, errUnsupported)
+	require.NoError(t, rep.ExpectationsWereMet())
+	require.ErrorContains(t, rep.Exec(ctx, "DELETE FROM users", []any{}, nil), "all exchanges were replayed")
+}
+
+func TestReplay_Sentinels(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	mock.ExpectQuery("SELECT id FROM users").WillReturnError(stdsql.ErrNoRows)
+	mock.ExpectExec("DELETE FROM users").WillReturnError(context.DeadlineExceeded)
+	mock.ExpectExec("DELETE FROM pets").WillReturnError(errLocked)
+	check := func(drv dialect.Driver) {
+		ctx := context.Background()
+		err := drv.Query(ctx, "SELECT id FROM users", []any{}, &sql.Rows{})
+		require.ErrorIs(t, err, stdsql.ErrNoRows)
+		require.EqualError(t, err, "dialect/sql: query: sql: no rows in result set")
+		err = drv.Exec(ctx, "DELETE FROM users", []any{}, nil)
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+		err = drv.Exec(ctx, "DELETE FROM pets", []any{}, nil)
+		require.EqualError(t, err, "dialect/sql: exec: locked")
+	}
+	rec := NewRecordingDriver(sql.OpenDB(dialect.Postgres, db))
+	check(rec)
+	require.NoError(t, mock.ExpectationsWereMet())
+
+	path := filepath.Join(t.TempDir(), "errors.json")
+	require.NoError(t, rec.WriteFile(path))
+	r, err := ReadFile(path)
+	require.NoError(t, err)
+	check(NewReplayDriver(r))
+}
+
+func TestRecord_Golden(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "golden.json")
+	record := func(t testing.TB, query string) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		mock.ExpectExec(regexp.QuoteMeta(query)).WillReturnResult(sqlmock.NewResult(1, 1))
+		drv := Record(t, sql.OpenDB(dialect.SQLite, db), path)
+		require.NoError(t, drv.Exec(context.Background(), query, []any{}, nil))
+	}
+	// A missing golden file is written.
+	t.Run("Write", func(t *testing.T) {
+		record(t, "DELETE FROM users")
+	})
+	r, err := ReadFile(path)
+	require.NoError(t, err)
+	require.Len(t, r.Exchanges, 1)
+	require.Equal(t, int64(1), *r.Exchanges[0].LastInsertID)
+
+	ft := &fakeT{TB: t}
+	t.Run("Match", func(t *testing.T) {
+		ft.TB = t
+		record(ft, "DELETE FROM users")
+	})
+	require.Empty(t, ft.errors)
+	t.Run("Differ", func(t *testing.T) {
+		ft.TB = t
+		record(ft, "DELETE FROM pets")
+	})
+	require.Len(t, ft.errors, 1)
+	require.Contains(t, ft.errors[0], "statements differ")
+}
+
+func TestAssertQueryCount(t *testing.T) {
+	rep := NewReplayDriver(&Recording{
+		Exchanges: []*Exchange{
+			{Kind: KindQuery, Query: "SELECT id FROM users"},
+			{Kind: KindQuery, Query: "SELECT id FROM pets WHERE owner_id = ?", Args: []Value{{V: int64(1)}}},
+			{Kind: KindQuery, Query: "SELECT id FROM pets WHERE owner_id = ?", Args: []Value{{V: int64(2)}}},
+		},
+	})
+	ctx := context.Background()
+	require.NoError(t, rep.Query(ctx, "SELECT id FROM users", []any{}, &sql.Rows{}))
+	rep.Reset()
+	for _, id := range []int{1, 2} {
+		require.NoError(t, rep.Query(ctx, "SELECT id FROM pets WHERE owner_id = ?", []any{id}, &sql.Rows{}))
+	}
+	require.Equal(t, 2, rep.QueryCount())
+	require.Len(t, rep.Exchanges(), 2)
+
+	ft := &fakeT{TB: t}
+	rep.AssertQueryCount(ft, 1)
+	require.Len(t, ft.errors, 1)
+	rep.AssertQueryCount(ft, 2)
+	require.Len(t, ft.errors, 1)
+}
+
+func TestValue_JSON(t *testing.T) {
+	for _, v := range []any{nil, int64(1), 1.5, "a8m", true, []byte("hello"), time.Date(2024, 1, 2, 3, 4, 5, 6, time.UTC)} {
+		data, err := newValue(v).MarshalJSON()
+		require.NoError(t, err)
+		var got Value
+		require.NoError(t, got.UnmarshalJSON(data))
+		require.Equal(t, v, got.V, string(data))
+	}
+	// Arguments are converted to driver values.
+	require.Equal(t, int64(1), newValue(uint8(1)).V)
+	require.Equal(t, "[1 2]", newValue([]int{1, 2}).V)
+}
@@ -82,6 +82,8 @@ func (s StatsSnapshot) String() string {
 }
 
 // SlowQueryHook is a function called when a slow query is detected.
+// The arguments are passed as they are. Use dialect.RedactArgs to drop
+// the values of sensitive columns before logging them.
 type SlowQueryHook func(ctx context.Context, query string, args []any, duration time.Duration)
 
 // StatsDriver wraps a Driver with query statistics collection.
@@ -114,10 +116,11 @@ func WithSlowQueryHook(hook SlowQueryHook) StatsOption {
 }
 
 // WithSlowQueryLog logs slow queries to the default logger.
-// This is a convenience wrapper around WithSlowQueryHook.
+// This is a convenience wrapper around WithSlowQueryHook. The arguments of
+// sensitive columns are redacted, see dialect.RedactArgs.
 func WithSlowQueryLog() StatsOption {
 	return WithSlowQueryHook(func(_ context.Context, query string, args []any, duration time.Duration) {
-		slog.Warn("slow query detected", "duration", duration, "query", query, "args", args)
+		slog.Warn("slow query detected", "duration", duration, "query", query, "args", dialect.RedactArgs(query, args))
 	})
 }
 
@@ -291,13 +294,13 @@ func NewLogDriver(drv *Driver, opts ...LogOption) *LogDriver {
 
 // Query executes a query and logs it.
 func (d *LogDriver) Query(ctx context.Context, query string, args, v any) error {
-	d.log(ctx, fmt.Sprintf("query: %s args: %v", query, args))
+	d.log(ctx, fmt.Sprintf("query: %s args: %v", query, logArgs(query, args)))
 	return d.Driver.Query(ctx, query, args, v)
 }
 
 // Exec executes a statement and logs it.
 func (d *LogDriver) Exec(ctx context.Context, query string, args, v any) error {
-	d.log(ctx, fmt.Sprintf("exec: %s args: %v", query, args))
+	d.log(ctx, fmt.Sprintf("exec: %s args: %v", query, logArgs(query, args)))
 	return d.Driver.Exec(ctx, query, args, v)
 }
 
@@ -335,13 +338,13 @@ type LogTx struct {
 
 // Query executes a query within the transaction and logs it.
 func (tx *LogTx) Query(ctx context.Context, query string, args, v any) error {
-	tx.log(ctx, fmt.Sprintf("tx query: %s args: %v", query, args))
+	tx.log(ctx, fmt.Sprintf("tx query: %s args: %v", query, logArgs(query, args)))
 	return tx.Tx.Query(ctx, query, args, v)
 }
 
 // Exec executes a statement within the transaction and logs it.
 func (tx *LogTx) Exec(ctx context.Context, query string, args, v any) error {
-	tx.log(ctx, fmt.Sprintf("tx exec: %s args: %v", query, args))
+	tx.log(ctx, fmt.Sprintf("tx exec: %s args: %v", query, logArgs(query, args)))
 	return tx.Tx.Exec(ctx, query, args, v)
 }
 
@@ -395,3 +398,12 @@ func OpenWithStats(driverName, source string, opts ...StatsOption) (*StatsDriver
 	stats := statsDriver.QueryStats()
 	return statsDriver, stats, nil
 }
+
+// logArgs returns the arguments of the query with the ones of
+// sensitive columns redacted, see dialect.RedactArgs.
+func logArgs(query string, args any) any {
+	if vs, ok := args.([]any); ok {
+		return dialect.RedactArgs(query, vs)
+	}
+	return args
+}
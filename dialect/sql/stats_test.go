@@ -2,9 +2,13 @@ package sql
 
 import (
 	"context"
+	"fmt"
 	"testing"
 	"time"
 
+	"github.com/syssam/velox/dialect"
+
+	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -180,3 +184,22 @@ func TestNewLogDriver_WithFunc(t *testing.T) {
 	dd := NewLogDriver(drv, LogWithFunc(func(_ context.Context, _ ...any) {}))
 	require.NotNil(t, dd)
 }
+
+func TestLogDriver_RedactArgs(t *testing.T) {
+	t.Parallel()
+
+	dialect.RegisterSensitiveColumns("log_users", "password")
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	mock.ExpectExec("UPDATE").WithArgs("secret", 1).WillReturnResult(sqlmock.NewResult(0, 1))
+	var logs []string
+	drv := NewLogDriver(OpenDB(dialect.Postgres, db), LogWithFunc(func(_ context.Context, v ...any) {
+		logs = append(logs, fmt.Sprint(v...))
+	}))
+	err = drv.Exec(context.Background(), `UPDATE "log_users" SET "password" = $1 WHERE "id" = $2`, []any{"secret", 1}, nil)
+	require.NoError(t, err)
+	require.Len(t, logs, 1)
+	assert.Contains(t, logs[0], "args: ["+dialect.Redacted+" 1]")
+	assert.NotContains(t, logs[0], "secret")
+	require.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,143 @@
+package sql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/syssam/velox/dialect"
+	"github.com/syssam/velox/dialect/sql/internal/rowsdb"
+)
+
+// VarDriver wraps a dialect.Driver and sets a session variable for every
+// statement, with the value that is derived from the statement context.
+// For example, the tenant of the viewer that PostgreSQL row-level security
+// policies compare the rows with:
+//
+//	drv = sql.NewVarDriver(drv, "app.tenant_id", privacy.TenantFromContext)
+//
+// The variable is set with WithVar. Hence, the wrapped driver must run its
+// statements with a Driver, and a variable that is already set in the context
+// is left as is.
+type VarDriver struct {
+	dialect.Driver
+	name  string
+	value func(context.Context) (string, bool)
+}
+
+// NewVarDriver wraps a driver with a session variable, whose value is returned
+// by the given function. The variable is not set if the function returns false.
+func NewVarDriver(drv dialect.Driver, name string, value func(context.Context) (string, bool)) *VarDriver {
+	return &VarDriver{Driver: drv, name: name, value: value}
+}
+
+// Query executes a query with the session variable of its context.
+func (d *VarDriver) Query(ctx context.Context, query string, args, v any) error {
+	return d.Driver.Query(d.withVar(ctx), query, args, v)
+}
+
+// Exec executes a statement with the session variable of its context.
+func (d *VarDriver) Exec(ctx context.Context, query string, args, v any) error {
+	return d.Driver.Exec(d.withVar(ctx), query, args, v)
+}
+
+// ExecContext executes a statement with the session variable of its context.
+func (d *VarDriver) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	return execContext(d.withVar(ctx), d.Driver, query, args)
+}
+
+// QueryContext executes a query with the session variable of its context.
+func (d *VarDriver) QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error) {
+	return queryContext(d.withVar(ctx), d.Driver, query, args)
+}
+
+// Tx starts a transaction whose statements set the session variable.
+func (d *VarDriver) Tx(ctx context.Context) (dialect.Tx, error) {
+	tx, err := d.Driver.Tx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &VarTx{Tx: tx, driver: d}, nil
+}
+
+// BeginTx starts a transaction with options whose statements set the session variable.
+func (d *VarDriver) BeginTx(ctx context.Context, opts *TxOptions) (dialect.Tx, error) {
+	drv, ok := d.Driver.(interface {
+		BeginTx(context.Context, *TxOptions) (dialect.Tx, error)
+	})
+	if !ok {
+		return nil, errors.New("dialect/sql: driver does not support transactions with options")
+	}
+	tx, err := drv.BeginTx(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	return &VarTx{Tx: tx, driver: d}, nil
+}
+
+// withVar returns the context with the session variable, if it has a value.
+func (d *VarDriver) withVar(ctx context.Context) context.Context {
+	if _, ok := VarFromContext(ctx, d.name); ok {
+		return ctx
+	}
+	if v, ok := d.value(ctx); ok {
+		return WithVar(ctx, d.name, v)
+	}
+	return ctx
+}
+
+// VarTx wraps a transaction of a VarDriver.
+type VarTx struct {
+	dialect.Tx
+	driver *VarDriver
+}
+
+// Query executes a query within the transaction with the session variable of its context.
+func (tx *VarTx) Query(ctx context.Context, query string, args, v any) error {
+	return tx.Tx.Query(tx.driver.withVar(ctx), query, args, v)
+}
+
+// Exec executes a statement within the transaction with the session variable of its context.
+func (tx *VarTx) Exec(ctx context.Context, query string, args, v any) error {
+	return tx.Tx.Exec(tx.driver.withVar(ctx), query, args, v)
+}
+
+// ExecContext executes a statement within the transaction with the session variable of its context.
+func (tx *VarTx) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	return execContext(tx.driver.withVar(ctx), tx.Tx, query, args)
+}
+
+// QueryContext executes a query within the transaction with the session variable of its context.
+func (tx *VarTx) QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error) {
+	return queryContext(tx.driver.withVar(ctx), tx.Tx, query, args)
+}
+
+// execContext executes a statement with the Exec method of ex, which sets the
+// session variables of the context, unlike the ExecContext method of a Conn.
+func execContext(ctx context.Context, ex dialect.ExecQuerier, query string, args []any) (sql.Result, error) {
+	var res sql.Result
+	if err := ex.Exec(ctx, query, args, &res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// queryContext executes a query with the Query method of ex, and streams its
+// rows as *sql.Rows. Closing the rows resets the session variables.
+func queryContext(ctx context.Context, ex dialect.ExecQuerier, query string, args []any) (*sql.Rows, error) {
+	rows := &Rows{}
+	if err := ex.Query(ctx, query, args, rows); err != nil {
+		return nil, err
+	}
+	dr, err := rowsdb.Stream(rows)
+	if err != nil {
+		return nil, err
+	}
+	return rowsdb.Open(ctx, dr)
+}
+
+// Ensure interfaces are implemented.
+var (
+	_ dialect.Driver = (*VarDriver)(nil)
+	_ dialect.Tx     = (*VarTx)(nil)
+)
@@ -0,0 +1,103 @@
+//go:build integration
+
+package sql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/syssam/velox/dialect"
+
+	_ "github.com/lib/pq"
+)
+
+// openPostgres opens a real PostgreSQL database using the VELOX_TEST_POSTGRES env var,
+// with a single connection, or skips the test if the env var is not set.
+func openPostgres(t *testing.T) *sql.DB {
+	t.Helper()
+	dsn := os.Getenv("VELOX_TEST_POSTGRES")
+	if dsn == "" {
+		t.Skip("VELOX_TEST_POSTGRES not set — skipping real Postgres test")
+	}
+	db, err := sql.Open("postgres", dsn)
+	require.NoError(t, err)
+	require.NoError(t, db.Ping(), "failed to ping Postgres")
+	// A single connection, such that leaked variables are seen by the next statements.
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+// currentTenant returns the tenant setting of the connection, or "" if it is not set.
+func currentTenant(t *testing.T, ctx context.Context, ex dialect.ExecQuerier) string {
+	t.Helper()
+	rows := &Rows{}
+	require.NoError(t, ex.Query(ctx, "SELECT COALESCE(current_setting('app.tenant_id', true), '')", []any{}, rows))
+	defer rows.Close()
+	v, err := ScanString(rows)
+	require.NoError(t, err)
+	return v
+}
+
+func TestVarDriver_Postgres(t *testing.T) {
+	ctx := context.Background()
+	db := openPostgres(t)
+	table := fmt.Sprintf("documents_%d", time.Now().UnixNano())
+	for _, stmt := range []string{
+		fmt.Sprintf(`CREATE TABLE %q ("id" serial PRIMARY KEY, "tenant_id" text NOT NULL)`, table),
+		fmt.Sprintf(`ALTER TABLE %q ENABLE ROW LEVEL SECURITY`, table),
+		// The test connects as the owner of the table.
+		fmt.Sprintf(`ALTER TABLE %q FORCE ROW LEVEL SECURITY`, table),
+		fmt.Sprintf(`CREATE POLICY "tenant_isolation" ON %q USING ("tenant_id" = current_setting('app.tenant_id', true))`, table),
+	} {
+		_, err := db.ExecContext(ctx, stmt)
+		require.NoError(t, err, stmt)
+	}
+	t.Cleanup(func() { db.ExecContext(ctx, fmt.Sprintf(`DROP TABLE IF EXISTS %q`, table)) })
+
+	drv := NewVarDriver(OpenDB(dialect.Postgres, db), "app.tenant_id", func(ctx context.Context) (string, bool) {
+		v, ok := ctx.Value(tenantKey{}).(string)
+		return v, ok
+	})
+	acme := context.WithValue(ctx, tenantKey{}, "acme")
+	globex := context.WithValue(ctx, tenantKey{}, "globex")
+	count := func(ctx context.Context, ex dialect.ExecQuerier) int {
+		rows := &Rows{}
+		require.NoError(t, ex.Query(ctx, fmt.Sprintf(`SELECT COUNT(*) FROM %q`, table), []any{}, rows))
+		defer rows.Close()
+		n, err := ScanInt(rows)
+		require.NoError(t, err)
+		return n
+	}
+
+	// Outside a transaction, the variable is set for the statement and reset after it.
+	insert := fmt.Sprintf(`INSERT INTO %q ("tenant_id") VALUES ($1)`, table)
+	require.NoError(t, drv.Exec(acme, insert, []any{"acme"}, nil))
+	require.NoError(t, drv.Exec(acme, insert, []any{"acme"}, nil))
+	require.NoError(t, drv.Exec(globex, insert, []any{"globex"}, nil))
+	require.Error(t, drv.Exec(acme, insert, []any{"globex"}, nil), "rows of other tenants are rejected")
+	require.Equal(t, 2, count(acme, drv))
+	require.Equal(t, 1, count(globex, drv))
+	require.Zero(t, count(ctx, drv), "statements without a tenant see no rows")
+	require.Empty(t, currentTenant(t, ctx, drv))
+
+	// In a transaction, the variable is local to the transaction.
+	tx, err := drv.Tx(acme)
+	require.NoError(t, err)
+	require.Equal(t, 2, count(acme, tx))
+	require.Equal(t, 1, count(WithVar(acme, "app.tenant_id", "globex"), tx))
+	require.NoError(t, tx.Commit())
+	require.Empty(t, currentTenant(t, ctx, drv))
+
+	tx, err = drv.Tx(globex)
+	require.NoError(t, err)
+	require.Equal(t, 1, count(globex, tx))
+	require.NoError(t, tx.Rollback())
+	require.Empty(t, currentTenant(t, ctx, drv))
+}
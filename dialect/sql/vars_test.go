@@ -0,0 +1,92 @@
+package sql
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/syssam/velox/dialect"
+)
+
+type tenantKey struct{}
+
+func TestVarDriver(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	require.NoError(t, err)
+	db.SetMaxOpenConns(1)
+	drv := NewVarDriver(OpenDB(dialect.Postgres, db), "app.tenant_id", func(ctx context.Context) (string, bool) {
+		v, ok := ctx.Value(tenantKey{}).(string)
+		return v, ok
+	})
+	ctx := context.WithValue(context.Background(), tenantKey{}, "acme")
+
+	mock.ExpectExec("SELECT set_config($1, $2, false)").WithArgs("app.tenant_id", "acme").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(`SELECT "id" FROM "users"`).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	mock.ExpectExec("RESET app.tenant_id").WillReturnResult(sqlmock.NewResult(0, 0))
+	rows := &Rows{}
+	require.NoError(t, drv.Query(ctx, `SELECT "id" FROM "users"`, []any{}, rows))
+	require.NoError(t, rows.Close())
+
+	// Statements without a value run as is.
+	mock.ExpectExec(`DELETE FROM "users"`).WillReturnResult(sqlmock.NewResult(0, 1))
+	require.NoError(t, drv.Exec(context.Background(), `DELETE FROM "users"`, []any{}, nil))
+
+	// Variables of the context take precedence.
+	mock.ExpectBegin()
+	mock.ExpectExec("SELECT set_config($1, $2, true)").WithArgs("app.tenant_id", "acme").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(`DELETE FROM "users"`).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("SELECT set_config($1, $2, true)").WithArgs("app.tenant_id", "globex").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(`DELETE FROM "users"`).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	tx, err := drv.BeginTx(ctx, nil)
+	require.NoError(t, err)
+	require.NoError(t, tx.Exec(ctx, `DELETE FROM "users"`, []any{}, nil))
+	require.NoError(t, tx.Exec(WithVar(ctx, "app.tenant_id", "globex"), `DELETE FROM "users"`, []any{}, nil))
+	require.NoError(t, tx.Commit())
+	require.NoError(t, mock.ExpectationsWereMet())
+
+	// The database/sql methods set the variable as well.
+	mock.ExpectExec("SELECT set_config($1, $2, false)").WithArgs("app.tenant_id", "acme").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(`SELECT "id" FROM "users" WHERE "id" > $1`).WithArgs(0).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1).AddRow(2))
+	mock.ExpectExec("RESET app.tenant_id").WillReturnResult(sqlmock.NewResult(0, 0))
+	sr, err := drv.QueryContext(ctx, `SELECT "id" FROM "users" WHERE "id" > $1`, 0)
+	require.NoError(t, err)
+	var ids []int
+	for sr.Next() {
+		var id int
+		require.NoError(t, sr.Scan(&id))
+		ids = append(ids, id)
+	}
+	require.NoError(t, sr.Err())
+	require.NoError(t, sr.Close())
+	require.Equal(t, []int{1, 2}, ids)
+
+	mock.ExpectExec("SELECT set_config($1, $2, false)").WithArgs("app.tenant_id", "acme").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(`DELETE FROM "users"`).WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectExec("RESET app.tenant_id").WillReturnResult(sqlmock.NewResult(0, 0))
+	res, err := drv.ExecContext(ctx, `DELETE FROM "users"`)
+	require.NoError(t, err)
+	n, err := res.RowsAffected()
+	require.NoError(t, err)
+	require.EqualValues(t, 2, n)
+
+	mock.ExpectBegin()
+	mock.ExpectExec("SELECT set_config($1, $2, true)").WithArgs("app.tenant_id", "acme").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(`DELETE FROM "users"`).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("SELECT set_config($1, $2, true)").WithArgs("app.tenant_id", "acme").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(`SELECT "id" FROM "users"`).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	mock.ExpectCommit()
+	tx, err = drv.Tx(ctx)
+	require.NoError(t, err)
+	varTx := tx.(*VarTx)
+	_, err = varTx.ExecContext(ctx, `DELETE FROM "users"`)
+	require.NoError(t, err)
+	sr, err = varTx.QueryContext(ctx, `SELECT "id" FROM "users"`)
+	require.NoError(t, err)
+	require.NoError(t, sr.Close())
+	require.NoError(t, tx.Commit())
+	require.NoError(t, mock.ExpectationsWereMet())
+}
@@ -122,6 +122,64 @@ func (c CascadeAction) ConstName() string {
 	}
 }
 
+// PartitionType is the partitioning strategy of a table. See PartitionBy.
+type PartitionType string
+
+const (
+	// Range partitions the rows by ranges of the partition key (e.g., the months of a timestamp).
+	Range PartitionType = "RANGE"
+	// List partitions the rows by lists of partition key values (PostgreSQL only).
+	List PartitionType = "LIST"
+	// Hash partitions the rows by the hash of the partition key.
+	Hash PartitionType = "HASH"
+)
+
+// ConstName returns the constant name of a partition type (e.g., "Range", "Hash").
+// Used by code generation templates for printing the constant name.
+func (p PartitionType) ConstName() string {
+	switch p {
+	case Range:
+		return "Range"
+	case List:
+		return "List"
+	case Hash:
+		return "Hash"
+	default:
+		return string(p)
+	}
+}
+
+// Partition defines the partition key of a partitioned table.
+type Partition struct {
+	// Type is the partitioning strategy.
+	Type PartitionType
+	// Columns are the columns of the partition key.
+	Columns []string
+}
+
+// TenantSetting is the default PostgreSQL setting that holds the tenant of
+// the session in row-level security policies. See TenantPolicy.
+const TenantSetting = "app.tenant_id"
+
+// RowSecurity defines the PostgreSQL row-level security policy of a table,
+// that limits the rows of the table to the tenant of the session.
+type RowSecurity struct {
+	// Column is the tenant column of the table.
+	Column string
+	// Setting is the setting that holds the tenant of the session.
+	// Defaults to TenantSetting.
+	Setting string
+	// Force applies the policy to the owner of the table as well, as
+	// applications usually connect with the role that owns the tables.
+	// Defaults to true. Set it to new(bool) to exempt the owner.
+	Force *bool
+}
+
+// Forced reports if the policy applies to the owner of the table.
+func (rs RowSecurity) Forced() bool {
+	return rs.Force == nil || *rs.Force
+}
+
 // Annotation holds SQL-specific settings for fields and edges.
 // Can be used with functional constructors or struct literals:
 //
@@ -194,6 +252,15 @@ type Annotation struct {
 	// Map from dialect name to SQL definition.
 	ViewFor map[string]string
 
+	// Materialized indicates the view is a materialized view. See Materialized.
+	Materialized bool
+
+	// Partition sets the partition key of the table. See PartitionBy.
+	Partition *Partition
+
+	// RowSecurity sets the row-level security policy of the table. See TenantPolicy.
+	RowSecurity *RowSecurity
+
 	// IndexType sets the index access method (BTREE, HASH, GIN, etc.).
 	IndexType string
 
@@ -212,6 +279,15 @@ type Annotation struct {
 	// DefaultExprs provides dialect-specific default expressions.
 	// Map from dialect name to expression string.
 	DefaultExprs map[string]string
+
+	// RenamedFrom is the previous column name of a renamed field. While set,
+	// generated mutations write both columns and the expand/contract planner
+	// treats the change as a rename instead of a drop and add.
+	RenamedFrom string
+
+	// Retyped reports that the renamed column also changes its type. The
+	// generated mutations then write the new column only. See RetypedFrom.
+	Retyped bool
 }
 
 // IndexAnnotation holds SQL-specific settings for indexes.
@@ -253,6 +329,15 @@ type IndexAnnotation struct {
 
 	// IncludeColumns specifies columns to include in a covering index.
 	IncludeColumns []string
+
+	// FullText marks the index as a full-text index. It is migrated to a GIN
+	// index on a tsvector expression in PostgreSQL, a FULLTEXT index in MySQL,
+	// and an FTS5 table in SQLite, and enables the generated Search predicate.
+	FullText bool
+
+	// FullTextConfig sets the text search configuration of a full-text
+	// index (PostgreSQL). Defaults to "english".
+	FullTextConfig string
 }
 
 // Name implements schema.Annotation for IndexAnnotation.
@@ -320,6 +405,12 @@ func (a IndexAnnotation) Merge(other schema.Annotation) schema.Annotation {
 	if ant.IncludeColumns != nil {
 		a.IncludeColumns = append(a.IncludeColumns, ant.IncludeColumns...)
 	}
+	if ant.FullText {
+		a.FullText = ant.FullText
+	}
+	if ant.FullTextConfig != "" {
+		a.FullTextConfig = ant.FullTextConfig
+	}
 	return a
 }
 
@@ -415,6 +506,15 @@ func (a Annotation) Merge(other schema.Annotation) schema.Annotation {
 		}
 		maps.Copy(a.ViewFor, ant.ViewFor)
 	}
+	if ant.Materialized {
+		a.Materialized = true
+	}
+	if ant.Partition != nil {
+		a.Partition = ant.Partition
+	}
+	if ant.RowSecurity != nil {
+		a.RowSecurity = ant.RowSecurity
+	}
 	if ant.ColumnType != "" {
 		a.ColumnType = ant.ColumnType
 	}
@@ -430,6 +530,12 @@ func (a Annotation) Merge(other schema.Annotation) schema.Annotation {
 	if ant.PrefixColumns {
 		a.PrefixColumns = ant.PrefixColumns
 	}
+	if ant.RenamedFrom != "" {
+		a.RenamedFrom = ant.RenamedFrom
+	}
+	if ant.Retyped {
+		a.Retyped = true
+	}
 	if ant.err != nil {
 		a.err = errors.Join(a.err, ant.err)
 	}
@@ -685,6 +791,41 @@ func IncrementStart(start int) Annotation {
 	return Annotation{IncrementStart: &start}
 }
 
+// RenamedFrom marks a field whose column was renamed from the given column.
+// It drives a zero-downtime rename: the planner adds the new column, backfills
+// it from the old one and drops the old one in a separate contract migration,
+// and generated mutations keep writing both columns in between. Remove the
+// annotation once the contract migration is applied.
+//
+// Example:
+//
+//	field.String("full_name").
+//	    Annotations(sql.RenamedFrom("name"))
+func RenamedFrom(column string) Annotation {
+	if column == "" || strings.ContainsAny(column, " \t\n;'\"`") {
+		panic(fmt.Errorf("sqlschema.RenamedFrom: invalid column name %q", column))
+	}
+	return Annotation{RenamedFrom: column}
+}
+
+// RetypedFrom marks a field whose column was renamed from the given column
+// with another type. It is planned like RenamedFrom, and the backfill casts
+// the old values on PostgreSQL, but generated mutations only write the new
+// column, as its values may not fit the old one. Hence, the rows written by
+// the new release are not visible to the previous release during the rollout.
+// The expand phase makes the old column nullable, so these rows can be inserted.
+//
+// Example:
+//
+//	field.Int("age_years").
+//	    Annotations(sql.RetypedFrom("age"))
+func RetypedFrom(column string) Annotation {
+	if column == "" || strings.ContainsAny(column, " \t\n;'\"`") {
+		panic(fmt.Errorf("sqlschema.RetypedFrom: invalid column name %q", column))
+	}
+	return Annotation{RenamedFrom: column, Retyped: true}
+}
+
 // Skip marks this entity/field to be skipped in SQL generation.
 //
 // Example:
@@ -825,6 +966,30 @@ func IndexWhere(pred string) *IndexAnnotation {
 	return &IndexAnnotation{Where: pred}
 }
 
+// FullText returns a new index annotation that marks the index as a full-text
+// index. The indexed fields must be strings, and a type can have at most one
+// full-text index.
+//
+// Example:
+//
+//	index.Fields("title", "body").Annotations(sqlschema.FullText())
+//	// PostgreSQL: CREATE INDEX "post_title_body" ON "posts" USING GIN ((to_tsvector('english', ...)))
+//	// MySQL:      CREATE FULLTEXT INDEX `post_title_body` ON `posts` (`title`, `body`)
+//	// SQLite:     CREATE VIRTUAL TABLE `post_title_body` USING fts5(...)
+func FullText() *IndexAnnotation {
+	return &IndexAnnotation{FullText: true}
+}
+
+// FullTextConfig returns a new full-text index annotation with the given
+// text search configuration. PostgreSQL-specific.
+//
+// Example:
+//
+//	index.Fields("title", "body").Annotations(sqlschema.FullTextConfig("simple"))
+func FullTextConfig(config string) *IndexAnnotation {
+	return &IndexAnnotation{FullText: true, FullTextConfig: config}
+}
+
 // View returns an annotation that defines a view with the given SQL query.
 // Use this for entities that represent database views instead of tables.
 //
@@ -861,6 +1026,74 @@ func ViewFor(d string, fn func(*sql.Selector)) *Annotation {
 	return &Annotation{ViewFor: map[string]string{d: q}}
 }
 
+// Materialized returns an annotation that makes a view a materialized view.
+// It is used with View or ViewFor, and the indexes of the schema are created
+// on the view. On PostgreSQL, the view is migrated as a MATERIALIZED VIEW.
+// On MySQL and SQLite, which lack materialized views, it is migrated as a
+// snapshot table that is filled with the rows of the view query.
+//
+// The generated client has a RefreshXxx method that recomputes the rows:
+//
+//	func (PostStats) Annotations() []schema.Annotation {
+//	    return []schema.Annotation{
+//	        sqlschema.View("SELECT author_id, COUNT(*) AS posts FROM posts GROUP BY author_id"),
+//	        sqlschema.Materialized(),
+//	    }
+//	}
+//
+//	client.RefreshPostStats(ctx, true)
+func Materialized() *Annotation {
+	return &Annotation{Materialized: true}
+}
+
+// PartitionBy returns a table annotation that partitions the table by the
+// given columns. On PostgreSQL, the table is created with a PARTITION BY
+// clause. On MySQL, RANGE tables are created with a catch-all partition
+// (pmax), HASH tables are partitioned by KEY, and LIST is not supported.
+// MySQL does not support foreign keys on partitioned tables, so they are
+// left out of the migration. SQLite tables are not partitioned.
+//
+// The primary key of the table is extended with the partition key, as both
+// databases require it, and its unique indexes must include the partition key.
+//
+//	func (Event) Annotations() []schema.Annotation {
+//	    return []schema.Annotation{
+//	        sqlschema.PartitionBy(sqlschema.Range, "created_at"),
+//	    }
+//	}
+//
+// The partitions of RANGE tables are created ahead of time with
+// schema.CreatePartitions, and detached with schema.DetachPartition.
+func PartitionBy(typ PartitionType, columns ...string) *Annotation {
+	return &Annotation{Partition: &Partition{Type: typ, Columns: columns}}
+}
+
+// TenantPolicy returns a table annotation that isolates the rows of the
+// table by the given tenant column. On PostgreSQL, the migration enables
+// row-level security on the table, and creates a policy that limits the
+// rows to the tenant of the session (the TenantSetting setting):
+//
+//	ALTER TABLE "users" ENABLE ROW LEVEL SECURITY
+//	ALTER TABLE "users" FORCE ROW LEVEL SECURITY
+//	CREATE POLICY "users_tenant_isolation" ON "users" USING ("tenant_id" = current_setting('app.tenant_id', true))
+//
+// The generated client sets the tenant of the viewer in the context
+// (privacy.ViewerFromContext) for every statement. Types that use the
+// mixin.TenantID mixin get this policy with the sql/rowsecurity feature.
+//
+//	func (Document) Annotations() []schema.Annotation {
+//	    return []schema.Annotation{
+//	        sqlschema.TenantPolicy("org_id"),
+//	    }
+//	}
+//
+// The policy applies to the owner of the table as well (FORCE ROW LEVEL
+// SECURITY), unless Force is set to false.
+// Other dialects ignore this annotation.
+func TenantPolicy(column string) *Annotation {
+	return &Annotation{RowSecurity: &RowSecurity{Column: column, Setting: TenantSetting}}
+}
+
 // Getters for use by generators.
 
 // GetTable returns the table name and whether it was set.
@@ -939,6 +1172,11 @@ func (a Annotation) GetStorageParams() string {
 	return a.StorageParams
 }
 
+// GetRenamedFrom returns the previous column name and whether it was set.
+func (a Annotation) GetRenamedFrom() (string, bool) {
+	return a.RenamedFrom, a.RenamedFrom != ""
+}
+
 // Merge combines multiple SQL annotations into one.
 // Later annotations override earlier ones for scalar fields; maps are merged.
 func Merge(annotations ...Annotation) Annotation {
@@ -1019,12 +1257,27 @@ func Merge(annotations ...Annotation) Annotation {
 			}
 			maps.Copy(result.ViewFor, a.ViewFor)
 		}
+		if a.Materialized {
+			result.Materialized = true
+		}
+		if a.Partition != nil {
+			result.Partition = a.Partition
+		}
+		if a.RowSecurity != nil {
+			result.RowSecurity = a.RowSecurity
+		}
 		if a.Prefix != "" {
 			result.Prefix = a.Prefix
 		}
 		if a.PrefixColumns {
 			result.PrefixColumns = true
 		}
+		if a.RenamedFrom != "" {
+			result.RenamedFrom = a.RenamedFrom
+		}
+		if a.Retyped {
+			result.Retyped = true
+		}
 	}
 	return result
 }
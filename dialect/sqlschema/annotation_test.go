@@ -122,6 +122,30 @@ func TestConstructor_Collation(t *testing.T) {
 	assert.Equal(t, "utf8mb4_unicode_ci", a.Collation)
 }
 
+func TestConstructor_RenamedFrom(t *testing.T) {
+	a := RenamedFrom("name")
+	assert.Equal(t, "name", a.RenamedFrom)
+	old, ok := a.GetRenamedFrom()
+	assert.True(t, ok)
+	assert.Equal(t, "name", old)
+	_, ok = Annotation{}.GetRenamedFrom()
+	assert.False(t, ok)
+	assert.Equal(t, "name", Annotation{Size: 1}.Merge(a).(Annotation).RenamedFrom)
+	assert.Equal(t, "name", Merge(a, Annotation{Size: 1}).RenamedFrom)
+	assert.Panics(t, func() { RenamedFrom("") })
+	assert.Panics(t, func() { RenamedFrom("name; DROP TABLE users") })
+}
+
+func TestConstructor_RetypedFrom(t *testing.T) {
+	a := RetypedFrom("age")
+	assert.Equal(t, "age", a.RenamedFrom)
+	assert.True(t, a.Retyped)
+	assert.True(t, Annotation{Size: 1}.Merge(a).(Annotation).Retyped)
+	assert.True(t, Merge(a, Annotation{Size: 1}).Retyped)
+	assert.False(t, RenamedFrom("age").Retyped)
+	assert.Panics(t, func() { RetypedFrom("") })
+}
+
 func TestConstructor_Check(t *testing.T) {
 	a := Check("age >= 0")
 	assert.Equal(t, "age >= 0", a.Check)
@@ -189,6 +213,12 @@ func TestConstructor_ViewFor(t *testing.T) {
 	assert.Contains(t, a.ViewFor["postgres"], "pets")
 }
 
+func TestConstructor_Materialized(t *testing.T) {
+	a := Materialized()
+	require.NotNil(t, a)
+	assert.True(t, a.Materialized)
+}
+
 // ---------------------------------------------------------------------------
 // Annotation.Name (interface compliance)
 // ---------------------------------------------------------------------------
@@ -403,6 +433,16 @@ func TestIndexAnnotation_Merge_AllFields(t *testing.T) {
 	assert.Equal(t, uint(5), result.PrefixColumns["name"])
 }
 
+func TestIndexAnnotation_Merge_FullText(t *testing.T) {
+	merged := FullText().Merge(FullTextConfig("simple")).(IndexAnnotation)
+	assert.True(t, merged.FullText)
+	assert.Equal(t, "simple", merged.FullTextConfig)
+
+	merged = FullTextConfig("simple").Merge(Desc()).(IndexAnnotation)
+	assert.True(t, merged.FullText)
+	assert.Equal(t, "simple", merged.FullTextConfig)
+}
+
 func TestIndexAnnotation_Merge_TypeOpClassPrefix(t *testing.T) {
 	// Exercises the three bodies not hit by AllFields (which keeps those in receiver, not other).
 	base := IndexAnnotation{Where: "x = 1"}
@@ -592,6 +632,36 @@ func TestMerge_ViewAs(t *testing.T) {
 	assert.Equal(t, "SELECT 2", merged.ViewAs)
 }
 
+func TestMerge_Materialized(t *testing.T) {
+	merged := Merge(*View("SELECT 1"), *Materialized())
+	assert.Equal(t, "SELECT 1", merged.ViewAs)
+	assert.True(t, merged.Materialized)
+}
+
+func TestConstructor_PartitionBy(t *testing.T) {
+	a := PartitionBy(Range, "created_at")
+	require.NotNil(t, a.Partition)
+	assert.Equal(t, Range, a.Partition.Type)
+	assert.Equal(t, []string{"created_at"}, a.Partition.Columns)
+}
+
+func TestMerge_Partition(t *testing.T) {
+	merged := Merge(Annotation{Table: "events"}, *PartitionBy(Hash, "tenant_id"))
+	assert.Equal(t, "events", merged.Table)
+	require.NotNil(t, merged.Partition)
+	assert.Equal(t, Hash, merged.Partition.Type)
+}
+
+func TestConstructor_TenantPolicy(t *testing.T) {
+	a := TenantPolicy("org_id")
+	require.NotNil(t, a.RowSecurity)
+	assert.Equal(t, RowSecurity{Column: "org_id", Setting: TenantSetting}, *a.RowSecurity)
+
+	merged := Merge(Annotation{Table: "documents"}, *a)
+	assert.Equal(t, "documents", merged.Table)
+	assert.Equal(t, a.RowSecurity, merged.RowSecurity)
+}
+
 func TestMerge_IncrementStart(t *testing.T) {
 	start := 1000
 	a := Annotation{IncrementStart: &start}
@@ -1038,6 +1108,12 @@ func TestAnnotation_Merge_SkipIsSticky(t *testing.T) {
 	assert.True(t, merged.Skip)
 }
 
+func TestAnnotation_Merge_MaterializedIsSticky(t *testing.T) {
+	a := Annotation{Materialized: true}
+	merged := a.Merge(Annotation{ViewAs: "SELECT 1"}).(Annotation)
+	assert.True(t, merged.Materialized)
+}
+
 func TestAnnotation_Merge_PointerFields(t *testing.T) {
 	enabled := true
 	a := Annotation{}
@@ -1092,6 +1168,8 @@ func TestAnnotation_Merge_AllScalarFields(t *testing.T) {
 		Prefix:         "p_",
 		PrefixColumns:  true,
 		IncrementStart: &start,
+		Partition:      &Partition{Type: List, Columns: []string{"region"}},
+		RowSecurity:    &RowSecurity{Column: "tenant_id", Force: new(bool)},
 	}
 	merged := Annotation{}.Merge(other).(Annotation)
 
@@ -1115,6 +1193,10 @@ func TestAnnotation_Merge_AllScalarFields(t *testing.T) {
 	assert.True(t, merged.PrefixColumns)
 	require.NotNil(t, merged.IncrementStart)
 	assert.Equal(t, 500, *merged.IncrementStart)
+	require.NotNil(t, merged.RowSecurity)
+	assert.False(t, merged.RowSecurity.Forced())
+	require.NotNil(t, merged.Partition)
+	assert.Equal(t, List, merged.Partition.Type)
 }
 
 func TestAnnotation_Merge_ErrPropagation(t *testing.T) {
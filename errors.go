@@ -194,11 +194,29 @@ type ValidationError struct {
 	Err    error  // Underlying validation error
 	Entity string // Entity type name (e.g., "User")
 	Field  string // Field name (e.g., "name") — same as Name for field validations
+	// Fields lists the field paths involved in a failed entity validator
+	// (e.g., "starts_at", "ends_at"). Empty for single-field validations.
+	Fields []string
 }
 
 // Error returns the error string.
 func (e *ValidationError) Error() string {
-	return fmt.Sprintf("velox: validator failed for field %q: %s", e.Name, e.Err)
+	switch {
+	case len(e.Fields) > 1:
+		return fmt.Sprintf("velox: validator failed for fields %s: %s", quoteFields(e.Fields), e.Err)
+	case e.Name == "" && e.Entity != "":
+		return fmt.Sprintf("velox: validator failed for %s: %s", e.Entity, e.Err)
+	default:
+		return fmt.Sprintf("velox: validator failed for field %q: %s", e.Name, e.Err)
+	}
+}
+
+func quoteFields(fields []string) string {
+	quoted := make([]string, len(fields))
+	for i, f := range fields {
+		quoted[i] = fmt.Sprintf("%q", f)
+	}
+	return strings.Join(quoted, ", ")
 }
 
 // Unwrap returns the underlying error.
@@ -211,6 +229,17 @@ func NewValidationError(name string, err error) *ValidationError {
 	return &ValidationError{Name: name, Err: err}
 }
 
+// NewEntityValidationError returns a new ValidationError for an entity
+// validator that failed on the given fields. The entity name is filled in
+// by the generated builders.
+func NewEntityValidationError(err error, fields ...string) *ValidationError {
+	e := &ValidationError{Err: err, Fields: fields}
+	if len(fields) > 0 {
+		e.Name, e.Field = fields[0], fields[0]
+	}
+	return e
+}
+
 // IsValidationError returns true if the error is a ValidationError.
 func IsValidationError(err error) bool {
 	if err == nil {
@@ -395,3 +424,29 @@ func IsPrivacyError(err error) bool {
 	var target *PrivacyError
 	return errors.As(err, &target)
 }
+
+// RestrictError is returned by the generated delete builders when the deleted
+// entities have dependents on an edge that is annotated with edge.Cascade(edge.Restrict).
+type RestrictError struct {
+	Entity string // Entity type being deleted
+	Edge   string // Edge that holds the dependents
+}
+
+// Error returns the error string.
+func (e *RestrictError) Error() string {
+	return fmt.Sprintf("velox: delete %s restricted by dependents on edge %q", e.Entity, e.Edge)
+}
+
+// NewRestrictError returns a new RestrictError.
+func NewRestrictError(entity, edge string) *RestrictError {
+	return &RestrictError{Entity: entity, Edge: edge}
+}
+
+// IsRestrictError returns true if the error is a RestrictError.
+func IsRestrictError(err error) bool {
+	if err == nil {
+		return false
+	}
+	var target *RestrictError
+	return errors.As(err, &target)
+}
@@ -184,6 +184,21 @@ func TestValidationError(t *testing.T) {
 		assert.Equal(t, "", err.Field)
 		assert.Equal(t, `velox: validator failed for field "age": must be positive`, err.Error())
 	})
+
+	t.Run("EntityValidationError", func(t *testing.T) {
+		err := velox.NewEntityValidationError(errors.New("must start before it ends"), "starts_at", "ends_at")
+		assert.Equal(t, []string{"starts_at", "ends_at"}, err.Fields)
+		assert.Equal(t, "starts_at", err.Name)
+		assert.Equal(t, "starts_at", err.Field)
+		assert.Equal(t, `velox: validator failed for fields "starts_at", "ends_at": must start before it ends`, err.Error())
+
+		single := velox.NewEntityValidationError(errors.New("required"), "coupon_code")
+		assert.Equal(t, `velox: validator failed for field "coupon_code": required`, single.Error())
+
+		none := velox.NewEntityValidationError(errors.New("invalid"))
+		none.Entity = "Event"
+		assert.Equal(t, `velox: validator failed for Event: invalid`, none.Error())
+	})
 }
 
 func TestRollbackError(t *testing.T) {
@@ -368,6 +383,14 @@ func TestPrivacyError(t *testing.T) {
 	})
 }
 
+func TestRestrictError(t *testing.T) {
+	err := velox.NewRestrictError("User", "posts")
+	assert.Equal(t, `velox: delete User restricted by dependents on edge "posts"`, err.Error())
+	assert.True(t, velox.IsRestrictError(fmt.Errorf("wrapper: %w", err)))
+	assert.False(t, velox.IsRestrictError(errors.New("other")))
+	assert.False(t, velox.IsRestrictError(nil))
+}
+
 func TestAggregateError_EmptyErrors(t *testing.T) {
 	agg := &velox.AggregateError{Errors: []error{}}
 	assert.Equal(t, "velox: no errors", agg.Error())
@@ -6,6 +6,7 @@ import (
 
 	"example.com/json-field/schema"
 	"example.com/json-field/velox"
+	"example.com/json-field/velox/product"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -60,3 +61,30 @@ func TestJSONField(t *testing.T) {
 	// Slice — stored as JSON array.
 	assert.Equal(t, []string{"featured", "new"}, loaded.Tags)
 }
+
+// TestJSONFieldPaths queries the values of the typed Specs struct, and
+// updates one of them in place, keeping the other values of the column.
+func TestJSONFieldPaths(t *testing.T) {
+	ctx := context.Background()
+	client, err := velox.Open("sqlite", "file:json_paths.db?mode=memory&_pragma=foreign_keys(1)")
+	require.NoError(t, err)
+	defer func() { require.NoError(t, client.Close()) }()
+
+	require.NoError(t, client.Schema.Create(ctx))
+
+	p := client.Product.Create().
+		SetName("Widget").
+		SetSpecs(schema.Specs{Weight: 1.25, Color: "graphite", Features: []string{"wireless"}}).
+		SaveX(ctx)
+
+	n, err := client.Product.Query().
+		Where(product.SpecsField.Color.EQ("graphite"), product.SpecsField.Weight.LT(2)).
+		Count(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, n)
+
+	// Only "color" is written, "weight_kg" and "features" are kept.
+	require.NoError(t, client.Product.UpdateOneID(p.ID).SetSpecsColor("silver").Exec(ctx))
+	loaded := client.Product.GetX(ctx, p.ID)
+	assert.Equal(t, schema.Specs{Weight: 1.25, Color: "silver", Features: []string{"wireless"}}, loaded.Specs)
+}
@@ -0,0 +1,19 @@
+// Package factory provides the value generators of the test data factories
+// that are generated by the "factory" feature.
+//
+// The generated factory package has one factory per entity type. A factory
+// fills the required fields of a create builder with deterministic values
+// derived from a per-type sequence number, and creates the required parent
+// entities with their own factories:
+//
+//	f := factory.New(client)
+//	post := f.Post().CreateX(ctx) // creates the required author too.
+//	posts := f.Post().With(func(c *postclient.PostCreate) {
+//	    c.SetAuthorID(author.ID)
+//	}).CreateManyX(ctx, 10)
+//
+// The values honor the constraints of the built-in validators: MinLen, MaxLen,
+// MinRuneLen, MaxRuneLen, Match, Range, Min, Max, and the values of enum fields. Custom validators
+// (Validate) are opaque to the generator. Fields with such validators should
+// be set with a trait.
+package factory
@@ -0,0 +1,139 @@
+package factory
+
+import (
+	"encoding/binary"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// Sequence is a counter of the entities created by a factory.
+// The zero value is ready to use, and the first number is 1.
+type Sequence struct {
+	n atomic.Int64
+}
+
+// Next returns the next number of the sequence. It is safe for concurrent use.
+func (s *Sequence) Next() int {
+	return int(s.n.Add(1))
+}
+
+// number is the constraint of the numeric field types.
+type number interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 |
+		~float32 | ~float64
+}
+
+// String returns the n-th value of a string field. The value is the prefix
+// followed by the sequence number, e.g. "name-1". Values shorter than minLen
+// are padded, and values longer than a positive maxLen keep their last maxLen
+// bytes, such that the sequence number is kept.
+func String(n int, prefix string, minLen, maxLen int) string {
+	s := prefix + "-" + strconv.Itoa(n)
+	if len(s) < minLen {
+		s += strings.Repeat("x", minLen-len(s))
+	}
+	if maxLen > 0 && len(s) > maxLen {
+		s = s[len(s)-maxLen:]
+	}
+	return s
+}
+
+// Match returns the n-th value of a string field that matches the pattern.
+// The sequence number picks the characters of the character classes of the
+// pattern, such that different numbers produce different values as long as
+// the pattern allows it. Unbounded repetitions are extended until the value
+// has minLen bytes.
+//
+// If no value that matches the pattern and the length limits is found, Match
+// returns a best-effort value, and the validator of the field fails on save.
+func Match(n int, pattern string, minLen, maxLen int) string {
+	s, _ := fromPattern(n-1, pattern, minLen, maxLen)
+	return s
+}
+
+// Number returns the n-th value of an unconstrained numeric field, n.
+func Number[T number](n int) T {
+	return T(n)
+}
+
+// Range returns the n-th value of a numeric field in the range [lo, hi].
+// Integers cycle through the range, starting at lo. Floats are strictly
+// between the bounds, unless lo equals hi.
+func Range[T number](n int, lo, hi T) T {
+	switch {
+	case lo >= hi:
+		return lo
+	case isFloat[T]():
+		return lo + (hi-lo)/T(n+1)
+	}
+	// The span is computed in 64 bits, and the addition wraps around in T.
+	span := uint64(int64(hi)-int64(lo)) + 1
+	if isUnsigned[T]() {
+		span = uint64(hi) - uint64(lo) + 1
+	}
+	offset := uint64(n - 1)
+	if span != 0 {
+		offset %= span
+	}
+	return lo + T(offset)
+}
+
+// Min returns the n-th value of a numeric field with a lower bound. Integers
+// count up from lo, and floats from lo+1, since the bound of a float field
+// can be exclusive (Positive).
+func Min[T number](n int, lo T) T {
+	if isFloat[T]() {
+		return lo + T(n)
+	}
+	return lo + T(n-1)
+}
+
+// Max returns the n-th value of a numeric field with an upper bound. Integers
+// count down from hi, and floats from hi-1, since the bound of a float field
+// can be exclusive (Negative). Unsigned integers cycle through [0, hi].
+func Max[T number](n int, hi T) T {
+	switch {
+	case isFloat[T]():
+		return hi - T(n)
+	case isUnsigned[T]():
+		return Range(n, 0, hi)
+	}
+	return hi - T(n-1)
+}
+
+// Enum returns the n-th value of an enum field. The values are used in turn.
+func Enum[T ~string](n int, values ...T) T {
+	return values[(n-1)%len(values)]
+}
+
+// epoch is the first value of the time fields.
+var epoch = time.Date(2000, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+// Time returns the n-th value of a time field: n hours after 2000-01-01 UTC.
+// The values have no sub-second part, so they survive the round-trip to
+// databases with second precision.
+func Time(n int) time.Time {
+	return epoch.Add(time.Duration(n) * time.Hour)
+}
+
+// UUID returns the n-th value of a UUID field. The value is a valid version 4
+// UUID that holds n in its last bytes, e.g. 00000000-0000-4000-8000-000000000001.
+func UUID(n int) [16]byte {
+	var u [16]byte
+	binary.BigEndian.PutUint64(u[8:], uint64(n))
+	u[6] = 0x40
+	u[8] = u[8]&0x3f | 0x80
+	return u
+}
+
+func isFloat[T number]() bool {
+	return T(1)/2 != 0
+}
+
+func isUnsigned[T number]() bool {
+	var zero T
+	return zero-1 > zero
+}
@@ -0,0 +1,92 @@
+package factory
+
+import (
+	"regexp"
+	"sync"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSequence(t *testing.T) {
+	var (
+		s  Sequence
+		wg sync.WaitGroup
+	)
+	for range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			s.Next()
+		}()
+	}
+	wg.Wait()
+	require.Equal(t, 11, s.Next())
+}
+
+func TestString(t *testing.T) {
+	require.Equal(t, "name-1", String(1, "name", 0, 0))
+	require.Equal(t, "name-1xxx", String(1, "name", 9, 0))
+	require.Equal(t, "e-12", String(12, "name", 0, 4))
+}
+
+func TestMatch(t *testing.T) {
+	tests := []struct {
+		pattern        string
+		minLen, maxLen int
+	}{
+		{pattern: `^[a-z]+$`},
+		{pattern: `^[a-z]+$`, minLen: 5},
+		{pattern: `^[A-Z]{2}-\d{4}$`},
+		{pattern: `^\w+@example\.(com|org)$`, minLen: 15, maxLen: 20},
+		{pattern: `^[^\s]+$`},
+		{pattern: `(?i)^sku_[0-9a-f]{8}$`},
+		{pattern: `^\d*$`, minLen: 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.pattern, func(t *testing.T) {
+			re := regexp.MustCompile(tt.pattern)
+			seen := make(map[string]bool)
+			for n := 1; n <= 5; n++ {
+				s, ok := fromPattern(n-1, tt.pattern, tt.minLen, tt.maxLen)
+				require.True(t, ok, s)
+				require.Regexp(t, re, s)
+				require.GreaterOrEqual(t, len(s), tt.minLen)
+				require.Equal(t, s, Match(n, tt.pattern, tt.minLen, tt.maxLen), "Match must be deterministic")
+				seen[s] = true
+			}
+			require.Len(t, seen, 5, "values must differ")
+		})
+	}
+	_, ok := fromPattern(0, `^a{10}$`, 0, 5)
+	require.False(t, ok)
+}
+
+func TestNumbers(t *testing.T) {
+	require.Equal(t, 3, Number[int](3))
+	require.Equal(t, []int{18, 19, 20, 18}, []int{Range(1, 18, 20), Range(2, 18, 20), Range(3, 18, 20), Range(4, 18, 20)})
+	require.Equal(t, int8(-128), Range[int8](1, -128, 127))
+	require.Equal(t, 0.5, Range(1, 0.0, 1.0))
+	require.Equal(t, 1, Min(1, 1))
+	require.Equal(t, 1.0, Min(1, 0.0), "float bounds can be exclusive")
+	require.Equal(t, -1, Max(2, 0))
+	require.Equal(t, -1.0, Max(1, 0.0))
+	require.Equal(t, uint8(0), Max[uint8](4, 2))
+}
+
+func TestEnum(t *testing.T) {
+	type status string
+	values := []status{"active", "inactive"}
+	require.Equal(t, status("active"), Enum(1, values...))
+	require.Equal(t, status("inactive"), Enum(2, values...))
+	require.Equal(t, status("active"), Enum(3, values...))
+}
+
+func TestTimeAndUUID(t *testing.T) {
+	require.Equal(t, "2000-01-01T01:00:00Z", Time(1).Format("2006-01-02T15:04:05Z07:00"))
+	u := uuid.UUID(UUID(1))
+	require.Equal(t, "00000000-0000-4000-8000-000000000001", u.String())
+	require.Equal(t, uuid.Version(4), u.Version())
+	require.Equal(t, uuid.RFC4122, u.Variant())
+}
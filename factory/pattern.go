@@ -0,0 +1,128 @@
+package factory
+
+import (
+	"regexp"
+	"regexp/syntax"
+	"strings"
+	"unicode/utf8"
+)
+
+// maxClassRunes caps the candidate runes of a character class.
+const maxClassRunes = 128
+
+// fromPattern returns the value for the (zero-based) index i that matches the
+// pattern, and reports if the value matches the pattern and the length limits.
+func fromPattern(i int, pattern string, minLen, maxLen int) (string, bool) {
+	re, err := syntax.Parse(pattern, syntax.Perl)
+	if err != nil {
+		return "", false
+	}
+	re = re.Simplify()
+	matcher, err := regexp.Compile(pattern)
+	if err != nil {
+		return "", false
+	}
+	var s string
+	for extra := 0; extra <= max(minLen, 1); extra++ {
+		g := &patternGen{seq: i, extra: extra}
+		g.gen(re)
+		s = g.b.String()
+		ok := len(s) >= minLen && (maxLen <= 0 || len(s) <= maxLen) && matcher.MatchString(s)
+		if ok {
+			return s, true
+		}
+		// Only an unbounded repetition can make the value longer.
+		if !g.extended || (maxLen > 0 && len(s) > maxLen) {
+			break
+		}
+	}
+	return s, false
+}
+
+// patternGen writes a value that matches a simplified regular expression.
+type patternGen struct {
+	b strings.Builder
+	// seq is the remainder of the sequence index. Every character class
+	// consumes a digit of it, in the base of its number of candidates.
+	seq int
+	// extra is the number of extra repetitions of the first
+	// unbounded repetition, used for reaching the minimum length.
+	extra    int
+	extended bool
+}
+
+func (g *patternGen) gen(re *syntax.Regexp) {
+	switch re.Op {
+	case syntax.OpLiteral:
+		for _, r := range re.Rune {
+			g.b.WriteRune(r)
+		}
+	case syntax.OpCharClass:
+		g.b.WriteRune(g.pick(classRunes(re.Rune)))
+	case syntax.OpAnyChar, syntax.OpAnyCharNotNL:
+		g.b.WriteRune(g.pick(classRunes([]rune{'a', 'z'})))
+	case syntax.OpCapture:
+		g.gen(re.Sub[0])
+	case syntax.OpConcat:
+		for _, sub := range re.Sub {
+			g.gen(sub)
+		}
+	case syntax.OpAlternate:
+		g.gen(re.Sub[0])
+	case syntax.OpStar, syntax.OpPlus:
+		g.repeat(re.Sub[0], 1, true)
+	case syntax.OpQuest:
+		g.repeat(re.Sub[0], 1, false)
+	case syntax.OpRepeat:
+		n := re.Min
+		if n == 0 && re.Max != 0 {
+			n = 1
+		}
+		g.repeat(re.Sub[0], n, re.Max == -1)
+	}
+	// Empty-width assertions (^, $, \b, etc.) and OpEmptyMatch write nothing.
+}
+
+func (g *patternGen) repeat(re *syntax.Regexp, n int, unbounded bool) {
+	if unbounded && !g.extended {
+		g.extended = true
+		n += g.extra
+	}
+	for range n {
+		g.gen(re)
+	}
+}
+
+// pick returns the candidate for the next digit of the sequence index.
+func (g *patternGen) pick(runes []rune) rune {
+	if len(runes) == 0 {
+		return utf8.RuneError
+	}
+	r := runes[g.seq%len(runes)]
+	g.seq /= len(runes)
+	return r
+}
+
+// classRunes returns the candidate runes of a character class, given as
+// pairs of ranges. Printable ASCII runes are preferred over others.
+func classRunes(ranges []rune) []rune {
+	var printable, other []rune
+	for i := 0; i+1 < len(ranges); i += 2 {
+		for r := ranges[i]; r <= ranges[i+1] && len(printable) < maxClassRunes; r++ {
+			switch {
+			case r > ' ' && r <= '~':
+				printable = append(printable, r)
+			case len(other) == 0:
+				other = append(other, r)
+			}
+			// Skip the rest of a large non-ASCII range.
+			if r > '~' {
+				break
+			}
+		}
+	}
+	if len(printable) > 0 {
+		return printable
+	}
+	return other
+}
@@ -0,0 +1,44 @@
+// Package outbox implements the transactional outbox pattern for Velox ORM.
+//
+// Tx.OnCommit hooks run after the commit, in memory: a crash between the
+// commit and the publish loses the event. The outbox writes the event in the
+// same transaction as the mutation instead, and a Relay publishes it later.
+//
+// # Recording Events
+//
+// Enable the sql/outbox feature and annotate the types whose mutations
+// are published:
+//
+//	func (Order) Annotations() []schema.Annotation {
+//	    return []schema.Annotation{
+//	        outbox.Events(),                                // all operations
+//	        // outbox.Events(velox.OpCreate, velox.OpDelete), // or some of them
+//	    }
+//	}
+//
+// The generated migrate package includes the outbox table (NewTable), and
+// every annotated type gets Hook as its innermost mutation hook. The hook
+// writes one row per created, updated or deleted entity, in the transaction
+// of the mutation. Mutations of annotated types must run in a transaction,
+// or they fail with ErrTxRequired (Annotation.AllowNonTx allows them, at the
+// cost of writing their events after they commit):
+//
+//	err := ent.WithTx(ctx, client, func(tx *ent.Tx) error {
+//	    _, err := tx.Order.Create().SetTotal(42).Save(ctx)
+//	    return err
+//	})
+//
+// # Relaying Events
+//
+// A Relay polls the pending rows, hands them to a Publisher and marks them
+// dispatched in one transaction per batch:
+//
+//	relay := outbox.NewRelay(drv, outbox.PublisherFunc(func(ctx context.Context, e *outbox.Event) error {
+//	    return broker.Send(ctx, e.Topic, e.Payload)
+//	}), outbox.WithMaxAttempts(5))
+//	go relay.Run(ctx)
+//
+// Failed events are retried with an exponential backoff. After MaxAttempts
+// failures they are moved to the dead letters (status "dead"), from where
+// Requeue moves them back.
+package outbox
@@ -0,0 +1,181 @@
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/syssam/velox"
+	"github.com/syssam/velox/dialect"
+	"github.com/syssam/velox/dialect/sql"
+)
+
+// HookConfig configures the outbox hook of a type. The generated
+// code fills it from the type schema and its Annotation.
+type HookConfig struct {
+	// Type is the schema type of the mutations.
+	Type string
+	// Topic is the topic of the events. Defaults to Type.
+	Topic string
+	// Ops is the set of recorded operations. Defaults to all of them.
+	Ops velox.Op
+	// Table and IDColumn select the ids of the rows matched by
+	// bulk updates and deletes.
+	Table, IDColumn string
+	// Omit lists the fields left out of the payloads, such as
+	// sensitive fields.
+	Omit []string
+	// AllowNonTx allows the mutations that do not run in a transaction,
+	// instead of failing them with ErrTxRequired. See Annotation.AllowNonTx.
+	AllowNonTx bool
+	// Driver returns the driver of a mutation.
+	Driver func(velox.Mutation) dialect.Driver
+}
+
+// Hook returns the hook that records the mutations of a type in the outbox.
+// The generated code registers it as the innermost hook of every type
+// annotated with Annotation.
+//
+// Events are written with the driver of the mutation, after the mutation
+// succeeds, so they commit or roll back with it. Mutations that do not run
+// in a transaction fail with ErrTxRequired, unless AllowNonTx is set. Then,
+// the events are written after the mutation commits, and are lost if the
+// process stops in between. Bulk updates and
+// deletes record one event per matched row; the ids are selected, and
+// locked in transactions where the dialect supports it, before the
+// mutation runs.
+func Hook(c HookConfig) velox.Hook {
+	if c.Topic == "" {
+		c.Topic = c.Type
+	}
+	if c.Ops == 0 {
+		c.Ops = velox.OpCreate | velox.OpUpdate | velox.OpUpdateOne | velox.OpDelete | velox.OpDeleteOne
+	}
+	return func(next velox.Mutator) velox.Mutator {
+		return velox.MutateFunc(func(ctx context.Context, m velox.Mutation) (velox.Value, error) {
+			op := m.Op()
+			if !op.Is(c.Ops) {
+				return next.Mutate(ctx, m)
+			}
+			drv := c.Driver(m)
+			_, inTx := drv.(dialect.Tx)
+			if !inTx && !c.AllowNonTx {
+				return nil, fmt.Errorf("%s: %w", c.Type, ErrTxRequired)
+			}
+			var (
+				ids []string
+				err error
+			)
+			if op.Is(velox.OpUpdate | velox.OpDelete | velox.OpDeleteOne) {
+				if ids, err = c.selectIDs(ctx, drv, m, inTx); err != nil {
+					return nil, err
+				}
+			}
+			v, err := next.Mutate(ctx, m)
+			if err != nil {
+				return v, err
+			}
+			events, err := c.events(m, v, ids)
+			if err != nil {
+				return nil, err
+			}
+			if err := Write(ctx, drv, drv.Dialect(), events...); err != nil {
+				return nil, err
+			}
+			return v, nil
+		})
+	}
+}
+
+// selectIDs returns the ids of the rows matched by the predicates of m,
+// and locks the rows if lock is set.
+func (c *HookConfig) selectIDs(ctx context.Context, drv dialect.Driver, m velox.Mutation, lock bool) ([]string, error) {
+	p, ok := m.(interface {
+		PredicatesFuncs() []func(*sql.Selector)
+	})
+	if !ok {
+		return nil, fmt.Errorf("outbox: unexpected mutation type %T", m)
+	}
+	s := sql.Dialect(drv.Dialect()).Select(c.IDColumn).From(sql.Table(c.Table))
+	if lock {
+		s.ForUpdate()
+	}
+	for _, pred := range p.PredicatesFuncs() {
+		pred(s)
+	}
+	query, args := s.Query()
+	rows := &sql.Rows{}
+	if err := drv.Query(ctx, query, args, rows); err != nil {
+		return nil, fmt.Errorf("outbox: select %s ids: %w", c.Type, err)
+	}
+	defer rows.Close()
+	var ids []string
+	for rows.Next() {
+		var id any
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		if b, ok := id.([]byte); ok {
+			id = string(b)
+		}
+		ids = append(ids, fmt.Sprint(id))
+	}
+	return ids, rows.Err()
+}
+
+// events returns the events of a mutation that returned v.
+func (c *HookConfig) events(m velox.Mutation, v velox.Value, ids []string) ([]*Event, error) {
+	switch op := m.Op(); {
+	case op.Is(velox.OpCreate | velox.OpUpdateOne):
+		idm, ok := m.(interface{ ID() (any, bool) })
+		if !ok {
+			return nil, fmt.Errorf("outbox: unexpected mutation type %T", m)
+		}
+		id, _ := idm.ID()
+		payload, err := c.payload(v)
+		if err != nil {
+			return nil, err
+		}
+		name := "update"
+		if op.Is(velox.OpCreate) {
+			name = "create"
+		}
+		return []*Event{c.event(name, fmt.Sprint(id), payload)}, nil
+	case op.Is(velox.OpUpdate):
+		payload, err := Changes(m, c.Omit...)
+		if err != nil {
+			return nil, err
+		}
+		events := make([]*Event, len(ids))
+		for i, id := range ids {
+			events[i] = c.event("update", id, payload)
+		}
+		return events, nil
+	default:
+		events := make([]*Event, len(ids))
+		for i, id := range ids {
+			events[i] = c.event("delete", id, nil)
+		}
+		return events, nil
+	}
+}
+
+func (c *HookConfig) event(op, id string, payload json.RawMessage) *Event {
+	return &Event{Topic: c.Topic, EntityType: c.Type, EntityID: id, Op: op, Payload: payload}
+}
+
+// payload returns the JSON of the entity, without the omitted fields.
+func (c *HookConfig) payload(v velox.Value) (json.RawMessage, error) {
+	b, err := json.Marshal(v)
+	if err != nil || len(c.Omit) == 0 {
+		return b, err
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(b, &fields); err != nil {
+		return nil, err
+	}
+	for _, name := range c.Omit {
+		delete(fields, name)
+	}
+	return json.Marshal(fields)
+}
@@ -0,0 +1,234 @@
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/syssam/velox"
+	"github.com/syssam/velox/dialect"
+	"github.com/syssam/velox/dialect/sql"
+	"github.com/syssam/velox/dialect/sql/schema"
+	"github.com/syssam/velox/schema/field"
+)
+
+// AnnotationName is the annotation name of Annotation.
+const AnnotationName = "Outbox"
+
+// Annotation is a schema annotation that records the mutations of a type in
+// the transactional outbox. It requires the sql/outbox feature in codegen:
+//
+//	func (Order) Annotations() []schema.Annotation {
+//	    return []schema.Annotation{
+//	        outbox.Events(),
+//	    }
+//	}
+type Annotation struct {
+	// Topic is the topic of the events of the type.
+	// Defaults to the type name.
+	Topic string `json:"topic,omitempty"`
+
+	// Ops is the set of operations recorded in the outbox.
+	// Defaults to all create, update and delete operations.
+	Ops velox.Op `json:"ops,omitempty"`
+
+	// AllowNonTx allows the mutations of the type that do not run in a
+	// transaction. By default, they fail with ErrTxRequired. Their events
+	// are written after they commit, and are lost if the process stops in
+	// between. A failed write returns an error for a mutation that was
+	// committed, and must not be retried as is.
+	AllowNonTx bool `json:"allow_non_tx,omitempty"`
+}
+
+// Name implements the schema.Annotation interface.
+func (Annotation) Name() string {
+	return AnnotationName
+}
+
+// Events returns an Annotation that records the given operations of the
+// type, or all of them if none is given.
+func Events(ops ...velox.Op) *Annotation {
+	a := &Annotation{}
+	for _, op := range ops {
+		a.Ops |= op
+	}
+	return a
+}
+
+// Topic returns an Annotation that records all operations of the type
+// under the given topic.
+func Topic(topic string) *Annotation {
+	return &Annotation{Topic: topic}
+}
+
+// Columns of the outbox table.
+const (
+	// TableName is the name of the outbox table.
+	TableName = "velox_outbox"
+
+	ColumnID           = "id"
+	ColumnTopic        = "topic"
+	ColumnEntityType   = "entity_type"
+	ColumnEntityID     = "entity_id"
+	ColumnOp           = "op"
+	ColumnPayload      = "payload"
+	ColumnStatus       = "status"
+	ColumnAttempts     = "attempts"
+	ColumnLastError    = "last_error"
+	ColumnCreatedAt    = "created_at"
+	ColumnAvailableAt  = "available_at"
+	ColumnDispatchedAt = "dispatched_at"
+)
+
+// Status values of the outbox rows.
+const (
+	// StatusPending marks events waiting to be published.
+	StatusPending = "pending"
+	// StatusDispatched marks events that were published.
+	StatusDispatched = "dispatched"
+	// StatusDead marks events that failed MaxAttempts times.
+	StatusDead = "dead"
+)
+
+// NewTable returns the schema of the outbox table. The generated migrate
+// package adds it to its Tables when the sql/outbox feature is enabled.
+func NewTable() *schema.Table {
+	t := schema.NewTable(TableName).
+		AddPrimary(&schema.Column{Name: ColumnID, Type: field.TypeInt64, Increment: true}).
+		AddColumn(&schema.Column{Name: ColumnTopic, Type: field.TypeString, Size: 255}).
+		AddColumn(&schema.Column{Name: ColumnEntityType, Type: field.TypeString, Size: 255}).
+		AddColumn(&schema.Column{Name: ColumnEntityID, Type: field.TypeString, Size: 255}).
+		AddColumn(&schema.Column{Name: ColumnOp, Type: field.TypeString, Size: 16}).
+		AddColumn(&schema.Column{Name: ColumnPayload, Type: field.TypeJSON, Nullable: true}).
+		AddColumn(&schema.Column{Name: ColumnStatus, Type: field.TypeString, Size: 16, Default: StatusPending}).
+		AddColumn(&schema.Column{Name: ColumnAttempts, Type: field.TypeInt, Default: 0}).
+		AddColumn(&schema.Column{Name: ColumnLastError, Type: field.TypeString, Size: 2147483647, Nullable: true}).
+		AddColumn(&schema.Column{Name: ColumnCreatedAt, Type: field.TypeTime}).
+		AddColumn(&schema.Column{Name: ColumnAvailableAt, Type: field.TypeTime}).
+		AddColumn(&schema.Column{Name: ColumnDispatchedAt, Type: field.TypeTime, Nullable: true})
+	return t.AddIndex("velox_outbox_status_available_at", false, []string{ColumnStatus, ColumnAvailableAt})
+}
+
+// Event is a mutation recorded in the outbox.
+type Event struct {
+	// ID is the id of the outbox row.
+	ID int64
+	// Topic is the topic of the event. See Annotation.Topic.
+	Topic string
+	// EntityType is the schema type of the mutated entity.
+	EntityType string
+	// EntityID is the id of the mutated entity, formatted with fmt.Sprint.
+	EntityID string
+	// Op is the operation: "create", "update" or "delete".
+	Op string
+	// Payload is the JSON of the entity after a create or an UpdateOne,
+	// the changes of a bulk Update (see Changes), or null after a delete.
+	Payload json.RawMessage
+	// Attempts is the number of failed attempts to publish the event.
+	Attempts int
+	// CreatedAt is the time the event was recorded.
+	CreatedAt time.Time
+}
+
+// ErrTxRequired is returned by the outbox hook for mutations that do not
+// run in a transaction, unless the type sets AllowNonTx. Events are written in
+// the transaction of the mutation, so a mutation outside a transaction
+// could commit without its event. Use client.Tx or the generated WithTx
+// helper.
+var ErrTxRequired = errors.New("outbox: mutations of outbox types must run in a transaction")
+
+// Write inserts the events in the outbox table using the given driver.
+// The events are pending and available for the relay immediately.
+func Write(ctx context.Context, drv dialect.ExecQuerier, dialectName string, events ...*Event) error {
+	if len(events) == 0 {
+		return nil
+	}
+	now := time.Now().UTC()
+	insert := sql.Dialect(dialectName).Insert(TableName).Columns(
+		ColumnTopic, ColumnEntityType, ColumnEntityID, ColumnOp, ColumnPayload,
+		ColumnStatus, ColumnAttempts, ColumnCreatedAt, ColumnAvailableAt,
+	)
+	for _, e := range events {
+		var payload any
+		if len(e.Payload) > 0 {
+			// Strings, not bytes: lib/pq encodes []byte as bytea,
+			// which PostgreSQL does not cast to json.
+			payload = string(e.Payload)
+		}
+		insert.Values(e.Topic, e.EntityType, e.EntityID, e.Op, payload, StatusPending, 0, now, now)
+	}
+	query, args := insert.Query()
+	if err := drv.Exec(ctx, query, args, nil); err != nil {
+		return fmt.Errorf("outbox: write events: %w", err)
+	}
+	return nil
+}
+
+// pathValue is a value set at a path of a JSON field, in the payload of a bulk update.
+type pathValue struct {
+	Path  []string `json:"path"`
+	Value any      `json:"value"`
+}
+
+// Changes returns the JSON payload of a bulk update: the fields set by the
+// mutation with their values, the numeric fields added to, the cleared
+// fields, the values appended to JSON fields and the values set at the
+// paths of JSON fields. The omit fields (e.g. sensitive fields) are left out.
+func Changes(m velox.Mutation, omit ...string) (json.RawMessage, error) {
+	skip := make(map[string]bool, len(omit))
+	for _, name := range omit {
+		skip[name] = true
+	}
+	var changes struct {
+		Fields  map[string]any `json:"fields,omitempty"`
+		Added   map[string]any `json:"added,omitempty"`
+		Cleared []string       `json:"cleared,omitempty"`
+		// Appended and Paths hold the changes that patch JSON
+		// fields in place (see velox.PatchMutation).
+		Appended map[string]any         `json:"appended,omitempty"`
+		Paths    map[string][]pathValue `json:"paths,omitempty"`
+	}
+	for _, name := range m.Fields() {
+		if v, ok := m.Field(name); ok && !skip[name] {
+			if changes.Fields == nil {
+				changes.Fields = make(map[string]any)
+			}
+			changes.Fields[name] = v
+		}
+	}
+	for _, name := range m.AddedFields() {
+		if v, ok := m.AddedField(name); ok && !skip[name] {
+			if changes.Added == nil {
+				changes.Added = make(map[string]any)
+			}
+			changes.Added[name] = v
+		}
+	}
+	for _, name := range m.ClearedFields() {
+		if !skip[name] {
+			changes.Cleared = append(changes.Cleared, name)
+		}
+	}
+	if p, ok := m.(velox.PatchMutation); ok {
+		for _, name := range p.PatchedFields() {
+			if skip[name] {
+				continue
+			}
+			if v, ok := p.AppendedField(name); ok {
+				if changes.Appended == nil {
+					changes.Appended = make(map[string]any)
+				}
+				changes.Appended[name] = v
+			}
+			for _, pv := range p.FieldPaths(name) {
+				if changes.Paths == nil {
+					changes.Paths = make(map[string][]pathValue)
+				}
+				changes.Paths[name] = append(changes.Paths[name], pathValue{Path: pv.Path, Value: pv.Value})
+			}
+		}
+	}
+	return json.Marshal(changes)
+}
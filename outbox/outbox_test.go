@@ -0,0 +1,325 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/syssam/velox"
+	"github.com/syssam/velox/dialect"
+	"github.com/syssam/velox/dialect/sql"
+	"github.com/syssam/velox/dialect/sql/sqljson"
+)
+
+// mockMutation is a minimal mutation of a "User" type, with the
+// ID and PredicatesFuncs methods of the generated mutations.
+type mockMutation struct {
+	op      velox.Op
+	id      any
+	fields  map[string]velox.Value
+	added   map[string]velox.Value
+	cleared []string
+	preds   []func(*sql.Selector)
+	drv     dialect.Driver
+}
+
+func (m *mockMutation) Op() velox.Op { return m.op }
+func (m *mockMutation) Type() string { return "User" }
+func (m *mockMutation) ID() (any, bool) {
+	return m.id, m.id != nil
+}
+func (m *mockMutation) PredicatesFuncs() []func(*sql.Selector) { return m.preds }
+
+func (m *mockMutation) Fields() []string {
+	names := make([]string, 0, len(m.fields))
+	for name := range m.fields {
+		names = append(names, name)
+	}
+	return names
+}
+func (m *mockMutation) Field(name string) (velox.Value, bool) {
+	v, ok := m.fields[name]
+	return v, ok
+}
+func (m *mockMutation) AddedFields() []string {
+	names := make([]string, 0, len(m.added))
+	for name := range m.added {
+		names = append(names, name)
+	}
+	return names
+}
+func (m *mockMutation) AddedField(name string) (velox.Value, bool) {
+	v, ok := m.added[name]
+	return v, ok
+}
+func (m *mockMutation) ClearedFields() []string            { return m.cleared }
+func (m *mockMutation) SetField(string, velox.Value) error { return nil }
+func (m *mockMutation) AddField(string, velox.Value) error { return nil }
+func (m *mockMutation) FieldCleared(string) bool           { return false }
+func (m *mockMutation) ClearField(string) error            { return nil }
+func (m *mockMutation) ResetField(string) error            { return nil }
+func (m *mockMutation) AddedEdges() []string               { return nil }
+func (m *mockMutation) AddedIDs(string) []velox.Value      { return nil }
+func (m *mockMutation) RemovedEdges() []string             { return nil }
+func (m *mockMutation) RemovedIDs(string) []velox.Value    { return nil }
+func (m *mockMutation) ClearedEdges() []string             { return nil }
+func (m *mockMutation) EdgeCleared(string) bool            { return false }
+func (m *mockMutation) ClearEdge(string) error             { return nil }
+func (m *mockMutation) ResetEdge(string) error             { return nil }
+func (m *mockMutation) OldField(context.Context, string) (velox.Value, error) {
+	return nil, errors.New("not supported")
+}
+
+var _ velox.Mutation = (*mockMutation)(nil)
+
+type user struct {
+	ID       int    `json:"id"`
+	Name     string `json:"name"`
+	Password string `json:"password"`
+}
+
+// txDriver is a dialect.Driver running in a transaction, like the
+// txDriver of the generated Tx.
+type txDriver struct {
+	tx dialect.Tx
+}
+
+func (d txDriver) Exec(ctx context.Context, query string, args, v any) error {
+	return d.tx.Exec(ctx, query, args, v)
+}
+
+func (d txDriver) Query(ctx context.Context, query string, args, v any) error {
+	return d.tx.Query(ctx, query, args, v)
+}
+
+func (txDriver) Close() error                             { return nil }
+func (txDriver) Commit() error                            { return nil }
+func (txDriver) Rollback() error                          { return nil }
+func (txDriver) Dialect() string                          { return dialect.MySQL }
+func (d txDriver) Tx(context.Context) (dialect.Tx, error) { return d, nil }
+
+func escape(query string) string {
+	return regexp.QuoteMeta(query)
+}
+
+func testHook(allowNonTx bool) velox.Hook {
+	return Hook(HookConfig{
+		Type:       "User",
+		Table:      "users",
+		IDColumn:   "id",
+		Omit:       []string{"password"},
+		AllowNonTx: allowNonTx,
+		Driver:     func(m velox.Mutation) dialect.Driver { return m.(*mockMutation).drv },
+	})
+}
+
+func mutate(ctx context.Context, m *mockMutation, v velox.Value) (velox.Value, error) {
+	return mutateWith(ctx, testHook(false), m, v)
+}
+
+func mutateWith(ctx context.Context, hook velox.Hook, m *mockMutation, v velox.Value) (velox.Value, error) {
+	return hook(velox.MutateFunc(func(context.Context, velox.Mutation) (velox.Value, error) {
+		return v, nil
+	})).Mutate(ctx, m)
+}
+
+func TestHook_Create(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	ctx := context.Background()
+	mock.ExpectBegin()
+	mock.ExpectExec(escape("INSERT INTO `velox_outbox` (`topic`, `entity_type`, `entity_id`, `op`, `payload`, `status`, `attempts`, `created_at`, `available_at`) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)")).
+		WithArgs("User", "User", "1", "create", `{"id":1,"name":"a8m"}`, StatusPending, 0, sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	tx, err := sql.OpenDB(dialect.MySQL, db).Tx(ctx)
+	require.NoError(t, err)
+
+	m := &mockMutation{op: velox.OpCreate, id: 1, drv: txDriver{tx}}
+	v, err := mutate(ctx, m, &user{ID: 1, Name: "a8m", Password: "secret"})
+	require.NoError(t, err)
+	require.Equal(t, 1, v.(*user).ID)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestHook_Delete(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	ctx := context.Background()
+	mock.ExpectBegin()
+	mock.ExpectQuery(escape("SELECT `id` FROM `users` WHERE `users`.`name` = ? FOR UPDATE")).
+		WithArgs("a8m").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1).AddRow(2))
+	mock.ExpectExec(escape("INSERT INTO `velox_outbox` (`topic`, `entity_type`, `entity_id`, `op`, `payload`, `status`, `attempts`, `created_at`, `available_at`) VALUES (?, ?, ?, ?, NULL, ?, ?, ?, ?), (?, ?, ?, ?, NULL, ?, ?, ?, ?)")).
+		WithArgs(
+			"User", "User", "1", "delete", StatusPending, 0, sqlmock.AnyArg(), sqlmock.AnyArg(),
+			"User", "User", "2", "delete", StatusPending, 0, sqlmock.AnyArg(), sqlmock.AnyArg(),
+		).
+		WillReturnResult(sqlmock.NewResult(1, 2))
+	tx, err := sql.OpenDB(dialect.MySQL, db).Tx(ctx)
+	require.NoError(t, err)
+
+	m := &mockMutation{op: velox.OpDelete, drv: txDriver{tx}, preds: []func(*sql.Selector){
+		func(s *sql.Selector) { s.Where(sql.EQ(s.C("name"), "a8m")) },
+	}}
+	_, err = mutate(ctx, m, 2)
+	require.NoError(t, err)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestHook_NoTx(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	mock.ExpectQuery(escape("SELECT `id` FROM `users` WHERE `users`.`name` = ?")).
+		WithArgs("a8m").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	mock.ExpectExec(escape("INSERT INTO `velox_outbox` (`topic`, `entity_type`, `entity_id`, `op`, `payload`, `status`, `attempts`, `created_at`, `available_at`) VALUES (?, ?, ?, ?, NULL, ?, ?, ?, ?)")).
+		WithArgs("User", "User", "1", "delete", StatusPending, 0, sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	m := &mockMutation{op: velox.OpDelete, drv: sql.OpenDB(dialect.MySQL, db), preds: []func(*sql.Selector){
+		func(s *sql.Selector) { s.Where(sql.EQ(s.C("name"), "a8m")) },
+	}}
+	_, err = mutateWith(context.Background(), testHook(true), m, 1)
+	require.NoError(t, err, "mutations outside a transaction write their events after they run")
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestHook_TxRequired(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	m := &mockMutation{op: velox.OpCreate, id: 1, drv: sql.OpenDB(dialect.MySQL, db)}
+	_, err = mutate(context.Background(), m, &user{ID: 1})
+	require.ErrorIs(t, err, ErrTxRequired)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestHook_SkipOps(t *testing.T) {
+	hook := Hook(HookConfig{
+		Type:   "User",
+		Ops:    velox.OpCreate,
+		Driver: func(velox.Mutation) dialect.Driver { t.Fatal("unexpected driver call"); return nil },
+	})
+	next := velox.MutateFunc(func(context.Context, velox.Mutation) (velox.Value, error) {
+		return 1, nil
+	})
+	v, err := hook(next).Mutate(context.Background(), &mockMutation{op: velox.OpUpdateOne})
+	require.NoError(t, err)
+	require.Equal(t, 1, v)
+}
+
+func TestChanges(t *testing.T) {
+	m := &mockMutation{
+		op:      velox.OpUpdate,
+		fields:  map[string]velox.Value{"name": "a8m", "password": "secret"},
+		added:   map[string]velox.Value{"age": 1},
+		cleared: []string{"nickname"},
+	}
+	b, err := Changes(m, "password")
+	require.NoError(t, err)
+	require.JSONEq(t, `{"fields":{"name":"a8m"},"added":{"age":1},"cleared":["nickname"]}`, string(b))
+}
+
+// patchMutation is a mockMutation that appends to, and sets paths of,
+// its JSON fields.
+type patchMutation struct {
+	mockMutation
+	appends map[string]velox.Value
+	paths   map[string][]sqljson.PathValue
+}
+
+func (m *patchMutation) PatchedFields() []string { return []string{"info", "secrets", "tags"} }
+func (m *patchMutation) AppendedField(name string) (velox.Value, bool) {
+	v, ok := m.appends[name]
+	return v, ok
+}
+func (m *patchMutation) FieldPaths(name string) []sqljson.PathValue { return m.paths[name] }
+
+func TestChanges_Patches(t *testing.T) {
+	m := &patchMutation{
+		mockMutation: mockMutation{op: velox.OpUpdate},
+		appends:      map[string]velox.Value{"tags": []string{"a", "b"}, "secrets": []string{"x"}},
+		paths: map[string][]sqljson.PathValue{
+			"info": {{Path: []string{"address", "city"}, Value: "TLV"}},
+		},
+	}
+	b, err := Changes(m, "secrets")
+	require.NoError(t, err)
+	require.JSONEq(t, `{"appended":{"tags":["a","b"]},"paths":{"info":[{"path":["address","city"],"value":"TLV"}]}}`, string(b))
+}
+
+func TestRelay_Poll(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	var (
+		published []int64
+		dead      []int64
+		now       = time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	)
+	relay := NewRelay(sql.OpenDB(dialect.MySQL, db), PublisherFunc(func(_ context.Context, e *Event) error {
+		if e.ID != 1 {
+			return errors.New("broker unavailable")
+		}
+		published = append(published, e.ID)
+		return nil
+	}), WithMaxAttempts(3), WithDeadLetter(func(_ context.Context, e *Event, _ error) {
+		dead = append(dead, e.ID)
+	}))
+	relay.now = func() time.Time { return now }
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(escape("SELECT `id`, `topic`, `entity_type`, `entity_id`, `op`, `payload`, `attempts`, `created_at` FROM `velox_outbox` WHERE `status` = ? AND `available_at` <= ? ORDER BY `id` LIMIT 100 FOR UPDATE SKIP LOCKED")).
+		WithArgs(StatusPending, now).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "topic", "entity_type", "entity_id", "op", "payload", "attempts", "created_at"}).
+			AddRow(1, "User", "User", "1", "create", `{"id":1}`, 0, now).
+			AddRow(2, "User", "User", "2", "create", `{"id":2}`, 0, now).
+			AddRow(3, "User", "User", "3", "delete", nil, 2, now))
+	mock.ExpectExec(escape("UPDATE `velox_outbox` SET `status` = ?, `dispatched_at` = ? WHERE `id` = ?")).
+		WithArgs(StatusDispatched, now, 1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(escape("UPDATE `velox_outbox` SET `attempts` = ?, `last_error` = ?, `available_at` = ? WHERE `id` = ?")).
+		WithArgs(1, "broker unavailable", now.Add(time.Second), 2).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(escape("UPDATE `velox_outbox` SET `status` = ?, `attempts` = ?, `last_error` = ? WHERE `id` = ?")).
+		WithArgs(StatusDead, 3, "broker unavailable", 3).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	n, err := relay.Poll(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 3, n)
+	require.Equal(t, []int64{1}, published)
+	require.Equal(t, []int64{3}, dead)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRequeue(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	mock.ExpectExec(escape("UPDATE `velox_outbox` SET `status` = ?, `attempts` = ?, `available_at` = ? WHERE `status` = ? AND `id` IN (?, ?)")).
+		WithArgs(StatusPending, 0, sqlmock.AnyArg(), StatusDead, 3, 4).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	n, err := Requeue(context.Background(), sql.OpenDB(dialect.MySQL, db), 3, 4)
+	require.NoError(t, err)
+	require.Equal(t, 2, n)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestBackoff(t *testing.T) {
+	assert.Equal(t, time.Second, backoff(1))
+	assert.Equal(t, 4*time.Second, backoff(3))
+	assert.Equal(t, time.Hour, backoff(13))
+	assert.Equal(t, time.Hour, backoff(100))
+}
+
+func TestNewTable(t *testing.T) {
+	table := NewTable()
+	require.Equal(t, TableName, table.Name)
+	require.Len(t, table.PrimaryKey, 1)
+	require.Len(t, table.Indexes, 1)
+	require.Equal(t, ColumnStatus, table.Indexes[0].Columns[0].Name)
+}
@@ -0,0 +1,257 @@
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/syssam/velox/dialect"
+	"github.com/syssam/velox/dialect/sql"
+)
+
+// Publisher publishes the events of the outbox, e.g. to a message broker.
+// Delivery is at-least-once: an event is published again if its row could
+// not be marked as dispatched, so consumers should deduplicate by Event.ID.
+type Publisher interface {
+	Publish(context.Context, *Event) error
+}
+
+// The PublisherFunc type is an adapter to allow the use of ordinary
+// functions as Publisher.
+type PublisherFunc func(context.Context, *Event) error
+
+// Publish calls f(ctx, e).
+func (f PublisherFunc) Publish(ctx context.Context, e *Event) error {
+	return f(ctx, e)
+}
+
+// Defaults of the Relay options.
+const (
+	DefaultBatchSize    = 100
+	DefaultPollInterval = time.Second
+	DefaultMaxAttempts  = 10
+)
+
+// Relay polls the outbox table and hands the pending events to a Publisher.
+// Several relays may poll the same table: rows are locked with
+// FOR UPDATE SKIP LOCKED on PostgreSQL and MySQL, so each event is handed
+// to one relay at a time. SQLite serializes writers and needs no locking.
+type Relay struct {
+	drv         dialect.Driver
+	pub         Publisher
+	batchSize   int
+	interval    time.Duration
+	maxAttempts int
+	backoff     func(attempts int) time.Duration
+	onError     func(error)
+	deadLetter  func(context.Context, *Event, error)
+	now         func() time.Time
+}
+
+// RelayOption configures a Relay.
+type RelayOption func(*Relay)
+
+// WithBatchSize sets the number of events handled per poll.
+// Defaults to DefaultBatchSize.
+func WithBatchSize(n int) RelayOption {
+	return func(r *Relay) {
+		r.batchSize = n
+	}
+}
+
+// WithPollInterval sets the time Run waits after a poll that
+// did not fill a batch. Defaults to DefaultPollInterval.
+func WithPollInterval(d time.Duration) RelayOption {
+	return func(r *Relay) {
+		r.interval = d
+	}
+}
+
+// WithMaxAttempts sets the number of failed attempts after which an
+// event is moved to the dead letters. Defaults to DefaultMaxAttempts.
+func WithMaxAttempts(n int) RelayOption {
+	return func(r *Relay) {
+		r.maxAttempts = n
+	}
+}
+
+// WithBackoff sets the delay before an event is retried, given the number
+// of failed attempts so far. Defaults to an exponential backoff starting at
+// one second and capped at one hour.
+func WithBackoff(f func(attempts int) time.Duration) RelayOption {
+	return func(r *Relay) {
+		r.backoff = f
+	}
+}
+
+// WithErrorHandler sets the function called with the errors of the polls
+// of Run. By default, Run ignores them and polls again.
+func WithErrorHandler(f func(error)) RelayOption {
+	return func(r *Relay) {
+		r.onError = f
+	}
+}
+
+// WithDeadLetter sets the function called with the events that are moved
+// to the dead letters, and the error of their last attempt.
+func WithDeadLetter(f func(context.Context, *Event, error)) RelayOption {
+	return func(r *Relay) {
+		r.deadLetter = f
+	}
+}
+
+// NewRelay returns a Relay that publishes the events of the outbox table
+// of the given driver with pub.
+func NewRelay(drv dialect.Driver, pub Publisher, opts ...RelayOption) *Relay {
+	r := &Relay{
+		drv:         drv,
+		pub:         pub,
+		batchSize:   DefaultBatchSize,
+		interval:    DefaultPollInterval,
+		maxAttempts: DefaultMaxAttempts,
+		backoff:     backoff,
+		now:         time.Now,
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
+}
+
+// backoff is the default backoff: 1s, 2s, 4s, ... capped at one hour.
+func backoff(attempts int) time.Duration {
+	if attempts > 12 {
+		return time.Hour
+	}
+	return min(time.Second<<max(attempts-1, 0), time.Hour)
+}
+
+// Run polls the outbox until ctx is done, and returns ctx.Err().
+func (r *Relay) Run(ctx context.Context) error {
+	for {
+		n, err := r.Poll(ctx)
+		if err != nil && r.onError != nil && ctx.Err() == nil {
+			r.onError(err)
+		}
+		if err == nil && n == r.batchSize {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			continue
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(r.interval):
+		}
+	}
+}
+
+// Poll handles one batch of pending events in a transaction and returns
+// the number of events handled. Published events are marked dispatched.
+// Failed events are retried after the backoff, and moved to the dead
+// letters after MaxAttempts attempts.
+func (r *Relay) Poll(ctx context.Context) (int, error) {
+	tx, err := r.drv.Tx(ctx)
+	if err != nil {
+		return 0, err
+	}
+	n, err := r.poll(ctx, tx)
+	if err != nil {
+		if rerr := tx.Rollback(); rerr != nil {
+			err = fmt.Errorf("%w: %v", err, rerr)
+		}
+		return 0, err
+	}
+	return n, tx.Commit()
+}
+
+func (r *Relay) poll(ctx context.Context, tx dialect.Tx) (int, error) {
+	events, err := r.pending(ctx, tx)
+	if err != nil {
+		return 0, err
+	}
+	for _, e := range events {
+		now := r.now().UTC()
+		update := sql.Dialect(r.drv.Dialect()).Update(TableName).Where(sql.EQ(ColumnID, e.ID))
+		switch perr := r.pub.Publish(ctx, e); {
+		case perr == nil:
+			update.Set(ColumnStatus, StatusDispatched).Set(ColumnDispatchedAt, now)
+		case e.Attempts+1 >= r.maxAttempts:
+			e.Attempts++
+			update.Set(ColumnStatus, StatusDead).Set(ColumnAttempts, e.Attempts).Set(ColumnLastError, perr.Error())
+			if r.deadLetter != nil {
+				r.deadLetter(ctx, e, perr)
+			}
+		default:
+			update.Set(ColumnAttempts, e.Attempts+1).
+				Set(ColumnLastError, perr.Error()).
+				Set(ColumnAvailableAt, now.Add(r.backoff(e.Attempts+1)))
+		}
+		query, args := update.Query()
+		if err := tx.Exec(ctx, query, args, nil); err != nil {
+			return 0, fmt.Errorf("outbox: update event %d: %w", e.ID, err)
+		}
+	}
+	return len(events), nil
+}
+
+// pending selects and locks the next batch of pending events.
+func (r *Relay) pending(ctx context.Context, tx dialect.Tx) ([]*Event, error) {
+	query, args := sql.Dialect(r.drv.Dialect()).
+		Select(ColumnID, ColumnTopic, ColumnEntityType, ColumnEntityID, ColumnOp, ColumnPayload, ColumnAttempts, ColumnCreatedAt).
+		From(sql.Table(TableName)).
+		Where(sql.And(
+			sql.EQ(ColumnStatus, StatusPending),
+			sql.LTE(ColumnAvailableAt, r.now().UTC()),
+		)).
+		OrderBy(ColumnID).
+		Limit(r.batchSize).
+		ForUpdate(sql.WithLockAction(sql.SkipLocked)).
+		Query()
+	rows := &sql.Rows{}
+	if err := tx.Query(ctx, query, args, rows); err != nil {
+		return nil, fmt.Errorf("outbox: select pending events: %w", err)
+	}
+	defer rows.Close()
+	var events []*Event
+	for rows.Next() {
+		var (
+			e       Event
+			payload sql.NullString
+		)
+		if err := rows.Scan(&e.ID, &e.Topic, &e.EntityType, &e.EntityID, &e.Op, &payload, &e.Attempts, &e.CreatedAt); err != nil {
+			return nil, err
+		}
+		if payload.Valid {
+			e.Payload = json.RawMessage(payload.String)
+		}
+		events = append(events, &e)
+	}
+	return events, rows.Err()
+}
+
+// Requeue moves the given dead-lettered events back to the pending events,
+// with their attempts reset. It returns the number of events requeued.
+func Requeue(ctx context.Context, drv dialect.Driver, ids ...int64) (int, error) {
+	if len(ids) == 0 {
+		return 0, nil
+	}
+	vs := make([]any, len(ids))
+	for i := range ids {
+		vs[i] = ids[i]
+	}
+	query, args := sql.Dialect(drv.Dialect()).Update(TableName).
+		Set(ColumnStatus, StatusPending).
+		Set(ColumnAttempts, 0).
+		Set(ColumnAvailableAt, time.Now().UTC()).
+		Where(sql.And(sql.EQ(ColumnStatus, StatusDead), sql.In(ColumnID, vs...))).
+		Query()
+	var res sql.Result
+	if err := drv.Exec(ctx, query, args, &res); err != nil {
+		return 0, fmt.Errorf("outbox: requeue events: %w", err)
+	}
+	n, err := res.RowsAffected()
+	return int(n), err
+}
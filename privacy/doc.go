@@ -61,6 +61,16 @@
 //   - Not(rule): Inverts the rule's decision
 //   - Chain(rules...): Chains rules in sequence
 //
+// # Field Policies
+//
+// A FieldPolicy annotation guards a single field with the same rules:
+//
+//	field.Float("salary").
+//	    Annotations(privacy.Field(privacy.HasRole("hr"), privacy.AlwaysDenyRule()))
+//
+// Denied reads clear the field on the returned nodes (StrictField fails the
+// query instead), and denied writes fail the mutation.
+//
 // # Viewer Interface
 //
 // The Viewer interface represents the authenticated user:
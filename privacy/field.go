@@ -0,0 +1,166 @@
+package privacy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/syssam/velox"
+	"github.com/syssam/velox/schema"
+)
+
+// FieldAnnotationName is the annotation name of FieldPolicy.
+const FieldAnnotationName = "PrivacyField"
+
+// FieldPolicy is a field annotation that guards reads and writes of a single
+// field. Row-level policies decide whether the viewer sees a node at all; a
+// FieldPolicy decides whether the viewer sees (or may set) one of its fields.
+//
+// Rules are evaluated in order with the same Allow/Deny/Skip semantics as
+// QueryPolicy and MutationPolicy: reads are evaluated against the query that
+// loads the node, writes against the mutation that sets the field. If no rule
+// decides, access is allowed, so policies usually end with AlwaysDenyRule:
+//
+//	field.Float("salary").
+//	    Annotations(
+//	        privacy.Field(privacy.HasRole("hr"), privacy.AlwaysDenyRule()),
+//	    )
+//
+// A denied read clears the field on the returned nodes (nil for Nillable
+// fields, the zero value otherwise) unless Strict is set, in which case the
+// query fails with the deny decision. Select, GroupBy and Scan cannot clear a
+// single column, so a denied guarded column always fails them. A denied write
+// fails Save with the deny decision. Rules see only the context, the query and
+// the mutation; FilterFunc rules are evaluated for their decision and their
+// predicates are not applied.
+//
+// Field policies require the privacy feature to be enabled in codegen.
+type FieldPolicy struct {
+	// Rules are the rules evaluated for every read and write of the field.
+	Rules []QueryMutationRule `json:"-"`
+
+	// Strict makes a denied read fail the whole query instead of
+	// clearing the field on the returned nodes.
+	Strict bool `json:"strict,omitempty"`
+
+	// typ and field are bound by FieldPolicyFor at runtime, and used
+	// for error messages and trace entries.
+	typ, field string
+}
+
+// Field returns a FieldPolicy annotation that clears the field on
+// reads denied by the given rules.
+func Field(rules ...QueryMutationRule) *FieldPolicy {
+	return &FieldPolicy{Rules: rules}
+}
+
+// StrictField returns a FieldPolicy annotation that fails the query
+// on reads denied by the given rules.
+func StrictField(rules ...QueryMutationRule) *FieldPolicy {
+	return &FieldPolicy{Rules: rules, Strict: true}
+}
+
+// Name describes the annotation name.
+func (FieldPolicy) Name() string {
+	return FieldAnnotationName
+}
+
+// Merge implements the schema.Merger interface. Rules of the merged
+// policy are appended, and Strict is kept if either side sets it.
+func (p FieldPolicy) Merge(other schema.Annotation) schema.Annotation {
+	var ant FieldPolicy
+	switch other := other.(type) {
+	case FieldPolicy:
+		ant = other
+	case *FieldPolicy:
+		if other != nil {
+			ant = *other
+		}
+	default:
+		return p
+	}
+	p.Rules = append(p.Rules[:len(p.Rules):len(p.Rules)], ant.Rules...)
+	p.Strict = p.Strict || ant.Strict
+	return p
+}
+
+// FieldPolicyFor returns the FieldPolicy declared in the given field
+// annotations, bound to the given type and field names. It returns nil
+// if the field has no FieldPolicy annotation.
+//
+// Note that, this is a runtime function used by velox-generated
+// code and should not be used in velox schemas.
+func FieldPolicyFor(typ, field string, annotations []schema.Annotation) *FieldPolicy {
+	var policy *FieldPolicy
+	for _, a := range annotations {
+		var p *FieldPolicy
+		switch a := a.(type) {
+		case FieldPolicy:
+			p = &a
+		case *FieldPolicy:
+			p = a
+		}
+		if p == nil {
+			continue
+		}
+		if policy == nil {
+			policy = &FieldPolicy{Rules: p.Rules, Strict: p.Strict}
+			continue
+		}
+		merged := policy.Merge(p).(FieldPolicy)
+		policy = &merged
+	}
+	if policy != nil {
+		policy.typ, policy.field = typ, field
+	}
+	return policy
+}
+
+// EvalRead evaluates the policy for a read of the field by the given query.
+// A nil policy allows every read.
+func (p *FieldPolicy) EvalRead(ctx context.Context, q velox.Query) error {
+	if p == nil {
+		return nil
+	}
+	return p.eval(ctx, "read", func(rule QueryMutationRule) error {
+		return rule.EvalQuery(ctx, q)
+	})
+}
+
+// EvalWrite evaluates the policy for a write of the field by the given
+// mutation. A nil policy allows every write.
+func (p *FieldPolicy) EvalWrite(ctx context.Context, m velox.Mutation) error {
+	if p == nil {
+		return nil
+	}
+	return p.eval(ctx, "write", func(rule QueryMutationRule) error {
+		return rule.EvalMutation(ctx, m)
+	})
+}
+
+func (p *FieldPolicy) eval(ctx context.Context, access string, eval func(QueryMutationRule) error) error {
+	if decision, ok := DecisionFromContext(ctx); ok && decision == nil {
+		return nil
+	}
+	for _, rule := range p.Rules {
+		switch decision := eval(rule); {
+		case decision == nil || errors.Is(decision, Skip):
+		case errors.Is(decision, Allow):
+			return nil
+		default:
+			RecordTrace(ctx, p.traceName(), decisionString(decision))
+			return fmt.Errorf("velox/privacy: %s of field %s.%s: %w", access, p.typ, p.field, decision)
+		}
+	}
+	return nil
+}
+
+// traceName returns the rule name recorded in the trace on denial.
+func (p *FieldPolicy) traceName() string {
+	return fmt.Sprintf("FieldPolicy(%s.%s)", p.typ, p.field)
+}
+
+var _ interface {
+	schema.Annotation
+	schema.Merger
+} = (*FieldPolicy)(nil)
@@ -0,0 +1,92 @@
+package privacy_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/syssam/velox"
+	"github.com/syssam/velox/privacy"
+	"github.com/syssam/velox/schema"
+)
+
+func hrOnly() *privacy.FieldPolicy {
+	return privacy.FieldPolicyFor("Employee", "salary", []schema.Annotation{
+		privacy.Field(privacy.HasRole("hr"), privacy.AlwaysDenyRule()),
+	})
+}
+
+func TestFieldPolicy_EvalRead(t *testing.T) {
+	policy := hrOnly()
+
+	t.Run("allows_matching_viewer", func(t *testing.T) {
+		ctx := privacy.WithViewer(context.Background(), &privacy.SimpleViewer{UserRoles: []string{"hr"}})
+		assert.NoError(t, policy.EvalRead(ctx, &mockQuery{}))
+	})
+
+	t.Run("denies_other_viewers", func(t *testing.T) {
+		ctx := privacy.WithViewer(context.Background(), &privacy.SimpleViewer{UserRoles: []string{"user"}})
+		err := policy.EvalRead(ctx, &mockQuery{})
+		require.Error(t, err)
+		assert.True(t, errors.Is(err, privacy.Deny))
+		assert.Contains(t, err.Error(), "read of field Employee.salary")
+	})
+
+	t.Run("allowed_by_cached_decision", func(t *testing.T) {
+		ctx := privacy.DecisionContext(context.Background(), privacy.Allow)
+		assert.NoError(t, policy.EvalRead(ctx, &mockQuery{}))
+	})
+}
+
+func TestFieldPolicy_EvalWrite(t *testing.T) {
+	policy := hrOnly()
+	m := &mockMutation{op: velox.OpUpdateOne, typ: "Employee"}
+
+	ctx := privacy.WithViewer(context.Background(), &privacy.SimpleViewer{UserRoles: []string{"hr"}})
+	assert.NoError(t, policy.EvalWrite(ctx, m))
+
+	err := policy.EvalWrite(context.Background(), m)
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, privacy.Deny))
+	assert.Contains(t, err.Error(), "write of field Employee.salary")
+}
+
+func TestFieldPolicy_NilAllows(t *testing.T) {
+	var policy *privacy.FieldPolicy
+	assert.NoError(t, policy.EvalRead(context.Background(), &mockQuery{}))
+	assert.NoError(t, policy.EvalWrite(context.Background(), &mockMutation{}))
+	assert.Nil(t, privacy.FieldPolicyFor("Employee", "salary", nil))
+}
+
+func TestFieldPolicy_NoDecisionAllows(t *testing.T) {
+	policy := privacy.FieldPolicyFor("Employee", "salary", []schema.Annotation{
+		privacy.Field(privacy.HasRole("hr")),
+	})
+	assert.NoError(t, policy.EvalRead(context.Background(), &mockQuery{}))
+}
+
+func TestFieldPolicy_RecordsDenialInTrace(t *testing.T) {
+	ctx := privacy.WithTrace(context.Background())
+	require.Error(t, hrOnly().EvalRead(ctx, &mockQuery{}))
+
+	entries := privacy.TraceFrom(ctx)
+	require.NotEmpty(t, entries)
+	last := entries[len(entries)-1]
+	assert.Equal(t, "FieldPolicy(Employee.salary)", last.Rule)
+	assert.Equal(t, "deny", last.Decision)
+}
+
+func TestFieldPolicy_Merge(t *testing.T) {
+	policy := privacy.FieldPolicyFor("Employee", "salary", []schema.Annotation{
+		privacy.Field(privacy.HasRole("hr")),
+		privacy.StrictField(privacy.AlwaysDenyRule()),
+	})
+	require.NotNil(t, policy)
+	assert.True(t, policy.Strict)
+	assert.Len(t, policy.Rules, 2)
+	assert.Error(t, policy.EvalRead(context.Background(), &mockQuery{}))
+	assert.Equal(t, privacy.FieldAnnotationName, policy.Name())
+}
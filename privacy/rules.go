@@ -41,6 +41,16 @@ func ViewerFromContext(ctx context.Context) Viewer {
 	return v
 }
 
+// TenantFromContext returns the tenant of the viewer in the context, and
+// false if there is no viewer, or it has no tenant (see TenantIDer).
+func TenantFromContext(ctx context.Context) (string, bool) {
+	tv, ok := ViewerFromContext(ctx).(TenantIDer)
+	if !ok || tv.TenantID() == "" {
+		return "", false
+	}
+	return tv.TenantID(), true
+}
+
 // SimpleViewer is a basic implementation of the Viewer and TenantIDer interfaces.
 // Use this for testing or simple use cases where a full Viewer implementation
 // is not needed.
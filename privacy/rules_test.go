@@ -50,6 +50,19 @@ func TestViewerContext(t *testing.T) {
 	})
 }
 
+func TestTenantFromContext(t *testing.T) {
+	tenant, ok := privacy.TenantFromContext(privacy.WithViewer(context.Background(), &privacy.SimpleViewer{UserID: "u1", UserTenant: "acme"}))
+	assert.True(t, ok)
+	assert.Equal(t, "acme", tenant)
+
+	_, ok = privacy.TenantFromContext(context.Background())
+	assert.False(t, ok)
+	_, ok = privacy.TenantFromContext(privacy.WithViewer(context.Background(), &privacy.SimpleViewer{UserID: "u1"}))
+	assert.False(t, ok, "empty tenant")
+	_, ok = privacy.TenantFromContext(privacy.WithViewer(context.Background(), &nonTenantViewer{id: "u1"}))
+	assert.False(t, ok, "viewer without TenantIDer")
+}
+
 // TestDenyIfNoViewer tests the DenyIfNoViewer rule.
 func TestDenyIfNoViewer(t *testing.T) {
 	rule := privacy.DenyIfNoViewer()
@@ -0,0 +1,228 @@
+package runtime
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+
+	"github.com/syssam/velox"
+	"github.com/syssam/velox/dialect"
+	"github.com/syssam/velox/dialect/sql"
+	"github.com/syssam/velox/schema/edge"
+)
+
+// =============================================================================
+// Cascading Deletes (edge.Cascade)
+// =============================================================================
+
+// Cascade describes an edge whose dependents are handled by the generated
+// delete builders of its entity. See edge.Cascade for details.
+type Cascade struct {
+	// Edge is the name of the edge (e.g. "posts").
+	Edge string
+	// Type is the entity type name of the dependents (e.g. "Post").
+	Type string
+	// Table is the table of the dependents, and Schema its schema, if any.
+	// The edge.Restrict cascades count the rows of the table.
+	Table  string
+	Schema string
+	// Column is the foreign-key column of the dependents table.
+	Column string
+	// Inverse is the name of the inverse edge of the dependents (e.g. "author").
+	// It is cleared by the SetNull action.
+	Inverse string
+	// Action is the action applied to the dependents.
+	Action edge.CascadeAction
+}
+
+// CascadeFunc applies a cascade to the entities whose Column references one of
+// the given IDs, through the generated builders of their type. It returns the
+// number of dependents that were deleted or cleared. It is not called for
+// edge.Restrict, whose dependents are counted by DeleteCascade.
+// Registered by each entity sub-package that is the dependent of a cascade.
+type CascadeFunc func(ctx context.Context, cfg Config, c Cascade, ids []any) (int, error)
+
+var (
+	cascadeMu sync.RWMutex
+	cascades  = map[string]CascadeFunc{}
+)
+
+// RegisterCascade registers the cascade function of an entity type.
+// Passing a nil function is a no-op.
+func RegisterCascade(name string, fn CascadeFunc) {
+	if fn == nil {
+		return
+	}
+	cascadeMu.Lock()
+	defer cascadeMu.Unlock()
+	cascades[name] = fn
+	slog.Debug("velox: registered cascade", "entity", name)
+}
+
+// findCascade looks up the cascade function of an entity type.
+func findCascade(name string) CascadeFunc {
+	cascadeMu.RLock()
+	defer cascadeMu.RUnlock()
+	return cascades[name]
+}
+
+// DeleteCascade deletes the entities that match the base predicates, after it
+// applies the cascades of the entity to their dependents. All statements run in
+// one transaction: the one of the driver, or a new one that is committed before
+// DeleteCascade returns. The restrict cascades are checked first, and a
+// velox.RestrictError that names the blocking edge is returned if dependents exist.
+// Their rows are counted in the table, such that dependents that are hidden from
+// the viewer by a privacy policy or an interceptor, like soft-deleted ones, block
+// the delete as well.
+// Used by the generated delete builders of entities with cascade edges.
+func DeleteCascade(ctx context.Context, cfg Config, base *DeleterBase, entity string, cs []Cascade) (int, error) {
+	tx, err := base.Driver.Tx(ctx)
+	if err != nil {
+		return 0, err
+	}
+	drv := &cascadeDriver{tx: tx, drv: base.Driver}
+	n, err := deleteCascade(ctx, cfg, base, drv, entity, cs)
+	if err != nil {
+		if rerr := tx.Rollback(); rerr != nil {
+			err = fmt.Errorf("%w: %v", err, rerr)
+		}
+		return 0, err
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+	return n, nil
+}
+
+// deleteCascade implements DeleteCascade within the transaction of the driver.
+func deleteCascade(ctx context.Context, cfg Config, base *DeleterBase, drv dialect.Driver, entity string, cs []Cascade) (int, error) {
+	ids, err := deletedIDs(ctx, drv, base)
+	if err != nil || len(ids) == 0 {
+		return 0, err
+	}
+	cfg.Driver = drv
+	// Restrict cascades run first, so no dependent is
+	// changed if the delete is restricted.
+	for _, restrict := range []bool{true, false} {
+		for _, c := range cs {
+			if (c.Action == edge.Restrict) != restrict {
+				continue
+			}
+			if restrict {
+				n, err := countDependents(ctx, drv, c, ids)
+				if err != nil {
+					return 0, fmt.Errorf("velox: cascade edge %q of %s: %w", c.Edge, entity, err)
+				}
+				if n > 0 {
+					return 0, velox.NewRestrictError(entity, c.Edge)
+				}
+				continue
+			}
+			fn := findCascade(c.Type)
+			if fn == nil {
+				return 0, fmt.Errorf("velox: cascade not registered for entity %q — ensure its entity package is imported", c.Type)
+			}
+			if _, err := fn(ctx, cfg, c, ids); err != nil {
+				return 0, fmt.Errorf("velox: cascade edge %q of %s: %w", c.Edge, entity, err)
+			}
+		}
+	}
+	del := *base
+	del.Driver = drv
+	del.Predicates = []func(*sql.Selector){
+		func(s *sql.Selector) {
+			s.Where(sql.In(s.C(base.IDColumn), ids...))
+		},
+	}
+	return DeleteNodes(ctx, &del)
+}
+
+// deletedIDs returns the IDs of the entities that match the base predicates.
+func deletedIDs(ctx context.Context, drv dialect.Driver, base *DeleterBase) ([]any, error) {
+	t := sql.Table(base.Table)
+	if base.Schema != "" {
+		t.Schema(base.Schema)
+	}
+	selector := sql.Dialect(drv.Dialect()).Select(t.C(base.IDColumn)).From(t)
+	for _, p := range base.Predicates {
+		p(selector)
+	}
+	rows := &sql.Rows{}
+	query, args := selector.Query()
+	if err := drv.Query(ctx, query, args, rows); err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var ids []any
+	for rows.Next() {
+		values := IDScanValues(base.IDType)
+		if err := rows.Scan(values...); err != nil {
+			return nil, err
+		}
+		id, err := ExtractID(values[0], base.IDType)
+		if err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+// countDependents counts the rows of the dependents table whose
+// column references one of the given IDs.
+func countDependents(ctx context.Context, drv dialect.Driver, c Cascade, ids []any) (int, error) {
+	t := sql.Table(c.Table)
+	if c.Schema != "" {
+		t.Schema(c.Schema)
+	}
+	query, args := sql.Dialect(drv.Dialect()).Select(sql.Count("*")).From(t).
+		Where(sql.In(t.C(c.Column), ids...)).
+		Query()
+	rows := &sql.Rows{}
+	if err := drv.Query(ctx, query, args, rows); err != nil {
+		return 0, err
+	}
+	defer rows.Close()
+	return sql.ScanInt(rows)
+}
+
+// cascadeDriver runs the statements of a cascading delete within its
+// transaction. The transactions that the builders of the dependents
+// start are no-ops, as they are part of the cascade transaction. It is
+// a dialect.Tx itself, such that hooks that require a transaction,
+// like the ones of the outbox, accept the statements of the dependents.
+type cascadeDriver struct {
+	tx  dialect.Tx
+	drv dialect.Driver
+}
+
+// Exec executes a statement within the cascade transaction.
+func (d *cascadeDriver) Exec(ctx context.Context, query string, args, v any) error {
+	return d.tx.Exec(ctx, query, args, v)
+}
+
+// Query executes a query within the cascade transaction.
+func (d *cascadeDriver) Query(ctx context.Context, query string, args, v any) error {
+	return d.tx.Query(ctx, query, args, v)
+}
+
+// Dialect returns the dialect of the underlying driver.
+func (d *cascadeDriver) Dialect() string { return d.drv.Dialect() }
+
+// Close is a no-op close.
+func (*cascadeDriver) Close() error { return nil }
+
+// Tx returns a no-op transaction that runs within the cascade transaction.
+func (d *cascadeDriver) Tx(context.Context) (dialect.Tx, error) { return dialect.NopTx(d), nil }
+
+// Commit is a no-op. The cascade transaction is committed by DeleteCascade.
+func (*cascadeDriver) Commit() error { return nil }
+
+// Rollback is a no-op. The cascade transaction is rolled back by DeleteCascade.
+func (*cascadeDriver) Rollback() error { return nil }
+
+var (
+	_ dialect.Driver = (*cascadeDriver)(nil)
+	_ dialect.Tx     = (*cascadeDriver)(nil)
+)
@@ -0,0 +1,135 @@
+package runtime
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/syssam/velox"
+	"github.com/syssam/velox/dialect"
+	"github.com/syssam/velox/dialect/sql"
+	"github.com/syssam/velox/schema/edge"
+	"github.com/syssam/velox/schema/field"
+)
+
+// registerPostCascade registers a cascade function for the "Post" entity that
+// stands in for the generated builders, and restores the registry on cleanup.
+// Like the hooks of the outbox, it requires the driver to be a transaction.
+func registerPostCascade(t *testing.T) {
+	t.Helper()
+	saved := cascades
+	cascades = map[string]CascadeFunc{}
+	t.Cleanup(func() { cascades = saved })
+	RegisterCascade("Post", func(ctx context.Context, cfg Config, c Cascade, ids []any) (int, error) {
+		if _, ok := cfg.Driver.(dialect.Tx); !ok {
+			return 0, errors.New("driver is not a transaction")
+		}
+		var (
+			b     = sql.Dialect(cfg.Driver.Dialect())
+			query string
+			args  []any
+		)
+		switch c.Action {
+		case edge.Restrict:
+			return 0, errors.New("restrict cascades are counted by DeleteCascade")
+		case edge.SetNull:
+			query, args = b.Update("posts").SetNull(c.Column).Where(sql.In(c.Column, ids...)).Query()
+		default:
+			query, args = b.Delete("posts").Where(sql.In(c.Column, ids...)).Query()
+		}
+		var res sql.Result
+		if err := cfg.Driver.Exec(ctx, query, args, &res); err != nil {
+			return 0, err
+		}
+		n, err := res.RowsAffected()
+		return int(n), err
+	})
+}
+
+func newCascadeDB(t *testing.T) dialect.Driver {
+	t.Helper()
+	drv := newTestDB(t)
+	ctx := context.Background()
+	for _, stmt := range []string{
+		"CREATE TABLE posts (id INTEGER PRIMARY KEY AUTOINCREMENT, user_posts INTEGER NULL)",
+		"INSERT INTO users (name, age) VALUES ('a8m', 30), ('nati', 28)",
+		"INSERT INTO posts (user_posts) VALUES (1), (1), (2)",
+	} {
+		require.NoError(t, drv.Exec(ctx, stmt, []any{}, nil))
+	}
+	return drv
+}
+
+func countRows(t *testing.T, drv dialect.Driver, query string) int {
+	t.Helper()
+	rows := &sql.Rows{}
+	require.NoError(t, drv.Query(context.Background(), query, []any{}, rows))
+	defer rows.Close()
+	n, err := sql.ScanInt(rows)
+	require.NoError(t, err)
+	return n
+}
+
+func userDeleter(drv dialect.Driver, ps ...func(*sql.Selector)) *DeleterBase {
+	return &DeleterBase{Driver: drv, Table: "users", IDColumn: "id", IDType: field.TypeInt, Predicates: ps}
+}
+
+func TestDeleteCascade(t *testing.T) {
+	registerPostCascade(t)
+	ctx := context.Background()
+	byName := func(name string) func(*sql.Selector) {
+		return func(s *sql.Selector) { s.Where(sql.EQ(s.C("name"), name)) }
+	}
+	posts := Cascade{Edge: "posts", Type: "Post", Table: "posts", Column: "user_posts", Inverse: "author", Action: edge.Delete}
+
+	t.Run("Delete", func(t *testing.T) {
+		drv := newCascadeDB(t)
+		n, err := DeleteCascade(ctx, Config{Driver: drv}, userDeleter(drv, byName("a8m")), "User", []Cascade{posts})
+		require.NoError(t, err)
+		assert.Equal(t, 1, n)
+		assert.Equal(t, 1, countRows(t, drv, "SELECT COUNT(*) FROM users"))
+		assert.Equal(t, 1, countRows(t, drv, "SELECT COUNT(*) FROM posts"))
+	})
+
+	t.Run("SetNull", func(t *testing.T) {
+		drv := newCascadeDB(t)
+		c := posts
+		c.Action = edge.SetNull
+		n, err := DeleteCascade(ctx, Config{Driver: drv}, userDeleter(drv), "User", []Cascade{c})
+		require.NoError(t, err)
+		assert.Equal(t, 2, n)
+		assert.Equal(t, 3, countRows(t, drv, "SELECT COUNT(*) FROM posts WHERE user_posts IS NULL"))
+	})
+
+	t.Run("Restrict", func(t *testing.T) {
+		drv := newCascadeDB(t)
+		restrict := posts
+		restrict.Edge, restrict.Action = "pinned", edge.Restrict
+		_, err := DeleteCascade(ctx, Config{Driver: drv}, userDeleter(drv), "User", []Cascade{posts, restrict})
+		require.True(t, velox.IsRestrictError(err))
+		assert.EqualError(t, err, `velox: delete User restricted by dependents on edge "pinned"`)
+		// Restrict cascades run first, and nothing is deleted.
+		assert.Equal(t, 2, countRows(t, drv, "SELECT COUNT(*) FROM users"))
+		assert.Equal(t, 3, countRows(t, drv, "SELECT COUNT(*) FROM posts"))
+	})
+
+	t.Run("NoMatch", func(t *testing.T) {
+		drv := newCascadeDB(t)
+		n, err := DeleteCascade(ctx, Config{Driver: drv}, userDeleter(drv, byName("unknown")), "User", []Cascade{posts})
+		require.NoError(t, err)
+		assert.Zero(t, n)
+		assert.Equal(t, 3, countRows(t, drv, "SELECT COUNT(*) FROM posts"))
+	})
+
+	t.Run("Unregistered", func(t *testing.T) {
+		drv := newCascadeDB(t)
+		c := posts
+		c.Type = "Comment"
+		_, err := DeleteCascade(ctx, Config{Driver: drv}, userDeleter(drv), "User", []Cascade{posts, c})
+		require.ErrorContains(t, err, `cascade not registered for entity "Comment"`)
+		assert.Equal(t, 3, countRows(t, drv, "SELECT COUNT(*) FROM posts"))
+	})
+}
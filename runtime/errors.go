@@ -21,6 +21,8 @@ var (
 	NewNotLoadedError   = velox.NewNotLoadedError
 	NewConstraintError  = velox.NewConstraintError
 	NewValidationError  = velox.NewValidationError
+
+	NewEntityValidationError = velox.NewEntityValidationError
 )
 
 // ConstraintError is an alias for velox.ConstraintError.
@@ -74,3 +74,30 @@ func TestNodeResolvers_ReturnsCopy(t *testing.T) {
 	nodeMu.RUnlock()
 	assert.True(t, ok, "deleting from copy should not affect global registry")
 }
+
+func TestNodeResolversFor(t *testing.T) {
+	RegisterNodeResolver("test_for_users", NodeResolver{Type: "User", IDPrefix: "usr"})
+	RegisterNodeResolver("test_for_orgs", NodeResolver{Type: "Org", IDPrefix: "org"})
+	defer func() {
+		nodeMu.Lock()
+		delete(nodeRegistry, "test_for_users")
+		delete(nodeRegistry, "test_for_orgs")
+		nodeMu.Unlock()
+	}()
+
+	resolvers := NodeResolversFor("org_01HQ3Z7N0D5X3VJ0B8TQ9W2K4M")
+	if assert.Len(t, resolvers, 1) {
+		assert.Equal(t, "Org", resolvers[0].Type)
+	}
+
+	// Unknown prefixes and non-string IDs fall back to all resolvers, ordered by table.
+	for _, id := range []any{"acc_01HQ3Z7N0D5X3VJ0B8TQ9W2K4M", 42} {
+		var types []string
+		for _, r := range NodeResolversFor(id) {
+			if r.Type == "User" || r.Type == "Org" {
+				types = append(types, r.Type)
+			}
+		}
+		assert.Equal(t, []string{"Org", "User"}, types)
+	}
+}
@@ -5,11 +5,13 @@ import (
 	"errors"
 	"fmt"
 	"math"
+	"time"
 
 	"github.com/google/uuid"
 
 	"github.com/syssam/velox/dialect"
 	"github.com/syssam/velox/dialect/sql"
+	"github.com/syssam/velox/dialect/sql/shard"
 	"github.com/syssam/velox/dialect/sql/sqlgraph"
 	"github.com/syssam/velox/schema/field"
 )
@@ -22,6 +24,7 @@ type QueryContext struct {
 	Unique     *bool
 	Limit      *int
 	Offset     *int
+	Timeout    time.Duration // statement timeout, see sql.WithTimeout.
 }
 
 // Clone returns a deep copy of the QueryContext.
@@ -786,6 +789,8 @@ type ScannableOf[T any] interface {
 // ScanAll executes the query and scans all rows into typed entity pointers.
 // The build function returns a fully-configured *sql.Selector (with columns,
 // DISTINCT, etc. already applied). ScanAll just executes and scans.
+// On a shard.Driver, scatter queries merge the rows of the shards by the
+// ORDER BY terms of the selector and apply its LIMIT and OFFSET.
 func ScanAll[T any, PT ScannableOf[T]](ctx context.Context, drv dialect.Driver, build func(context.Context) (*sql.Selector, error)) ([]*T, error) {
 	selector, err := build(ctx)
 	if err != nil {
@@ -793,6 +798,9 @@ func ScanAll[T any, PT ScannableOf[T]](ctx context.Context, drv dialect.Driver,
 	}
 	rows := &sql.Rows{}
 	query, args := selector.Query()
+	if shard.IsScatter(ctx) {
+		ctx = shard.WithMerge(ctx, sqlgraph.ScatterMerge(selector))
+	}
 	if qErr := drv.Query(ctx, query, args, rows); qErr != nil {
 		return nil, qErr
 	}
@@ -1156,6 +1164,9 @@ type DeleterBase struct {
 	FieldTypes map[string]field.Type
 	Predicates []func(*sql.Selector)
 	Schema     string // for multi-schema support
+	// Counters holds the counter caches that count the
+	// rows of the table (edge.CounterCache).
+	Counters []*sqlgraph.CounterSpec
 }
 
 // ScanWithInterceptors runs sqlFn through the interceptor chain.
@@ -1202,6 +1213,7 @@ func DeleteNodes(ctx context.Context, base *DeleterBase) (int, error) {
 	if base.Schema != "" {
 		spec.Node.Schema = base.Schema
 	}
+	spec.Counters = base.Counters
 
 	if len(base.Predicates) > 0 {
 		spec.Predicate = func(s *sql.Selector) {
@@ -5,10 +5,12 @@ import (
 	"fmt"
 	"log/slog"
 	"maps"
+	"slices"
 	"strings"
 	"sync"
 
 	"github.com/syssam/velox"
+	"github.com/syssam/velox/dialect"
 )
 
 // =============================================================================
@@ -123,6 +125,12 @@ type EntityRegistration struct {
 	Mutator MutatorFunc
 	// Client constructs a typed entity client from Config.
 	Client EntityClientFunc
+	// SensitiveColumns are the columns of the Sensitive fields. Their
+	// arguments are redacted by the logging drivers.
+	SensitiveColumns []string
+	// Cascade applies the cascades (edge.Cascade) of the referencing entities
+	// to this entity. Set only for entities that are the dependents of a cascade.
+	Cascade CascadeFunc
 }
 
 // RegisterEntity registers all metadata for an entity in one call.
@@ -132,6 +140,8 @@ func RegisterEntity(r EntityRegistration) {
 	RegisterEntityClient(r.Name, r.Client)
 	RegisterTypeInfo(r.Table, r.TypeInfo)
 	RegisterColumns(r.Table, r.ValidColumn)
+	dialect.RegisterSensitiveColumns(r.Table, r.SensitiveColumns...)
+	RegisterCascade(r.Name, r.Cascade)
 	slog.Debug("velox: registered entity", "entity", r.Name, "table", r.Table)
 }
 
@@ -264,6 +274,9 @@ type NodeResolver struct {
 	Type string
 	// Resolve fetches the node by ID.
 	Resolve func(ctx context.Context, id any) (any, error)
+	// IDPrefix is the type prefix of the string IDs of the node, if any
+	// (e.g., "usr" for "usr_01HQ3Z7N0D5X3VJ0B8TQ9W2K4M").
+	IDPrefix string
 }
 
 // RegisterNodeResolver registers a node resolver for a table.
@@ -283,3 +296,30 @@ func NodeResolvers() map[string]NodeResolver {
 	maps.Copy(result, nodeRegistry)
 	return result
 }
+
+// NodeResolversFor returns the node resolvers to try for the given ID. A
+// string ID whose prefix matches the IDPrefix of a resolver is resolved by
+// that resolver only. Otherwise, all resolvers are returned, ordered by table.
+func NodeResolversFor(id any) []NodeResolver {
+	nodeMu.RLock()
+	defer nodeMu.RUnlock()
+	if s, ok := id.(string); ok {
+		if prefix, _, ok := strings.Cut(s, "_"); ok {
+			for _, r := range nodeRegistry {
+				if r.IDPrefix != "" && r.IDPrefix == prefix {
+					return []NodeResolver{r}
+				}
+			}
+		}
+	}
+	tables := make([]string, 0, len(nodeRegistry))
+	for t := range nodeRegistry {
+		tables = append(tables, t)
+	}
+	slices.Sort(tables)
+	result := make([]NodeResolver, len(tables))
+	for i, t := range tables {
+		result[i] = nodeRegistry[t]
+	}
+	return result
+}
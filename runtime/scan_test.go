@@ -2,12 +2,15 @@ package runtime
 
 import (
 	"context"
+	"fmt"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/syssam/velox/dialect"
 	velsql "github.com/syssam/velox/dialect/sql"
+	"github.com/syssam/velox/dialect/sql/shard"
 )
 
 func TestScanAll(t *testing.T) {
@@ -60,6 +63,62 @@ func TestScanFirst_NotFound(t *testing.T) {
 	assert.True(t, IsNotFound(err))
 }
 
+func TestScanAll_Scatter(t *testing.T) {
+	ctx := context.Background()
+	meta := testTypeInfo()
+	var shards []dialect.Driver
+	for _, ages := range [][]int{{10, 40, 50}, {20, 30, 60}} {
+		drv := newTestDB(t)
+		for _, age := range ages {
+			seedUsers(ctx, t, drv, meta, []struct {
+				Name string
+				Age  int
+			}{{fmt.Sprint("u", age), age}})
+		}
+		shards = append(shards, drv)
+	}
+	drv, err := shard.NewDriver(shards)
+	require.NoError(t, err)
+	build := func(limit, offset int) func(context.Context) (*velsql.Selector, error) {
+		return func(ctx context.Context) (*velsql.Selector, error) {
+			qb := NewQueryBase(drv, "users", meta.Columns, meta.IDColumn, nil, "User")
+			qb.AddOrder(velsql.OrderByField("age", velsql.OrderDesc()).ToFunc())
+			if limit != 0 {
+				qb.SetLimit(limit)
+			}
+			if offset != 0 {
+				qb.SetOffset(offset)
+			}
+			return qb.BuildSelector(ctx)
+		}
+	}
+	ctx = shard.WithScatter(ctx)
+	nodes, err := ScanAll[testEntity, *testEntity](ctx, drv, build(3, 0))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"u60", "u50", "u40"}, entityNames(nodes))
+
+	nodes, err = ScanAll[testEntity, *testEntity](ctx, drv, build(2, 1))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"u50", "u40"}, entityNames(nodes))
+
+	nodes, err = ScanAll[testEntity, *testEntity](ctx, drv, build(0, 4))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"u20", "u10"}, entityNames(nodes))
+
+	node, err := ScanFirst[testEntity, *testEntity](ctx, drv, build(0, 0), "User")
+	require.NoError(t, err)
+	assert.Equal(t, "u60", node.Name)
+}
+
+// entityNames returns the names of the entities, in order.
+func entityNames(nodes []*testEntity) []string {
+	names := make([]string, len(nodes))
+	for i, n := range nodes {
+		names[i] = n.Name
+	}
+	return names
+}
+
 func TestScanOnly(t *testing.T) {
 	drv := newTestDB(t)
 	meta := testTypeInfo()
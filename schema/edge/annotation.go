@@ -13,6 +13,55 @@ type Annotation struct {
 	//	}
 	//
 	StructTag string
+
+	// Cascade defines the ORM-level action that the generated delete builders
+	// apply to the dependents of the edge. See the Cascade function for details.
+	Cascade CascadeAction
+
+	// CounterCache is the name of the integer column of the edge owner that
+	// counts the dependents of the edge. See the CounterCache function for details.
+	CounterCache string
+}
+
+// CascadeAction defines the action that is applied to the dependents of an
+// edge when the entities that they reference are deleted.
+type CascadeAction string
+
+// Cascade actions.
+const (
+	Delete   CascadeAction = "DELETE"   // Delete the dependents.
+	SetNull  CascadeAction = "SET NULL" // Clear the reference of the dependents.
+	Restrict CascadeAction = "RESTRICT" // Fail the delete if dependents exist.
+)
+
+// Cascade returns an edge annotation that makes the generated delete builders
+// walk the dependents of the edge in the same transaction. Unlike the database
+// ON DELETE actions (sqlschema.OnDelete), the dependents are deleted or updated
+// through their own generated builders, and hence, their hooks, privacy policy
+// and soft-delete logic apply. For example:
+//
+//	edge.To("posts", Post.Type).
+//		Annotations(edge.Cascade(edge.Delete))
+//
+// The annotation is supported on O2M and O2O edges whose foreign-key resides in
+// the table of the dependents. SetNull requires the inverse edge to be defined.
+func Cascade(action CascadeAction) *Annotation {
+	return &Annotation{Cascade: action}
+}
+
+// CounterCache returns an edge annotation that adds a denormalized counter of
+// the edge dependents to the schema of the edge owner. The counter is an integer
+// column with the given name, and is kept in sync by the generated create, update
+// and delete builders within their statements' transaction. For example:
+//
+//	edge.To("posts", Post.Type).
+//		Annotations(edge.CounterCache("posts_count"))
+//
+// The counter is read-only, and the generated XxxClient.RecountXxx method
+// (e.g. RecountPostsCount) recomputes it in case it drifted. The annotation is
+// supported on O2M edges whose foreign-key resides in the table of the dependents.
+func CounterCache(column string) *Annotation {
+	return &Annotation{CounterCache: column}
 }
 
 // Name describes the annotation name.
@@ -36,6 +85,12 @@ func (a Annotation) Merge(other schema.Annotation) schema.Annotation {
 	if tag := ant.StructTag; tag != "" {
 		a.StructTag = tag
 	}
+	if action := ant.Cascade; action != "" {
+		a.Cascade = action
+	}
+	if column := ant.CounterCache; column != "" {
+		a.CounterCache = column
+	}
 	return a
 }
 
@@ -100,6 +100,14 @@
 //	edge.To("followers", User.Type)
 //	edge.To("following", User.Type)
 //
+// # Polymorphic Edges
+//
+// An edge can point to one entity of several types. It is stored in a type
+// and an ID column of the owner table, without a foreign-key constraint:
+//
+//	// Comment on a Post or a Photo: "subject_type" and "subject_id" columns.
+//	edge.ToOneOf("subject", Post.Type, Photo.Type)
+//
 // # Storage Key Customization
 //
 // Customize the foreign key column name:
@@ -16,6 +16,7 @@ type Descriptor struct {
 	RefName     string                 // ref name; inverse only.
 	Ref         *Descriptor            // edge reference; to/from of the same type.
 	Through     *struct{ N, T string } // through type and name.
+	OneOf       []string               // edge types; polymorphic edges only.
 	Unique      bool                   // unique edge.
 	Inverse     bool                   // inverse edge.
 	Required    bool                   // required on creation.
@@ -38,6 +39,28 @@ func From(name string, t any) *inverseBuilder {
 	return &inverseBuilder{desc: &Descriptor{Name: name, Type: typeName, Inverse: true, Err: err}}
 }
 
+// ToOneOf defines a polymorphic edge that points to one entity of one of
+// the given types. The edge is stored in two columns of the owner table, the
+// name of the referenced type and its ID (e.g. "subject_type" and "subject_id"),
+// and has no foreign-key constraint.
+//
+//	edge.ToOneOf("subject", Post.Type, Photo.Type).
+//		Required()
+func ToOneOf(name string, types ...any) *oneOfBuilder {
+	desc := &Descriptor{Name: name, Unique: true}
+	for _, t := range types {
+		typeName, err := typ(name, t)
+		if err != nil && desc.Err == nil {
+			desc.Err = err
+		}
+		desc.OneOf = append(desc.OneOf, typeName)
+	}
+	if len(types) < 2 && desc.Err == nil {
+		desc.Err = fmt.Errorf("edge %q: polymorphic edge requires at least 2 types, use edge.To for a single type", name)
+	}
+	return &oneOfBuilder{desc: desc}
+}
+
 // typ extracts the type name from the edge type function.
 // It returns an error if the type is invalid.
 func typ(edgeName string, t any) (string, error) {
@@ -242,6 +265,48 @@ func (b *inverseBuilder) Descriptor() *Descriptor {
 	return b.desc
 }
 
+// oneOfBuilder is the builder for polymorphic edges.
+type oneOfBuilder struct {
+	desc *Descriptor
+}
+
+// Required indicates that this edge is a required field on creation.
+// Unlike fields, edges are optional by default.
+func (b *oneOfBuilder) Required() *oneOfBuilder {
+	b.desc.Required = true
+	return b
+}
+
+// Immutable indicates that this edge cannot be updated.
+func (b *oneOfBuilder) Immutable() *oneOfBuilder {
+	b.desc.Immutable = true
+	return b
+}
+
+// StructTag sets the struct tag of the polymorphic edge.
+func (b *oneOfBuilder) StructTag(s string) *oneOfBuilder {
+	b.desc.Tag = s
+	return b
+}
+
+// Comment used to put annotations on the schema.
+func (b *oneOfBuilder) Comment(c string) *oneOfBuilder {
+	b.desc.Comment = c
+	return b
+}
+
+// Annotations adds a list of annotations to the edge object to be used by
+// codegen extensions.
+func (b *oneOfBuilder) Annotations(annotations ...schema.Annotation) *oneOfBuilder {
+	b.desc.Annotations = append(b.desc.Annotations, annotations...)
+	return b
+}
+
+// Descriptor implements the velox.Descriptor interface.
+func (b *oneOfBuilder) Descriptor() *Descriptor {
+	return b.desc
+}
+
 // StorageKey holds the configuration for edge storage-key.
 type StorageKey struct {
 	Table   string   // Table or label.
@@ -686,6 +686,35 @@ func TestThroughWithInvalidType(t *testing.T) {
 	})
 }
 
+// TestEdgeToOneOf tests the edge.ToOneOf builder of polymorphic edges.
+func TestEdgeToOneOf(t *testing.T) {
+	t.Parallel()
+
+	desc := edge.ToOneOf("subject", Post.Type, Group.Type).
+		Required().
+		Immutable().
+		StructTag(`json:"subject"`).
+		Comment("commented entity").
+		Descriptor()
+	require.NoError(t, desc.Err)
+	assert.Equal(t, "subject", desc.Name)
+	assert.Empty(t, desc.Type)
+	assert.Equal(t, []string{"Post", "Group"}, desc.OneOf)
+	assert.True(t, desc.Unique)
+	assert.True(t, desc.Required)
+	assert.True(t, desc.Immutable)
+	assert.Equal(t, `json:"subject"`, desc.Tag)
+	assert.Equal(t, "commented entity", desc.Comment)
+
+	desc = edge.ToOneOf("subject", Post.Type).Descriptor()
+	require.Error(t, desc.Err)
+	assert.Contains(t, desc.Err.Error(), "requires at least 2 types")
+
+	desc = edge.ToOneOf("subject", Post.Type, nil).Descriptor()
+	require.Error(t, desc.Err)
+	assert.Contains(t, desc.Err.Error(), "nil")
+}
+
 // TestAnnotationName tests the edge.Annotation Name method.
 func TestAnnotationName(t *testing.T) {
 	t.Parallel()
@@ -755,6 +784,24 @@ func TestAnnotationMerge(t *testing.T) {
 		require.True(t, ok)
 		assert.Empty(t, ann.StructTag)
 	})
+
+	t.Run("merge_cascade", func(t *testing.T) {
+		t.Parallel()
+		a := edge.Annotation{StructTag: `json:"original"`}
+		ann, ok := a.Merge(edge.Cascade(edge.Restrict)).(edge.Annotation)
+		require.True(t, ok)
+		assert.Equal(t, edge.Restrict, ann.Cascade)
+		assert.Equal(t, `json:"original"`, ann.StructTag)
+	})
+
+	t.Run("merge_counter_cache", func(t *testing.T) {
+		t.Parallel()
+		a := *edge.Cascade(edge.Delete)
+		ann, ok := a.Merge(edge.CounterCache("posts_count")).(edge.Annotation)
+		require.True(t, ok)
+		assert.Equal(t, "posts_count", ann.CounterCache)
+		assert.Equal(t, edge.Delete, ann.Cascade)
+	})
 }
 
 // BenchmarkEdgeBuilder benchmarks edge builder performance.
@@ -30,6 +30,16 @@ type Annotation struct {
 	//	}
 	//
 	ID []string
+
+	// IDPrefix is the type prefix of the string IDs of the type, set on
+	// its ID field. The GraphQL node resolution uses it to resolve a
+	// prefixed ID by the type that owns it. See PrefixedID.
+	//
+	//	field.String("id").
+	//		DefaultFunc(field.PrefixedID("usr")).
+	//		Annotations(field.IDPrefix("usr"))
+	//
+	IDPrefix string
 }
 
 // ID defines a multi-field schema identifier. Note, the
@@ -44,6 +54,15 @@ func ID(first, second string, fields ...string) *Annotation {
 	return &Annotation{ID: append([]string{first, second}, fields...)}
 }
 
+// IDPrefix sets the type prefix of the string IDs generated by PrefixedID.
+// It panics if the prefix is not made of lowercase letters and digits.
+func IDPrefix(prefix string) *Annotation {
+	if err := validIDPrefix(prefix); err != nil {
+		panic(err)
+	}
+	return &Annotation{IDPrefix: prefix}
+}
+
 // Name describes the annotation name.
 func (Annotation) Name() string {
 	return "Fields"
@@ -69,6 +88,9 @@ func (a Annotation) Merge(other schema.Annotation) schema.Annotation {
 	if len(ant.ID) > 0 {
 		a.ID = ant.ID
 	}
+	if ant.IDPrefix != "" {
+		a.IDPrefix = ant.IDPrefix
+	}
 	return a
 }
 
@@ -89,6 +89,9 @@ func JSON(name string, typ any) *jsonBuilder {
 		b.desc.Info.Nillable = true
 		b.desc.Info.PkgPath = pkgPath(t)
 	}
+	if tv := indirect(t); tv.Kind() == reflect.Struct && !customJSON(tv) {
+		b.desc.Info.RType.Fields = jsonFields(tv, make(map[reflect.Type]bool))
+	}
 	return b
 }
 
@@ -203,6 +206,7 @@ func (b *stringBuilder) Sensitive() *stringBuilder {
 
 // Match adds a regex matcher for this field. Operation fails if the regex fails.
 func (b *stringBuilder) Match(re *regexp.Regexp) *stringBuilder {
+	b.desc.constrain().Pattern = re.String()
 	b.desc.Validators = append(b.desc.Validators, func(v string) error {
 		if !re.MatchString(v) {
 			return fmt.Errorf("value %q does not match pattern %q", v, re.String())
@@ -215,6 +219,7 @@ func (b *stringBuilder) Match(re *regexp.Regexp) *stringBuilder {
 // MinLen adds a length validator for this field.
 // Operation fails if the length of the string is less than the given value.
 func (b *stringBuilder) MinLen(i int) *stringBuilder {
+	b.desc.constrain().minLen(i)
 	b.desc.Validators = append(b.desc.Validators, func(v string) error {
 		if len(v) < i {
 			return fmt.Errorf("value length %d is less than minimum %d", len(v), i)
@@ -227,6 +232,7 @@ func (b *stringBuilder) MinLen(i int) *stringBuilder {
 // MinRuneLen adds a rune length validator for this field.
 // Operation fails if the rune count of the string is less than the given value.
 func (b *stringBuilder) MinRuneLen(i int) *stringBuilder {
+	b.desc.constrain().minRuneLen(i)
 	b.desc.Validators = append(b.desc.Validators, func(v string) error {
 		if utf8.RuneCountInString(v) < i {
 			return fmt.Errorf("value rune length %d is less than minimum %d", utf8.RuneCountInString(v), i)
@@ -245,6 +251,7 @@ func (b *stringBuilder) NotEmpty() *stringBuilder {
 // MaxLen adds a length validator for this field.
 // Operation fails if the length of the string is greater than the given value.
 func (b *stringBuilder) MaxLen(i int) *stringBuilder {
+	b.desc.constrain().maxLen(i)
 	b.desc.Size = i
 	b.desc.Validators = append(b.desc.Validators, func(v string) error {
 		if len(v) > i {
@@ -258,6 +265,7 @@ func (b *stringBuilder) MaxLen(i int) *stringBuilder {
 // MaxRuneLen adds a rune length validator for this field.
 // Operation fails if the rune count of the string is greater than the given value.
 func (b *stringBuilder) MaxRuneLen(i int) *stringBuilder {
+	b.desc.constrain().maxRuneLen(i)
 	b.desc.Size = i
 	b.desc.Validators = append(b.desc.Validators, func(v string) error {
 		if utf8.RuneCountInString(v) > i {
@@ -359,6 +367,30 @@ func (b *stringBuilder) SchemaType(types map[string]string) *stringBuilder {
 	return b
 }
 
+// Generated makes the field a column that is computed by the database from
+// the given SQL expression. The create and update builders have no setters
+// for it, and the migration does not alter the column once it exists.
+//
+//	field.String("full_name").
+//		Generated("first_name || ' ' || last_name", field.Stored)
+func (b *stringBuilder) Generated(expr string, kind GeneratedKind) *stringBuilder {
+	b.desc.generated(kind).Expr = expr
+	return b
+}
+
+// GeneratedExprs is like Generated, with an expression per dialect.
+// The expressions override the one of Generated for their dialects.
+//
+//	field.String("full_name").
+//		GeneratedExprs(map[string]string{
+//			dialect.MySQL:    "CONCAT(first_name, ' ', last_name)",
+//			dialect.Postgres: "first_name || ' ' || last_name",
+//		}, field.Stored)
+func (b *stringBuilder) GeneratedExprs(exprs map[string]string, kind GeneratedKind) *stringBuilder {
+	b.desc.generated(kind).Exprs = exprs
+	return b
+}
+
 // GoType overrides the default Go type with a custom one.
 // If the provided type implements the Validator interface
 // and no validators have been set, the type validator will
@@ -555,6 +587,30 @@ func (b *timeBuilder) SchemaType(types map[string]string) *timeBuilder {
 	return b
 }
 
+// Generated makes the field a column that is computed by the database from
+// the given SQL expression. The create and update builders have no setters
+// for it, and the migration does not alter the column once it exists.
+//
+//	field.Time("expires_at").
+//		Generated("created_at + INTERVAL 1 DAY", field.Stored)
+func (b *timeBuilder) Generated(expr string, kind GeneratedKind) *timeBuilder {
+	b.desc.generated(kind).Expr = expr
+	return b
+}
+
+// GeneratedExprs is like Generated, with an expression per dialect.
+// The expressions override the one of Generated for their dialects.
+//
+//	field.Time("expires_at").
+//		GeneratedExprs(map[string]string{
+//			dialect.MySQL:    "created_at + INTERVAL 1 DAY",
+//			dialect.Postgres: "created_at + INTERVAL '1 day'",
+//		}, field.Stored)
+func (b *timeBuilder) GeneratedExprs(exprs map[string]string, kind GeneratedKind) *timeBuilder {
+	b.desc.generated(kind).Exprs = exprs
+	return b
+}
+
 // boolBuilder is the builder for boolean fields.
 type boolBuilder struct {
 	desc *Descriptor
@@ -650,6 +706,29 @@ func (b *boolBuilder) SchemaType(types map[string]string) *boolBuilder {
 	return b
 }
 
+// Generated makes the field a column that is computed by the database from
+// the given SQL expression. The create and update builders have no setters
+// for it, and the migration does not alter the column once it exists.
+//
+//	field.Bool("overdue").
+//		Generated("due_at < paid_at", field.Stored)
+func (b *boolBuilder) Generated(expr string, kind GeneratedKind) *boolBuilder {
+	b.desc.generated(kind).Expr = expr
+	return b
+}
+
+// GeneratedExprs is like Generated, with an expression per dialect.
+// The expressions override the one of Generated for their dialects.
+//
+//	field.Bool("overdue").
+//		GeneratedExprs(map[string]string{
+//			dialect.Postgres: "due_at < paid_at",
+//		}, field.Stored)
+func (b *boolBuilder) GeneratedExprs(exprs map[string]string, kind GeneratedKind) *boolBuilder {
+	b.desc.generated(kind).Exprs = exprs
+	return b
+}
+
 // Deprecated marks the field as deprecated. Deprecated fields are not
 // selected by default in queries, and their struct fields are annotated
 // with `deprecated` in the generated code.
@@ -754,6 +833,7 @@ func (b *bytesBuilder) StructTag(s string) *bytesBuilder {
 // In MySQL, this affects the BLOB type (tiny 2^8-1, regular 2^16-1, medium 2^24-1, long 2^32-1).
 // In SQLite, it does not have any effect on the type size, which is default to 1B bytes.
 func (b *bytesBuilder) MaxLen(i int) *bytesBuilder {
+	b.desc.constrain().maxLen(i)
 	b.desc.Size = i
 	b.desc.Validators = append(b.desc.Validators, func(buf []byte) error {
 		if len(buf) > i {
@@ -767,6 +847,7 @@ func (b *bytesBuilder) MaxLen(i int) *bytesBuilder {
 // MinLen adds a length validator for this field.
 // Operation fails if the length of the buffer is less than the given value.
 func (b *bytesBuilder) MinLen(i int) *bytesBuilder {
+	b.desc.constrain().minLen(i)
 	b.desc.Validators = append(b.desc.Validators, func(buf []byte) error {
 		if len(buf) < i {
 			return fmt.Errorf("value length %d is less than minimum %d", len(buf), i)
@@ -844,6 +925,29 @@ func (b *bytesBuilder) SchemaType(types map[string]string) *bytesBuilder {
 	return b
 }
 
+// Generated makes the field a column that is computed by the database from
+// the given SQL expression. The create and update builders have no setters
+// for it, and the migration does not alter the column once it exists.
+//
+//	field.Bytes("digest").
+//		Generated("sha256(content)", field.Stored)
+func (b *bytesBuilder) Generated(expr string, kind GeneratedKind) *bytesBuilder {
+	b.desc.generated(kind).Expr = expr
+	return b
+}
+
+// GeneratedExprs is like Generated, with an expression per dialect.
+// The expressions override the one of Generated for their dialects.
+//
+//	field.Bytes("digest").
+//		GeneratedExprs(map[string]string{
+//			dialect.Postgres: "sha256(content)",
+//		}, field.Stored)
+func (b *bytesBuilder) GeneratedExprs(exprs map[string]string, kind GeneratedKind) *bytesBuilder {
+	b.desc.generated(kind).Exprs = exprs
+	return b
+}
+
 // Deprecated marks the field as deprecated. Deprecated fields are not
 // selected by default in queries, and their struct fields are annotated
 // with `deprecated` in the generated code.
@@ -920,6 +1024,29 @@ func (b *jsonBuilder) SchemaType(types map[string]string) *jsonBuilder {
 	return b
 }
 
+// Generated makes the field a column that is computed by the database from
+// the given SQL expression. The create and update builders have no setters
+// for it, and the migration does not alter the column once it exists.
+//
+//	field.JSON("tags", []string{}).
+//		Generated("data->'tags'", field.Stored)
+func (b *jsonBuilder) Generated(expr string, kind GeneratedKind) *jsonBuilder {
+	b.desc.generated(kind).Expr = expr
+	return b
+}
+
+// GeneratedExprs is like Generated, with an expression per dialect.
+// The expressions override the one of Generated for their dialects.
+//
+//	field.JSON("tags", []string{}).
+//		GeneratedExprs(map[string]string{
+//			dialect.Postgres: "data->'tags'",
+//		}, field.Stored)
+func (b *jsonBuilder) GeneratedExprs(exprs map[string]string, kind GeneratedKind) *jsonBuilder {
+	b.desc.generated(kind).Exprs = exprs
+	return b
+}
+
 // Annotations adds a list of annotations to the field object to be used by
 // codegen extensions.
 func (b *jsonBuilder) Annotations(annotations ...schema.Annotation) *jsonBuilder {
@@ -1190,6 +1317,29 @@ func (b *enumBuilder) SchemaType(types map[string]string) *enumBuilder {
 	return b
 }
 
+// Generated makes the field a column that is computed by the database from
+// the given SQL expression. The create and update builders have no setters
+// for it, and the migration does not alter the column once it exists.
+//
+//	field.Enum("size").
+//		Generated("CASE WHEN weight > 10 THEN 'large' ELSE 'small' END", field.Stored)
+func (b *enumBuilder) Generated(expr string, kind GeneratedKind) *enumBuilder {
+	b.desc.generated(kind).Expr = expr
+	return b
+}
+
+// GeneratedExprs is like Generated, with an expression per dialect.
+// The expressions override the one of Generated for their dialects.
+//
+//	field.Enum("size").
+//		GeneratedExprs(map[string]string{
+//			dialect.Postgres: "CASE WHEN weight > 10 THEN 'large' ELSE 'small' END",
+//		}, field.Stored)
+func (b *enumBuilder) GeneratedExprs(exprs map[string]string, kind GeneratedKind) *enumBuilder {
+	b.desc.generated(kind).Exprs = exprs
+	return b
+}
+
 // Annotations adds a list of annotations to the field object to be used by
 // codegen extensions.
 //
@@ -1334,6 +1484,29 @@ func (b *uuidBuilder) SchemaType(types map[string]string) *uuidBuilder {
 	return b
 }
 
+// Generated makes the field a column that is computed by the database from
+// the given SQL expression. The create and update builders have no setters
+// for it, and the migration does not alter the column once it exists.
+//
+//	field.UUID("tenant_id", uuid.UUID{}).
+//		Generated("(data->>'tenant_id')::uuid", field.Stored)
+func (b *uuidBuilder) Generated(expr string, kind GeneratedKind) *uuidBuilder {
+	b.desc.generated(kind).Expr = expr
+	return b
+}
+
+// GeneratedExprs is like Generated, with an expression per dialect.
+// The expressions override the one of Generated for their dialects.
+//
+//	field.UUID("tenant_id", uuid.UUID{}).
+//		GeneratedExprs(map[string]string{
+//			dialect.Postgres: "(data->>'tenant_id')::uuid",
+//		}, field.Stored)
+func (b *uuidBuilder) GeneratedExprs(exprs map[string]string, kind GeneratedKind) *uuidBuilder {
+	b.desc.generated(kind).Exprs = exprs
+	return b
+}
+
 // Annotations adds a list of annotations to the field object to be used by
 // codegen extensions.
 //
@@ -1470,6 +1643,29 @@ func (b *otherBuilder) SchemaType(types map[string]string) *otherBuilder {
 	return b
 }
 
+// Generated makes the field a column that is computed by the database from
+// the given SQL expression. The create and update builders have no setters
+// for it, and the migration does not alter the column once it exists.
+//
+//	field.Other("total", &Money{}).
+//		Generated("price * quantity", field.Stored)
+func (b *otherBuilder) Generated(expr string, kind GeneratedKind) *otherBuilder {
+	b.desc.generated(kind).Expr = expr
+	return b
+}
+
+// GeneratedExprs is like Generated, with an expression per dialect.
+// The expressions override the one of Generated for their dialects.
+//
+//	field.Other("total", &Money{}).
+//		GeneratedExprs(map[string]string{
+//			dialect.Postgres: "price * quantity",
+//		}, field.Stored)
+func (b *otherBuilder) GeneratedExprs(exprs map[string]string, kind GeneratedKind) *otherBuilder {
+	b.desc.generated(kind).Exprs = exprs
+	return b
+}
+
 // Annotations adds a list of annotations to the field object to be used by
 // codegen extensions.
 //
@@ -1520,6 +1716,8 @@ type Descriptor struct {
 	Default          any                     // default value on create.
 	UpdateDefault    any                     // default value on update.
 	Validators       []any                   // validator functions.
+	Constraints      *Constraints            // constraints of the validators.
+	Generated        *Generated              // database-generated column.
 	StorageKey       string                  // sql column name.
 	Enums            []struct{ N, V string } // enum values.
 	Sensitive        bool                    // sensitive info string field.
@@ -1531,6 +1729,101 @@ type Descriptor struct {
 	Err              error
 }
 
+// GeneratedKind defines when the database computes a generated column.
+type GeneratedKind string
+
+// Kinds of generated columns.
+const (
+	// Virtual columns are computed when they are read, and use no storage.
+	// PostgreSQL supports stored columns only.
+	Virtual GeneratedKind = "VIRTUAL"
+	// Stored columns are computed when the row is written, and stored like
+	// other columns. Only stored columns can be indexed on all dialects.
+	Stored GeneratedKind = "STORED"
+)
+
+// Generated describes a column that is computed by the database from a SQL
+// expression of the other columns of the row.
+type Generated struct {
+	Expr  string            `json:"expr,omitempty"`  // SQL expression.
+	Exprs map[string]string `json:"exprs,omitempty"` // SQL expressions per dialect.
+	Kind  GeneratedKind     `json:"kind,omitempty"`  // virtual or stored.
+}
+
+// ExprFor returns the expression of the given dialect, and false if there is none.
+func (g *Generated) ExprFor(dialect string) (string, bool) {
+	if x, ok := g.Exprs[dialect]; ok {
+		return x, true
+	}
+	return g.Expr, g.Expr != ""
+}
+
+// generated returns the Generated of the field, and sets its kind.
+func (d *Descriptor) generated(kind GeneratedKind) *Generated {
+	if d.Generated == nil {
+		d.Generated = &Generated{}
+	}
+	d.Generated.Kind = kind
+	return d.Generated
+}
+
+// Constraints holds the value constraints declared by the built-in validators
+// of a field (MinLen, MaxLen, Match, Range, Min, Max, etc.). Unlike validator
+// functions, they are visible to the code generator. For example, the test
+// factories use them for deriving valid values.
+//
+// Bounds are inclusive. Positive and Negative of float fields record a bound
+// of 0, although the validator excludes it.
+type Constraints struct {
+	MinLen     int      `json:"min_len,omitempty"`      // minimum length in bytes.
+	MaxLen     int      `json:"max_len,omitempty"`      // maximum length in bytes.
+	MinRuneLen int      `json:"min_rune_len,omitempty"` // minimum length in runes.
+	MaxRuneLen int      `json:"max_rune_len,omitempty"` // maximum length in runes.
+	Pattern    string   `json:"pattern,omitempty"`      // last pattern added with Match.
+	Min        *float64 `json:"min,omitempty"`          // minimum value.
+	Max        *float64 `json:"max,omitempty"`          // maximum value.
+}
+
+func (c *Constraints) minLen(i int) {
+	c.MinLen = max(c.MinLen, i)
+}
+
+func (c *Constraints) maxLen(i int) {
+	if c.MaxLen == 0 || i < c.MaxLen {
+		c.MaxLen = i
+	}
+}
+
+func (c *Constraints) minRuneLen(i int) {
+	c.MinRuneLen = max(c.MinRuneLen, i)
+}
+
+func (c *Constraints) maxRuneLen(i int) {
+	if c.MaxRuneLen == 0 || i < c.MaxRuneLen {
+		c.MaxRuneLen = i
+	}
+}
+
+func (c *Constraints) atLeast(v float64) {
+	if c.Min == nil || v > *c.Min {
+		c.Min = &v
+	}
+}
+
+func (c *Constraints) atMost(v float64) {
+	if c.Max == nil || v < *c.Max {
+		c.Max = &v
+	}
+}
+
+// constrain returns the constraints of the field, and allocates them on first use.
+func (d *Descriptor) constrain() *Constraints {
+	if d.Constraints == nil {
+		d.Constraints = &Constraints{}
+	}
+	return d.Constraints
+}
+
 func (d *Descriptor) goType(typ any) {
 	t := reflect.TypeOf(typ)
 	tv := indirect(t)
@@ -474,6 +474,54 @@ func (s VString) Value() (driver.Value, error) {
 	return "", nil
 }
 
+func TestConstraints(t *testing.T) {
+	fd := field.String("name").Descriptor()
+	assert.Nil(t, fd.Constraints)
+
+	fd = field.String("name").
+		NotEmpty().
+		MinLen(3).
+		MaxLen(100).
+		MinRuneLen(2).
+		MaxRuneLen(50).
+		Match(regexp.MustCompile(`^[a-z]+$`)).
+		Descriptor()
+	assert.Equal(t, &field.Constraints{MinLen: 3, MaxLen: 100, MinRuneLen: 2, MaxRuneLen: 50, Pattern: `^[a-z]+$`}, fd.Constraints)
+
+	fd = field.Int("age").Positive().Range(0, 120).Max(150).Descriptor()
+	require.NotNil(t, fd.Constraints)
+	assert.Equal(t, 1.0, *fd.Constraints.Min)
+	assert.Equal(t, 120.0, *fd.Constraints.Max)
+
+	fd = field.Float("score").Negative().Descriptor()
+	assert.Nil(t, fd.Constraints.Min)
+	assert.Equal(t, 0.0, *fd.Constraints.Max)
+
+	fd = field.Bytes("blob").MaxLen(10).Descriptor()
+	assert.Equal(t, 10, fd.Constraints.MaxLen)
+}
+
+func TestGenerated(t *testing.T) {
+	fd := field.String("full_name").
+		Generated("first_name || ' ' || last_name", field.Stored).
+		GeneratedExprs(map[string]string{dialect.MySQL: "CONCAT(first_name, ' ', last_name)"}, field.Stored).
+		Descriptor()
+	require.NotNil(t, fd.Generated)
+	assert.Equal(t, field.Stored, fd.Generated.Kind)
+	x, ok := fd.Generated.ExprFor(dialect.MySQL)
+	assert.True(t, ok)
+	assert.Equal(t, "CONCAT(first_name, ' ', last_name)", x)
+	x, ok = fd.Generated.ExprFor(dialect.SQLite)
+	assert.True(t, ok)
+	assert.Equal(t, "first_name || ' ' || last_name", x)
+
+	fd = field.Int("total").GeneratedExprs(map[string]string{dialect.Postgres: "price * quantity"}, field.Virtual).Descriptor()
+	assert.Equal(t, field.Virtual, fd.Generated.Kind)
+	_, ok = fd.Generated.ExprFor(dialect.MySQL)
+	assert.False(t, ok)
+	assert.Nil(t, field.Int("total").Descriptor().Generated)
+}
+
 func TestString(t *testing.T) {
 	fd := field.String("name").
 		DefaultFunc(func() string {
@@ -1868,6 +1916,53 @@ func TestBoolBuilderDeprecated(t *testing.T) {
 }
 
 // TestJSONBuilderDeprecated tests jsonBuilder Deprecated method.
+func TestJSON_Fields(t *testing.T) {
+	type (
+		Address struct {
+			City    string  `json:"city"`
+			Zip     *string `json:"zip,omitempty"`
+			private string
+		}
+		Base struct {
+			Version int `json:"version"`
+		}
+		Info struct {
+			Base
+			Address  Address           `json:"address"`
+			Tags     []string          `json:"tags"`
+			Extra    map[string]string `json:"extra"`
+			Created  time.Time         `json:"created"`
+			Ignored  string            `json:"-"`
+			Quoted   int               `json:"quoted,string"`
+			Verified bool
+		}
+	)
+	fd := field.JSON("info", &Info{}).Descriptor()
+	require.NoError(t, fd.Err)
+	fields := fd.Info.RType.Fields
+	require.Len(t, fields, 3)
+	assert.Equal(t, &field.RField{Name: "Version", Key: "version", Type: &field.RType{Name: "int", Ident: "int", Kind: reflect.Int}}, fields[0])
+	assert.Equal(t, "Address", fields[1].Name)
+	assert.Equal(t, "address", fields[1].Key)
+	assert.Equal(t, reflect.Struct, fields[1].Type.Kind)
+	require.Len(t, fields[1].Type.Fields, 2)
+	assert.Equal(t, "city", fields[1].Type.Fields[0].Key)
+	assert.Equal(t, &field.RType{Name: "string", Ident: "string", Kind: reflect.String}, fields[1].Type.Fields[1].Type)
+	assert.Equal(t, "Verified", fields[2].Key)
+
+	type Node struct {
+		Name string `json:"name"`
+		Next *Node  `json:"next"`
+	}
+	fd = field.JSON("node", Node{}).Descriptor()
+	require.Len(t, fd.Info.RType.Fields, 1)
+
+	fd = field.JSON("time", time.Time{}).Descriptor()
+	assert.Empty(t, fd.Info.RType.Fields)
+	fd = field.Strings("strings").Descriptor()
+	assert.Empty(t, fd.Info.RType.Fields)
+}
+
 func TestJSONBuilderDeprecated(t *testing.T) {
 	fd := field.JSON("old_meta", map[string]any{}).Deprecated().Descriptor()
 	assert.True(t, fd.Deprecated)
@@ -0,0 +1,225 @@
+package field
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ID generators for use as field defaults. See the mixins in the
+// schema/mixin package for ready-made ID fields.
+//
+//	field.UUID("id", uuid.UUID{}).Default(field.NewUUIDv7)
+//	field.String("id").DefaultFunc(field.NewULID)
+//	field.Int64("id").DefaultFunc(field.SnowflakeNode(1).Next)
+//	field.String("id").DefaultFunc(field.PrefixedID("usr")).Annotations(field.IDPrefix("usr"))
+
+// NewUUIDv7 returns a new time-ordered UUID (version 7).
+func NewUUIDv7() uuid.UUID {
+	return uuid.Must(uuid.NewV7())
+}
+
+// crockford is the Crockford's base32 alphabet used by ULIDs.
+const crockford = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+// ulidGen generates monotonic ULIDs: IDs generated within the same
+// millisecond increment the random part of the previous ID.
+var ulidGen struct {
+	sync.Mutex
+	ms   uint64
+	rand [10]byte
+}
+
+// NewULID returns a new ULID: a 26-character, lexicographically sortable
+// identifier made of a 48-bit millisecond timestamp and 80 random bits.
+// IDs generated by the same process are strictly increasing.
+func NewULID() string {
+	ms := uint64(time.Now().UnixMilli())
+	ulidGen.Lock()
+	if ms <= ulidGen.ms {
+		ms = ulidGen.ms
+		// Increment the 80-bit random part. An overflow moves to the next millisecond.
+		i := len(ulidGen.rand) - 1
+		for ; i >= 0; i-- {
+			ulidGen.rand[i]++
+			if ulidGen.rand[i] != 0 {
+				break
+			}
+		}
+		if i < 0 {
+			ms++
+		}
+	} else if _, err := rand.Read(ulidGen.rand[:]); err != nil {
+		ulidGen.Unlock()
+		panic(fmt.Errorf("field.NewULID: %w", err))
+	}
+	ulidGen.ms = ms
+	var b [16]byte
+	b[0], b[1], b[2], b[3], b[4], b[5] = byte(ms>>40), byte(ms>>32), byte(ms>>24), byte(ms>>16), byte(ms>>8), byte(ms)
+	copy(b[6:], ulidGen.rand[:])
+	ulidGen.Unlock()
+	return encodeULID(b)
+}
+
+// encodeULID encodes the 128-bit ULID in 26 base32 characters.
+func encodeULID(b [16]byte) string {
+	hi, lo := binary.BigEndian.Uint64(b[:8]), binary.BigEndian.Uint64(b[8:])
+	var s [26]byte
+	for i := 25; i >= 0; i-- {
+		s[i] = crockford[lo&0x1f]
+		lo = lo>>5 | hi<<59
+		hi >>= 5
+	}
+	return string(s[:])
+}
+
+// ULIDTime returns the time encoded in the given ULID.
+func ULIDTime(id string) (time.Time, error) {
+	if len(id) != 26 {
+		return time.Time{}, fmt.Errorf("field: invalid ULID %q", id)
+	}
+	var ms uint64
+	for i := range 10 {
+		v := strings.IndexByte(crockford, id[i])
+		if v < 0 {
+			return time.Time{}, fmt.Errorf("field: invalid ULID %q", id)
+		}
+		ms = ms<<5 | uint64(v)
+	}
+	return time.UnixMilli(int64(ms)), nil
+}
+
+// Snowflake ID layout: 41 bits of milliseconds since SnowflakeEpoch,
+// 10 bits of node ID and 12 bits of sequence.
+const (
+	snowflakeNodeBits = 10
+	snowflakeSeqBits  = 12
+	// MaxSnowflakeNode is the largest node ID of a Snowflake generator.
+	MaxSnowflakeNode = 1<<snowflakeNodeBits - 1
+)
+
+// SnowflakeEpoch is the epoch of Snowflake IDs. Changing it after IDs
+// were generated may produce duplicate IDs.
+var SnowflakeEpoch = time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+// Snowflake generates 64-bit, time-ordered IDs. Each process (or replica)
+// that generates IDs for the same table must use a different node ID.
+type Snowflake struct {
+	mu   sync.Mutex
+	node int64
+	ms   int64
+	seq  int64
+}
+
+// NewSnowflake returns a Snowflake generator for the given node ID.
+func NewSnowflake(node int64) (*Snowflake, error) {
+	if node < 0 || node > MaxSnowflakeNode {
+		return nil, fmt.Errorf("field: snowflake node %d out of range [0, %d]", node, MaxSnowflakeNode)
+	}
+	return &Snowflake{node: node}, nil
+}
+
+var snowflakes sync.Map
+
+// SnowflakeNode returns the shared Snowflake generator of the given node ID.
+// Schemas and mixins that use the same node share the same sequence, so IDs
+// are unique across types. It panics if the node ID is out of range.
+//
+//	field.Int64("id").DefaultFunc(field.SnowflakeNode(1).Next)
+func SnowflakeNode(node int64) *Snowflake {
+	if s, ok := snowflakes.Load(node); ok {
+		return s.(*Snowflake)
+	}
+	s, err := NewSnowflake(node)
+	if err != nil {
+		panic(err)
+	}
+	actual, _ := snowflakes.LoadOrStore(node, s)
+	return actual.(*Snowflake)
+}
+
+// Next returns the next ID. It blocks until the next millisecond if the
+// sequence of the current millisecond is exhausted, or if the clock moved
+// backwards.
+func (s *Snowflake) Next() int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	ms := time.Since(SnowflakeEpoch).Milliseconds()
+	switch {
+	case ms < s.ms:
+		ms = s.wait()
+		s.seq = 0
+	case ms == s.ms:
+		s.seq = (s.seq + 1) & (1<<snowflakeSeqBits - 1)
+		if s.seq == 0 {
+			ms = s.wait()
+		}
+	default:
+		s.seq = 0
+	}
+	s.ms = ms
+	return ms<<(snowflakeNodeBits+snowflakeSeqBits) | s.node<<snowflakeSeqBits | s.seq
+}
+
+// wait blocks until the clock passes the last used millisecond.
+func (s *Snowflake) wait() int64 {
+	for {
+		if ms := time.Since(SnowflakeEpoch).Milliseconds(); ms > s.ms {
+			return ms
+		}
+		time.Sleep(100 * time.Microsecond)
+	}
+}
+
+// ParseSnowflake returns the time, node ID and sequence of a Snowflake ID.
+func ParseSnowflake(id int64) (t time.Time, node, seq int64) {
+	ms := id >> (snowflakeNodeBits + snowflakeSeqBits)
+	node = id >> snowflakeSeqBits & MaxSnowflakeNode
+	seq = id & (1<<snowflakeSeqBits - 1)
+	return SnowflakeEpoch.Add(time.Duration(ms) * time.Millisecond), node, seq
+}
+
+// PrefixedID returns a generator of Stripe-style IDs made of the given
+// prefix, an underscore and a ULID, e.g. "usr_01HQ3Z7N0D5X3VJ0B8TQ9W2K4M".
+// The prefix tags the ID with its type; annotate the ID field with IDPrefix
+// to let the GraphQL node resolution dispatch on it. It panics if the prefix
+// is not made of lowercase letters and digits.
+//
+//	field.String("id").
+//		DefaultFunc(field.PrefixedID("usr")).
+//		Annotations(field.IDPrefix("usr"))
+func PrefixedID(prefix string) func() string {
+	if err := validIDPrefix(prefix); err != nil {
+		panic(err)
+	}
+	return func() string {
+		return prefix + "_" + NewULID()
+	}
+}
+
+// SplitPrefixedID splits a prefixed ID into its prefix and the rest.
+func SplitPrefixedID(id string) (prefix, rest string, ok bool) {
+	prefix, rest, ok = strings.Cut(id, "_")
+	if !ok || validIDPrefix(prefix) != nil || rest == "" {
+		return "", "", false
+	}
+	return prefix, rest, true
+}
+
+// validIDPrefix reports an error if the prefix is not a valid ID prefix.
+func validIDPrefix(prefix string) error {
+	if prefix == "" {
+		return fmt.Errorf("field: empty ID prefix")
+	}
+	for _, r := range prefix {
+		if (r < 'a' || r > 'z') && (r < '0' || r > '9') {
+			return fmt.Errorf("field: invalid ID prefix %q: only lowercase letters and digits are allowed", prefix)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,77 @@
+package field_test
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/syssam/velox/schema/field"
+)
+
+func TestNewUUIDv7(t *testing.T) {
+	a, b := field.NewUUIDv7(), field.NewUUIDv7()
+	assert.EqualValues(t, 7, a.Version())
+	assert.NotEqual(t, a, b)
+}
+
+func TestNewULID(t *testing.T) {
+	ids := make([]string, 1000)
+	for i := range ids {
+		ids[i] = field.NewULID()
+		require.Len(t, ids[i], 26)
+	}
+	assert.True(t, slices.IsSorted(ids), "ULIDs generated by the same process are increasing")
+	assert.Len(t, slices.Compact(slices.Clone(ids)), len(ids))
+
+	ts, err := field.ULIDTime(ids[0])
+	require.NoError(t, err)
+	assert.WithinDuration(t, time.Now(), ts, time.Minute)
+	_, err = field.ULIDTime("invalid")
+	assert.Error(t, err)
+	_, err = field.ULIDTime("ILOU0000000000000000000000")
+	assert.Error(t, err)
+}
+
+func TestSnowflake(t *testing.T) {
+	_, err := field.NewSnowflake(field.MaxSnowflakeNode + 1)
+	require.Error(t, err)
+	assert.Panics(t, func() { field.SnowflakeNode(-1) })
+
+	s, err := field.NewSnowflake(7)
+	require.NoError(t, err)
+	ids := make([]int64, 10000)
+	for i := range ids {
+		ids[i] = s.Next()
+	}
+	assert.True(t, slices.IsSorted(ids))
+	assert.Len(t, slices.Compact(slices.Clone(ids)), len(ids))
+
+	ts, node, _ := field.ParseSnowflake(ids[0])
+	assert.EqualValues(t, 7, node)
+	assert.WithinDuration(t, time.Now(), ts, time.Minute)
+
+	assert.Same(t, field.SnowflakeNode(3), field.SnowflakeNode(3))
+}
+
+func TestPrefixedID(t *testing.T) {
+	id := field.PrefixedID("usr")()
+	prefix, rest, ok := field.SplitPrefixedID(id)
+	require.True(t, ok)
+	assert.Equal(t, "usr", prefix)
+	assert.Len(t, rest, 26)
+
+	for _, id := range []string{"usr", "usr_", "Usr_01HQ3Z7N0D5X3VJ0B8TQ9W2K4M", "_01HQ3Z7N0D5X3VJ0B8TQ9W2K4M"} {
+		_, _, ok := field.SplitPrefixedID(id)
+		assert.False(t, ok, id)
+	}
+	assert.Panics(t, func() { field.PrefixedID("user-") })
+	assert.Panics(t, func() { field.IDPrefix("") })
+}
+
+func TestAnnotation_IDPrefix(t *testing.T) {
+	a := field.Annotation{}.Merge(field.IDPrefix("org"))
+	assert.Equal(t, "org", a.(field.Annotation).IDPrefix)
+}
@@ -127,6 +127,9 @@ func (b *intBuilder) Unique() *intBuilder {
 
 // Range adds a range validator for this field where the given value needs to be in the range of [i, j].
 func (b *intBuilder) Range(i, j int) *intBuilder {
+	c := b.desc.constrain()
+	c.atLeast(float64(i))
+	c.atMost(float64(j))
 	b.desc.Validators = append(b.desc.Validators, func(v int) error {
 		if v < i || v > j {
 			return fmt.Errorf("value %v out of range [%v, %v]", v, i, j)
@@ -138,6 +141,7 @@ func (b *intBuilder) Range(i, j int) *intBuilder {
 
 // Min adds a minimum value validator for this field. Operation fails if the validator fails.
 func (b *intBuilder) Min(i int) *intBuilder {
+	b.desc.constrain().atLeast(float64(i))
 	b.desc.Validators = append(b.desc.Validators, func(v int) error {
 		if v < i {
 			return fmt.Errorf("value %v less than minimum %v", v, i)
@@ -149,6 +153,7 @@ func (b *intBuilder) Min(i int) *intBuilder {
 
 // Max adds a maximum value validator for this field. Operation fails if the validator fails.
 func (b *intBuilder) Max(i int) *intBuilder {
+	b.desc.constrain().atMost(float64(i))
 	b.desc.Validators = append(b.desc.Validators, func(v int) error {
 		if v > i {
 			return fmt.Errorf("value %v greater than maximum %v", v, i)
@@ -263,6 +268,30 @@ func (b *intBuilder) SchemaType(types map[string]string) *intBuilder {
 	return b
 }
 
+// Generated makes the field a column that is computed by the database from
+// the given SQL expression. The create and update builders have no setters
+// for it, and the migration does not alter the column once it exists.
+//
+//	field.Int("total").
+//		Generated("price * quantity", field.Stored)
+func (b *intBuilder) Generated(expr string, kind GeneratedKind) *intBuilder {
+	b.desc.generated(kind).Expr = expr
+	return b
+}
+
+// GeneratedExprs is like Generated, with an expression per dialect.
+// The expressions override the one of Generated for their dialects.
+//
+//	field.Int("total").
+//		GeneratedExprs(map[string]string{
+//			dialect.MySQL:    "JSON_EXTRACT(data, '$.total')",
+//			dialect.Postgres: "(data->>'total')::numeric",
+//		}, field.Stored)
+func (b *intBuilder) GeneratedExprs(exprs map[string]string, kind GeneratedKind) *intBuilder {
+	b.desc.generated(kind).Exprs = exprs
+	return b
+}
+
 // GoType overrides the default Go type with a custom one.
 // If the provided type implements the Validator interface
 // and no validators have been set, the type validator will
@@ -334,6 +363,9 @@ func (b *uintBuilder) Unique() *uintBuilder {
 
 // Range adds a range validator for this field where the given value needs to be in the range of [i, j].
 func (b *uintBuilder) Range(i, j uint) *uintBuilder {
+	c := b.desc.constrain()
+	c.atLeast(float64(i))
+	c.atMost(float64(j))
 	b.desc.Validators = append(b.desc.Validators, func(v uint) error {
 		if v < i || v > j {
 			return fmt.Errorf("value %v out of range [%v, %v]", v, i, j)
@@ -345,6 +377,7 @@ func (b *uintBuilder) Range(i, j uint) *uintBuilder {
 
 // Min adds a minimum value validator for this field. Operation fails if the validator fails.
 func (b *uintBuilder) Min(i uint) *uintBuilder {
+	b.desc.constrain().atLeast(float64(i))
 	b.desc.Validators = append(b.desc.Validators, func(v uint) error {
 		if v < i {
 			return fmt.Errorf("value %v less than minimum %v", v, i)
@@ -356,6 +389,7 @@ func (b *uintBuilder) Min(i uint) *uintBuilder {
 
 // Max adds a maximum value validator for this field. Operation fails if the validator fails.
 func (b *uintBuilder) Max(i uint) *uintBuilder {
+	b.desc.constrain().atMost(float64(i))
 	b.desc.Validators = append(b.desc.Validators, func(v uint) error {
 		if v > i {
 			return fmt.Errorf("value %v greater than maximum %v", v, i)
@@ -460,6 +494,30 @@ func (b *uintBuilder) SchemaType(types map[string]string) *uintBuilder {
 	return b
 }
 
+// Generated makes the field a column that is computed by the database from
+// the given SQL expression. The create and update builders have no setters
+// for it, and the migration does not alter the column once it exists.
+//
+//	field.Uint("total").
+//		Generated("price * quantity", field.Stored)
+func (b *uintBuilder) Generated(expr string, kind GeneratedKind) *uintBuilder {
+	b.desc.generated(kind).Expr = expr
+	return b
+}
+
+// GeneratedExprs is like Generated, with an expression per dialect.
+// The expressions override the one of Generated for their dialects.
+//
+//	field.Uint("total").
+//		GeneratedExprs(map[string]string{
+//			dialect.MySQL:    "JSON_EXTRACT(data, '$.total')",
+//			dialect.Postgres: "(data->>'total')::numeric",
+//		}, field.Stored)
+func (b *uintBuilder) GeneratedExprs(exprs map[string]string, kind GeneratedKind) *uintBuilder {
+	b.desc.generated(kind).Exprs = exprs
+	return b
+}
+
 // GoType overrides the default Go type with a custom one.
 // If the provided type implements the Validator interface
 // and no validators have been set, the type validator will
@@ -531,6 +589,9 @@ func (b *int8Builder) Unique() *int8Builder {
 
 // Range adds a range validator for this field where the given value needs to be in the range of [i, j].
 func (b *int8Builder) Range(i, j int8) *int8Builder {
+	c := b.desc.constrain()
+	c.atLeast(float64(i))
+	c.atMost(float64(j))
 	b.desc.Validators = append(b.desc.Validators, func(v int8) error {
 		if v < i || v > j {
 			return fmt.Errorf("value %v out of range [%v, %v]", v, i, j)
@@ -542,6 +603,7 @@ func (b *int8Builder) Range(i, j int8) *int8Builder {
 
 // Min adds a minimum value validator for this field. Operation fails if the validator fails.
 func (b *int8Builder) Min(i int8) *int8Builder {
+	b.desc.constrain().atLeast(float64(i))
 	b.desc.Validators = append(b.desc.Validators, func(v int8) error {
 		if v < i {
 			return fmt.Errorf("value %v less than minimum %v", v, i)
@@ -553,6 +615,7 @@ func (b *int8Builder) Min(i int8) *int8Builder {
 
 // Max adds a maximum value validator for this field. Operation fails if the validator fails.
 func (b *int8Builder) Max(i int8) *int8Builder {
+	b.desc.constrain().atMost(float64(i))
 	b.desc.Validators = append(b.desc.Validators, func(v int8) error {
 		if v > i {
 			return fmt.Errorf("value %v greater than maximum %v", v, i)
@@ -667,6 +730,30 @@ func (b *int8Builder) SchemaType(types map[string]string) *int8Builder {
 	return b
 }
 
+// Generated makes the field a column that is computed by the database from
+// the given SQL expression. The create and update builders have no setters
+// for it, and the migration does not alter the column once it exists.
+//
+//	field.Int8("total").
+//		Generated("price * quantity", field.Stored)
+func (b *int8Builder) Generated(expr string, kind GeneratedKind) *int8Builder {
+	b.desc.generated(kind).Expr = expr
+	return b
+}
+
+// GeneratedExprs is like Generated, with an expression per dialect.
+// The expressions override the one of Generated for their dialects.
+//
+//	field.Int8("total").
+//		GeneratedExprs(map[string]string{
+//			dialect.MySQL:    "JSON_EXTRACT(data, '$.total')",
+//			dialect.Postgres: "(data->>'total')::numeric",
+//		}, field.Stored)
+func (b *int8Builder) GeneratedExprs(exprs map[string]string, kind GeneratedKind) *int8Builder {
+	b.desc.generated(kind).Exprs = exprs
+	return b
+}
+
 // GoType overrides the default Go type with a custom one.
 // If the provided type implements the Validator interface
 // and no validators have been set, the type validator will
@@ -738,6 +825,9 @@ func (b *int16Builder) Unique() *int16Builder {
 
 // Range adds a range validator for this field where the given value needs to be in the range of [i, j].
 func (b *int16Builder) Range(i, j int16) *int16Builder {
+	c := b.desc.constrain()
+	c.atLeast(float64(i))
+	c.atMost(float64(j))
 	b.desc.Validators = append(b.desc.Validators, func(v int16) error {
 		if v < i || v > j {
 			return fmt.Errorf("value %v out of range [%v, %v]", v, i, j)
@@ -749,6 +839,7 @@ func (b *int16Builder) Range(i, j int16) *int16Builder {
 
 // Min adds a minimum value validator for this field. Operation fails if the validator fails.
 func (b *int16Builder) Min(i int16) *int16Builder {
+	b.desc.constrain().atLeast(float64(i))
 	b.desc.Validators = append(b.desc.Validators, func(v int16) error {
 		if v < i {
 			return fmt.Errorf("value %v less than minimum %v", v, i)
@@ -760,6 +851,7 @@ func (b *int16Builder) Min(i int16) *int16Builder {
 
 // Max adds a maximum value validator for this field. Operation fails if the validator fails.
 func (b *int16Builder) Max(i int16) *int16Builder {
+	b.desc.constrain().atMost(float64(i))
 	b.desc.Validators = append(b.desc.Validators, func(v int16) error {
 		if v > i {
 			return fmt.Errorf("value %v greater than maximum %v", v, i)
@@ -874,6 +966,30 @@ func (b *int16Builder) SchemaType(types map[string]string) *int16Builder {
 	return b
 }
 
+// Generated makes the field a column that is computed by the database from
+// the given SQL expression. The create and update builders have no setters
+// for it, and the migration does not alter the column once it exists.
+//
+//	field.Int16("total").
+//		Generated("price * quantity", field.Stored)
+func (b *int16Builder) Generated(expr string, kind GeneratedKind) *int16Builder {
+	b.desc.generated(kind).Expr = expr
+	return b
+}
+
+// GeneratedExprs is like Generated, with an expression per dialect.
+// The expressions override the one of Generated for their dialects.
+//
+//	field.Int16("total").
+//		GeneratedExprs(map[string]string{
+//			dialect.MySQL:    "JSON_EXTRACT(data, '$.total')",
+//			dialect.Postgres: "(data->>'total')::numeric",
+//		}, field.Stored)
+func (b *int16Builder) GeneratedExprs(exprs map[string]string, kind GeneratedKind) *int16Builder {
+	b.desc.generated(kind).Exprs = exprs
+	return b
+}
+
 // GoType overrides the default Go type with a custom one.
 // If the provided type implements the Validator interface
 // and no validators have been set, the type validator will
@@ -945,6 +1061,9 @@ func (b *int32Builder) Unique() *int32Builder {
 
 // Range adds a range validator for this field where the given value needs to be in the range of [i, j].
 func (b *int32Builder) Range(i, j int32) *int32Builder {
+	c := b.desc.constrain()
+	c.atLeast(float64(i))
+	c.atMost(float64(j))
 	b.desc.Validators = append(b.desc.Validators, func(v int32) error {
 		if v < i || v > j {
 			return fmt.Errorf("value %v out of range [%v, %v]", v, i, j)
@@ -956,6 +1075,7 @@ func (b *int32Builder) Range(i, j int32) *int32Builder {
 
 // Min adds a minimum value validator for this field. Operation fails if the validator fails.
 func (b *int32Builder) Min(i int32) *int32Builder {
+	b.desc.constrain().atLeast(float64(i))
 	b.desc.Validators = append(b.desc.Validators, func(v int32) error {
 		if v < i {
 			return fmt.Errorf("value %v less than minimum %v", v, i)
@@ -967,6 +1087,7 @@ func (b *int32Builder) Min(i int32) *int32Builder {
 
 // Max adds a maximum value validator for this field. Operation fails if the validator fails.
 func (b *int32Builder) Max(i int32) *int32Builder {
+	b.desc.constrain().atMost(float64(i))
 	b.desc.Validators = append(b.desc.Validators, func(v int32) error {
 		if v > i {
 			return fmt.Errorf("value %v greater than maximum %v", v, i)
@@ -1081,6 +1202,30 @@ func (b *int32Builder) SchemaType(types map[string]string) *int32Builder {
 	return b
 }
 
+// Generated makes the field a column that is computed by the database from
+// the given SQL expression. The create and update builders have no setters
+// for it, and the migration does not alter the column once it exists.
+//
+//	field.Int32("total").
+//		Generated("price * quantity", field.Stored)
+func (b *int32Builder) Generated(expr string, kind GeneratedKind) *int32Builder {
+	b.desc.generated(kind).Expr = expr
+	return b
+}
+
+// GeneratedExprs is like Generated, with an expression per dialect.
+// The expressions override the one of Generated for their dialects.
+//
+//	field.Int32("total").
+//		GeneratedExprs(map[string]string{
+//			dialect.MySQL:    "JSON_EXTRACT(data, '$.total')",
+//			dialect.Postgres: "(data->>'total')::numeric",
+//		}, field.Stored)
+func (b *int32Builder) GeneratedExprs(exprs map[string]string, kind GeneratedKind) *int32Builder {
+	b.desc.generated(kind).Exprs = exprs
+	return b
+}
+
 // GoType overrides the default Go type with a custom one.
 // If the provided type implements the Validator interface
 // and no validators have been set, the type validator will
@@ -1152,6 +1297,9 @@ func (b *int64Builder) Unique() *int64Builder {
 
 // Range adds a range validator for this field where the given value needs to be in the range of [i, j].
 func (b *int64Builder) Range(i, j int64) *int64Builder {
+	c := b.desc.constrain()
+	c.atLeast(float64(i))
+	c.atMost(float64(j))
 	b.desc.Validators = append(b.desc.Validators, func(v int64) error {
 		if v < i || v > j {
 			return fmt.Errorf("value %v out of range [%v, %v]", v, i, j)
@@ -1163,6 +1311,7 @@ func (b *int64Builder) Range(i, j int64) *int64Builder {
 
 // Min adds a minimum value validator for this field. Operation fails if the validator fails.
 func (b *int64Builder) Min(i int64) *int64Builder {
+	b.desc.constrain().atLeast(float64(i))
 	b.desc.Validators = append(b.desc.Validators, func(v int64) error {
 		if v < i {
 			return fmt.Errorf("value %v less than minimum %v", v, i)
@@ -1174,6 +1323,7 @@ func (b *int64Builder) Min(i int64) *int64Builder {
 
 // Max adds a maximum value validator for this field. Operation fails if the validator fails.
 func (b *int64Builder) Max(i int64) *int64Builder {
+	b.desc.constrain().atMost(float64(i))
 	b.desc.Validators = append(b.desc.Validators, func(v int64) error {
 		if v > i {
 			return fmt.Errorf("value %v greater than maximum %v", v, i)
@@ -1288,6 +1438,30 @@ func (b *int64Builder) SchemaType(types map[string]string) *int64Builder {
 	return b
 }
 
+// Generated makes the field a column that is computed by the database from
+// the given SQL expression. The create and update builders have no setters
+// for it, and the migration does not alter the column once it exists.
+//
+//	field.Int64("total").
+//		Generated("price * quantity", field.Stored)
+func (b *int64Builder) Generated(expr string, kind GeneratedKind) *int64Builder {
+	b.desc.generated(kind).Expr = expr
+	return b
+}
+
+// GeneratedExprs is like Generated, with an expression per dialect.
+// The expressions override the one of Generated for their dialects.
+//
+//	field.Int64("total").
+//		GeneratedExprs(map[string]string{
+//			dialect.MySQL:    "JSON_EXTRACT(data, '$.total')",
+//			dialect.Postgres: "(data->>'total')::numeric",
+//		}, field.Stored)
+func (b *int64Builder) GeneratedExprs(exprs map[string]string, kind GeneratedKind) *int64Builder {
+	b.desc.generated(kind).Exprs = exprs
+	return b
+}
+
 // GoType overrides the default Go type with a custom one.
 // If the provided type implements the Validator interface
 // and no validators have been set, the type validator will
@@ -1359,6 +1533,9 @@ func (b *uint8Builder) Unique() *uint8Builder {
 
 // Range adds a range validator for this field where the given value needs to be in the range of [i, j].
 func (b *uint8Builder) Range(i, j uint8) *uint8Builder {
+	c := b.desc.constrain()
+	c.atLeast(float64(i))
+	c.atMost(float64(j))
 	b.desc.Validators = append(b.desc.Validators, func(v uint8) error {
 		if v < i || v > j {
 			return fmt.Errorf("value %v out of range [%v, %v]", v, i, j)
@@ -1370,6 +1547,7 @@ func (b *uint8Builder) Range(i, j uint8) *uint8Builder {
 
 // Min adds a minimum value validator for this field. Operation fails if the validator fails.
 func (b *uint8Builder) Min(i uint8) *uint8Builder {
+	b.desc.constrain().atLeast(float64(i))
 	b.desc.Validators = append(b.desc.Validators, func(v uint8) error {
 		if v < i {
 			return fmt.Errorf("value %v less than minimum %v", v, i)
@@ -1381,6 +1559,7 @@ func (b *uint8Builder) Min(i uint8) *uint8Builder {
 
 // Max adds a maximum value validator for this field. Operation fails if the validator fails.
 func (b *uint8Builder) Max(i uint8) *uint8Builder {
+	b.desc.constrain().atMost(float64(i))
 	b.desc.Validators = append(b.desc.Validators, func(v uint8) error {
 		if v > i {
 			return fmt.Errorf("value %v greater than maximum %v", v, i)
@@ -1485,6 +1664,30 @@ func (b *uint8Builder) SchemaType(types map[string]string) *uint8Builder {
 	return b
 }
 
+// Generated makes the field a column that is computed by the database from
+// the given SQL expression. The create and update builders have no setters
+// for it, and the migration does not alter the column once it exists.
+//
+//	field.Uint8("total").
+//		Generated("price * quantity", field.Stored)
+func (b *uint8Builder) Generated(expr string, kind GeneratedKind) *uint8Builder {
+	b.desc.generated(kind).Expr = expr
+	return b
+}
+
+// GeneratedExprs is like Generated, with an expression per dialect.
+// The expressions override the one of Generated for their dialects.
+//
+//	field.Uint8("total").
+//		GeneratedExprs(map[string]string{
+//			dialect.MySQL:    "JSON_EXTRACT(data, '$.total')",
+//			dialect.Postgres: "(data->>'total')::numeric",
+//		}, field.Stored)
+func (b *uint8Builder) GeneratedExprs(exprs map[string]string, kind GeneratedKind) *uint8Builder {
+	b.desc.generated(kind).Exprs = exprs
+	return b
+}
+
 // GoType overrides the default Go type with a custom one.
 // If the provided type implements the Validator interface
 // and no validators have been set, the type validator will
@@ -1556,6 +1759,9 @@ func (b *uint16Builder) Unique() *uint16Builder {
 
 // Range adds a range validator for this field where the given value needs to be in the range of [i, j].
 func (b *uint16Builder) Range(i, j uint16) *uint16Builder {
+	c := b.desc.constrain()
+	c.atLeast(float64(i))
+	c.atMost(float64(j))
 	b.desc.Validators = append(b.desc.Validators, func(v uint16) error {
 		if v < i || v > j {
 			return fmt.Errorf("value %v out of range [%v, %v]", v, i, j)
@@ -1567,6 +1773,7 @@ func (b *uint16Builder) Range(i, j uint16) *uint16Builder {
 
 // Min adds a minimum value validator for this field. Operation fails if the validator fails.
 func (b *uint16Builder) Min(i uint16) *uint16Builder {
+	b.desc.constrain().atLeast(float64(i))
 	b.desc.Validators = append(b.desc.Validators, func(v uint16) error {
 		if v < i {
 			return fmt.Errorf("value %v less than minimum %v", v, i)
@@ -1578,6 +1785,7 @@ func (b *uint16Builder) Min(i uint16) *uint16Builder {
 
 // Max adds a maximum value validator for this field. Operation fails if the validator fails.
 func (b *uint16Builder) Max(i uint16) *uint16Builder {
+	b.desc.constrain().atMost(float64(i))
 	b.desc.Validators = append(b.desc.Validators, func(v uint16) error {
 		if v > i {
 			return fmt.Errorf("value %v greater than maximum %v", v, i)
@@ -1682,6 +1890,30 @@ func (b *uint16Builder) SchemaType(types map[string]string) *uint16Builder {
 	return b
 }
 
+// Generated makes the field a column that is computed by the database from
+// the given SQL expression. The create and update builders have no setters
+// for it, and the migration does not alter the column once it exists.
+//
+//	field.Uint16("total").
+//		Generated("price * quantity", field.Stored)
+func (b *uint16Builder) Generated(expr string, kind GeneratedKind) *uint16Builder {
+	b.desc.generated(kind).Expr = expr
+	return b
+}
+
+// GeneratedExprs is like Generated, with an expression per dialect.
+// The expressions override the one of Generated for their dialects.
+//
+//	field.Uint16("total").
+//		GeneratedExprs(map[string]string{
+//			dialect.MySQL:    "JSON_EXTRACT(data, '$.total')",
+//			dialect.Postgres: "(data->>'total')::numeric",
+//		}, field.Stored)
+func (b *uint16Builder) GeneratedExprs(exprs map[string]string, kind GeneratedKind) *uint16Builder {
+	b.desc.generated(kind).Exprs = exprs
+	return b
+}
+
 // GoType overrides the default Go type with a custom one.
 // If the provided type implements the Validator interface
 // and no validators have been set, the type validator will
@@ -1753,6 +1985,9 @@ func (b *uint32Builder) Unique() *uint32Builder {
 
 // Range adds a range validator for this field where the given value needs to be in the range of [i, j].
 func (b *uint32Builder) Range(i, j uint32) *uint32Builder {
+	c := b.desc.constrain()
+	c.atLeast(float64(i))
+	c.atMost(float64(j))
 	b.desc.Validators = append(b.desc.Validators, func(v uint32) error {
 		if v < i || v > j {
 			return fmt.Errorf("value %v out of range [%v, %v]", v, i, j)
@@ -1764,6 +1999,7 @@ func (b *uint32Builder) Range(i, j uint32) *uint32Builder {
 
 // Min adds a minimum value validator for this field. Operation fails if the validator fails.
 func (b *uint32Builder) Min(i uint32) *uint32Builder {
+	b.desc.constrain().atLeast(float64(i))
 	b.desc.Validators = append(b.desc.Validators, func(v uint32) error {
 		if v < i {
 			return fmt.Errorf("value %v less than minimum %v", v, i)
@@ -1775,6 +2011,7 @@ func (b *uint32Builder) Min(i uint32) *uint32Builder {
 
 // Max adds a maximum value validator for this field. Operation fails if the validator fails.
 func (b *uint32Builder) Max(i uint32) *uint32Builder {
+	b.desc.constrain().atMost(float64(i))
 	b.desc.Validators = append(b.desc.Validators, func(v uint32) error {
 		if v > i {
 			return fmt.Errorf("value %v greater than maximum %v", v, i)
@@ -1879,6 +2116,30 @@ func (b *uint32Builder) SchemaType(types map[string]string) *uint32Builder {
 	return b
 }
 
+// Generated makes the field a column that is computed by the database from
+// the given SQL expression. The create and update builders have no setters
+// for it, and the migration does not alter the column once it exists.
+//
+//	field.Uint32("total").
+//		Generated("price * quantity", field.Stored)
+func (b *uint32Builder) Generated(expr string, kind GeneratedKind) *uint32Builder {
+	b.desc.generated(kind).Expr = expr
+	return b
+}
+
+// GeneratedExprs is like Generated, with an expression per dialect.
+// The expressions override the one of Generated for their dialects.
+//
+//	field.Uint32("total").
+//		GeneratedExprs(map[string]string{
+//			dialect.MySQL:    "JSON_EXTRACT(data, '$.total')",
+//			dialect.Postgres: "(data->>'total')::numeric",
+//		}, field.Stored)
+func (b *uint32Builder) GeneratedExprs(exprs map[string]string, kind GeneratedKind) *uint32Builder {
+	b.desc.generated(kind).Exprs = exprs
+	return b
+}
+
 // GoType overrides the default Go type with a custom one.
 // If the provided type implements the Validator interface
 // and no validators have been set, the type validator will
@@ -1950,6 +2211,9 @@ func (b *uint64Builder) Unique() *uint64Builder {
 
 // Range adds a range validator for this field where the given value needs to be in the range of [i, j].
 func (b *uint64Builder) Range(i, j uint64) *uint64Builder {
+	c := b.desc.constrain()
+	c.atLeast(float64(i))
+	c.atMost(float64(j))
 	b.desc.Validators = append(b.desc.Validators, func(v uint64) error {
 		if v < i || v > j {
 			return fmt.Errorf("value %v out of range [%v, %v]", v, i, j)
@@ -1961,6 +2225,7 @@ func (b *uint64Builder) Range(i, j uint64) *uint64Builder {
 
 // Min adds a minimum value validator for this field. Operation fails if the validator fails.
 func (b *uint64Builder) Min(i uint64) *uint64Builder {
+	b.desc.constrain().atLeast(float64(i))
 	b.desc.Validators = append(b.desc.Validators, func(v uint64) error {
 		if v < i {
 			return fmt.Errorf("value %v less than minimum %v", v, i)
@@ -1972,6 +2237,7 @@ func (b *uint64Builder) Min(i uint64) *uint64Builder {
 
 // Max adds a maximum value validator for this field. Operation fails if the validator fails.
 func (b *uint64Builder) Max(i uint64) *uint64Builder {
+	b.desc.constrain().atMost(float64(i))
 	b.desc.Validators = append(b.desc.Validators, func(v uint64) error {
 		if v > i {
 			return fmt.Errorf("value %v greater than maximum %v", v, i)
@@ -2076,6 +2342,30 @@ func (b *uint64Builder) SchemaType(types map[string]string) *uint64Builder {
 	return b
 }
 
+// Generated makes the field a column that is computed by the database from
+// the given SQL expression. The create and update builders have no setters
+// for it, and the migration does not alter the column once it exists.
+//
+//	field.Uint64("total").
+//		Generated("price * quantity", field.Stored)
+func (b *uint64Builder) Generated(expr string, kind GeneratedKind) *uint64Builder {
+	b.desc.generated(kind).Expr = expr
+	return b
+}
+
+// GeneratedExprs is like Generated, with an expression per dialect.
+// The expressions override the one of Generated for their dialects.
+//
+//	field.Uint64("total").
+//		GeneratedExprs(map[string]string{
+//			dialect.MySQL:    "JSON_EXTRACT(data, '$.total')",
+//			dialect.Postgres: "(data->>'total')::numeric",
+//		}, field.Stored)
+func (b *uint64Builder) GeneratedExprs(exprs map[string]string, kind GeneratedKind) *uint64Builder {
+	b.desc.generated(kind).Exprs = exprs
+	return b
+}
+
 // GoType overrides the default Go type with a custom one.
 // If the provided type implements the Validator interface
 // and no validators have been set, the type validator will
@@ -2160,6 +2450,9 @@ func (b *float64Builder) Unique() *float64Builder {
 
 // Range adds a range validator for this field where the given value needs to be in the range of [i, j].
 func (b *float64Builder) Range(i, j float64) *float64Builder {
+	c := b.desc.constrain()
+	c.atLeast(float64(i))
+	c.atMost(float64(j))
 	b.desc.Validators = append(b.desc.Validators, func(v float64) error {
 		if v < i || v > j {
 			return fmt.Errorf("value %v out of range [%v, %v]", v, i, j)
@@ -2171,6 +2464,7 @@ func (b *float64Builder) Range(i, j float64) *float64Builder {
 
 // Min adds a minimum value validator for this field. Operation fails if the validator fails.
 func (b *float64Builder) Min(i float64) *float64Builder {
+	b.desc.constrain().atLeast(float64(i))
 	b.desc.Validators = append(b.desc.Validators, func(v float64) error {
 		if v < i {
 			return fmt.Errorf("value %v less than minimum %v", v, i)
@@ -2182,6 +2476,7 @@ func (b *float64Builder) Min(i float64) *float64Builder {
 
 // Max adds a maximum value validator for this field. Operation fails if the validator fails.
 func (b *float64Builder) Max(i float64) *float64Builder {
+	b.desc.constrain().atMost(float64(i))
 	b.desc.Validators = append(b.desc.Validators, func(v float64) error {
 		if v > i {
 			return fmt.Errorf("value %v greater than maximum %v", v, i)
@@ -2194,6 +2489,7 @@ func (b *float64Builder) Max(i float64) *float64Builder {
 // Positive adds a validator that requires the value to be strictly greater than 0.
 // Operation fails if the validator fails.
 func (b *float64Builder) Positive() *float64Builder {
+	b.desc.constrain().atLeast(0)
 	b.desc.Validators = append(b.desc.Validators, func(v float64) error {
 		if v <= 0 {
 			return fmt.Errorf("value %v must be positive (> 0)", v)
@@ -2206,6 +2502,7 @@ func (b *float64Builder) Positive() *float64Builder {
 // Negative adds a validator that requires the value to be strictly less than 0.
 // Operation fails if the validator fails.
 func (b *float64Builder) Negative() *float64Builder {
+	b.desc.constrain().atMost(0)
 	b.desc.Validators = append(b.desc.Validators, func(v float64) error {
 		if v >= 0 {
 			return fmt.Errorf("value %v must be negative (< 0)", v)
@@ -2312,6 +2609,30 @@ func (b *float64Builder) SchemaType(types map[string]string) *float64Builder {
 	return b
 }
 
+// Generated makes the field a column that is computed by the database from
+// the given SQL expression. The create and update builders have no setters
+// for it, and the migration does not alter the column once it exists.
+//
+//	field.Float64("total").
+//		Generated("price * quantity", field.Stored)
+func (b *float64Builder) Generated(expr string, kind GeneratedKind) *float64Builder {
+	b.desc.generated(kind).Expr = expr
+	return b
+}
+
+// GeneratedExprs is like Generated, with an expression per dialect.
+// The expressions override the one of Generated for their dialects.
+//
+//	field.Float64("total").
+//		GeneratedExprs(map[string]string{
+//			dialect.MySQL:    "JSON_EXTRACT(data, '$.total')",
+//			dialect.Postgres: "(data->>'total')::numeric",
+//		}, field.Stored)
+func (b *float64Builder) GeneratedExprs(exprs map[string]string, kind GeneratedKind) *float64Builder {
+	b.desc.generated(kind).Exprs = exprs
+	return b
+}
+
 // GoType overrides the default Go type with a custom one.
 // If the provided type implements the Validator interface
 // and no validators have been set, the type validator will
@@ -2383,6 +2704,9 @@ func (b *float32Builder) Unique() *float32Builder {
 
 // Range adds a range validator for this field where the given value needs to be in the range of [i, j].
 func (b *float32Builder) Range(i, j float32) *float32Builder {
+	c := b.desc.constrain()
+	c.atLeast(float64(i))
+	c.atMost(float64(j))
 	b.desc.Validators = append(b.desc.Validators, func(v float32) error {
 		if v < i || v > j {
 			return fmt.Errorf("value %v out of range [%v, %v]", v, i, j)
@@ -2394,6 +2718,7 @@ func (b *float32Builder) Range(i, j float32) *float32Builder {
 
 // Min adds a minimum value validator for this field. Operation fails if the validator fails.
 func (b *float32Builder) Min(i float32) *float32Builder {
+	b.desc.constrain().atLeast(float64(i))
 	b.desc.Validators = append(b.desc.Validators, func(v float32) error {
 		if v < i {
 			return fmt.Errorf("value %v less than minimum %v", v, i)
@@ -2405,6 +2730,7 @@ func (b *float32Builder) Min(i float32) *float32Builder {
 
 // Max adds a maximum value validator for this field. Operation fails if the validator fails.
 func (b *float32Builder) Max(i float32) *float32Builder {
+	b.desc.constrain().atMost(float64(i))
 	b.desc.Validators = append(b.desc.Validators, func(v float32) error {
 		if v > i {
 			return fmt.Errorf("value %v greater than maximum %v", v, i)
@@ -2417,6 +2743,7 @@ func (b *float32Builder) Max(i float32) *float32Builder {
 // Positive adds a validator that requires the value to be strictly greater than 0.
 // Operation fails if the validator fails.
 func (b *float32Builder) Positive() *float32Builder {
+	b.desc.constrain().atLeast(0)
 	b.desc.Validators = append(b.desc.Validators, func(v float32) error {
 		if v <= 0 {
 			return fmt.Errorf("value %v must be positive (> 0)", v)
@@ -2429,6 +2756,7 @@ func (b *float32Builder) Positive() *float32Builder {
 // Negative adds a validator that requires the value to be strictly less than 0.
 // Operation fails if the validator fails.
 func (b *float32Builder) Negative() *float32Builder {
+	b.desc.constrain().atMost(0)
 	b.desc.Validators = append(b.desc.Validators, func(v float32) error {
 		if v >= 0 {
 			return fmt.Errorf("value %v must be negative (< 0)", v)
@@ -2535,6 +2863,30 @@ func (b *float32Builder) SchemaType(types map[string]string) *float32Builder {
 	return b
 }
 
+// Generated makes the field a column that is computed by the database from
+// the given SQL expression. The create and update builders have no setters
+// for it, and the migration does not alter the column once it exists.
+//
+//	field.Float32("total").
+//		Generated("price * quantity", field.Stored)
+func (b *float32Builder) Generated(expr string, kind GeneratedKind) *float32Builder {
+	b.desc.generated(kind).Expr = expr
+	return b
+}
+
+// GeneratedExprs is like Generated, with an expression per dialect.
+// The expressions override the one of Generated for their dialects.
+//
+//	field.Float32("total").
+//		GeneratedExprs(map[string]string{
+//			dialect.MySQL:    "JSON_EXTRACT(data, '$.total')",
+//			dialect.Postgres: "(data->>'total')::numeric",
+//		}, field.Stored)
+func (b *float32Builder) GeneratedExprs(exprs map[string]string, kind GeneratedKind) *float32Builder {
+	b.desc.generated(kind).Exprs = exprs
+	return b
+}
+
 // GoType overrides the default Go type with a custom one.
 // If the provided type implements the Validator interface
 // and no validators have been set, the type validator will
@@ -1,8 +1,11 @@
 package field
 
 import (
+	"encoding"
+	"encoding/json"
 	"fmt"
 	"reflect"
+	"strings"
 
 	"golang.org/x/text/cases"
 	"golang.org/x/text/language"
@@ -213,10 +216,23 @@ type RType struct {
 	Kind    reflect.Kind
 	PkgPath string
 	Methods map[string]struct{ In, Out []*RType }
+	// Fields holds the fields of a JSON struct type, as they are encoded by
+	// encoding/json. Only fields of basic types (or pointers to them), and of
+	// nested struct types are recorded. Set for JSON fields only.
+	Fields []*RField `json:",omitempty"`
 	// Used only for in-package checks.
 	rtype reflect.Type
 }
 
+// RField holds the serializable information of a field of a JSON struct type.
+type RField struct {
+	Name string // Go field name
+	Key  string // JSON object key
+	// Type is the (indirect) type of the field. It holds
+	// the nested fields of struct types.
+	Type *RType
+}
+
 // TypeEqual reports if the underlying type is equal to the RType (after pointer indirections).
 func (r *RType) TypeEqual(t reflect.Type) bool {
 	tv := indirect(t)
@@ -263,3 +279,72 @@ func (r *RType) Implements(typ reflect.Type) bool {
 	}
 	return true
 }
+
+var (
+	jsonMarshalerType = reflect.TypeFor[json.Marshaler]()
+	textMarshalerType = reflect.TypeFor[encoding.TextMarshaler]()
+)
+
+// jsonFields returns the fields of a struct type as they are encoded by
+// encoding/json. Fields with custom marshalers, composite types other than
+// structs, and recursive struct types are omitted.
+func jsonFields(t reflect.Type, visiting map[reflect.Type]bool) []*RField {
+	if visiting[t] {
+		return nil
+	}
+	visiting[t] = true
+	defer delete(visiting, t)
+	var (
+		fields []*RField
+		keys   = make(map[string]bool)
+	)
+	for i := range t.NumField() {
+		sf := t.Field(i)
+		tag := sf.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		key, opts, _ := strings.Cut(tag, ",")
+		ft := indirect(sf.Type)
+		switch {
+		// Fields of embedded structs are promoted to the outer object.
+		case sf.Anonymous && key == "" && ft.Kind() == reflect.Struct && !customJSON(ft):
+			for _, f := range jsonFields(ft, visiting) {
+				if !keys[f.Key] {
+					keys[f.Key] = true
+					fields = append(fields, f)
+				}
+			}
+			continue
+		case !sf.IsExported(), strings.Contains(opts, "string"), customJSON(ft):
+			continue
+		}
+		if key == "" {
+			key = sf.Name
+		}
+		typ := &RType{Name: ft.Name(), Ident: ft.String(), Kind: ft.Kind(), PkgPath: ft.PkgPath()}
+		switch ft.Kind() {
+		case reflect.Bool, reflect.String, reflect.Float32, reflect.Float64,
+			reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+			reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		case reflect.Struct:
+			if typ.Fields = jsonFields(ft, visiting); len(typ.Fields) == 0 {
+				continue
+			}
+		default:
+			continue
+		}
+		if !keys[key] {
+			keys[key] = true
+			fields = append(fields, &RField{Name: sf.Name, Key: key, Type: typ})
+		}
+	}
+	return fields
+}
+
+// customJSON reports if the type has a custom JSON encoding.
+func customJSON(t reflect.Type) bool {
+	pt := reflect.PointerTo(t)
+	return t.Implements(jsonMarshalerType) || pt.Implements(jsonMarshalerType) ||
+		t.Implements(textMarshalerType) || pt.Implements(textMarshalerType)
+}
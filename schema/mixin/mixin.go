@@ -48,6 +48,7 @@
 //   - SoftDelete: deleted_at for soft deletion
 //   - TimeSoftDelete: Time + SoftDelete combined
 //   - ID: UUID primary key with auto-generation
+//   - UUIDv7ID, ULIDID, SnowflakeID, PrefixedID: time-ordered primary keys
 //   - TenantID: tenant_id for multi-tenancy
 //
 // Usage:
@@ -69,6 +70,7 @@ import (
 	"github.com/google/uuid"
 
 	"github.com/syssam/velox"
+	"github.com/syssam/velox/dialect/sqlschema"
 	"github.com/syssam/velox/schema"
 	"github.com/syssam/velox/schema/field"
 )
@@ -237,13 +239,14 @@ func (TimeSoftDelete) Fields() []velox.Field {
 //
 //	sqlschema.DefaultExpr("gen_random_uuid()")
 //
-// For custom ID types (e.g., Snowflake IDs), create your own mixin:
+// For time-ordered IDs, use UUIDv7ID, ULIDID, SnowflakeID or PrefixedID.
+// For other ID types, create your own mixin:
 //
-//	type SnowflakeID struct{ mixin.Schema }
+//	type KSUID struct{ mixin.Schema }
 //
-//	func (SnowflakeID) Fields() []velox.Field {
+//	func (KSUID) Fields() []velox.Field {
 //	    return []velox.Field{
-//	        field.Int64("id").Default(snowflake.Generate).Immutable(),
+//	        field.String("id").DefaultFunc(ksuid.NewString).Immutable(),
 //	    }
 //	}
 type ID struct{ Schema }
@@ -260,6 +263,95 @@ func (ID) Fields() []velox.Field {
 // id mixin must implement `Mixin` interface.
 var _ velox.Mixin = (*ID)(nil)
 
+// UUIDv7ID adds a time-ordered UUID (version 7) primary key. Unlike random
+// UUIDs, sequential inserts stay local in B-tree indexes.
+type UUIDv7ID struct{ Schema }
+
+// Fields of the UUIDv7ID mixin.
+func (UUIDv7ID) Fields() []velox.Field {
+	return []velox.Field{
+		field.UUID("id", uuid.UUID{}).
+			Default(field.NewUUIDv7).
+			Immutable(),
+	}
+}
+
+// ULIDID adds a ULID primary key: a 26-character, lexicographically sortable
+// string ID.
+type ULIDID struct{ Schema }
+
+// Fields of the ULIDID mixin.
+func (ULIDID) Fields() []velox.Field {
+	return []velox.Field{
+		field.String("id").
+			DefaultFunc(field.NewULID).
+			MaxLen(26).
+			NotEmpty().
+			Immutable(),
+	}
+}
+
+// SnowflakeID adds a 64-bit, time-ordered integer primary key generated by
+// the application. Processes that insert into the same table must use
+// different node IDs (0 to field.MaxSnowflakeNode).
+//
+//	func (Order) Mixin() []velox.Mixin {
+//	    return []velox.Mixin{
+//	        mixin.SnowflakeID{Node: nodeID},
+//	    }
+//	}
+type SnowflakeID struct {
+	Schema
+	// Node is the node ID of the generator.
+	Node int64
+}
+
+// Fields of the SnowflakeID mixin. The column is not auto-incremented,
+// and is excluded from the IncrementStarts ranges of global IDs.
+func (m SnowflakeID) Fields() []velox.Field {
+	return []velox.Field{
+		field.Int64("id").
+			DefaultFunc(field.SnowflakeNode(m.Node).Next).
+			Immutable().
+			Annotations(sqlschema.Annotation{Incremental: new(bool)}),
+	}
+}
+
+// PrefixedID adds a Stripe-style string primary key made of a type prefix
+// and a ULID, e.g. "usr_01HQ3Z7N0D5X3VJ0B8TQ9W2K4M". The prefix is recorded
+// on the field, so the generated GraphQL Noder resolves an ID with the
+// resolver of its type only. Prefixes must be unique across the schema.
+//
+//	func (User) Mixin() []velox.Mixin {
+//	    return []velox.Mixin{
+//	        mixin.PrefixedID{Prefix: "usr"},
+//	    }
+//	}
+type PrefixedID struct {
+	Schema
+	// Prefix is the type prefix of the IDs. Lowercase letters and digits only.
+	Prefix string
+}
+
+// Fields of the PrefixedID mixin.
+func (m PrefixedID) Fields() []velox.Field {
+	return []velox.Field{
+		field.String("id").
+			DefaultFunc(field.PrefixedID(m.Prefix)).
+			NotEmpty().
+			Immutable().
+			Annotations(field.IDPrefix(m.Prefix)),
+	}
+}
+
+// ID generator mixins must implement `Mixin` interface.
+var (
+	_ velox.Mixin = (*UUIDv7ID)(nil)
+	_ velox.Mixin = (*ULIDID)(nil)
+	_ velox.Mixin = (*SnowflakeID)(nil)
+	_ velox.Mixin = (*PrefixedID)(nil)
+)
+
 // TenantID adds a tenant_id field for multi-tenancy support.
 // Combined with privacy policies, this enables row-level tenant isolation.
 //
@@ -275,6 +367,9 @@ var _ velox.Mixin = (*ID)(nil)
 //	    }
 //	}
 //
+// With the sql/rowsecurity feature, the table is also isolated by a PostgreSQL
+// row-level security policy on tenant_id (see sqlschema.TenantPolicy).
+//
 // For different naming conventions, create your own mixin:
 //
 //	type WorkspaceID struct{ mixin.Schema }
@@ -1,9 +1,13 @@
 package mixin_test
 
 import (
+	"reflect"
 	"testing"
 
+	"github.com/google/uuid"
+
 	"github.com/syssam/velox"
+	"github.com/syssam/velox/dialect/sqlschema"
 	"github.com/syssam/velox/schema"
 	"github.com/syssam/velox/schema/edge"
 	"github.com/syssam/velox/schema/field"
@@ -256,6 +260,50 @@ func TestIDMixin(t *testing.T) {
 	})
 }
 
+// TestIDGeneratorMixins tests the time-ordered ID mixins.
+func TestIDGeneratorMixins(t *testing.T) {
+	for _, tt := range []struct {
+		name  string
+		mixin velox.Mixin
+		check func(t *testing.T, id any)
+	}{
+		{"UUIDv7ID", mixin.UUIDv7ID{}, func(t *testing.T, id any) {
+			assert.EqualValues(t, 7, id.(uuid.UUID).Version())
+		}},
+		{"ULIDID", mixin.ULIDID{}, func(t *testing.T, id any) {
+			assert.Len(t, id, 26)
+		}},
+		{"SnowflakeID", mixin.SnowflakeID{Node: 5}, func(t *testing.T, id any) {
+			_, node, _ := field.ParseSnowflake(id.(int64))
+			assert.EqualValues(t, 5, node)
+		}},
+		{"PrefixedID", mixin.PrefixedID{Prefix: "usr"}, func(t *testing.T, id any) {
+			assert.Regexp(t, "^usr_[0-9A-Z]{26}$", id)
+		}},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			fields := tt.mixin.Fields()
+			require.Len(t, fields, 1)
+			desc := fields[0].Descriptor()
+			require.NoError(t, desc.Err)
+			assert.Equal(t, "id", desc.Name)
+			assert.True(t, desc.Immutable)
+			out := reflect.ValueOf(desc.Default).Call(nil)
+			tt.check(t, out[0].Interface())
+		})
+	}
+
+	desc := mixin.PrefixedID{Prefix: "usr"}.Fields()[0].Descriptor()
+	require.Len(t, desc.Annotations, 1)
+	assert.Equal(t, "usr", desc.Annotations[0].(*field.Annotation).IDPrefix)
+
+	desc = mixin.SnowflakeID{}.Fields()[0].Descriptor()
+	require.Len(t, desc.Annotations, 1)
+	incremental, ok := desc.Annotations[0].(sqlschema.Annotation).GetIncremental()
+	assert.True(t, ok)
+	assert.False(t, incremental, "Snowflake IDs are not auto-incremented")
+}
+
 // TestTenantIDMixin tests the TenantID mixin.
 func TestTenantIDMixin(t *testing.T) {
 	m := mixin.TenantID{}
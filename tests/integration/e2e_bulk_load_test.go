@@ -0,0 +1,106 @@
+package integration_test
+
+import (
+	"context"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	integration "github.com/syssam/velox/tests/integration"
+	postclient "github.com/syssam/velox/tests/integration/client/post"
+	tagclient "github.com/syssam/velox/tests/integration/client/tag"
+	"github.com/syssam/velox/tests/integration/post"
+	"github.com/syssam/velox/tests/integration/user"
+)
+
+// TestBulkLoad_Copy verifies CreateBulk.Copy (sql/bulkload feature):
+// defaults, JSON values and M2O foreign keys are written, and the rows
+// are split into batches of BatchSize. On Postgres, the batches are
+// streamed with COPY FROM STDIN.
+func TestBulkLoad_Copy(t *testing.T) {
+	t.Run("sqlite", func(t *testing.T) {
+		testBulkLoadCopy(t, openTestClient(t))
+	})
+	t.Run("postgres", func(t *testing.T) {
+		client, cleanup := openPostgresOrSkip(t)
+		t.Cleanup(cleanup)
+		testBulkLoadCopy(t, client)
+	})
+}
+
+func testBulkLoadCopy(t *testing.T, client *integration.Client) {
+	t.Helper()
+	ctx := context.Background()
+	author := createUser(t, client, "Author", "author@test.com")
+
+	builders := make([]*postclient.PostCreate, 25)
+	for i := range builders {
+		builders[i] = client.Post.Create().
+			SetTitle("post " + strconv.Itoa(i)).
+			SetLabels([]string{"bulk"}).
+			SetAuthorID(author.ID)
+	}
+	n, err := client.Post.CreateBulk(builders...).BatchSize(10).Copy(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 25, n)
+
+	posts, err := client.Post.Query().All(ctx)
+	require.NoError(t, err)
+	require.Len(t, posts, 25)
+	for _, p := range posts {
+		assert.Equal(t, post.StatusDraft, p.Status, "defaults run for every row")
+		assert.False(t, p.CreatedAt.IsZero())
+		assert.Equal(t, []string{"bulk"}, p.Labels)
+	}
+	count, err := client.Post.Query().Where(post.HasAuthorWith(user.IDField.EQ(author.ID))).Count(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 25, count)
+}
+
+// TestBulkLoad_Load verifies client.Xxx.Load: builders are consumed from an
+// iterator, validators run for every row, and mutation hooks are bypassed.
+func TestBulkLoad_Load(t *testing.T) {
+	client := openTestClient(t)
+	ctx := context.Background()
+
+	var hooked bool
+	client.Use(func(next integration.Mutator) integration.Mutator {
+		return integration.MutateFunc(func(ctx context.Context, m integration.Mutation) (integration.Value, error) {
+			hooked = true
+			return next.Mutate(ctx, m)
+		})
+	})
+	n, err := client.Tag.Load(ctx, func(yield func(*tagclient.TagCreate) bool) {
+		for i := range 100 {
+			if !yield(client.Tag.Create().SetName("tag-" + strconv.Itoa(i))) {
+				return
+			}
+		}
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 100, n)
+	assert.False(t, hooked, "Load bypasses mutation hooks")
+	count, err := client.Tag.Query().Count(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 100, count)
+
+	// A validation error stops the load before the batch is written.
+	n, err = client.Tag.Load(ctx, func(yield func(*tagclient.TagCreate) bool) {
+		_ = yield(client.Tag.Create().SetName("valid")) &&
+			yield(client.Tag.Create().SetName(""))
+	})
+	require.Error(t, err)
+	assert.Zero(t, n)
+	count, err = client.Tag.Query().Count(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 100, count)
+
+	// Constraint violations are reported as constraint errors.
+	_, err = client.Tag.Load(ctx, func(yield func(*tagclient.TagCreate) bool) {
+		yield(client.Tag.Create().SetName("tag-1"))
+	})
+	require.Error(t, err)
+	assert.True(t, integration.IsConstraintError(err))
+}
@@ -0,0 +1,130 @@
+package integration_test
+
+import (
+	"context"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	integration "github.com/syssam/velox/tests/integration"
+	postclient "github.com/syssam/velox/tests/integration/client/post"
+	"github.com/syssam/velox/tests/integration/entity"
+	"github.com/syssam/velox/tests/integration/post"
+)
+
+// TestUpdateBulk verifies client.Xxx.UpdateBulk: every builder writes its own
+// values, mutation hooks run once per row, and the updated entities are
+// returned in the order of the builders.
+func TestUpdateBulk(t *testing.T) {
+	t.Run("sqlite", func(t *testing.T) {
+		testUpdateBulk(t, openTestClient(t))
+	})
+	t.Run("postgres", func(t *testing.T) {
+		client, cleanup := openPostgresOrSkip(t)
+		t.Cleanup(cleanup)
+		testUpdateBulk(t, client)
+	})
+	t.Run("mysql", func(t *testing.T) {
+		client, cleanup := openMySQLOrSkip(t)
+		t.Cleanup(cleanup)
+		testUpdateBulk(t, client)
+	})
+}
+
+func testUpdateBulk(t *testing.T, client *integration.Client) {
+	t.Helper()
+	ctx := context.Background()
+	author := createUser(t, client, "Author", "author@test.com")
+	posts := make([]*entity.Post, 25)
+	for i := range posts {
+		posts[i] = createPost(t, client, author, "post "+strconv.Itoa(i), "content")
+	}
+
+	var hooked int
+	client.Post.Use(func(next integration.Mutator) integration.Mutator {
+		return integration.MutateFunc(func(ctx context.Context, m integration.Mutation) (integration.Value, error) {
+			hooked++
+			return next.Mutate(ctx, m)
+		})
+	})
+	// Reverse order, so the returned entities must be matched by id.
+	builders := make([]*postclient.PostUpdateOne, 0, len(posts))
+	for i := len(posts) - 1; i >= 0; i-- {
+		b := client.Post.UpdateOne(posts[i]).SetViewCount(i * 10)
+		if i%5 == 0 {
+			b.SetTitle("renamed " + strconv.Itoa(i))
+		}
+		builders = append(builders, b)
+	}
+	updated, err := client.Post.UpdateBulk(builders...).BatchSize(10).Save(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, len(posts), hooked, "hooks run once per row")
+	require.Len(t, updated, len(posts))
+	for j, p := range updated {
+		i := len(posts) - 1 - j
+		assert.Equal(t, posts[i].ID, p.ID)
+		assert.Equal(t, i*10, p.ViewCount)
+	}
+
+	for i, p := range posts {
+		got := client.Post.GetX(ctx, p.ID)
+		assert.Equal(t, i*10, got.ViewCount)
+		if i%5 == 0 {
+			assert.Equal(t, "renamed "+strconv.Itoa(i), got.Title)
+		} else {
+			assert.Equal(t, p.Title, got.Title)
+		}
+	}
+
+	// MapUpdateBulk adds to the current values.
+	updated, err = client.Post.MapUpdateBulk(posts, func(i int) int {
+		return posts[i].ID
+	}, func(b *postclient.PostUpdateOne, i int) {
+		b.AddViewCount(i)
+	}).Save(ctx)
+	require.NoError(t, err)
+	for i, p := range updated {
+		assert.Equal(t, i*11, p.ViewCount)
+	}
+}
+
+func TestUpdateBulk_Errors(t *testing.T) {
+	client := openTestClient(t)
+	ctx := context.Background()
+	author := createUser(t, client, "Author", "author@test.com")
+	p1 := createPost(t, client, author, "first", "content")
+	p2 := createPost(t, client, author, "second", "content")
+
+	// Constraint violations roll back the whole chunk.
+	t1, t2 := createTag(t, client, "go"), createTag(t, client, "sql")
+	_, err := client.Tag.UpdateBulk(
+		client.Tag.UpdateOne(t1).SetName("golang"),
+		client.Tag.UpdateOne(t2).SetName("golang"),
+	).Save(ctx)
+	require.Error(t, err)
+	assert.True(t, integration.IsConstraintError(err))
+	assert.Equal(t, "go", client.Tag.GetX(ctx, t1.ID).Name)
+
+	// A missing row is reported as not found.
+	_, err = client.Post.UpdateBulk(
+		client.Post.UpdateOne(p1).SetViewCount(1),
+		client.Post.UpdateOneID(p2.ID+1000).SetViewCount(2),
+	).Save(ctx)
+	require.Error(t, err)
+	assert.True(t, integration.IsNotFound(err))
+
+	// A builder with predicates is updated on its own, and its guard holds.
+	_, err = client.Post.UpdateBulk(
+		client.Post.UpdateOne(p1).SetViewCount(3),
+		client.Post.UpdateOne(p2).SetViewCount(4).Where(post.TitleField.EQ("other")),
+	).Save(ctx)
+	require.Error(t, err)
+	assert.True(t, integration.IsNotFound(err))
+	assert.Equal(t, 0, client.Post.GetX(ctx, p2.ID).ViewCount)
+
+	// MapUpdateBulk requires a slice.
+	_, err = client.Post.MapUpdateBulk(1, func(int) int { return 0 }, func(*postclient.PostUpdateOne, int) {}).Save(ctx)
+	require.Error(t, err)
+}
@@ -35,6 +35,7 @@ func main() {
 			gen.FeatureLock,
 			gen.FeatureExecQuery,
 			gen.FeatureUpsert,
+			gen.FeatureBulkLoad,
 			gen.FeatureVersionedMigration,
 			gen.FeatureGlobalID,
 			gen.FeatureValidator,
@@ -0,0 +1,274 @@
+package velox
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+
+	"github.com/syssam/velox/dialect/sql/sqljson"
+)
+
+type (
+	// EntityValidator validates an entity as a whole, as opposed to the
+	// per-field validators declared in the schema fields. It is declared
+	// in the Validators method of the schema and runs on create, update-one
+	// and bulk-create, after the generated field checks passed. Updates by
+	// predicate, and the values an upsert sets on conflict, are not validated:
+	//
+	//	func(ctx context.Context, s velox.EntityState) error {
+	//		if s.Changed("discount") && !s.Has("coupon_code") {
+	//			return velox.NewEntityValidationError(errors.New("discount requires a coupon code"), "discount", "coupon_code")
+	//		}
+	//		return nil
+	//	}
+	//
+	// Errors that are not a *ValidationError are wrapped in one, such that
+	// IsValidationError reports true for every failing validator.
+	EntityValidator func(context.Context, EntityState) error
+
+	// EntityState is the resolved state of an entity that is about to be
+	// written. For creates, it holds the values set on the mutation (including
+	// defaults). For update-one operations, it merges the pending changes
+	// with the values currently stored in the database.
+	EntityState interface {
+		// Op returns the operation of the underlying mutation.
+		Op() Op
+		// Type returns the schema type of the entity.
+		Type() string
+		// Value returns the value the field will hold once the mutation is
+		// applied. The second value is false if the field holds no value,
+		// i.e. it was never set, it was cleared, or it is NULL.
+		Value(name string) (Value, bool)
+		// Has reports whether the field holds a value once the mutation is applied.
+		Has(name string) bool
+		// Changed reports whether the mutation sets, adds to or clears the
+		// field, appends to it, or sets a value at one of its paths.
+		Changed(name string) bool
+	}
+)
+
+// OldFieldFunc returns the value of a field stored in the database, or nil
+// if it is NULL. Generated mutations provide it for update-one operations, as
+// Mutation.OldField returns the zero value for NULL fields.
+type OldFieldFunc func(ctx context.Context, name string) (Value, error)
+
+// ValidateEntity runs the given validators on the resolved state of m and
+// returns the first error. It is called by the generated builders, right
+// after the field checks of a create or update-one operation. If old is nil,
+// stored values are read with m.OldField.
+func ValidateEntity(ctx context.Context, m Mutation, old OldFieldFunc, validators []EntityValidator) error {
+	if old == nil {
+		old = m.OldField
+	}
+	s := &entityState{ctx: ctx, m: m, old: old}
+	for _, v := range validators {
+		if v == nil {
+			return fmt.Errorf("velox: uninitialized entity validator (forgotten import runtime?)")
+		}
+		err := v(ctx, s)
+		// Errors loading the stored values take precedence, since the
+		// validator saw an incomplete state.
+		if s.err != nil {
+			return s.err
+		}
+		if err == nil {
+			continue
+		}
+		var ve *ValidationError
+		if !errors.As(err, &ve) {
+			return &ValidationError{Err: err, Entity: m.Type()}
+		}
+		if ve.Entity == "" {
+			ve.Entity = m.Type()
+		}
+		return err
+	}
+	return nil
+}
+
+// entityState implements EntityState on top of a generated mutation.
+type entityState struct {
+	ctx context.Context
+	m   Mutation
+	old OldFieldFunc
+	err error // first error returned by old.
+}
+
+func (s *entityState) Op() Op       { return s.m.Op() }
+func (s *entityState) Type() string { return s.m.Type() }
+
+func (s *entityState) Has(name string) bool {
+	_, ok := s.Value(name)
+	return ok
+}
+
+func (s *entityState) Changed(name string) bool {
+	if _, ok := s.m.Field(name); ok {
+		return true
+	}
+	if _, ok := s.m.AddedField(name); ok {
+		return true
+	}
+	if s.m.FieldCleared(name) {
+		return true
+	}
+	p, ok := s.m.(PatchMutation)
+	return ok && slices.Contains(p.PatchedFields(), name)
+}
+
+func (s *entityState) Value(name string) (Value, bool) {
+	if v, ok := s.m.Field(name); ok {
+		return s.patch(name, v)
+	}
+	if s.m.FieldCleared(name) {
+		return nil, false
+	}
+	delta, added := s.m.AddedField(name)
+	if !s.m.Op().Is(OpUpdateOne) {
+		return present(delta)
+	}
+	old, err := s.old(s.ctx, name)
+	if err != nil {
+		s.fail(name, err)
+		return nil, false
+	}
+	if added {
+		return present(addValues(old, delta))
+	}
+	return s.patch(name, old)
+}
+
+// patch applies the values set at the paths of a JSON field, and the values
+// appended to it, to v. Only update-one operations patch the stored value.
+func (s *entityState) patch(name string, v Value) (Value, bool) {
+	p, ok := s.m.(PatchMutation)
+	if !ok || !s.m.Op().Is(OpUpdateOne) {
+		return present(v)
+	}
+	if paths := p.FieldPaths(name); len(paths) > 0 {
+		// NULL columns are patched from the zero value of the field.
+		if v == nil {
+			zero, err := s.m.OldField(s.ctx, name)
+			if err != nil {
+				s.fail(name, err)
+				return nil, false
+			}
+			v = zero
+		}
+		patched, err := setPaths(v, paths)
+		if err != nil {
+			s.fail(name, err)
+			return nil, false
+		}
+		v = patched
+	}
+	if appended, ok := p.AppendedField(name); ok {
+		v = appendValues(v, appended)
+	}
+	return present(v)
+}
+
+// fail records the first error that occurred while resolving a field.
+func (s *entityState) fail(name string, err error) {
+	if s.err == nil {
+		s.err = fmt.Errorf("velox: resolving %s.%s: %w", s.m.Type(), name, err)
+	}
+}
+
+// present reports whether v holds a value, dereferencing nillable fields.
+func present(v Value) (Value, bool) {
+	if v == nil {
+		return nil, false
+	}
+	if rv := reflect.ValueOf(v); rv.Kind() == reflect.Pointer {
+		if rv.IsNil() {
+			return nil, false
+		}
+		return rv.Elem().Interface(), true
+	}
+	return v, true
+}
+
+// addValues returns old+delta for numeric fields incremented by update-one
+// mutations. The result has the type of delta.
+func addValues(old, delta Value) Value {
+	o, ok := present(old)
+	if !ok {
+		return delta
+	}
+	ov, dv := reflect.ValueOf(o), reflect.ValueOf(delta)
+	if ov.Type() != dv.Type() {
+		return delta
+	}
+	sum := reflect.New(dv.Type()).Elem()
+	switch {
+	case dv.CanInt():
+		sum.SetInt(ov.Int() + dv.Int())
+	case dv.CanUint():
+		sum.SetUint(ov.Uint() + dv.Uint())
+	case dv.CanFloat():
+		sum.SetFloat(ov.Float() + dv.Float())
+	default:
+		return delta
+	}
+	return sum.Interface()
+}
+
+// setPaths returns a copy of v with the given values set at their paths.
+// The copy is made through encoding/json, like the UPDATE statement built
+// by sqljson.SetPaths, and has the type of v.
+func setPaths(v Value, paths []sqljson.PathValue) (Value, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var doc map[string]any
+	if err := json.Unmarshal(b, &doc); err != nil || doc == nil {
+		doc = make(map[string]any)
+	}
+	for _, pv := range paths {
+		if len(pv.Path) == 0 {
+			continue
+		}
+		obj := doc
+		for _, k := range pv.Path[:len(pv.Path)-1] {
+			next, ok := obj[k].(map[string]any)
+			if !ok {
+				next = make(map[string]any)
+				obj[k] = next
+			}
+			obj = next
+		}
+		obj[pv.Path[len(pv.Path)-1]] = pv.Value
+	}
+	t := reflect.TypeOf(v)
+	if t == nil {
+		return doc, nil
+	}
+	if b, err = json.Marshal(doc); err != nil {
+		return nil, err
+	}
+	out := reflect.New(t)
+	if err := json.Unmarshal(b, out.Interface()); err != nil {
+		return nil, err
+	}
+	return out.Elem().Interface(), nil
+}
+
+// appendValues returns the values of old followed by the appended values
+// of a JSON slice field. The result has the type of appended.
+func appendValues(old, appended Value) Value {
+	o, ok := present(old)
+	if !ok {
+		return appended
+	}
+	ov, av := reflect.ValueOf(o), reflect.ValueOf(appended)
+	if ov.Type() != av.Type() || av.Kind() != reflect.Slice {
+		return appended
+	}
+	vs := reflect.MakeSlice(ov.Type(), 0, ov.Len()+av.Len())
+	return reflect.AppendSlice(reflect.AppendSlice(vs, ov), av).Interface()
+}
@@ -0,0 +1,224 @@
+package velox_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/syssam/velox"
+	"github.com/syssam/velox/dialect/sql/sqljson"
+)
+
+// stateTestMutation is a hookTestMutation holding pending, added, cleared
+// and stored field values.
+type stateTestMutation struct {
+	hookTestMutation
+	fields  map[string]velox.Value
+	added   map[string]velox.Value
+	cleared map[string]bool
+	old     map[string]velox.Value
+	oldErr  error
+}
+
+func (m *stateTestMutation) Field(name string) (velox.Value, bool) {
+	v, ok := m.fields[name]
+	return v, ok
+}
+
+func (m *stateTestMutation) AddedField(name string) (velox.Value, bool) {
+	v, ok := m.added[name]
+	return v, ok
+}
+
+func (m *stateTestMutation) FieldCleared(name string) bool { return m.cleared[name] }
+
+func (m *stateTestMutation) OldField(_ context.Context, name string) (velox.Value, error) {
+	if m.oldErr != nil {
+		return nil, m.oldErr
+	}
+	return m.old[name], nil
+}
+
+func TestValidateEntity_State(t *testing.T) {
+	ctx := context.Background()
+	discount := 5
+	m := &stateTestMutation{
+		hookTestMutation: hookTestMutation{op: velox.OpUpdateOne, typ: "Event"},
+		fields:           map[string]velox.Value{"title": "launch"},
+		added:            map[string]velox.Value{"seats": 3},
+		cleared:          map[string]bool{"coupon_code": true},
+		old: map[string]velox.Value{
+			"title":       "draft",
+			"seats":       10,
+			"coupon_code": "X",
+			"discount":    &discount,
+			"note":        (*string)(nil),
+		},
+	}
+	var got map[string]velox.Value
+	err := velox.ValidateEntity(ctx, m, nil, []velox.EntityValidator{
+		func(_ context.Context, s velox.EntityState) error {
+			assert.Equal(t, velox.OpUpdateOne, s.Op())
+			assert.Equal(t, "Event", s.Type())
+			got = make(map[string]velox.Value)
+			for _, name := range []string{"title", "seats", "coupon_code", "discount", "note"} {
+				if v, ok := s.Value(name); ok {
+					got[name] = v
+				}
+			}
+			assert.True(t, s.Changed("title"))
+			assert.True(t, s.Changed("seats"))
+			assert.True(t, s.Changed("coupon_code"))
+			assert.False(t, s.Changed("discount"))
+			assert.False(t, s.Has("note"))
+			return nil
+		},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]velox.Value{"title": "launch", "seats": 13, "discount": 5}, got)
+}
+
+// patchTestMutation is a stateTestMutation that appends to, and sets
+// paths of, its JSON fields.
+type patchTestMutation struct {
+	stateTestMutation
+	appends map[string]velox.Value
+	paths   map[string][]sqljson.PathValue
+}
+
+func (m *patchTestMutation) PatchedFields() []string {
+	var fields []string
+	for _, name := range []string{"info", "tags"} {
+		if _, ok := m.appends[name]; ok || len(m.paths[name]) > 0 {
+			fields = append(fields, name)
+		}
+	}
+	return fields
+}
+
+func (m *patchTestMutation) AppendedField(name string) (velox.Value, bool) {
+	v, ok := m.appends[name]
+	return v, ok
+}
+
+func (m *patchTestMutation) FieldPaths(name string) []sqljson.PathValue { return m.paths[name] }
+
+func TestValidateEntity_Patches(t *testing.T) {
+	type (
+		address struct {
+			City string `json:"city"`
+			Zip  int    `json:"zip"`
+		}
+		info struct {
+			Nickname string  `json:"nick"`
+			Address  address `json:"address"`
+		}
+	)
+	m := &patchTestMutation{
+		stateTestMutation: stateTestMutation{
+			hookTestMutation: hookTestMutation{op: velox.OpUpdateOne, typ: "User"},
+			old: map[string]velox.Value{
+				"info": info{Nickname: "a8m", Address: address{City: "TLV", Zip: 1}},
+				"tags": []string{"a"},
+			},
+		},
+		appends: map[string]velox.Value{"tags": []string{"b", "c"}},
+		paths:   map[string][]sqljson.PathValue{"info": {{Path: []string{"address", "city"}, Value: "NYC"}}},
+	}
+	err := velox.ValidateEntity(context.Background(), m, nil, []velox.EntityValidator{
+		func(_ context.Context, s velox.EntityState) error {
+			assert.True(t, s.Changed("info"))
+			assert.True(t, s.Changed("tags"))
+			v, ok := s.Value("info")
+			require.True(t, ok)
+			assert.Equal(t, info{Nickname: "a8m", Address: address{City: "NYC", Zip: 1}}, v)
+			v, ok = s.Value("tags")
+			require.True(t, ok)
+			assert.Equal(t, []string{"a", "b", "c"}, v)
+			return nil
+		},
+	})
+	require.NoError(t, err)
+}
+
+func TestValidateEntity_Create(t *testing.T) {
+	m := &stateTestMutation{
+		hookTestMutation: hookTestMutation{op: velox.OpCreate, typ: "Event"},
+		fields:           map[string]velox.Value{"discount": 5},
+		oldErr:           errors.New("OldField is only allowed on UpdateOne operations"),
+	}
+	err := velox.ValidateEntity(context.Background(), m, nil, []velox.EntityValidator{
+		func(_ context.Context, s velox.EntityState) error {
+			if s.Has("discount") && !s.Has("coupon_code") {
+				return errors.New("discount requires a coupon code")
+			}
+			return nil
+		},
+	})
+	var ve *velox.ValidationError
+	require.True(t, errors.As(err, &ve))
+	assert.Equal(t, "Event", ve.Entity)
+	assert.EqualError(t, err, "velox: validator failed for Event: discount requires a coupon code")
+}
+
+func TestValidateEntity_Errors(t *testing.T) {
+	ctx := context.Background()
+	m := &stateTestMutation{hookTestMutation: hookTestMutation{op: velox.OpUpdateOne, typ: "Event"}}
+
+	t.Run("ValidationError", func(t *testing.T) {
+		err := velox.ValidateEntity(ctx, m, nil, []velox.EntityValidator{
+			func(context.Context, velox.EntityState) error { return nil },
+			func(context.Context, velox.EntityState) error {
+				return velox.NewEntityValidationError(errors.New("invalid range"), "starts_at", "ends_at")
+			},
+		})
+		var ve *velox.ValidationError
+		require.True(t, errors.As(err, &ve))
+		assert.Equal(t, "Event", ve.Entity)
+		assert.Equal(t, []string{"starts_at", "ends_at"}, ve.Fields)
+	})
+
+	t.Run("OldFieldFunc", func(t *testing.T) {
+		old := func(_ context.Context, name string) (velox.Value, error) {
+			assert.Equal(t, "coupon_code", name)
+			return nil, nil
+		}
+		m := &stateTestMutation{
+			hookTestMutation: hookTestMutation{op: velox.OpUpdateOne, typ: "Event"},
+			old:              map[string]velox.Value{"coupon_code": ""},
+		}
+		err := velox.ValidateEntity(ctx, m, old, []velox.EntityValidator{
+			func(_ context.Context, s velox.EntityState) error {
+				assert.False(t, s.Has("coupon_code"))
+				return nil
+			},
+		})
+		require.NoError(t, err)
+	})
+
+	t.Run("OldFieldError", func(t *testing.T) {
+		m := &stateTestMutation{
+			hookTestMutation: hookTestMutation{op: velox.OpUpdateOne, typ: "Event"},
+			oldErr:           errors.New("connection refused"),
+		}
+		err := velox.ValidateEntity(ctx, m, nil, []velox.EntityValidator{
+			func(_ context.Context, s velox.EntityState) error {
+				if !s.Has("starts_at") {
+					return errors.New("starts_at is required")
+				}
+				return nil
+			},
+		})
+		require.Error(t, err)
+		assert.False(t, velox.IsValidationError(err))
+		assert.Contains(t, err.Error(), "connection refused")
+	})
+
+	t.Run("Uninitialized", func(t *testing.T) {
+		err := velox.ValidateEntity(ctx, m, nil, make([]velox.EntityValidator, 1))
+		assert.EqualError(t, err, "velox: uninitialized entity validator (forgotten import runtime?)")
+	})
+}
@@ -37,6 +37,7 @@
 //   - [Hook] - Defines mutation lifecycle middleware
 //   - [Interceptor] - Defines query middleware
 //   - [Policy] - Defines privacy/authorization policies
+//   - [EntityValidator] - Validates invariants spanning several fields
 //
 // # Mutation Operations
 //
@@ -72,6 +73,25 @@
 //			Mutation: privacy.MutationPolicy{privacy.DenyIfNoViewer()},
 //		}
 //	}
+//
+// # Entity Validators
+//
+// Implement Validators to check invariants that span several fields. Each
+// [EntityValidator] receives the [EntityState] the entity will have once the
+// mutation is applied:
+//
+//	func (Event) Validators() []velox.EntityValidator {
+//		return []velox.EntityValidator{
+//			func(ctx context.Context, s velox.EntityState) error {
+//				start, _ := s.Value("starts_at")
+//				end, _ := s.Value("ends_at")
+//				if !start.(time.Time).Before(end.(time.Time)) {
+//					return velox.NewEntityValidationError(errors.New("must start before it ends"), "starts_at", "ends_at")
+//				}
+//				return nil
+//			},
+//		}
+//	}
 package velox
 
 import (
@@ -79,6 +99,7 @@ import (
 	"fmt"
 	"slices"
 
+	"github.com/syssam/velox/dialect/sql/sqljson"
 	"github.com/syssam/velox/schema"
 	"github.com/syssam/velox/schema/edge"
 	"github.com/syssam/velox/schema/field"
@@ -139,6 +160,9 @@ type (
 		Interceptors() []Interceptor
 		// Policy returns the privacy policy of the schema.
 		Policy() Policy
+		// Validators returns an optional list of EntityValidator to run
+		// on the resolved entity state before it is written.
+		Validators() []EntityValidator
 		// Annotations returns a list of schema annotations to be used by
 		// codegen extensions.
 		Annotations() []schema.Annotation
@@ -321,6 +345,9 @@ func (Schema) Interceptors() []Interceptor { return nil }
 // Policy of the schema.
 func (Schema) Policy() Policy { return nil }
 
+// Validators of the schema.
+func (Schema) Validators() []EntityValidator { return nil }
+
 // Annotations of the schema.
 func (Schema) Annotations() []schema.Annotation { return nil }
 
@@ -419,6 +446,23 @@ type (
 		OldField(ctx context.Context, name string) (Value, error)
 	}
 
+	// PatchMutation is implemented by the mutations of types with JSON
+	// fields. Appending to a JSON field, or setting a value at a path of a
+	// JSON field with a struct type, patches the column in place. These
+	// changes are not reported by Field, AddedField or FieldCleared.
+	PatchMutation interface {
+		Mutation
+
+		// PatchedFields returns all JSON fields that were appended to,
+		// or set at a path, during this mutation.
+		PatchedFields() []string
+		// AppendedField returns the values appended to a JSON field.
+		// The second value indicates that nothing was appended to it.
+		AppendedField(name string) (Value, bool)
+		// FieldPaths returns the values set at the paths of a JSON field.
+		FieldPaths(name string) []sqljson.PathValue
+	}
+
 	// Mutator is the interface that wraps the Mutate method.
 	Mutator interface {
 		// Mutate apply the given mutation on the graph. The returned
@@ -584,6 +628,7 @@ func WithHooks[V Value, M any, PM interface {
 	*M
 	Mutation
 }](ctx context.Context, exec func(context.Context) (V, error), mutation PM, hooks []Hook) (v V, err error) {
+	ctx = NewMutationContext(ctx, mutation)
 	if len(hooks) == 0 {
 		return exec(ctx)
 	}
@@ -678,6 +723,38 @@ func QueryFromContext(ctx context.Context) *QueryContext {
 	return c
 }
 
+// mutationCtxKey is the key used for attaching the executed mutation.
+type mutationCtxKey struct{}
+
+// NewMutationContext returns a new context with the given Mutation attached.
+// It is set by the generated builders for the statements of a mutation.
+func NewMutationContext(parent context.Context, m Mutation) context.Context {
+	return context.WithValue(parent, mutationCtxKey{}, m)
+}
+
+// MutationFromContext returns the Mutation stored in ctx, if any.
+func MutationFromContext(ctx context.Context) Mutation {
+	m, _ := ctx.Value(mutationCtxKey{}).(Mutation)
+	return m
+}
+
+// CommentTags returns the entity and the operation of the query or mutation
+// executed with ctx, as sqlcommenter tags. It is meant for sql.CommentWithTags:
+//
+//	drv := sql.NewCommentDriver(db, sql.CommentWithTags(velox.CommentTags))
+//
+// Queries take precedence over mutations, such that queries executed by
+// mutation hooks are tagged with their own entity.
+func CommentTags(ctx context.Context) map[string]string {
+	if q := QueryFromContext(ctx); q != nil {
+		return map[string]string{"entity": q.Type, "op": q.Op}
+	}
+	if m := MutationFromContext(ctx); m != nil {
+		return map[string]string{"entity": m.Type(), "op": m.Op().String()}
+	}
+	return nil
+}
+
 // Clone returns a deep copy of the query context.
 func (q *QueryContext) Clone() *QueryContext {
 	c := &QueryContext{
@@ -163,3 +163,20 @@ func TestHookContextPropagation(t *testing.T) {
 
 	assert.Equal(t, "injected-value", receivedValue)
 }
+
+// TestCommentTags verifies that WithHooks attaches the mutation to the
+// context, and that queries take precedence over mutations.
+func TestCommentTags(t *testing.T) {
+	assert.Nil(t, velox.CommentTags(context.Background()))
+
+	m := &hookTestMutation{op: velox.OpUpdateOne, typ: "User"}
+	var tags, queryTags map[string]string
+	_, err := velox.WithHooks[velox.Value, hookTestMutation](context.Background(), func(ctx context.Context) (velox.Value, error) {
+		tags = velox.CommentTags(ctx)
+		queryTags = velox.CommentTags(velox.NewQueryContext(ctx, &velox.QueryContext{Type: "Pet", Op: velox.OpQueryAll}))
+		return nil, nil
+	}, m, nil)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"entity": "User", "op": "OpUpdateOne"}, tags)
+	assert.Equal(t, map[string]string{"entity": "Pet", "op": "QueryAll"}, queryTags)
+}